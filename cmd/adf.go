@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// adfNode is a node of an Atlassian Document Format document, the rich text
+// format of descriptions and comments in version 3 of the Jira API
+type adfNode struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Marks   []adfMark              `json:"marks,omitempty"`
+	Content []adfNode              `json:"content,omitempty"`
+	Version int                    `json:"version,omitempty"`
+}
+
+// adfMark is a formatting mark on an ADF text node
+type adfMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// jiraRichText is a description or comment from the Jira API: plain text in
+// version 2, or an ADF document in version 3, which is rendered as markdown
+type jiraRichText string
+
+// UnmarshalJSON accepts plain text, an ADF document or null
+func (t *jiraRichText) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = ""
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*t = jiraRichText(text)
+		return nil
+	}
+	var doc adfNode
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse rich text: %w", err)
+	}
+	*t = jiraRichText(renderADF(doc))
+	return nil
+}
+
+// textToADF converts plain text to an ADF document, with a paragraph for each
+// block of text separated by blank lines
+func textToADF(text string) adfNode {
+	doc := adfNode{Type: "doc", Version: 1, Content: []adfNode{}}
+	for _, block := range strings.Split(strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n"), "\n\n") {
+		block = strings.Trim(block, "\n")
+		if block == "" {
+			continue
+		}
+		paragraph := adfNode{Type: "paragraph"}
+		for i, line := range strings.Split(block, "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, adfNode{Type: "hardBreak"})
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, adfNode{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+	return doc
+}
+
+// renderADF renders an ADF document as markdown
+func renderADF(doc adfNode) string {
+	return strings.TrimSpace(renderADFBlocks(doc.Content))
+}
+
+// renderADFBlocks renders block nodes separated by blank lines
+func renderADFBlocks(nodes []adfNode) string {
+	var blocks []string
+	for _, node := range nodes {
+		if block := renderADFBlock(node); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderADFBlock renders a block node
+func renderADFBlock(node adfNode) string {
+	switch node.Type {
+	case "paragraph":
+		return renderADFInline(node.Content)
+	case "heading":
+		level := int(adfNumber(node.Attrs["level"]))
+		if level < 1 {
+			level = 1
+		}
+		return strings.Repeat("#", level) + " " + renderADFInline(node.Content)
+	case "bulletList", "orderedList":
+		return renderADFList(node)
+	case "codeBlock":
+		language, _ := node.Attrs["language"].(string)
+		return "```" + language + "\n" + renderADFInline(node.Content) + "\n```"
+	case "blockquote":
+		return prefixLines(renderADFBlocks(node.Content), "> ")
+	case "rule":
+		return "---"
+	case "table":
+		return renderADFTable(node)
+	case "mediaSingle", "mediaGroup":
+		return "[attachment]"
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return renderADFInline([]adfNode{node})
+	default:
+		// Panels, expands and unknown blocks are rendered through their content
+		return renderADFBlocks(node.Content)
+	}
+}
+
+// renderADFList renders a bullet or ordered list, indenting nested lists
+func renderADFList(list adfNode) string {
+	var items []string
+	number := 1
+	if start := adfNumber(list.Attrs["order"]); start > 0 {
+		number = int(start)
+	}
+	for _, item := range list.Content {
+		marker := "- "
+		if list.Type == "orderedList" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		var parts []string
+		for _, child := range item.Content {
+			if child.Type == "bulletList" || child.Type == "orderedList" {
+				parts = append(parts, prefixLines(renderADFList(child), "  "))
+			} else if block := renderADFBlock(child); block != "" {
+				parts = append(parts, block)
+			}
+		}
+		items = append(items, marker+strings.Join(parts, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderADFTable renders a table as a markdown table, with the first row as header
+func renderADFTable(table adfNode) string {
+	var lines []string
+	for i, row := range table.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			cells = append(cells, strings.ReplaceAll(renderADFBlocks(cell.Content), "\n", " "))
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", len(cells)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderADFInline renders inline nodes
+func renderADFInline(nodes []adfNode) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			b.WriteString(applyADFMarks(node.Text, node.Marks))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			text, _ := node.Attrs["text"].(string)
+			if !strings.HasPrefix(text, "@") {
+				text = "@" + text
+			}
+			b.WriteString(text)
+		case "emoji":
+			if text, ok := node.Attrs["text"].(string); ok && text != "" {
+				b.WriteString(text)
+			} else {
+				shortName, _ := node.Attrs["shortName"].(string)
+				b.WriteString(shortName)
+			}
+		case "inlineCard":
+			url, _ := node.Attrs["url"].(string)
+			b.WriteString(url)
+		case "status":
+			text, _ := node.Attrs["text"].(string)
+			b.WriteString("[" + text + "]")
+		case "date":
+			// Dates are stored as milliseconds since the epoch, as a string
+			if ms, err := strconv.ParseInt(fmt.Sprint(node.Attrs["timestamp"]), 10, 64); err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format("2006-01-02"))
+			}
+		default:
+			b.WriteString(renderADFInline(node.Content))
+		}
+	}
+	return b.String()
+}
+
+// applyADFMarks wraps text in the markdown of its marks
+func applyADFMarks(text string, marks []adfMark) string {
+	for _, mark := range marks {
+		switch mark.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "_" + text + "_"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "code":
+			text = "`" + text + "`"
+		case "link":
+			if href, _ := mark.Attrs["href"].(string); href != "" && href != text {
+				text = "[" + text + "](" + href + ")"
+			}
+		}
+	}
+	return text
+}
+
+// adfNumber returns a numeric attribute, which JSON decodes as a float64
+func adfNumber(value interface{}) float64 {
+	number, _ := value.(float64)
+	return number
+}
+
+// prefixLines adds a prefix to every line of text
+func prefixLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderADF(t *testing.T) {
+	doc := `{"type": "doc", "version": 1, "content": [
+		{"type": "heading", "attrs": {"level": 2}, "content": [{"type": "text", "text": "Steps"}]},
+		{"type": "paragraph", "content": [
+			{"type": "text", "text": "Log in as "},
+			{"type": "mention", "attrs": {"id": "1", "text": "@Alex"}},
+			{"type": "text", "text": " and open "},
+			{"type": "text", "text": "settings", "marks": [{"type": "strong"}]},
+			{"type": "hardBreak"},
+			{"type": "text", "text": "docs", "marks": [{"type": "link", "attrs": {"href": "https://example.com"}}]}
+		]},
+		{"type": "orderedList", "content": [
+			{"type": "listItem", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "First"}]},
+				{"type": "bulletList", "content": [
+					{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Nested"}]}]}
+				]}
+			]},
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Second"}]}]}
+		]},
+		{"type": "codeBlock", "attrs": {"language": "go"}, "content": [{"type": "text", "text": "fmt.Println()"}]},
+		{"type": "panel", "content": [{"type": "blockquote", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Quoted"}]}]}]}
+	]}`
+
+	want := "## Steps\n\n" +
+		"Log in as @Alex and open **settings**\n[docs](https://example.com)\n\n" +
+		"1. First\n  - Nested\n2. Second\n\n" +
+		"```go\nfmt.Println()\n```\n\n" +
+		"> Quoted"
+
+	var text jiraRichText
+	if err := json.Unmarshal([]byte(doc), &text); err != nil {
+		t.Fatalf("Failed to parse ADF: %v", err)
+	}
+	if string(text) != want {
+		t.Errorf("Unexpected rendering:\n%s\nwant:\n%s", text, want)
+	}
+}
+
+func TestJiraRichTextPlain(t *testing.T) {
+	var text jiraRichText
+	if err := json.Unmarshal([]byte(`"Plain description"`), &text); err != nil || text != "Plain description" {
+		t.Errorf("Expected plain text to be kept, got %q (%v)", text, err)
+	}
+	if err := json.Unmarshal([]byte(`null`), &text); err != nil || text != "" {
+		t.Errorf("Expected null to be empty, got %q (%v)", text, err)
+	}
+}
+
+func TestTextToADF(t *testing.T) {
+	doc := textToADF("First line\nsecond line\n\nNext paragraph")
+	if doc.Type != "doc" || doc.Version != 1 || len(doc.Content) != 2 {
+		t.Fatalf("Unexpected document %+v", doc)
+	}
+	if got := renderADF(doc); got != "First line\nsecond line\n\nNext paragraph" {
+		t.Errorf("Expected the text to survive a round trip, got %q", got)
+	}
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// autoTrackTag marks work recorded automatically from commits
+	autoTrackTag = "auto"
+	// autoTrackGap is the longest gap between commits that still counts as the same work
+	autoTrackGap = 30 * time.Minute
+	// autoTrackHookLine is the line added to the post-commit hook
+	autoTrackHookLine = "plannet auto-track >/dev/null 2>&1 || true"
+)
+
+// autoTrackCmd represents the auto-track command
+var autoTrackCmd = &cobra.Command{
+	Use:   "auto-track",
+	Short: "Record tracked work from the latest commit",
+	Long: `Record tracked work automatically from the latest commit, so side quests
+are captured even when you forget to run 'plannet track'.
+
+This is meant to run from a post-commit hook; install one in the current
+repository with --install. Each commit is tied to the ticket ID in its
+message or branch name and then:
+
+- Recorded on the active work if it is for the same ticket (or branch)
+- Used to extend the last automatically tracked entry for the same ticket
+  if it ended less than 30 minutes earlier
+- Otherwise recorded as a new completed entry tagged "auto", starting at
+  the previous commit if that was less than 30 minutes earlier
+
+Auto-track is opt-in: set auto_track to true in your configuration.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		install, _ := cmd.Flags().GetBool("install")
+		runAutoTrack(install)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(autoTrackCmd)
+	autoTrackCmd.Flags().Bool("install", false, "Install a post-commit hook in the current repository")
+}
+
+func runAutoTrack(install bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if install {
+		hookPath, err := installAutoTrackHook()
+		if err != nil {
+			printError("Error installing hook:", err)
+			return
+		}
+		fmt.Printf("Installed auto-track hook in %s\n", hookPath)
+		if !cfg.AutoTrack {
+			fmt.Println("Set auto_track to true in your configuration to start tracking commits.")
+		}
+		return
+	}
+
+	if !cfg.AutoTrack {
+		fmt.Println("Auto-track is disabled. Set auto_track to true in your configuration.")
+		return
+	}
+
+	commits, err := getRecentCommits(2)
+	if err != nil || len(commits) == 0 {
+		fmt.Println("No commit to track.")
+		return
+	}
+	commit := commits[0]
+	var previous *Commit
+	if len(commits) > 1 {
+		previous = &commits[1]
+	}
+
+	branch, err := getCurrentBranch()
+	if err != nil {
+		printError("Error getting current branch:", err)
+		return
+	}
+	files, _ := getCommitFiles(".", commit.Hash)
+
+	result, err := autoTrackCommit(cfg, commit, previous, branch, files)
+	if err != nil {
+		printError("Error tracking commit:", err)
+		return
+	}
+	fmt.Println(result)
+	autoReplaySmartCommits(cfg, commits[:1])
+}
+
+// autoTrackCommit records a commit as tracked work and describes what was done
+func autoTrackCommit(cfg *config.Config, commit Commit, previous *Commit, branch string, files []string) (string, error) {
+	ticketID := extractTicketIDFromMessage(commit.FullMessage(), cfg.TicketPrefixes)
+	if ticketID == "" {
+		ticketID = extractTicketID(branch, cfg.TicketPrefixes)
+	}
+
+	// Commits for the active work just add to its context
+	activeWork, err := getActiveWork()
+	if err != nil {
+		return "", err
+	}
+	if activeWork != nil && sameWorkStream(*activeWork, ticketID, branch) {
+		recordCommit(activeWork, commit, files)
+		if err := saveTrackedWork(*activeWork); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Recorded commit on active work: %s", activeWork.Description), nil
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		return "", err
+	}
+
+	// Extend the latest automatic entry for the same work if it is recent enough
+	var latest *TrackedWork
+	for i := range trackedWork {
+		w := &trackedWork[i]
+		if w.Status != "completed" || !containsString(w.Tags, autoTrackTag) || !sameWorkStream(*w, ticketID, branch) {
+			continue
+		}
+		if latest == nil || w.EndTime.After(latest.EndTime) {
+			latest = w
+		}
+	}
+	if latest != nil && !commit.Time.Before(latest.EndTime) && commit.Time.Sub(latest.EndTime) <= autoTrackGap {
+		latest.EndTime = commit.Time
+		recordCommit(latest, commit, files)
+		recordDiffStats(latest, commit.Hash)
+		if err := saveTrackedWork(*latest); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Extended auto-tracked work: %s (%s)", latest.Description, formatDuration(latest.Duration())), nil
+	}
+
+	start := commit.Time
+	if previous != nil && commit.Time.Sub(previous.Time) <= autoTrackGap {
+		start = previous.Time
+	}
+	var ticketIDs []string
+	if ticketID != "" {
+		ticketIDs = []string{ticketID}
+	}
+	work := TrackedWork{
+		ID:          generateID(),
+		Description: commit.Message,
+		TicketIDs:   ticketIDs,
+		StartTime:   start,
+		EndTime:     commit.Time,
+		Tags:        []string{autoTrackTag},
+		Status:      "completed",
+		Context: WorkContext{
+			Branch:     branch,
+			CommitHash: commit.Hash,
+			Files:      files,
+		},
+	}
+	if topLevel, err := getGitBackend().TopLevel("."); err == nil {
+		work.Context.Repo = topLevel
+	}
+	// The first commit of a repository has no parent to start from
+	if previous != nil {
+		work.Context.StartCommit = commit.Hash + "^"
+	}
+	recordDiffStats(&work, commit.Hash)
+	if err := saveTrackedWork(work); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Auto-tracked: %s", work.Description), nil
+}
+
+// sameWorkStream reports whether work is linked to the given ticket, or
+// belongs to the given branch when there is no ticket
+func sameWorkStream(work TrackedWork, ticketID, branch string) bool {
+	if ticketID != "" {
+		return work.HasTicket(ticketID)
+	}
+	return len(work.TicketIDs) == 0 && branch != "" && work.Context.Branch == branch
+}
+
+// recordCommit adds a commit and its files to the context of tracked work
+func recordCommit(work *TrackedWork, commit Commit, files []string) {
+	work.Context.CommitHash = commit.Hash
+	for _, file := range files {
+		if !containsString(work.Context.Files, file) {
+			work.Context.Files = append(work.Context.Files, file)
+		}
+	}
+}
+
+// recordDiffStats records the lines added and removed between the commit
+// the work started from and the given commit of the work's repository, or
+// of the current one for work tracked before repositories were recorded.
+// Statistics are left as they are when the repository can't be read.
+func recordDiffStats(work *TrackedWork, to string) {
+	if work.Context.StartCommit == "" {
+		return
+	}
+	dir := work.Context.Repo
+	if dir == "" {
+		dir = "."
+	}
+	churn, err := getGitBackend().DiffStats(dir, work.Context.StartCommit, to)
+	if err != nil {
+		return
+	}
+
+	work.Context.LinesAdded, work.Context.LinesRemoved = 0, 0
+	for _, file := range churn {
+		work.Context.LinesAdded += file.Added
+		work.Context.LinesRemoved += file.Removed
+	}
+	work.Context.FileChurn = churn
+}
+
+// containsString reports whether a list contains the given value
+func containsString(list []string, value string) bool {
+	for _, s := range list {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
+// installAutoTrackHook adds auto-track to the post-commit hook of the current repository
+func installAutoTrackHook() (string, error) {
+	hooksDir, err := getHooksDir()
+	if err != nil {
+		return "", err
+	}
+
+	// The hook installed by 'plannet hooks install' already auto-tracks commits
+	hookPath := filepath.Join(hooksDir, "post-commit")
+	if existing, err := os.ReadFile(hookPath); err == nil && strings.Contains(string(existing), postCommitHookLine) {
+		return hookPath, nil
+	}
+	return installHookLine(hooksDir, "post-commit", autoTrackHookLine)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestAutoTrackCommit(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	cfg := &config.Config{TicketPrefixes: []string{"JIRA-"}}
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	commit := func(hash, message string, offset time.Duration) Commit {
+		return Commit{Hash: hash, Message: message, Time: start.Add(offset)}
+	}
+
+	// The active work is on JIRA-1
+	active := TrackedWork{ID: "active-1", Description: "Feature", TicketIDs: []string{"JIRA-1"}, StartTime: start, Status: "active"}
+	if err := saveTrackedWork(active); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	first := commit("a1", "JIRA-1: add feature", 10*time.Minute)
+	side := commit("b1", "Fix typo in README", 20*time.Minute)
+	sideAgain := commit("b2", "Fix another typo", 35*time.Minute)
+	sideLater := commit("b3", "Fix a third typo", 3*time.Hour)
+
+	steps := []struct {
+		commit   Commit
+		previous *Commit
+		files    []string
+	}{
+		{first, nil, []string{"feature.go"}},
+		{side, &first, []string{"README.md"}},
+		{sideAgain, &side, []string{"README.md"}},
+		{sideLater, &sideAgain, nil},
+	}
+	for _, step := range steps {
+		if _, err := autoTrackCommit(cfg, step.commit, step.previous, "main", step.files); err != nil {
+			t.Fatalf("Failed to auto-track %s: %v", step.commit.Hash, err)
+		}
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	if activeWork == nil || activeWork.Context.CommitHash != "a1" {
+		t.Errorf("Expected the JIRA-1 commit to be recorded on the active work, got %+v", activeWork)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+
+	var auto []TrackedWork
+	for _, w := range trackedWork {
+		if containsString(w.Tags, autoTrackTag) {
+			auto = append(auto, w)
+		}
+	}
+	if len(auto) != 2 {
+		t.Fatalf("Expected 2 auto-tracked entries, got %d", len(auto))
+	}
+
+	for _, w := range auto {
+		switch w.Context.CommitHash {
+		case "b2":
+			// Started at the previous commit and extended by the next one
+			if !w.StartTime.Equal(first.Time) || !w.EndTime.Equal(sideAgain.Time) {
+				t.Errorf("Expected side quest to span %v – %v, got %v – %v", first.Time, sideAgain.Time, w.StartTime, w.EndTime)
+			}
+			if len(w.Context.Files) != 1 {
+				t.Errorf("Expected files to be merged, got %v", w.Context.Files)
+			}
+		case "b3":
+			if !w.StartTime.Equal(sideLater.Time) {
+				t.Errorf("Expected a late commit to start new work at %v, got %v", sideLater.Time, w.StartTime)
+			}
+		default:
+			t.Errorf("Unexpected auto-tracked entry for commit %s", w.Context.CommitHash)
+		}
+	}
+}
+
+func TestAutoTrackRootCommit(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	cfg := &config.Config{}
+	root := Commit{Hash: "a1", Message: "Initial commit", Time: time.Now()}
+	if _, err := autoTrackCommit(cfg, root, nil, "main", nil); err != nil {
+		t.Fatalf("Failed to auto-track the root commit: %v", err)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(trackedWork) != 1 || trackedWork[0].Context.StartCommit != "" {
+		t.Errorf("Expected the root commit's work to have no start commit, got %+v", trackedWork)
+	}
+}
@@ -0,0 +1,576 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// backupManifestName is the name of the manifest inside a backup archive
+	backupManifestName = "manifest.json"
+	// backupConfigName is the name of the sanitized config inside a backup archive
+	backupConfigName = "plannetrc.json"
+)
+
+// backupManifest describes the content of a backup archive
+type backupManifest struct {
+	CreatedAt     time.Time    `json:"created_at"`
+	SchemaVersion int          `json:"schema_version"`
+	Incremental   bool         `json:"incremental"`
+	Base          string       `json:"base,omitempty"` // Archive this one is incremental to
+	Files         []backupFile `json:"files"`
+}
+
+// backupFile describes one file of the backed up database. Incremental backups
+// only contain changed files; Archive names the backup that holds the content.
+type backupFile struct {
+	Path    string `json:"path"`
+	SHA256  string `json:"sha256"`
+	Size    int64  `json:"size"`
+	Archive string `json:"archive"`
+}
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup [path]",
+	Short: "Back up your tracked work",
+	Long: `Back up your tracked work database and configuration.
+API tokens are never included in backups. If no path is given, the backup is
+written to ~/.plannet/backups. With --incremental only the files that changed
+since the most recent backup in the same directory are archived.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		incremental, _ := cmd.Flags().GetBool("incremental")
+		runBackup(args, incremental)
+	},
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [path]",
+	Short: "Restore tracked work from a backup",
+	Long: `Restore your tracked work database from a backup.
+Every file is verified against the checksums recorded in the backup before
+anything is replaced, and the current database is copied aside first.
+Use --config to also restore your configuration; your current API tokens are kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		withConfig, _ := cmd.Flags().GetBool("config")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		runRestore(args[0], withConfig, dryRun, force)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	backupCmd.Flags().BoolP("incremental", "i", false, "Only back up files changed since the last backup")
+	restoreCmd.Flags().Bool("config", false, "Also restore the configuration")
+	restoreCmd.Flags().Bool("dry-run", false, "Verify the backup without restoring it")
+	restoreCmd.Flags().BoolP("force", "f", false, "Restore without asking for confirmation")
+}
+
+func runBackup(args []string, incremental bool) {
+	dbDir, err := openDB()
+	if err != nil {
+		printError("Error opening database:", err)
+		return
+	}
+
+	// Work out where the backup goes
+	backupPath, err := resolveBackupPath(args)
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+
+	var base *backupManifest
+	var baseName string
+	if incremental {
+		basePath, err := latestBackup(filepath.Dir(backupPath))
+		if err != nil {
+			printError("Error finding previous backup:", err)
+			return
+		}
+		if basePath == "" {
+			fmt.Println("No previous backup found; creating a full backup.")
+		} else {
+			base, err = readBackupManifest(basePath)
+			if err != nil {
+				printError("Error reading previous backup:", err)
+				return
+			}
+			baseName = filepath.Base(basePath)
+		}
+	}
+
+	manifest, err := writeBackup(backupPath, dbDir, base, baseName)
+	if err != nil {
+		os.Remove(backupPath)
+		printError("Error creating backup:", err)
+		return
+	}
+
+	changed := 0
+	for _, f := range manifest.Files {
+		if f.Archive == filepath.Base(backupPath) {
+			changed++
+		}
+	}
+
+	fmt.Printf("Backup written to %s\n", backupPath)
+	if manifest.Incremental {
+		fmt.Printf("Incremental to %s: %d of %d files changed\n", manifest.Base, changed, len(manifest.Files))
+	} else {
+		fmt.Printf("Files backed up: %d\n", len(manifest.Files))
+	}
+}
+
+func runRestore(backupPath string, withConfig, dryRun, force bool) {
+	manifest, contents, cfgData, err := readVerifiedBackup(backupPath)
+	if err != nil {
+		fmt.Println("Backup verification failed:", err)
+		return
+	}
+
+	fmt.Printf("Backup from %s verified: %d files", manifest.CreatedAt.Format("2006-01-02 15:04"), len(manifest.Files))
+	if manifest.Incremental {
+		fmt.Printf(" (incremental to %s)", manifest.Base)
+	}
+	fmt.Println()
+
+	if manifest.SchemaVersion > currentSchemaVersion {
+		fmt.Printf("The backup uses schema version %d, which is newer than this version of plannet supports (%d).\n", manifest.SchemaVersion, currentSchemaVersion)
+		return
+	}
+
+	if dryRun {
+		return
+	}
+
+	if !force {
+		prompt := promptui.Prompt{
+			Label:     "Replace your current tracked work with this backup",
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("Restore cancelled.")
+			return
+		}
+	}
+
+	dbDir, err := getDBDir()
+	if err != nil {
+		printError("Error getting database directory:", err)
+		return
+	}
+
+	// Keep a copy of the current database in case the backup was the wrong one
+	if hasData, _ := hasWorkFiles(dbDir); hasData {
+		version, _ := readSchemaVersion(dbDir)
+		aside, err := backupDBDir(dbDir, version)
+		if err != nil {
+			printError("Error saving current database:", err)
+			return
+		}
+		fmt.Printf("Current database saved to %s\n", aside)
+	}
+
+	if err := replaceDBFiles(dbDir, contents); err != nil {
+		printError("Error restoring database:", err)
+		return
+	}
+	delete(checkedSchemaDirs, dbDir)
+
+	if withConfig {
+		if cfgData == nil {
+			fmt.Println("The backup does not contain a configuration.")
+		} else if err := restoreConfig(cfgData); err != nil {
+			printError("Error restoring configuration:", err)
+			return
+		} else {
+			fmt.Println("Configuration restored (API tokens were kept from your current configuration).")
+		}
+	}
+
+	fmt.Println("Restore completed successfully!")
+	if manifest.SchemaVersion < currentSchemaVersion {
+		fmt.Println("The restored database is from an older version of plannet. Run 'plannet migrate' to upgrade it.")
+	}
+}
+
+// resolveBackupPath returns the archive path for a new backup
+func resolveBackupPath(args []string) (string, error) {
+	name := fmt.Sprintf("plannet-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+
+	if len(args) == 0 {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dir := filepath.Join(homeDir, ".plannet", "backups")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		return filepath.Join(dir, name), nil
+	}
+
+	if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+		return filepath.Join(args[0], name), nil
+	}
+	return args[0], nil
+}
+
+// latestBackup returns the most recent backup archive in dir, if any
+func latestBackup(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "plannet-backup-*.tar.gz"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	// Backup names embed a sortable timestamp
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// writeBackup archives the database and sanitized config. If base is given,
+// files unchanged since that backup are referenced instead of archived.
+func writeBackup(backupPath, dbDir string, base *backupManifest, baseName string) (*backupManifest, error) {
+	archiveName := filepath.Base(backupPath)
+	version, err := readSchemaVersion(dbDir)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &backupManifest{
+		CreatedAt:     time.Now(),
+		SchemaVersion: version,
+		Incremental:   base != nil,
+		Base:          baseName,
+	}
+
+	previous := map[string]backupFile{}
+	if base != nil {
+		for _, f := range base.Files {
+			previous[f.Path] = f
+		}
+	}
+
+	file, err := os.OpenFile(backupPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	entries, err := os.ReadDir(dbDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read database directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dbDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		sum := sha256.Sum256(data)
+		f := backupFile{
+			Path:    entry.Name(),
+			SHA256:  hex.EncodeToString(sum[:]),
+			Size:    int64(len(data)),
+			Archive: archiveName,
+		}
+
+		// Unchanged files live in the archive that already holds them
+		if prev, ok := previous[f.Path]; ok && prev.SHA256 == f.SHA256 {
+			f.Archive = prev.Archive
+		} else if err := writeTarFile(tw, "db/"+f.Path, data); err != nil {
+			return nil, err
+		}
+
+		manifest.Files = append(manifest.Files, f)
+	}
+
+	// The config is always included, without any credentials
+	if cfg, err := config.Load(); err == nil {
+		data, err := json.MarshalIndent(sanitizedConfig(cfg), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal configuration: %w", err)
+		}
+		if err := writeTarFile(tw, backupConfigName, data); err != nil {
+			return nil, err
+		}
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeTarFile(tw, backupManifestName, manifestData); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// sanitizedConfig returns a copy of the config with all credentials removed
+func sanitizedConfig(cfg *config.Config) config.Config {
+	sanitized := *cfg
+	for _, field := range sanitized.CredentialFields() {
+		*field = ""
+	}
+	sanitized.Headers = withoutAuthorization(cfg.Headers)
+	sanitized.LLMFallbacks = nil
+	for _, fallback := range cfg.LLMFallbacks {
+		fallback.Token = ""
+		fallback.Headers = withoutAuthorization(fallback.Headers)
+		sanitized.LLMFallbacks = append(sanitized.LLMFallbacks, fallback)
+	}
+	return sanitized
+}
+
+// withoutAuthorization returns a copy of headers without the Authorization header
+func withoutAuthorization(headers map[string]string) map[string]string {
+	var copied map[string]string
+	for key, value := range headers {
+		if strings.EqualFold(key, "Authorization") {
+			continue
+		}
+		if copied == nil {
+			copied = map[string]string{}
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+// writeTarFile adds a single file to a tar archive
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s to backup: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to backup: %w", name, err)
+	}
+	return nil
+}
+
+// readBackupArchive reads every file of a backup archive into memory
+func readBackupArchive(path string) (map[string][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup %s: %w", filepath.Base(path), err)
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup %s: %w", filepath.Base(path), err)
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from backup: %w", header.Name, err)
+		}
+		files[header.Name] = data
+	}
+	return files, nil
+}
+
+// readBackupManifest reads only the manifest of a backup archive
+func readBackupManifest(path string) (*backupManifest, error) {
+	files, err := readBackupArchive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := files[backupManifestName]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a plannet backup (no manifest)", filepath.Base(path))
+	}
+
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// readVerifiedBackup loads a backup, pulling unchanged files from the archives
+// an incremental backup refers to, and checks every file against its checksum
+func readVerifiedBackup(path string) (*backupManifest, map[string][]byte, []byte, error) {
+	files, err := readBackupArchive(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	data, ok := files[backupManifestName]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%s is not a plannet backup (no manifest)", filepath.Base(path))
+	}
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse backup manifest: %w", err)
+	}
+
+	archives := map[string]map[string][]byte{filepath.Base(path): files}
+	contents := map[string][]byte{}
+	for _, f := range manifest.Files {
+		if filepath.Base(f.Path) != f.Path {
+			return nil, nil, nil, fmt.Errorf("backup contains an invalid file name: %s", f.Path)
+		}
+
+		archive, ok := archives[f.Archive]
+		if !ok {
+			// Referenced archives are expected next to the incremental backup
+			archive, err = readBackupArchive(filepath.Join(filepath.Dir(path), f.Archive))
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("%s is needed for %s: %w", f.Archive, f.Path, err)
+			}
+			archives[f.Archive] = archive
+		}
+
+		content, ok := archive["db/"+f.Path]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("%s is missing from %s", f.Path, f.Archive)
+		}
+
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != f.SHA256 || int64(len(content)) != f.Size {
+			return nil, nil, nil, fmt.Errorf("checksum mismatch for %s", f.Path)
+		}
+		contents[f.Path] = content
+	}
+
+	return &manifest, contents, files[backupConfigName], nil
+}
+
+// replaceDBFiles replaces the content of the database directory with the restored files
+func replaceDBFiles(dbDir string, contents map[string][]byte) error {
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dbDir)
+	if err != nil {
+		return fmt.Errorf("failed to read database directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if _, restored := contents[entry.Name()]; restored {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dbDir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+	}
+
+	for name, data := range contents {
+		tmpFile := filepath.Join(dbDir, name+".tmp")
+		if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if err := os.Rename(tmpFile, filepath.Join(dbDir, name)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// restoreFallbackCredentials copies the token and Authorization header of
+// each current LLM fallback to the restored fallback of the same endpoint
+func restoreFallbackCredentials(restored, current []config.LLMEndpoint) {
+	for i := range restored {
+		for _, fallback := range current {
+			if fallback.Provider != restored[i].Provider || fallback.BaseURL != restored[i].BaseURL {
+				continue
+			}
+			restored[i].Token = fallback.Token
+			for key, value := range fallback.Headers {
+				if strings.EqualFold(key, "Authorization") {
+					if restored[i].Headers == nil {
+						restored[i].Headers = map[string]string{}
+					}
+					restored[i].Headers[key] = value
+				}
+			}
+			break
+		}
+	}
+}
+
+// restoreConfig restores the configuration from a backup, keeping current credentials
+func restoreConfig(data []byte) error {
+	var restored config.Config
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	if current, err := config.Load(); err == nil {
+		restoredFields := restored.CredentialFields()
+		for i, field := range current.CredentialFields() {
+			*restoredFields[i] = *field
+		}
+		for key, value := range current.Headers {
+			if strings.EqualFold(key, "Authorization") {
+				if restored.Headers == nil {
+					restored.Headers = map[string]string{}
+				}
+				restored.Headers[key] = value
+			}
+		}
+		restoreFallbackCredentials(restored.LLMFallbacks, current.LLMFallbacks)
+	}
+
+	return config.Save(&restored)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestIncrementalBackupAndRestore(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	dbDir, err := openDB()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	completed := TrackedWork{ID: "test-1", Description: "Done", StartTime: start, EndTime: start.Add(30 * time.Minute), Status: "completed"}
+	if err := saveTrackedWork(completed); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	backupDir := filepath.Join(tempDir, "backups")
+	fullPath := filepath.Join(backupDir, "plannet-backup-20240501-090000.tar.gz")
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		t.Fatalf("Failed to create backup dir: %v", err)
+	}
+	full, err := writeBackup(fullPath, dbDir, nil, "")
+	if err != nil {
+		t.Fatalf("Failed to write full backup: %v", err)
+	}
+
+	// Start new work, then take an incremental backup
+	active := TrackedWork{ID: "test-2", Description: "In progress", StartTime: time.Now(), Status: "active"}
+	if err := saveTrackedWork(active); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	incPath := filepath.Join(backupDir, "plannet-backup-20240501-100000.tar.gz")
+	inc, err := writeBackup(incPath, dbDir, full, filepath.Base(fullPath))
+	if err != nil {
+		t.Fatalf("Failed to write incremental backup: %v", err)
+	}
+
+	for _, f := range inc.Files {
+		wantArchive := filepath.Base(fullPath)
+		if f.Path == "active.json" {
+			wantArchive = filepath.Base(incPath)
+		}
+		if f.Archive != wantArchive {
+			t.Errorf("Expected %s to be stored in %s, got %s", f.Path, wantArchive, f.Archive)
+		}
+	}
+
+	// Lose everything, then restore from the incremental backup
+	if err := deleteTrackedWork("test-1"); err != nil {
+		t.Fatalf("Failed to delete work: %v", err)
+	}
+	if err := deleteTrackedWork("test-2"); err != nil {
+		t.Fatalf("Failed to delete work: %v", err)
+	}
+
+	_, contents, _, err := readVerifiedBackup(incPath)
+	if err != nil {
+		t.Fatalf("Failed to verify backup: %v", err)
+	}
+	if err := replaceDBFiles(dbDir, contents); err != nil {
+		t.Fatalf("Failed to restore backup: %v", err)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(trackedWork) != 2 {
+		t.Errorf("Expected 2 tracked work items after restore, got %d", len(trackedWork))
+	}
+}
+
+func TestBackupExcludesTokens(t *testing.T) {
+	cfg := &config.Config{
+		JiraToken:   "jira-secret-token",
+		LLMToken:    "llm-secret-token",
+		GitHubToken: "github-secret-token",
+		GitLabToken: "gitlab-secret-token",
+		Headers: map[string]string{
+			"Authorization": "Bearer llm-secret-token",
+			"X-Team":        "core",
+		},
+		LLMFallbacks: []config.LLMEndpoint{
+			{Provider: "anthropic", Model: "m", Token: "fallback-secret-token"},
+			{Provider: "ollama", Model: "m", Headers: map[string]string{"authorization": "Basic c2VjcmV0"}},
+		},
+	}
+	sanitized := sanitizedConfig(cfg)
+
+	if sanitized.JiraToken != "" || sanitized.LLMToken != "" || sanitized.GitHubToken != "" || sanitized.GitLabToken != "" {
+		t.Error("Expected tokens to be removed from backed up config")
+	}
+	if _, ok := sanitized.Headers["Authorization"]; ok {
+		t.Error("Expected Authorization header to be removed from backed up config")
+	}
+	if sanitized.Headers["X-Team"] != "core" {
+		t.Error("Expected other headers to be kept")
+	}
+	if sanitized.LLMFallbacks[0].Token != "" || sanitized.LLMFallbacks[1].Headers != nil {
+		t.Errorf("Expected LLM fallback credentials to be removed, got %+v", sanitized.LLMFallbacks)
+	}
+	if cfg.JiraToken == "" || cfg.LLMFallbacks[0].Token == "" || cfg.LLMFallbacks[1].Headers == nil {
+		t.Error("Expected the original config to be left untouched")
+	}
+}
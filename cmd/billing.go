@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// billingTotals summarises billable work
+type billingTotals struct {
+	Time   time.Duration
+	Amount float64
+}
+
+// hourlyRate returns the hourly rate for work, given the project it belongs to.
+// The first of the work's tags with a rate wins, then the project's rate,
+// then the default rate.
+func hourlyRate(work TrackedWork, project string, rates config.Rates) float64 {
+	for _, tag := range work.Tags {
+		if rate, ok := rates.Tags[tag]; ok {
+			return rate
+		}
+	}
+	if rate, ok := rates.Projects[project]; ok && project != "" {
+		return rate
+	}
+	return rates.Default
+}
+
+// billableAmount returns the value of work at its hourly rate, rounded to
+// cents. Work that is not billable is worth nothing.
+func billableAmount(work TrackedWork, project string, rates config.Rates) float64 {
+	if !work.Billable {
+		return 0
+	}
+	amount := work.Duration().Hours() * hourlyRate(work, project, rates)
+	return math.Round(amount*100) / 100
+}
+
+// sumBillable totals the billable time and amount of tracked work. Work
+// without a project of its own is taken to belong to defaultProject.
+func sumBillable(trackedWork []TrackedWork, defaultProject string, rates config.Rates) billingTotals {
+	var totals billingTotals
+	for _, work := range trackedWork {
+		if !work.Billable {
+			continue
+		}
+		totals.Time += work.Duration()
+		totals.Amount += billableAmount(work, workProject(work, defaultProject), rates)
+	}
+	totals.Amount = math.Round(totals.Amount*100) / 100
+	return totals
+}
+
+// workProject returns the project of work, falling back to defaultProject
+func workProject(work TrackedWork, defaultProject string) string {
+	if work.Project != "" {
+		return work.Project
+	}
+	return defaultProject
+}
+
+// currentProjectName returns the name of the project work is currently
+// tracked in, or "" when work is not scoped per repository
+func currentProjectName() string {
+	if project := scopedProject(); project != nil {
+		return project.Name
+	}
+	return ""
+}
+
+// formatAmount formats a billable amount with the configured currency
+func formatAmount(amount float64, rates config.Rates) string {
+	if rates.Currency == "" {
+		return fmt.Sprintf("%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, rates.Currency)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestBillableTotals(t *testing.T) {
+	rates := config.Rates{
+		Currency: "EUR",
+		Default:  80,
+		Projects: map[string]float64{"webshop": 100},
+		Tags:     map[string]float64{"support": 60},
+	}
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	work := func(hours float64, billable bool, project string, tags ...string) TrackedWork {
+		return TrackedWork{
+			StartTime: start,
+			EndTime:   start.Add(time.Duration(hours * float64(time.Hour))),
+			Status:    "completed",
+			Billable:  billable,
+			Project:   project,
+			Tags:      tags,
+		}
+	}
+
+	tests := []struct {
+		name string
+		work TrackedWork
+		rate float64
+		want float64
+	}{
+		{"default rate", work(1.5, true, ""), 80, 120},
+		{"project rate", work(2, true, "webshop"), 100, 200},
+		{"tag rate wins over project", work(1, true, "webshop", "support"), 60, 60},
+		{"not billable", work(3, false, "webshop"), 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rate := hourlyRate(tt.work, tt.work.Project, rates); rate != tt.rate {
+				t.Errorf("Expected rate %.2f, got %.2f", tt.rate, rate)
+			}
+			if amount := billableAmount(tt.work, tt.work.Project, rates); amount != tt.want {
+				t.Errorf("Expected amount %.2f, got %.2f", tt.want, amount)
+			}
+		})
+	}
+
+	var all []TrackedWork
+	for _, tt := range tests {
+		all = append(all, tt.work)
+	}
+	totals := sumBillable(all, "", rates)
+	if totals.Time != 4*time.Hour+30*time.Minute {
+		t.Errorf("Expected 4h 30m billable, got %v", totals.Time)
+	}
+	if totals.Amount != 380 {
+		t.Errorf("Expected total 380.00, got %.2f", totals.Amount)
+	}
+	if got := formatAmount(totals.Amount, rates); got != "380.00 EUR" {
+		t.Errorf("Expected 380.00 EUR, got %s", got)
+	}
+}
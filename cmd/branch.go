@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/spf13/cobra"
+)
+
+// maxBranchSlugLength limits the part of a branch name taken from the ticket summary
+const maxBranchSlugLength = 50
+
+// branchCmd represents the branch command
+var branchCmd = &cobra.Command{
+	Use:   "branch <ticket>",
+	Short: "Create a branch for a ticket and check it out",
+	Long: `Create a branch for a ticket and check it out. When a ticket system is
+configured, the ticket's summary is added to the branch name:
+
+  plannet branch PROJ-123            # feature/PROJ-123-fix-login-timeout
+  plannet branch PROJ-124 --type bugfix --track
+
+If the branch already exists it is checked out instead. Use --track to start
+tracking work on the ticket straight away, setting any active work aside as
+'plannet switch' does.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		branchType, _ := cmd.Flags().GetString("type")
+		track, _ := cmd.Flags().GetBool("track")
+		runBranch(cmd, args[0], branchType, track)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+	branchCmd.Flags().String("type", "feature", "Prefix of the branch name, such as feature or bugfix")
+	branchCmd.Flags().Bool("track", false, "Start tracking work on the ticket")
+}
+
+func runBranch(cmd *cobra.Command, ticketID, branchType string, track bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if err := validateTicketID(ticketID); err != nil {
+		fmt.Println("Invalid ticket ID:", err)
+		setExitCode(exitUsage)
+		return
+	}
+	if !isGitRepo(".") {
+		fmt.Println("Not in a git repository.")
+		return
+	}
+
+	// The summary only makes the branch name more descriptive, so carry on without it
+	summary := ""
+	if system, err := systems.New(cfg); err == nil {
+		if key, err := system.ResolveKey(ticketID); err == nil {
+			ticket, err := system.View(cmd.Context(), key)
+			if err != nil {
+				fmt.Printf("Could not fetch %s from %s, creating the branch without its summary: %v\n", ticketID, ticketSystemTitle(system), err)
+			} else {
+				summary = ticket.Summary
+			}
+		}
+	}
+
+	branch := ticketBranchName(branchType, ticketID, summary)
+	created, err := checkoutBranch(branch)
+	if err != nil {
+		printError("Error checking out branch:", err)
+		return
+	}
+	if created {
+		fmt.Printf("Created and checked out %s\n", branch)
+	} else {
+		fmt.Printf("Checked out existing branch %s\n", branch)
+	}
+
+	if track {
+		description := summary
+		if description == "" {
+			description = ticketID
+		}
+		runSwitch([]string{description}, false, []string{ticketID}, nil, 0, false)
+	}
+}
+
+// ticketBranchName builds a branch name such as feature/PROJ-123-fix-login-timeout
+func ticketBranchName(branchType, ticketID, summary string) string {
+	name := ticketID
+	if slug := slugify(summary, maxBranchSlugLength); slug != "" {
+		name += "-" + slug
+	}
+	if branchType != "" {
+		name = strings.TrimSuffix(branchType, "/") + "/" + name
+	}
+	return name
+}
+
+// slugify turns text into lowercase words joined by hyphens, cut at a word
+// boundary so it is no longer than maxLength
+func slugify(text string, maxLength int) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r > unicode.MaxASCII || (!unicode.IsLetter(r) && !unicode.IsDigit(r))
+	})
+
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len(next) > maxLength {
+			break
+		}
+		slug = next
+	}
+	return slug
+}
+
+// checkoutBranch checks out a branch, creating it from the current HEAD if it
+// doesn't exist yet, and reports whether it was created. Branches are
+// changed through the git binary, as the git backends only read repositories.
+func checkoutBranch(branch string) (bool, error) {
+	if err := exec.Command("git", "check-ref-format", "--branch", branch).Run(); err != nil {
+		return false, fmt.Errorf("%s is not a valid branch name", branch)
+	}
+
+	created := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() != nil
+	args := []string{"checkout", branch}
+	if created {
+		args = []string{"checkout", "-b", branch}
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return created, nil
+}
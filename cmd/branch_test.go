@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestTicketBranchName(t *testing.T) {
+	tests := []struct {
+		branchType string
+		summary    string
+		want       string
+	}{
+		{"feature", "Fix login timeout", "feature/PROJ-123-fix-login-timeout"},
+		{"bugfix/", "Crash when saving: \"null\" user!", "bugfix/PROJ-123-crash-when-saving-null-user"},
+		{"feature", "", "feature/PROJ-123"},
+		{"", "Fix login", "PROJ-123-fix-login"},
+		{"feature", "Make the very long summary of this ticket fit into a reasonably short branch name", "feature/PROJ-123-make-the-very-long-summary-of-this-ticket-fit-into"},
+	}
+
+	for _, tt := range tests {
+		if got := ticketBranchName(tt.branchType, "PROJ-123", tt.summary); got != tt.want {
+			t.Errorf("ticketBranchName(%q, %q) = %q, want %q", tt.branchType, tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestCheckoutBranch(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# Test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := exec.Command("git", "add", "README.md").Run(); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if err := exec.Command("git", "commit", "-m", "Initial commit").Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	base, err := getCurrentBranch()
+	if err != nil {
+		t.Fatalf("Failed to get current branch: %v", err)
+	}
+
+	created, err := checkoutBranch("feature/PROJ-1-login")
+	if err != nil || !created {
+		t.Fatalf("Expected the branch to be created, got %v (%v)", created, err)
+	}
+	if branch, _ := getCurrentBranch(); branch != "feature/PROJ-1-login" {
+		t.Errorf("Expected to be on feature/PROJ-1-login, got %s", branch)
+	}
+
+	// Checking out an existing branch doesn't create it again
+	if _, err := checkoutBranch(base); err != nil {
+		t.Fatalf("Failed to check out %s: %v", base, err)
+	}
+	created, err = checkoutBranch("feature/PROJ-1-login")
+	if err != nil || created {
+		t.Errorf("Expected the existing branch to be checked out, got %v (%v)", created, err)
+	}
+
+	if _, err := checkoutBranch("feature/bad..name"); err == nil {
+		t.Error("Expected an error for an invalid branch name")
+	}
+}
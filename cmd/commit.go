@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/spf13/cobra"
+)
+
+// maxCommitDiffLength limits how much of the staged diff is sent to the LLM
+const maxCommitDiffLength = 12000
+
+// defaultCommitPrompt is the prompt template used when commit_prompt is not set
+const defaultCommitPrompt = `Write a git commit message for the staged changes below.
+Use a subject line of at most 72 characters in the imperative mood, then a
+blank line and a short body explaining what changed and why. Reply with the
+commit message only.
+
+Branch: {{.Branch}}
+
+{{.Diff}}`
+
+// commitPromptData is the data available to the commit prompt template
+type commitPromptData struct {
+	Diff   string
+	Branch string
+	Ticket string
+}
+
+// commitCmd represents the commit command
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Commit the staged changes with a message written by the LLM",
+	Long: `Commit the staged changes with a message suggested by the LLM from the
+staged diff. The ticket ID of the current branch is added to the subject if
+the message doesn't mention it already. You can commit the suggestion as it
+is, edit it first, or cancel.
+
+The prompt can be customised with commit_prompt in your configuration, a
+template that can use {{.Diff}}, {{.Branch}} and {{.Ticket}}.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCommit()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commitCmd)
+}
+
+func runCommit() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if !llm.IsConfigured(cfg) {
+		fmt.Println("LLM integration is not configured. Run 'plannet init' to set it up.")
+		setExitCode(exitConfig)
+		return
+	}
+	if !isGitRepo(".") {
+		fmt.Println("Not in a git repository.")
+		return
+	}
+
+	diff, err := getGitBackend().StagedDiff(".")
+	if err != nil {
+		printError("Error getting staged changes:", err)
+		return
+	}
+	if strings.TrimSpace(diff) == "" {
+		fmt.Println("Nothing staged to commit. Stage changes with 'git add' first.")
+		return
+	}
+
+	branch, _ := getCurrentBranch()
+	ticketID := extractTicketID(branch, cfg.TicketPrefixes)
+
+	prompt, err := buildCommitPrompt(cfg.CommitPrompt, commitPromptData{
+		Diff:   truncateDiff(diff, maxCommitDiffLength),
+		Branch: branch,
+		Ticket: ticketID,
+	})
+	if err != nil {
+		printError("Error building commit prompt:", err)
+		return
+	}
+
+	printInfo("Writing commit message...")
+	response, err := llm.NewGenerator(cfg).Generate(prompt)
+	if err != nil {
+		printError("Error generating commit message:", err)
+		return
+	}
+	message := addTicketToMessage(cleanCommitMessage(response), ticketID, cfg.TicketPrefixes)
+	if message == "" {
+		fmt.Println("The LLM didn't suggest a commit message.")
+		return
+	}
+
+	fmt.Printf("\n%s\n\n", message)
+
+	selectPrompt := promptui.Select{
+		Label: "Commit with this message?",
+		Items: []string{"Commit", "Edit before committing", "Cancel"},
+	}
+	index, _, err := selectPrompt.Run()
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			fmt.Println("\nOperation cancelled by user.")
+			return
+		}
+		printError("Error getting confirmation:", err)
+		return
+	}
+
+	args := []string{"commit", "-m", message}
+	switch index {
+	case 1:
+		args = append(args, "--edit")
+	case 2:
+		fmt.Println("Commit cancelled.")
+		return
+	}
+
+	// Commits are made by the git binary so hooks and signing work as usual
+	gitCmd := exec.Command("git", args...)
+	gitCmd.Stdin = os.Stdin
+	gitCmd.Stdout = os.Stdout
+	gitCmd.Stderr = os.Stderr
+	if err := gitCmd.Run(); err != nil {
+		printError("Error committing:", err)
+	}
+}
+
+// buildCommitPrompt renders the commit prompt template, falling back to the default
+func buildCommitPrompt(tmpl string, data commitPromptData) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = defaultCommitPrompt
+	}
+	t, err := template.New("commit_prompt").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid commit_prompt template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("invalid commit_prompt template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// truncateDiff shortens a diff to at most maxLength bytes, ending at a line break
+func truncateDiff(diff string, maxLength int) string {
+	if len(diff) <= maxLength {
+		return diff
+	}
+	cut := diff[:maxLength]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "\n[diff truncated]"
+}
+
+// cleanCommitMessage strips code fences and surrounding whitespace from a suggested message
+func cleanCommitMessage(response string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(response), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// addTicketToMessage prefixes the subject with the ticket ID unless the message already references it
+func addTicketToMessage(message, ticketID string, prefixes []string) string {
+	if message == "" || ticketID == "" || extractTicketIDFromMessage(message, prefixes) == ticketID {
+		return message
+	}
+	return ticketID + ": " + message
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildCommitPrompt(t *testing.T) {
+	data := commitPromptData{Diff: "+added line", Branch: "feature/PROJ-1-login", Ticket: "PROJ-1"}
+
+	prompt, err := buildCommitPrompt("", data)
+	if err != nil {
+		t.Fatalf("Failed to build default prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "+added line") || !strings.Contains(prompt, "feature/PROJ-1-login") {
+		t.Errorf("Expected the default prompt to include the diff and branch, got %q", prompt)
+	}
+
+	prompt, err = buildCommitPrompt("{{.Ticket}} on {{.Branch}}:\n{{.Diff}}", data)
+	if err != nil {
+		t.Fatalf("Failed to build custom prompt: %v", err)
+	}
+	if prompt != "PROJ-1 on feature/PROJ-1-login:\n+added line" {
+		t.Errorf("Unexpected custom prompt %q", prompt)
+	}
+
+	if _, err := buildCommitPrompt("{{.Missing", data); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestAddTicketToMessage(t *testing.T) {
+	prefixes := []string{"PROJ-"}
+	tests := []struct {
+		message string
+		ticket  string
+		want    string
+	}{
+		{"Fix login timeout", "PROJ-1", "PROJ-1: Fix login timeout"},
+		{"PROJ-1: Fix login timeout", "PROJ-1", "PROJ-1: Fix login timeout"},
+		{"Fix login timeout\n\nRefs: PROJ-1", "PROJ-1", "Fix login timeout\n\nRefs: PROJ-1"},
+		{"Fix login timeout", "", "Fix login timeout"},
+	}
+	for _, tt := range tests {
+		if got := addTicketToMessage(tt.message, tt.ticket, prefixes); got != tt.want {
+			t.Errorf("addTicketToMessage(%q, %q) = %q, want %q", tt.message, tt.ticket, got, tt.want)
+		}
+	}
+}
+
+func TestCleanCommitMessage(t *testing.T) {
+	got := cleanCommitMessage("```\nFix login timeout\n\nRetry once on 504.  \n```\n")
+	if got != "Fix login timeout\n\nRetry once on 504." {
+		t.Errorf("Unexpected cleaned message %q", got)
+	}
+	if got := truncateDiff("line one\nline two\n", 12); got != "line one\n[diff truncated]" {
+		t.Errorf("Unexpected truncated diff %q", got)
+	}
+}
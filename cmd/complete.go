@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -12,109 +13,212 @@ import (
 
 // completeCmd represents the complete command
 var completeCmd = &cobra.Command{
-	Use:   "complete [id]",
+	Use:   "complete [id...]",
 	Short: "Mark tracked work as complete",
 	Long: `Mark tracked work as complete.
-This command allows you to mark a piece of tracked work as finished,
-recording the end time.`,
+This command allows you to mark one or more pieces of tracked work as
+finished, recording the end time.
+
+Without an ID you can tick off several pieces of work at once. Use --all to
+complete all incomplete work, and --before to only consider work started
+before a date, e.g. to close out last week's forgotten entries:
+
+  plannet complete --all --before 2024-05-06`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runComplete(args)
+		all, _ := cmd.Flags().GetBool("all")
+		before, _ := cmd.Flags().GetString("before")
+		runComplete(args, all, before)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(completeCmd)
+	completeCmd.Flags().BoolP("all", "a", false, "Complete all incomplete work")
+	completeCmd.Flags().String("before", "", "Only complete work started before this date (YYYY-MM-DD)")
 }
 
-func runComplete(args []string) {
+func runComplete(args []string, all bool, before string) {
 	// Load configuration
-	_, err := config.Load()
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
+	if all && len(args) > 0 {
+		fmt.Println("Specify either work IDs or --all, not both.")
+		return
+	}
+
+	var cutoff time.Time
+	if before != "" {
+		cutoff, err = time.ParseInLocation(dateInputLayout, before, time.Local)
+		if err != nil {
+			fmt.Printf("Invalid --before date %q, expected YYYY-MM-DD\n", before)
+			setExitCode(exitUsage)
+			return
+		}
+	}
+
 	// Get tracked work
 	trackedWork, err := getTrackedWork()
 	if err != nil {
-		fmt.Println("Error getting tracked work:", err)
+		printError("Error getting tracked work:", err)
 		return
 	}
 
 	// Filter for incomplete work
 	var incompleteWork []TrackedWork
 	for _, work := range trackedWork {
-		if work.EndTime.IsZero() {
-			incompleteWork = append(incompleteWork, work)
+		if !work.EndTime.IsZero() {
+			continue
 		}
+		if !cutoff.IsZero() && !work.StartTime.Before(cutoff) {
+			continue
+		}
+		incompleteWork = append(incompleteWork, work)
 	}
 
 	if len(incompleteWork) == 0 {
 		fmt.Println("No incomplete work found.")
+		setExitCode(exitNotFound)
 		return
 	}
 
-	// Get work ID from args or prompt
-	var workID string
-	if len(args) > 0 {
-		workID = args[0]
-	} else {
-		// Create items for the prompt
-		var items []string
-		for _, work := range incompleteWork {
-			items = append(items, fmt.Sprintf("%s: %s", work.ID, work.Description))
+	// Get the work from args, --all or prompt
+	var selected []TrackedWork
+	switch {
+	case len(args) > 0:
+		for _, workID := range args {
+			work := findWorkByID(incompleteWork, workID)
+			if work == nil {
+				fmt.Printf("Work with ID %s not found.\n", workID)
+				return
+			}
+			selected = append(selected, *work)
 		}
-
-		// Create the prompt
-		prompt := promptui.Select{
-			Label: "Select work to complete",
-			Items: items,
-		}
-
-		// Run the prompt
-		index, _, err := prompt.Run()
+	case all:
+		selected = incompleteWork
+	default:
+		selected, err = selectWorkToComplete(incompleteWork)
 		if err != nil {
-			fmt.Println("Error selecting work:", err)
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error selecting work:", err)
+			return
+		}
+		if len(selected) == 0 {
+			fmt.Println("No work selected.")
 			return
 		}
-
-		workID = incompleteWork[index].ID
 	}
 
-	// Find the work
-	var work *TrackedWork
-	for i := range incompleteWork {
-		if incompleteWork[i].ID == workID {
-			work = &incompleteWork[i]
-			break
+	// Mark work as complete
+	now := time.Now()
+	for i := range selected {
+		completeWork(&selected[i], now)
+		if err := saveTrackedWork(selected[i]); err != nil {
+			printError("Error saving work:", err)
+			return
 		}
 	}
 
-	if work == nil {
-		fmt.Printf("Work with ID %s not found.\n", workID)
-		return
+	if len(selected) == 1 {
+		printCompletedWork(selected[0])
+	} else {
+		fmt.Printf("Marked %d pieces of work as complete:\n", len(selected))
+		for _, work := range selected {
+			fmt.Printf("  %s: %s (%s)\n", work.ID, work.Description, formatDuration(work.Duration()))
+		}
 	}
 
-	// Mark work as complete
-	work.EndTime = time.Now()
+	offerTicketTransitions(context.Background(), cfg, "completed", selected)
+}
 
-	// Save the work
-	err = saveTrackedWork(*work)
+// selectWorkToComplete lets the user tick off any number of pieces of work
+func selectWorkToComplete(incompleteWork []TrackedWork) ([]TrackedWork, error) {
+	items := make([]string, len(incompleteWork))
+	for i, work := range incompleteWork {
+		items[i] = fmt.Sprintf("%s: %s", work.ID, work.Description)
+	}
+	indexes, err := selectMany("Select work to complete", items)
 	if err != nil {
-		fmt.Println("Error saving work:", err)
-		return
+		return nil, err
 	}
 
+	var selected []TrackedWork
+	for _, i := range indexes {
+		selected = append(selected, incompleteWork[i])
+	}
+	return selected, nil
+}
+
+// selectMany lets the user check any number of items and returns the
+// indexes of the checked ones, in order
+func selectMany(label string, items []string) ([]int, error) {
+	checked := make([]bool, len(items))
+	cursor := 0
+	for {
+		count := 0
+		for _, c := range checked {
+			if c {
+				count++
+			}
+		}
+
+		// The first item finishes the selection, the others toggle an item
+		options := []string{fmt.Sprintf("Done (%d selected)", count)}
+		for i, item := range items {
+			box := "[ ]"
+			if checked[i] {
+				box = "[x]"
+			}
+			options = append(options, fmt.Sprintf("%s %s", box, item))
+		}
+
+		prompt := promptui.Select{
+			Label: label,
+			Items: options,
+			Size:  10,
+		}
+		index, _, err := prompt.RunCursorAt(cursor, 0)
+		if err != nil {
+			return nil, err
+		}
+		if index == 0 {
+			break
+		}
+		checked[index-1] = !checked[index-1]
+		cursor = index
+	}
+
+	var indexes []int
+	for i, c := range checked {
+		if c {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// printCompletedWork prints the details of a piece of completed work
+func printCompletedWork(work TrackedWork) {
 	fmt.Println("Work marked as complete!")
 	fmt.Printf("ID: %s\n", work.ID)
 	fmt.Printf("Description: %s\n", work.Description)
-	if work.TicketID != "" {
-		fmt.Printf("Ticket ID: %s\n", work.TicketID)
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(work.TicketIDs, ", "))
 	}
 	fmt.Printf("Start time: %s\n", work.StartTime.Format("2006-01-02 15:04"))
 	fmt.Printf("End time: %s\n", work.EndTime.Format("2006-01-02 15:04"))
+	fmt.Printf("Duration: %s\n", formatDuration(work.Duration()))
+	if work.EstimateMinutes > 0 {
+		fmt.Printf("Estimate: %s\n", formatEstimate(work))
+	}
 	if len(work.Tags) > 0 {
 		fmt.Printf("Tags: %s\n", strings.Join(work.Tags, ", "))
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompleteAllBefore(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	now := time.Now()
+	weekAgo := now.AddDate(0, 0, -7)
+	forgotten := []TrackedWork{
+		{ID: "old-1", Description: "Forgotten", StartTime: weekAgo, Status: "paused", Pauses: []PauseInterval{{Start: weekAgo.Add(time.Hour)}}},
+		{ID: "old-2", Description: "Also forgotten", StartTime: weekAgo.Add(2 * time.Hour), Status: "paused", Pauses: []PauseInterval{{Start: weekAgo.Add(3 * time.Hour)}}},
+		{ID: "today", Description: "Still going", StartTime: now.Add(-time.Hour), Status: "active"},
+	}
+	for _, work := range forgotten {
+		if err := saveTrackedWork(work); err != nil {
+			t.Fatalf("Failed to save work: %v", err)
+		}
+	}
+
+	runComplete(nil, true, now.AddDate(0, 0, -1).Format(dateInputLayout))
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	want := map[string]string{"old-1": "completed", "old-2": "completed", "today": "active"}
+	for _, work := range trackedWork {
+		if work.Status != want[work.ID] {
+			t.Errorf("Expected %s to be %s, got %s", work.ID, want[work.ID], work.Status)
+		}
+		if work.Status == "completed" && work.Duration() != time.Hour {
+			t.Errorf("Expected %s to keep its 1h duration, got %v", work.ID, work.Duration())
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultConfluenceTitle starts the title of report pages unless another
+// is configured
+const defaultConfluenceTitle = "Work report"
+
+// confluencePage is a Confluence page as the content API returns it
+type confluencePage struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Links struct {
+		Base  string `json:"base"`
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// confluenceReport is what a report page shows
+type confluenceReport struct {
+	Description string
+	GroupBy     string
+	Groups      []confluenceReportGroup
+	Total       string
+	Generated   time.Time
+}
+
+// confluenceReportGroup is a row of a report page, linked when it is a
+// ticket whose address is known
+type confluenceReportGroup struct {
+	Name     string
+	URL      string
+	Duration string
+	Percent  float64
+}
+
+// newConfluenceClient returns a client for the configured Confluence, which
+// is sent the Jira credentials
+func newConfluenceClient(cfg *config.Config) (*jiraClient, error) {
+	if cfg.Confluence.Space == "" {
+		return nil, fmt.Errorf("no Confluence space configured. Set space in the confluence section of your configuration")
+	}
+	if cfg.JiraToken == "" || (cfg.JiraURL == "" && cfg.Confluence.URL == "") {
+		return nil, fmt.Errorf("Atlassian credentials not found. Run 'plannet init' to set up Jira integration")
+	}
+	client := newJiraClient(cfg, cfg.JiraToken)
+	client.baseURL = strings.TrimSuffix(cfg.Confluence.URL, "/")
+	if client.baseURL == "" {
+		client.baseURL = strings.TrimSuffix(cfg.JiraURL, "/") + "/wiki"
+	}
+	return client, nil
+}
+
+// publishReport creates the Confluence page of a report, or updates it when
+// the space already has a page with its title, and returns its address
+func publishReport(ctx context.Context, cfg *config.Config, title, groupBy string, report []reportGroup, filter workFilter) (string, error) {
+	client, err := newConfluenceClient(cfg)
+	if err != nil {
+		setExitCode(exitConfig)
+		return "", err
+	}
+	body, err := renderConfluenceReport(cfg, groupBy, report, filter, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	space := cfg.Confluence.Space
+	page := map[string]interface{}{
+		"type":  "page",
+		"title": title,
+		"space": map[string]string{"key": space},
+		"body": map[string]interface{}{
+			"storage": map[string]string{"value": body, "representation": "storage"},
+		},
+	}
+
+	existing, err := findConfluencePage(ctx, client, space, title)
+	if err != nil {
+		return "", fmt.Errorf("failed to look for the report page: %w", err)
+	}
+	var result confluencePage
+	if existing != nil {
+		page["version"] = map[string]int{"number": existing.Version.Number + 1}
+		if err := client.do(ctx, "PUT", "/rest/api/content/"+existing.ID, page, &result); err != nil {
+			return "", fmt.Errorf("failed to update the report page: %w", err)
+		}
+	} else {
+		if cfg.Confluence.Parent != "" {
+			page["ancestors"] = []map[string]string{{"id": cfg.Confluence.Parent}}
+		}
+		if err := client.do(ctx, "POST", "/rest/api/content", page, &result); err != nil {
+			return "", fmt.Errorf("failed to create the report page: %w", err)
+		}
+	}
+	if result.Links.Base == "" {
+		result.Links.Base = client.baseURL
+	}
+	return result.Links.Base + result.Links.WebUI, nil
+}
+
+// findConfluencePage returns the page of a space with a title, or nil
+func findConfluencePage(ctx context.Context, client *jiraClient, space, title string) (*confluencePage, error) {
+	query := url.Values{"type": {"page"}, "spaceKey": {space}, "title": {title}, "expand": {"version"}}
+	var result struct {
+		Results []confluencePage `json:"results"`
+	}
+	if err := client.do(ctx, "GET", "/rest/api/content?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+	return &result.Results[0], nil
+}
+
+// reportPageTitle returns the title of the page of a report: the configured
+// start, followed by the first and last days the report covers. Reports of
+// the same days have the same title, so publishing again updates the page.
+func reportPageTitle(cfg *config.Config, filter workFilter, now time.Time) string {
+	title := cfg.Confluence.Title
+	if title == "" {
+		title = defaultConfluenceTitle
+	}
+	switch {
+	case !filter.From.IsZero() && !filter.To.IsZero():
+		// The end of the filter is exclusive, such as midnight after the last day
+		return fmt.Sprintf("%s %s – %s", title, filter.From.Format(dateInputLayout), filter.To.Add(-time.Nanosecond).Format(dateInputLayout))
+	case !filter.From.IsZero():
+		return title + " " + filter.From.Format(dateInputLayout)
+	case !filter.To.IsZero():
+		return title + " until " + filter.To.Add(-time.Nanosecond).Format(dateInputLayout)
+	}
+	return title + " " + now.Format(dateInputLayout)
+}
+
+// renderConfluenceReport renders a report in the storage format of
+// Confluence, with tickets linked to Jira
+func renderConfluenceReport(cfg *config.Config, groupBy string, report []reportGroup, filter workFilter, now time.Time) (string, error) {
+	page := confluenceReport{
+		Description: "Time spent" + describeReportRange(filter) + ", by " + groupBy + ".",
+		GroupBy:     strings.ToUpper(groupBy[:1]) + groupBy[1:],
+		Generated:   now,
+	}
+	var total time.Duration
+	for _, group := range report {
+		row := confluenceReportGroup{Name: group.Name, Duration: formatDuration(group.duration), Percent: group.Percent}
+		if groupBy == "ticket" && group.Name != noTicketGroup {
+			row.URL = jiraTicketURL(cfg, group.Name)
+		}
+		page.Groups = append(page.Groups, row)
+		total += group.duration
+	}
+	page.Total = formatDuration(total)
+
+	var buf bytes.Buffer
+	if err := confluenceReportTemplate.Execute(&buf, page); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// confluenceReportTemplate is a report page in the storage format, the
+// XHTML Confluence keeps pages in
+var confluenceReportTemplate = template.Must(template.New("confluence").Parse(`<p>{{.Description}}</p>
+<table><tbody>
+<tr><th>{{.GroupBy}}</th><th>Time</th><th>Share</th></tr>
+{{range .Groups}}<tr><td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{.Duration}}</td><td>{{printf "%.1f" .Percent}}%</td></tr>
+{{end}}<tr><td><strong>Total</strong></td><td><strong>{{.Total}}</strong></td><td></td></tr>
+</tbody></table>
+<p><em>Published by plannet on {{.Generated.Format "2006-01-02 15:04"}}.</em></p>
+`))
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestReportPageTitle(t *testing.T) {
+	monday := time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local)
+	now := time.Date(2024, 5, 10, 17, 0, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		title  string
+		filter workFilter
+		want   string
+	}{
+		{"since", "", workFilter{From: monday}, "Work report 2024-05-06"},
+		{"range", "", workFilter{From: monday, To: monday.AddDate(0, 0, 7)}, "Work report 2024-05-06 – 2024-05-12"},
+		{"until", "", workFilter{To: monday}, "Work report until 2024-05-05"},
+		{"any time", "", workFilter{}, "Work report 2024-05-10"},
+		{"configured title", "Team Rocket weekly", workFilter{From: monday}, "Team Rocket weekly 2024-05-06"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Confluence: config.ConfluenceSettings{Title: tt.title}}
+			if got := reportPageTitle(cfg, tt.filter, now); got != tt.want {
+				t.Errorf("reportPageTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderConfluenceReport(t *testing.T) {
+	cfg := &config.Config{JiraURL: "https://example.atlassian.net"}
+	report := []reportGroup{
+		{Name: "JIRA-1", Percent: 75, duration: 3 * time.Hour},
+		{Name: noTicketGroup, Percent: 25, duration: time.Hour},
+	}
+	since := time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local)
+	body, err := renderConfluenceReport(cfg, "ticket", report, workFilter{From: since}, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to render report: %v", err)
+	}
+	for _, want := range []string{
+		"<p>Time spent since Monday, 2024-05-06 00:00, by ticket.</p>",
+		"<tr><th>Ticket</th><th>Time</th><th>Share</th></tr>",
+		`<td><a href="https://example.atlassian.net/browse/JIRA-1">JIRA-1</a></td><td>3h</td><td>75.0%</td>`,
+		"<td>(no ticket)</td><td>1h</td><td>25.0%</td>",
+		"<td><strong>Total</strong></td><td><strong>4h</strong></td>",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected page to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestPublishReport(t *testing.T) {
+	var pages []string // Titles of the pages in the space
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Header.Get("Authorization") != "Basic secret" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		var page map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&page)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /wiki/rest/api/content":
+			query := r.URL.Query()
+			if query.Get("spaceKey") != "TEAM" {
+				t.Errorf("Unexpected query %s", r.URL.RawQuery)
+			}
+			if len(pages) > 0 && query.Get("title") == pages[0] {
+				w.Write([]byte(`{"results": [{"id": "77", "title": "` + pages[0] + `", "version": {"number": 3}}]}`))
+				return
+			}
+			w.Write([]byte(`{"results": []}`))
+		case "POST /wiki/rest/api/content":
+			ancestors, _ := page["ancestors"].([]interface{})
+			if len(ancestors) != 1 || ancestors[0].(map[string]interface{})["id"] != "12345" {
+				t.Errorf("Expected the page under its parent, got %v", page["ancestors"])
+			}
+			pages = append(pages, page["title"].(string))
+			w.Write([]byte(`{"id": "77", "_links": {"base": "https://example.atlassian.net/wiki", "webui": "/spaces/TEAM/pages/77"}}`))
+		case "PUT /wiki/rest/api/content/77":
+			version, _ := page["version"].(map[string]interface{})
+			if version["number"] != float64(4) {
+				t.Errorf("Expected the next version, got %v", page["version"])
+			}
+			w.Write([]byte(`{"id": "77", "_links": {"base": "https://example.atlassian.net/wiki", "webui": "/spaces/TEAM/pages/77"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JiraURL: server.URL, JiraToken: "secret", Confluence: config.ConfluenceSettings{Space: "TEAM", Parent: "12345"}}
+	report := []reportGroup{{Name: "JIRA-1", Percent: 100, duration: time.Hour}}
+	for i := 0; i < 2; i++ {
+		pageURL, err := publishReport(context.Background(), cfg, "Work report 2024-05-06", "ticket", report, workFilter{})
+		if err != nil {
+			t.Fatalf("Failed to publish report: %v", err)
+		}
+		if pageURL != "https://example.atlassian.net/wiki/spaces/TEAM/pages/77" {
+			t.Errorf("Unexpected page address %q", pageURL)
+		}
+	}
+	want := []string{"GET /wiki/rest/api/content", "POST /wiki/rest/api/content", "GET /wiki/rest/api/content", "PUT /wiki/rest/api/content/77"}
+	if strings.Join(requests, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Expected the page to be created, then updated, got %v", requests)
+	}
+}
+
+func TestPublishReportWithoutSpace(t *testing.T) {
+	resetExitCode(t)
+	cfg := &config.Config{JiraURL: "https://example.atlassian.net", JiraToken: "secret"}
+	if _, err := publishReport(context.Background(), cfg, "Work report", "ticket", nil, workFilter{}); err == nil || exitCode != exitConfig {
+		t.Errorf("Expected a configuration error, got %v (exit code %d)", err, exitCode)
+	}
+}
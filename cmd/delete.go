@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:     "delete [id]",
+	Aliases: []string{"cancel"},
+	Short:   "Delete tracked work",
+	Long: `Delete a piece of tracked work.
+This permanently removes the work from your history, whether it is active,
+paused or completed. You will be asked to confirm unless --force is given.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		runDelete(args, force)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+	deleteCmd.Flags().BoolP("force", "f", false, "Delete without asking for confirmation")
+}
+
+func runDelete(args []string, force bool) {
+	// Load configuration
+	_, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Get tracked work
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	if len(trackedWork) == 0 {
+		fmt.Println("No tracked work found.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	// Find the work to delete
+	var work *TrackedWork
+	if len(args) > 0 {
+		work = findWorkByID(trackedWork, args[0])
+		if work == nil {
+			fmt.Printf("Work with ID %s not found.\n", args[0])
+			return
+		}
+	} else {
+		work, err = selectTrackedWork("Select work to delete", trackedWork)
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error selecting work:", err)
+			return
+		}
+	}
+
+	if !force {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Delete %q (%s)", work.Description, work.Status),
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("Deletion cancelled.")
+			return
+		}
+	}
+
+	if err := deleteTrackedWork(work.ID); err != nil {
+		printError("Error deleting work:", err)
+		return
+	}
+
+	fmt.Printf("Deleted work %s: %s\n", work.ID, work.Description)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeleteTrackedWork(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Now().Add(-2 * time.Hour)
+	items := []TrackedWork{
+		{ID: "test-1", Description: "Completed", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "test-2", Description: "Paused", StartTime: start, Status: "paused"},
+		{ID: "test-3", Description: "Active", StartTime: start, Status: "active"},
+	}
+	for _, work := range items {
+		if err := saveTrackedWork(work); err != nil {
+			t.Fatalf("Failed to save work %s: %v", work.ID, err)
+		}
+	}
+
+	for _, id := range []string{"test-1", "test-3"} {
+		if err := deleteTrackedWork(id); err != nil {
+			t.Fatalf("Failed to delete work %s: %v", id, err)
+		}
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(trackedWork) != 1 || trackedWork[0].ID != "test-2" {
+		t.Errorf("Expected only test-2 to remain, got %+v", trackedWork)
+	}
+}
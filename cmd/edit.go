@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// timeInputLayout is the layout used when entering times interactively
+const timeInputLayout = "2006-01-02 15:04"
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit [id]",
+	Short: "Edit tracked work",
+	Long: `Edit a piece of tracked work.
+This command lets you fix the description, ticket ID, tags, and start or end
+times of work after the fact. By default you are prompted for each field;
+use --editor to edit the raw record in your configured editor instead.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		useEditor, _ := cmd.Flags().GetBool("editor")
+		runEdit(args, useEditor)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().BoolP("editor", "e", false, "Edit the record in your configured editor")
+}
+
+func runEdit(args []string, useEditor bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Get tracked work
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	if len(trackedWork) == 0 {
+		fmt.Println("No tracked work found.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	// Find the work to edit
+	var work *TrackedWork
+	if len(args) > 0 {
+		work = findWorkByID(trackedWork, args[0])
+		if work == nil {
+			fmt.Printf("Work with ID %s not found.\n", args[0])
+			return
+		}
+	} else {
+		work, err = selectTrackedWork("Select work to edit", trackedWork)
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error selecting work:", err)
+			return
+		}
+	}
+
+	var edited TrackedWork
+	if useEditor {
+		edited, err = editWorkInEditor(*work, cfg.Editor)
+	} else {
+		edited, err = editWorkInteractively(*work)
+	}
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			fmt.Println("\nOperation cancelled by user.")
+			return
+		}
+		printError("Error editing work:", err)
+		return
+	}
+
+	if err := validateEditedWork(*work, edited); err != nil {
+		fmt.Println("Invalid changes:", err)
+		setExitCode(exitUsage)
+		return
+	}
+
+	if err := saveTrackedWork(edited); err != nil {
+		printError("Error saving work:", err)
+		return
+	}
+
+	fmt.Println("Work updated!")
+	fmt.Printf("ID: %s\n", edited.ID)
+	fmt.Printf("Description: %s\n", edited.Description)
+	if len(edited.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(edited.TicketIDs, ", "))
+	}
+	fmt.Printf("Start time: %s\n", edited.StartTime.Format(timeInputLayout))
+	if !edited.EndTime.IsZero() {
+		fmt.Printf("End time: %s\n", edited.EndTime.Format(timeInputLayout))
+	}
+	if len(edited.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(edited.Tags, ", "))
+	}
+}
+
+// findWorkByID returns the work with the given ID, or nil if there is none
+func findWorkByID(trackedWork []TrackedWork, id string) *TrackedWork {
+	for i := range trackedWork {
+		if trackedWork[i].ID == id {
+			return &trackedWork[i]
+		}
+	}
+	return nil
+}
+
+// selectTrackedWork prompts the user to pick one piece of work
+func selectTrackedWork(label string, trackedWork []TrackedWork) (*TrackedWork, error) {
+	var items []string
+	for _, work := range trackedWork {
+		items = append(items, fmt.Sprintf("%s: %s (%s)", work.ID, work.Description, work.Status))
+	}
+
+	prompt := promptui.Select{
+		Label: label,
+		Items: items,
+	}
+
+	index, _, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return &trackedWork[index], nil
+}
+
+// editWorkInteractively prompts for each editable field, defaulting to the current values
+func editWorkInteractively(work TrackedWork) (TrackedWork, error) {
+	descriptionPrompt := promptui.Prompt{
+		Label:     "Description",
+		Default:   work.Description,
+		AllowEdit: true,
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("description cannot be empty")
+			}
+			return nil
+		},
+	}
+	description, err := descriptionPrompt.Run()
+	if err != nil {
+		return work, err
+	}
+	work.Description = strings.TrimSpace(description)
+
+	ticketPrompt := promptui.Prompt{
+		Label:     "Ticket IDs (optional, comma-separated)",
+		Default:   strings.Join(work.TicketIDs, ", "),
+		AllowEdit: true,
+		Validate:  validateTicketIDs,
+	}
+	ticketIDs, err := ticketPrompt.Run()
+	if err != nil {
+		return work, err
+	}
+	work.TicketIDs = parseTicketIDs(ticketIDs)
+
+	tagsPrompt := promptui.Prompt{
+		Label:     "Tags (comma-separated)",
+		Default:   strings.Join(work.Tags, ", "),
+		AllowEdit: true,
+	}
+	tagsStr, err := tagsPrompt.Run()
+	if err != nil {
+		return work, err
+	}
+	work.Tags = parseTagList(tagsStr)
+
+	startPrompt := promptui.Prompt{
+		Label:     "Start time (YYYY-MM-DD HH:MM)",
+		Default:   work.StartTime.Format(timeInputLayout),
+		AllowEdit: true,
+		Validate:  validateTimeInput,
+	}
+	startStr, err := startPrompt.Run()
+	if err != nil {
+		return work, err
+	}
+	if work.StartTime, err = parseTimeInput(startStr); err != nil {
+		return work, err
+	}
+
+	// Only completed work has an end time
+	if work.Status == "completed" {
+		endPrompt := promptui.Prompt{
+			Label:     "End time (YYYY-MM-DD HH:MM)",
+			Default:   work.EndTime.Format(timeInputLayout),
+			AllowEdit: true,
+			Validate:  validateTimeInput,
+		}
+		endStr, err := endPrompt.Run()
+		if err != nil {
+			return work, err
+		}
+		if work.EndTime, err = parseTimeInput(endStr); err != nil {
+			return work, err
+		}
+	}
+
+	return work, nil
+}
+
+// editWorkInEditor opens the work as JSON in the user's editor and reads back the result
+func editWorkInEditor(work TrackedWork, editor string) (TrackedWork, error) {
+	data, err := json.MarshalIndent(work, "", "  ")
+	if err != nil {
+		return work, fmt.Errorf("failed to marshal work data: %w", err)
+	}
+
+	editedData, err := editTextInEditor(string(data), editor, "plannet-edit-*.json")
+	if err != nil {
+		return work, err
+	}
+
+	var edited TrackedWork
+	if err := json.Unmarshal([]byte(editedData), &edited); err != nil {
+		return work, fmt.Errorf("failed to parse edited work: %w", err)
+	}
+	return edited, nil
+}
+
+// editTextInEditor opens text in the user's editor, falling back to $EDITOR,
+// and returns the edited text. The pattern names the temporary file, as for
+// os.CreateTemp, so its extension can select the editor's syntax mode.
+func editTextInEditor(text, editor, pattern string) (string, error) {
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return "", fmt.Errorf("no editor configured. Set 'editor' in your configuration or the EDITOR environment variable")
+	}
+
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.WriteString(text); err != nil {
+		tmpFile.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	tmpFile.Close()
+
+	// The editor setting may include arguments, e.g. "code --wait"
+	editorArgs := strings.Fields(editor)
+	editorCmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmpFile.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("editor exited with error: %w", err)
+	}
+
+	edited, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return string(edited), nil
+}
+
+// validateEditedWork checks that an edit leaves the work in a consistent state
+func validateEditedWork(original, edited TrackedWork) error {
+	if edited.ID != original.ID {
+		return fmt.Errorf("the ID of tracked work cannot be changed")
+	}
+	if edited.Status != original.Status {
+		return fmt.Errorf("the status cannot be changed by editing; use pause, resume or complete instead")
+	}
+	if strings.TrimSpace(edited.Description) == "" {
+		return fmt.Errorf("description cannot be empty")
+	}
+	if edited.StartTime.IsZero() {
+		return fmt.Errorf("start time cannot be empty")
+	}
+	if edited.Status == "completed" {
+		if edited.EndTime.IsZero() {
+			return fmt.Errorf("completed work must have an end time")
+		}
+		if !edited.EndTime.After(edited.StartTime) {
+			return fmt.Errorf("end time must be after start time")
+		}
+	} else if !edited.EndTime.IsZero() {
+		return fmt.Errorf("only completed work can have an end time")
+	}
+	return nil
+}
+
+// parseTagList splits a comma-separated list of tags, dropping empty entries
+func parseTagList(input string) []string {
+	var tags []string
+	for _, tag := range strings.Split(input, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// parseTimeInput parses a time entered by the user in the local time zone
+func parseTimeInput(input string) (time.Time, error) {
+	t, err := time.ParseInLocation(timeInputLayout, strings.TrimSpace(input), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected format YYYY-MM-DD HH:MM", input)
+	}
+	return t, nil
+}
+
+// validateTimeInput validates a time entered in a prompt
+func validateTimeInput(input string) error {
+	_, err := parseTimeInput(input)
+	return err
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateEditedWork(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	original := TrackedWork{
+		ID:          "test-1",
+		Description: "Original",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		Status:      "completed",
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(w *TrackedWork)
+		wantErr bool
+	}{
+		{"Description change", func(w *TrackedWork) { w.Description = "Fixed typo" }, false},
+		{"ID change", func(w *TrackedWork) { w.ID = "test-2" }, true},
+		{"Status change", func(w *TrackedWork) { w.Status = "active" }, true},
+		{"Empty description", func(w *TrackedWork) { w.Description = " " }, true},
+		{"End before start", func(w *TrackedWork) { w.EndTime = start.Add(-time.Minute) }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edited := original
+			tt.edit(&edited)
+			err := validateEditedWork(original, edited)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateEditedWork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEditedWorkIsPersisted(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Now().Add(-2 * time.Hour)
+	work := TrackedWork{
+		ID:          "test-1",
+		Description: "Tpyo in description",
+		StartTime:   start,
+		EndTime:     start.Add(time.Hour),
+		Status:      "completed",
+	}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	work.Description = "Typo in description"
+	work.TicketIDs = []string{"JIRA-42", "JIRA-43"}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save edited work: %v", err)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(trackedWork) != 1 {
+		t.Fatalf("Expected 1 tracked work item, got %d", len(trackedWork))
+	}
+	if trackedWork[0].Description != work.Description || !reflect.DeepEqual(trackedWork[0].TicketIDs, work.TicketIDs) {
+		t.Errorf("Expected edited work to be saved, got %+v", trackedWork[0])
+	}
+}
+
+func TestParseTagList(t *testing.T) {
+	got := parseTagList(" meeting, ,research ,")
+	want := []string{"meeting", "research"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTagList() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// estimateVariance returns how far the time spent on work is over (positive)
+// or under (negative) its estimate, and that difference as a percentage of
+// the estimate
+func estimateVariance(work TrackedWork) (time.Duration, float64) {
+	estimate := work.Estimate()
+	if estimate <= 0 {
+		return 0, 0
+	}
+	variance := work.Duration().Round(time.Minute) - estimate
+	return variance, float64(variance) / float64(estimate) * 100
+}
+
+// formatEstimate describes the estimate of work and how the actual time compares
+func formatEstimate(work TrackedWork) string {
+	estimate := work.Estimate()
+	if estimate <= 0 {
+		return ""
+	}
+
+	actual := "actual"
+	if work.Status != "completed" {
+		actual = "so far"
+	}
+
+	variance, percent := estimateVariance(work)
+	switch {
+	case work.Status != "completed" && variance <= 0:
+		return fmt.Sprintf("%s (%s %s, %s left)", formatDuration(estimate), formatDuration(work.Duration()), actual, formatDuration(-variance))
+	case variance == 0:
+		return fmt.Sprintf("%s (%s %s, on estimate)", formatDuration(estimate), formatDuration(work.Duration()), actual)
+	case variance > 0:
+		return fmt.Sprintf("%s (%s %s, %s over, +%.0f%%)", formatDuration(estimate), formatDuration(work.Duration()), actual, formatDuration(variance), percent)
+	default:
+		return fmt.Sprintf("%s (%s %s, %s under, %.0f%%)", formatDuration(estimate), formatDuration(work.Duration()), actual, formatDuration(-variance), percent)
+	}
+}
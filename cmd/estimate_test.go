@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatEstimate(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	completed := func(estimate int, took time.Duration) TrackedWork {
+		return TrackedWork{StartTime: start, EndTime: start.Add(took), Status: "completed", EstimateMinutes: estimate}
+	}
+
+	tests := []struct {
+		name string
+		work TrackedWork
+		want string
+	}{
+		{"no estimate", completed(0, time.Hour), ""},
+		{"over", completed(120, 150*time.Minute), "2h (2h 30m actual, 30m over, +25%)"},
+		{"under", completed(60, 45*time.Minute), "1h (45m actual, 15m under, -25%)"},
+		{"on estimate", completed(60, time.Hour), "1h (1h actual, on estimate)"},
+		{"in progress", TrackedWork{StartTime: time.Now().Add(-30 * time.Minute), Status: "active", EstimateMinutes: 60}, "1h (30m so far, 30m left)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatEstimate(tt.work); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
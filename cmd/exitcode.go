@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/plannet-ai/plannet/security"
+)
+
+// Exit codes, so that scripts can tell why plannet failed
+const (
+	exitOK       = 0
+	exitError    = 1 // Any other error
+	exitUsage    = 2 // Invalid arguments, flags or input
+	exitConfig   = 3 // The configuration is missing, invalid or incomplete
+	exitNetwork  = 4 // Jira, another ticket system or the LLM couldn't be reached
+	exitNotFound = 5 // There was nothing to show or act on
+)
+
+// exitCode is the code plannet exits with, as set by setExitCode
+var exitCode = exitOK
+
+// setExitCode sets the code plannet exits with. The first failure decides
+// the code, except that a more specific code replaces exitError.
+func setExitCode(code int) {
+	if exitCode == exitOK || exitCode == exitError {
+		exitCode = code
+	}
+}
+
+// exitCodeFor returns the exit code of an error
+func exitCodeFor(err error) int {
+	var loadErr *config.LoadError
+	var urlErr *url.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &loadErr):
+		return exitConfig
+	case errors.As(err, &urlErr), errors.As(err, &netErr),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.Is(err, security.ErrRateLimitExceeded),
+		errors.Is(err, llm.ErrUnavailable):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+// setExitCodeFor sets the exit code from the first error among args,
+// such as those of an error message, or to exitError without one
+func setExitCodeFor(args ...interface{}) {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			setExitCode(exitCodeFor(err))
+			return
+		}
+	}
+	setExitCode(exitError)
+}
+
+// printError prints an error message, like fmt.Println, and sets the exit
+// code from the error among args
+func printError(args ...interface{}) {
+	fprintError(os.Stdout, args...)
+}
+
+// printErrorf prints an error message, like fmt.Printf, and sets the exit
+// code from the error among args
+func printErrorf(format string, args ...interface{}) {
+	fprintErrorf(os.Stdout, format, args...)
+}
+
+// fprintError prints an error message to w, like fmt.Fprintln, and sets
+// the exit code from the error among args
+func fprintError(w io.Writer, args ...interface{}) {
+	setExitCodeFor(args...)
+	fmt.Fprintln(w, args...)
+}
+
+// fprintErrorf prints an error message to w, like fmt.Fprintf, and sets
+// the exit code from the error among args
+func fprintErrorf(w io.Writer, format string, args ...interface{}) {
+	setExitCodeFor(args...)
+	fmt.Fprintf(w, format, args...)
+}
+
+// printInfo prints a message that is only there to help, such as a hint
+// or that plannet is busy, like fmt.Println. --quiet leaves it out.
+func printInfo(args ...interface{}) {
+	if output.Quiet() {
+		return
+	}
+	fmt.Println(args...)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/plannet-ai/plannet/output"
+)
+
+// resetExitCode restores the exit code after a test
+func resetExitCode(t *testing.T) {
+	exitCode = exitOK
+	t.Cleanup(func() { exitCode = exitOK })
+}
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"missing configuration", &config.LoadError{Err: config.ErrNotFound}, exitConfig},
+		{"wrapped configuration error", fmt.Errorf("loading: %w", &config.LoadError{Err: errors.New("bad")}), exitConfig},
+		{"unreachable server", fmt.Errorf("failed to send Jira API request: %w", &url.Error{Op: "Get", URL: "https://jira", Err: errors.New("connection refused")}), exitNetwork},
+		{"timeout", context.DeadlineExceeded, exitNetwork},
+		{"LLM unavailable", fmt.Errorf("%w: too many failures", llm.ErrUnavailable), exitNetwork},
+		{"other error", errors.New("disk full"), exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err); got != tt.want {
+				t.Errorf("exitCodeFor(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetExitCode(t *testing.T) {
+	resetExitCode(t)
+
+	setExitCode(exitError)
+	setExitCode(exitNetwork)
+	if exitCode != exitNetwork {
+		t.Errorf("exit code = %d, want a specific code to replace exitError", exitCode)
+	}
+	setExitCode(exitNotFound)
+	if exitCode != exitNetwork {
+		t.Errorf("exit code = %d, want the first specific code kept", exitCode)
+	}
+}
+
+func TestFprintError(t *testing.T) {
+	resetExitCode(t)
+
+	fprintError(io.Discard, "Error loading configuration:", &config.LoadError{Err: config.ErrNotFound})
+	if exitCode != exitConfig {
+		t.Errorf("exit code = %d, want %d", exitCode, exitConfig)
+	}
+
+	exitCode = exitOK
+	fprintErrorf(io.Discard, "Error: %s\n", "something went wrong")
+	if exitCode != exitError {
+		t.Errorf("exit code without an error = %d, want %d", exitCode, exitError)
+	}
+}
+
+func TestNotFoundExitCode(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	resetExitCode(t)
+
+	captureStdout(t, func() { runList(nil, false, false, nil, workFilter{}) })
+	if exitCode != exitNotFound {
+		t.Errorf("exit code of listing no work = %d, want %d", exitCode, exitNotFound)
+	}
+}
+
+func TestPrintInfoQuiet(t *testing.T) {
+	defer output.SetQuiet(false)
+
+	output.SetQuiet(true)
+	if got := captureStdout(t, func() { printInfo("Planning your day...") }); got != "" {
+		t.Errorf("printInfo() when quiet printed %q", got)
+	}
+	output.SetQuiet(false)
+	if got := captureStdout(t, func() { printInfo("Planning your day...") }); got != "Planning your day...\n" {
+		t.Errorf("printInfo() = %q", got)
+	}
+}
@@ -1,14 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/timelog"
 )
 
 // exportCmd represents the export command
@@ -17,34 +20,90 @@ var exportCmd = &cobra.Command{
 	Short: "Export tracked work",
 	Long: `Export tracked work to various formats.
 This command allows you to export your tracked work to CSV, JSON, or other formats
-for use in other tools or for reporting.`,
+for use in other tools or for reporting.
+When work is scoped per repository, use --all to export work from every project.
+Export part of your work with --since and --until, --ticket, --tag and
+--status, such as --since 2024-05-01 --until 2024-05-31 --tag client-a.
+
+The html format is a page to share with people who don't use plannet, such
+as your manager, with a timeline of each day, the time spent on each ticket
+and the side quests committed over the same time:
+
+  plannet export html report.html
+
+The timesheet format totals completed work per day and ticket, rounded as
+set in the timesheet section of your configuration, or with --round,
+--rounding and --minimum, ready to paste into a timesheet tool:
+
+  plannet export timesheet --round 15 --rounding up --minimum 30
+
+The tempo format logs completed work as Tempo worklogs on its Jira tickets,
+rounded as set in the worklog section of your configuration, or with --round
+and --rounding. Tags are mapped to Tempo accounts and work attributes in the
+tempo section of your configuration. As with 'plannet jira worklog push',
+worklogs are only created once and updated when the work changes; use
+--dry-run to see what would be logged:
+
+  plannet export tempo --since monday --dry-run
+
+The toggl and harvest formats push completed work as time entries to Toggl
+Track and Harvest, rounded the same way, in the project and task its tags or
+ticket prefixes are mapped to in the toggl and harvest sections of your
+configuration:
+
+  plannet export harvest --since monday --dry-run`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runExport(args)
+		all, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		settings := config.TimesheetSettings{}
+		settings.RoundMinutes, _ = cmd.Flags().GetInt("round")
+		settings.Rounding, _ = cmd.Flags().GetString("rounding")
+		settings.MinimumMinutes, _ = cmd.Flags().GetInt("minimum")
+		filter, err := workFilterFromFlags(cmd)
+		if err != nil {
+			printError("Error:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		runExport(cmd.Context(), args, all, settings, filter, dryRun)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().BoolP("all", "a", false, "Export work from every project")
+	exportCmd.Flags().Int("round", 0, "Round timesheet or pushed time to a multiple of this many minutes")
+	exportCmd.Flags().String("rounding", "", "How timesheet or pushed time is rounded: nearest, up or down")
+	exportCmd.Flags().Int("minimum", 0, "The least time in minutes recorded for a ticket on a day")
+	exportCmd.Flags().Bool("dry-run", false, "Show the worklogs or time entries that would be pushed to Tempo, Toggl or Harvest")
+	addWorkFilterFlags(exportCmd, "")
 }
 
-func runExport(args []string) {
+func runExport(ctx context.Context, args []string, all bool, flags config.TimesheetSettings, filter workFilter, dryRun bool) {
 	// Load configuration
-	_, err := config.Load()
+	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
 	// Get tracked work
-	trackedWork, err := getTrackedWork()
+	var trackedWork []TrackedWork
+	if all {
+		trackedWork, err = getAllTrackedWork()
+	} else {
+		trackedWork, err = getTrackedWork()
+	}
 	if err != nil {
-		fmt.Println("Error getting tracked work:", err)
+		printError("Error getting tracked work:", err)
 		return
 	}
+	trackedWork = filter.apply(trackedWork)
 
 	if len(trackedWork) == 0 {
 		fmt.Println("No tracked work found.")
+		setExitCode(exitNotFound)
 		return
 	}
 
@@ -61,27 +120,95 @@ func runExport(args []string) {
 	}
 
 	// Export based on format
+	project := currentProjectName()
 	switch format {
 	case "csv":
-		err = exportCSV(trackedWork, outputPath)
+		err = exportCSV(trackedWork, outputPath, project, cfg.Rates)
 	case "json":
-		err = exportJSON(trackedWork, outputPath)
+		err = exportJSON(trackedWork, outputPath, project, cfg.Rates)
+	case "html":
+		err = exportHTML(cfg, trackedWork, outputPath, project, all)
+	case "timesheet":
+		settings := cfg.Timesheet
+		if flags.RoundMinutes > 0 {
+			settings.RoundMinutes = flags.RoundMinutes
+		}
+		if flags.Rounding != "" {
+			settings.Rounding = flags.Rounding
+		}
+		if flags.MinimumMinutes > 0 {
+			settings.MinimumMinutes = flags.MinimumMinutes
+		}
+		if err := validateRounding(settings.Rounding); err != nil {
+			printError("Error:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		err = exportTimesheet(trackedWork, outputPath, settings)
+	case "tempo", "toggl", "harvest":
+		// Pushed worklogs and time entries are recorded on the work, which is
+		// only saved to the current project
+		if all {
+			printErrorf("Error: --all is not supported by the %s format. Push the work of each project from its repository.\n", format)
+			setExitCode(exitUsage)
+			return
+		}
+		settings := cfg.Worklog
+		if flags.RoundMinutes > 0 {
+			settings.RoundMinutes = flags.RoundMinutes
+		}
+		if flags.Rounding != "" {
+			settings.Rounding = flags.Rounding
+		}
+		if err := validateRounding(settings.Rounding); err != nil {
+			printError("Error:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		if format == "tempo" {
+			err = exportTempo(ctx, cfg, trackedWork, settings, dryRun)
+		} else {
+			var service timelog.TimeEntryService
+			if service, err = newTimeEntryService(cfg, format); err != nil {
+				setExitCode(exitConfig)
+			} else {
+				err = exportTimeEntries(ctx, service, trackedWork, settings, dryRun)
+			}
+		}
+		if err != nil {
+			printError("Error pushing work:", err)
+		}
+		return
 	default:
 		fmt.Printf("Unsupported format: %s\n", format)
-		fmt.Println("Supported formats: csv, json")
+		fmt.Println("Supported formats: csv, json, html, timesheet, tempo, toggl, harvest")
+		setExitCode(exitUsage)
 		return
 	}
 
 	if err != nil {
-		fmt.Println("Error exporting work:", err)
+		printError("Error exporting work:", err)
 		return
 	}
 
-	fmt.Println("Export completed successfully!")
+	// Without a file the export is written to stdout, which must stay
+	// readable by 'plannet import' and other tools
+	status := os.Stdout
+	if outputPath == "" {
+		status = os.Stderr
+	}
+	fmt.Fprintln(status, "Export completed successfully!")
+
+	if totals := sumBillable(trackedWork, project, cfg.Rates); totals.Time > 0 {
+		fmt.Fprintf(status, "Billable: %s, %s\n", formatDuration(totals.Time), formatAmount(totals.Amount, cfg.Rates))
+	}
 }
 
+// csvTimeLayout is the layout of times in CSV exports, in local time
+const csvTimeLayout = "2006-01-02 15:04:05"
+
 // exportCSV exports tracked work to CSV
-func exportCSV(work []TrackedWork, outputPath string) error {
+func exportCSV(work []TrackedWork, outputPath string, project string, rates config.Rates) error {
 	// Create a new CSV writer
 	var writer *csv.Writer
 	var file *os.File
@@ -103,10 +230,24 @@ func exportCSV(work []TrackedWork, outputPath string) error {
 	err = writer.Write([]string{
 		"ID",
 		"Description",
-		"Ticket ID",
+		"Ticket IDs",
 		"Start Time",
 		"End Time",
 		"Tags",
+		"Status",
+		"Duration (minutes)",
+		"Paused (minutes)",
+		"Project",
+		"Notes",
+		"Estimate (minutes)",
+		"Variance (minutes)",
+		"Billable",
+		"Rate",
+		"Amount",
+		"Lines Added",
+		"Lines Removed",
+		"Files Changed",
+		"Files",
 	})
 	if err != nil {
 		return err
@@ -115,22 +256,36 @@ func exportCSV(work []TrackedWork, outputPath string) error {
 	// Write data
 	for _, w := range work {
 		// Format times
-		startTime := w.StartTime.Format("2006-01-02 15:04:05")
+		startTime := w.StartTime.Format(csvTimeLayout)
 		var endTime string
 		if w.EndTime.IsZero() {
 			endTime = ""
 		} else {
-			endTime = w.EndTime.Format("2006-01-02 15:04:05")
+			endTime = w.EndTime.Format(csvTimeLayout)
 		}
 
 		// Write row
 		err = writer.Write([]string{
 			w.ID,
 			w.Description,
-			w.TicketID,
+			strings.Join(w.TicketIDs, ";"),
 			startTime,
 			endTime,
 			strings.Join(w.Tags, ";"),
+			w.Status,
+			strconv.Itoa(int(w.Duration().Minutes())),
+			strconv.Itoa(int(w.PausedDuration().Minutes())),
+			w.Project,
+			formatNotes(w.Notes),
+			estimateColumn(w.EstimateMinutes),
+			varianceColumn(w),
+			strconv.FormatBool(w.Billable),
+			billingColumn(w.Billable, hourlyRate(w, workProject(w, project), rates)),
+			billingColumn(w.Billable, billableAmount(w, workProject(w, project), rates)),
+			strconv.Itoa(w.Context.LinesAdded),
+			strconv.Itoa(w.Context.LinesRemoved),
+			strconv.Itoa(len(w.Context.FileChurn)),
+			formatFileChurn(w.Context.FileChurn),
 		})
 		if err != nil {
 			return err
@@ -140,10 +295,76 @@ func exportCSV(work []TrackedWork, outputPath string) error {
 	return nil
 }
 
+// formatFileChurn formats the lines changed per file for CSV, as
+// "path (+added/-removed)" separated by semicolons
+func formatFileChurn(churn []FileChurn) string {
+	files := make([]string, 0, len(churn))
+	for _, file := range churn {
+		files = append(files, fmt.Sprintf("%s (+%d/-%d)", file.Path, file.Added, file.Removed))
+	}
+	return strings.Join(files, ";")
+}
+
+// estimateColumn formats an estimate for CSV, leaving it empty without one
+func estimateColumn(minutes int) string {
+	if minutes <= 0 {
+		return ""
+	}
+	return strconv.Itoa(minutes)
+}
+
+// varianceColumn formats the estimate variance of work for CSV
+func varianceColumn(w TrackedWork) string {
+	if w.EstimateMinutes <= 0 {
+		return ""
+	}
+	variance, _ := estimateVariance(w)
+	return strconv.Itoa(int(variance.Minutes()))
+}
+
+// billingColumn formats a rate or amount for CSV, leaving it empty for work that is not billable
+func billingColumn(billable bool, value float64) string {
+	if !billable {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// exportedWork is the JSON export representation of tracked work,
+// including durations computed from the recorded pauses
+type exportedWork struct {
+	TrackedWork
+	DurationMinutes int     `json:"duration_minutes"`
+	PausedMinutes   int     `json:"paused_minutes"`
+	Project         string  `json:"project,omitempty"`
+	VarianceMinutes *int    `json:"variance_minutes,omitempty"`
+	Rate            float64 `json:"rate,omitempty"`
+	Amount          float64 `json:"amount,omitempty"`
+}
+
 // exportJSON exports tracked work to JSON
-func exportJSON(work []TrackedWork, outputPath string) error {
+func exportJSON(work []TrackedWork, outputPath string, project string, rates config.Rates) error {
+	exported := make([]exportedWork, 0, len(work))
+	for _, w := range work {
+		exported = append(exported, exportedWork{
+			TrackedWork:     w,
+			DurationMinutes: int(w.Duration().Minutes()),
+			PausedMinutes:   int(w.PausedDuration().Minutes()),
+			Project:         w.Project,
+		})
+		if w.Billable {
+			exported[len(exported)-1].Rate = hourlyRate(w, workProject(w, project), rates)
+			exported[len(exported)-1].Amount = billableAmount(w, workProject(w, project), rates)
+		}
+		if w.EstimateMinutes > 0 {
+			variance, _ := estimateVariance(w)
+			minutes := int(variance.Minutes())
+			exported[len(exported)-1].VarianceMinutes = &minutes
+		}
+	}
+
 	// Convert to JSON
-	data, err := json.MarshalIndent(work, "", "  ")
+	data, err := json.MarshalIndent(exported, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
 
 	"github.com/plannet-ai/plannet/config"
@@ -16,29 +20,50 @@ var generateCmd = &cobra.Command{
 	Short: "Generate content using the LLM",
 	Long: `Generate content using the LLM.
 This command allows you to generate content based on a prompt.
-If no prompt is provided, it will use the --prompt flag.`,
+If no prompt is provided, it will use the --prompt flag.
+The content is shown as it is generated; press Ctrl+C to stop.
+
+With --create, the LLM drafts a ticket from the prompt instead, which is
+created in your ticket system once you have reviewed, and maybe edited, it.
+With --output json, the draft is printed as JSON for scripts, with the
+summary, description, type and labels of the ticket.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runGenerateCmd(args)
+		runGenerateCmd(cmd.Context(), args)
 	},
 }
 
 // generatePrompt is the prompt for content generation
 var generatePrompt string
 
+// generateCreate creates a ticket from the generated content
+var generateCreate bool
+
+// generateProject is where a created ticket goes
+var generateProject string
+
+// generateJSON prints a ticket draft as JSON, as --output json does
+var generateJSON bool
+
 func init() {
 	rootCmd.AddCommand(generateCmd)
+	supportJSONOutput(generateCmd)
 
 	// Add flags
 	generateCmd.Flags().StringVarP(&generatePrompt, "prompt", "p", "", "Prompt for content generation")
+	generateCmd.Flags().BoolVar(&generateCreate, "create", false, "Draft a ticket and create it in your ticket system")
+	generateCmd.Flags().StringVar(&generateProject, "project", "", "Where to create the ticket with --create, instead of the configured default")
+	generateCmd.Flags().BoolVar(&generateJSON, "json", false, "Draft a ticket and print it as JSON")
+	generateCmd.MarkFlagsMutuallyExclusive("create", "json")
+	generateCmd.Flags().MarkDeprecated("json", "use --output json instead")
 }
 
 // runGenerateCmd executes the generate command
-func runGenerateCmd(args []string) {
+func runGenerateCmd(ctx context.Context, args []string) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
@@ -49,24 +74,44 @@ func runGenerateCmd(args []string) {
 	}
 
 	if userPrompt == "" {
-		fmt.Println("Error: No prompt provided.")
+		printError("Error: No prompt provided.")
 		fmt.Println("Usage: plannet generate [prompt] or plannet generate --prompt \"your prompt\"")
 		return
 	}
 
+	if generateJSON || jsonOutput() {
+		if generateCreate {
+			printError("Error: use either --create or --output json, not both")
+			setExitCode(exitUsage)
+			return
+		}
+		runGenerateTicketJSON(ctx, cfg, userPrompt)
+		return
+	}
+	if generateCreate {
+		runGenerateTicket(ctx, cfg, userPrompt, generateProject)
+		return
+	}
+
 	// Create generator
 	generator := llm.NewGenerator(cfg)
 
-	// Generate content
-	content, err := generator.Generate(userPrompt)
-	if err != nil {
-		fmt.Println("Error generating content:", err)
+	// Generate content, showing it as it arrives until Ctrl+C
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	write, done := output.StreamOutput(cfg)
+	if _, err := generator.Stream(ctx, userPrompt, write); err != nil {
+		if errors.Is(err, context.Canceled) {
+			fmt.Println("\nStopped.")
+		} else {
+			printError("\nError generating content:", err)
+		}
 		return
 	}
 
 	// Handle output
-	if err := output.HandleOutput(content, cfg); err != nil {
-		fmt.Println("Error handling output:", err)
+	if err := done(); err != nil {
+		printError("Error handling output:", err)
 		return
 	}
 }
@@ -17,8 +17,8 @@ func runGenerate(args []string) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
@@ -29,7 +29,7 @@ func runGenerate(args []string) {
 	}
 
 	if userPrompt == "" {
-		fmt.Println("Error: No prompt provided.")
+		printError("Error: No prompt provided.")
 		fmt.Println("Usage: plannet generate [prompt] or plannet generate --prompt \"your prompt\"")
 		return
 	}
@@ -40,13 +40,13 @@ func runGenerate(args []string) {
 	// Generate content
 	content, err := generator.Generate(userPrompt)
 	if err != nil {
-		fmt.Println("Error generating content:", err)
+		printError("Error generating content:", err)
 		return
 	}
 
 	// Handle output
 	if err := output.HandleOutput(content, cfg); err != nil {
-		fmt.Println("Error handling output:", err)
+		printError("Error handling output:", err)
 		return
 	}
 }
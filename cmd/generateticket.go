@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/llm"
+)
+
+// generatedTicketSchema is the JSON schema of the ticket drafts the LLM writes.
+// Every property is required, as OpenAI's strict mode needs.
+var generatedTicketSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "summary": {"type": "string", "description": "A one-line title of the ticket"},
+    "description": {"type": "string", "description": "What needs doing and why, with acceptance criteria, in markdown"},
+    "type": {"type": "string", "description": "The kind of ticket, such as Bug, Task or Story"},
+    "labels": {"type": "array", "items": {"type": "string"}, "description": "Short lowercase labels, often none"}
+  },
+  "required": ["summary", "description", "type", "labels"],
+  "additionalProperties": false
+}`)
+
+// generatedTicket is a ticket the LLM drafted for --create, as it writes it
+type generatedTicket struct {
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Type        string   `json:"type"`
+	Labels      []string `json:"labels"`
+}
+
+// parseGeneratedTicket reads a ticket draft the LLM or the user wrote
+func parseGeneratedTicket(text string) (generatedTicket, error) {
+	var draft generatedTicket
+	if err := llm.DecodeJSON(text, &draft); err != nil {
+		return draft, err
+	}
+	draft.Summary = strings.TrimSpace(draft.Summary)
+	if draft.Summary == "" {
+		return draft, fmt.Errorf("the ticket has no summary")
+	}
+	return draft, nil
+}
+
+// String shows the draft for review
+func (d generatedTicket) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary: %s\n", d.Summary)
+	if d.Type != "" {
+		fmt.Fprintf(&b, "Type: %s\n", d.Type)
+	}
+	if len(d.Labels) > 0 {
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(d.Labels, ", "))
+	}
+	if d.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(d.Description))
+	}
+	return b.String()
+}
+
+// generateTicketConversation asks the LLM to draft a ticket for a system
+func generateTicketConversation(cfg *config.Config, systemTitle, prompt string) llm.Conversation {
+	system := fmt.Sprintf("You write tickets for %s from a description of the work to do. "+
+		"Keep the summary short and specific, and the description clear enough for someone new to the code.", systemTitle)
+	if cfg.SystemPrompt != "" {
+		system = cfg.SystemPrompt + "\n\n" + system
+	}
+	conv := llm.UserPrompt(system, prompt)
+	conv.Schema = generatedTicketSchema
+	return conv
+}
+
+// runGenerateTicket drafts a ticket with the LLM and creates it in the
+// configured ticket system, once the user has reviewed it
+func runGenerateTicket(ctx context.Context, cfg *config.Config, prompt, project string) {
+	if !isInteractive() {
+		printError("Error: --create asks to confirm the ticket, and needs a terminal.")
+		return
+	}
+	system, err := systems.New(cfg)
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+
+	printInfo("Drafting ticket...")
+	sendCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	reply, err := llm.SendJSON(sendCtx, cfg, generateTicketConversation(cfg, ticketSystemTitle(system), prompt))
+	stop()
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("Stopped.")
+		return
+	}
+	if err != nil {
+		printError("Error generating ticket:", err)
+		return
+	}
+	draft, err := parseGeneratedTicket(reply)
+	if err != nil {
+		printError("Error reading the generated ticket:", err)
+		return
+	}
+
+	for {
+		fmt.Printf("\n%s\n", draft)
+		selectPrompt := promptui.Select{
+			Label: fmt.Sprintf("Create this ticket in %s?", ticketSystemTitle(system)),
+			Items: []string{"Create", "Edit", "Cancel"},
+		}
+		index, _, err := selectPrompt.Run()
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error getting confirmation:", err)
+			return
+		}
+
+		switch index {
+		case 1:
+			draft = editGeneratedTicket(cfg, draft)
+			continue
+		case 2:
+			fmt.Println("Ticket not created.")
+			return
+		}
+
+		created, err := system.Create(ctx, systems.NewTicket{
+			Project:     project,
+			Type:        draft.Type,
+			Summary:     draft.Summary,
+			Description: draft.Description,
+			Labels:      draft.Labels,
+		})
+		if err != nil {
+			printError("Error creating ticket:", err)
+			return
+		}
+		fmt.Printf("Created ticket %s\n", created.Key)
+		if created.URL != "" {
+			fmt.Printf("URL: %s\n", created.URL)
+		}
+		return
+	}
+}
+
+// runGenerateTicketJSON drafts a ticket with the LLM and prints it as JSON.
+// Errors go to stderr, to keep stdout parseable.
+func runGenerateTicketJSON(ctx context.Context, cfg *config.Config, prompt string) {
+	systemTitle := "a ticket system"
+	if system, err := systems.New(cfg); err == nil {
+		systemTitle = ticketSystemTitle(system)
+	}
+
+	sendCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	reply, err := llm.SendJSON(sendCtx, cfg, generateTicketConversation(cfg, systemTitle, prompt))
+	stop()
+	if err != nil {
+		fprintError(os.Stderr, "Error generating ticket:", err)
+		return
+	}
+	draft, err := parseGeneratedTicket(reply)
+	if err != nil {
+		fprintError(os.Stderr, "Error reading the generated ticket:", err)
+		return
+	}
+	if draft.Labels == nil {
+		draft.Labels = []string{}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(draft); err != nil {
+		fprintError(os.Stderr, "Error printing ticket:", err)
+	}
+}
+
+// editGeneratedTicket opens a draft in the user's editor as JSON, and returns
+// it edited, or unchanged if the edit fails
+func editGeneratedTicket(cfg *config.Config, draft generatedTicket) generatedTicket {
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		printError("Error preparing the ticket for editing:", err)
+		return draft
+	}
+	text, err := editTextInEditor(string(data)+"\n", cfg.Editor, "plannet-ticket-*.json")
+	if err != nil {
+		printError("Error:", err)
+		return draft
+	}
+	edited, err := parseGeneratedTicket(text)
+	if err != nil {
+		printError("Error reading the edited ticket, keeping the previous one:", err)
+		return draft
+	}
+	return edited
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestParseGeneratedTicket(t *testing.T) {
+	reply := "```json\n" + `{"summary": " Add CSV export ", "description": "Export entries as CSV.", "type": "Story", "labels": ["export"]}` + "\n```"
+	ticket, err := parseGeneratedTicket(reply)
+	if err != nil {
+		t.Fatalf("parseGeneratedTicket() failed: %v", err)
+	}
+	if ticket.Summary != "Add CSV export" || ticket.Type != "Story" || len(ticket.Labels) != 1 {
+		t.Errorf("parseGeneratedTicket() = %+v", ticket)
+	}
+	want := "Summary: Add CSV export\nType: Story\nLabels: export\n\nExport entries as CSV.\n"
+	if got := ticket.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	for _, reply := range []string{`{"summary": "", "description": "Something"}`, "I can't write that ticket."} {
+		if _, err := parseGeneratedTicket(reply); err == nil {
+			t.Errorf("parseGeneratedTicket(%q) succeeded, want an error", reply)
+		}
+	}
+}
+
+func TestGenerateTicketConversation(t *testing.T) {
+	if !json.Valid(generatedTicketSchema) {
+		t.Fatal("generatedTicketSchema is not valid JSON")
+	}
+	conv := generateTicketConversation(&config.Config{SystemPrompt: "Be brief."}, "GitHub Issues", "Users want CSV export")
+	if conv.Schema == nil {
+		t.Error("The conversation doesn't ask for the ticket schema")
+	}
+	if !strings.HasPrefix(conv.System, "Be brief.\n\n") || !strings.Contains(conv.System, "GitHub Issues") {
+		t.Errorf("System prompt = %q", conv.System)
+	}
+	if len(conv.Messages) != 1 || conv.Messages[0].Content != "Users want CSV export" {
+		t.Errorf("Messages = %+v", conv.Messages)
+	}
+}
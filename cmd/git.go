@@ -2,39 +2,163 @@ package cmd
 
 import (
 	"fmt"
-	"os/exec"
-	"strconv"
+	"regexp"
 	"strings"
 	"time"
+	"unicode"
+
+	"github.com/plannet-ai/plannet/config"
 )
 
 // Commit represents a git commit
 type Commit struct {
-	Hash    string
-	Message string
-	Time    time.Time
+	Hash       string
+	Message    string // The subject line
+	Body       string // The rest of the message, including any trailers
+	Time       time.Time
+	AuthorTime time.Time // Kept when a commit is rebased or cherry-picked
+}
+
+// FullMessage returns the subject and body of the commit message
+func (c Commit) FullMessage() string {
+	if c.Body == "" {
+		return c.Message
+	}
+	return c.Message + "\n\n" + c.Body
+}
+
+// ticketReference matches trailers and closing keywords that reference an
+// issue by number, such as "Refs: #12" or "Closes #42"
+var ticketReference = regexp.MustCompile(`(?i)\b(?:refs?|references|related(?:-to)?|close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+(#\d+)\b`)
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// WorkInProgress describes the uncommitted changes in a working tree
+type WorkInProgress struct {
+	Staged       []string `json:"staged,omitempty"`
+	Unstaged     []string `json:"unstaged,omitempty"`
+	Untracked    []string `json:"untracked,omitempty"`
+	Stashes      int      `json:"stashes,omitempty"`
+	LinesAdded   int      `json:"lines_added"` // Added in tracked files, staged or not
+	LinesRemoved int      `json:"lines_removed"`
+}
+
+// Files returns every file with uncommitted changes
+func (w WorkInProgress) Files() []string {
+	var files []string
+	for _, list := range [][]string{w.Staged, w.Unstaged, w.Untracked} {
+		for _, file := range list {
+			if !containsString(files, file) {
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+// IsEmpty reports whether there is no uncommitted work
+func (w WorkInProgress) IsEmpty() bool {
+	return len(w.Files()) == 0 && w.Stashes == 0
+}
+
+// gitBackend reads repository information for the git layer. Backends are
+// registered in gitBackends and selected with the git_backend setting, so an
+// in-process implementation such as go-git can replace the git binary
+// without touching callers.
+type gitBackend interface {
+	// IsRepo reports whether dir is inside a git repository
+	IsRepo(dir string) bool
+	// CurrentBranch returns the checked out branch, or "HEAD" when detached
+	CurrentBranch(dir string) (string, error)
+	// Log returns the commits selected by opts, newest first
+	Log(dir string, opts logOptions) ([]Commit, error)
+	// LogRange returns commits reachable from to but not from from, newest first
+	LogRange(dir, from, to string) ([]Commit, error)
+	// MergeBase returns the best common ancestor of two commits
+	MergeBase(dir, first, second string) (string, error)
+	// DefaultBranch returns the branch work is usually merged into, preferring
+	// the remote's HEAD and falling back to main or master
+	DefaultBranch(dir string) (string, error)
+	// BranchExists reports whether a branch exists locally or on the origin remote
+	BranchExists(dir, branch string) bool
+	// IsAncestor reports whether commit is reachable from ref
+	IsAncestor(dir, commit, ref string) bool
+	// MergeCommit returns the commit that brought commit into ref: the first
+	// merge commit between them, or commit itself when it was fast-forwarded
+	MergeCommit(dir, commit, ref string) (string, error)
+	// FilesChanged returns the files that differ between a commit and the working tree
+	FilesChanged(dir, commitHash string) ([]string, error)
+	// CommitFiles returns the files changed by a commit
+	CommitFiles(dir, commitHash string) ([]string, error)
+	// DiffStats returns the lines added and removed per file between two commits
+	DiffStats(dir, from, to string) ([]FileChurn, error)
+	// StagedDiff returns the diff of the changes staged for the next commit
+	StagedDiff(dir string) (string, error)
+	// WorkInProgress returns the uncommitted changes and stashes in the working tree
+	WorkInProgress(dir string) (WorkInProgress, error)
+	// TopLevel returns the root directory of the working tree
+	TopLevel(dir string) (string, error)
+	// RemoteURL returns the URL of the named remote
+	RemoteURL(dir, remote string) (string, error)
+}
+
+// commitFilter selects which commits are scanned for a timeline
+type commitFilter struct {
+	AllBranches bool     // Every local branch instead of just HEAD's history
+	Authors     []string // Author emails to keep; empty keeps every author
+}
+
+// logOptions selects the commits returned by gitBackend.Log
+type logOptions struct {
+	commitFilter
+	Count int       // 0 for every commit
+	Since time.Time // Zero for commits of any age
+}
+
+// defaultGitBackend is used when git_backend is unset or unknown
+const defaultGitBackend = "go-git"
+
+// gitBackends maps git_backend names to their implementations
+var gitBackends = map[string]gitBackend{
+	"go-git": goGitBackend{},
+	"exec":   execGitBackend{},
+}
+
+// getGitBackend returns the configured git backend
+func getGitBackend() gitBackend {
+	if cfg, err := config.Get(); err == nil {
+		if backend, ok := gitBackends[cfg.GitBackend]; ok {
+			return backend
+		}
+	}
+	return gitBackends[defaultGitBackend]
 }
 
 // isGitRepo checks if the given directory is a git repository
 func isGitRepo(dir string) bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = dir
-	err := cmd.Run()
-	return err == nil
+	return getGitBackend().IsRepo(dir)
 }
 
 // getCurrentBranch gets the current branch name
 func getCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	output, err := cmd.Output()
+	branch, err := getGitBackend().CurrentBranch(".")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return branch, nil
 }
 
 // extractTicketID extracts a ticket ID from a branch name
 func extractTicketID(branchName string, prefixes []string) string {
+	if ticketID := matchTicketPattern(branchName); ticketID != "" {
+		return ticketID
+	}
 	for _, prefix := range prefixes {
 		if strings.Contains(branchName, prefix) {
 			parts := strings.Split(branchName, prefix)
@@ -55,37 +179,10 @@ func extractTicketID(branchName string, prefixes []string) string {
 
 // getRecentCommits gets the most recent commits
 func getRecentCommits(count int) ([]Commit, error) {
-	cmd := exec.Command("git", "log", "-n", fmt.Sprintf("%d", count), "--format=%H|%s|%ct")
-	output, err := cmd.Output()
+	commits, err := getGitBackend().Log(".", logOptions{Count: count})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent commits: %w", err)
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	commits := make([]Commit, 0, len(lines))
-
-	for _, line := range lines {
-		parts := strings.Split(line, "|")
-		if len(parts) >= 2 {
-			hash := parts[0]
-			message := parts[1]
-
-			var commitTime time.Time
-			if len(parts) >= 3 {
-				timestamp := parts[2]
-				if unixSeconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
-					commitTime = time.Unix(unixSeconds, 0)
-				}
-			}
-
-			commits = append(commits, Commit{
-				Hash:    hash,
-				Message: message,
-				Time:    commitTime,
-			})
-		}
-	}
-
 	return commits, nil
 }
 
@@ -94,15 +191,7 @@ func findSideQuests(commits []Commit, prefixes []string) []Commit {
 	sideQuests := []Commit{}
 
 	for _, commit := range commits {
-		hasTicketID := false
-		for _, prefix := range prefixes {
-			if strings.Contains(commit.Message, prefix) {
-				hasTicketID = true
-				break
-			}
-		}
-
-		if !hasTicketID {
+		if extractTicketIDFromMessage(commit.FullMessage(), prefixes) == "" {
 			sideQuests = append(sideQuests, commit)
 		}
 	}
@@ -112,78 +201,171 @@ func findSideQuests(commits []Commit, prefixes []string) []Commit {
 
 // getFilesChanged gets the list of files changed since a specific commit
 func getFilesChanged(dir string, commitHash string) ([]string, error) {
-	cmd := exec.Command("git", "diff", "--name-only", commitHash)
-	cmd.Dir = dir
-	output, err := cmd.Output()
+	files, err := getGitBackend().FilesChanged(dir, commitHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get changed files: %w", err)
 	}
+	return files, nil
+}
 
-	files := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(files) == 1 && files[0] == "" {
-		return []string{}, nil
+// getCommitFiles gets the list of files changed in a specific commit
+func getCommitFiles(dir string, commitHash string) ([]string, error) {
+	files, err := getGitBackend().CommitFiles(dir, commitHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get files in commit: %w", err)
 	}
 	return files, nil
 }
 
-// getCommitsSince gets all commits since a specific time
-func getCommitsSince(dir string, since string) ([]Commit, error) {
-	cmd := exec.Command("git", "log", "--since", since, "--format=%H|%s|%ct")
-	cmd.Dir = dir
-	output, err := cmd.Output()
+// getWorkInProgress gets the uncommitted changes in a working tree
+func getWorkInProgress(dir string) (WorkInProgress, error) {
+	wip, err := getGitBackend().WorkInProgress(dir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get commits since %s: %w", since, err)
+		return WorkInProgress{}, fmt.Errorf("failed to get uncommitted changes: %w", err)
 	}
+	return wip, nil
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	commits := make([]Commit, 0, len(lines))
+// getCommitsSince gets all commits selected by filter since a specific time,
+// given as "midnight", a date, a date and time, or a duration such as "24h" ago.
+// Copies of a commit on several branches are listed once.
+func getCommitsSince(dir string, since string, filter commitFilter) ([]Commit, error) {
+	sinceTime, err := parseSince(since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	commits, err := getCommitsAfter(dir, sinceTime, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits since %s: %w", since, err)
+	}
+	return commits, nil
+}
 
-	for _, line := range lines {
-		parts := strings.Split(line, "|")
-		if len(parts) >= 2 {
-			hash := parts[0]
-			message := parts[1]
+// getCommitsAfter gets all commits selected by filter since a time, or of
+// any age for the zero time. Copies of a commit on several branches are
+// listed once.
+func getCommitsAfter(dir string, since time.Time, filter commitFilter) ([]Commit, error) {
+	commits, err := getGitBackend().Log(dir, logOptions{commitFilter: filter, Since: since})
+	if err != nil {
+		return nil, err
+	}
+	return dedupeCommits(commits, make(map[string]bool)), nil
+}
 
-			var commitTime time.Time
-			if len(parts) >= 3 {
-				timestamp := parts[2]
-				if unixSeconds, err := strconv.ParseInt(timestamp, 10, 64); err == nil {
-					commitTime = time.Unix(unixSeconds, 0)
-				}
-			}
+// commitFilterFor returns the commit filter configured in cfg, with the
+// given flags taking precedence
+func commitFilterFor(cfg *config.Config, allBranches bool, authors []string) commitFilter {
+	filter := commitFilter{AllBranches: allBranches || cfg.ScanAllBranches, Authors: authors}
+	if len(filter.Authors) == 0 {
+		filter.Authors = cfg.AuthorEmails
+	}
+	return filter
+}
 
-			commits = append(commits, Commit{
-				Hash:    hash,
-				Message: message,
-				Time:    commitTime,
-			})
+// dedupeCommits drops commits already in seen, which it updates. A commit
+// rebased or cherry-picked onto another branch gets a new hash but keeps its
+// subject and author time, so those identify it.
+func dedupeCommits(commits []Commit, seen map[string]bool) []Commit {
+	var unique []Commit
+	for _, commit := range commits {
+		key := commitKey(commit)
+		if seen[key] {
+			continue
 		}
+		seen[key] = true
+		unique = append(unique, commit)
 	}
+	return unique
+}
 
-	return commits, nil
+// commitKey identifies a commit across rebases and cherry-picks, which keep
+// its author time and subject but change its hash
+func commitKey(commit Commit) string {
+	if commit.AuthorTime.IsZero() {
+		return commit.Hash
+	}
+	return fmt.Sprintf("%d %s", commit.AuthorTime.Unix(), commit.Message)
 }
 
-// extractTicketIDFromMessage extracts a ticket ID from a commit message
+// parseSince parses the start of a commit range relative to now. A weekday,
+// such as "monday", is midnight of its latest occurrence, today included.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	since = strings.TrimSpace(since)
+	if since == "midnight" {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(since, day.String()) {
+			daysAgo := (int(now.Weekday()) - int(day) + 7) % 7
+			return startOfDay(now).AddDate(0, 0, -daysAgo), nil
+		}
+	}
+	for _, layout := range []string{time.RFC3339, timeInputLayout, dateInputLayout} {
+		if t, err := time.ParseInLocation(layout, since, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected midnight, a weekday, YYYY-MM-DD, YYYY-MM-DD HH:MM or a duration", since)
+}
+
+// extractTicketIDFromMessage extracts a ticket ID from a commit message.
+// Besides IDs anywhere in the message, such as "feat: login (JIRA-123)" or
+// a "Refs: JIRA-123" trailer, issue numbers referenced with "Refs:",
+// "Closes" and similar keywords are recognized, e.g. "#42" in "Closes #42".
 func extractTicketIDFromMessage(message string, prefixes []string) string {
+	if ticketID := matchTicketPattern(message); ticketID != "" {
+		return ticketID
+	}
 	for _, prefix := range prefixes {
-		if strings.Contains(message, prefix) {
-			// Find the start of the ticket ID
-			startIndex := strings.Index(message, prefix)
-			if startIndex == -1 {
-				continue
-			}
+		startIndex := strings.Index(message, prefix)
+		if startIndex == -1 {
+			continue
+		}
 
-			// Extract the part after the prefix
-			ticketPart := message[startIndex:]
+		// The ticket ID ends at the first character that can't be part of it,
+		// such as a space, colon or closing bracket
+		ticketPart := message[startIndex+len(prefix):]
+		endIndex := strings.IndexFunc(ticketPart, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if endIndex == -1 {
+			endIndex = len(ticketPart)
+		}
+		if endIndex > 0 {
+			return prefix + ticketPart[:endIndex]
+		}
+	}
+	if match := ticketReference.FindStringSubmatch(message); match != nil {
+		return match[1]
+	}
+	return ""
+}
 
-			// Find the end of the ticket ID (usually a space, colon, or end of string)
-			endIndex := strings.IndexAny(ticketPart, " :")
-			if endIndex == -1 {
-				endIndex = len(ticketPart)
-			}
+// ticketPatterns returns the configured ticket ID patterns, compiled when
+// the configuration was loaded
+func ticketPatterns() []*regexp.Regexp {
+	cfg, err := config.Get()
+	if err != nil {
+		return nil
+	}
+	return cfg.CompiledTicketPatterns()
+}
 
-			return ticketPart[:endIndex]
+// matchTicketPattern returns the first ticket ID matched by a configured
+// pattern: the first capture group, or the whole match for patterns without one
+func matchTicketPattern(text string) string {
+	for _, re := range ticketPatterns() {
+		match := re.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+		if len(match) > 1 && match[1] != "" {
+			return match[1]
 		}
+		return match[0]
 	}
 	return ""
 }
@@ -5,9 +5,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/plannet-ai/plannet/config"
 )
 
 func setupGitRepo(t *testing.T) (string, func()) {
@@ -319,7 +322,7 @@ func TestGetCommitsSince(t *testing.T) {
 	}
 
 	// Test getting commits since midnight
-	commits, err := getCommitsSince(tempDir, "midnight")
+	commits, err := getCommitsSince(tempDir, "midnight", commitFilter{})
 	if err != nil {
 		t.Fatalf("Failed to get commits since midnight: %v", err)
 	}
@@ -389,3 +392,328 @@ func TestGetFilesChanged(t *testing.T) {
 		t.Errorf("Expected file 'test.txt', got '%s'", files[0])
 	}
 }
+
+func TestExecGitBackend(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := exec.Command("git", "add", "main.go").Run(); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if err := exec.Command("git", "commit", "-m", "PROJ-1 | Add main").Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	backend := execGitBackend{}
+	commits, err := backend.Log(tempDir, logOptions{Since: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Message != "PROJ-1 | Add main" {
+		t.Fatalf("Expected the commit subject to be kept intact, got %+v", commits)
+	}
+
+	// The first commit has no parent to diff against
+	files, err := backend.CommitFiles(tempDir, commits[0].Hash)
+	if err != nil {
+		t.Fatalf("Failed to get commit files: %v", err)
+	}
+	if len(files) != 1 || files[0] != "main.go" {
+		t.Errorf("Expected main.go in the root commit, got %v", files)
+	}
+
+	topLevel, err := backend.TopLevel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to get top level: %v", err)
+	}
+	if filepath.Base(topLevel) != filepath.Base(tempDir) {
+		t.Errorf("Expected top level %s, got %s", tempDir, topLevel)
+	}
+	if _, err := backend.RemoteURL(tempDir, "origin"); err == nil {
+		t.Error("Expected an error for a missing remote")
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		since    string
+		expected time.Time
+	}{
+		{"midnight", time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)},
+		{"2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"2024-03-14 09:00", time.Date(2024, 3, 14, 9, 0, 0, 0, time.Local)},
+		{"2h", time.Date(2024, 3, 15, 12, 30, 0, 0, time.Local)},
+		{"monday", time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)},
+		{"Friday", time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)},
+		{"saturday", time.Date(2024, 3, 9, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.since, now)
+		if err != nil {
+			t.Errorf("parseSince(%q) returned error: %v", tt.since, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.since, got, tt.expected)
+		}
+	}
+
+	if _, err := parseSince("last tuesday", now); err == nil {
+		t.Error("Expected an error for an unsupported time")
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	output := "10\t2\tcmd/list.go\n-\t-\tdocs/logo.png\n3\t0\tREADME.md"
+	churn := parseNumstat(output)
+
+	expected := []FileChurn{
+		{Path: "cmd/list.go", Added: 10, Removed: 2},
+		{Path: "docs/logo.png"},
+		{Path: "README.md", Added: 3},
+	}
+	if len(churn) != len(expected) {
+		t.Fatalf("Expected %d files, got %d", len(expected), len(churn))
+	}
+	for i := range expected {
+		if churn[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], churn[i])
+		}
+	}
+
+	if churn := parseNumstat(""); len(churn) != 0 {
+		t.Errorf("Expected no files for empty output, got %v", churn)
+	}
+}
+
+func TestRecordDiffStats(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	commitFile := func(content, message string) {
+		if err := os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if err := exec.Command("git", "add", "notes.txt").Run(); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		if err := exec.Command("git", "commit", "-m", message).Run(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	commitFile("one\ntwo\n", "Initial notes")
+	start, err := execGitBackend{}.Log(tempDir, logOptions{Count: 1})
+	if err != nil || len(start) == 0 {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	commitFile("one\nthree\nfour\n", "Update notes")
+
+	// Statistics come from the work's repository, wherever plannet runs
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	work := TrackedWork{Context: WorkContext{Repo: tempDir, StartCommit: start[0].Hash}}
+	completeWork(&work, time.Now())
+	if work.Context.LinesAdded != 2 || work.Context.LinesRemoved != 1 {
+		t.Errorf("Expected +2/-1, got +%d/-%d", work.Context.LinesAdded, work.Context.LinesRemoved)
+	}
+	if len(work.Context.FileChurn) != 1 || work.Context.FileChurn[0].Path != "notes.txt" {
+		t.Errorf("Expected churn for notes.txt, got %+v", work.Context.FileChurn)
+	}
+}
+
+func TestWorkInProgress(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := exec.Command("git", "add", "main.go").Run(); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if err := exec.Command("git", "commit", "-m", "Add main").Run(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	// One unstaged change, one staged new file and one untracked file
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "util.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	if err := exec.Command("git", "add", "util.go").Run(); err != nil {
+		t.Fatalf("Failed to add file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "scratch.txt"), []byte("todo\n"), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	wip, err := execGitBackend{}.WorkInProgress(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to get work in progress: %v", err)
+	}
+	if len(wip.Staged) != 1 || wip.Staged[0] != "util.go" {
+		t.Errorf("Expected util.go to be staged, got %v", wip.Staged)
+	}
+	if len(wip.Unstaged) != 1 || wip.Unstaged[0] != "main.go" {
+		t.Errorf("Expected main.go to be unstaged, got %v", wip.Unstaged)
+	}
+	if len(wip.Untracked) != 1 || wip.Untracked[0] != "scratch.txt" {
+		t.Errorf("Expected scratch.txt to be untracked, got %v", wip.Untracked)
+	}
+
+	expected := "Uncommitted work in progress on main: 3 files, +3/-0"
+	if got := describeWorkInProgress("main", wip); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if got := wipDetails(wip); got != "1 staged, 1 unstaged, 1 untracked" {
+		t.Errorf("Unexpected details %q", got)
+	}
+}
+
+func TestParsePorcelainStatus(t *testing.T) {
+	wip := parsePorcelainStatus("MM both.go\nR  old.go -> new.go\n?? notes.txt")
+	if len(wip.Staged) != 2 || wip.Staged[1] != "new.go" {
+		t.Errorf("Expected both.go and new.go to be staged, got %v", wip.Staged)
+	}
+	if len(wip.Unstaged) != 1 || wip.Unstaged[0] != "both.go" {
+		t.Errorf("Expected both.go to be unstaged, got %v", wip.Unstaged)
+	}
+	if len(wip.Files()) != 3 {
+		t.Errorf("Expected 3 files, got %v", wip.Files())
+	}
+}
+
+func TestTicketPatterns(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	cfg := &config.Config{
+		TicketPrefixes: []string{"JIRA-"},
+		TicketPatterns: []string{`(?i)\b(gh-\d+)\b`, `#(\d+)`},
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	branches := map[string]string{
+		"feature/gh-1234-login": "gh-1234",
+		"feature/JIRA-9-login":  "JIRA-9",
+		"feature/login":         "",
+	}
+	for branch, want := range branches {
+		if got := extractTicketID(branch, cfg.TicketPrefixes); got != want {
+			t.Errorf("extractTicketID(%q) = %q, want %q", branch, got, want)
+		}
+	}
+
+	messages := map[string]string{
+		"Fix crash on startup (#567)": "567",
+		"GH-12: handle empty input":   "GH-12",
+		"Tidy up imports":             "",
+	}
+	for message, want := range messages {
+		if got := extractTicketIDFromMessage(message, cfg.TicketPrefixes); got != want {
+			t.Errorf("extractTicketIDFromMessage(%q) = %q, want %q", message, got, want)
+		}
+	}
+
+	commits := []Commit{{Message: "Fix crash (#567)"}, {Message: "Tidy up imports"}}
+	if sideQuests := findSideQuests(commits, cfg.TicketPrefixes); len(sideQuests) != 1 || sideQuests[0].Message != "Tidy up imports" {
+		t.Errorf("Expected only the commit without a ticket to be a side quest, got %v", sideQuests)
+	}
+
+	for _, ticketID := range []string{"gh-42", "#42"} {
+		if err := validateTicketID(ticketID); err != nil {
+			t.Errorf("Expected %s, which matches a pattern, to be valid, got %v", ticketID, err)
+		}
+	}
+	for _, ticketID := range []string{"PROJ-1", "foo #42 bar", "xgh-42y", "#42 and more"} {
+		if err := validateTicketID(ticketID); err == nil {
+			t.Errorf("Expected an error for %q, which isn't a whole ticket ID of any prefix or pattern", ticketID)
+		}
+	}
+}
+
+func TestExtractTicketIDFromMessage(t *testing.T) {
+	prefixes := []string{"JIRA-"}
+	tests := []struct {
+		name   string
+		commit Commit
+		want   string
+	}{
+		{"Ticket at the start", Commit{Message: "JIRA-1: add login"}, "JIRA-1"},
+		{"Squash merge subject", Commit{Message: "feat: login timeout (JIRA-123)"}, "JIRA-123"},
+		{"Merge of a ticket branch", Commit{Message: "Merge pull request #7 from acme/feature/JIRA-45-login"}, "JIRA-45"},
+		{"Refs trailer", Commit{Message: "Tidy up login", Body: "Longer explanation.\n\nRefs: JIRA-77"}, "JIRA-77"},
+		{"Closing keyword", Commit{Message: "Fix crash on startup", Body: "Closes #42"}, "#42"},
+		{"Issue number without keyword", Commit{Message: "Bump version to #3"}, ""},
+		{"No reference", Commit{Message: "Tidy up imports"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractTicketIDFromMessage(tt.commit.FullMessage(), prefixes); got != tt.want {
+				t.Errorf("extractTicketIDFromMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCommitsSinceFilter(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	commit := func(file, message, email string) {
+		if err := os.WriteFile(file, []byte(message), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		exec.Command("git", "add", file).Run()
+		cmd := exec.Command("git", "commit", "-m", message, "--author", "Someone <"+email+">")
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	exec.Command("git", "checkout", "-q", "-b", "main").Run()
+	commit("main.go", "Add main", "test@example.com")
+	exec.Command("git", "checkout", "-q", "-b", "feature").Run()
+	commit("feature.go", "Add feature", "test@example.com")
+	commit("other.go", "Add other", "other@example.com")
+	exec.Command("git", "checkout", "-q", "main").Run()
+	// The same change picked onto main is a copy with a new hash
+	if err := exec.Command("git", "cherry-pick", "feature~1").Run(); err != nil {
+		t.Fatalf("Failed to cherry-pick: %v", err)
+	}
+
+	messages := func(filter commitFilter) []string {
+		commits, err := getCommitsSince(tempDir, "midnight", filter)
+		if err != nil {
+			t.Fatalf("Failed to get commits: %v", err)
+		}
+		var messages []string
+		for _, commit := range commits {
+			messages = append(messages, commit.Message)
+		}
+		sort.Strings(messages)
+		return messages
+	}
+
+	if got := strings.Join(messages(commitFilter{}), ","); got != "Add feature,Add main" {
+		t.Errorf("Expected HEAD's history only, got %s", got)
+	}
+	if got := strings.Join(messages(commitFilter{AllBranches: true}), ","); got != "Add feature,Add main,Add other" {
+		t.Errorf("Expected every branch with the cherry-pick listed once, got %s", got)
+	}
+	if got := strings.Join(messages(commitFilter{AllBranches: true, Authors: []string{"Other@example.com"}}), ","); got != "Add other" {
+		t.Errorf("Expected only the other author's commit, got %s", got)
+	}
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logFormat is the git log format parsed by parseLog. Fields are separated by
+// the unit separator and commits by the record separator, neither of which
+// appears in commit messages.
+const logFormat = "%H%x1f%s%x1f%ct%x1f%b%x1f%at%x1e"
+
+// execGitBackend reads repositories by running the git binary
+type execGitBackend struct{}
+
+// git runs a git command in dir and returns its output without the trailing newline
+func (execGitBackend) git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	// Leading spaces are kept, as they are significant in git status output
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// IsRepo reports whether dir is inside a git repository
+func (b execGitBackend) IsRepo(dir string) bool {
+	_, err := b.git(dir, "rev-parse", "--git-dir")
+	return err == nil
+}
+
+// CurrentBranch returns the checked out branch, or "HEAD" when detached
+func (b execGitBackend) CurrentBranch(dir string) (string, error) {
+	return b.git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// Log returns the commits selected by opts, newest first
+func (b execGitBackend) Log(dir string, opts logOptions) ([]Commit, error) {
+	args := []string{"log", "--format=" + logFormat}
+	if opts.Count > 0 {
+		args = append(args, "-n", strconv.Itoa(opts.Count))
+	}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since", opts.Since.Format(time.RFC3339))
+	}
+	if opts.AllBranches {
+		args = append(args, "--branches")
+	}
+	if len(opts.Authors) > 0 {
+		// Authors are matched as "<email>" so one address isn't mistaken
+		// for part of another; several --author options match any of them
+		args = append(args, "--fixed-strings", "--regexp-ignore-case")
+		for _, author := range opts.Authors {
+			args = append(args, "--author=<"+author+">")
+		}
+	}
+	output, err := b.git(dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output), nil
+}
+
+// LogRange returns commits reachable from to but not from from, newest first
+func (b execGitBackend) LogRange(dir, from, to string) ([]Commit, error) {
+	output, err := b.git(dir, "log", "--format="+logFormat, from+".."+to)
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(output), nil
+}
+
+// MergeBase returns the best common ancestor of two commits
+func (b execGitBackend) MergeBase(dir, first, second string) (string, error) {
+	return b.git(dir, "merge-base", first, second)
+}
+
+// DefaultBranch returns the branch work is usually merged into, preferring
+// the remote's HEAD and falling back to main or master
+func (b execGitBackend) DefaultBranch(dir string) (string, error) {
+	if ref, err := b.git(dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return ref, nil
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := b.git(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no default branch found")
+}
+
+// BranchExists reports whether a branch exists locally or on the origin remote
+func (b execGitBackend) BranchExists(dir, branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		if _, err := b.git(dir, "rev-parse", "--verify", "--quiet", ref); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAncestor reports whether commit is reachable from ref
+func (b execGitBackend) IsAncestor(dir, commit, ref string) bool {
+	_, err := b.git(dir, "merge-base", "--is-ancestor", commit, ref)
+	return err == nil
+}
+
+// MergeCommit returns the commit that brought commit into ref: the first
+// merge commit between them, or commit itself when it was fast-forwarded
+func (b execGitBackend) MergeCommit(dir, commit, ref string) (string, error) {
+	output, err := b.git(dir, "rev-list", "--ancestry-path", "--merges", "--reverse", commit+".."+ref)
+	if err != nil {
+		return "", err
+	}
+	if merges := splitLines(output); len(merges) > 0 {
+		return merges[0], nil
+	}
+	return b.git(dir, "rev-parse", commit)
+}
+
+// FilesChanged returns the files that differ between a commit and the working tree
+func (b execGitBackend) FilesChanged(dir, commitHash string) ([]string, error) {
+	output, err := b.git(dir, "diff", "--name-only", commitHash)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+// CommitFiles returns the files changed by a commit
+func (b execGitBackend) CommitFiles(dir, commitHash string) ([]string, error) {
+	output, err := b.git(dir, "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", commitHash)
+	if err != nil {
+		return nil, err
+	}
+	return splitLines(output), nil
+}
+
+// DiffStats returns the lines added and removed per file between two commits
+func (b execGitBackend) DiffStats(dir, from, to string) ([]FileChurn, error) {
+	output, err := b.git(dir, "diff", "--numstat", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(output), nil
+}
+
+// StagedDiff returns the diff of the changes staged for the next commit
+func (b execGitBackend) StagedDiff(dir string) (string, error) {
+	return b.git(dir, "diff", "--cached")
+}
+
+// WorkInProgress returns the uncommitted changes and stashes in the working tree
+func (b execGitBackend) WorkInProgress(dir string) (WorkInProgress, error) {
+	output, err := b.git(dir, "status", "--porcelain")
+	if err != nil {
+		return WorkInProgress{}, err
+	}
+	wip := parsePorcelainStatus(output)
+
+	// A repository without commits has no HEAD to diff against
+	if output, err := b.git(dir, "diff", "--numstat", "HEAD"); err == nil {
+		for _, file := range parseNumstat(output) {
+			wip.LinesAdded += file.Added
+			wip.LinesRemoved += file.Removed
+		}
+	}
+	if output, err := b.git(dir, "stash", "list"); err == nil {
+		wip.Stashes = len(splitLines(output))
+	}
+	return wip, nil
+}
+
+// TopLevel returns the root directory of the working tree
+func (b execGitBackend) TopLevel(dir string) (string, error) {
+	return b.git(dir, "rev-parse", "--show-toplevel")
+}
+
+// RemoteURL returns the URL of the named remote
+func (b execGitBackend) RemoteURL(dir, remote string) (string, error) {
+	url, err := b.git(dir, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("remote %s not found", remote)
+	}
+	return url, nil
+}
+
+// splitLines splits command output into lines, returning an empty list for empty output
+func splitLines(output string) []string {
+	if output == "" {
+		return []string{}
+	}
+	return strings.Split(output, "\n")
+}
+
+// parseLog parses git log output written with logFormat
+func parseLog(output string) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		parts := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(parts) < 2 {
+			continue
+		}
+
+		commit := Commit{Hash: parts[0], Message: parts[1]}
+		if len(parts) >= 3 {
+			if unixSeconds, err := strconv.ParseInt(parts[2], 10, 64); err == nil {
+				commit.Time = time.Unix(unixSeconds, 0)
+			}
+		}
+		if len(parts) >= 4 {
+			commit.Body = strings.TrimSpace(parts[3])
+		}
+		if len(parts) >= 5 {
+			if unixSeconds, err := strconv.ParseInt(parts[4], 10, 64); err == nil {
+				commit.AuthorTime = time.Unix(unixSeconds, 0)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// parseNumstat parses the output of git diff --numstat. Binary files, which
+// have no line counts, are listed with none added or removed.
+func parseNumstat(output string) []FileChurn {
+	var churn []FileChurn
+	for _, line := range splitLines(output) {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		removed, _ := strconv.Atoi(parts[1])
+		churn = append(churn, FileChurn{Path: parts[2], Added: added, Removed: removed})
+	}
+	return churn
+}
+
+// parsePorcelainStatus sorts the files in git status --porcelain output into
+// staged, unstaged and untracked changes
+func parsePorcelainStatus(output string) WorkInProgress {
+	var wip WorkInProgress
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		index, worktree, path := line[0], line[1], line[3:]
+		// Renames are shown as "old -> new"
+		if i := strings.Index(path, " -> "); i >= 0 {
+			path = path[i+4:]
+		}
+
+		if index == '?' {
+			wip.Untracked = append(wip.Untracked, path)
+			continue
+		}
+		if index != ' ' {
+			wip.Staged = append(wip.Staged, path)
+		}
+		if worktree != ' ' {
+			wip.Unstaged = append(wip.Unstaged, path)
+		}
+	}
+	return wip
+}
@@ -0,0 +1,589 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// goGitBackend reads repositories in-process with go-git, so plannet works
+// without the git binary and doesn't start a process for every query
+type goGitBackend struct{}
+
+// open opens the repository dir is in
+func (goGitBackend) open(dir string) (*git.Repository, error) {
+	return git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+}
+
+// commit opens the repository dir is in and resolves a revision such as
+// "HEAD", a branch or an abbreviated hash to its commit
+func (b goGitBackend) commit(dir, revision string) (*git.Repository, *object.Commit, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	commit, err := resolveCommit(repo, revision)
+	return repo, commit, err
+}
+
+// resolveCommit resolves a revision to its commit
+func resolveCommit(repo *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %s: %w", revision, err)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// IsRepo reports whether dir is inside a git repository
+func (b goGitBackend) IsRepo(dir string) bool {
+	_, err := b.open(dir)
+	return err == nil
+}
+
+// CurrentBranch returns the checked out branch, or "HEAD" when detached
+func (b goGitBackend) CurrentBranch(dir string) (string, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if !head.Name().IsBranch() {
+		return "HEAD", nil
+	}
+	return head.Name().Short(), nil
+}
+
+// Log returns the commits selected by opts, newest first
+func (b goGitBackend) Log(dir string, opts logOptions) ([]Commit, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tips []*object.Commit
+	if opts.AllBranches {
+		branches, err := repo.Branches()
+		if err != nil {
+			return nil, err
+		}
+		err = branches.ForEach(func(ref *plumbing.Reference) error {
+			commit, err := repo.CommitObject(ref.Hash())
+			if err != nil {
+				return err
+			}
+			tips = append(tips, commit)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		head, err := resolveCommit(repo, "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		tips = append(tips, head)
+	}
+
+	authors := make(map[string]bool)
+	for _, author := range opts.Authors {
+		authors[strings.ToLower(author)] = true
+	}
+
+	// Commits reachable from several branches are visited once
+	seen := make(map[plumbing.Hash]bool)
+	var commits []Commit
+	for _, tip := range tips {
+		err := object.NewCommitIterCTime(tip, seen, nil).ForEach(func(commit *object.Commit) error {
+			seen[commit.Hash] = true
+			// Commits are visited newest first, so the rest are older still
+			if !opts.Since.IsZero() && commit.Committer.When.Before(opts.Since) {
+				return storer.ErrStop
+			}
+			if len(authors) > 0 && !authors[strings.ToLower(commit.Author.Email)] {
+				return nil
+			}
+			commits = append(commits, newGoGitCommit(commit))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].Time.After(commits[j].Time) })
+	if opts.Count > 0 && len(commits) > opts.Count {
+		commits = commits[:opts.Count]
+	}
+	return commits, nil
+}
+
+// LogRange returns commits reachable from to but not from from, newest first
+func (b goGitBackend) LogRange(dir, from, to string) ([]Commit, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return nil, err
+	}
+	fromCommit, err := resolveCommit(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := ancestors(fromCommit)
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	err = object.NewCommitIterCTime(toCommit, excluded, nil).ForEach(func(commit *object.Commit) error {
+		commits = append(commits, newGoGitCommit(commit))
+		return nil
+	})
+	return commits, err
+}
+
+// MergeBase returns the best common ancestor of two commits
+func (b goGitBackend) MergeBase(dir, first, second string) (string, error) {
+	repo, firstCommit, err := b.commit(dir, first)
+	if err != nil {
+		return "", err
+	}
+	secondCommit, err := resolveCommit(repo, second)
+	if err != nil {
+		return "", err
+	}
+	bases, err := firstCommit.MergeBase(secondCommit)
+	if err != nil {
+		return "", err
+	}
+	if len(bases) == 0 {
+		return "", fmt.Errorf("%s and %s have no common ancestor", first, second)
+	}
+	return bases[0].Hash.String(), nil
+}
+
+// DefaultBranch returns the branch work is usually merged into, preferring
+// the remote's HEAD and falling back to main or master
+func (b goGitBackend) DefaultBranch(dir string) (string, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return "", err
+	}
+	if ref, err := repo.Reference("refs/remotes/origin/HEAD", false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		return ref.Target().Short(), nil
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("no default branch found")
+}
+
+// BranchExists reports whether a branch exists locally or on the origin remote
+func (b goGitBackend) BranchExists(dir, branch string) bool {
+	repo, err := b.open(dir)
+	if err != nil {
+		return false
+	}
+	for _, ref := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(branch), plumbing.NewRemoteReferenceName("origin", branch)} {
+		if _, err := repo.Reference(ref, true); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAncestor reports whether commit is reachable from ref
+func (b goGitBackend) IsAncestor(dir, commit, ref string) bool {
+	repo, commitObject, err := b.commit(dir, commit)
+	if err != nil {
+		return false
+	}
+	refCommit, err := resolveCommit(repo, ref)
+	if err != nil {
+		return false
+	}
+	isAncestor, err := commitObject.IsAncestor(refCommit)
+	return err == nil && isAncestor
+}
+
+// MergeCommit returns the commit that brought commit into ref: the first
+// merge commit between them, or commit itself when it was fast-forwarded
+func (b goGitBackend) MergeCommit(dir, commit, ref string) (string, error) {
+	repo, commitObject, err := b.commit(dir, commit)
+	if err != nil {
+		return "", err
+	}
+	refCommit, err := resolveCommit(repo, ref)
+	if err != nil {
+		return "", err
+	}
+
+	// The commits reachable from ref but not from commit, newest first
+	excluded, err := ancestors(commitObject)
+	if err != nil {
+		return "", err
+	}
+	var between []*object.Commit
+	parents := make(map[plumbing.Hash][]plumbing.Hash)
+	err = object.NewCommitIterCTime(refCommit, excluded, nil).ForEach(func(c *object.Commit) error {
+		between = append(between, c)
+		parents[c.Hash] = c.ParentHashes
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Only commits that descend from commit are on the ancestry path
+	onPath := map[plumbing.Hash]bool{commitObject.Hash: true}
+	var descends func(hash plumbing.Hash) bool
+	descends = func(hash plumbing.Hash) bool {
+		if result, ok := onPath[hash]; ok {
+			return result
+		}
+		onPath[hash] = false
+		for _, parent := range parents[hash] {
+			if descends(parent) {
+				onPath[hash] = true
+				break
+			}
+		}
+		return onPath[hash]
+	}
+
+	for i := len(between) - 1; i >= 0; i-- {
+		if between[i].NumParents() > 1 && descends(between[i].Hash) {
+			return between[i].Hash.String(), nil
+		}
+	}
+	return commitObject.Hash.String(), nil
+}
+
+// FilesChanged returns the files that differ between a commit and the working tree
+func (b goGitBackend) FilesChanged(dir, commitHash string) ([]string, error) {
+	repo, commit, err := b.commit(dir, commitHash)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	// The candidates are the files changed since commit was checked in and
+	// the tracked files changed in the working tree
+	candidates := make(map[string]bool)
+	if head, err := resolveCommit(repo, "HEAD"); err == nil {
+		headTree, err := head.Tree()
+		if err != nil {
+			return nil, err
+		}
+		changes, err := object.DiffTree(tree, headTree)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			candidates[change.From.Name] = true
+			candidates[change.To.Name] = true
+		}
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	for path, fileStatus := range status {
+		if fileStatus.Worktree != git.Untracked {
+			candidates[path] = true
+		}
+	}
+
+	// Keep the candidates whose contents differ between commit and the
+	// working tree, as a file can be changed and changed back
+	files := []string{}
+	for path := range candidates {
+		if path == "" {
+			continue
+		}
+		committed, err := treeContents(tree, path)
+		if err != nil {
+			return nil, err
+		}
+		current, err := os.ReadFile(filepath.Join(worktree.Filesystem.Root(), path))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if committed != string(current) {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// CommitFiles returns the files changed by a commit
+func (b goGitBackend) CommitFiles(dir, commitHash string) ([]string, error) {
+	_, commit, err := b.commit(dir, commitHash)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	switch commit.NumParents() {
+	case 0:
+		// A root commit adds every file in it
+		err = tree.Files().ForEach(func(file *object.File) error {
+			files = append(files, file.Name)
+			return nil
+		})
+	case 1:
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		parentTree, err := parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+		changes, err := object.DiffTree(parentTree, tree)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			// A rename without rename detection is a deletion and an addition
+			for _, name := range []string{change.From.Name, change.To.Name} {
+				if name != "" && !containsString(files, name) {
+					files = append(files, name)
+				}
+			}
+		}
+	}
+	// Like git diff-tree, merges list no files of their own
+	sort.Strings(files)
+	return files, err
+}
+
+// DiffStats returns the lines added and removed per file between two commits
+func (b goGitBackend) DiffStats(dir, from, to string) ([]FileChurn, error) {
+	repo, fromCommit, err := b.commit(dir, from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := resolveCommit(repo, to)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := fromCommit.Patch(toCommit)
+	if err != nil {
+		return nil, err
+	}
+	var churn []FileChurn
+	for _, stat := range patch.Stats() {
+		churn = append(churn, FileChurn{Path: stat.Name, Added: stat.Addition, Removed: stat.Deletion})
+	}
+	return churn, nil
+}
+
+// StagedDiff returns the diff of the changes staged for the next commit.
+// It runs git, as go-git can't diff the index and plannet commit runs git
+// to commit them anyway.
+func (goGitBackend) StagedDiff(dir string) (string, error) {
+	return execGitBackend{}.StagedDiff(dir)
+}
+
+// WorkInProgress returns the uncommitted changes and stashes in the working tree
+func (b goGitBackend) WorkInProgress(dir string) (WorkInProgress, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return WorkInProgress{}, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return WorkInProgress{}, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return WorkInProgress{}, err
+	}
+
+	paths := make([]string, 0, len(status))
+	for path := range status {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	// A repository without commits has no HEAD to count changed lines against
+	var headTree *object.Tree
+	if head, err := resolveCommit(repo, "HEAD"); err == nil {
+		if headTree, err = head.Tree(); err != nil {
+			return WorkInProgress{}, err
+		}
+	}
+
+	var wip WorkInProgress
+	for _, path := range paths {
+		fileStatus := status[path]
+		if fileStatus.Worktree == git.Untracked {
+			wip.Untracked = append(wip.Untracked, path)
+			continue
+		}
+		if fileStatus.Staging != git.Unmodified {
+			wip.Staged = append(wip.Staged, path)
+		}
+		if fileStatus.Worktree != git.Unmodified {
+			wip.Unstaged = append(wip.Unstaged, path)
+		}
+
+		if headTree == nil {
+			continue
+		}
+		committed, err := treeContents(headTree, path)
+		if err != nil {
+			return WorkInProgress{}, err
+		}
+		current, err := os.ReadFile(filepath.Join(worktree.Filesystem.Root(), path))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return WorkInProgress{}, err
+		}
+		added, removed := countChangedLines(committed, string(current))
+		wip.LinesAdded += added
+		wip.LinesRemoved += removed
+	}
+
+	if storage, ok := repo.Storer.(*filesystem.Storage); ok {
+		if stashLog, err := storage.Filesystem().Open("logs/refs/stash"); err == nil {
+			defer stashLog.Close()
+			wip.Stashes = countLines(stashLog)
+		}
+	}
+	return wip, nil
+}
+
+// TopLevel returns the root directory of the working tree
+func (b goGitBackend) TopLevel(dir string) (string, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return "", err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	return worktree.Filesystem.Root(), nil
+}
+
+// RemoteURL returns the URL of the named remote
+func (b goGitBackend) RemoteURL(dir, remote string) (string, error) {
+	repo, err := b.open(dir)
+	if err != nil {
+		return "", err
+	}
+	found, err := repo.Remote(remote)
+	if err != nil || len(found.Config().URLs) == 0 {
+		return "", fmt.Errorf("remote %s not found", remote)
+	}
+	return found.Config().URLs[0], nil
+}
+
+// newGoGitCommit converts a go-git commit, splitting its message the way
+// git log's %s and %b do
+func newGoGitCommit(commit *object.Commit) Commit {
+	message := strings.TrimLeft(commit.Message, "\n")
+	subject, body, _ := strings.Cut(message, "\n\n")
+	lines := strings.Split(strings.TrimSpace(subject), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return Commit{
+		Hash:       commit.Hash.String(),
+		Message:    strings.Join(lines, " "),
+		Body:       strings.TrimSpace(body),
+		Time:       time.Unix(commit.Committer.When.Unix(), 0),
+		AuthorTime: time.Unix(commit.Author.When.Unix(), 0),
+	}
+}
+
+// ancestors returns commit and every commit reachable from it
+func ancestors(commit *object.Commit) (map[plumbing.Hash]bool, error) {
+	seen := make(map[plumbing.Hash]bool)
+	err := object.NewCommitPreorderIter(commit, seen, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	return seen, err
+}
+
+// treeContents returns the contents of a file in a tree, or "" when the
+// tree doesn't have it
+func treeContents(tree *object.Tree, path string) (string, error) {
+	file, err := tree.File(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return file.Contents()
+}
+
+// countChangedLines returns the lines added and removed between two
+// versions of a file. Binary files, which have no lines, count none.
+func countChangedLines(before, after string) (int, int) {
+	if strings.ContainsRune(before, 0) || strings.ContainsRune(after, 0) {
+		return 0, 0
+	}
+	var added, removed int
+	for _, change := range diff.Do(before, after) {
+		lines := strings.Count(change.Text, "\n")
+		if !strings.HasSuffix(change.Text, "\n") {
+			lines++
+		}
+		switch change.Type {
+		case diffmatchpatch.DiffInsert:
+			added += lines
+		case diffmatchpatch.DiffDelete:
+			removed += lines
+		}
+	}
+	return added, removed
+}
+
+// countLines returns the number of lines read from r
+func countLines(r io.Reader) int {
+	count := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		count++
+	}
+	return count
+}
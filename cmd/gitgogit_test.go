@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gitRun runs a git command in dir and returns its trimmed output
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	return gitRunAt(t, dir, time.Time{}, args...)
+}
+
+// gitRunAt runs a git command that commits at the given time, so the order
+// of commits doesn't depend on how fast the test runs
+func gitRunAt(t *testing.T, dir string, when time.Time, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if !when.IsZero() {
+		date := when.Format(time.RFC3339)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// commitFile writes a file and commits it with the given message at the given time
+func commitFile(t *testing.T, dir string, when time.Time, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	gitRun(t, dir, "add", name)
+	gitRunAt(t, dir, when, "commit", "-m", message)
+}
+
+func TestGoGitBackendMatchesExec(t *testing.T) {
+	tempDir, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	gitRun(t, tempDir, "checkout", "-b", "main")
+	commitFile(t, tempDir, start, "main.go", "package main\n", "PROJ-1 | Add main\n\nWith a body\n\nRefs: #12")
+	root := gitRun(t, tempDir, "rev-parse", "HEAD")
+	gitRun(t, tempDir, "checkout", "-b", "feature")
+	commitFile(t, tempDir, start.Add(time.Minute), "util.go", "package main\n\nfunc util() {}\n", "PROJ-2 Add util")
+	feature := gitRun(t, tempDir, "rev-parse", "HEAD")
+	gitRun(t, tempDir, "checkout", "main")
+	commitFile(t, tempDir, start.Add(2*time.Minute), "readme.md", "# Test\n", "Add readme")
+	gitRunAt(t, tempDir, start.Add(3*time.Minute), "merge", "--no-ff", "-m", "Merge feature", "feature")
+
+	// Uncommitted work: a change, a staged file, an untracked file and a stash
+	if err := os.WriteFile(filepath.Join(tempDir, "readme.md"), []byte("# Stashed\n"), 0644); err != nil {
+		t.Fatalf("Failed to write readme.md: %v", err)
+	}
+	gitRun(t, tempDir, "stash")
+	if err := os.WriteFile(filepath.Join(tempDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "new.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("Failed to write new.go: %v", err)
+	}
+	gitRun(t, tempDir, "add", "new.go")
+	if err := os.WriteFile(filepath.Join(tempDir, "scratch.txt"), []byte("todo\n"), 0644); err != nil {
+		t.Fatalf("Failed to write scratch.txt: %v", err)
+	}
+
+	execBackend, goGit := execGitBackend{}, goGitBackend{}
+	check := func(name string, want, got interface{}) {
+		t.Helper()
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: exec returned %+v, go-git returned %+v", name, want, got)
+		}
+	}
+	call := func(name string, f func(b gitBackend) (interface{}, error)) {
+		t.Helper()
+		want, err := f(execBackend)
+		if err != nil {
+			t.Fatalf("%s with exec: %v", name, err)
+		}
+		got, err := f(goGit)
+		if err != nil {
+			t.Fatalf("%s with go-git: %v", name, err)
+		}
+		check(name, want, got)
+	}
+
+	check("IsRepo", execBackend.IsRepo(tempDir), goGit.IsRepo(tempDir))
+	call("CurrentBranch", func(b gitBackend) (interface{}, error) { return b.CurrentBranch(tempDir) })
+	call("Log", func(b gitBackend) (interface{}, error) { return b.Log(tempDir, logOptions{}) })
+	call("Log with options", func(b gitBackend) (interface{}, error) {
+		return b.Log(tempDir, logOptions{
+			commitFilter: commitFilter{AllBranches: true, Authors: []string{"TEST@example.com"}},
+			Count:        2,
+			Since:        start.Add(30 * time.Second),
+		})
+	})
+	call("LogRange", func(b gitBackend) (interface{}, error) { return b.LogRange(tempDir, root, "HEAD") })
+	call("MergeBase", func(b gitBackend) (interface{}, error) { return b.MergeBase(tempDir, feature, "main") })
+	call("DefaultBranch", func(b gitBackend) (interface{}, error) { return b.DefaultBranch(tempDir) })
+	check("BranchExists", execBackend.BranchExists(tempDir, "feature"), goGit.BranchExists(tempDir, "feature"))
+	check("BranchExists missing", execBackend.BranchExists(tempDir, "missing"), goGit.BranchExists(tempDir, "missing"))
+	check("IsAncestor", execBackend.IsAncestor(tempDir, feature, "main"), goGit.IsAncestor(tempDir, feature, "main"))
+	check("IsAncestor reversed", execBackend.IsAncestor(tempDir, "main", feature), goGit.IsAncestor(tempDir, "main", feature))
+	call("MergeCommit", func(b gitBackend) (interface{}, error) { return b.MergeCommit(tempDir, feature, "main") })
+	call("MergeCommit fast-forward", func(b gitBackend) (interface{}, error) { return b.MergeCommit(tempDir, root, root) })
+	call("FilesChanged", func(b gitBackend) (interface{}, error) { return b.FilesChanged(tempDir, root) })
+	call("CommitFiles", func(b gitBackend) (interface{}, error) { return b.CommitFiles(tempDir, feature) })
+	call("CommitFiles root", func(b gitBackend) (interface{}, error) { return b.CommitFiles(tempDir, root) })
+	call("CommitFiles merge", func(b gitBackend) (interface{}, error) { return b.CommitFiles(tempDir, "HEAD") })
+	call("DiffStats", func(b gitBackend) (interface{}, error) { return b.DiffStats(tempDir, root, "HEAD") })
+	call("WorkInProgress", func(b gitBackend) (interface{}, error) { return b.WorkInProgress(tempDir) })
+
+	topLevel, err := goGit.TopLevel(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to get top level: %v", err)
+	}
+	if filepath.Base(topLevel) != filepath.Base(tempDir) {
+		t.Errorf("Expected top level %s, got %s", tempDir, topLevel)
+	}
+	if _, err := goGit.RemoteURL(tempDir, "origin"); err == nil {
+		t.Error("Expected an error for a missing remote")
+	}
+	gitRun(t, tempDir, "remote", "add", "origin", "https://example.com/repo.git")
+	call("RemoteURL", func(b gitBackend) (interface{}, error) { return b.RemoteURL(tempDir, "origin") })
+}
+
+func TestGoGitBackendOutsideRepo(t *testing.T) {
+	tempDir := t.TempDir()
+	backend := goGitBackend{}
+	if backend.IsRepo(tempDir) {
+		t.Error("Expected a directory without a repository not to be one")
+	}
+	if _, err := backend.Log(tempDir, logOptions{}); err == nil {
+		t.Error("Expected an error reading the log outside a repository")
+	}
+}
+
+func TestCountChangedLines(t *testing.T) {
+	tests := []struct {
+		before, after  string
+		added, removed int
+	}{
+		{"a\nb\n", "a\nb\n", 0, 0},
+		{"a\n", "a\nb\nc\n", 2, 0},
+		{"a\nb\nc\n", "a\nc\n", 0, 1},
+		{"a\nb\n", "a\nB\n", 1, 1},
+		{"", "no newline", 1, 0},
+		{"bin\x00ary", "changed\x00", 0, 0},
+	}
+	for _, test := range tests {
+		added, removed := countChangedLines(test.before, test.after)
+		if added != test.added || removed != test.removed {
+			t.Errorf("countChangedLines(%q, %q) = +%d/-%d, expected +%d/-%d", test.before, test.after, added, removed, test.added, test.removed)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// postCommitHookLine is the line 'plannet hooks install' adds to the post-commit hook
+const postCommitHookLine = `plannet hooks run post-commit >/dev/null 2>&1 || true`
+
+// gitHook is a git hook plannet installs and the line it adds to it
+type gitHook struct {
+	Name string
+	Line string
+}
+
+// gitHooks lists the hooks installed by 'plannet hooks install'
+var gitHooks = []gitHook{
+	{Name: "post-commit", Line: postCommitHookLine},
+	{Name: "post-checkout", Line: `plannet hooks run post-checkout "$@" >/dev/null 2>&1 || true`},
+}
+
+// hooksCmd represents the hooks command
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage the git hooks that notify plannet",
+	Long: `Install git hooks in the current repository so commits and branch switches
+are captured without running 'plannet status' by hand:
+
+- post-commit records the commit on the active work, or auto-tracks it when
+  auto_track is enabled
+- post-checkout links the new branch to the active work when it belongs to
+  the same ticket
+
+Both hooks also count as activity for idle detection. Existing hooks are
+kept; plannet only adds its own line to them.`,
+}
+
+// hooksInstallCmd represents the hooks install command
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install plannet's git hooks in the current repository",
+	Run: func(cmd *cobra.Command, args []string) {
+		runHooksInstall()
+	},
+}
+
+// hooksUninstallCmd represents the hooks uninstall command
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove plannet's git hooks from the current repository",
+	Run: func(cmd *cobra.Command, args []string) {
+		runHooksUninstall()
+	},
+}
+
+// hooksRunCmd represents the hooks run command, which the installed hooks call
+var hooksRunCmd = &cobra.Command{
+	Use:    "run <hook> [args...]",
+	Short:  "Handle a git hook event",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runHook(args[0], args[1:])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd)
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksUninstallCmd)
+	hooksCmd.AddCommand(hooksRunCmd)
+}
+
+func runHooksInstall() {
+	hooksDir, err := getHooksDir()
+	if err != nil {
+		printError("Error finding hooks directory:", err)
+		return
+	}
+
+	for _, hook := range gitHooks {
+		// The post-commit hook covers auto-track, so an existing auto-track line is replaced
+		hookPath, err := installHookLine(hooksDir, hook.Name, hook.Line, autoTrackHookLine)
+		if err != nil {
+			printErrorf("Error installing %s hook: %v\n", hook.Name, err)
+			return
+		}
+		fmt.Printf("Installed %s hook in %s\n", hook.Name, hookPath)
+	}
+}
+
+func runHooksUninstall() {
+	hooksDir, err := getHooksDir()
+	if err != nil {
+		printError("Error finding hooks directory:", err)
+		return
+	}
+
+	for _, hook := range gitHooks {
+		removed, err := removeHookLine(hooksDir, hook.Name, hook.Line)
+		if err != nil {
+			printErrorf("Error removing %s hook: %v\n", hook.Name, err)
+			return
+		}
+		if removed {
+			fmt.Printf("Removed plannet from the %s hook\n", hook.Name)
+		}
+	}
+}
+
+// runHook handles an event from one of the installed git hooks. Hooks run
+// in the background, so errors are not reported.
+func runHook(hook string, args []string) {
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+
+	switch hook {
+	case "post-commit":
+		handlePostCommit(cfg)
+	case "post-checkout":
+		// The third argument is 1 for a branch checkout and 0 for a file checkout
+		if len(args) < 3 || args[2] != "1" {
+			return
+		}
+		branch, err := getCurrentBranch()
+		if err != nil || branch == "HEAD" {
+			return
+		}
+		handleBranchSwitch(cfg, branch)
+	default:
+		return
+	}
+	recordHeartbeat(time.Now())
+}
+
+// handlePostCommit records the latest commit on the active work, or
+// auto-tracks it when auto-track is enabled
+func handlePostCommit(cfg *config.Config) {
+	commits, err := getRecentCommits(2)
+	if err != nil || len(commits) == 0 {
+		return
+	}
+	commit := commits[0]
+	files, _ := getCommitFiles(".", commit.Hash)
+
+	if cfg.AutoTrack {
+		var previous *Commit
+		if len(commits) > 1 {
+			previous = &commits[1]
+		}
+		branch, _ := getCurrentBranch()
+		autoTrackCommit(cfg, commit, previous, branch, files)
+		autoReplaySmartCommits(cfg, commits[:1])
+		return
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil || activeWork == nil {
+		return
+	}
+	recordCommit(activeWork, commit, files)
+	saveTrackedWork(*activeWork)
+}
+
+// handleBranchSwitch links a branch to the active work when the work has no
+// branch yet or the branch is for one of its tickets
+func handleBranchSwitch(cfg *config.Config, branch string) {
+	activeWork, err := getActiveWork()
+	if err != nil || activeWork == nil || activeWork.Context.Branch == branch {
+		return
+	}
+	ticketID := extractTicketID(branch, cfg.TicketPrefixes)
+	if activeWork.Context.Branch != "" && (ticketID == "" || !activeWork.HasTicket(ticketID)) {
+		return
+	}
+	activeWork.Context.Branch = branch
+	saveTrackedWork(*activeWork)
+}
+
+// getHooksDir returns the hooks directory of the current repository. The git
+// binary is used because only it knows about core.hooksPath and worktrees.
+func getHooksDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+// installHookLine adds a line to a git hook, creating the hook if needed and
+// dropping any of the replaced lines. It returns the path of the hook.
+func installHookLine(hooksDir, hook, line string, replaces ...string) (string, error) {
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	hookPath := filepath.Join(hooksDir, hook)
+	existing, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s hook: %w", hook, err)
+	}
+
+	lines := []string{"#!/bin/sh"}
+	if len(existing) > 0 {
+		lines = strings.Split(strings.TrimRight(string(existing), "\n"), "\n")
+	}
+
+	changed := len(existing) == 0
+	installed := false
+	var kept []string
+	for _, l := range lines {
+		if containsString(replaces, l) {
+			changed = true
+			continue
+		}
+		if l == line {
+			installed = true
+		}
+		kept = append(kept, l)
+	}
+	if !installed {
+		kept = append(kept, line)
+		changed = true
+	}
+	if !changed {
+		return hookPath, nil
+	}
+
+	if err := os.WriteFile(hookPath, []byte(strings.Join(kept, "\n")+"\n"), 0755); err != nil {
+		return "", fmt.Errorf("failed to write %s hook: %w", hook, err)
+	}
+	return hookPath, nil
+}
+
+// removeHookLine removes a line from a git hook, deleting the hook if
+// nothing but the shebang is left. It reports whether the line was found.
+func removeHookLine(hooksDir, hook, line string) (bool, error) {
+	hookPath := filepath.Join(hooksDir, hook)
+	existing, err := os.ReadFile(hookPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read %s hook: %w", hook, err)
+	}
+
+	var lines []string
+	found := false
+	for _, l := range strings.Split(strings.TrimRight(string(existing), "\n"), "\n") {
+		if l == line {
+			found = true
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if !found {
+		return false, nil
+	}
+
+	if len(lines) == 0 || (len(lines) == 1 && strings.HasPrefix(lines[0], "#!")) {
+		if err := os.Remove(hookPath); err != nil {
+			return false, fmt.Errorf("failed to remove %s hook: %w", hook, err)
+		}
+		return true, nil
+	}
+	if err := os.WriteFile(hookPath, []byte(strings.Join(lines, "\n")+"\n"), 0755); err != nil {
+		return false, fmt.Errorf("failed to write %s hook: %w", hook, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestInstallHookLine(t *testing.T) {
+	hooksDir := t.TempDir()
+	hookPath := filepath.Join(hooksDir, "post-commit")
+	if err := os.WriteFile(hookPath, []byte("#!/bin/sh\nmake lint\n"+autoTrackHookLine+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write hook: %v", err)
+	}
+
+	// Installing twice adds the line once and replaces the auto-track line
+	for i := 0; i < 2; i++ {
+		if _, err := installHookLine(hooksDir, "post-commit", postCommitHookLine, autoTrackHookLine); err != nil {
+			t.Fatalf("Failed to install hook: %v", err)
+		}
+	}
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		t.Fatalf("Failed to read hook: %v", err)
+	}
+	expected := "#!/bin/sh\nmake lint\n" + postCommitHookLine + "\n"
+	if string(data) != expected {
+		t.Errorf("Expected hook %q, got %q", expected, string(data))
+	}
+
+	// Removing the line keeps the rest of the hook
+	if removed, err := removeHookLine(hooksDir, "post-commit", postCommitHookLine); err != nil || !removed {
+		t.Fatalf("Expected the line to be removed, got %v (%v)", removed, err)
+	}
+	data, _ = os.ReadFile(hookPath)
+	if strings.Contains(string(data), "plannet") || !strings.Contains(string(data), "make lint") {
+		t.Errorf("Expected only the plannet line to be removed, got %q", string(data))
+	}
+
+	// A hook plannet created is removed entirely
+	if _, err := installHookLine(hooksDir, "post-checkout", "plannet hooks run post-checkout"); err != nil {
+		t.Fatalf("Failed to install hook: %v", err)
+	}
+	if _, err := removeHookLine(hooksDir, "post-checkout", "plannet hooks run post-checkout"); err != nil {
+		t.Fatalf("Failed to remove hook: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "post-checkout")); !os.IsNotExist(err) {
+		t.Error("Expected the empty hook to be deleted")
+	}
+}
+
+func TestHandleBranchSwitch(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	cfg := &config.Config{TicketPrefixes: []string{"JIRA-"}}
+	active := TrackedWork{ID: "active-1", Description: "Feature", TicketIDs: []string{"JIRA-1"}, StartTime: time.Now(), Status: "active"}
+	if err := saveTrackedWork(active); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	steps := []struct {
+		branch   string
+		expected string
+	}{
+		{"feature/JIRA-1-login", "feature/JIRA-1-login"}, // Work without a branch takes the first one
+		{"main", "feature/JIRA-1-login"},                 // Unrelated branches are ignored
+		{"fix/JIRA-1-followup", "fix/JIRA-1-followup"},   // Branches for the same ticket are followed
+	}
+	for _, step := range steps {
+		handleBranchSwitch(cfg, step.branch)
+		work, err := getActiveWork()
+		if err != nil || work == nil {
+			t.Fatalf("Failed to get active work: %v", err)
+		}
+		if work.Context.Branch != step.expected {
+			t.Errorf("After switching to %s, expected branch %s, got %s", step.branch, step.expected, work.Context.Branch)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// htmlReport is what the HTML report shows
+type htmlReport struct {
+	From, To   time.Time
+	Generated  time.Time
+	Total      string
+	Days       []htmlReportDay
+	Tickets    []htmlReportBar
+	SideQuests []htmlReportSideQuest
+}
+
+// htmlReportDay is the timeline of one day of the HTML report
+type htmlReportDay struct {
+	Date    time.Time
+	Total   string
+	Entries []htmlReportEntry
+}
+
+// htmlReportEntry is a piece of work on the timeline of a day. Left and
+// Width place it on the day, as percentages of 24 hours.
+type htmlReportEntry struct {
+	Start, End  time.Time
+	Duration    string
+	Description string
+	Status      string
+	Tickets     []htmlReportTicket
+	Left, Width float64
+}
+
+// htmlReportTicket is a ticket, linked when its address is known
+type htmlReportTicket struct {
+	Key string
+	URL string
+}
+
+// htmlReportBar is the time spent on a ticket, with the width of its bar as
+// a percentage of the longest
+type htmlReportBar struct {
+	htmlReportTicket
+	Duration string
+	Percent  float64
+	Width    float64
+}
+
+// htmlReportSideQuest is a commit that doesn't reference a ticket
+type htmlReportSideQuest struct {
+	Time    time.Time
+	Repo    string
+	Hash    string
+	Message string
+}
+
+// exportHTML exports tracked work as a self-contained HTML page with a
+// timeline of each day, the time spent on each ticket and the side quests
+// committed over the same time, in the current repository or, with all, in
+// every repository
+func exportHTML(cfg *config.Config, work []TrackedWork, outputPath, project string, all bool) error {
+	report := buildHTMLReport(cfg, work, project, time.Now())
+	if cfg.GitIntegration && !report.From.IsZero() {
+		sideQuests, err := htmlReportSideQuests(cfg, all, project, report.From)
+		if err != nil {
+			return err
+		}
+		report.SideQuests = sideQuests
+	}
+
+	if outputPath == "" {
+		return writeHTMLReport(os.Stdout, report)
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeHTMLReport(file, report)
+}
+
+// buildHTMLReport lays out the timeline of each day, oldest first, and the
+// time spent on each ticket
+func buildHTMLReport(cfg *config.Config, trackedWork []TrackedWork, project string, now time.Time) htmlReport {
+	report := htmlReport{Generated: now}
+
+	sorted := append([]TrackedWork(nil), trackedWork...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	var total time.Duration
+	days := make(map[time.Time]*htmlReportDay)
+	dayTotals := make(map[time.Time]time.Duration)
+	var dates []time.Time
+	for _, work := range sorted {
+		end := work.EndTime
+		if end.IsZero() {
+			end = now
+		}
+		if report.From.IsZero() || work.StartTime.Before(report.From) {
+			report.From = work.StartTime
+		}
+		if end.After(report.To) {
+			report.To = end
+		}
+
+		date := startOfDay(work.StartTime)
+		day, ok := days[date]
+		if !ok {
+			day = &htmlReportDay{Date: date}
+			days[date] = day
+			dates = append(dates, date)
+		}
+		day.Entries = append(day.Entries, htmlReportEntry{
+			Start:       work.StartTime,
+			End:         end,
+			Duration:    formatDuration(work.Duration()),
+			Description: work.Description,
+			Status:      work.Status,
+			Tickets:     htmlReportTickets(cfg, work.TicketIDs),
+			Left:        dayPercent(work.StartTime.Sub(date)),
+			Width:       dayPercent(timelineEnd(date, end).Sub(work.StartTime)),
+		})
+		dayTotals[date] += work.Duration()
+		total += work.Duration()
+	}
+	for _, date := range dates {
+		day := days[date]
+		day.Total = formatDuration(dayTotals[date])
+		report.Days = append(report.Days, *day)
+	}
+	report.Total = formatDuration(total)
+
+	groups := buildReport(workReportEntries(trackedWork, project), "ticket")
+	for _, group := range groups {
+		bar := htmlReportBar{
+			htmlReportTicket: htmlReportTicket{Key: group.Name},
+			Duration:         formatDuration(group.duration),
+			Percent:          group.Percent,
+			Width:            100 * float64(group.duration) / float64(groups[0].duration),
+		}
+		if group.Name != noTicketGroup {
+			bar.URL = jiraTicketURL(cfg, group.Name)
+		}
+		report.Tickets = append(report.Tickets, bar)
+	}
+	return report
+}
+
+// htmlReportTickets returns the tickets of work, linked to Jira when it is
+// the configured ticket system
+func htmlReportTickets(cfg *config.Config, ticketIDs []string) []htmlReportTicket {
+	var tickets []htmlReportTicket
+	for _, key := range ticketIDs {
+		tickets = append(tickets, htmlReportTicket{Key: key, URL: jiraTicketURL(cfg, key)})
+	}
+	return tickets
+}
+
+// timelineEnd returns the end of work on the timeline of the day starting
+// at date, which work running past midnight is cut off at
+func timelineEnd(date, end time.Time) time.Time {
+	if midnight := date.AddDate(0, 0, 1); end.After(midnight) {
+		return midnight
+	}
+	return end
+}
+
+// dayPercent returns a duration as a percentage of a day
+func dayPercent(d time.Duration) float64 {
+	if d < 0 {
+		return 0
+	}
+	return 100 * float64(d) / float64(24*time.Hour)
+}
+
+// htmlReportSideQuests returns the commits since the given time that don't
+// reference a ticket, newest first
+func htmlReportSideQuests(cfg *config.Config, all bool, project string, since time.Time) ([]htmlReportSideQuest, error) {
+	repos, err := reportRepos(cfg, all, project)
+	if err != nil {
+		return nil, err
+	}
+	var sideQuests []htmlReportSideQuest
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		commits, err := getCommitsAfter(repo.Path, since, commitFilterFor(cfg, false, nil))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits of %s: %w", repo.Name, err)
+		}
+		for _, commit := range findSideQuests(dedupeCommits(commits, seen), cfg.TicketPrefixes) {
+			sideQuests = append(sideQuests, htmlReportSideQuest{Time: commit.Time, Repo: repo.Name, Hash: shortHash(commit.Hash), Message: commit.Message})
+		}
+	}
+	sort.SliceStable(sideQuests, func(i, j int) bool { return sideQuests[i].Time.After(sideQuests[j].Time) })
+	return sideQuests, nil
+}
+
+// writeHTMLReport renders the HTML report
+func writeHTMLReport(w io.Writer, report htmlReport) error {
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// htmlReportTemplate is the HTML report. Its styles are inline, so the page
+// can be shared as a single file.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Work report {{.From.Format "2006-01-02"}} – {{.To.Format "2006-01-02"}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 960px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+h1 { margin-bottom: 0; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; margin-top: 2.5rem; }
+h3 { margin-bottom: .5rem; }
+a { color: #0969da; text-decoration: none; }
+.meta, .muted { color: #656d76; }
+.track { position: relative; height: 1.5rem; background: #f6f8fa; border: 1px solid #d0d7de; border-radius: 4px; }
+.track .work { position: absolute; top: 0; bottom: 0; min-width: 2px; background: #2da44e; opacity: .85; }
+.track .work.active, .track .work.paused { background: #bf8700; }
+.hours { display: flex; justify-content: space-between; font-size: .75rem; color: #656d76; }
+table { border-collapse: collapse; width: 100%; }
+td, th { text-align: left; padding: .25rem .5rem; vertical-align: top; }
+th { font-size: .85rem; color: #656d76; font-weight: 600; }
+.num { text-align: right; white-space: nowrap; }
+.bar { background: #0969da; height: 1rem; border-radius: 2px; min-width: 2px; }
+.side-quest { color: #8250df; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>Work report</h1>
+<p class="meta">{{.From.Format "Monday, 2 January 2006"}} – {{.To.Format "Monday, 2 January 2006"}} · {{.Total}} tracked · generated {{.Generated.Format "2006-01-02 15:04"}}</p>
+
+<h2>Time per ticket</h2>
+{{if .Tickets}}<table>
+<tr><th>Ticket</th><th class="num">Time</th><th class="num">Share</th><th style="width:50%"></th></tr>
+{{range .Tickets}}<tr>
+<td>{{if .URL}}<a href="{{.URL}}">{{.Key}}</a>{{else}}{{.Key}}{{end}}</td>
+<td class="num">{{.Duration}}</td>
+<td class="num">{{printf "%.1f" .Percent}}%</td>
+<td><div class="bar" style="width:{{printf "%.1f" .Width}}%"></div></td>
+</tr>
+{{end}}</table>
+{{else}}<p class="muted">No completed work.</p>
+{{end}}
+<h2>Daily timeline</h2>
+{{range .Days}}<h3>{{.Date.Format "Monday, 2 January"}} <span class="muted">· {{.Total}}</span></h3>
+<div class="track">{{range .Entries}}<div class="work {{.Status}}" style="left:{{printf "%.2f" .Left}}%;width:{{printf "%.2f" .Width}}%" title="{{.Start.Format "15:04"}}–{{.End.Format "15:04"}} {{.Description}}"></div>{{end}}</div>
+<div class="hours"><span>00:00</span><span>06:00</span><span>12:00</span><span>18:00</span><span>24:00</span></div>
+<table>
+{{range .Entries}}<tr>
+<td class="num">{{.Start.Format "15:04"}}–{{.End.Format "15:04"}}</td>
+<td class="num">{{.Duration}}</td>
+<td>{{range $i, $t := .Tickets}}{{if $i}}, {{end}}{{if $t.URL}}<a href="{{$t.URL}}">{{$t.Key}}</a>{{else}}{{$t.Key}}{{end}}{{end}}</td>
+<td>{{.Description}}{{if ne .Status "completed"}} <span class="muted">({{.Status}})</span>{{end}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+<h2>Side quests</h2>
+{{if .SideQuests}}<p class="muted">Commits that don't reference a ticket.</p>
+<table>
+{{range .SideQuests}}<tr>
+<td class="num">{{.Time.Format "2006-01-02 15:04"}}</td>
+<td><code>{{.Hash}}</code></td>
+<td class="muted">{{.Repo}}</td>
+<td class="side-quest">{{.Message}}</td>
+</tr>
+{{end}}</table>
+{{else}}<p class="muted">No side quests: every commit references a ticket.</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestBuildHTMLReport(t *testing.T) {
+	cfg := &config.Config{JiraURL: "https://example.atlassian.net"}
+	monday := time.Date(2024, 5, 6, 6, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{Description: "Review", TicketIDs: []string{"JIRA-2"}, StartTime: monday.AddDate(0, 0, 1), EndTime: monday.AddDate(0, 0, 1).Add(time.Hour), Status: "completed"},
+		{Description: "Login", TicketIDs: []string{"JIRA-1"}, StartTime: monday, EndTime: monday.Add(3 * time.Hour), Status: "completed"},
+		{Description: "Late fix", StartTime: monday.Add(16*time.Hour + 30*time.Minute), EndTime: monday.Add(18*time.Hour + 30*time.Minute), Status: "completed"},
+	}
+
+	report := buildHTMLReport(cfg, trackedWork, "", monday.AddDate(0, 0, 2))
+	if !report.From.Equal(monday) || !report.To.Equal(monday.AddDate(0, 0, 1).Add(time.Hour)) {
+		t.Errorf("report covers %v to %v", report.From, report.To)
+	}
+	if report.Total != "6h" {
+		t.Errorf("report total = %s, want 6h", report.Total)
+	}
+	if len(report.Days) != 2 || report.Days[0].Total != "5h" || len(report.Days[0].Entries) != 2 {
+		t.Fatalf("report days = %+v, want Monday with 5h of work first", report.Days)
+	}
+	login := report.Days[0].Entries[0]
+	if login.Left != 25 || login.Width != 12.5 {
+		t.Errorf("login is placed at %.2f%% with a width of %.2f%%, want 25%% and 12.5%%", login.Left, login.Width)
+	}
+	if late := report.Days[0].Entries[1]; late.Width != 6.25 {
+		t.Errorf("work past midnight has a width of %.2f%%, want it cut off at midnight", late.Width)
+	}
+
+	if len(report.Tickets) != 3 {
+		t.Fatalf("report tickets = %+v, want 3", report.Tickets)
+	}
+	first := report.Tickets[0]
+	if first.Key != "JIRA-1" || first.Width != 100 || first.URL != "https://example.atlassian.net/browse/JIRA-1" {
+		t.Errorf("first ticket = %+v, want JIRA-1 with the full bar and a link", first)
+	}
+	for _, bar := range report.Tickets {
+		if bar.Key == noTicketGroup && bar.URL != "" {
+			t.Errorf("work without a ticket is linked to %s", bar.URL)
+		}
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	monday := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	report := buildHTMLReport(&config.Config{}, []TrackedWork{
+		{Description: "Fix <script> injection", TicketIDs: []string{"JIRA-1"}, StartTime: monday, EndTime: monday.Add(time.Hour), Status: "completed"},
+	}, "", monday.Add(2*time.Hour))
+	report.SideQuests = []htmlReportSideQuest{{Time: monday, Repo: "api", Hash: "abc1234", Message: "Bump deps"}}
+
+	var buf bytes.Buffer
+	if err := writeHTMLReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{"Fix &lt;script&gt; injection", "JIRA-1", "Monday, 6 May", "Bump deps", "width:100.0%"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("report page doesn't contain %q", want)
+		}
+	}
+	for _, external := range []string{"<script", "<link", "src="} {
+		if strings.Contains(page, external) {
+			t.Errorf("report page contains %q, want it self-contained", external)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// heartbeatFile records the last time activity was seen
+const heartbeatFile = "heartbeat"
+
+// backgroundCheckSkipped lists commands that never trigger idle or stale work detection
+var backgroundCheckSkipped = map[string]bool{
+	"init":       true,
+	"migrate":    true,
+	"restore":    true,
+	"sync":       true,
+	"help":       true,
+	"completion": true,
+}
+
+// heartbeatCmd represents the heartbeat command
+var heartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Record activity for idle detection",
+	Long: `Record that you are active, so tracked work is not paused as idle.
+Every plannet command records activity, as do new commits in the current
+repository. Call this from an editor or shell hook to report keyboard
+activity in between, for example from your shell prompt:
+
+  PROMPT_COMMAND="plannet heartbeat; $PROMPT_COMMAND"
+
+Idle detection is enabled by setting idle_threshold_minutes in your configuration.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Activity is recorded for every command before it runs
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(heartbeatCmd)
+}
+
+// checkIdle pauses the active work if nothing has happened for longer than
+// the configured idle threshold, then records the current activity
+func checkIdle(cmd *cobra.Command) {
+	if backgroundCheckSkipped[cmd.Name()] {
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil || cfg.IdleThresholdMinutes <= 0 {
+		return
+	}
+	defer recordHeartbeat(time.Now())
+
+	// Without a previous heartbeat there is nothing to measure idle time from
+	heartbeat, err := readHeartbeat()
+	if err != nil || heartbeat.IsZero() {
+		return
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil || activeWork == nil {
+		return
+	}
+
+	now := time.Now()
+	idleSince := lastActiveTime(*activeWork, heartbeat, lastCommitTime())
+	idle := now.Sub(idleSince)
+	if idle < time.Duration(cfg.IdleThresholdMinutes)*time.Minute {
+		return
+	}
+
+	keep := false
+	if isInteractive() {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("No activity since %s (%s). Count the idle time towards %q", idleSince.Format("15:04"), formatDuration(idle), activeWork.Description),
+			IsConfirm: true,
+		}
+		_, err := prompt.Run()
+		keep = err == nil
+	}
+	if keep {
+		return
+	}
+
+	pauseWork(activeWork, idleSince)
+	if err := saveTrackedWork(*activeWork); err != nil {
+		printError("Error pausing idle work:", err)
+		return
+	}
+	fmt.Printf("Paused %s as of %s after %s idle. Run 'plannet resume' to continue.\n", activeWork.Description, idleSince.Format("15:04"), formatDuration(idle))
+}
+
+// lastActiveTime returns the most recent sign of activity on a piece of work
+func lastActiveTime(work TrackedWork, heartbeat, lastCommit time.Time) time.Time {
+	latest := lastActivity(work)
+	for _, t := range []time.Time{heartbeat, lastCommit} {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// lastCommitTime returns the time of the latest commit in the current
+// repository, or the zero time outside a repository
+func lastCommitTime() time.Time {
+	commits, err := getRecentCommits(1)
+	if err != nil || len(commits) == 0 {
+		return time.Time{}
+	}
+	return commits[0].Time
+}
+
+// getHeartbeatPath returns the path of the heartbeat file
+func getHeartbeatPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".plannet", heartbeatFile), nil
+}
+
+// readHeartbeat returns the last recorded activity, or the zero time if none was recorded
+func readHeartbeat() (time.Time, error) {
+	path, err := getHeartbeatPath()
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to read heartbeat: %w", err)
+	}
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse heartbeat: %w", err)
+	}
+	return t, nil
+}
+
+// recordHeartbeat records activity at the given time
+func recordHeartbeat(at time.Time) error {
+	path, err := getHeartbeatPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create plannet directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(at.Format(time.RFC3339)), 0644); err != nil {
+		return fmt.Errorf("failed to write heartbeat: %w", err)
+	}
+	return nil
+}
+
+// isInteractive reports whether plannet can prompt the user
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
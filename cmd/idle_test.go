@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestCheckIdlePausesWork(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Run outside any repository so commits don't count as activity
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.IdleThresholdMinutes = 30
+
+	now := time.Now().Truncate(time.Second)
+	work := TrackedWork{ID: "test-1", Description: "Idle work", StartTime: now.Add(-2 * time.Hour), Status: "active"}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+	lastSeen := now.Add(-90 * time.Minute)
+	if err := recordHeartbeat(lastSeen); err != nil {
+		t.Fatalf("Failed to record heartbeat: %v", err)
+	}
+
+	checkIdle(nowCmd)
+
+	paused, err := getPausedWork()
+	if err != nil {
+		t.Fatalf("Failed to get paused work: %v", err)
+	}
+	if len(paused) != 1 {
+		t.Fatalf("Expected idle work to be paused, got %d paused items", len(paused))
+	}
+	if len(paused[0].Pauses) != 1 || !paused[0].Pauses[0].Start.Equal(lastSeen) {
+		t.Errorf("Expected pause to start at the last activity %v, got %v", lastSeen, paused[0].Pauses)
+	}
+	if d := paused[0].Duration().Round(time.Minute); d != 30*time.Minute {
+		t.Errorf("Expected idle time to be excluded leaving 30m, got %v", d)
+	}
+
+	heartbeat, err := readHeartbeat()
+	if err != nil {
+		t.Fatalf("Failed to read heartbeat: %v", err)
+	}
+	if !heartbeat.After(lastSeen) {
+		t.Error("Expected the heartbeat to be updated")
+	}
+}
+
+func TestLastActiveTime(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	work := TrackedWork{
+		StartTime: start,
+		Pauses:    []PauseInterval{{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}},
+	}
+
+	tests := []struct {
+		name       string
+		heartbeat  time.Time
+		lastCommit time.Time
+		want       time.Time
+	}{
+		{"resumed", time.Time{}, time.Time{}, start.Add(2 * time.Hour)},
+		{"heartbeat", start.Add(3 * time.Hour), start.Add(time.Hour), start.Add(3 * time.Hour)},
+		{"commit", start.Add(3 * time.Hour), start.Add(4 * time.Hour), start.Add(4 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lastActiveTime(work, tt.heartbeat, tt.lastCommit); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
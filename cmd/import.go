@@ -0,0 +1,476 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// importFormats are the formats 'plannet import' reads
+var importFormats = []string{"csv", "json", "toggl", "clockify"}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import work tracked elsewhere",
+	Long: `Import time tracked before plannet, or in another copy of it, into the
+tracked work of the current project.
+
+The format is taken from the file unless given with --format:
+
+  csv       the columns of 'plannet export csv'
+  json      the output of 'plannet export json'
+  toggl     time entries from the Toggl Track API, as a JSON array
+  clockify  time entries from the Clockify API, as a JSON array
+
+Only completed work is imported; running timers and active or paused work
+are skipped. With scope_by_repo, work exported from another project goes
+back to that project when it is known here. Work keeps its ID, or gets one from the entry it came from,
+so importing the same file again only adds what is new. Ticket IDs are
+read from the descriptions of Toggl and Clockify entries.
+
+  plannet import work.csv --dry-run
+  plannet import toggl.json --tag imported`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		runImport(args[0], format, tags, dryRun)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().String("format", "", "The format of the file: csv, json, toggl or clockify (default from the file)")
+	importCmd.Flags().StringSlice("tag", nil, "Add this tag to the imported work (can be repeated)")
+	importCmd.Flags().Bool("dry-run", false, "Show the work that would be imported without importing it")
+	importCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return importFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+	importCmd.RegisterFlagCompletionFunc("tag", completeTags)
+}
+
+func runImport(path, format string, tags []string, dryRun bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError("Error reading file:", err)
+		return
+	}
+	if format == "" {
+		format = importFormatFor(path, data)
+	}
+	if !containsString(importFormats, format) {
+		printErrorf("Error: invalid format %q, expected one of %s\n", format, strings.Join(importFormats, ", "))
+		setExitCode(exitUsage)
+		return
+	}
+
+	imported, err := parseImport(format, data, cfg.TicketPrefixes)
+	if err != nil {
+		printErrorf("Error reading %s: %v\n", filepath.Base(path), err)
+		return
+	}
+	// Work may be saved to the project it was exported from, so it is
+	// known when any project tracks it
+	existing, err := getTrackedWork()
+	if scopeByRepo() {
+		existing, err = getAllTrackedWork()
+	}
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+	work, known, unfinished := newImportedWork(imported, existing, tags)
+
+	for _, w := range work {
+		description := w.Description
+		// Descriptions of other services usually mention the ticket already
+		if len(w.TicketIDs) > 0 && !strings.Contains(description, w.TicketIDs[0]) {
+			description = timeEntryDescription(w)
+		}
+		fmt.Printf("  %s  %6s  %s\n", w.StartTime.Format("2006-01-02 15:04"), formatDuration(w.Duration()), description)
+	}
+	var skipped []string
+	if known > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d already imported", known))
+	}
+	if unfinished > 0 {
+		skipped = append(skipped, fmt.Sprintf("%d not completed", unfinished))
+	}
+	summary := ""
+	if len(skipped) > 0 {
+		summary = " (skipped " + strings.Join(skipped, ", ") + ")"
+	}
+
+	if dryRun {
+		fmt.Printf("Would import %d pieces of work%s.\n", len(work), summary)
+		return
+	}
+	if len(work) > 0 {
+		if err := saveImportedWork(work); err != nil {
+			printError("Error saving imported work:", err)
+			return
+		}
+	}
+	fmt.Printf("Imported %d pieces of work%s.\n", len(work), summary)
+}
+
+// importFormatFor returns the format of a file to import: CSV by its
+// extension, or the kind of JSON by the fields of its first entry
+func importFormatFor(path string, data []byte) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return "csv"
+	}
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil || len(entries) == 0 {
+		return "json"
+	}
+	first := entries[0]
+	switch {
+	case first["timeInterval"] != nil:
+		return "clockify"
+	case first["start_time"] != nil:
+		return "json"
+	case first["start"] != nil:
+		return "toggl"
+	}
+	return "json"
+}
+
+// parseImport reads the work in a file to import. Ticket IDs are read from
+// descriptions with the given prefixes where the format has no field for them.
+func parseImport(format string, data []byte, prefixes []string) ([]TrackedWork, error) {
+	switch format {
+	case "csv":
+		return parseImportCSV(bytes.NewReader(data))
+	case "toggl":
+		return parseTogglEntries(data, prefixes)
+	case "clockify":
+		return parseClockifyEntries(data, prefixes)
+	}
+	var exported []exportedWork
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, err
+	}
+	var work []TrackedWork
+	for _, e := range exported {
+		e.TrackedWork.Project = e.Project
+		work = append(work, e.TrackedWork)
+	}
+	return work, nil
+}
+
+// parseImportCSV reads work in the columns of 'plannet export csv'. Columns
+// are found by their heading, so any of them but the start time may be left out.
+func parseImportCSV(r io.Reader) ([]TrackedWork, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, heading := range records[0] {
+		columns[strings.TrimSpace(heading)] = i
+	}
+	if _, ok := columns["Start Time"]; !ok {
+		return nil, fmt.Errorf("no Start Time column")
+	}
+
+	var work []TrackedWork
+	for n, record := range records[1:] {
+		field := func(heading string) string {
+			if i, ok := columns[heading]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		minutes := func(heading string) time.Duration {
+			m, _ := strconv.Atoi(field(heading))
+			return time.Duration(m) * time.Minute
+		}
+
+		w := TrackedWork{
+			ID:              field("ID"),
+			Description:     field("Description"),
+			TicketIDs:       splitImportList(field("Ticket IDs")),
+			Tags:            splitImportList(field("Tags")),
+			Status:          field("Status"),
+			EstimateMinutes: int(minutes("Estimate (minutes)").Minutes()),
+			Project:         field("Project"),
+		}
+		if w.StartTime, err = time.ParseInLocation(csvTimeLayout, field("Start Time"), time.Local); err != nil {
+			return nil, fmt.Errorf("line %d: invalid start time %q", n+2, field("Start Time"))
+		}
+		if end := field("End Time"); end != "" {
+			if w.EndTime, err = time.ParseInLocation(csvTimeLayout, end, time.Local); err != nil {
+				return nil, fmt.Errorf("line %d: invalid end time %q", n+2, end)
+			}
+		}
+		if w.Status == "" {
+			w.Status = "completed"
+		}
+		paused := minutes("Paused (minutes)")
+		if w.EndTime.IsZero() && w.Status == "completed" {
+			w.EndTime = w.StartTime.Add(minutes("Duration (minutes)") + paused)
+		}
+		// The pauses themselves aren't exported, so their time is kept as one
+		// pause at the end, which leaves the duration as it was
+		if paused > 0 && !w.EndTime.IsZero() {
+			w.Pauses = []PauseInterval{{Start: w.EndTime.Add(-paused), End: w.EndTime}}
+		}
+		w.Billable, _ = strconv.ParseBool(field("Billable"))
+		w.Notes = parseImportNotes(field("Notes"), w.StartTime)
+		w.Context.LinesAdded, _ = strconv.Atoi(field("Lines Added"))
+		w.Context.LinesRemoved, _ = strconv.Atoi(field("Lines Removed"))
+		w.Context.FileChurn = parseFileChurn(field("Files"))
+		if w.ID == "" {
+			w.ID = fmt.Sprintf("tw-%d", w.StartTime.UnixNano())
+		}
+		work = append(work, w)
+	}
+	return work, nil
+}
+
+// splitImportList splits a list exported as values separated by semicolons
+func splitImportList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// fileChurnPattern matches a file as formatFileChurn writes it
+var fileChurnPattern = regexp.MustCompile(`^(.+) \(\+(\d+)/-(\d+)\)$`)
+
+// parseFileChurn reads the lines changed per file as formatFileChurn writes them
+func parseFileChurn(value string) []FileChurn {
+	var churn []FileChurn
+	for _, file := range splitImportList(value) {
+		match := fileChurnPattern.FindStringSubmatch(file)
+		if match == nil {
+			churn = append(churn, FileChurn{Path: file})
+			continue
+		}
+		added, _ := strconv.Atoi(match[2])
+		removed, _ := strconv.Atoi(match[3])
+		churn = append(churn, FileChurn{Path: match[1], Added: added, Removed: removed})
+	}
+	return churn
+}
+
+// parseImportNotes reads notes as formatNotes writes them, one per line
+// after its time. Lines without a time are taken to be from the start.
+func parseImportNotes(value string, start time.Time) []Note {
+	var notes []Note
+	for _, line := range strings.Split(value, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		const layout = "2006-01-02 15:04"
+		if len(line) > len(layout) {
+			if t, err := time.ParseInLocation(layout, line[:len(layout)], time.Local); err == nil {
+				notes = append(notes, Note{Time: t, Text: strings.TrimSpace(line[len(layout):])})
+				continue
+			}
+		}
+		notes = append(notes, Note{Time: start, Text: strings.TrimSpace(line)})
+	}
+	return notes
+}
+
+// togglEntry is a time entry as the Toggl Track API returns it
+type togglEntry struct {
+	ID          int64      `json:"id"`
+	Description string     `json:"description"`
+	Start       time.Time  `json:"start"`
+	Stop        *time.Time `json:"stop"`
+	Duration    int64      `json:"duration"` // Seconds, negative while running
+	Tags        []string   `json:"tags"`
+	Billable    bool       `json:"billable"`
+}
+
+// parseTogglEntries reads Toggl Track time entries as work. Running entries
+// are active work.
+func parseTogglEntries(data []byte, prefixes []string) ([]TrackedWork, error) {
+	var entries []togglEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	var work []TrackedWork
+	for _, entry := range entries {
+		w := importedEntry(fmt.Sprintf("toggl-%d", entry.ID), entry.Description, entry.Start, entry.Tags, entry.Billable, prefixes)
+		switch {
+		case entry.Stop != nil:
+			w.EndTime = *entry.Stop
+		case entry.Duration >= 0:
+			w.EndTime = entry.Start.Add(time.Duration(entry.Duration) * time.Second)
+		default:
+			w.Status = "active"
+		}
+		work = append(work, w)
+	}
+	return work, nil
+}
+
+// clockifyEntry is a time entry as the Clockify API returns it, with its
+// tags when it was requested hydrated
+type clockifyEntry struct {
+	ID           string `json:"id"`
+	Description  string `json:"description"`
+	Billable     bool   `json:"billable"`
+	TimeInterval struct {
+		Start time.Time  `json:"start"`
+		End   *time.Time `json:"end"` // Nil while running
+	} `json:"timeInterval"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+// parseClockifyEntries reads Clockify time entries as work. Running entries
+// are active work.
+func parseClockifyEntries(data []byte, prefixes []string) ([]TrackedWork, error) {
+	var entries []clockifyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	var work []TrackedWork
+	for _, entry := range entries {
+		var tags []string
+		for _, tag := range entry.Tags {
+			tags = append(tags, tag.Name)
+		}
+		w := importedEntry("clockify-"+entry.ID, entry.Description, entry.TimeInterval.Start, tags, entry.Billable, prefixes)
+		if entry.TimeInterval.End != nil {
+			w.EndTime = *entry.TimeInterval.End
+		} else {
+			w.Status = "active"
+		}
+		work = append(work, w)
+	}
+	return work, nil
+}
+
+// importedEntry returns the completed work of a time entry from another
+// service, with the ticket its description mentions
+func importedEntry(id, description string, start time.Time, tags []string, billable bool, prefixes []string) TrackedWork {
+	w := TrackedWork{
+		ID:          id,
+		Description: description,
+		StartTime:   start.Local(),
+		Tags:        tags,
+		Status:      "completed",
+		Billable:    billable,
+	}
+	if ticketID := extractTicketIDFromMessage(description, prefixes); ticketID != "" {
+		w.TicketIDs = []string{ticketID}
+	}
+	return w
+}
+
+// newImportedWork returns the completed imported work that isn't tracked
+// yet, with the given tags added, and how many pieces of work were left out
+// for being known already or not completed
+func newImportedWork(imported, existing []TrackedWork, tags []string) (work []TrackedWork, known, unfinished int) {
+	seen := make(map[string]bool)
+	for _, w := range existing {
+		seen[w.ID] = true
+	}
+	for _, w := range imported {
+		switch {
+		case w.Status != "completed" || w.EndTime.IsZero():
+			unfinished++
+			continue
+		case seen[w.ID]:
+			known++
+			continue
+		}
+		seen[w.ID] = true
+		w.EndTime = w.EndTime.Local()
+		for _, tag := range tags {
+			if !containsString(w.Tags, tag) {
+				w.Tags = append(w.Tags, tag)
+			}
+		}
+		work = append(work, w)
+	}
+	return work, known, unfinished
+}
+
+// saveImportedWork adds completed work to the database in one write per
+// project, rather than rewriting it for each piece of work as
+// saveTrackedWork would. With scope_by_repo, work exported from a project
+// known here goes back to that project; the rest goes to the current one.
+func saveImportedWork(work []TrackedWork) error {
+	dbDir, err := openDB()
+	if err != nil {
+		return err
+	}
+	projectDirs := make(map[string]string)
+	if scopeByRepo() {
+		dbs, err := listProjectDBs()
+		if err != nil {
+			return err
+		}
+		for _, db := range dbs {
+			if _, ok := projectDirs[db.Project.Name]; db.Project.Name != "" && !ok {
+				projectDirs[db.Project.Name] = db.Dir
+			}
+		}
+	}
+
+	byDir := make(map[string][]TrackedWork)
+	var dirs []string
+	for _, w := range work {
+		dir := dbDir
+		if projectDir, ok := projectDirs[w.Project]; ok {
+			dir = projectDir
+		}
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], w)
+	}
+
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create database directory: %w", err)
+		}
+		completedFile := filepath.Join(dir, "completed.json")
+		completed, err := readWorkFile(completedFile)
+		if err != nil {
+			return err
+		}
+		if err := writeWorkFile(completedFile, append(completed, byDir[dir]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestParseImportCSVRoundTrip(t *testing.T) {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	work := []TrackedWork{
+		{ID: "tw-1", Description: "Fix login, again", TicketIDs: []string{"PROJ-1", "PROJ-2"}, Tags: []string{"backend", "urgent"},
+			StartTime: start, EndTime: start.Add(2 * time.Hour), Status: "completed", EstimateMinutes: 90, Billable: true,
+			Pauses: []PauseInterval{{Start: start.Add(30 * time.Minute), End: start.Add(45 * time.Minute)}},
+			Notes:  []Note{{Time: start.Add(time.Hour), Text: "Found the cause"}}},
+		{ID: "tw-2", Description: "Still going", StartTime: start.Add(3 * time.Hour), Status: "active"},
+	}
+	path := filepath.Join(t.TempDir(), "work.csv")
+	if err := exportCSV(work, path, "", config.Rates{}); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open export: %v", err)
+	}
+	defer file.Close()
+
+	imported, err := parseImportCSV(file)
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("Expected 2 pieces of work, got %+v", imported)
+	}
+	got := imported[0]
+	if got.ID != "tw-1" || got.Description != "Fix login, again" || !reflect.DeepEqual(got.TicketIDs, work[0].TicketIDs) ||
+		!reflect.DeepEqual(got.Tags, work[0].Tags) || got.EstimateMinutes != 90 || !got.Billable {
+		t.Errorf("Unexpected work %+v", got)
+	}
+	// The pause moves to the end, keeping the duration
+	if !got.StartTime.Equal(start) || !got.EndTime.Equal(work[0].EndTime) || got.Duration() != work[0].Duration() {
+		t.Errorf("Expected the times of the work, got %v – %v (%v)", got.StartTime, got.EndTime, got.Duration())
+	}
+	if len(got.Notes) != 1 || got.Notes[0].Text != "Found the cause" || !got.Notes[0].Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("Unexpected notes %+v", got.Notes)
+	}
+	if imported[1].Status != "active" || !imported[1].EndTime.IsZero() {
+		t.Errorf("Expected active work, got %+v", imported[1])
+	}
+}
+
+func TestParseImportCSVWithoutEndTime(t *testing.T) {
+	csv := "Description,Start Time,Duration (minutes)\nPlanning,2024-05-06 09:00:00,45\n"
+	work, err := parseImport("csv", []byte(csv), nil)
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	if len(work) != 1 || work[0].Status != "completed" || work[0].Duration() != 45*time.Minute || work[0].ID != fmt.Sprintf("tw-%d", start.UnixNano()) {
+		t.Errorf("Unexpected work %+v", work)
+	}
+
+	if _, err := parseImport("csv", []byte("Description\nPlanning\n"), nil); err == nil {
+		t.Error("Expected an error without a start time column")
+	}
+}
+
+func TestParseTogglAndClockifyEntries(t *testing.T) {
+	toggl := `[
+		{"id": 11, "description": "JIRA-7 Review", "start": "2024-05-06T09:00:00Z", "stop": "2024-05-06T10:30:00Z", "duration": 5400, "tags": ["review"], "billable": true},
+		{"id": 12, "description": "Email", "start": "2024-05-06T11:00:00Z", "duration": 900},
+		{"id": 13, "description": "Running", "start": "2024-05-06T12:00:00Z", "duration": -1714996800}
+	]`
+	if format := importFormatFor("entries.json", []byte(toggl)); format != "toggl" {
+		t.Errorf("Expected Toggl entries to be recognized, got %q", format)
+	}
+	work, err := parseImport("toggl", []byte(toggl), []string{"JIRA-"})
+	if err != nil {
+		t.Fatalf("Failed to import Toggl entries: %v", err)
+	}
+	if len(work) != 3 || work[0].ID != "toggl-11" || !reflect.DeepEqual(work[0].TicketIDs, []string{"JIRA-7"}) ||
+		work[0].Duration() != 90*time.Minute || !work[0].Billable || !reflect.DeepEqual(work[0].Tags, []string{"review"}) {
+		t.Fatalf("Unexpected Toggl work %+v", work)
+	}
+	if work[1].Duration() != 15*time.Minute || work[1].TicketIDs != nil || work[2].Status != "active" {
+		t.Errorf("Unexpected Toggl work %+v", work[1:])
+	}
+
+	clockify := `[
+		{"id": "abc", "description": "DEV-3 Deploy", "timeInterval": {"start": "2024-05-06T09:00:00Z", "end": "2024-05-06T09:20:00Z"}, "tags": [{"id": "t1", "name": "ops"}]},
+		{"id": "def", "description": "Running", "timeInterval": {"start": "2024-05-06T10:00:00Z", "end": null}}
+	]`
+	if format := importFormatFor("entries.json", []byte(clockify)); format != "clockify" {
+		t.Errorf("Expected Clockify entries to be recognized, got %q", format)
+	}
+	work, err = parseImport("clockify", []byte(clockify), []string{"DEV-"})
+	if err != nil {
+		t.Fatalf("Failed to import Clockify entries: %v", err)
+	}
+	if len(work) != 2 || work[0].ID != "clockify-abc" || !reflect.DeepEqual(work[0].TicketIDs, []string{"DEV-3"}) ||
+		work[0].Duration() != 20*time.Minute || !reflect.DeepEqual(work[0].Tags, []string{"ops"}) || work[1].Status != "active" {
+		t.Errorf("Unexpected Clockify work %+v", work)
+	}
+
+	if format := importFormatFor("work.json", []byte(`[{"id": "tw-1", "start_time": "2024-05-06T09:00:00Z"}]`)); format != "json" {
+		t.Errorf("Expected a plannet export to be recognized, got %q", format)
+	}
+	if format := importFormatFor("work.CSV", nil); format != "csv" {
+		t.Errorf("Expected CSV by the extension, got %q", format)
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	if err := saveTrackedWork(TrackedWork{ID: "toggl-11", Description: "Imported before", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"}); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "toggl.json")
+	toggl := `[
+		{"id": 11, "description": "JIRA-7 Review", "start": "2024-05-06T09:00:00Z", "duration": 5400},
+		{"id": 12, "description": "Email", "start": "2024-05-06T11:00:00Z", "duration": 900, "tags": ["mail"]},
+		{"id": 13, "description": "Running", "start": "2024-05-06T12:00:00Z", "duration": -1}
+	]`
+	if err := os.WriteFile(path, []byte(toggl), 0644); err != nil {
+		t.Fatalf("Failed to write entries: %v", err)
+	}
+
+	out := captureStdout(t, func() { runImport(path, "", []string{"imported", "mail"}, true) })
+	if want := "Would import 1 pieces of work (skipped 1 already imported, 1 not completed).\n"; !strings.HasSuffix(out, want) {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+	work, _ := getTrackedWork()
+	if len(work) != 1 {
+		t.Fatalf("Expected a dry run to import nothing, got %+v", work)
+	}
+
+	captureStdout(t, func() { runImport(path, "", []string{"imported", "mail"}, false) })
+	work, _ = getTrackedWork()
+	if len(work) != 2 {
+		t.Fatalf("Expected the new entry to be imported, got %+v", work)
+	}
+	var email TrackedWork
+	for _, w := range work {
+		if w.ID == "toggl-12" {
+			email = w
+		}
+	}
+	if email.Status != "completed" || !reflect.DeepEqual(email.Tags, []string{"mail", "imported"}) {
+		t.Errorf("Unexpected imported work %+v", email)
+	}
+
+	// Importing the same file again adds nothing
+	out = captureStdout(t, func() { runImport(path, "", nil, false) })
+	if !strings.Contains(out, "Imported 0 pieces of work (skipped 2 already imported, 1 not completed).") {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
+
+func TestRunImportInvalidFormat(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	resetExitCode(t)
+
+	path := filepath.Join(t.TempDir(), "work.txt")
+	os.WriteFile(path, []byte("[]"), 0644)
+	captureStdout(t, func() { runImport(path, "timewarrior", nil, false) })
+	if exitCode != exitUsage {
+		t.Errorf("Expected a usage error, got exit code %d", exitCode)
+	}
+}
+
+func TestImportRoundTripThroughStdout(t *testing.T) {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	work := []TrackedWork{{
+		ID: "tw-1", Description: "Fix login", TicketIDs: []string{"PROJ-1"}, StartTime: start, EndTime: start.Add(time.Hour),
+		Status: "completed", Project: "webapp",
+		Context: WorkContext{LinesAdded: 12, LinesRemoved: 3, FileChurn: []FileChurn{
+			{Path: "login.go", Added: 10, Removed: 3},
+			{Path: "docs/login (old).md", Added: 2},
+		}},
+	}}
+
+	exporters := map[string]func() error{
+		"csv":  func() error { return exportCSV(work, "", "", config.Rates{}) },
+		"json": func() error { return exportJSON(work, "", "", config.Rates{}) },
+	}
+	for format, export := range exporters {
+		var err error
+		out := captureStdout(t, func() { err = export() })
+		if err != nil {
+			t.Fatalf("Failed to export %s: %v", format, err)
+		}
+		imported, err := parseImport(format, []byte(out), nil)
+		if err != nil {
+			t.Fatalf("Failed to import %s: %v", format, err)
+		}
+		if len(imported) != 1 {
+			t.Fatalf("Expected 1 piece of work from %s, got %+v", format, imported)
+		}
+		got := imported[0]
+		if got.ID != "tw-1" || got.Project != "webapp" || got.Duration() != time.Hour || !reflect.DeepEqual(got.TicketIDs, work[0].TicketIDs) {
+			t.Errorf("Unexpected work from %s: %+v", format, got)
+		}
+		if got.Context.LinesAdded != 12 || got.Context.LinesRemoved != 3 || !reflect.DeepEqual(got.Context.FileChurn, work[0].Context.FileChurn) {
+			t.Errorf("Expected the changed files from %s, got %+v", format, got.Context)
+		}
+	}
+}
+
+func TestRunExportToStdoutIsImportable(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	if err := saveTrackedWork(TrackedWork{ID: "tw-1", Description: "Review", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"}); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+	for _, format := range []string{"csv", "json"} {
+		out := captureStdout(t, func() {
+			runExport(context.Background(), []string{format}, false, config.TimesheetSettings{}, workFilter{}, false)
+		})
+		if strings.Contains(out, "Export completed") {
+			t.Errorf("Expected only the %s export on stdout, got %q", format, out)
+		}
+		imported, err := parseImport(format, []byte(out), nil)
+		if err != nil || len(imported) != 1 || imported[0].ID != "tw-1" {
+			t.Errorf("Expected the %s export to import, got %+v, %v", format, imported, err)
+		}
+	}
+}
+
+func TestSaveImportedWorkToItsProject(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.ScopeByRepo = true
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	// The webapp project is known once work is tracked in it
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	repoDir := filepath.Join(tempDir, "webapp")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	if err := exec.Command("git", "-C", repoDir, "init").Run(); err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	if err := saveTrackedWork(TrackedWork{ID: "repo-1", StartTime: start, EndTime: start.Add(time.Minute), Status: "completed"}); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	// Import from outside the repository
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	imported := []TrackedWork{
+		{ID: "tw-1", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed", Project: "webapp"},
+		{ID: "tw-2", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed", Project: "elsewhere"},
+	}
+	if err := saveImportedWork(imported); err != nil {
+		t.Fatalf("Failed to save imported work: %v", err)
+	}
+
+	allWork, err := getAllTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get all tracked work: %v", err)
+	}
+	projects := map[string]string{}
+	for _, w := range allWork {
+		projects[w.ID] = w.Project
+	}
+	if projects["tw-1"] != "webapp" {
+		t.Errorf("Expected work from webapp to go back to it, got %q", projects["tw-1"])
+	}
+	if project, ok := projects["tw-2"]; !ok || project != "" {
+		t.Errorf("Expected work from an unknown project in the current database, got %q", project)
+	}
+}
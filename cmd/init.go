@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/manifoldco/promptui"
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
 	"github.com/plannet-ai/plannet/security"
 	"github.com/spf13/cobra"
 )
@@ -31,7 +33,7 @@ func runInit() {
 	// Get user's home directory
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Println("Error finding home directory:", err)
+		printError("Error finding home directory:", err)
 		return
 	}
 
@@ -47,7 +49,7 @@ func runInit() {
 
 		_, result, err := prompt.Run()
 		if err != nil {
-			fmt.Println("Error:", err)
+			printError("Error:", err)
 			return
 		}
 
@@ -78,7 +80,7 @@ func runInit() {
 
 	prefixesStr, err := prefixPrompt.Run()
 	if err != nil {
-		fmt.Println("Error:", err)
+		printError("Error:", err)
 		return
 	}
 
@@ -103,7 +105,7 @@ func runInit() {
 
 	editor, err := editorPrompt.Run()
 	if err != nil {
-		fmt.Println("Error:", err)
+		printError("Error:", err)
 		return
 	}
 	cfg.Editor = editor
@@ -116,7 +118,7 @@ func runInit() {
 
 	_, gitResult, err := gitPrompt.Run()
 	if err != nil {
-		fmt.Println("Error:", err)
+		printError("Error:", err)
 		return
 	}
 
@@ -135,7 +137,7 @@ func runInit() {
 
 	_, copyResult, err := copyPrompt.Run()
 	if err != nil {
-		fmt.Println("Error:", err)
+		printError("Error:", err)
 		return
 	}
 
@@ -159,24 +161,41 @@ func runInit() {
 
 	_, llmResult, err := llmPrompt.Run()
 	if err != nil {
-		fmt.Println("Error:", err)
+		printError("Error:", err)
 		return
 	}
 
 	if llmResult == "Yes" {
+		// Offer the models of the LLM servers running locally first
+		printInfo("Looking for local LLM servers...")
+		localModels := localModelItems(llm.DiscoverLocalModels(context.Background()))
+		items := make([]string, 0, len(localModels)+5)
+		for _, item := range localModels {
+			items = append(items, item.label)
+		}
+		items = append(items, "Plannet (brain.plannet.dev)", "OpenAI", "Anthropic", "Ollama (local)", "Custom endpoint")
+
 		// Ask for LLM provider
 		providerPrompt := promptui.Select{
 			Label: "Select LLM provider",
-			Items: []string{"Plannet (brain.plannet.dev)", "Custom endpoint"},
+			Items: items,
+			Size:  10,
 		}
 
-		_, providerResult, err := providerPrompt.Run()
+		index, providerResult, err := providerPrompt.Run()
 		if err != nil {
-			fmt.Println("Error:", err)
+			printError("Error:", err)
 			return
 		}
 
-		if providerResult == "Plannet (brain.plannet.dev)" {
+		if index < len(localModels) {
+			useLocalModel(cfg, localModels[index].model)
+		} else if provider, ok := llmProviders[providerResult]; ok {
+			if err := initLLMProvider(cfg, provider); err != nil {
+				printError("Error:", err)
+				return
+			}
+		} else if providerResult == "Plannet (brain.plannet.dev)" {
 			// Set up Plannet LLM
 			cfg.BaseURL = "https://brain.plannet.dev/v1/completions"
 			cfg.Model = "plannet-default"
@@ -197,7 +216,7 @@ func runInit() {
 
 			apiKey, err := apiKeyPrompt.Run()
 			if err != nil {
-				fmt.Println("Error:", err)
+				printError("Error:", err)
 				return
 			}
 
@@ -220,7 +239,7 @@ func runInit() {
 
 			baseURL, err := baseURLPrompt.Run()
 			if err != nil {
-				fmt.Println("Error:", err)
+				printError("Error:", err)
 				return
 			}
 			cfg.BaseURL = baseURL
@@ -239,7 +258,7 @@ func runInit() {
 
 			model, err := modelPrompt.Run()
 			if err != nil {
-				fmt.Println("Error:", err)
+				printError("Error:", err)
 				return
 			}
 			cfg.Model = model
@@ -255,7 +274,7 @@ func runInit() {
 
 			apiKey, err := apiKeyPrompt.Run()
 			if err != nil {
-				fmt.Println("Error:", err)
+				printError("Error:", err)
 				return
 			}
 
@@ -275,7 +294,7 @@ func runInit() {
 
 		systemPrompt, err := systemPromptPrompt.Run()
 		if err != nil {
-			fmt.Println("Error:", err)
+			printError("Error:", err)
 			return
 		}
 
@@ -284,87 +303,20 @@ func runInit() {
 		}
 	}
 
-	// Ask about Jira integration
-	jiraPrompt := promptui.Select{
-		Label: "Would you like to set up Jira integration? (Optional)",
-		Items: []string{"Yes", "No"},
-	}
-
-	_, jiraResult, err := jiraPrompt.Run()
+	// Ask which ticket system to integrate with
+	system, err := selectTicketSystem()
 	if err != nil {
-		fmt.Println("Error:", err)
+		printError("Error:", err)
 		return
 	}
-
-	if jiraResult == "Yes" {
-		// Ask for Jira URL
-		fmt.Println("\nPlease enter your Jira instance URL.")
-		fmt.Println("Example: https://your-company.atlassian.net")
-
-		jiraURLPrompt := promptui.Prompt{
-			Label:   "Jira URL",
-			Default: "https://your-instance.atlassian.net",
-			Validate: func(input string) error {
-				return security.ValidateURL(input)
-			},
-		}
-
-		jiraURL, err := jiraURLPrompt.Run()
-		if err != nil {
-			fmt.Println("Error:", err)
-			return
-		}
-		cfg.JiraURL = jiraURL
-
-		// Ask for Jira username/email
-		fmt.Println("\nPlease enter your Jira username or email address.")
-
-		jiraUserPrompt := promptui.Prompt{
-			Label: "Jira Username/Email",
-			Validate: func(input string) error {
-				if input == "" {
-					return fmt.Errorf("username cannot be empty")
-				}
-				return nil
-			},
-		}
-
-		jiraUser, err := jiraUserPrompt.Run()
-		if err != nil {
-			fmt.Println("Error:", err)
-			return
-		}
-		cfg.JiraUser = jiraUser
-
-		// Ask for Jira API token
-		fmt.Println("\nTo use Jira, you need an API token.")
-		fmt.Println("1. Visit https://id.atlassian.com/manage-profile/security/api-tokens")
-		fmt.Println("2. Click 'Create API token'")
-		fmt.Println("3. Give it a name (e.g., 'Plannet')")
-		fmt.Println("4. Copy the token and paste it below")
-		fmt.Println("\nNote: The token will be securely stored and masked when displayed.")
-
-		jiraTokenPrompt := promptui.Prompt{
-			Label: "Jira API Token",
-			Mask:  '•',
-			Validate: func(input string) error {
-				return security.ValidateAPIKey(input)
-			},
-		}
-
-		jiraToken, err := jiraTokenPrompt.Run()
-		if err != nil {
-			fmt.Println("Error:", err)
-			return
-		}
-
-		// Store the Jira token in the config
-		cfg.JiraToken = jiraToken
+	if err := initTicketSystem(cfg, system); err != nil {
+		printError("Error:", err)
+		return
 	}
 
 	// Save the configuration
 	if err := config.Save(cfg); err != nil {
-		fmt.Println("Error saving configuration:", err)
+		printError("Error saving configuration:", err)
 		return
 	}
 
@@ -378,3 +330,156 @@ func runInit() {
 	fmt.Println("3. View your current focus with 'plannet now'")
 	fmt.Println("4. See your work timeline with 'plannet status'")
 }
+
+// llmProvider is an LLM provider plannet init can set up
+type llmProvider struct {
+	name         string // The name the provider setting selects it by
+	title        string
+	defaultModel string
+	keyURL       string // Where API keys are created; empty for providers without keys
+}
+
+// llmProviders are the providers plannet init can set up, by their item in
+// the provider prompt
+var llmProviders = map[string]llmProvider{
+	"OpenAI":         {name: llm.ProviderOpenAI, title: "OpenAI", defaultModel: "gpt-4o-mini", keyURL: "https://platform.openai.com/api-keys"},
+	"Anthropic":      {name: llm.ProviderAnthropic, title: "Anthropic", defaultModel: "claude-3-5-sonnet-latest", keyURL: "https://console.anthropic.com/settings/keys"},
+	"Ollama (local)": {name: llm.ProviderOllama, title: "Ollama", defaultModel: "llama3.1"},
+}
+
+// initLLMProvider sets up a provider at its usual endpoint: it asks for the
+// model and, unless the provider runs locally, an API key
+func initLLMProvider(cfg *config.Config, provider llmProvider) error {
+	cfg.Provider = provider.name
+	cfg.BaseURL = ""
+
+	modelPrompt := promptui.Prompt{
+		Label:   "Enter model name",
+		Default: provider.defaultModel,
+		Validate: func(input string) error {
+			if input == "" {
+				return fmt.Errorf("model name cannot be empty")
+			}
+			return nil
+		},
+	}
+	model, err := modelPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Model = model
+
+	if provider.keyURL == "" {
+		fmt.Printf("\n%s needs to be running, with the model pulled: ollama pull %s\n", provider.title, model)
+		return nil
+	}
+
+	fmt.Printf("\nTo use %s, you need an API key. Create one at %s\n", provider.title, provider.keyURL)
+	apiKeyPrompt := promptui.Prompt{
+		Label: provider.title + " API Key",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	apiKey, err := apiKeyPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.LLMToken = apiKey
+	return nil
+}
+
+// localModelItem is a local model offered in the provider prompt
+type localModelItem struct {
+	label string
+	model llm.LocalModel
+}
+
+// localModelItems returns the provider prompt's items of local models,
+// such as "llama3.1:latest (Ollama, running locally)"
+func localModelItems(models []llm.LocalModel) []localModelItem {
+	items := make([]localModelItem, len(models))
+	for i, model := range models {
+		items[i] = localModelItem{
+			label: fmt.Sprintf("%s (%s, running locally)", model.Model, model.Server),
+			model: model,
+		}
+	}
+	return items
+}
+
+// useLocalModel sets up a model of a local server, which needs no API key
+func useLocalModel(cfg *config.Config, model llm.LocalModel) {
+	cfg.Provider = model.Provider
+	cfg.BaseURL = model.BaseURL
+	cfg.Model = model.Model
+	cfg.LLMToken = ""
+	cfg.Headers = nil
+	fmt.Printf("Using %s on %s at %s\n", model.Model, model.Server, model.BaseURL)
+}
+
+// initJira asks for the Jira instance, user and API token
+func initJira(cfg *config.Config) error {
+	// Ask for Jira URL
+	fmt.Println("\nPlease enter your Jira instance URL.")
+	fmt.Println("Example: https://your-company.atlassian.net")
+
+	jiraURLPrompt := promptui.Prompt{
+		Label:   "Jira URL",
+		Default: "https://your-instance.atlassian.net",
+		Validate: func(input string) error {
+			return security.ValidateURL(input)
+		},
+	}
+
+	jiraURL, err := jiraURLPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.JiraURL = jiraURL
+
+	// Ask for Jira username/email
+	fmt.Println("\nPlease enter your Jira username or email address.")
+
+	jiraUserPrompt := promptui.Prompt{
+		Label: "Jira Username/Email",
+		Validate: func(input string) error {
+			if input == "" {
+				return fmt.Errorf("username cannot be empty")
+			}
+			return nil
+		},
+	}
+
+	jiraUser, err := jiraUserPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.JiraUser = jiraUser
+
+	// Ask for Jira API token
+	fmt.Println("\nTo use Jira, you need an API token.")
+	fmt.Println("1. Visit https://id.atlassian.com/manage-profile/security/api-tokens")
+	fmt.Println("2. Click 'Create API token'")
+	fmt.Println("3. Give it a name (e.g., 'Plannet')")
+	fmt.Println("4. Copy the token and paste it below")
+	fmt.Println("\nNote: The token will be securely stored and masked when displayed.")
+
+	jiraTokenPrompt := promptui.Prompt{
+		Label: "Jira API Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+
+	jiraToken, err := jiraTokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	// Store the Jira token in the config
+	cfg.JiraToken = jiraToken
+	return nil
+}
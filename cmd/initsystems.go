@@ -0,0 +1,733 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/security"
+)
+
+// selectTicketSystem asks which of the registered ticket systems to
+// integrate with, Jira first, and returns its name, or "" for none
+func selectTicketSystem() (string, error) {
+	names := []string{systems.DefaultSystem}
+	items := []string{"Jira"}
+	for _, reg := range systems.List() {
+		if reg.Name == systems.DefaultSystem {
+			continue
+		}
+		names = append(names, reg.Name)
+		items = append(items, reg.Title)
+	}
+	names = append(names, "")
+	items = append(items, "None")
+
+	prompt := promptui.Select{
+		Label: "Which ticket system would you like to integrate with? (Optional)",
+		Items: items,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+	return names[index], nil
+}
+
+// initTicketSystem asks for the settings of a ticket system and selects it
+func initTicketSystem(cfg *config.Config, system string) error {
+	switch system {
+	case "":
+		return nil
+	case "jira":
+		return initJira(cfg)
+	case "github":
+		if err := initGitHub(cfg); err != nil {
+			return err
+		}
+	case "gitlab":
+		if err := initGitLab(cfg); err != nil {
+			return err
+		}
+	case "linear":
+		if err := initLinear(cfg); err != nil {
+			return err
+		}
+	case "asana":
+		if err := initAsana(cfg); err != nil {
+			return err
+		}
+	case "trello":
+		if err := initTrello(cfg); err != nil {
+			return err
+		}
+	case "youtrack":
+		if err := initYouTrack(cfg); err != nil {
+			return err
+		}
+	case "clickup":
+		if err := initClickUp(cfg); err != nil {
+			return err
+		}
+	case "notion":
+		if err := initNotion(cfg); err != nil {
+			return err
+		}
+	case "redmine":
+		if err := initRedmine(cfg); err != nil {
+			return err
+		}
+	case "generic":
+		if err := initGeneric(cfg); err != nil {
+			return err
+		}
+	default:
+		reg, ok := systems.Default.Lookup(system)
+		if !ok || reg.Plugin == "" {
+			return fmt.Errorf("plannet init can't set up %q yet; set it up in your configuration", system)
+		}
+		fmt.Printf("\nUsing the %s plugin at %s.\n", system, reg.Plugin)
+		fmt.Printf("Put its settings under system_plugins.%s in your configuration, if it needs any.\n", system)
+	}
+	cfg.TicketSystem = system
+	return nil
+}
+
+// initGitHub asks for the repository of GitHub issues and logs in, with a
+// personal access token or the device flow of an OAuth app
+func initGitHub(cfg *config.Config) error {
+	repoPrompt := promptui.Prompt{
+		Label: "Default repository for issues (owner/name, optional)",
+		Validate: func(input string) error {
+			if input != "" && strings.Count(input, "/") != 1 {
+				return fmt.Errorf("expected owner/name")
+			}
+			return nil
+		},
+	}
+	repo, err := repoPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.GitHub.Repo = repo
+
+	authPrompt := promptui.Select{
+		Label: "How would you like to log in to GitHub?",
+		Items: []string{"Personal access token", "Log in with the browser (OAuth device flow)"},
+	}
+	index, _, err := authPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	if index == 1 {
+		fmt.Println("\nThe device flow needs the client ID of a GitHub OAuth app with device flow enabled.")
+		clientIDPrompt := promptui.Prompt{
+			Label: "OAuth app client ID",
+			Validate: func(input string) error {
+				if input == "" {
+					return fmt.Errorf("client ID cannot be empty")
+				}
+				return nil
+			},
+		}
+		clientID, err := clientIDPrompt.Run()
+		if err != nil {
+			return err
+		}
+		cfg.GitHub.ClientID = clientID
+
+		token, err := systems.GitHubDeviceLogin(context.Background(), cfg.GitHub.APIURL, clientID, func(code systems.GitHubDeviceCode) {
+			fmt.Printf("\nOpen %s and enter the code %s\n", code.VerificationURI, code.UserCode)
+			printInfo("Waiting for you to authorize plannet...")
+		})
+		if err != nil {
+			return err
+		}
+		cfg.GitHubToken = token
+		fmt.Println("Logged in to GitHub.")
+		return nil
+	}
+
+	fmt.Println("\nTo use GitHub Issues, you need a personal access token.")
+	fmt.Println("1. Visit https://github.com/settings/tokens")
+	fmt.Println("2. Create a token with access to the issues of your repositories")
+	fmt.Println("3. Copy the token and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "GitHub Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.GitHubToken = token
+	return nil
+}
+
+// initGitLab asks for the GitLab instance, the project of issues and merge
+// requests, and a personal access token
+func initGitLab(cfg *config.Config) error {
+	urlPrompt := promptui.Prompt{
+		Label:   "GitLab URL",
+		Default: "https://gitlab.com",
+		Validate: func(input string) error {
+			return security.ValidateURL(input)
+		},
+	}
+	gitlabURL, err := urlPrompt.Run()
+	if err != nil {
+		return err
+	}
+	if gitlabURL != "https://gitlab.com" {
+		cfg.GitLab.URL = gitlabURL
+	}
+
+	projectPrompt := promptui.Prompt{
+		Label: "Default project for issues and merge requests (group/project, optional)",
+	}
+	project, err := projectPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.GitLab.Project = strings.Trim(project, "/")
+
+	fmt.Println("\nTo use GitLab, you need a personal access token with the api scope.")
+	fmt.Printf("1. Visit %s/-/user_settings/personal_access_tokens\n", strings.TrimSuffix(gitlabURL, "/"))
+	fmt.Println("2. Create a token with the api scope")
+	fmt.Println("3. Copy the token and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "GitLab Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.GitLabToken = token
+	return nil
+}
+
+// initLinear asks for a Linear API key and the team of new issues, and adds
+// the keys of the user's teams to the ticket prefixes, so that issues such
+// as ENG-123 are found in branch names and commit messages
+func initLinear(cfg *config.Config) error {
+	fmt.Println("\nTo use Linear, you need a personal API key.")
+	fmt.Println("1. Visit https://linear.app/settings/account/security")
+	fmt.Println("2. Create a personal API key")
+	fmt.Println("3. Copy the key and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "Linear API Key",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.LinearToken = token
+
+	teamPrompt := promptui.Prompt{
+		Label: "Default team key for new issues (such as ENG, optional)",
+	}
+	team, err := teamPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Linear.Team = strings.ToUpper(strings.TrimSpace(team))
+
+	linear, err := systems.NewLinear(cfg)
+	if err != nil {
+		return err
+	}
+	prefixes, err := linear.TeamPrefixes(context.Background())
+	if err != nil {
+		fmt.Printf("Warning: Could not fetch your Linear teams: %v\n", err)
+		return nil
+	}
+	for _, prefix := range prefixes {
+		if !containsString(cfg.TicketPrefixes, prefix) {
+			cfg.TicketPrefixes = append(cfg.TicketPrefixes, prefix)
+		}
+	}
+	if len(prefixes) > 0 {
+		fmt.Printf("Added your Linear team prefixes: %s\n", strings.Join(prefixes, ", "))
+	}
+	return nil
+}
+
+// initAsana asks for an Asana personal access token, the workspace whose
+// tasks are listed, and the project new tasks are added to
+func initAsana(cfg *config.Config) error {
+	fmt.Println("\nTo use Asana, you need a personal access token.")
+	fmt.Println("1. Visit https://app.asana.com/0/my-apps")
+	fmt.Println("2. Create a personal access token")
+	fmt.Println("3. Copy the token and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "Asana Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.AsanaToken = token
+
+	asana, err := systems.NewAsana(cfg)
+	if err != nil {
+		return err
+	}
+	workspaces, err := asana.Workspaces(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to fetch your Asana workspaces: %w", err)
+	}
+	switch len(workspaces) {
+	case 0:
+		return fmt.Errorf("your Asana account has no workspaces")
+	case 1:
+		cfg.Asana.Workspace = workspaces[0].GID
+	default:
+		names := make([]string, len(workspaces))
+		for i, workspace := range workspaces {
+			names[i] = workspace.Name
+		}
+		workspacePrompt := promptui.Select{
+			Label: "Which workspace are your tasks in?",
+			Items: names,
+		}
+		index, _, err := workspacePrompt.Run()
+		if err != nil {
+			return err
+		}
+		cfg.Asana.Workspace = workspaces[index].GID
+	}
+
+	projectPrompt := promptui.Prompt{
+		Label: "Project ID for new tasks (the number in the project's URL, optional)",
+	}
+	project, err := projectPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Asana.Project = strings.TrimSpace(project)
+	return nil
+}
+
+// initTrello asks for a Trello API key and token, the board whose cards are
+// listed, and the list new cards are added to
+func initTrello(cfg *config.Config) error {
+	fmt.Println("\nTo use Trello, you need an API key and a token.")
+	fmt.Println("1. Visit https://trello.com/power-ups/admin and create a Power-Up")
+	fmt.Println("2. Generate an API key for it and paste it below")
+
+	keyPrompt := promptui.Prompt{
+		Label: "Trello API Key",
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	key, err := keyPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Trello.APIKey = key
+
+	fmt.Printf("3. Visit https://trello.com/1/authorize?expiration=never&scope=read,write&response_type=token&key=%s\n", key)
+	fmt.Println("4. Allow access, then copy the token and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "Trello Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.TrelloToken = token
+
+	trello, err := systems.NewTrello(cfg)
+	if err != nil {
+		return err
+	}
+	boards, err := trello.Boards(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to fetch your Trello boards: %w", err)
+	}
+	if len(boards) == 0 {
+		fmt.Println("You have no open Trello boards; cards on every board will be listed.")
+		return nil
+	}
+
+	names := []string{"All boards"}
+	for _, board := range boards {
+		names = append(names, board.Name)
+	}
+	boardPrompt := promptui.Select{
+		Label: "Which board do you work from?",
+		Items: names,
+	}
+	index, _, err := boardPrompt.Run()
+	if err != nil || index == 0 {
+		return err
+	}
+	board := boards[index-1]
+	cfg.Trello.Board = board.ID
+	if len(board.Lists) == 0 {
+		return nil
+	}
+
+	lists := make([]string, len(board.Lists))
+	for i, list := range board.Lists {
+		lists[i] = list.Name
+	}
+	listPrompt := promptui.Select{
+		Label: "Which list should new cards go to?",
+		Items: lists,
+	}
+	index, _, err = listPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Trello.List = board.Lists[index].ID
+	return nil
+}
+
+// initGeneric asks for the mapping file describing a tracker's REST API,
+// and the token its requests authenticate with
+func initGeneric(cfg *config.Config) error {
+	mappingPrompt := promptui.Prompt{
+		Label: "Path of the mapping file describing the tracker's API",
+		Validate: func(input string) error {
+			_, err := systems.LoadGenericMapping(input)
+			return err
+		},
+	}
+	mapping, err := mappingPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Generic.Mapping = mapping
+
+	tokenPrompt := promptui.Prompt{
+		Label: "API token, replacing {{token}} in the mapping's auth (optional)",
+		Mask:  '•',
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.GenericToken = token
+	return nil
+}
+
+// initYouTrack asks for the YouTrack instance, the project of new issues,
+// and a permanent token
+func initYouTrack(cfg *config.Config) error {
+	urlPrompt := promptui.Prompt{
+		Label: "YouTrack URL (e.g., https://your-company.youtrack.cloud)",
+		Validate: func(input string) error {
+			return security.ValidateURL(input)
+		},
+	}
+	youtrackURL, err := urlPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.YouTrack.URL = strings.TrimSuffix(youtrackURL, "/")
+
+	projectPrompt := promptui.Prompt{
+		Label: "Short name of the project for new issues (such as ABC, optional)",
+	}
+	project, err := projectPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.YouTrack.Project = strings.TrimSpace(project)
+
+	fmt.Println("\nTo use YouTrack, you need a permanent token.")
+	fmt.Println("1. Open your profile in YouTrack, then Account Security")
+	fmt.Println("2. Create a token with access to YouTrack")
+	fmt.Println("3. Copy the token and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "YouTrack Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.YouTrackToken = token
+	return nil
+}
+
+// initClickUp asks for a ClickUp API token, then the workspace and space
+// whose tasks are listed and the list new tasks are created in
+func initClickUp(cfg *config.Config) error {
+	fmt.Println("\nTo use ClickUp, you need a personal API token.")
+	fmt.Println("1. Open Settings in ClickUp, then Apps")
+	fmt.Println("2. Generate an API token")
+	fmt.Println("3. Copy the token and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "ClickUp Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.ClickUpToken = token
+
+	clickup, err := systems.NewClickUp(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	workspaces, err := clickup.Workspaces(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch your ClickUp workspaces: %w", err)
+	}
+	workspace, err := selectClickUpItem("Which workspace are your tasks in?", workspaces, "")
+	if err != nil {
+		return err
+	}
+	if workspace == nil {
+		return fmt.Errorf("your ClickUp account has no workspaces")
+	}
+	cfg.ClickUp.Workspace = workspace.ID
+
+	spaces, err := clickup.Spaces(ctx, workspace.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch your ClickUp spaces: %w", err)
+	}
+	space, err := selectClickUpItem("Which space do you work in?", spaces, "All spaces")
+	if err != nil || space == nil {
+		return err
+	}
+	cfg.ClickUp.Space = space.ID
+
+	lists, err := clickup.Lists(ctx, space.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch your ClickUp lists: %w", err)
+	}
+	list, err := selectClickUpItem("Which list should new tasks go to?", lists, "None")
+	if err != nil || list == nil {
+		return err
+	}
+	cfg.ClickUp.List = list.ID
+	return nil
+}
+
+// selectClickUpItem asks the user to pick one of the items, or none if
+// noneLabel is given, and returns nil for none. A single item is picked
+// without asking unless none can be picked.
+func selectClickUpItem(label string, items []systems.ClickUpItem, noneLabel string) (*systems.ClickUpItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if len(items) == 1 && noneLabel == "" {
+		return &items[0], nil
+	}
+
+	var names []string
+	if noneLabel != "" {
+		names = append(names, noneLabel)
+	}
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	prompt := promptui.Select{
+		Label: label,
+		Items: names,
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	if noneLabel != "" {
+		if index == 0 {
+			return nil, nil
+		}
+		index--
+	}
+	return &items[index], nil
+}
+
+// initNotion asks for a Notion integration token, the database of tickets,
+// the properties holding their status, assignee and ID, and which person
+// you are
+func initNotion(cfg *config.Config) error {
+	fmt.Println("\nTo use a Notion database, you need an internal integration token.")
+	fmt.Println("1. Visit https://www.notion.so/my-integrations and create an integration")
+	fmt.Println("2. Give it read, update and insert content, comment, and user capabilities")
+	fmt.Println("3. Connect the integration to your database, from the database's ... menu")
+	fmt.Println("4. Copy the integration's secret and paste it below")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "Notion Token",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.NotionToken = token
+
+	databasePrompt := promptui.Prompt{
+		Label: "Database URL or ID",
+		Validate: func(input string) error {
+			if notionDatabaseID(input) == "" {
+				return fmt.Errorf("expected the database's URL or 32 character ID")
+			}
+			return nil
+		},
+	}
+	database, err := databasePrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Notion.Database = notionDatabaseID(database)
+
+	properties := []struct {
+		label   string
+		value   *string
+		initial string
+	}{
+		{"Status property (a status or select)", &cfg.Notion.StatusProperty, "Status"},
+		{"Assignee property (a person)", &cfg.Notion.AssigneeProperty, "Assignee"},
+		{"Unique ID property, such as TASK-12 (optional)", &cfg.Notion.IDProperty, ""},
+	}
+	for _, property := range properties {
+		prompt := promptui.Prompt{Label: property.label, Default: property.initial}
+		value, err := prompt.Run()
+		if err != nil {
+			return err
+		}
+		if value != property.initial {
+			*property.value = strings.TrimSpace(value)
+		}
+	}
+
+	notion, err := systems.NewNotion(cfg)
+	if err != nil {
+		return err
+	}
+	users, err := notion.Users(context.Background())
+	if err != nil {
+		fmt.Printf("Warning: Could not fetch the people in your Notion workspace: %v\n", err)
+		fmt.Println("Set notion.user to your Notion user ID to list your pages.")
+		return nil
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.Name
+	}
+	userPrompt := promptui.Select{
+		Label: "Which person are you?",
+		Items: names,
+	}
+	index, _, err := userPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Notion.User = users[index].ID
+	return nil
+}
+
+// notionDatabaseID returns the ID of a database given as its URL or ID, or
+// "" if it is neither
+func notionDatabaseID(input string) string {
+	input = strings.TrimSpace(input)
+	if before, _, ok := strings.Cut(input, "?"); ok {
+		input = before
+	}
+	input = strings.ReplaceAll(input, "-", "")
+	if len(input) < 32 {
+		return ""
+	}
+	id := input[len(input)-32:]
+	if strings.Trim(strings.ToLower(id), "0123456789abcdef") != "" {
+		return ""
+	}
+	return strings.ToLower(id)
+}
+
+// initRedmine asks for the Redmine instance, the project of new issues,
+// and a REST API key
+func initRedmine(cfg *config.Config) error {
+	urlPrompt := promptui.Prompt{
+		Label: "Redmine URL (e.g., https://redmine.your-company.com)",
+		Validate: func(input string) error {
+			return security.ValidateURL(input)
+		},
+	}
+	redmineURL, err := urlPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Redmine.URL = strings.TrimSuffix(redmineURL, "/")
+
+	projectPrompt := promptui.Prompt{
+		Label: "Identifier of the project for new issues (optional)",
+	}
+	project, err := projectPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.Redmine.Project = strings.TrimSpace(project)
+
+	fmt.Println("\nTo use Redmine, you need your REST API key.")
+	fmt.Println("1. Open My account in Redmine")
+	fmt.Println("2. Click Show under API access key in the sidebar")
+	fmt.Println("3. Copy the key and paste it below")
+	fmt.Println("If there is no API access key, ask your administrator to enable the REST web service.")
+
+	tokenPrompt := promptui.Prompt{
+		Label: "Redmine API Key",
+		Mask:  '•',
+		Validate: func(input string) error {
+			return security.ValidateAPIKey(input)
+		},
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	cfg.RedmineToken = token
+	return nil
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import "testing"
+
+func TestNotionDatabaseID(t *testing.T) {
+	tests := map[string]string{
+		"0123456789abcdef0123456789ABCDEF":                                          "0123456789abcdef0123456789abcdef",
+		"https://www.notion.so/acme/0123456789abcdef0123456789abcdef?v=fedcba98765": "0123456789abcdef0123456789abcdef",
+		"01234567-89ab-cdef-0123-456789abcdef":                                      "0123456789abcdef0123456789abcdef",
+		"https://www.notion.so/acme/Tasks":                                          "",
+		"not-an-id-but-long-enough-to-be-checked-xyz":                               "",
+	}
+	for input, want := range tests {
+		if got := notionDatabaseID(input); got != want {
+			t.Errorf("notionDatabaseID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,521 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// invoiceFormats are the formats an invoice can be rendered in
+var invoiceFormats = []string{"markdown", "html", "pdf"}
+
+// invoiceCmd represents the invoice command
+var invoiceCmd = &cobra.Command{
+	Use:   "invoice [output]",
+	Short: "Invoice billable work",
+	Long: `Render an itemized invoice of your completed billable work, with a line
+for each piece of work, the subtotal, a tax line and the total.
+
+Each line is charged at the hourly rate of the work: the rate of its first
+tag with one, else of its project, else the default rate from the rates
+section of your configuration, or --rate. Who the invoice is from and to,
+the tax, payment terms and notes come from the invoice section, and --to,
+--tax and --number override them.
+
+The invoice is written in markdown, html or pdf, as --format says or the
+extension of the output file suggests, to the output file or the terminal:
+
+  plannet invoice --since 2024-05-01 --until 2024-05-31 --rate 120
+  plannet invoice --since 2024-05-01 --tag client-a --tax 20 invoice.pdf`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		format, _ := cmd.Flags().GetString("format")
+		options := invoiceOptions{}
+		options.Rate, _ = cmd.Flags().GetFloat64("rate")
+		options.To, _ = cmd.Flags().GetString("to")
+		options.Number, _ = cmd.Flags().GetString("number")
+		if cmd.Flags().Changed("tax") {
+			tax, _ := cmd.Flags().GetFloat64("tax")
+			options.TaxPercent = &tax
+		}
+		filter, err := workFilterFromFlags(cmd)
+		if err != nil {
+			printError("Error:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		outputPath := ""
+		if len(args) > 0 {
+			outputPath = args[0]
+		}
+		runInvoice(outputPath, format, all, options, filter)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(invoiceCmd)
+	supportJSONOutput(invoiceCmd)
+	invoiceCmd.Flags().String("format", "", "Render the invoice as markdown, html or pdf (default from the output file, else markdown)")
+	invoiceCmd.Flags().Float64("rate", 0, "The hourly rate of work without a tag or project rate")
+	invoiceCmd.Flags().String("to", "", "Who the invoice is to")
+	invoiceCmd.Flags().Float64("tax", 0, "Add a tax line of this percentage of the subtotal")
+	invoiceCmd.Flags().String("number", "", "The invoice number (default INV- and today's date)")
+	invoiceCmd.Flags().BoolP("all", "a", false, "Invoice work from every project")
+	invoiceCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return invoiceFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+	addWorkFilterFlags(invoiceCmd, "")
+}
+
+// invoiceOptions override the invoice settings of the configuration
+type invoiceOptions struct {
+	Rate       float64  // Default hourly rate (0 for the configured one)
+	To         string   // Empty for the configured one
+	Number     string   // Empty for one from the invoice date
+	TaxPercent *float64 // Nil for the configured one
+}
+
+// invoice is an itemized invoice of billable work
+type invoice struct {
+	Number      string        `json:"number"`
+	Date        time.Time     `json:"date"`
+	Due         *time.Time    `json:"due,omitempty"`
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	From        string        `json:"from,omitempty"`
+	To          string        `json:"to,omitempty"`
+	Currency    string        `json:"currency,omitempty"`
+	Items       []invoiceItem `json:"items"`
+	Hours       float64       `json:"hours"`
+	Subtotal    float64       `json:"subtotal"`
+	TaxLabel    string        `json:"tax_label,omitempty"`
+	TaxPercent  float64       `json:"tax_percent,omitempty"`
+	Tax         float64       `json:"tax,omitempty"`
+	Total       float64       `json:"total"`
+	Notes       string        `json:"notes,omitempty"`
+}
+
+// invoiceItem is a line of an invoice: a piece of billable work
+type invoiceItem struct {
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Hours       float64   `json:"hours"`
+	Rate        float64   `json:"rate"`
+	Amount      float64   `json:"amount"`
+}
+
+func runInvoice(outputPath, format string, all bool, options invoiceOptions, filter workFilter) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if format == "" {
+		format = invoiceFormatFor(outputPath)
+	}
+	if !containsString(invoiceFormats, format) {
+		printErrorf("Error: invalid format %q, expected one of %s\n", format, strings.Join(invoiceFormats, ", "))
+		setExitCode(exitUsage)
+		return
+	}
+	if format == "pdf" && outputPath == "" && !jsonOutput() {
+		printError("Error: a PDF invoice needs an output file, such as 'plannet invoice invoice.pdf'")
+		setExitCode(exitUsage)
+		return
+	}
+
+	var trackedWork []TrackedWork
+	if all {
+		trackedWork, err = getAllTrackedWork()
+	} else {
+		trackedWork, err = getTrackedWork()
+	}
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	rates := cfg.Rates
+	if options.Rate > 0 {
+		rates.Default = options.Rate
+	}
+	settings := cfg.Invoice
+	if options.To != "" {
+		settings.To = options.To
+	}
+	if options.TaxPercent != nil {
+		settings.TaxPercent = *options.TaxPercent
+	}
+	inv := buildInvoice(filter.apply(trackedWork), currentProjectName(), rates, settings, filter, time.Now())
+	if options.Number != "" {
+		inv.Number = options.Number
+	}
+
+	if jsonOutput() {
+		printJSON(inv)
+		return
+	}
+	if len(inv.Items) == 0 {
+		fmt.Printf("No completed billable work found%s.\n", describeReportRange(filter))
+		printInfo("Mark work as billable with 'plannet track --billable'.")
+		setExitCode(exitNotFound)
+		return
+	}
+	if err := writeInvoiceFile(outputPath, format, inv); err != nil {
+		printError("Error writing invoice:", err)
+		return
+	}
+	// Messages would end up in an invoice written to the terminal
+	if outputPath != "" {
+		fmt.Printf("Invoice %s for %s written to %s\n", inv.Number, formatAmount(inv.Total, rates), outputPath)
+		if unpriced := countUnpriced(inv.Items); unpriced > 0 {
+			printInfo(fmt.Sprintf("%d item(s) have no rate. Set rates in your configuration, or pass --rate.", unpriced))
+		}
+	}
+}
+
+// invoiceFormatFor returns the format the extension of an output file
+// suggests, markdown unless it is an HTML or PDF file
+func invoiceFormatFor(outputPath string) string {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".html", ".htm":
+		return "html"
+	case ".pdf":
+		return "pdf"
+	}
+	return "markdown"
+}
+
+// buildInvoice itemizes the completed billable work, oldest first, at the
+// rates of its tags or projects. Each line's hours are rounded to hundredths
+// and its amount to cents, so that the lines add up as they read. Work
+// without a project of its own is taken to belong to defaultProject.
+func buildInvoice(trackedWork []TrackedWork, defaultProject string, rates config.Rates, settings config.InvoiceSettings, filter workFilter, now time.Time) invoice {
+	inv := invoice{
+		Number:     "INV-" + now.Format("20060102"),
+		Date:       startOfDay(now),
+		From:       settings.From,
+		To:         settings.To,
+		Currency:   rates.Currency,
+		Items:      []invoiceItem{},
+		TaxPercent: settings.TaxPercent,
+		Notes:      settings.Notes,
+	}
+	if settings.DueDays > 0 {
+		due := inv.Date.AddDate(0, 0, settings.DueDays)
+		inv.Due = &due
+	}
+
+	sorted := append([]TrackedWork(nil), trackedWork...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+	for _, work := range sorted {
+		if !work.Billable || work.Status != "completed" {
+			continue
+		}
+		hours := roundCents(work.Duration().Hours())
+		rate := hourlyRate(work, workProject(work, defaultProject), rates)
+		item := invoiceItem{
+			Date:        startOfDay(work.StartTime),
+			Description: timeEntryDescription(work),
+			Hours:       hours,
+			Rate:        rate,
+			Amount:      roundCents(hours * rate),
+		}
+		inv.Items = append(inv.Items, item)
+		inv.Hours += item.Hours
+		inv.Subtotal += item.Amount
+	}
+	inv.Hours = roundCents(inv.Hours)
+	inv.Subtotal = roundCents(inv.Subtotal)
+
+	if inv.TaxPercent > 0 {
+		inv.TaxLabel = settings.TaxLabel
+		if inv.TaxLabel == "" {
+			inv.TaxLabel = "Tax"
+		}
+		inv.Tax = roundCents(inv.Subtotal * inv.TaxPercent / 100)
+	}
+	inv.Total = roundCents(inv.Subtotal + inv.Tax)
+
+	// The period is the range of the filter, or else of the work invoiced
+	inv.PeriodStart, inv.PeriodEnd = filter.From, filter.To
+	if !inv.PeriodEnd.IsZero() {
+		// The end of the filter is exclusive, such as midnight after the last day
+		inv.PeriodEnd = inv.PeriodEnd.Add(-time.Nanosecond)
+	}
+	if len(inv.Items) > 0 {
+		if inv.PeriodStart.IsZero() {
+			inv.PeriodStart = inv.Items[0].Date
+		}
+		if inv.PeriodEnd.IsZero() {
+			inv.PeriodEnd = inv.Items[len(inv.Items)-1].Date
+		}
+	}
+	return inv
+}
+
+// roundCents rounds an amount, or a number of hours, to hundredths
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// countUnpriced returns how many items of an invoice have no rate
+func countUnpriced(items []invoiceItem) int {
+	count := 0
+	for _, item := range items {
+		if item.Rate == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// writeInvoiceFile writes an invoice in a format to a file, or to stdout
+// when outputPath is empty
+func writeInvoiceFile(outputPath, format string, inv invoice) error {
+	w := io.Writer(os.Stdout)
+	if outputPath != "" {
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case "html":
+		return writeInvoiceHTML(w, inv)
+	case "pdf":
+		return writeInvoicePDF(w, inv)
+	}
+	return writeInvoiceMarkdown(w, inv)
+}
+
+// Money formats an amount of the invoice with its currency
+func (inv invoice) Money(amount float64) string {
+	return formatAmount(amount, config.Rates{Currency: inv.Currency})
+}
+
+// TaxLine names the tax line, with its percentage
+func (inv invoice) TaxLine() string {
+	return fmt.Sprintf("%s (%s%%)", inv.TaxLabel, formatPercent(inv.TaxPercent))
+}
+
+// formatPercent formats a percentage without needless decimals, such as 20
+// or 8.5
+func formatPercent(percent float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", percent), "0"), ".")
+}
+
+// writeInvoiceMarkdown writes an invoice as markdown
+func writeInvoiceMarkdown(w io.Writer, inv invoice) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Invoice %s\n\n", inv.Number)
+	fmt.Fprintf(&b, "**Date:** %s  \n", inv.Date.Format(dateInputLayout))
+	if inv.Due != nil {
+		fmt.Fprintf(&b, "**Due:** %s  \n", inv.Due.Format(dateInputLayout))
+	}
+	fmt.Fprintf(&b, "**Period:** %s – %s\n\n", inv.PeriodStart.Format(dateInputLayout), inv.PeriodEnd.Format(dateInputLayout))
+	if inv.From != "" {
+		fmt.Fprintf(&b, "**From:**  \n%s\n\n", markdownLines(inv.From))
+	}
+	if inv.To != "" {
+		fmt.Fprintf(&b, "**To:**  \n%s\n\n", markdownLines(inv.To))
+	}
+
+	b.WriteString("| Date | Description | Hours | Rate | Amount |\n")
+	b.WriteString("|------|-------------|------:|-----:|-------:|\n")
+	for _, item := range inv.Items {
+		fmt.Fprintf(&b, "| %s | %s | %.2f | %s | %s |\n", item.Date.Format(dateInputLayout), markdownCell(item.Description),
+			item.Hours, inv.Money(item.Rate), inv.Money(item.Amount))
+	}
+	fmt.Fprintf(&b, "| | **Subtotal** | **%.2f** | | **%s** |\n", inv.Hours, inv.Money(inv.Subtotal))
+	if inv.TaxPercent > 0 {
+		fmt.Fprintf(&b, "| | %s | | | %s |\n", markdownCell(inv.TaxLine()), inv.Money(inv.Tax))
+	}
+	fmt.Fprintf(&b, "| | **Total** | | | **%s** |\n", inv.Money(inv.Total))
+
+	if inv.Notes != "" {
+		fmt.Fprintf(&b, "\n%s\n", inv.Notes)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownLines keeps the lines of text, such as an address, apart in markdown
+func markdownLines(text string) string {
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "  \n")
+}
+
+// markdownCell escapes text for a cell of a markdown table
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+}
+
+// writeInvoiceHTML writes an invoice as a self-contained HTML page
+func writeInvoiceHTML(w io.Writer, inv invoice) error {
+	return invoiceHTMLTemplate.Execute(w, inv)
+}
+
+// invoiceHTMLTemplate is the HTML invoice. Like the HTML report, its styles
+// are inline, so the page can be sent or printed as a single file.
+var invoiceHTMLTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"lines": func(text string) []string { return strings.Split(strings.TrimSpace(text), "\n") },
+	"date":  func(t time.Time) string { return t.Format(dateInputLayout) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Invoice {{.Number}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 800px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+h1 { margin-bottom: .5rem; }
+.meta { color: #656d76; }
+.parties { display: flex; gap: 4rem; margin: 2rem 0; }
+.parties h2 { font-size: .85rem; color: #656d76; text-transform: uppercase; margin: 0 0 .25rem; }
+table { border-collapse: collapse; width: 100%; }
+td, th { text-align: left; padding: .35rem .5rem; vertical-align: top; border-bottom: 1px solid #d0d7de; }
+th { font-size: .85rem; color: #656d76; font-weight: 600; }
+.num { text-align: right; white-space: nowrap; }
+tr.subtotal td { border-top: 2px solid #1f2328; }
+tr.total td { font-weight: 700; border-bottom: none; }
+.notes { margin-top: 2rem; white-space: pre-line; }
+</style>
+</head>
+<body>
+<h1>Invoice {{.Number}}</h1>
+<div class="meta">Date: {{date .Date}}{{with .Due}} · Due: {{date .}}{{end}} · Period: {{date .PeriodStart}} – {{date .PeriodEnd}}</div>
+{{if or .From .To}}<div class="parties">
+{{if .From}}<div><h2>From</h2>{{range lines .From}}{{.}}<br>{{end}}</div>{{end}}
+{{if .To}}<div><h2>To</h2>{{range lines .To}}{{.}}<br>{{end}}</div>{{end}}
+</div>{{end}}
+<table>
+<tr><th>Date</th><th>Description</th><th class="num">Hours</th><th class="num">Rate</th><th class="num">Amount</th></tr>
+{{range .Items}}<tr>
+<td class="num">{{date .Date}}</td>
+<td>{{.Description}}</td>
+<td class="num">{{printf "%.2f" .Hours}}</td>
+<td class="num">{{$.Money .Rate}}</td>
+<td class="num">{{$.Money .Amount}}</td>
+</tr>
+{{end}}<tr class="subtotal"><td></td><td>Subtotal</td><td class="num">{{printf "%.2f" .Hours}}</td><td></td><td class="num">{{.Money .Subtotal}}</td></tr>
+{{if .TaxPercent}}<tr><td></td><td>{{.TaxLine}}</td><td></td><td></td><td class="num">{{.Money .Tax}}</td></tr>
+{{end}}<tr class="total"><td></td><td>Total</td><td></td><td></td><td class="num">{{.Money .Total}}</td></tr>
+</table>
+{{if .Notes}}<div class="notes">{{.Notes}}</div>{{end}}
+</body>
+</html>
+`))
+
+// writeInvoicePDF writes an invoice as an A4 PDF
+func writeInvoicePDF(w io.Writer, inv invoice) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AddPage()
+	// The core fonts are encoded in cp1252, which covers the euro and the dash
+	text := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFont("Helvetica", "B", 20)
+	pdf.CellFormat(0, 10, text("Invoice "+inv.Number), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(101, 109, 118)
+	meta := "Date: " + inv.Date.Format(dateInputLayout)
+	if inv.Due != nil {
+		meta += "   Due: " + inv.Due.Format(dateInputLayout)
+	}
+	meta += "   Period: " + inv.PeriodStart.Format(dateInputLayout) + " – " + inv.PeriodEnd.Format(dateInputLayout)
+	pdf.CellFormat(0, 6, text(meta), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(31, 35, 40)
+	pdf.Ln(6)
+
+	if inv.From != "" || inv.To != "" {
+		top := pdf.GetY()
+		writePDFParty(pdf, text, 20, top, "From", inv.From)
+		fromBottom := pdf.GetY()
+		writePDFParty(pdf, text, 110, top, "To", inv.To)
+		pdf.SetY(math.Max(fromBottom, pdf.GetY()) + 6)
+	}
+
+	widths := []float64{25, 80, 20, 22, 23}
+	header := []string{"Date", "Description", "Hours", "Rate", "Amount"}
+	aligns := []string{"L", "L", "R", "R", "R"}
+	pdf.SetFont("Helvetica", "B", 9)
+	for i, title := range header {
+		pdf.CellFormat(widths[i], 7, title, "B", 0, aligns[i], false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, item := range inv.Items {
+		description := pdf.SplitText(text(item.Description), widths[1]-2)
+		height := 6 * float64(len(description))
+		if pdf.GetY()+height > 277 {
+			pdf.AddPage()
+		}
+		x, y := pdf.GetX(), pdf.GetY()
+		pdf.CellFormat(widths[0], 6, item.Date.Format(dateInputLayout), "", 0, "L", false, 0, "")
+		pdf.MultiCell(widths[1], 6, strings.Join(description, "\n"), "", "L", false)
+		pdf.SetXY(x+widths[0]+widths[1], y)
+		pdf.CellFormat(widths[2], 6, fmt.Sprintf("%.2f", item.Hours), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, text(inv.Money(item.Rate)), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 6, text(inv.Money(item.Amount)), "", 0, "R", false, 0, "")
+		pdf.SetXY(x, y+height)
+	}
+
+	total := func(label, hours, amount string, bold bool, border string) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.CellFormat(widths[0], 7, "", border, 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, text(label), border, 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, hours, border, 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, "", border, 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 7, text(amount), border, 1, "R", false, 0, "")
+	}
+	total("Subtotal", fmt.Sprintf("%.2f", inv.Hours), inv.Money(inv.Subtotal), false, "T")
+	if inv.TaxPercent > 0 {
+		total(inv.TaxLine(), "", inv.Money(inv.Tax), false, "")
+	}
+	total("Total", "", inv.Money(inv.Total), true, "")
+
+	if inv.Notes != "" {
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 5, text(inv.Notes), "", "L", false)
+	}
+	return pdf.Output(w)
+}
+
+// writePDFParty writes who an invoice is from or to, as a heading over its
+// lines, at a position of the page
+func writePDFParty(pdf *fpdf.Fpdf, text func(string) string, x, y float64, heading, lines string) {
+	if lines == "" {
+		return
+	}
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetTextColor(101, 109, 118)
+	pdf.CellFormat(80, 5, strings.ToUpper(heading), "", 2, "L", false, 0, "")
+	pdf.SetTextColor(31, 35, 40)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(80, 5, text(strings.TrimSpace(lines)), "", "L", false)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// invoiceTestWork returns billable and other work over two days
+func invoiceTestWork() []TrackedWork {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	return []TrackedWork{
+		{ID: "w2", Description: "Workshop | planning", Tags: []string{"workshop"}, StartTime: start.AddDate(0, 0, 1),
+			EndTime: start.AddDate(0, 0, 1).Add(2 * time.Hour), Status: "completed", Billable: true},
+		{ID: "w1", Description: "Fix login", TicketIDs: []string{"PROJ-1"}, StartTime: start,
+			EndTime: start.Add(90*time.Minute + 20*time.Second), Status: "completed", Billable: true},
+		{ID: "internal", Description: "Team meeting", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "active", Description: "Still going", StartTime: start, Status: "active", Billable: true},
+	}
+}
+
+func TestBuildInvoice(t *testing.T) {
+	rates := config.Rates{Currency: "EUR", Default: 100, Tags: map[string]float64{"workshop": 150}}
+	settings := config.InvoiceSettings{From: "Sam Lee\nMain St 1", To: "ACME", TaxPercent: 20, TaxLabel: "VAT", DueDays: 30}
+	now := time.Date(2024, 5, 31, 17, 0, 0, 0, time.Local)
+
+	inv := buildInvoice(invoiceTestWork(), "", rates, settings, workFilter{}, now)
+	if inv.Number != "INV-20240531" || !inv.Date.Equal(startOfDay(now)) || inv.Due == nil || inv.Due.Format(dateInputLayout) != "2024-06-30" {
+		t.Errorf("Unexpected number and dates %q %v %v", inv.Number, inv.Date, inv.Due)
+	}
+	if len(inv.Items) != 2 {
+		t.Fatalf("Expected the two completed billable pieces of work, got %+v", inv.Items)
+	}
+	// Oldest first, with hours rounded before they are charged
+	first, second := inv.Items[0], inv.Items[1]
+	if first.Description != "PROJ-1 Fix login" || first.Hours != 1.51 || first.Rate != 100 || first.Amount != 151 {
+		t.Errorf("Unexpected first item %+v", first)
+	}
+	if second.Description != "Workshop | planning" || second.Hours != 2 || second.Rate != 150 || second.Amount != 300 {
+		t.Errorf("Unexpected second item %+v", second)
+	}
+	if inv.Hours != 3.51 || inv.Subtotal != 451 || inv.Tax != 90.2 || inv.Total != 541.2 || inv.TaxLabel != "VAT" {
+		t.Errorf("Unexpected totals %+v", inv)
+	}
+	if inv.PeriodStart.Format(dateInputLayout) != "2024-05-06" || inv.PeriodEnd.Format(dateInputLayout) != "2024-05-07" {
+		t.Errorf("Expected the period of the work, got %v – %v", inv.PeriodStart, inv.PeriodEnd)
+	}
+
+	filter := workFilter{From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), To: time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)}
+	inv = buildInvoice(invoiceTestWork(), "", rates, config.InvoiceSettings{}, filter, now)
+	if inv.PeriodStart.Format(dateInputLayout) != "2024-05-01" || inv.PeriodEnd.Format(dateInputLayout) != "2024-05-31" {
+		t.Errorf("Expected the period of the filter, got %v – %v", inv.PeriodStart, inv.PeriodEnd)
+	}
+	if inv.Tax != 0 || inv.Total != inv.Subtotal || inv.Due != nil {
+		t.Errorf("Expected no tax or due date, got %+v", inv)
+	}
+}
+
+func TestWriteInvoiceMarkdown(t *testing.T) {
+	rates := config.Rates{Currency: "EUR", Default: 100, Tags: map[string]float64{"workshop": 150}}
+	settings := config.InvoiceSettings{From: "Sam Lee\nMain St 1", TaxPercent: 8.5, Notes: "Pay within 30 days."}
+	inv := buildInvoice(invoiceTestWork(), "", rates, settings, workFilter{}, time.Date(2024, 5, 31, 17, 0, 0, 0, time.Local))
+
+	var buf bytes.Buffer
+	if err := writeInvoiceMarkdown(&buf, inv); err != nil {
+		t.Fatalf("Failed to write invoice: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"# Invoice INV-20240531\n",
+		"**Period:** 2024-05-06 – 2024-05-07\n",
+		"**From:**  \nSam Lee  \nMain St 1\n",
+		"| 2024-05-06 | PROJ-1 Fix login | 1.51 | 100.00 EUR | 151.00 EUR |\n",
+		`| 2024-05-07 | Workshop \| planning | 2.00 | 150.00 EUR | 300.00 EUR |` + "\n",
+		"| | **Subtotal** | **3.51** | | **451.00 EUR** |\n",
+		"| | Tax (8.5%) | | | 38.34 EUR |\n",
+		"| | **Total** | | | **489.34 EUR** |\n",
+		"\nPay within 30 days.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected invoice to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "**To:**") || strings.Contains(got, "**Due:**") {
+		t.Errorf("Expected no recipient or due date, got:\n%s", got)
+	}
+}
+
+func TestWriteInvoiceHTMLAndPDF(t *testing.T) {
+	rates := config.Rates{Currency: "€", Default: 100}
+	settings := config.InvoiceSettings{To: "ACME <Ltd>\nHigh St 2", TaxPercent: 20}
+	inv := buildInvoice(invoiceTestWork(), "", rates, settings, workFilter{}, time.Date(2024, 5, 31, 17, 0, 0, 0, time.Local))
+
+	var html bytes.Buffer
+	if err := writeInvoiceHTML(&html, inv); err != nil {
+		t.Fatalf("Failed to write HTML invoice: %v", err)
+	}
+	for _, want := range []string{"<title>Invoice INV-20240531</title>", "ACME &lt;Ltd&gt;<br>High St 2<br>", "<td>Tax (20%)</td>", "421.20 €"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("Expected HTML invoice to contain %q", want)
+		}
+	}
+
+	var pdf bytes.Buffer
+	if err := writeInvoicePDF(&pdf, inv); err != nil {
+		t.Fatalf("Failed to write PDF invoice: %v", err)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")) || !bytes.Contains(pdf.Bytes(), []byte("%%EOF")) {
+		t.Errorf("Expected a PDF document, got %d bytes", pdf.Len())
+	}
+}
+
+func TestInvoiceFormatFor(t *testing.T) {
+	tests := map[string]string{"": "markdown", "invoice.md": "markdown", "invoice.HTML": "html", "out/invoice.pdf": "pdf"}
+	for path, want := range tests {
+		if got := invoiceFormatFor(path); got != want {
+			t.Errorf("invoiceFormatFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRunInvoiceJSON(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	useJSONOutput(t)
+
+	for _, work := range invoiceTestWork()[:3] {
+		if err := saveTrackedWork(work); err != nil {
+			t.Fatalf("Failed to save work: %v", err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		runInvoice("", "", false, invoiceOptions{Rate: 80, Number: "2024-017"}, workFilter{})
+	})
+	var inv invoice
+	if err := json.Unmarshal([]byte(out), &inv); err != nil {
+		t.Fatalf("Failed to parse JSON output %q: %v", out, err)
+	}
+	if inv.Number != "2024-017" || len(inv.Items) != 2 || inv.Items[0].Rate != 80 || inv.Subtotal != 280.8 {
+		t.Errorf("Unexpected invoice %+v", inv)
+	}
+}
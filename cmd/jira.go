@@ -1,19 +1,16 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"regexp"
-	"time"
+	"sort"
+	"strings"
 
 	"github.com/manifoldco/promptui"
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
 	"github.com/plannet-ai/plannet/logger"
-	"github.com/plannet-ai/plannet/security"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +25,21 @@ type JiraTicket struct {
 	Priority    string `json:"priority"`
 	Assignee    string `json:"assignee"`
 	URL         string `json:"url"`
+
+	Attachments []JiraAttachment `json:"attachments,omitempty"`
+	Links       []JiraLink       `json:"links,omitempty"`
+
+	Parent       *JiraParent      `json:"parent,omitempty"`
+	CustomFields []JiraFieldValue `json:"custom_fields,omitempty"`
+}
+
+// JiraAttachment is a file attached to a Jira ticket
+type JiraAttachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	URL      string `json:"content"` // Where the file is downloaded from
 }
 
 // jiraCmd represents the jira command
@@ -36,7 +48,11 @@ var jiraCmd = &cobra.Command{
 	Short: "Interact with Jira",
 	Long: `Interact with Jira to view and manage tickets.
 This command allows you to list your assigned tickets, view ticket details,
-and create new tickets.`,
+and create new tickets.
+
+When your configuration selects another ticket system, list, view, create
+and comment work with it instead, like 'plannet ticket'. Searches, epics and
+attachments work with the systems that have them.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		log := logger.WithContext(cmd.Context())
 		log.Info("Use one of the subcommands: list, view, create")
@@ -44,13 +60,36 @@ and create new tickets.`,
 	},
 }
 
+// defaultJiraListJQL is the search 'plannet jira list' runs by default
+const defaultJiraListJQL = "assignee = currentUser() ORDER BY updated DESC"
+
 // jiraListCmd represents the jira list command
 var jiraListCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [query]",
 	Short: "List your Jira tickets",
-	Long:  `List all Jira tickets assigned to you.`,
+	Long: `List the open tickets assigned to you.
+Use --jql to search with your own JQL instead, or give the name of a query
+saved under jira_queries in your configuration, such as
+'plannet jira list review'. Use --epic to list the children of an epic.
+
+In a terminal, the tickets are shown in a searchable picker; pick one to
+start tracking work on it or view its details. Use --plain to print them in
+a table of their key, status and summary instead, or choose its columns with
+--columns, from key, status, summary, type, priority, assignee and url.
+Outside a terminal, the rows are printed with their columns separated by tabs.
+Use --format to print each ticket with a Go template, such as
+'{{.Key}} {{.Summary}}'.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		runJiraList(cmd.Context())
+		jql, _ := cmd.Flags().GetString("jql")
+		epic, _ := cmd.Flags().GetString("epic")
+		plain, _ := cmd.Flags().GetBool("plain")
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
+		runJiraList(cmd.Context(), query, jql, epic, plain || !canPickInteractively(), columns)
 	},
 }
 
@@ -58,10 +97,15 @@ var jiraListCmd = &cobra.Command{
 var jiraViewCmd = &cobra.Command{
 	Use:   "view [ticket]",
 	Short: "View a Jira ticket",
-	Long:  `View details of a specific Jira ticket.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `View details of a specific Jira ticket, including its attachments.
+Use --download to save the attachments, or only those named with --attachment,
+into the current directory or the one given with --dir.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		runJiraView(cmd.Context(), args[0])
+		download, _ := cmd.Flags().GetBool("download")
+		names, _ := cmd.Flags().GetStringSlice("attachment")
+		dir, _ := cmd.Flags().GetString("dir")
+		runTicketView(cmd.Context(), args[0], download, names, dir)
 	},
 }
 
@@ -69,9 +113,20 @@ var jiraViewCmd = &cobra.Command{
 var jiraCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new Jira ticket",
-	Long:  `Create a new Jira ticket with the specified details.`,
+	Long: `Create a new ticket with the specified details. For Jira, the project,
+issue type, configured custom fields and parent are asked for too.
+
+Use --template to start from a YAML or JSON file, or a template saved in
+~/.plannet/jira-templates, that sets any of the project, type, summary,
+description, labels, components, priority, parent and other fields. Only
+what the template leaves out is asked for. {{placeholders}} in the summary
+and description are asked for too, unless given with --set name=value.
+Without an interactive terminal, nothing is asked for: the template must set
+the summary and project, and --set must give every placeholder.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runJiraCreate(cmd.Context())
+		templateName, _ := cmd.Flags().GetString("template")
+		set, _ := cmd.Flags().GetStringArray("set")
+		runJiraCreate(cmd.Context(), templateName, set)
 	},
 }
 
@@ -80,318 +135,337 @@ func init() {
 	jiraCmd.AddCommand(jiraListCmd)
 	jiraCmd.AddCommand(jiraViewCmd)
 	jiraCmd.AddCommand(jiraCreateCmd)
+	supportJSONOutput(jiraListCmd)
+	supportJSONOutput(jiraViewCmd)
+	jiraListCmd.Flags().String("jql", "", "Search with this JQL instead of listing your tickets")
+	jiraListCmd.Flags().String("epic", "", "List the children of this epic")
+	jiraListCmd.Flags().Bool("plain", false, "Print the tickets instead of picking one")
+	jiraListCmd.Flags().StringSlice("columns", nil, "Columns of the printed table, such as key,status,summary")
+	jiraListCmd.RegisterFlagCompletionFunc("columns", completeTicketColumns)
+	addFormatFlag(jiraListCmd, "Print each ticket with a Go template, such as '{{.Key}} {{.Summary}}'")
+	jiraViewCmd.Flags().Bool("download", false, "Download the ticket's attachments")
+	jiraViewCmd.Flags().StringSlice("attachment", nil, "Only download the attachment with this file name (can be repeated)")
+	jiraViewCmd.Flags().String("dir", ".", "Directory to download attachments into")
+	jiraCreateCmd.Flags().String("template", "", "Template file or name to create the ticket from")
+	jiraCreateCmd.Flags().StringArray("set", nil, "Value of a template placeholder, as name=value (can be repeated)")
 }
 
-// runJiraList lists all Jira tickets assigned to you
-func runJiraList(ctx context.Context) {
+// runJiraList lists the tickets matched by a saved query, by JQL, the
+// children of an epic, or by default the open tickets assigned to you, in
+// the configured ticket system. Unless plain, the tickets are shown in an
+// interactive picker; otherwise they are printed in a table with the given
+// columns.
+func runJiraList(ctx context.Context, query, jql, epic string, plain bool, columns []string) {
 	log := logger.WithContext(ctx)
-
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		log.Error("Failed to load configuration: %v", err)
-		log.Info("Run 'plannet init' to set up your configuration.")
+	if query == "" && jql == "" && epic == "" {
+		runTicketList(ctx, plain, columns)
 		return
 	}
-
-	// Check if Jira integration is configured
-	if cfg.JiraURL == "" || cfg.JiraUser == "" {
-		log.Error("Jira integration is not configured")
-		log.Info("Run 'plannet init' to set up Jira integration.")
-		return
-	}
-
-	// Get Jira token from config
-	token := cfg.JiraToken
-	if token == "" {
-		fmt.Println("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
-		return
-	}
-
-	// Create HTTP client with rate limiting
-	rateLimiter := security.NewHTTPRateLimiter(10, time.Minute) // 10 requests per minute
-	client := rateLimiter.WrapHTTPClient(&http.Client{}, "jira")
-
-	// Create request
-	url := cfg.JiraURL + "/rest/api/2/search?jql=assignee=" + cfg.JiraUser + "+ORDER+BY+updated+DESC"
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Error("Failed to create Jira API request: %v", err)
-		return
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Basic "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request
-	resp, err := client.Do(req.WithContext(ctx))
-	if err != nil {
-		log.Error("Failed to send Jira API request: %v", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Error("Jira API returned status %d: %s", resp.StatusCode, string(body))
-		return
-	}
-
-	// Parse response
-	var result struct {
-		Issues []JiraTicket `json:"issues"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Error("Failed to parse Jira API response: %v", err)
+	if _, err := ticketTable(nil, columns); err != nil {
+		log.Error("%v", err)
 		return
 	}
-
-	// Display tickets
-	if len(result.Issues) == 0 {
-		log.Info("No tickets found.")
+	cfg, system, ok := loadTicketSystem(ctx)
+	if !ok {
 		return
 	}
 
-	log.Info("Your Jira tickets:")
-	log.Info("-----------------")
-	for _, ticket := range result.Issues {
-		log.Info("%s: %s (%s)", ticket.Key, ticket.Summary, ticket.Status)
-	}
+	var tickets []systems.Ticket
+	var title string
+	if epic != "" {
+		if query != "" || jql != "" {
+			log.Error("Use --epic on its own, without a saved query or --jql")
+			return
+		}
+		lister, ok := system.(systems.ChildLister)
+		if !ok {
+			log.Error("%s tickets have no children to list with --epic", ticketSystemTitle(system))
+			setExitCode(exitUsage)
+			return
+		}
+		key, err := system.ResolveKey(epic)
+		if err != nil {
+			log.Error("Invalid epic key: %v", err)
+			setExitCode(exitUsage)
+			return
+		}
+		if tickets, err = lister.Children(ctx, key); err != nil {
+			log.Error("Failed to list the children of %s: %v", key, err)
+			return
+		}
+		title = fmt.Sprintf("Children of %s", key)
+	} else {
+		searcher, ok := system.(systems.Searcher)
+		if !ok {
+			log.Error("%s can't be searched with saved queries or --jql", ticketSystemTitle(system))
+			setExitCode(exitUsage)
+			return
+		}
+		jql, err := resolveJiraListJQL(cfg, query, jql)
+		if err != nil {
+			log.Error("%v", err)
+			return
+		}
+		if tickets, err = searcher.Search(ctx, jql); err != nil {
+			log.Error("Failed to search tickets: %v", err)
+			return
+		}
+		title = fmt.Sprintf("%s tickets matching %s", ticketSystemTitle(system), jql)
+	}
+	showTickets(ctx, title, tickets, plain, columns)
 }
 
-// runJiraView views a specific Jira ticket
-func runJiraView(ctx context.Context, ticketKey string) {
-	log := logger.WithContext(ctx)
-
-	// Validate ticket key
-	if err := security.ValidateTicketKey(ticketKey); err != nil {
-		log.Error("Invalid ticket key: %v", err)
-		return
+// resolveJiraListJQL returns the JQL of a saved query, the given JQL, or the
+// default search for tickets assigned to you
+func resolveJiraListJQL(cfg *config.Config, query, jql string) (string, error) {
+	if query != "" && jql != "" {
+		return "", fmt.Errorf("use either a saved query or --jql, not both")
 	}
-
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		log.Error("Failed to load configuration: %v", err)
-		log.Info("Run 'plannet init' to set up your configuration.")
-		return
+	if jql != "" {
+		return jql, nil
 	}
-
-	// Check if Jira integration is configured
-	if cfg.JiraURL == "" || cfg.JiraUser == "" {
-		log.Error("Jira integration is not configured")
-		log.Info("Run 'plannet init' to set up Jira integration.")
-		return
+	if query == "" {
+		return defaultJiraListJQL, nil
 	}
 
-	// Get Jira token from config
-	token := cfg.JiraToken
-	if token == "" {
-		fmt.Println("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
-		return
+	saved, ok := cfg.JiraQueries[query]
+	if !ok {
+		names := make([]string, 0, len(cfg.JiraQueries))
+		for name := range cfg.JiraQueries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "", fmt.Errorf("unknown query %q; add it to jira_queries in your configuration", query)
+		}
+		return "", fmt.Errorf("unknown query %q, expected one of: %s", query, strings.Join(names, ", "))
 	}
+	return saved, nil
+}
 
-	// Create HTTP client with rate limiting
-	rateLimiter := security.NewHTTPRateLimiter(10, time.Minute) // 10 requests per minute
-	client := rateLimiter.WrapHTTPClient(&http.Client{}, "jira")
-
-	// Create request
-	url := cfg.JiraURL + "/rest/api/2/issue/" + ticketKey
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		log.Error("Failed to create Jira API request: %v", err)
-		return
-	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Basic "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request
-	resp, err := client.Do(req.WithContext(ctx))
+// searchJiraTickets returns the tickets matching a JQL query
+func searchJiraTickets(ctx context.Context, cfg *config.Config, token, jql string) ([]JiraTicket, error) {
+	issues, err := newJiraClient(cfg, token).search(ctx, jql)
 	if err != nil {
-		log.Error("Failed to send Jira API request: %v", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Error("Jira API returned status %d: %s", resp.StatusCode, string(body))
-		return
+		return nil, err
 	}
 
-	// Parse response
-	var ticket JiraTicket
-	if err := json.NewDecoder(resp.Body).Decode(&ticket); err != nil {
-		log.Error("Failed to parse Jira API response: %v", err)
-		return
+	tickets := make([]JiraTicket, len(issues))
+	for i, raw := range issues {
+		var issue jiraIssue
+		if err := json.Unmarshal(raw, &issue); err != nil {
+			return nil, fmt.Errorf("failed to parse Jira API response: %w", err)
+		}
+		tickets[i] = issue.ticket(cfg.JiraURL)
 	}
-
-	// Display ticket details
-	log.Info("Ticket: %s", ticket.Key)
-	log.Info("Summary: %s", ticket.Summary)
-	log.Info("Status: %s", ticket.Status)
-	log.Info("Type: %s", ticket.Type)
-	log.Info("Priority: %s", ticket.Priority)
-	log.Info("Assignee: %s", ticket.Assignee)
-	log.Info("URL: %s", ticket.URL)
-	log.Info("\nDescription:")
-	log.Info(ticket.Description)
+	return tickets, nil
 }
 
-// runJiraCreate creates a new Jira ticket
-func runJiraCreate(ctx context.Context) {
+// runJiraCreate creates a ticket in the configured ticket system, from a
+// template if one is given. Without a terminal, the template and --set
+// values must give everything that would otherwise be asked for.
+func runJiraCreate(ctx context.Context, templateName string, set []string) {
 	log := logger.WithContext(ctx)
-
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		log.Error("Failed to load configuration: %v", err)
-		log.Info("Run 'plannet init' to set up your configuration.")
+	_, system, ok := loadTicketSystem(ctx)
+	if !ok {
 		return
 	}
 
-	// Check if Jira integration is configured
-	if cfg.JiraURL == "" || cfg.JiraUser == "" {
-		log.Error("Jira integration is not configured")
-		log.Info("Run 'plannet init' to set up Jira integration.")
-		return
+	// Start from the template, if any, and fill in its placeholders
+	tpl := &jiraTemplate{}
+	if templateName != "" {
+		var err error
+		tpl, err = loadJiraTemplate(templateName)
+		if err != nil {
+			log.Error("%v", err)
+			return
+		}
 	}
-
-	// Get Jira token from config
-	token := cfg.JiraToken
-	if token == "" {
-		fmt.Println("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
-		return
-	}
-
-	// Ask for project key
-	projectPrompt := promptui.Prompt{
-		Label: "Enter project key (e.g., PROJ)",
-		Validate: func(input string) error {
-			if input == "" {
-				return fmt.Errorf("project key cannot be empty")
-			}
-			// Project keys are typically uppercase letters and numbers
-			pattern := regexp.MustCompile(`^[A-Z0-9]+$`)
-			if !pattern.MatchString(input) {
-				return fmt.Errorf("project key must contain only uppercase letters and numbers")
-			}
-			return nil
-		},
-	}
-
-	projectKey, err := projectPrompt.Run()
+	values, err := parsePlaceholderValues(set)
 	if err != nil {
-		log.Error("Error: %v", err)
+		log.Error("%v", err)
+		setExitCode(exitUsage)
 		return
 	}
-
-	// Ask for issue type
-	issueTypePrompt := promptui.Select{
-		Label: "Select issue type",
-		Items: []string{"Task", "Bug", "Story", "Epic"},
-	}
-
-	_, issueType, err := issueTypePrompt.Run()
-	if err != nil {
-		log.Error("Error: %v", err)
-		return
-	}
-
-	// Ask for summary
-	summaryPrompt := promptui.Prompt{
-		Label: "Enter summary",
-		Validate: func(input string) error {
-			if input == "" {
-				return fmt.Errorf("summary cannot be empty")
+	placeholders := templatePlaceholders(tpl.Summary, tpl.Description)
+	interactive := isInteractive()
+	if !interactive {
+		var missing []string
+		for _, name := range placeholders {
+			if _, ok := values[name]; !ok {
+				missing = append(missing, name)
 			}
-			return nil
-		},
-	}
-
-	summary, err := summaryPrompt.Run()
-	if err != nil {
-		log.Error("Error: %v", err)
-		return
-	}
-
-	// Ask for description
-	descriptionPrompt := promptui.Prompt{
-		Label: "Enter description",
-	}
-
-	description, err := descriptionPrompt.Run()
-	if err != nil {
+		}
+		if len(missing) > 0 {
+			log.Error("Give a value for %s with --set name=value", strings.Join(missing, ", "))
+			setExitCode(exitUsage)
+			return
+		}
+	}
+	if err := promptPlaceholders(placeholders, values); err != nil {
 		log.Error("Error: %v", err)
 		return
 	}
-
-	// Create ticket
-	ticket := map[string]interface{}{
-		"fields": map[string]interface{}{
-			"project": map[string]string{
-				"key": projectKey,
+	summary := fillPlaceholders(tpl.Summary, values)
+	description := fillPlaceholders(tpl.Description, values)
+
+	if !interactive {
+		// Without a terminal, only the template can give the summary and,
+		// for a system that would ask for it, the project
+		if strings.TrimSpace(summary) == "" {
+			log.Error("The template must set the summary without an interactive terminal; or use 'plannet ticket create --summary'")
+			setExitCode(exitUsage)
+			return
+		}
+		if _, asks := system.(ticketDetailsPrompter); asks && tpl.Project == "" {
+			log.Error("The template must set the project without an interactive terminal")
+			setExitCode(exitUsage)
+			return
+		}
+	} else {
+		// Ask for summary, starting from the template's
+		summaryPrompt := promptui.Prompt{
+			Label:     "Enter summary",
+			Default:   summary,
+			AllowEdit: true,
+			Validate: func(input string) error {
+				if input == "" {
+					return fmt.Errorf("summary cannot be empty")
+				}
+				return nil
 			},
-			"issuetype": map[string]string{
-				"name": issueType,
-			},
-			"summary":     summary,
-			"description": description,
-		},
-	}
-
-	// Marshal ticket data
-	ticketData, err := json.Marshal(ticket)
-	if err != nil {
-		log.Error("Failed to marshal ticket data: %v", err)
-		return
-	}
-
-	// Create HTTP client with rate limiting
-	rateLimiter := security.NewHTTPRateLimiter(10, time.Minute) // 10 requests per minute
-	client := rateLimiter.WrapHTTPClient(&http.Client{}, "jira")
+		}
+		summary, err = summaryPrompt.Run()
+		if err != nil {
+			log.Error("Error: %v", err)
+			return
+		}
+
+		// Ask for description
+		if tpl.Description == "" {
+			descriptionPrompt := promptui.Prompt{
+				Label: "Enter description",
+			}
+			description, err = descriptionPrompt.Run()
+			if err != nil {
+				log.Error("Error: %v", err)
+				return
+			}
+		}
+	}
+
+	// The ticket system asks for what else it needs, such as Jira's project,
+	// issue type, custom fields and parent
+	createTicket(ctx, system, systems.NewTicket{
+		Project:     tpl.Project,
+		Type:        tpl.Type,
+		Summary:     summary,
+		Description: description,
+		Labels:      tpl.Labels,
+		Parent:      tpl.Parent,
+		Fields:      tpl.fields(),
+	})
+}
 
-	// Create request
-	url := cfg.JiraURL + "/rest/api/2/issue"
-	req, err := http.NewRequest("POST", url, bytes.NewReader(ticketData))
-	if err != nil {
-		log.Error("Failed to create Jira API request: %v", err)
-		return
+// createJiraTicket creates a ticket in the configured Jira instance and returns
+// its key. Custom holds any other fields to set, by field ID.
+func createJiraTicket(ctx context.Context, cfg *config.Config, token, projectKey, issueType, summary, description string, custom map[string]interface{}) (string, error) {
+	client := newJiraClient(cfg, token)
+	fields := map[string]interface{}{
+		"project": map[string]string{
+			"key": projectKey,
+		},
+		"issuetype": map[string]string{
+			"name": issueType,
+		},
+		"summary": summary,
 	}
-
-	// Set headers
-	req.Header.Set("Authorization", "Basic "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	// Send request
-	resp, err := client.Do(req.WithContext(ctx))
-	if err != nil {
-		log.Error("Failed to send Jira API request: %v", err)
-		return
+	if strings.TrimSpace(description) != "" {
+		fields["description"] = client.richText(description)
 	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		log.Error("Jira API returned status %d: %s", resp.StatusCode, string(body))
-		return
+	for id, value := range custom {
+		fields[id] = value
 	}
+	ticket := map[string]interface{}{"fields": fields}
 
-	// Parse response
 	var result struct {
 		Key string `json:"key"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		log.Error("Failed to parse Jira API response: %v", err)
-		return
+	if err := client.do(ctx, "POST", client.api("/issue"), ticket, &result); err != nil {
+		return "", err
 	}
+	return result.Key, nil
+}
+
+// jiraIssue is an issue as returned by the Jira REST API
+type jiraIssue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string       `json:"summary"`
+		Description jiraRichText `json:"description"`
+		Status      struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Priority struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Assignee struct {
+			DisplayName string `json:"displayName"`
+		} `json:"assignee"`
+		Attachment []JiraAttachment `json:"attachment"`
+		IssueLinks []jiraIssueLink  `json:"issuelinks"`
+		Parent     *jiraParentIssue `json:"parent"`
+	} `json:"fields"`
+}
 
-	log.Info("Successfully created ticket %s", result.Key)
-	log.Info("URL: %s/browse/%s", cfg.JiraURL, result.Key)
+// fetchJiraTicket fetches a ticket from the configured Jira instance
+func fetchJiraTicket(ctx context.Context, cfg *config.Config, token, ticketKey string) (*JiraTicket, error) {
+	var raw json.RawMessage
+	client := newJiraClient(cfg, token)
+	if err := client.do(ctx, "GET", client.api("/issue/"+ticketKey), nil, &raw); err != nil {
+		return nil, err
+	}
+
+	var issue jiraIssue
+	if err := json.Unmarshal(raw, &issue); err != nil {
+		return nil, fmt.Errorf("failed to parse Jira API response: %w", err)
+	}
+	ticket := issue.ticket(cfg.JiraURL)
+
+	// Custom fields and the Epic Link field are read by the IDs in the configuration
+	if len(cfg.JiraFields) > 0 || cfg.JiraEpicField != "" {
+		var fields struct {
+			Fields map[string]json.RawMessage `json:"fields"`
+		}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse Jira API response: %w", err)
+		}
+		ticket.CustomFields = customFieldValues(fields.Fields, cfg.JiraFields)
+		if ticket.Parent == nil {
+			ticket.Parent = epicFromField(cfg, fields.Fields)
+		}
+	}
+	return &ticket, nil
+}
+
+// ticket converts an issue from the API to a JiraTicket
+func (issue jiraIssue) ticket(jiraURL string) JiraTicket {
+	return JiraTicket{
+		ID:          issue.ID,
+		Key:         issue.Key,
+		Summary:     issue.Fields.Summary,
+		Description: string(issue.Fields.Description),
+		Status:      issue.Fields.Status.Name,
+		Type:        issue.Fields.IssueType.Name,
+		Priority:    issue.Fields.Priority.Name,
+		Assignee:    issue.Fields.Assignee.DisplayName,
+		URL:         jiraURL + "/browse/" + issue.Key,
+		Attachments: issue.Fields.Attachment,
+		Links:       ticketLinks(issue.Fields.IssueLinks),
+		Parent:      issue.Fields.Parent.parent(),
+	}
 }
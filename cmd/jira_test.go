@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -504,3 +508,140 @@ func TestJiraResponseParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveJiraListJQL(t *testing.T) {
+	cfg := &config.Config{JiraQueries: map[string]string{"review": "status = 'In Review'"}}
+
+	tests := []struct {
+		name    string
+		query   string
+		jql     string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", want: defaultJiraListJQL},
+		{name: "jql", jql: "project = PROJ", want: "project = PROJ"},
+		{name: "saved query", query: "review", want: "status = 'In Review'"},
+		{name: "unknown query", query: "triage", wantErr: true},
+		{name: "query and jql", query: "review", jql: "project = PROJ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveJiraListJQL(cfg, tt.query, tt.jql)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveJiraListJQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveJiraListJQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchJiraTickets(t *testing.T) {
+	// Version 3 pages /search/jql by token, version 2 pages /search by offset
+	pages := map[int]map[string]string{
+		3: {
+			"":     `{"issues": [{"key": "PROJ-1", "fields": {"summary": "Fix login", "status": {"name": "In Review"}}}], "nextPageToken": "next", "isLast": false}`,
+			"next": `{"issues": [{"key": "PROJ-2", "fields": {"summary": "Add export", "status": {"name": "In Review"}}}], "isLast": true}`,
+		},
+		2: {
+			"0": `{"issues": [{"key": "PROJ-1", "fields": {"summary": "Fix login", "status": {"name": "In Review"}}}], "startAt": 0, "total": 2}`,
+			"1": `{"issues": [{"key": "PROJ-2", "fields": {"summary": "Add export", "status": {"name": "In Review"}}}], "startAt": 1, "total": 2}`,
+		},
+	}
+	paths := map[int]string{3: "/rest/api/3/search/jql", 2: "/rest/api/2/search"}
+
+	for version, path := range paths {
+		t.Run(fmt.Sprintf("version %d", version), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != path {
+					t.Errorf("Expected a request to %s, got %s", path, r.URL.Path)
+				}
+				query := r.URL.Query()
+				if jql := query.Get("jql"); jql != "status = 'In Review' AND project = PROJ" {
+					t.Errorf("Unexpected JQL %q", jql)
+				}
+				page := query.Get("startAt")
+				if version >= 3 {
+					page = query.Get("nextPageToken")
+					if query.Get("fields") == "" {
+						t.Errorf("Expected the fields to be requested")
+					}
+				}
+				w.Write([]byte(pages[version][page]))
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{JiraURL: server.URL, JiraAPIVersion: version}
+			tickets, err := searchJiraTickets(context.Background(), cfg, "test-token", "status = 'In Review' AND project = PROJ")
+			if err != nil {
+				t.Fatalf("Failed to search tickets: %v", err)
+			}
+			if len(tickets) != 2 || tickets[0].Key != "PROJ-1" || tickets[1].Key != "PROJ-2" || tickets[0].Status != "In Review" {
+				t.Fatalf("Expected the tickets of both pages, got %+v", tickets)
+			}
+			if !strings.HasSuffix(tickets[0].URL, "/browse/PROJ-1") {
+				t.Errorf("Unexpected ticket URL %q", tickets[0].URL)
+			}
+		})
+	}
+}
+
+func TestRunJiraCreateWithoutATerminal(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	resetExitCode(t)
+
+	// Standard input from a pipe isn't a terminal
+	stdin, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	defer w.Close()
+	oldStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	var created map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/3/issue" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Fields map[string]interface{} `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		created = body.Fields
+		w.Write([]byte(`{"key": "PROJ-9"}`))
+	}))
+	defer server.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.JiraURL, cfg.JiraUser, cfg.JiraToken = server.URL, "sam@example.com", "test-token"
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	template := filepath.Join(t.TempDir(), "bug.yaml")
+	if err := os.WriteFile(template, []byte("project: PROJ\ntype: Bug\nsummary: \"[{{area}}] Login fails\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	// A placeholder without a value would need a prompt
+	runJiraCreate(context.Background(), template, nil)
+	if exitCode != exitUsage || created != nil {
+		t.Errorf("Expected a usage error without a value for the placeholder, got exit code %d", exitCode)
+	}
+
+	resetExitCode(t)
+	runJiraCreate(context.Background(), template, []string{"area=web"})
+	if exitCode != 0 {
+		t.Fatalf("Expected the ticket to be created, got exit code %d", exitCode)
+	}
+	if created["summary"] != "[web] Login fails" {
+		t.Errorf("Expected the template's summary with the placeholder filled, got %v", created["summary"])
+	}
+}
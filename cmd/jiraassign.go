@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/security"
+	"github.com/spf13/cobra"
+)
+
+// jiraUser is a user as returned by the Jira REST API. Jira Cloud identifies
+// users by account ID, Jira Server by user name.
+type jiraUser struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// jiraAssignCmd represents the jira assign command
+var jiraAssignCmd = &cobra.Command{
+	Use:   "assign [ticket] [user|me|none]",
+	Short: "Assign a Jira ticket",
+	Long: `Assign a Jira ticket to a user, found by name or email, to yourself with
+"me" (the default), or unassign it with "none".`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		user := "me"
+		if len(args) > 1 {
+			user = args[1]
+		}
+		runJiraAssign(cmd.Context(), args[0], user)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraAssignCmd)
+}
+
+// runJiraAssign assigns a Jira ticket
+func runJiraAssign(ctx context.Context, ticketKey, user string) {
+	log := logger.WithContext(ctx)
+
+	// Validate ticket key
+	if err := security.ValidateTicketKey(ticketKey); err != nil {
+		log.Error("Invalid ticket key: %v", err)
+		setExitCode(exitUsage)
+		return
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	assignee, err := assignJiraTicket(ctx, newJiraClient(cfg, cfg.JiraToken), ticketKey, user)
+	if err != nil {
+		log.Error("Failed to assign %s: %v", ticketKey, err)
+		return
+	}
+	if assignee == nil {
+		log.Info("Unassigned %s", ticketKey)
+		return
+	}
+	log.Info("Assigned %s to %s", ticketKey, assignee.DisplayName)
+}
+
+// assignJiraTicket assigns a ticket to a user, or unassigns it for "none",
+// and returns the assignee
+func assignJiraTicket(ctx context.Context, client *jiraClient, ticketKey, user string) (*jiraUser, error) {
+	var assignee *jiraUser
+	if !strings.EqualFold(user, "none") {
+		var err error
+		if assignee, err = client.findUser(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	if err := setJiraAssignee(ctx, client, ticketKey, assignee); err != nil {
+		return nil, err
+	}
+	return assignee, nil
+}
+
+// setJiraAssignee assigns a ticket to a user, or unassigns it for nil
+func setJiraAssignee(ctx context.Context, client *jiraClient, ticketKey string, assignee *jiraUser) error {
+	body := map[string]interface{}{client.userField(): nil}
+	if assignee != nil {
+		body[client.userField()] = client.userID(*assignee)
+	}
+	return client.do(ctx, "PUT", client.api("/issue/"+ticketKey+"/assignee"), body, nil)
+}
+
+// findUser returns the current user for "me", or the user whose name or
+// email matches the query
+func (c *jiraClient) findUser(ctx context.Context, query string) (*jiraUser, error) {
+	if strings.EqualFold(query, "me") {
+		var user jiraUser
+		if err := c.do(ctx, "GET", c.api("/myself"), nil, &user); err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+
+	param := "query"
+	if c.version < 3 {
+		param = "username"
+	}
+	var users []jiraUser
+	if err := c.do(ctx, "GET", c.api("/user/search?"+param+"="+url.QueryEscape(query)), nil, &users); err != nil {
+		return nil, err
+	}
+	if len(users) == 1 {
+		return &users[0], nil
+	}
+	for i, user := range users {
+		if strings.EqualFold(user.EmailAddress, query) || strings.EqualFold(user.DisplayName, query) || strings.EqualFold(user.Name, query) {
+			return &users[i], nil
+		}
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("no Jira user matches %q", query)
+	}
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.DisplayName
+	}
+	return nil, fmt.Errorf("%q matches several users: %s", query, strings.Join(names, ", "))
+}
+
+// userField is the field that identifies a user in requests
+func (c *jiraClient) userField() string {
+	if c.version < 3 {
+		return "name"
+	}
+	return "accountId"
+}
+
+// userID returns the value that identifies a user in requests
+func (c *jiraClient) userID(user jiraUser) string {
+	if c.version < 3 {
+		return user.Name
+	}
+	return user.AccountID
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestAssignJiraTicket(t *testing.T) {
+	var assigned []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/user/search":
+			if r.URL.Query().Get("query") != "sam@example.com" {
+				t.Errorf("Unexpected user query %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`[
+				{"accountId": "a1", "displayName": "Sam Lee", "emailAddress": "sam.lee@example.com"},
+				{"accountId": "a2", "displayName": "Sam Park", "emailAddress": "sam@example.com"}
+			]`))
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/myself":
+			w.Write([]byte(`{"accountId": "me1", "displayName": "Me"}`))
+		case r.Method == "PUT" && r.URL.Path == "/rest/api/3/issue/PROJ-1/assignee":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assigned = append(assigned, body["accountId"])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+	ctx := context.Background()
+
+	assignee, err := assignJiraTicket(ctx, client, "PROJ-1", "sam@example.com")
+	if err != nil || assignee.DisplayName != "Sam Park" {
+		t.Fatalf("Expected the user with the exact email to be assigned, got %+v (%v)", assignee, err)
+	}
+	if assignee, err = assignJiraTicket(ctx, client, "PROJ-1", "me"); err != nil || assignee.AccountID != "me1" {
+		t.Fatalf("Expected to be assigned myself, got %+v (%v)", assignee, err)
+	}
+	if assignee, err = assignJiraTicket(ctx, client, "PROJ-1", "none"); err != nil || assignee != nil {
+		t.Fatalf("Expected the ticket to be unassigned, got %+v (%v)", assignee, err)
+	}
+
+	want := []interface{}{"a2", "me1", nil}
+	if len(assigned) != len(want) {
+		t.Fatalf("Expected %d assignments, got %v", len(want), assigned)
+	}
+	for i := range want {
+		if assigned[i] != want[i] {
+			t.Errorf("Assignment %d: expected %v, got %v", i, want[i], assigned[i])
+		}
+	}
+}
+
+func TestFindUserAmbiguous(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"accountId": "a1", "displayName": "Sam Lee"}, {"accountId": "a2", "displayName": "Sam Park"}]`))
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+	if _, err := client.findUser(context.Background(), "sam"); err == nil {
+		t.Error("Expected an error when several users match")
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/security"
+	"github.com/spf13/cobra"
+)
+
+// jiraAttachCmd represents the jira attach command
+var jiraAttachCmd = &cobra.Command{
+	Use:   "attach [ticket] [file...]",
+	Short: "Attach files to a Jira ticket",
+	Long: `Attach one or more files, such as screenshots or logs, to a Jira ticket.
+Use 'plannet jira view --download' to download a ticket's attachments.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runJiraAttach(cmd.Context(), args[0], args[1:])
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraAttachCmd)
+}
+
+// runJiraAttach attaches files to a Jira ticket
+func runJiraAttach(ctx context.Context, ticketKey string, files []string) {
+	log := logger.WithContext(ctx)
+
+	// Validate ticket key
+	if err := security.ValidateTicketKey(ticketKey); err != nil {
+		log.Error("Invalid ticket key: %v", err)
+		setExitCode(exitUsage)
+		return
+	}
+
+	// Check the files before uploading any of them
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			log.Error("Cannot attach %s: %v", file, err)
+			return
+		}
+		if info.IsDir() {
+			log.Error("Cannot attach %s: it is a directory", file)
+			return
+		}
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	attachments, err := attachJiraFiles(ctx, newJiraClient(cfg, cfg.JiraToken), ticketKey, files)
+	if err != nil {
+		log.Error("Failed to attach files to %s: %v", ticketKey, err)
+		return
+	}
+	for _, attachment := range attachments {
+		log.Info("Attached %s (%s) to %s", attachment.Filename, formatFileSize(attachment.Size), ticketKey)
+	}
+}
+
+// attachJiraFiles uploads files as attachments of a ticket
+func attachJiraFiles(ctx context.Context, client *jiraClient, ticketKey string, files []string) ([]JiraAttachment, error) {
+	var attachments []JiraAttachment
+	if err := client.upload(ctx, client.api("/issue/"+ticketKey+"/attachments"), files, &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// selectAttachments returns the attachments with the given file names, or all
+// of them when no names are given
+func selectAttachments(attachments []systems.Attachment, names []string) ([]systems.Attachment, error) {
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("the ticket has no attachments")
+	}
+	if len(names) == 0 {
+		return attachments, nil
+	}
+
+	var selected []systems.Attachment
+	for _, name := range names {
+		found := false
+		for _, attachment := range attachments {
+			if attachment.Filename == name {
+				selected = append(selected, attachment)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the ticket has no attachment named %q", name)
+		}
+	}
+	return selected, nil
+}
+
+// downloadAttachment saves an attachment into dir and returns its path.
+// Existing files are never overwritten.
+func downloadAttachment(ctx context.Context, client *jiraClient, attachment JiraAttachment, dir string) (string, error) {
+	// Only the base name is used, so a file name can't escape dir
+	name := filepath.Base(filepath.Clean("/" + strings.ReplaceAll(attachment.Filename, "\\", "/")))
+	if name == "/" || name == "." {
+		name = "attachment-" + attachment.ID
+	}
+	path := filepath.Join(dir, name)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	if err := client.download(ctx, attachment.URL, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// formatFileSize formats a size in bytes for display
+func formatFileSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestAttachJiraFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "screenshot.png")
+	if err := os.WriteFile(file, []byte("png data"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/3/issue/PROJ-1/attachments" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("X-Atlassian-Token") != "no-check" {
+			t.Errorf("Expected the X-Atlassian-Token header, got %q", r.Header.Get("X-Atlassian-Token"))
+		}
+		upload, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("Expected a file in the upload: %v", err)
+		}
+		data, _ := io.ReadAll(upload)
+		if header.Filename != "screenshot.png" || string(data) != "png data" {
+			t.Errorf("Unexpected upload %s: %q", header.Filename, data)
+		}
+		w.Write([]byte(`[{"id": "10", "filename": "screenshot.png", "size": 8, "mimeType": "image/png"}]`))
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+	attachments, err := attachJiraFiles(context.Background(), client, "PROJ-1", []string{file})
+	if err != nil {
+		t.Fatalf("Failed to attach file: %v", err)
+	}
+	if len(attachments) != 1 || attachments[0].ID != "10" || attachments[0].Size != 8 {
+		t.Errorf("Unexpected attachments %+v", attachments)
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic test-token" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte("log output"))
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+	dir := t.TempDir()
+	attachment := JiraAttachment{ID: "11", Filename: "../../build.log", URL: server.URL + "/secure/attachment/11/build.log"}
+
+	path, err := downloadAttachment(context.Background(), client, attachment, dir)
+	if err != nil {
+		t.Fatalf("Failed to download attachment: %v", err)
+	}
+	if path != filepath.Join(dir, "build.log") {
+		t.Errorf("Expected the attachment to be saved in the directory, got %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "log output" {
+		t.Errorf("Unexpected content %q", data)
+	}
+
+	if _, err := downloadAttachment(context.Background(), client, attachment, dir); err == nil {
+		t.Error("Expected an existing file not to be overwritten")
+	}
+	attachment.URL = "https://elsewhere.example.com/build.log"
+	if _, err := downloadAttachment(context.Background(), client, attachment, t.TempDir()); err == nil {
+		t.Error("Expected a download from another host to be refused")
+	}
+}
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/security"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// jiraBulkBatchPause is how long bulk changes wait between batches, for
+	// the client's rate limit to allow another batch of requests
+	jiraBulkBatchPause = jiraRequestWindow
+	// jiraBulkRetryWait is how long a rate limited change waits before it is retried
+	jiraBulkRetryWait = 15 * time.Second
+	// jiraBulkRetries is how many times a rate limited change is retried
+	jiraBulkRetries = 4
+)
+
+// jiraBulkChange is a change applied to several tickets at once
+type jiraBulkChange struct {
+	Transition   string
+	AddLabels    []string
+	RemoveLabels []string
+	Assignee     string // A user, "me", or "none" to unassign
+}
+
+// jiraBulkResult is the outcome of a bulk change on one ticket
+type jiraBulkResult struct {
+	Key     string
+	Skipped bool // The ticket already had the change
+	Err     error
+}
+
+// jiraBulkCmd represents the jira bulk command
+var jiraBulkCmd = &cobra.Command{
+	Use:   "bulk [query]",
+	Short: "Change several Jira tickets at once",
+	Long: `Apply a transition, label changes or a new assignee to several tickets at
+once. The tickets are those matching --jql or a saved query from
+jira_queries, or those you pick from the tickets assigned to you.
+
+Changes are sent in batches that respect the Jira rate limit, and a summary
+of the tickets that were and weren't changed is shown at the end.
+
+Examples:
+  plannet jira bulk --jql "sprint in openSprints() AND status = Review" --transition Done
+  plannet jira bulk stale --add-label needs-triage --assign none
+  plannet jira bulk --assign sam@example.com`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
+		jql, _ := cmd.Flags().GetString("jql")
+		var change jiraBulkChange
+		change.Transition, _ = cmd.Flags().GetString("transition")
+		change.AddLabels, _ = cmd.Flags().GetStringSlice("add-label")
+		change.RemoveLabels, _ = cmd.Flags().GetStringSlice("remove-label")
+		change.Assignee, _ = cmd.Flags().GetString("assign")
+		yes, _ := cmd.Flags().GetBool("yes")
+		runJiraBulk(cmd.Context(), query, jql, change, yes)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraBulkCmd)
+	jiraBulkCmd.Flags().String("jql", "", "Change the tickets matching this JQL query")
+	jiraBulkCmd.Flags().String("transition", "", "Move the tickets through this transition, or to this status")
+	jiraBulkCmd.Flags().StringSlice("add-label", nil, "Add this label (can be repeated)")
+	jiraBulkCmd.Flags().StringSlice("remove-label", nil, "Remove this label (can be repeated)")
+	jiraBulkCmd.Flags().String("assign", "", "Assign the tickets to this user, 'me', or 'none' to unassign them")
+	jiraBulkCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+}
+
+// runJiraBulk applies a change to the selected tickets
+func runJiraBulk(ctx context.Context, query, jql string, change jiraBulkChange, yes bool) {
+	log := logger.WithContext(ctx)
+
+	if change.empty() {
+		log.Error("Nothing to change: use --transition, --add-label, --remove-label or --assign")
+		return
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	// Without a query, the tickets are picked from those assigned to you
+	pick := query == "" && jql == ""
+	if pick && !canPickInteractively() {
+		log.Error("Use --jql or a saved query to select tickets when not running in a terminal")
+		return
+	}
+	jql, err = resolveJiraListJQL(cfg, query, jql)
+	if err != nil {
+		log.Error("%v", err)
+		return
+	}
+
+	tickets, err := searchJiraTickets(ctx, cfg, cfg.JiraToken, jql)
+	if err != nil {
+		log.Error("Failed to search Jira tickets: %v", err)
+		return
+	}
+	if len(tickets) == 0 {
+		log.Info("No tickets found.")
+		return
+	}
+
+	if pick {
+		tickets, err = pickJiraTickets("Select the tickets to change", tickets)
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			log.Error("Error selecting tickets: %v", err)
+			return
+		}
+		if len(tickets) == 0 {
+			log.Info("No tickets selected.")
+			return
+		}
+	}
+
+	client := newJiraClient(cfg, cfg.JiraToken)
+	var assignee *jiraUser
+	if change.Assignee != "" && !strings.EqualFold(change.Assignee, "none") {
+		if assignee, err = client.findUser(ctx, change.Assignee); err != nil {
+			log.Error("Failed to find user %q: %v", change.Assignee, err)
+			return
+		}
+	}
+
+	fmt.Printf("Will %s on %d ticket(s):\n", change.describe(assignee), len(tickets))
+	for _, ticket := range tickets {
+		fmt.Printf("  %s  [%s]  %s\n", ticket.Key, ticket.Status, ticket.Summary)
+	}
+	if !yes {
+		prompt := promptui.Prompt{
+			Label:     "Apply these changes",
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("No tickets were changed.")
+			return
+		}
+	}
+
+	results := applyJiraBulkChange(ctx, client, tickets, change, assignee, func(done, total int) {
+		fmt.Printf("Changed %d of %d ticket(s), waiting %s for the Jira rate limit...\n", done, total, jiraBulkBatchPause)
+	})
+	printJiraBulkSummary(results)
+}
+
+// empty reports whether the change changes nothing
+func (c jiraBulkChange) empty() bool {
+	return c.Transition == "" && len(c.AddLabels) == 0 && len(c.RemoveLabels) == 0 && c.Assignee == ""
+}
+
+// describe describes the change, with the assignee it assigns to
+func (c jiraBulkChange) describe(assignee *jiraUser) string {
+	var parts []string
+	if c.Transition != "" {
+		parts = append(parts, fmt.Sprintf("transition to %q", c.Transition))
+	}
+	if len(c.AddLabels) > 0 {
+		parts = append(parts, "add labels "+strings.Join(c.AddLabels, ", "))
+	}
+	if len(c.RemoveLabels) > 0 {
+		parts = append(parts, "remove labels "+strings.Join(c.RemoveLabels, ", "))
+	}
+	if c.Assignee != "" {
+		if assignee != nil {
+			parts = append(parts, "assign to "+assignee.DisplayName)
+		} else {
+			parts = append(parts, "unassign")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// requestsPerTicket returns how many requests changing one ticket takes
+func (c jiraBulkChange) requestsPerTicket() int {
+	n := 0
+	if c.Transition != "" {
+		n += 2 // The ticket's transitions are looked up first
+	}
+	if len(c.AddLabels) > 0 || len(c.RemoveLabels) > 0 {
+		n++
+	}
+	if c.Assignee != "" {
+		n++
+	}
+	return n
+}
+
+// applyJiraBulkChange applies a change to each ticket, in batches that fit
+// in the client's rate limit. Between batches, progress is called with the
+// number of tickets changed so far before pausing.
+func applyJiraBulkChange(ctx context.Context, client *jiraClient, tickets []JiraTicket, change jiraBulkChange, assignee *jiraUser, progress func(done, total int)) []jiraBulkResult {
+	batchSize := jiraRequestLimit / change.requestsPerTicket()
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	var results []jiraBulkResult
+	for i, ticket := range tickets {
+		if i > 0 && i%batchSize == 0 {
+			progress(i, len(tickets))
+			time.Sleep(jiraBulkBatchPause)
+		}
+		skipped, err := applyJiraTicketChange(ctx, client, ticket, change, assignee)
+		results = append(results, jiraBulkResult{Key: ticket.Key, Skipped: skipped, Err: err})
+	}
+	return results
+}
+
+// applyJiraTicketChange applies a change to a ticket, retrying requests that
+// are rate limited, and reports whether the ticket already had the change
+func applyJiraTicketChange(ctx context.Context, client *jiraClient, ticket JiraTicket, change jiraBulkChange, assignee *jiraUser) (bool, error) {
+	changed := false
+	if change.Transition != "" && !strings.EqualFold(ticket.Status, change.Transition) {
+		err := retryRateLimited(func() error {
+			_, err := transitionJiraTicket(ctx, client, ticket.Key, change.Transition)
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	if len(change.AddLabels) > 0 || len(change.RemoveLabels) > 0 {
+		var labels []map[string]string
+		for _, label := range change.AddLabels {
+			labels = append(labels, map[string]string{"add": label})
+		}
+		for _, label := range change.RemoveLabels {
+			labels = append(labels, map[string]string{"remove": label})
+		}
+		body := map[string]interface{}{"update": map[string]interface{}{"labels": labels}}
+		err := retryRateLimited(func() error {
+			return client.do(ctx, "PUT", client.api("/issue/"+ticket.Key), body, nil)
+		})
+		if err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	if change.Assignee != "" {
+		err := retryRateLimited(func() error {
+			return setJiraAssignee(ctx, client, ticket.Key, assignee)
+		})
+		if err != nil {
+			return false, err
+		}
+		changed = true
+	}
+	return !changed, nil
+}
+
+// retryRateLimited calls fn, and calls it again after a pause while it fails
+// because of a rate limit
+func retryRateLimited(fn func() error) error {
+	err := fn()
+	for attempt := 0; attempt < jiraBulkRetries && errors.Is(err, security.ErrRateLimitExceeded); attempt++ {
+		time.Sleep(jiraBulkRetryWait)
+		err = fn()
+	}
+	return err
+}
+
+// printJiraBulkSummary shows which tickets were changed and which failed
+func printJiraBulkSummary(results []jiraBulkResult) {
+	changed, skipped := 0, 0
+	var failed []jiraBulkResult
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed = append(failed, result)
+		case result.Skipped:
+			skipped++
+		default:
+			changed++
+		}
+	}
+
+	fmt.Printf("\nChanged %d of %d ticket(s)", changed, len(results))
+	if skipped > 0 {
+		fmt.Printf(", %d already up to date", skipped)
+	}
+	fmt.Println(".")
+	if len(failed) > 0 {
+		fmt.Printf("Failed to change %d ticket(s):\n", len(failed))
+		for _, result := range failed {
+			fmt.Printf("  %s: %v\n", result.Key, result.Err)
+		}
+	}
+}
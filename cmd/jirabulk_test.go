@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestApplyJiraBulkTransition(t *testing.T) {
+	defer func(wait time.Duration) { jiraBulkRetryWait = wait }(jiraBulkRetryWait)
+	jiraBulkRetryWait = 0
+
+	rateLimited := false
+	var transitioned []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			w.Write([]byte(`{"transitions": [{"id": "31", "name": "Finish", "to": {"name": "Done"}}]}`))
+		case r.Method == "GET" && r.URL.Path == "/rest/api/2/issue/PROJ-3/transitions":
+			w.Write([]byte(`{"transitions": []}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			// The first attempt is rate limited, and retried
+			if !rateLimited {
+				rateLimited = true
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			transitioned = append(transitioned, "PROJ-1")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL, JiraAPIVersion: 2}, "test-token")
+	tickets := []JiraTicket{
+		{Key: "PROJ-1", Status: "To Do"},
+		{Key: "PROJ-2", Status: "Done"},
+		{Key: "PROJ-3", Status: "Blocked"},
+	}
+	results := applyJiraBulkChange(context.Background(), client, tickets, jiraBulkChange{Transition: "Done"}, nil, func(int, int) {
+		t.Error("Three transitions fit in a single batch")
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Skipped {
+		t.Errorf("PROJ-1 should be transitioned, got %+v", results[0])
+	}
+	if results[1].Err != nil || !results[1].Skipped {
+		t.Errorf("PROJ-2 is already done and should be skipped, got %+v", results[1])
+	}
+	if results[2].Err == nil {
+		t.Error("PROJ-3 has no Done transition and should fail")
+	}
+	if !reflect.DeepEqual(transitioned, []string{"PROJ-1"}) {
+		t.Errorf("Transitioned %v", transitioned)
+	}
+}
+
+func TestApplyJiraBulkLabelsAndAssignee(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(data))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL, JiraAPIVersion: 2}, "test-token")
+	change := jiraBulkChange{AddLabels: []string{"triage"}, RemoveLabels: []string{"stale"}, Assignee: "none"}
+	results := applyJiraBulkChange(context.Background(), client, []JiraTicket{{Key: "PROJ-1"}}, change, nil, func(int, int) {})
+
+	if len(results) != 1 || results[0].Err != nil || results[0].Skipped {
+		t.Fatalf("Unexpected results %+v", results)
+	}
+	want := []string{
+		`PUT /rest/api/2/issue/PROJ-1 {"update":{"labels":[{"add":"triage"},{"remove":"stale"}]}}`,
+		`PUT /rest/api/2/issue/PROJ-1/assignee {"name":null}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests = %v, want %v", requests, want)
+	}
+}
+
+func TestJiraBulkBatchSize(t *testing.T) {
+	defer func(pause time.Duration) { jiraBulkBatchPause = pause }(jiraBulkBatchPause)
+	jiraBulkBatchPause = 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Each ticket takes two requests, so five fit in a batch. The client
+	// isn't rate limited, so that the test doesn't wait for the limit.
+	var pauses []int
+	client := &jiraClient{baseURL: server.URL, version: 2, client: &http.Client{}}
+	tickets := make([]JiraTicket, 7)
+	for i := range tickets {
+		tickets[i].Key = "PROJ-1"
+	}
+	change := jiraBulkChange{AddLabels: []string{"triage"}, Assignee: "none"}
+	applyJiraBulkChange(context.Background(), client, tickets, change, nil, func(done, total int) {
+		pauses = append(pauses, done)
+	})
+	if !reflect.DeepEqual(pauses, []int{5}) {
+		t.Errorf("Paused after %v tickets, want [5]", pauses)
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/security"
+)
+
+// defaultJiraAPIVersion is the version of the Jira REST API used unless
+// the configuration asks for another
+const defaultJiraAPIVersion = 3
+
+// The number of requests a Jira client sends in each window of time
+const (
+	jiraRequestLimit  = 10
+	jiraRequestWindow = time.Minute
+)
+
+// jiraClient sends requests to the REST API of a Jira instance
+type jiraClient struct {
+	baseURL string
+	token   string
+	version int
+	client  *http.Client
+}
+
+// newJiraClient returns a rate limited client for the configured Jira instance
+func newJiraClient(cfg *config.Config, token string) *jiraClient {
+	rateLimiter := security.NewHTTPRateLimiter(jiraRequestLimit, jiraRequestWindow)
+	rateLimiter.OnWait = reportRateLimitWait
+	return &jiraClient{
+		baseURL: cfg.JiraURL,
+		token:   token,
+		version: jiraAPIVersion(cfg),
+		client:  rateLimiter.WrapHTTPClient(&http.Client{}, "jira"),
+	}
+}
+
+// reportRateLimitWait tells the user that a request is waiting for a rate
+// limit, on stderr so that it doesn't mix with a command's output
+func reportRateLimitWait(key string, delay time.Duration) {
+	if delay < time.Second {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Waiting %s for the %s rate limit...\n", delay.Round(time.Second), key)
+}
+
+// api returns the path of a resource of the REST API, such as
+// "/rest/api/3/issue/PROJ-1" for "/issue/PROJ-1"
+func (c *jiraClient) api(resource string) string {
+	return fmt.Sprintf("/rest/api/%d%s", c.version, resource)
+}
+
+// search returns every issue matching a JQL query, a page at a time. Version
+// 3 of the API pages /search/jql with a token, as Jira Cloud retired /search
+// there; version 2 pages /search by offset.
+func (c *jiraClient) search(ctx context.Context, jql string) ([]json.RawMessage, error) {
+	var issues []json.RawMessage
+	query := url.Values{"jql": {jql}}
+	if c.version >= 3 {
+		// Without fields, /search/jql returns only the IDs of issues
+		query.Set("fields", "*navigable")
+		for {
+			var page struct {
+				Issues        []json.RawMessage `json:"issues"`
+				NextPageToken string            `json:"nextPageToken"`
+				IsLast        bool              `json:"isLast"`
+			}
+			if err := c.do(ctx, "GET", c.api("/search/jql?"+query.Encode()), nil, &page); err != nil {
+				return nil, err
+			}
+			issues = append(issues, page.Issues...)
+			if page.IsLast || page.NextPageToken == "" {
+				return issues, nil
+			}
+			query.Set("nextPageToken", page.NextPageToken)
+		}
+	}
+
+	for {
+		var page struct {
+			Issues []json.RawMessage `json:"issues"`
+			Total  int               `json:"total"`
+		}
+		query.Set("startAt", strconv.Itoa(len(issues)))
+		if err := c.do(ctx, "GET", c.api("/search?"+query.Encode()), nil, &page); err != nil {
+			return nil, err
+		}
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// richText returns text in the form descriptions and comments are sent in:
+// plain text in version 2 of the API and an ADF document in version 3
+func (c *jiraClient) richText(text string) interface{} {
+	if c.version < 3 {
+		return text
+	}
+	return textToADF(text)
+}
+
+// do sends a request to the API path, such as "/rest/api/3/issue/PROJ-1",
+// with body encoded as JSON, and decodes the JSON response into out. Either
+// may be nil. Any status other than 2xx is returned as an error.
+func (c *jiraClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Jira API request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create Jira API request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.send(ctx, req, out)
+}
+
+// upload sends files to the API path as a multipart form, in the "file"
+// field Jira expects attachments in, and decodes the JSON response into out
+func (c *jiraClient) upload(ctx context.Context, path string, files []string, out interface{}) error {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	for _, file := range files {
+		if err := addFormFile(form, file); err != nil {
+			return err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return fmt.Errorf("failed to create upload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+path, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create Jira API request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	// Jira rejects multipart requests without this header as possible XSRF
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	return c.send(ctx, req, out)
+}
+
+// addFormFile adds a file to a multipart form
+func addFormFile(form *multipart.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	defer f.Close()
+
+	part, err := form.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return fmt.Errorf("failed to add %s to upload: %w", file, err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return nil
+}
+
+// download writes the content at a URL of the Jira instance to w. URLs of
+// other hosts are refused, so that the token is never sent elsewhere.
+func (c *jiraClient) download(ctx context.Context, contentURL string, w io.Writer) error {
+	if !strings.HasPrefix(contentURL, strings.TrimSuffix(c.baseURL, "/")+"/") {
+		return fmt.Errorf("refusing to download %s from outside %s", contentURL, c.baseURL)
+	}
+	req, err := http.NewRequest("GET", contentURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Jira API request: %w", err)
+	}
+	req.Header.Set("Authorization", "Basic "+c.token)
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to send Jira API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Jira API returned status %d", resp.StatusCode)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", contentURL, err)
+	}
+	return nil
+}
+
+// send authenticates and sends a request, and decodes the JSON response into
+// out, which may be nil. Any status other than 2xx is returned as an error.
+func (c *jiraClient) send(ctx context.Context, req *http.Request, out interface{}) error {
+	req.Header.Set("Authorization", "Basic "+c.token)
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to send Jira API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: Jira API returned status %d", security.ErrRateLimitExceeded, resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Jira API returned status %d: %s", resp.StatusCode, string(data))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse Jira API response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// jiraCommentCmd represents the jira comment command
+var jiraCommentCmd = &cobra.Command{
+	Use:   "comment [ticket] [text]",
+	Short: "Comment on a Jira ticket",
+	Long: `Add a comment to a Jira ticket. The comment is taken from the arguments,
+from standard input when the text is "-" or input is piped in, or written
+in your editor with --editor.
+With --work, a summary of the latest work tracked on the ticket is appended,
+or used as the whole comment when no text is given.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		useEditor, _ := cmd.Flags().GetBool("editor")
+		withWork, _ := cmd.Flags().GetBool("work")
+		runTicketComment(cmd.Context(), args[0], strings.Join(args[1:], " "), useEditor, withWork)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraCommentCmd)
+	jiraCommentCmd.Flags().BoolP("editor", "e", false, "Write the comment in your editor")
+	jiraCommentCmd.Flags().Bool("work", false, "Append a summary of the latest work tracked on the ticket")
+}
+
+// readCommentText returns the text of a comment on a ticket: the given
+// text, or piped input when the text is "-" or not given, with a summary of
+// the latest work on the ticket if withWork, edited in the editor if
+// useEditor
+func readCommentText(cfg *config.Config, ticketKey, text string, useEditor, withWork bool) (string, error) {
+	// Piped input is read unless the text was given as arguments
+	if text == "-" || (text == "" && !useEditor && !isInteractive()) {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read comment from standard input: %w", err)
+		}
+		text = string(data)
+	}
+
+	if withWork {
+		work, err := latestWorkOnTicket(ticketKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to get tracked work: %w", err)
+		}
+		if work == nil {
+			return "", fmt.Errorf("no work tracked on %s", ticketKey)
+		}
+		text = joinParagraphs(text, workSummary(*work))
+	}
+
+	if useEditor {
+		var err error
+		text, err = editTextInEditor(text, cfg.Editor, "plannet-comment-*.md")
+		if err != nil {
+			return "", fmt.Errorf("failed to edit comment: %w", err)
+		}
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("the comment is empty")
+	}
+	return text, nil
+}
+
+// addJiraComment adds a comment to a ticket in the configured Jira instance
+func addJiraComment(ctx context.Context, cfg *config.Config, token, ticketKey, body string) error {
+	client := newJiraClient(cfg, token)
+	comment := map[string]interface{}{"body": client.richText(body)}
+	return client.do(ctx, "POST", client.api("/issue/"+ticketKey+"/comment"), comment, nil)
+}
+
+// latestWorkOnTicket returns the most recently started work linked to a
+// ticket, or nil if there is none
+func latestWorkOnTicket(ticketID string) (*TrackedWork, error) {
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *TrackedWork
+	for i, work := range trackedWork {
+		if work.HasTicket(ticketID) && (latest == nil || work.StartTime.After(latest.StartTime)) {
+			latest = &trackedWork[i]
+		}
+	}
+	return latest, nil
+}
+
+// workSummary describes tracked work for sharing: the time spent, the
+// subtasks completed, the notes taken and the size of the change
+func workSummary(work TrackedWork) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Worked %s on %s", formatDuration(work.Duration()), work.Description)
+	if work.Status == "completed" {
+		fmt.Fprintf(&b, " (%s - %s)", work.StartTime.Format("2006-01-02 15:04"), work.EndTime.Format("15:04"))
+	} else {
+		fmt.Fprintf(&b, " (since %s, %s)", work.StartTime.Format("2006-01-02 15:04"), work.Status)
+	}
+	b.WriteString(".")
+
+	var done []string
+	for _, subtask := range work.Subtasks {
+		if subtask.Done {
+			done = append(done, subtask.Text)
+		}
+	}
+	if len(done) > 0 {
+		b.WriteString("\n\nDone:")
+		for _, text := range done {
+			fmt.Fprintf(&b, "\n- %s", text)
+		}
+	}
+	if len(work.Notes) > 0 {
+		b.WriteString("\n\nNotes:")
+		for _, note := range work.Notes {
+			fmt.Fprintf(&b, "\n- %s", note.Text)
+		}
+	}
+	if len(work.Context.FileChurn) > 0 {
+		fmt.Fprintf(&b, "\n\nChanges: +%d/-%d lines in %d file(s)", work.Context.LinesAdded, work.Context.LinesRemoved, len(work.Context.FileChurn))
+	}
+	return b.String()
+}
+
+// joinParagraphs joins the non-empty texts with blank lines
+func joinParagraphs(texts ...string) string {
+	var paragraphs []string
+	for _, text := range texts {
+		if text = strings.TrimSpace(text); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestAddJiraComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/3/issue/PROJ-123/comment" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Basic test-token" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		var comment struct {
+			Body adfNode `json:"body"`
+		}
+		json.NewDecoder(r.Body).Decode(&comment)
+		if comment.Body.Type != "doc" || renderADF(comment.Body) != "Fixed the flaky test" {
+			t.Errorf("Unexpected comment %+v", comment)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "10001"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JiraURL: server.URL}
+	if err := addJiraComment(context.Background(), cfg, "test-token", "PROJ-123", "Fixed the flaky test"); err != nil {
+		t.Fatalf("Failed to add comment: %v", err)
+	}
+}
+
+func TestWorkSummary(t *testing.T) {
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local)
+	work := TrackedWork{
+		Description: "Fix login timeout",
+		StartTime:   start,
+		EndTime:     start.Add(90 * time.Minute),
+		Status:      "completed",
+		Subtasks:    []Subtask{{Text: "Reproduce", Done: true}, {Text: "Add test"}},
+		Notes:       []Note{{Time: start, Text: "Caused by the proxy"}},
+	}
+
+	summary := workSummary(work)
+	for _, want := range []string{"Worked 1h 30m on Fix login timeout (2024-03-11 09:00 - 10:30).", "- Reproduce", "- Caused by the proxy"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+	if strings.Contains(summary, "Add test") {
+		t.Errorf("Expected open subtasks to be left out, got:\n%s", summary)
+	}
+
+	if got := joinParagraphs("Looks good", "", summary); !strings.HasPrefix(got, "Looks good\n\nWorked") {
+		t.Errorf("Unexpected joined comment %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// JiraParent is the parent of a Jira ticket: its epic, or the story of a subtask
+type JiraParent struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// jiraParentIssue is the parent of an issue as returned by the Jira REST API
+type jiraParentIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary   string `json:"summary"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+	} `json:"fields"`
+}
+
+// epicJQL returns the JQL that finds the children of an epic, through the
+// Epic Link field when one is configured and the parent field otherwise
+func epicJQL(cfg *config.Config, epicKey string) string {
+	if id := strings.TrimPrefix(cfg.JiraEpicField, "customfield_"); id != cfg.JiraEpicField {
+		return fmt.Sprintf("cf[%s] = %s ORDER BY rank", id, epicKey)
+	}
+	return fmt.Sprintf("parent = %s ORDER BY rank", epicKey)
+}
+
+// parentField returns the ID and value of the field that sets the parent or
+// epic of a new ticket
+func parentField(cfg *config.Config, parentKey string) (string, interface{}) {
+	if cfg.JiraEpicField != "" {
+		return cfg.JiraEpicField, parentKey
+	}
+	return "parent", map[string]string{"key": parentKey}
+}
+
+// epicFromField returns the parent set through the configured Epic Link
+// field among the raw fields of an issue, or nil
+func epicFromField(cfg *config.Config, fields map[string]json.RawMessage) *JiraParent {
+	if cfg.JiraEpicField == "" {
+		return nil
+	}
+	var key string
+	if err := json.Unmarshal(fields[cfg.JiraEpicField], &key); err != nil || key == "" {
+		return nil
+	}
+	return &JiraParent{Key: key, Type: "Epic"}
+}
+
+// parent converts the parent of an issue from the API to a JiraParent
+func (p *jiraParentIssue) parent() *JiraParent {
+	if p == nil || p.Key == "" {
+		return nil
+	}
+	return &JiraParent{Key: p.Key, Summary: p.Fields.Summary, Type: p.Fields.IssueType.Name}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestEpicJQL(t *testing.T) {
+	if got := epicJQL(&config.Config{}, "PROJ-100"); got != "parent = PROJ-100 ORDER BY rank" {
+		t.Errorf("Unexpected JQL %q", got)
+	}
+	cfg := &config.Config{JiraEpicField: "customfield_10014"}
+	if got := epicJQL(cfg, "PROJ-100"); got != "cf[10014] = PROJ-100 ORDER BY rank" {
+		t.Errorf("Unexpected Epic Link JQL %q", got)
+	}
+
+	if id, value := parentField(&config.Config{}, "PROJ-100"); id != "parent" || !reflect.DeepEqual(value, map[string]string{"key": "PROJ-100"}) {
+		t.Errorf("Unexpected parent field %s = %v", id, value)
+	}
+	if id, value := parentField(cfg, "PROJ-100"); id != "customfield_10014" || value != "PROJ-100" {
+		t.Errorf("Unexpected Epic Link field %s = %v", id, value)
+	}
+}
+
+func TestFetchJiraTicketParent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/issue/PROJ-1":
+			w.Write([]byte(`{"key": "PROJ-1", "fields": {"summary": "Fix login",
+				"parent": {"key": "PROJ-100", "fields": {"summary": "Auth revamp", "issuetype": {"name": "Epic"}}}}}`))
+		case "/rest/api/3/issue/PROJ-2":
+			w.Write([]byte(`{"key": "PROJ-2", "fields": {"summary": "Add export", "customfield_10014": "PROJ-200"}}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JiraURL: server.URL, JiraEpicField: "customfield_10014"}
+	ticket, err := fetchJiraTicket(context.Background(), cfg, "test-token", "PROJ-1")
+	if err != nil {
+		t.Fatalf("Failed to fetch ticket: %v", err)
+	}
+	if want := (&JiraParent{Key: "PROJ-100", Summary: "Auth revamp", Type: "Epic"}); !reflect.DeepEqual(ticket.Parent, want) {
+		t.Errorf("Expected parent %+v, got %+v", want, ticket.Parent)
+	}
+
+	ticket, err = fetchJiraTicket(context.Background(), cfg, "test-token", "PROJ-2")
+	if err != nil {
+		t.Fatalf("Failed to fetch ticket: %v", err)
+	}
+	if ticket.Parent == nil || ticket.Parent.Key != "PROJ-200" {
+		t.Errorf("Expected the epic from the Epic Link field, got %+v", ticket.Parent)
+	}
+}
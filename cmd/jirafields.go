@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+)
+
+// JiraFieldValue is the value of a configured Jira field, as displayed
+type JiraFieldValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// promptJiraFields asks for the value of each configured field and returns
+// the fields to send when creating a ticket. Fields left empty are not sent.
+func promptJiraFields(ctx context.Context, client *jiraClient, fields []config.JiraField) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for _, field := range fields {
+		input, err := promptJiraField(field)
+		if err != nil {
+			return nil, err
+		}
+		if input == "" {
+			continue
+		}
+		value, err := jiraFieldValue(ctx, client, field, input)
+		if err != nil {
+			return nil, err
+		}
+		values[field.ID] = value
+	}
+	return values, nil
+}
+
+// promptJiraField asks for the value of a field, picking from its options if it has any
+func promptJiraField(field config.JiraField) (string, error) {
+	label := field.Prompt
+	if label == "" {
+		label = field.Name
+	}
+
+	if len(field.Options) > 0 {
+		items := field.Options
+		if !field.Required {
+			items = append([]string{"(none)"}, items...)
+		}
+		prompt := promptui.Select{
+			Label: label,
+			Items: items,
+		}
+		index, value, err := prompt.Run()
+		if err != nil {
+			return "", err
+		}
+		if !field.Required && index == 0 {
+			return "", nil
+		}
+		return value, nil
+	}
+
+	if isMultiValueField(field) {
+		label += " (comma separated)"
+	}
+	prompt := promptui.Prompt{
+		Label: label,
+		Validate: func(input string) error {
+			input = strings.TrimSpace(input)
+			if input == "" {
+				if field.Required {
+					return fmt.Errorf("%s cannot be empty", field.Name)
+				}
+				return nil
+			}
+			if field.Type == "number" {
+				if _, err := strconv.ParseFloat(input, 64); err != nil {
+					return fmt.Errorf("%s must be a number", field.Name)
+				}
+			}
+			return nil
+		},
+	}
+	input, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(input), nil
+}
+
+// jiraFieldValue converts the input for a field to the value the Jira API
+// expects for the field's type
+func jiraFieldValue(ctx context.Context, client *jiraClient, field config.JiraField, input string) (interface{}, error) {
+	switch field.Type {
+	case "number":
+		number, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", field.Name)
+		}
+		return number, nil
+	case "option":
+		return map[string]string{"value": input}, nil
+	case "options":
+		var options []map[string]string
+		for _, value := range splitList(input) {
+			options = append(options, map[string]string{"value": value})
+		}
+		return options, nil
+	case "labels":
+		return splitList(input), nil
+	case "components":
+		var components []map[string]string
+		for _, name := range splitList(input) {
+			components = append(components, map[string]string{"name": name})
+		}
+		return components, nil
+	case "user":
+		user, err := client.findUser(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		return map[string]string{client.userField(): client.userID(*user)}, nil
+	default:
+		return input, nil
+	}
+}
+
+// isMultiValueField reports whether a field takes several values
+func isMultiValueField(field config.JiraField) bool {
+	return field.Type == "options" || field.Type == "labels" || field.Type == "components"
+}
+
+// splitList splits comma separated input into its non-empty values
+func splitList(input string) []string {
+	var values []string
+	for _, value := range strings.Split(input, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// customFieldValues returns the displayed values of the configured fields
+// among the fields of an issue; fields without a value are left out
+func customFieldValues(issueFields map[string]json.RawMessage, fields []config.JiraField) []JiraFieldValue {
+	var values []JiraFieldValue
+	for _, field := range fields {
+		if value := renderJiraFieldValue(issueFields[field.ID]); value != "" {
+			values = append(values, JiraFieldValue{Name: field.Name, Value: value})
+		}
+	}
+	return values
+}
+
+// renderJiraFieldValue renders the value of a field for display, whatever
+// its type: text, numbers, options, users, lists of those, or rich text
+func renderJiraFieldValue(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		var items []json.RawMessage
+		json.Unmarshal(raw, &items)
+		var rendered []string
+		for _, item := range items {
+			if text := renderJiraFieldValue(item); text != "" {
+				rendered = append(rendered, text)
+			}
+		}
+		return strings.Join(rendered, ", ")
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			var doc adfNode
+			json.Unmarshal(raw, &doc)
+			return renderADF(doc)
+		}
+		for _, key := range []string{"value", "name", "displayName", "key"} {
+			if text, ok := v[key].(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
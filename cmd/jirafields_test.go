@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestJiraFieldValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"accountId": "a1", "displayName": "Sam Park"}]`))
+	}))
+	defer server.Close()
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+
+	tests := []struct {
+		field config.JiraField
+		input string
+		want  interface{}
+	}{
+		{config.JiraField{Name: "Team"}, "Payments", "Payments"},
+		{config.JiraField{Name: "Points", Type: "number"}, "3", 3.0},
+		{config.JiraField{Name: "Severity", Type: "option"}, "High", map[string]string{"value": "High"}},
+		{config.JiraField{Name: "Areas", Type: "options"}, "API, Web", []map[string]string{{"value": "API"}, {"value": "Web"}}},
+		{config.JiraField{Name: "Labels", Type: "labels"}, "backend,, urgent", []string{"backend", "urgent"}},
+		{config.JiraField{Name: "Components", Type: "components"}, "Auth", []map[string]string{{"name": "Auth"}}},
+		{config.JiraField{Name: "Reviewer", Type: "user"}, "sam", map[string]string{"accountId": "a1"}},
+	}
+	for _, tt := range tests {
+		got, err := jiraFieldValue(context.Background(), client, tt.field, tt.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.field.Name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %#v, got %#v", tt.field.Name, tt.want, got)
+		}
+	}
+
+	if _, err := jiraFieldValue(context.Background(), client, config.JiraField{Name: "Points", Type: "number"}, "a few"); err == nil {
+		t.Error("Expected an invalid number to fail")
+	}
+}
+
+func TestCustomFieldValues(t *testing.T) {
+	var fields map[string]json.RawMessage
+	data := `{
+		"customfield_1": "Payments",
+		"customfield_2": 5,
+		"customfield_3": {"value": "High"},
+		"components": [{"name": "Auth"}, {"name": "API"}],
+		"customfield_4": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Notes"}]}]},
+		"customfield_5": null
+	}`
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		t.Fatalf("Failed to parse fields: %v", err)
+	}
+
+	defs := []config.JiraField{
+		{Name: "Team", ID: "customfield_1"},
+		{Name: "Story Points", ID: "customfield_2", Type: "number"},
+		{Name: "Severity", ID: "customfield_3", Type: "option"},
+		{Name: "Components", ID: "components", Type: "components"},
+		{Name: "Notes", ID: "customfield_4"},
+		{Name: "Empty", ID: "customfield_5"},
+		{Name: "Missing", ID: "customfield_6"},
+	}
+	want := []JiraFieldValue{
+		{Name: "Team", Value: "Payments"},
+		{Name: "Story Points", Value: "5"},
+		{Name: "Severity", Value: "High"},
+		{Name: "Components", Value: "Auth, API"},
+		{Name: "Notes", Value: "Notes"},
+	}
+	if got := customFieldValues(fields, defs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCreateJiraTicketWithCustomFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ticket struct {
+			Fields map[string]interface{} `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&ticket)
+		if ticket.Fields["customfield_10016"] != 3.0 || ticket.Fields["summary"] != "Add export" {
+			t.Errorf("Unexpected fields %v", ticket.Fields)
+		}
+		if _, ok := ticket.Fields["description"]; ok {
+			t.Errorf("Expected an empty description not to be sent, got %v", ticket.Fields["description"])
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"key": "PROJ-9"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JiraURL: server.URL}
+	key, err := createJiraTicket(context.Background(), cfg, "test-token", "PROJ", "Story", "Add export", "", map[string]interface{}{"customfield_10016": 3.0})
+	if err != nil || key != "PROJ-9" {
+		t.Fatalf("Failed to create ticket: %q (%v)", key, err)
+	}
+}
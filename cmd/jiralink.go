@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/security"
+	"github.com/spf13/cobra"
+)
+
+// JiraLink is a link from a Jira ticket to another, such as "blocks PROJ-2"
+type JiraLink struct {
+	Relation string `json:"relation"`
+	Key      string `json:"key"`
+	Summary  string `json:"summary"`
+	Status   string `json:"status"`
+}
+
+// jiraLinkType is a kind of issue link, described from either end
+type jiraLinkType struct {
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// jiraIssueLink is a link as returned with an issue; only the issue at the
+// other end is set
+type jiraIssueLink struct {
+	Type         jiraLinkType   `json:"type"`
+	InwardIssue  *jiraLinkedKey `json:"inwardIssue"`
+	OutwardIssue *jiraLinkedKey `json:"outwardIssue"`
+}
+
+// jiraLinkedKey is the issue at the other end of a link
+type jiraLinkedKey struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+// jiraLinkCmd represents the jira link command
+var jiraLinkCmd = &cobra.Command{
+	Use:   "link [ticket] [relation] [ticket]",
+	Short: "Link two Jira tickets",
+	Long: `Link a Jira ticket to another, such as 'plannet jira link PROJ-1 blocks PROJ-2'.
+The relation is the description of a link type from either end, like
+"blocks" or "is blocked by", or the name of the link type.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		runJiraLink(cmd.Context(), args[0], args[1], args[2])
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraLinkCmd)
+}
+
+// runJiraLink links two Jira tickets
+func runJiraLink(ctx context.Context, fromKey, relation, toKey string) {
+	log := logger.WithContext(ctx)
+
+	// Validate ticket keys
+	for _, key := range []string{fromKey, toKey} {
+		if err := security.ValidateTicketKey(key); err != nil {
+			log.Error("Invalid ticket key: %v", err)
+			setExitCode(exitUsage)
+			return
+		}
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	description, err := linkJiraTickets(ctx, newJiraClient(cfg, cfg.JiraToken), fromKey, relation, toKey)
+	if err != nil {
+		log.Error("Failed to link %s to %s: %v", fromKey, toKey, err)
+		return
+	}
+	log.Info("%s %s %s", fromKey, description, toKey)
+}
+
+// linkJiraTickets links from to to with the link type matching the relation,
+// and returns the relation as Jira describes it
+func linkJiraTickets(ctx context.Context, client *jiraClient, fromKey, relation, toKey string) (string, error) {
+	var result struct {
+		IssueLinkTypes []jiraLinkType `json:"issueLinkTypes"`
+	}
+	if err := client.do(ctx, "GET", client.api("/issueLinkType"), nil, &result); err != nil {
+		return "", err
+	}
+
+	linkType, outward, ok := findLinkType(result.IssueLinkTypes, relation)
+	if !ok {
+		var relations []string
+		for _, t := range result.IssueLinkTypes {
+			relations = append(relations, fmt.Sprintf("%q", t.Outward), fmt.Sprintf("%q", t.Inward))
+		}
+		return "", fmt.Errorf("unknown relation %q, expected one of: %s", relation, strings.Join(relations, ", "))
+	}
+
+	// The API names the ends of a link after the description the other end
+	// shows: the inward issue is the one that "blocks" the outward issue
+	inward, outwardKey, description := fromKey, toKey, linkType.Outward
+	if !outward {
+		inward, outwardKey, description = toKey, fromKey, linkType.Inward
+	}
+	link := map[string]interface{}{
+		"type":         map[string]string{"name": linkType.Name},
+		"inwardIssue":  map[string]string{"key": inward},
+		"outwardIssue": map[string]string{"key": outwardKey},
+	}
+	if err := client.do(ctx, "POST", client.api("/issueLink"), link, nil); err != nil {
+		return "", err
+	}
+	return description, nil
+}
+
+// findLinkType returns the link type a relation describes, and whether it
+// describes it from the outward end, as the link type's name does
+func findLinkType(types []jiraLinkType, relation string) (jiraLinkType, bool, bool) {
+	for _, t := range types {
+		if strings.EqualFold(t.Outward, relation) || strings.EqualFold(t.Name, relation) {
+			return t, true, true
+		}
+		if strings.EqualFold(t.Inward, relation) {
+			return t, false, true
+		}
+	}
+	return jiraLinkType{}, false, false
+}
+
+// ticketLinks converts the links of an issue to JiraLinks, described from the issue's end
+func ticketLinks(issueLinks []jiraIssueLink) []JiraLink {
+	var links []JiraLink
+	for _, issueLink := range issueLinks {
+		relation, other := issueLink.Type.Outward, issueLink.OutwardIssue
+		if other == nil {
+			relation, other = issueLink.Type.Inward, issueLink.InwardIssue
+		}
+		if other == nil {
+			continue
+		}
+		links = append(links, JiraLink{
+			Relation: relation,
+			Key:      other.Key,
+			Summary:  other.Fields.Summary,
+			Status:   other.Fields.Status.Name,
+		})
+	}
+	return links
+}
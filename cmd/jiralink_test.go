@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestLinkJiraTickets(t *testing.T) {
+	var links []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/issueLinkType":
+			w.Write([]byte(`{"issueLinkTypes": [
+				{"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+				{"name": "Relates", "inward": "relates to", "outward": "relates to"}
+			]}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issueLink":
+			var link map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&link)
+			links = append(links, link)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+	ctx := context.Background()
+
+	if description, err := linkJiraTickets(ctx, client, "PROJ-1", "blocks", "PROJ-2"); err != nil || description != "blocks" {
+		t.Fatalf("Failed to link with an outward relation: %q (%v)", description, err)
+	}
+	if description, err := linkJiraTickets(ctx, client, "PROJ-1", "Is Blocked By", "PROJ-3"); err != nil || description != "is blocked by" {
+		t.Fatalf("Failed to link with an inward relation: %q (%v)", description, err)
+	}
+	if _, err := linkJiraTickets(ctx, client, "PROJ-1", "clones", "PROJ-4"); err == nil {
+		t.Error("Expected an unknown relation to fail")
+	}
+
+	// Both links make the blocking ticket the inward issue
+	want := [][2]string{{"PROJ-1", "PROJ-2"}, {"PROJ-3", "PROJ-1"}}
+	if len(links) != len(want) {
+		t.Fatalf("Expected %d links, got %v", len(want), links)
+	}
+	for i, link := range links {
+		inward := link["inwardIssue"].(map[string]interface{})["key"]
+		outward := link["outwardIssue"].(map[string]interface{})["key"]
+		if inward != want[i][0] || outward != want[i][1] {
+			t.Errorf("Link %d: expected %s -> %s, got %v -> %v", i, want[i][0], want[i][1], inward, outward)
+		}
+	}
+}
+
+func TestTicketLinks(t *testing.T) {
+	var issue jiraIssue
+	data := `{"key": "PROJ-1", "fields": {"issuelinks": [
+		{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+		 "outwardIssue": {"key": "PROJ-2", "fields": {"summary": "Release", "status": {"name": "To Do"}}}},
+		{"type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+		 "inwardIssue": {"key": "PROJ-3", "fields": {"summary": "Schema change", "status": {"name": "In Progress"}}}}
+	]}}`
+	if err := json.Unmarshal([]byte(data), &issue); err != nil {
+		t.Fatalf("Failed to parse issue: %v", err)
+	}
+
+	links := issue.ticket("https://jira.example.com").Links
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %+v", links)
+	}
+	if links[0] != (JiraLink{Relation: "blocks", Key: "PROJ-2", Summary: "Release", Status: "To Do"}) {
+		t.Errorf("Unexpected first link %+v", links[0])
+	}
+	if links[1].Relation != "is blocked by" || links[1].Key != "PROJ-3" {
+		t.Errorf("Unexpected second link %+v", links[1])
+	}
+}
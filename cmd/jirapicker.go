@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+)
+
+// jiraTicketTemplates shows a ticket per line, with the details of the
+// highlighted ticket below the list
+var jiraTicketTemplates = &promptui.SelectTemplates{
+	Label:    "{{ . }}",
+	Active:   "▸ {{ .Key | cyan }}  {{ .Summary }} ({{ .Status | faint }})",
+	Inactive: "  {{ .Key | cyan }}  {{ .Summary }} ({{ .Status | faint }})",
+	Selected: "{{ .Key | cyan }}  {{ .Summary }}",
+	Details: `
+--------- {{ .Key }} ---------
+{{ "Type:" | faint }}	{{ .Type }}
+{{ "Status:" | faint }}	{{ .Status }}
+{{ "Priority:" | faint }}	{{ .Priority }}
+{{ "Assignee:" | faint }}	{{ .Assignee }}{{ if .Parent }}
+{{ "Parent:" | faint }}	{{ .Parent.Key }} {{ .Parent.Summary }}{{ end }}`,
+}
+
+// canPickInteractively reports whether both input and output are a terminal,
+// so that an interactive picker can be shown instead of printing lines
+func canPickInteractively() bool {
+	info, err := os.Stdout.Stat()
+	return isInteractive() && err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// pickJiraTicket asks the user to pick one of the tickets, searching by key or summary
+func pickJiraTicket(label string, tickets []JiraTicket) (*JiraTicket, error) {
+	prompt := promptui.Select{
+		Label:     label,
+		Items:     tickets,
+		Templates: jiraTicketTemplates,
+		Size:      10,
+		Searcher: func(input string, index int) bool {
+			return matchesTicket(tickets[index], input)
+		},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return &tickets[index], nil
+}
+
+// matchesTicket reports whether every word of the input is part of the
+// ticket's key or summary, ignoring case
+func matchesTicket(ticket JiraTicket, input string) bool {
+	text := strings.ToLower(ticket.Key + " " + ticket.Summary)
+	for _, word := range strings.Fields(strings.ToLower(input)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// pickAndActOnJiraTicket lets the user pick a ticket, then track work on it or view it
+func pickAndActOnJiraTicket(ctx context.Context, label string, tickets []JiraTicket) error {
+	ticket, err := pickJiraTicket(label, tickets)
+	if err != nil {
+		return err
+	}
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("What would you like to do with %s?", ticket.Key),
+		Items: []string{"Track work on it", "View details", "Cancel"},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	switch index {
+	case 0:
+		runTrack(nil, "", "", 0, 0, 0, false, func(*config.Config) (*JiraTicket, error) {
+			return ticket, nil
+		})
+	case 1:
+		runTicketView(ctx, ticket.Key, false, nil, ".")
+	}
+	return nil
+}
+
+// pickJiraTickets asks the user to pick any number of the tickets
+func pickJiraTickets(label string, tickets []JiraTicket) ([]JiraTicket, error) {
+	items := make([]string, len(tickets))
+	for i, ticket := range tickets {
+		items[i] = fmt.Sprintf("%s  %s (%s)", ticket.Key, ticket.Summary, ticket.Status)
+	}
+	indexes, err := selectMany(label, items)
+	if err != nil {
+		return nil, err
+	}
+
+	var picked []JiraTicket
+	for _, i := range indexes {
+		picked = append(picked, tickets[i])
+	}
+	return picked, nil
+}
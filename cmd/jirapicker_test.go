@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+)
+
+func TestMatchesTicket(t *testing.T) {
+	ticket := JiraTicket{Key: "PROJ-12", Summary: "Fix flaky login test"}
+	for _, input := range []string{"", "proj-12", "login", "LOGIN flaky", "12 test"} {
+		if !matchesTicket(ticket, input) {
+			t.Errorf("Expected %q to match", input)
+		}
+	}
+	for _, input := range []string{"logout", "login logout", "PROJ-13"} {
+		if matchesTicket(ticket, input) {
+			t.Errorf("Expected %q not to match", input)
+		}
+	}
+}
+
+func TestJiraTicketTemplates(t *testing.T) {
+	tickets := []JiraTicket{
+		{Key: "PROJ-1", Summary: "Fix login", Status: "To Do", Type: "Bug", Assignee: "Sam"},
+		{Key: "PROJ-2", Summary: "Add export", Status: "In Progress", Parent: &JiraParent{Key: "PROJ-100", Summary: "Reports"}},
+	}
+	templates := map[string]string{
+		"active":   jiraTicketTemplates.Active,
+		"inactive": jiraTicketTemplates.Inactive,
+		"selected": jiraTicketTemplates.Selected,
+		"details":  jiraTicketTemplates.Details,
+	}
+	for name, text := range templates {
+		tpl, err := template.New(name).Funcs(promptui.FuncMap).Parse(text)
+		if err != nil {
+			t.Fatalf("Failed to parse the %s template: %v", name, err)
+		}
+		for _, ticket := range tickets {
+			var buf bytes.Buffer
+			if err := tpl.Execute(&buf, ticket); err != nil {
+				t.Errorf("Failed to render the %s template for %s: %v", name, ticket.Key, err)
+			}
+			if !strings.Contains(buf.String(), ticket.Key) {
+				t.Errorf("Expected the %s template to show %s, got %q", name, ticket.Key, buf.String())
+			}
+		}
+	}
+}
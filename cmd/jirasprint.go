@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/spf13/cobra"
+)
+
+// defaultStoryPointsField is the field story points are read from unless
+// another is configured, the Story point estimate of Jira Cloud
+const defaultStoryPointsField = "customfield_10016"
+
+// sprintIssue is an issue in an active sprint
+type sprintIssue struct {
+	Sprint          string     `json:"sprint,omitempty"` // Empty when the sprint isn't known
+	Ticket          JiraTicket `json:"ticket"`
+	StoryPoints     float64    `json:"story_points,omitempty"`
+	EstimateSeconds int64      `json:"estimate_seconds,omitempty"` // The original estimate
+	sprintStart     time.Time  // Zero when the sprint isn't known
+}
+
+// jiraSprintCmd represents the jira sprint command
+var jiraSprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "List the issues in your active sprints",
+	Long: `List the issues assigned to you in the active sprints of the boards listed
+in jira_boards, or in every open sprint when no boards are configured.
+Use --all to include issues assigned to others and --status to only show
+issues with the given statuses.
+
+Use 'plannet track --sprint' to pick the issue to work on from this list.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		all, _ := cmd.Flags().GetBool("all")
+		runJiraSprint(cmd.Context(), statuses, all)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraSprintCmd)
+	supportJSONOutput(jiraSprintCmd)
+	jiraSprintCmd.Flags().StringSlice("status", nil, "Only show issues with this status (can be repeated)")
+	jiraSprintCmd.Flags().Bool("all", false, "Include issues assigned to others")
+}
+
+// runJiraSprint lists the issues in the active sprints
+func runJiraSprint(ctx context.Context, statuses []string, all bool) {
+	log := logger.WithContext(ctx)
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	issues, err := fetchSprintIssues(ctx, cfg, !all)
+	if err != nil {
+		log.Error("Failed to get sprint issues: %v", err)
+		return
+	}
+	issues = filterSprintIssues(issues, statuses)
+	if jsonOutput() {
+		if issues == nil {
+			issues = []sprintIssue{}
+		}
+		printJSON(issues)
+		return
+	}
+	if len(issues) == 0 {
+		log.Info("No issues found in the active sprints.")
+		return
+	}
+
+	sprint := "-"
+	for _, issue := range issues {
+		if issue.Sprint != sprint {
+			sprint = issue.Sprint
+			name := sprint
+			if name == "" {
+				name = "Active sprints"
+			}
+			fmt.Printf("\n%s:\n", name)
+		}
+		fmt.Printf("  %s  [%s]  %s\n", issue.Ticket.Key, issue.Ticket.Status, issue.Ticket.Summary)
+	}
+}
+
+// fetchSprintIssues returns the issues in the active sprints of the
+// configured boards, or of every open sprint without configured boards.
+// With mine, only issues assigned to the current user are returned.
+func fetchSprintIssues(ctx context.Context, cfg *config.Config, mine bool) ([]sprintIssue, error) {
+	jql := ""
+	if mine {
+		jql = "assignee = currentUser()"
+	}
+
+	client := newJiraClient(cfg, cfg.JiraToken)
+	if len(cfg.JiraBoards) == 0 {
+		search := "sprint in openSprints()"
+		if jql != "" {
+			search += " AND " + jql
+		}
+		found, err := client.search(ctx, search+" ORDER BY rank")
+		if err != nil {
+			return nil, err
+		}
+		var issues []sprintIssue
+		for _, raw := range found {
+			issue, err := newSprintIssue(cfg, raw)
+			if err != nil {
+				return nil, err
+			}
+			issues = append(issues, issue)
+		}
+		return issues, nil
+	}
+
+	var issues []sprintIssue
+	seen := make(map[string]bool)
+	for _, board := range cfg.JiraBoards {
+		var sprints struct {
+			Values []struct {
+				ID        int       `json:"id"`
+				Name      string    `json:"name"`
+				StartDate time.Time `json:"startDate"`
+			} `json:"values"`
+		}
+		if err := client.do(ctx, "GET", fmt.Sprintf("/rest/agile/1.0/board/%d/sprint?state=active", board), nil, &sprints); err != nil {
+			return nil, fmt.Errorf("failed to get active sprints of board %d: %w", board, err)
+		}
+
+		for _, sprint := range sprints.Values {
+			path := fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprint.ID)
+			if jql != "" {
+				path += "?jql=" + url.QueryEscape(jql)
+			}
+			var result struct {
+				Issues []json.RawMessage `json:"issues"`
+			}
+			if err := client.do(ctx, "GET", path, nil, &result); err != nil {
+				return nil, fmt.Errorf("failed to get issues of sprint %s: %w", sprint.Name, err)
+			}
+			// Boards can share a sprint, whose issues are listed once
+			for _, raw := range result.Issues {
+				issue, err := newSprintIssue(cfg, raw)
+				if err != nil {
+					return nil, err
+				}
+				if seen[issue.Ticket.Key] {
+					continue
+				}
+				seen[issue.Ticket.Key] = true
+				issue.Sprint, issue.sprintStart = sprint.Name, sprint.StartDate
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// newSprintIssue converts an issue from the API to a sprintIssue, with its
+// original estimate and story points
+func newSprintIssue(cfg *config.Config, raw json.RawMessage) (sprintIssue, error) {
+	var issue jiraIssue
+	var fields struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(raw, &issue); err != nil {
+		return sprintIssue{}, fmt.Errorf("failed to parse Jira API response: %w", err)
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return sprintIssue{}, fmt.Errorf("failed to parse Jira API response: %w", err)
+	}
+
+	pointsField := cfg.JiraStoryPointsField
+	if pointsField == "" {
+		pointsField = defaultStoryPointsField
+	}
+	sprint := sprintIssue{Ticket: issue.ticket(cfg.JiraURL)}
+	// Either is null when it isn't set, which leaves it zero
+	json.Unmarshal(fields.Fields["timeoriginalestimate"], &sprint.EstimateSeconds)
+	json.Unmarshal(fields.Fields[pointsField], &sprint.StoryPoints)
+	return sprint, nil
+}
+
+// filterSprintIssues keeps the issues with one of the given statuses, or
+// every issue when no statuses are given
+func filterSprintIssues(issues []sprintIssue, statuses []string) []sprintIssue {
+	if len(statuses) == 0 {
+		return issues
+	}
+	var filtered []sprintIssue
+	for _, issue := range issues {
+		for _, status := range statuses {
+			if strings.EqualFold(issue.Ticket.Status, status) {
+				filtered = append(filtered, issue)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// pickSprintTicket asks the user to pick one of their unfinished issues in
+// the active sprints
+func pickSprintTicket(ctx context.Context, cfg *config.Config) (*JiraTicket, error) {
+	if cfg.JiraURL == "" || cfg.JiraToken == "" {
+		return nil, fmt.Errorf("Jira integration is not configured. Run 'plannet init' to set it up")
+	}
+
+	issues, err := fetchSprintIssues(ctx, cfg, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint issues: %w", err)
+	}
+	var open []sprintIssue
+	for _, issue := range issues {
+		if !strings.EqualFold(issue.Ticket.Status, "Done") {
+			open = append(open, issue)
+		}
+	}
+	if len(open) == 0 {
+		return nil, fmt.Errorf("no open issues assigned to you in the active sprints")
+	}
+
+	items := make([]string, len(open))
+	for i, issue := range open {
+		items[i] = fmt.Sprintf("%s [%s] %s", issue.Ticket.Key, issue.Ticket.Status, issue.Ticket.Summary)
+	}
+	prompt := promptui.Select{
+		Label: "Select an issue from the sprint",
+		Items: items,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(items[index]), strings.ToLower(input))
+		},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+	return &open[index].Ticket, nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestFetchSprintIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/agile/1.0/board/1/sprint", "/rest/agile/1.0/board/2/sprint":
+			if r.URL.Query().Get("state") != "active" {
+				t.Errorf("Expected only active sprints to be requested, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"values": [{"id": 7, "name": "Sprint 7", "startDate": "2024-05-06T09:00:00.000Z"}]}`))
+		case "/rest/agile/1.0/sprint/7/issue":
+			if jql := r.URL.Query().Get("jql"); jql != "assignee = currentUser()" {
+				t.Errorf("Unexpected JQL %q", jql)
+			}
+			w.Write([]byte(`{"issues": [
+				{"key": "PROJ-1", "fields": {"summary": "Fix login", "status": {"name": "In Progress"}, "timeoriginalestimate": 7200, "customfield_10016": 3}},
+				{"key": "PROJ-2", "fields": {"summary": "Add export", "status": {"name": "To Do"}}}
+			]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Both boards share the sprint, whose issues should be listed once
+	cfg := &config.Config{JiraURL: server.URL, JiraToken: "test-token", JiraBoards: []int{1, 2}}
+	issues, err := fetchSprintIssues(context.Background(), cfg, true)
+	if err != nil {
+		t.Fatalf("Failed to fetch sprint issues: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].Sprint != "Sprint 7" || issues[0].Ticket.Key != "PROJ-1" || issues[0].Ticket.Status != "In Progress" {
+		t.Errorf("Unexpected issue %+v", issues[0])
+	}
+	if issues[0].EstimateSeconds != 7200 || issues[0].StoryPoints != 3 || issues[1].EstimateSeconds != 0 {
+		t.Errorf("Expected the estimate and story points of PROJ-1 only, got %+v", issues)
+	}
+	if start := sprintStart(issues); !start.Equal(time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the start of the sprint, got %v", start)
+	}
+
+	filtered := filterSprintIssues(issues, []string{"to do"})
+	if len(filtered) != 1 || filtered[0].Ticket.Key != "PROJ-2" {
+		t.Errorf("Expected only PROJ-2 to match the status, got %+v", filtered)
+	}
+}
+
+func TestFetchSprintIssuesWithoutBoards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		if jql := r.URL.Query().Get("jql"); jql != "sprint in openSprints() ORDER BY rank" {
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+		w.Write([]byte(`{"issues": [{"key": "PROJ-3", "fields": {"summary": "Tidy up", "status": {"name": "To Do"}}}], "isLast": true}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JiraURL: server.URL, JiraToken: "test-token"}
+	issues, err := fetchSprintIssues(context.Background(), cfg, false)
+	if err != nil {
+		t.Fatalf("Failed to fetch sprint issues: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Sprint != "" || issues[0].Ticket.Key != "PROJ-3" {
+		t.Errorf("Unexpected issues %+v", issues)
+	}
+}
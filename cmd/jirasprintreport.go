@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// sprintReport compares the estimates of the issues in the active sprints
+// with the time spent on them
+type sprintReport struct {
+	Since            time.Time          `json:"since"`
+	Tickets          []sprintReportItem `json:"tickets"`
+	OtherTickets     []reportGroup      `json:"other_tickets"` // Time on tickets outside the sprints
+	EstimateMinutes  int                `json:"estimate_minutes"`
+	ActualMinutes    int                `json:"actual_minutes"`
+	SideQuestMinutes int                `json:"side_quest_minutes"` // Time without a ticket
+	TicketsOver      int                `json:"tickets_over_estimate"`
+
+	estimate, actual, sideQuests time.Duration
+}
+
+// sprintReportItem is the estimate of an issue and the time spent on it
+type sprintReportItem struct {
+	Ticket          string  `json:"ticket"`
+	Summary         string  `json:"summary"`
+	Status          string  `json:"status"`
+	Sprint          string  `json:"sprint,omitempty"`
+	StoryPoints     float64 `json:"story_points,omitempty"`
+	EstimateMinutes int     `json:"estimate_minutes,omitempty"`
+	ActualMinutes   int     `json:"actual_minutes"`
+	// VarianceMinutes is the time spent beyond the estimate, negative when
+	// under it, and nil without an estimate
+	VarianceMinutes *int `json:"variance_minutes,omitempty"`
+	Over            bool `json:"over_estimate"`
+
+	estimate, actual time.Duration
+}
+
+// jiraSprintReportCmd represents the jira sprint report command
+var jiraSprintReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Compare the estimates of the sprint with the time spent",
+	Long: `Compare the estimates of the issues in the active sprints with the time
+spent on them since the sprint started, and highlight the issues that went
+over their estimate.
+
+An issue's estimate is its original estimate or, when it has none, its
+story points times jira_hours_per_point. Story points are read from the
+field in jira_story_points_field, customfield_10016 unless set. Time is
+counted as 'plannet report --all' counts it: completed tracked work, and
+the time between commits no tracked work covers. The time spent on
+tickets outside the sprints and the side quests, time without any ticket,
+are totalled below the issues.
+
+The sprints start when the active sprints of jira_boards started; without
+boards, give the start with --since. Issues nobody has spent time on are
+left out unless --all is given.
+
+  plannet jira sprint report
+  plannet jira sprint report --since 2024-05-06 --all`,
+	Run: func(cmd *cobra.Command, args []string) {
+		since, _ := cmd.Flags().GetString("since")
+		all, _ := cmd.Flags().GetBool("all")
+		noGit, _ := cmd.Flags().GetBool("no-git")
+		runJiraSprintReport(cmd.Context(), since, all, noGit)
+	},
+}
+
+func init() {
+	jiraSprintCmd.AddCommand(jiraSprintReportCmd)
+	supportJSONOutput(jiraSprintReportCmd)
+	jiraSprintReportCmd.Flags().String("since", "", "Count time since a weekday, a date, a date and time, or a duration ago (default the start of the sprint)")
+	jiraSprintReportCmd.Flags().Bool("all", false, "Include the issues no time was spent on")
+	jiraSprintReportCmd.Flags().Bool("no-git", false, "Leave out the time between commits")
+}
+
+// runJiraSprintReport prints the planned and actual time of the active sprints
+func runJiraSprintReport(ctx context.Context, since string, all, noGit bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+	if cfg.JiraURL == "" || cfg.JiraToken == "" {
+		setExitCode(exitConfig)
+		printError("Error: Jira integration is not configured. Run 'plannet init' to set it up.")
+		return
+	}
+
+	issues, err := fetchSprintIssues(ctx, cfg, false)
+	if err != nil {
+		printError("Error getting sprint issues:", err)
+		return
+	}
+	if len(issues) == 0 {
+		fmt.Println("No issues found in the active sprints.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	var from time.Time
+	if since != "" {
+		if from, err = parseSince(since, time.Now()); err != nil {
+			setExitCode(exitUsage)
+			printError("Error:", err)
+			return
+		}
+	} else if from = sprintStart(issues); from.IsZero() {
+		setExitCode(exitUsage)
+		printError("Error: the start of the sprint is unknown without jira_boards. Give it with --since.")
+		return
+	}
+
+	trackedWork, err := getAllTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+	filter := workFilter{From: from}
+	entries := workReportEntries(filter.apply(trackedWork), "")
+	if cfg.GitIntegration && !noGit {
+		commitEntries, err := reportCommitEntries(cfg, true, from, "")
+		if err != nil {
+			printError("Error getting commits:", err)
+			return
+		}
+		entries = append(entries, filterReportEntries(uncoveredEntries(commitEntries, trackedWork), filter)...)
+	}
+
+	report := buildSprintReport(issues, entries, cfg.JiraHoursPerPoint, all)
+	report.Since = from
+	if jsonOutput() {
+		if report.Tickets == nil {
+			report.Tickets = []sprintReportItem{}
+		}
+		if report.OtherTickets == nil {
+			report.OtherTickets = []reportGroup{}
+		}
+		printJSON(report)
+		return
+	}
+	printSprintReport(report)
+}
+
+// sprintStart returns when the earliest of the sprints of the issues
+// started, or zero when none of their sprints is known
+func sprintStart(issues []sprintIssue) time.Time {
+	var start time.Time
+	for _, issue := range issues {
+		if !issue.sprintStart.IsZero() && (start.IsZero() || issue.sprintStart.Before(start)) {
+			start = issue.sprintStart
+		}
+	}
+	return start
+}
+
+// buildSprintReport compares the estimates of the issues with the time the
+// entries spent on them, in the order of the sprints. Without all, issues
+// no time was spent on are left out.
+func buildSprintReport(issues []sprintIssue, entries []reportEntry, hoursPerPoint float64, all bool) sprintReport {
+	groups := buildReport(entries, "ticket")
+	spent := make(map[string]reportGroup)
+	for _, group := range groups {
+		spent[strings.ToUpper(group.Name)] = group
+	}
+
+	var report sprintReport
+	for _, issue := range issues {
+		key := strings.ToUpper(issue.Ticket.Key)
+		actual := spent[key].duration
+		delete(spent, key)
+		if actual == 0 && !all {
+			continue
+		}
+
+		item := sprintReportItem{
+			Ticket:        issue.Ticket.Key,
+			Summary:       issue.Ticket.Summary,
+			Status:        issue.Ticket.Status,
+			Sprint:        issue.Sprint,
+			StoryPoints:   issue.StoryPoints,
+			ActualMinutes: int(actual.Minutes()),
+			estimate:      time.Duration(issue.EstimateSeconds) * time.Second,
+			actual:        actual,
+		}
+		if item.estimate == 0 && hoursPerPoint > 0 {
+			item.estimate = time.Duration(issue.StoryPoints * hoursPerPoint * float64(time.Hour))
+		}
+		if item.estimate > 0 {
+			variance := int((actual - item.estimate).Minutes())
+			item.EstimateMinutes = int(item.estimate.Minutes())
+			item.VarianceMinutes = &variance
+			item.Over = actual > item.estimate
+		}
+		if item.Over {
+			report.TicketsOver++
+		}
+		report.Tickets = append(report.Tickets, item)
+		report.estimate += item.estimate
+		report.actual += actual
+	}
+
+	// What is left was spent outside the sprints, in the order of time spent
+	for _, group := range groups {
+		if _, ok := spent[strings.ToUpper(group.Name)]; !ok {
+			continue
+		}
+		if group.Name == noTicketGroup {
+			report.sideQuests = group.duration
+			continue
+		}
+		report.OtherTickets = append(report.OtherTickets, group)
+	}
+
+	report.EstimateMinutes = int(report.estimate.Minutes())
+	report.ActualMinutes = int(report.actual.Minutes())
+	report.SideQuestMinutes = int(report.sideQuests.Minutes())
+	return report
+}
+
+// printSprintReport prints a sprint report as a table of the issues,
+// followed by the time spent outside the sprints
+func printSprintReport(report sprintReport) {
+	if len(report.Tickets) == 0 {
+		fmt.Printf("No time spent on the issues in the active sprints since %s.\n", report.Since.Format("Monday, 2006-01-02 15:04"))
+	} else {
+		if !output.Quiet() {
+			fmt.Printf("Estimates and time spent since %s:\n\n", report.Since.Format("Monday, 2006-01-02 15:04"))
+		}
+		overStyle := func(text string) string {
+			if strings.HasPrefix(text, "+") {
+				return output.Warning(text)
+			}
+			return text
+		}
+		table := output.NewTable(
+			output.Column{Heading: "ticket", Style: output.Ticket},
+			output.Column{Heading: "status"},
+			output.Column{Heading: "points"},
+			output.Column{Heading: "estimate"},
+			output.Column{Heading: "actual"},
+			output.Column{Heading: "variance", Style: overStyle},
+			output.Column{Heading: "summary", Shrink: true},
+		)
+		for _, item := range report.Tickets {
+			var points, estimate, variance string
+			if item.StoryPoints > 0 {
+				points = strconv.FormatFloat(item.StoryPoints, 'f', -1, 64)
+			}
+			if item.estimate > 0 {
+				estimate = formatDuration(item.estimate)
+				variance = formatVariance(item.actual - item.estimate)
+			}
+			table.AddRow(item.Ticket, item.Status, points, estimate, formatDuration(item.actual), variance, item.Summary)
+		}
+		if err := table.Print(); err != nil {
+			printError("Error printing report:", err)
+			return
+		}
+		fmt.Printf("\nEstimated: %s  Spent: %s\n", formatDuration(report.estimate), formatDuration(report.actual))
+		if report.TicketsOver > 0 {
+			fmt.Println(output.Warning(fmt.Sprintf("%d of %d tickets went over their estimate", report.TicketsOver, len(report.Tickets))))
+		}
+	}
+
+	if len(report.OtherTickets) > 0 {
+		fmt.Printf("\n%s\n", output.Heading("Outside the sprint:"))
+		for _, group := range report.OtherTickets {
+			fmt.Printf("  %s  %s\n", output.Ticket(group.Name), formatDuration(group.duration))
+		}
+	}
+	if report.sideQuests > 0 {
+		fmt.Printf("\n%s %s\n", output.SideQuest("Side quests:"), formatDuration(report.sideQuests))
+	}
+}
+
+// formatVariance formats the time spent beyond an estimate, with a "+"
+// when over it and a "-" when under it
+func formatVariance(d time.Duration) string {
+	switch {
+	case d >= time.Minute:
+		return "+" + formatDuration(d)
+	case d <= -time.Minute:
+		return "-" + formatDuration(-d)
+	}
+	return "0m"
+}
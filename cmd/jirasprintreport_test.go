@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildSprintReport(t *testing.T) {
+	issues := []sprintIssue{
+		{Ticket: JiraTicket{Key: "PROJ-1", Summary: "Fix login"}, EstimateSeconds: 7200},
+		{Ticket: JiraTicket{Key: "PROJ-2", Summary: "Add export"}, StoryPoints: 2},
+		{Ticket: JiraTicket{Key: "PROJ-3", Summary: "Tidy up"}, StoryPoints: 1},
+		{Ticket: JiraTicket{Key: "PROJ-4", Summary: "Untouched"}, EstimateSeconds: 3600},
+	}
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	entries := []reportEntry{
+		{Start: start, Duration: 3 * time.Hour, Tickets: []string{"PROJ-1"}},
+		{Start: start, Duration: 2 * time.Hour, Tickets: []string{"proj-2", "OTHER-9"}},
+		{Start: start, Duration: 30 * time.Minute, Tickets: []string{"PROJ-3"}},
+		{Start: start, Duration: 45 * time.Minute},
+	}
+
+	report := buildSprintReport(issues, entries, 4, false)
+	if len(report.Tickets) != 3 {
+		t.Fatalf("Expected the three tickets time was spent on, got %+v", report.Tickets)
+	}
+	over, under, unestimated := report.Tickets[0], report.Tickets[1], report.Tickets[2]
+	if over.Ticket != "PROJ-1" || !over.Over || over.VarianceMinutes == nil || *over.VarianceMinutes != 60 {
+		t.Errorf("Expected PROJ-1 to be an hour over its estimate, got %+v", over)
+	}
+	// Story points count as hours per point without an original estimate
+	if under.EstimateMinutes != 480 || under.ActualMinutes != 60 || under.Over || *under.VarianceMinutes != -420 {
+		t.Errorf("Expected PROJ-2 to be under its estimate from story points, got %+v", under)
+	}
+	if unestimated.EstimateMinutes != 240 || unestimated.ActualMinutes != 30 {
+		t.Errorf("Unexpected PROJ-3 %+v", unestimated)
+	}
+	if report.TicketsOver != 1 || report.EstimateMinutes != 840 || report.ActualMinutes != 270 {
+		t.Errorf("Unexpected totals %+v", report)
+	}
+	if len(report.OtherTickets) != 1 || report.OtherTickets[0].Name != "OTHER-9" || report.OtherTickets[0].DurationMinutes != 60 {
+		t.Errorf("Expected the hour on OTHER-9 outside the sprint, got %+v", report.OtherTickets)
+	}
+	if report.SideQuestMinutes != 45 {
+		t.Errorf("Expected 45 minutes of side quests, got %d", report.SideQuestMinutes)
+	}
+
+	// Without hours per point, story points aren't an estimate
+	report = buildSprintReport(issues, entries, 0, true)
+	if len(report.Tickets) != 4 || report.Tickets[1].VarianceMinutes != nil || report.Tickets[3].ActualMinutes != 0 {
+		t.Errorf("Expected every ticket, PROJ-2 without an estimate, got %+v", report.Tickets)
+	}
+}
+
+func TestFormatVariance(t *testing.T) {
+	tests := map[time.Duration]string{
+		90 * time.Minute:  "+1h 30m",
+		-45 * time.Minute: "-45m",
+		20 * time.Second:  "0m",
+	}
+	for d, want := range tests {
+		if got := formatVariance(d); got != want {
+			t.Errorf("formatVariance(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/security"
+)
+
+// jiraProjectKeyPattern matches Jira project keys, such as PROJ
+var jiraProjectKeyPattern = regexp.MustCompile(`^[A-Z0-9]+$`)
+
+// jiraOpenTicketsJQL finds the unfinished tickets assigned to the current user
+const jiraOpenTicketsJQL = "assignee = currentUser() AND statusCategory != Done ORDER BY updated DESC"
+
+func init() {
+	// Jira's client lives in this package, so it registers here rather
+	// than in internal/systems with the other systems
+	systems.Register(systems.Registration{
+		Name:  "jira",
+		Title: "Jira",
+		New: func(cfg *config.Config) (systems.TicketSystem, error) {
+			return newJiraSystem(cfg)
+		},
+	})
+	systems.OnRateLimitWait = reportRateLimitWait
+}
+
+// jiraSystem is Jira as a systems.TicketSystem, for the commands that work
+// with whichever ticket system is configured
+type jiraSystem struct {
+	cfg *config.Config
+}
+
+// newJiraSystem creates the Jira ticket system of the configuration
+func newJiraSystem(cfg *config.Config) (*jiraSystem, error) {
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		return nil, fmt.Errorf("Jira integration is not configured. Run 'plannet init' to set it up")
+	}
+	if cfg.JiraToken == "" {
+		return nil, fmt.Errorf("Jira token not found. Please run 'plannet init' to set up Jira integration")
+	}
+	return &jiraSystem{cfg: cfg}, nil
+}
+
+// Name returns "jira"
+func (j *jiraSystem) Name() string {
+	return "jira"
+}
+
+// List returns the unfinished tickets assigned to the current user
+func (j *jiraSystem) List(ctx context.Context) ([]systems.Ticket, error) {
+	tickets, err := searchJiraTickets(ctx, j.cfg, j.cfg.JiraToken, jiraOpenTicketsJQL)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]systems.Ticket, len(tickets))
+	for i, ticket := range tickets {
+		result[i] = ticket.systemTicket()
+	}
+	return result, nil
+}
+
+// View returns a ticket with its details, its description in markdown like
+// the other systems' descriptions
+func (j *jiraSystem) View(ctx context.Context, key string) (*systems.Ticket, error) {
+	ticket, err := fetchJiraTicket(ctx, j.cfg, j.cfg.JiraToken, key)
+	if err != nil {
+		return nil, err
+	}
+	result := ticket.systemTicket()
+	result.Description = jiraDescriptionMarkdown(j.cfg, result.Description)
+	if ticket.Parent != nil {
+		result.Parent = &systems.TicketLink{Key: ticket.Parent.Key, Summary: ticket.Parent.Summary, Type: ticket.Parent.Type}
+	}
+	for _, link := range ticket.Links {
+		result.Links = append(result.Links, systems.TicketLink{Relation: link.Relation, Key: link.Key, Summary: link.Summary, Status: link.Status})
+	}
+	for _, field := range ticket.CustomFields {
+		result.Fields = append(result.Fields, systems.Field{Name: field.Name, Value: field.Value})
+	}
+	for _, attachment := range ticket.Attachments {
+		result.Attachments = append(result.Attachments, systems.Attachment{
+			ID:       attachment.ID,
+			Filename: attachment.Filename,
+			Size:     attachment.Size,
+			MimeType: attachment.MimeType,
+			URL:      attachment.URL,
+		})
+	}
+	return &result, nil
+}
+
+// Create creates a ticket in the ticket's project, as a Task unless it has
+// another type, under its parent epic or ticket if it has one
+func (j *jiraSystem) Create(ctx context.Context, ticket systems.NewTicket) (*systems.Ticket, error) {
+	if ticket.Project == "" {
+		return nil, fmt.Errorf("no project given for the new ticket")
+	}
+	issueType := ticket.Type
+	if issueType == "" {
+		issueType = "Task"
+	}
+	fields := map[string]interface{}{}
+	for id, value := range ticket.Fields {
+		fields[id] = value
+	}
+	if len(ticket.Labels) > 0 {
+		fields["labels"] = ticket.Labels
+	}
+	if ticket.Parent != "" {
+		id, value := parentField(j.cfg, ticket.Parent)
+		fields[id] = value
+	}
+	key, err := createJiraTicket(ctx, j.cfg, j.cfg.JiraToken, strings.ToUpper(ticket.Project), issueType, ticket.Summary, ticket.Description, fields)
+	if err != nil {
+		return nil, err
+	}
+	return &systems.Ticket{
+		Key:         key,
+		Summary:     ticket.Summary,
+		Description: ticket.Description,
+		Type:        issueType,
+		URL:         j.cfg.JiraURL + "/browse/" + key,
+		Labels:      ticket.Labels,
+	}, nil
+}
+
+// Comment adds a comment to a ticket
+func (j *jiraSystem) Comment(ctx context.Context, key, body string) error {
+	return addJiraComment(ctx, j.cfg, j.cfg.JiraToken, key, body)
+}
+
+// Transition moves a ticket through the transition with the given name, or
+// to the status with that name
+func (j *jiraSystem) Transition(ctx context.Context, key, status string) error {
+	_, err := transitionJiraTicket(ctx, newJiraClient(j.cfg, j.cfg.JiraToken), key, status)
+	return err
+}
+
+// ResolveKey returns a ticket key in upper case, such as PROJ-123
+func (j *jiraSystem) ResolveKey(key string) (string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if err := security.ValidateTicketKey(key); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Search returns the tickets matching a JQL query
+func (j *jiraSystem) Search(ctx context.Context, jql string) ([]systems.Ticket, error) {
+	tickets, err := searchJiraTickets(ctx, j.cfg, j.cfg.JiraToken, jql)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]systems.Ticket, len(tickets))
+	for i, ticket := range tickets {
+		result[i] = ticket.systemTicket()
+	}
+	return result, nil
+}
+
+// Children returns the tickets of an epic
+func (j *jiraSystem) Children(ctx context.Context, key string) ([]systems.Ticket, error) {
+	return j.Search(ctx, epicJQL(j.cfg, key))
+}
+
+// Transitions returns the transitions of its workflow a ticket can currently take
+func (j *jiraSystem) Transitions(ctx context.Context, key string) ([]systems.Transition, error) {
+	return fetchJiraTransitions(ctx, newJiraClient(j.cfg, j.cfg.JiraToken), key)
+}
+
+// DownloadAttachment saves an attachment into dir and returns its path
+func (j *jiraSystem) DownloadAttachment(ctx context.Context, attachment systems.Attachment, dir string) (string, error) {
+	return downloadAttachment(ctx, newJiraClient(j.cfg, j.cfg.JiraToken), JiraAttachment{
+		ID:       attachment.ID,
+		Filename: attachment.Filename,
+		Size:     attachment.Size,
+		MimeType: attachment.MimeType,
+		URL:      attachment.URL,
+	}, dir)
+}
+
+// promptTicketDetails asks for what Jira needs of a new ticket that it
+// doesn't have yet: its project key, issue type, the custom fields in the
+// configuration and, optionally, its parent
+func (j *jiraSystem) promptTicketDetails(ctx context.Context, ticket *systems.NewTicket) error {
+	if ticket.Project == "" {
+		projectKey := ""
+		if len(j.cfg.TicketPrefixes) > 0 {
+			projectKey = strings.TrimRight(j.cfg.TicketPrefixes[0], "-")
+		}
+		projectPrompt := promptui.Prompt{
+			Label:     "Enter project key (e.g., PROJ)",
+			Default:   projectKey,
+			AllowEdit: true,
+			Validate: func(input string) error {
+				if input == "" {
+					return fmt.Errorf("project key cannot be empty")
+				}
+				// Project keys are typically uppercase letters and numbers
+				if !jiraProjectKeyPattern.MatchString(input) {
+					return fmt.Errorf("project key must contain only uppercase letters and numbers")
+				}
+				return nil
+			},
+		}
+		project, err := projectPrompt.Run()
+		if err != nil {
+			return err
+		}
+		ticket.Project = project
+	}
+
+	if ticket.Type == "" {
+		issueTypePrompt := promptui.Select{
+			Label: "Select issue type",
+			Items: []string{"Task", "Bug", "Story", "Epic"},
+		}
+		_, issueType, err := issueTypePrompt.Run()
+		if err != nil {
+			return err
+		}
+		ticket.Type = issueType
+	}
+
+	// Ask for the configured custom fields the ticket doesn't set
+	var missing []config.JiraField
+	for _, field := range j.cfg.JiraFields {
+		if _, ok := ticket.Fields[field.ID]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	custom, err := promptJiraFields(ctx, newJiraClient(j.cfg, j.cfg.JiraToken), missing)
+	if err != nil {
+		return err
+	}
+	if len(custom) > 0 && ticket.Fields == nil {
+		ticket.Fields = make(map[string]interface{})
+	}
+	for id, value := range custom {
+		ticket.Fields[id] = value
+	}
+
+	if ticket.Parent == "" {
+		parentPrompt := promptui.Prompt{
+			Label: "Enter parent or epic key (optional)",
+			Validate: func(input string) error {
+				if input == "" {
+					return nil
+				}
+				return security.ValidateTicketKey(input)
+			},
+		}
+		parent, err := parentPrompt.Run()
+		if err != nil {
+			return err
+		}
+		ticket.Parent = parent
+	}
+	return nil
+}
+
+// systemTicket converts a JiraTicket to a systems.Ticket
+func (t JiraTicket) systemTicket() systems.Ticket {
+	return systems.Ticket{
+		Key:         t.Key,
+		Summary:     t.Summary,
+		Description: t.Description,
+		Status:      t.Status,
+		Type:        t.Type,
+		Priority:    t.Priority,
+		Assignee:    t.Assignee,
+		URL:         t.URL,
+	}
+}
+
+// jiraTicketFromSystem converts a ticket of any system to a JiraTicket, the
+// form tracked work and the ticket pickers take tickets in
+func jiraTicketFromSystem(ticket systems.Ticket) JiraTicket {
+	return JiraTicket{
+		Key:         ticket.Key,
+		Summary:     ticket.Summary,
+		Description: ticket.Description,
+		Status:      ticket.Status,
+		Type:        ticket.Type,
+		Priority:    ticket.Priority,
+		Assignee:    ticket.Assignee,
+		URL:         ticket.URL,
+	}
+}
+
+// usesJira reports whether the configuration selects Jira as its ticket system
+func usesJira(cfg *config.Config) bool {
+	return systems.SelectedName(cfg) == "jira"
+}
+
+// jiraTicketURL returns the address of a Jira ticket, or "" when Jira isn't
+// the configured ticket system
+func jiraTicketURL(cfg *config.Config, key string) string {
+	if !usesJira(cfg) || cfg.JiraURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(cfg.JiraURL, "/") + "/browse/" + key
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+)
+
+func TestJiraSystemIsTheDefault(t *testing.T) {
+	cfg := &config.Config{JiraURL: "https://example.atlassian.net", JiraUser: "sam@example.com", JiraToken: "test-token"}
+	system, err := systems.New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create the default ticket system: %v", err)
+	}
+	if system.Name() != "jira" || !usesJira(cfg) {
+		t.Errorf("Expected Jira by default, got %s", system.Name())
+	}
+
+	cfg.TicketSystem = "github"
+	if usesJira(cfg) {
+		t.Error("Expected GitHub to be used when selected")
+	}
+
+	if _, err := systems.New(&config.Config{}); err == nil {
+		t.Error("Expected an error when Jira is not configured")
+	}
+}
+
+func TestJiraSystemResolveKey(t *testing.T) {
+	system := &jiraSystem{cfg: &config.Config{}}
+	if key, err := system.ResolveKey(" proj-12 "); err != nil || key != "PROJ-12" {
+		t.Errorf("ResolveKey() = %q, %v, want PROJ-12", key, err)
+	}
+	if _, err := system.ResolveKey("octo/app#12"); err == nil {
+		t.Error("Expected an error for a key that is not Jira's")
+	}
+}
+
+func TestJiraSystemList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("jql") != jiraOpenTicketsJQL {
+			t.Errorf("Unexpected search %q", r.URL.Query().Get("jql"))
+		}
+		w.Write([]byte(`{"issues": [{"key": "PROJ-1", "fields": {"summary": "Fix login",
+			"status": {"name": "In Progress"}, "issuetype": {"name": "Bug"}, "assignee": {"displayName": "Sam"}}}]}`))
+	}))
+	defer server.Close()
+
+	system, err := newJiraSystem(&config.Config{JiraURL: server.URL, JiraUser: "sam@example.com", JiraToken: "test-token"})
+	if err != nil {
+		t.Fatalf("Failed to create Jira system: %v", err)
+	}
+	tickets, err := system.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list tickets: %v", err)
+	}
+	want := []systems.Ticket{{Key: "PROJ-1", Summary: "Fix login", Status: "In Progress", Type: "Bug",
+		Assignee: "Sam", URL: server.URL + "/browse/PROJ-1"}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+	if back := jiraTicketFromSystem(tickets[0]); back.Key != "PROJ-1" || back.URL != want[0].URL {
+		t.Errorf("Unexpected converted ticket %+v", back)
+	}
+}
+
+func TestJiraSystemView(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-2" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"key": "PROJ-2", "fields": {"summary": "Fix logout", "priority": {"name": "High"},
+			"parent": {"key": "PROJ-1", "fields": {"summary": "Sessions", "issuetype": {"name": "Epic"}}},
+			"attachment": [{"id": "10", "filename": "trace.log", "size": 2048, "mimeType": "text/plain", "content": "https://example.com/10"}]}}`))
+	}))
+	defer server.Close()
+
+	system, err := newJiraSystem(&config.Config{JiraURL: server.URL, JiraUser: "sam@example.com", JiraToken: "test-token"})
+	if err != nil {
+		t.Fatalf("Failed to create Jira system: %v", err)
+	}
+	ticket, err := system.View(context.Background(), "PROJ-2")
+	if err != nil {
+		t.Fatalf("Failed to view ticket: %v", err)
+	}
+	if ticket.Priority != "High" {
+		t.Errorf("Expected priority High, got %q", ticket.Priority)
+	}
+	if want := (&systems.TicketLink{Key: "PROJ-1", Summary: "Sessions", Type: "Epic"}); !reflect.DeepEqual(ticket.Parent, want) {
+		t.Errorf("Parent = %+v, want %+v", ticket.Parent, want)
+	}
+	want := []systems.Attachment{{ID: "10", Filename: "trace.log", Size: 2048, MimeType: "text/plain", URL: "https://example.com/10"}}
+	if !reflect.DeepEqual(ticket.Attachments, want) {
+		t.Errorf("Attachments = %+v, want %+v", ticket.Attachments, want)
+	}
+}
+
+func TestRunJiraListSearchesThroughTheTicketSystem(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	resetExitCode(t)
+	useJSONOutput(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if jql := r.URL.Query().Get("jql"); jql != "project = PROJ" {
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+		w.Write([]byte(`{"issues": [{"key": "PROJ-1", "fields": {"summary": "Fix login"}}]}`))
+	}))
+	defer server.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.JiraURL, cfg.JiraUser, cfg.JiraToken = server.URL, "sam@example.com", "test-token"
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	out := captureStdout(t, func() { runJiraList(context.Background(), "", "project = PROJ", "", true, nil) })
+	var tickets []systems.Ticket
+	if err := json.Unmarshal([]byte(out), &tickets); err != nil {
+		t.Fatalf("Expected the tickets as JSON, got %q: %v", out, err)
+	}
+	if len(tickets) != 1 || tickets[0].Key != "PROJ-1" {
+		t.Errorf("Unexpected tickets %+v", tickets)
+	}
+
+	// Systems that can't search reject JQL rather than ignoring it
+	cfg.TicketSystem, cfg.GitHubToken = "github", "test-token"
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	captureStdout(t, func() { runJiraList(context.Background(), "", "project = PROJ", "", true, nil) })
+	if exitCode != exitUsage {
+		t.Errorf("Expected a usage error searching GitHub with JQL, got exit code %d", exitCode)
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"gopkg.in/yaml.v3"
+)
+
+// jiraTemplatesDir is the directory, in the data directory, searched for
+// Jira ticket templates given by name
+const jiraTemplatesDir = "jira-templates"
+
+// jiraTemplate is a recurring shape of Jira ticket, read from a YAML or JSON
+// file. The summary and description can hold {{placeholders}}, which are
+// asked for when the ticket is created.
+type jiraTemplate struct {
+	Project     string                 `yaml:"project"`
+	Type        string                 `yaml:"type"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description"`
+	Labels      []string               `yaml:"labels"`
+	Components  []string               `yaml:"components"`
+	Priority    string                 `yaml:"priority"`
+	Parent      string                 `yaml:"parent"`
+	Fields      map[string]interface{} `yaml:"fields"` // Any other fields, by ID
+}
+
+// placeholderPattern matches a {{placeholder}} in a template
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([\w .-]+?)\s*\}\}`)
+
+// loadJiraTemplate reads a template from a file, or by name from the
+// templates directory, with or without its .yaml, .yml or .json extension
+func loadJiraTemplate(name string) (*jiraTemplate, error) {
+	paths := []string{name}
+	if !strings.ContainsRune(name, os.PathSeparator) {
+		dataDir, err := getDataDir()
+		if err != nil {
+			return nil, err
+		}
+		for _, ext := range []string{"", ".yaml", ".yml", ".json"} {
+			paths = append(paths, filepath.Join(dataDir, jiraTemplatesDir, name+ext))
+		}
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+		// JSON is valid YAML, so both are read the same way
+		var tpl jiraTemplate
+		if err := yaml.Unmarshal(data, &tpl); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		return &tpl, nil
+	}
+	return nil, fmt.Errorf("template %q not found", name)
+}
+
+// fields returns the fields the template sets, other than the project,
+// type, summary and description
+func (t *jiraTemplate) fields() map[string]interface{} {
+	fields := make(map[string]interface{})
+	for id, value := range t.Fields {
+		fields[id] = value
+	}
+	if len(t.Labels) > 0 {
+		fields["labels"] = t.Labels
+	}
+	if len(t.Components) > 0 {
+		var components []map[string]string
+		for _, name := range t.Components {
+			components = append(components, map[string]string{"name": name})
+		}
+		fields["components"] = components
+	}
+	if t.Priority != "" {
+		fields["priority"] = map[string]string{"name": t.Priority}
+	}
+	return fields
+}
+
+// templatePlaceholders returns the names of the placeholders in the texts,
+// in order of first appearance
+func templatePlaceholders(texts ...string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// fillPlaceholders replaces the placeholders in text with their values;
+// placeholders without a value are left as they are
+func fillPlaceholders(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// parsePlaceholderValues parses name=value pairs
+func parsePlaceholderValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid value %q, expected name=value", pair)
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+	return values, nil
+}
+
+// promptPlaceholders asks for the value of each placeholder that has none yet
+func promptPlaceholders(names []string, values map[string]string) error {
+	for _, name := range names {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		prompt := promptui.Prompt{
+			Label: "Enter " + name,
+		}
+		value, err := prompt.Run()
+		if err != nil {
+			return err
+		}
+		values[name] = value
+	}
+	return nil
+}
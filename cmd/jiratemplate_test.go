@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadJiraTemplate(t *testing.T) {
+	setupTest(t)
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		t.Fatalf("Failed to get data directory: %v", err)
+	}
+	dir := filepath.Join(dataDir, jiraTemplatesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create templates directory: %v", err)
+	}
+	yamlTemplate := `project: PROJ
+type: Bug
+summary: "[{{ area }}] "
+labels: [bug, triage]
+components: [Web]
+priority: High
+fields:
+  customfield_10016: 1
+description: |
+  Steps to reproduce:
+  {{steps}}
+
+  Seen in {{ area }}.
+`
+	if err := os.WriteFile(filepath.Join(dir, "bug.yaml"), []byte(yamlTemplate), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	jsonPath := filepath.Join(t.TempDir(), "chore.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"project": "OPS", "type": "Task", "labels": ["chore"]}`), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	tpl, err := loadJiraTemplate("bug")
+	if err != nil {
+		t.Fatalf("Failed to load template by name: %v", err)
+	}
+	if tpl.Project != "PROJ" || tpl.Type != "Bug" {
+		t.Errorf("Unexpected template %+v", tpl)
+	}
+	want := map[string]interface{}{
+		"customfield_10016": 1,
+		"labels":            []string{"bug", "triage"},
+		"components":        []map[string]string{{"name": "Web"}},
+		"priority":          map[string]string{"name": "High"},
+	}
+	if got := tpl.fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected fields %v, got %v", want, got)
+	}
+
+	names := templatePlaceholders(tpl.Summary, tpl.Description)
+	if !reflect.DeepEqual(names, []string{"area", "steps"}) {
+		t.Errorf("Unexpected placeholders %v", names)
+	}
+	values, err := parsePlaceholderValues([]string{"area=Checkout", "steps=Pay with a=b coupon"})
+	if err != nil {
+		t.Fatalf("Failed to parse values: %v", err)
+	}
+	if got := fillPlaceholders(tpl.Description, values); got != "Steps to reproduce:\nPay with a=b coupon\n\nSeen in Checkout.\n" {
+		t.Errorf("Unexpected description %q", got)
+	}
+	if got := fillPlaceholders("{{area}} {{other}}", values); got != "Checkout {{other}}" {
+		t.Errorf("Expected unknown placeholders to be kept, got %q", got)
+	}
+	if _, err := parsePlaceholderValues([]string{"steps"}); err == nil {
+		t.Error("Expected a value without a name to fail")
+	}
+
+	if tpl, err = loadJiraTemplate(jsonPath); err != nil || tpl.Project != "OPS" || tpl.Labels[0] != "chore" {
+		t.Errorf("Failed to load JSON template by path: %+v (%v)", tpl, err)
+	}
+	if _, err := loadJiraTemplate("missing"); err == nil {
+		t.Error("Expected a missing template to fail")
+	}
+}
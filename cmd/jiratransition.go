@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plannet-ai/plannet/internal/systems"
+)
+
+// jiraTransition is a transition of a Jira ticket as returned by the Jira REST API
+type jiraTransition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		Name string `json:"name"`
+	} `json:"to"`
+}
+
+// fetchJiraTransitions returns the transitions a ticket can currently take
+func fetchJiraTransitions(ctx context.Context, client *jiraClient, ticketKey string) ([]systems.Transition, error) {
+	var result struct {
+		Transitions []jiraTransition `json:"transitions"`
+	}
+	if err := client.do(ctx, "GET", client.api("/issue/"+ticketKey+"/transitions"), nil, &result); err != nil {
+		return nil, err
+	}
+	transitions := make([]systems.Transition, len(result.Transitions))
+	for i, t := range result.Transitions {
+		transitions[i] = systems.Transition{ID: t.ID, Name: t.Name, To: t.To.Name}
+	}
+	return transitions, nil
+}
+
+// transitionJiraTicket moves a ticket through the transition with the given
+// name, or to the status with that name, and returns the new status
+func transitionJiraTicket(ctx context.Context, client *jiraClient, ticketKey, name string) (string, error) {
+	transitions, err := fetchJiraTransitions(ctx, client, ticketKey)
+	if err != nil {
+		return "", err
+	}
+	transition, ok := findTransition(transitions, name)
+	if !ok {
+		return "", fmt.Errorf("no transition %q is available, expected one of: %s", name, describeTransitions(transitions))
+	}
+	if err := applyJiraTransition(ctx, client, ticketKey, *transition); err != nil {
+		return "", err
+	}
+	return transition.To, nil
+}
+
+// applyJiraTransition moves a ticket through a transition
+func applyJiraTransition(ctx context.Context, client *jiraClient, ticketKey string, transition systems.Transition) error {
+	body := map[string]interface{}{"transition": map[string]string{"id": transition.ID}}
+	return client.do(ctx, "POST", client.api("/issue/"+ticketKey+"/transitions"), body, nil)
+}
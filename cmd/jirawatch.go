@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/security"
+	"github.com/spf13/cobra"
+)
+
+// jiraWatchCmd represents the jira watch command
+var jiraWatchCmd = &cobra.Command{
+	Use:   "watch [ticket]",
+	Short: "Watch a Jira ticket",
+	Long:  `Start watching a Jira ticket, to be notified of its changes.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runJiraWatch(cmd.Context(), args[0], true)
+	},
+}
+
+// jiraUnwatchCmd represents the jira unwatch command
+var jiraUnwatchCmd = &cobra.Command{
+	Use:   "unwatch [ticket]",
+	Short: "Stop watching a Jira ticket",
+	Long:  `Stop watching a Jira ticket.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runJiraWatch(cmd.Context(), args[0], false)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraWatchCmd)
+	jiraCmd.AddCommand(jiraUnwatchCmd)
+}
+
+// runJiraWatch starts or stops watching a Jira ticket
+func runJiraWatch(ctx context.Context, ticketKey string, watch bool) {
+	log := logger.WithContext(ctx)
+
+	// Validate ticket key
+	if err := security.ValidateTicketKey(ticketKey); err != nil {
+		log.Error("Invalid ticket key: %v", err)
+		setExitCode(exitUsage)
+		return
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	client := newJiraClient(cfg, cfg.JiraToken)
+	if watch {
+		if err := watchJiraTicket(ctx, client, ticketKey); err != nil {
+			log.Error("Failed to watch %s: %v", ticketKey, err)
+			return
+		}
+		log.Info("Watching %s", ticketKey)
+		return
+	}
+	if err := unwatchJiraTicket(ctx, client, ticketKey); err != nil {
+		log.Error("Failed to unwatch %s: %v", ticketKey, err)
+		return
+	}
+	log.Info("Stopped watching %s", ticketKey)
+}
+
+// watchJiraTicket adds the current user to the watchers of a ticket
+func watchJiraTicket(ctx context.Context, client *jiraClient, ticketKey string) error {
+	me, err := client.findUser(ctx, "me")
+	if err != nil {
+		return err
+	}
+	return client.do(ctx, "POST", client.api("/issue/"+ticketKey+"/watchers"), client.userID(*me), nil)
+}
+
+// unwatchJiraTicket removes the current user from the watchers of a ticket
+func unwatchJiraTicket(ctx context.Context, client *jiraClient, ticketKey string) error {
+	me, err := client.findUser(ctx, "me")
+	if err != nil {
+		return err
+	}
+	// The user is identified by a query parameter, named like the body field
+	// of other requests except on Jira Server, where it is "username"
+	param := client.userField()
+	if param == "name" {
+		param = "username"
+	}
+	return client.do(ctx, "DELETE", client.api("/issue/"+ticketKey+"/watchers?"+param+"="+url.QueryEscape(client.userID(*me))), nil, nil)
+}
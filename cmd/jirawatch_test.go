@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestWatchJiraTicket(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/2/myself":
+			w.Write([]byte(`{"name": "jdoe", "displayName": "Jane Doe"}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/PROJ-1/watchers":
+			var name string
+			json.NewDecoder(r.Body).Decode(&name)
+			requests = append(requests, "watch "+name)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "DELETE" && r.URL.Path == "/rest/api/2/issue/PROJ-1/watchers":
+			requests = append(requests, "unwatch "+r.URL.Query().Get("username"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// Jira Server identifies users by name rather than account ID
+	client := newJiraClient(&config.Config{JiraURL: server.URL, JiraAPIVersion: 2}, "test-token")
+	if err := watchJiraTicket(context.Background(), client, "PROJ-1"); err != nil {
+		t.Fatalf("Failed to watch: %v", err)
+	}
+	if err := unwatchJiraTicket(context.Background(), client, "PROJ-1"); err != nil {
+		t.Fatalf("Failed to unwatch: %v", err)
+	}
+	if len(requests) != 2 || requests[0] != "watch jdoe" || requests[1] != "unwatch jdoe" {
+		t.Errorf("Unexpected requests %v", requests)
+	}
+}
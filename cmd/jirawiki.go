@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+var (
+	wikiHeading   = regexp.MustCompile(`^\s*h([1-6])\.\s+`)
+	wikiQuote     = regexp.MustCompile(`^\s*bq\.\s+`)
+	wikiList      = regexp.MustCompile(`^\s*([*#-]+)\s+`)
+	wikiRule      = regexp.MustCompile(`^\s*-{4,}\s*$`)
+	wikiCode      = regexp.MustCompile(`\{(code|noformat)(?::([^}]*))?\}`)
+	wikiBold      = regexp.MustCompile(`(^|[\s(\[])\*([^\s*](?:[^*]*[^\s*])?)\*($|[\s).,:;!?\]])`)
+	wikiItalic    = regexp.MustCompile(`(^|[\s(\[])_([^\s_](?:[^_]*[^\s_])?)_($|[\s).,:;!?\]])`)
+	wikiMonospace = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	wikiLink      = regexp.MustCompile(`\[([^|\]]+)\|([^\]]+)\]`)
+	wikiBareLink  = regexp.MustCompile(`\[((?:https?|mailto):[^\]]+)\]`)
+	wikiColor     = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+)
+
+// jiraAPIVersion returns the version of the Jira REST API the configuration uses
+func jiraAPIVersion(cfg *config.Config) int {
+	if cfg.JiraAPIVersion == 0 {
+		return defaultJiraAPIVersion
+	}
+	return cfg.JiraAPIVersion
+}
+
+// jiraDescriptionMarkdown returns a description from Jira as markdown.
+// Version 3 of the API gives ADF documents, which are already rendered as
+// markdown; version 2 gives wiki markup, which is converted.
+func jiraDescriptionMarkdown(cfg *config.Config, description string) string {
+	if jiraAPIVersion(cfg) != 2 {
+		return description
+	}
+	return wikiToMarkdown(description)
+}
+
+// wikiToMarkdown converts Jira wiki markup to markdown: headings, quotes,
+// lists, rules, code blocks, emphasis, monospace and links. Anything else
+// is kept as it is.
+func wikiToMarkdown(text string) string {
+	var lines []string
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		// {code} and {noformat} open and close code blocks, with the
+		// text around them on lines of their own
+		hadMacro := false
+		for {
+			loc := wikiCode.FindStringSubmatchIndex(line)
+			if loc == nil {
+				break
+			}
+			if before := line[:loc[0]]; strings.TrimSpace(before) != "" {
+				lines = append(lines, convertWikiLine(before, inCode))
+			}
+			fence := "```"
+			if !inCode && loc[4] >= 0 {
+				fence += wikiCodeLanguage(line[loc[4]:loc[5]])
+			}
+			lines = append(lines, fence)
+			inCode = !inCode
+			hadMacro = true
+			line = line[loc[1]:]
+		}
+		if hadMacro && strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, convertWikiLine(line, inCode))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wikiCodeLanguage returns the language of a {code:...} macro, given as
+// its first parameter or as language=...
+func wikiCodeLanguage(params string) string {
+	for _, param := range strings.Split(params, "|") {
+		if name, value, ok := strings.Cut(param, "="); ok {
+			if strings.TrimSpace(name) == "language" {
+				return strings.TrimSpace(value)
+			}
+			continue
+		}
+		return strings.TrimSpace(param)
+	}
+	return ""
+}
+
+// convertWikiLine converts a line of wiki markup to markdown; lines of
+// code are kept as they are
+func convertWikiLine(line string, inCode bool) string {
+	if inCode {
+		return line
+	}
+	switch {
+	case wikiRule.MatchString(line):
+		return "---"
+	case wikiHeading.MatchString(line):
+		m := wikiHeading.FindStringSubmatch(line)
+		level := int(m[1][0] - '0')
+		return strings.Repeat("#", level) + " " + convertWikiInline(line[len(m[0]):])
+	case wikiQuote.MatchString(line):
+		return "> " + convertWikiInline(wikiQuote.ReplaceAllString(line, ""))
+	case wikiList.MatchString(line):
+		m := wikiList.FindStringSubmatch(line)
+		markers := m[1]
+		bullet := "-"
+		if strings.HasSuffix(markers, "#") {
+			bullet = "1."
+		}
+		indent := strings.Repeat("  ", len(markers)-1)
+		return fmt.Sprintf("%s%s %s", indent, bullet, convertWikiInline(line[len(m[0]):]))
+	}
+	if strings.HasPrefix(strings.TrimSpace(line), "||") {
+		line = strings.ReplaceAll(line, "||", "|")
+	}
+	return convertWikiInline(line)
+}
+
+// convertWikiInline converts the inline markup of wiki text to markdown
+func convertWikiInline(text string) string {
+	// Monospace text is taken out first, so that nothing inside it is converted
+	var code []string
+	text = wikiMonospace.ReplaceAllStringFunc(text, func(s string) string {
+		code = append(code, "`"+wikiMonospace.FindStringSubmatch(s)[1]+"`")
+		return fmt.Sprintf("\x00%d\x00", len(code)-1)
+	})
+
+	text = wikiColor.ReplaceAllString(text, "")
+	text = wikiLink.ReplaceAllString(text, "[$1]($2)")
+	text = wikiBareLink.ReplaceAllString(text, "<$1>")
+	// Bold is converted before italics, whose markdown it would take for bold
+	text = replaceAllAdjacent(wikiBold, text, "$1**$2**$3")
+	text = replaceAllAdjacent(wikiItalic, text, "$1*$2*$3")
+
+	for i, span := range code {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), span, 1)
+	}
+	return text
+}
+
+// replaceAllAdjacent replaces the matches of re, including those that only
+// start where the previous match ended, such as "*a* *b*", whose matches
+// share the space between them
+func replaceAllAdjacent(re *regexp.Regexp, text, repl string) string {
+	for {
+		replaced := re.ReplaceAllString(text, repl)
+		if replaced == text {
+			return text
+		}
+		text = replaced
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestWikiToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		wiki string
+		want string
+	}{
+		{"heading", "h2. Steps", "## Steps"},
+		{"emphasis", "A *bold* and _italic_ *word* here", "A **bold** and *italic* **word** here"},
+		{"snake case", "Set max_retry_count", "Set max_retry_count"},
+		{"monospace", "Run {{make *all*}}", "Run `make *all*`"},
+		{"links", "See [the docs|https://example.com] or [https://example.org]", "See [the docs](https://example.com) or <https://example.org>"},
+		{"lists", "* one\n** two\n# first\n#* nested", "- one\n  - two\n1. first\n  - nested"},
+		{"quote and rule", "bq. Quoted\n----", "> Quoted\n---"},
+		{"code", "Before\n{code:language=go}\nx := *p\n{code}\nAfter", "Before\n```go\nx := *p\n```\nAfter"},
+		{"inline code macro", "{noformat}raw *text*{noformat}", "```\nraw *text*\n```"},
+		{"colors", "{color:red}Urgent{color}", "Urgent"},
+		{"table header", "||Name||Value||\n|a|b|", "|Name|Value|\n|a|b|"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wikiToMarkdown(tt.wiki); got != tt.want {
+				t.Errorf("wikiToMarkdown(%q) = %q, want %q", tt.wiki, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJiraDescriptionMarkdown(t *testing.T) {
+	if got := jiraDescriptionMarkdown(&config.Config{}, "*kept*"); got != "*kept*" {
+		t.Errorf("jiraDescriptionMarkdown() in version 3 = %q, want the description as it is", got)
+	}
+	if got := jiraDescriptionMarkdown(&config.Config{JiraAPIVersion: 2}, "*bold*"); got != "**bold**" {
+		t.Errorf("jiraDescriptionMarkdown() in version 2 = %q, want markdown", got)
+	}
+}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/spf13/cobra"
+)
+
+// jiraTimeLayout is the timestamp format the Jira API expects
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// worklogChange is a worklog to create, or to update when Existing is set,
+// for one ticket of a piece of completed work
+type worklogChange struct {
+	Work     *TrackedWork
+	TicketID string
+	Seconds  int
+	Existing *Worklog
+}
+
+// worklogList returns the worklogs of work pushed to one service, such as
+// its Jira worklogs
+type worklogList func(work *TrackedWork) *[]Worklog
+
+// jiraWorklogs returns the worklogs of work pushed to Jira
+func jiraWorklogs(work *TrackedWork) *[]Worklog {
+	return &work.Worklogs
+}
+
+// jiraWorklogCmd represents the jira worklog command
+var jiraWorklogCmd = &cobra.Command{
+	Use:   "worklog",
+	Short: "Log tracked time to Jira",
+}
+
+// jiraWorklogPushCmd represents the jira worklog push command
+var jiraWorklogPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Log completed work to its Jira tickets",
+	Long: `Log the time spent on completed work as worklogs on its Jira tickets.
+Work linked to several tickets has its time split evenly between them.
+Time is rounded as set in the worklog section of your configuration, or
+with --round and --rounding. Worklogs are only created once; if the time
+of the work changes afterwards, the worklog is updated on the next push.
+Use --dry-run to see what would be logged without changing anything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		since, _ := cmd.Flags().GetString("since")
+		settings := config.WorklogSettings{}
+		settings.RoundMinutes, _ = cmd.Flags().GetInt("round")
+		settings.Rounding, _ = cmd.Flags().GetString("rounding")
+		runJiraWorklogPush(cmd.Context(), since, settings, dryRun)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraWorklogCmd)
+	jiraWorklogCmd.AddCommand(jiraWorklogPushCmd)
+	jiraWorklogPushCmd.Flags().Bool("dry-run", false, "Show the worklogs that would be created or updated")
+	jiraWorklogPushCmd.Flags().String("since", "", "Only push work started since a date, a date and time, or a duration ago")
+	jiraWorklogPushCmd.Flags().Int("round", 0, "Round logged time to a multiple of this many minutes")
+	jiraWorklogPushCmd.Flags().String("rounding", "", "How time is rounded: nearest, up or down")
+}
+
+// runJiraWorklogPush logs completed work to Jira
+func runJiraWorklogPush(ctx context.Context, since string, flags config.WorklogSettings, dryRun bool) {
+	log := logger.WithContext(ctx)
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	settings := cfg.Worklog
+	if flags.RoundMinutes > 0 {
+		settings.RoundMinutes = flags.RoundMinutes
+	}
+	if flags.Rounding != "" {
+		settings.Rounding = flags.Rounding
+	}
+	if err := validateRounding(settings.Rounding); err != nil {
+		log.Error("%v", err)
+		return
+	}
+
+	var sinceTime time.Time
+	if since != "" {
+		sinceTime, err = parseSince(since, time.Now())
+		if err != nil {
+			log.Error("%v", err)
+			return
+		}
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		log.Error("Failed to get tracked work: %v", err)
+		return
+	}
+
+	changes := planWorklogs(trackedWork, settings, sinceTime, jiraWorklogs)
+	if len(changes) == 0 {
+		log.Info("All completed work is already logged in Jira.")
+		return
+	}
+
+	for _, change := range changes {
+		fmt.Println(describeWorklogChange(change))
+	}
+	if dryRun {
+		fmt.Printf("\n%d worklog(s) would be pushed (dry run).\n", len(changes))
+		return
+	}
+
+	client := newJiraClient(cfg, cfg.JiraToken)
+	pushed, failed := 0, 0
+	for _, change := range changes {
+		if err := pushWorklog(ctx, client, change); err != nil {
+			log.Error("Failed to log time to %s: %v", change.TicketID, err)
+			failed++
+			continue
+		}
+		if err := saveTrackedWork(*change.Work); err != nil {
+			log.Error("Failed to save tracked work: %v", err)
+			return
+		}
+		pushed++
+	}
+	log.Info("Pushed %d worklog(s)", pushed)
+	if failed > 0 {
+		log.Error("%d worklog(s) failed", failed)
+	}
+}
+
+// planWorklogs returns the worklogs needed to bring a service in line with
+// the completed work started since the given time (zero for any time), given
+// the worklogs already pushed to it
+func planWorklogs(trackedWork []TrackedWork, settings config.WorklogSettings, since time.Time, pushed worklogList) []worklogChange {
+	var changes []worklogChange
+	for i := range trackedWork {
+		work := &trackedWork[i]
+		if work.Status != "completed" || len(work.TicketIDs) == 0 || work.StartTime.Before(since) {
+			continue
+		}
+
+		share := work.Duration() / time.Duration(len(work.TicketIDs))
+		seconds := roundWorklog(share, settings)
+		for _, ticketID := range work.TicketIDs {
+			existing := findWorklog(*pushed(work), ticketID)
+			// Jira and Tempo reject worklogs without time, so rounded-away work is skipped
+			if seconds == 0 || (existing != nil && existing.Seconds == seconds) {
+				continue
+			}
+			changes = append(changes, worklogChange{Work: work, TicketID: ticketID, Seconds: seconds, Existing: existing})
+		}
+	}
+	return changes
+}
+
+// findWorklog returns the worklog pushed for a ticket, or nil
+func findWorklog(worklogs []Worklog, ticketID string) *Worklog {
+	for i := range worklogs {
+		if worklogs[i].TicketID == ticketID {
+			return &worklogs[i]
+		}
+	}
+	return nil
+}
+
+// roundWorklog rounds a duration to whole minutes, then to a multiple of the
+// configured number of minutes, and returns it in seconds
+func roundWorklog(d time.Duration, settings config.WorklogSettings) int {
+	return int(roundDuration(d, settings.RoundMinutes, settings.Rounding).Seconds())
+}
+
+// roundDuration rounds a duration to a multiple of roundMinutes minutes, or
+// to whole minutes when that is 0 or 1, nearest, up or down as rounding says
+func roundDuration(d time.Duration, roundMinutes int, rounding string) time.Duration {
+	unit := time.Minute
+	if roundMinutes > 1 {
+		unit = time.Duration(roundMinutes) * time.Minute
+	}
+
+	var rounded time.Duration
+	switch rounding {
+	case "up":
+		rounded = d.Truncate(unit)
+		if rounded < d {
+			rounded += unit
+		}
+	case "down":
+		rounded = d.Truncate(unit)
+	default:
+		rounded = d.Round(unit)
+	}
+	return rounded
+}
+
+// validateRounding checks a rounding mode
+func validateRounding(rounding string) error {
+	switch rounding {
+	case "", "nearest", "up", "down":
+		return nil
+	}
+	return fmt.Errorf("invalid rounding %q, expected nearest, up or down", rounding)
+}
+
+// describeWorklogChange formats a worklog change as a line of a diff
+func describeWorklogChange(change worklogChange) string {
+	logged := formatDuration(time.Duration(change.Seconds) * time.Second)
+	if change.Existing != nil {
+		logged = formatDuration(time.Duration(change.Existing.Seconds)*time.Second) + " -> " + logged
+	}
+	marker := "+"
+	if change.Existing != nil {
+		marker = "~"
+	}
+	return fmt.Sprintf("%s %s  %s  %s  %s", marker, change.TicketID, change.Work.StartTime.Format("2006-01-02"), logged, change.Work.Description)
+}
+
+// pushWorklog creates or updates a worklog in Jira and records it on the work
+func pushWorklog(ctx context.Context, client *jiraClient, change worklogChange) error {
+	worklog := map[string]interface{}{
+		"started":          change.Work.StartTime.Format(jiraTimeLayout),
+		"timeSpentSeconds": change.Seconds,
+		"comment":          client.richText(change.Work.Description),
+	}
+
+	if change.Existing != nil {
+		path := client.api(fmt.Sprintf("/issue/%s/worklog/%s", change.TicketID, change.Existing.ID))
+		if err := client.do(ctx, "PUT", path, worklog, nil); err != nil {
+			return err
+		}
+		// Worklogs added for other tickets may have moved the slice, so the
+		// entry is looked up again rather than updated through Existing
+		findWorklog(change.Work.Worklogs, change.TicketID).Seconds = change.Seconds
+		return nil
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := client.do(ctx, "POST", client.api("/issue/"+change.TicketID+"/worklog"), worklog, &result); err != nil {
+		return err
+	}
+	change.Work.Worklogs = append(change.Work.Worklogs, Worklog{TicketID: change.TicketID, ID: result.ID, Seconds: change.Seconds})
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestRoundWorklog(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		settings config.WorklogSettings
+		want     int
+	}{
+		{37*time.Minute + 40*time.Second, config.WorklogSettings{}, 38 * 60},
+		{37 * time.Minute, config.WorklogSettings{RoundMinutes: 15}, 30 * 60},
+		{38 * time.Minute, config.WorklogSettings{RoundMinutes: 15}, 45 * 60},
+		{31 * time.Minute, config.WorklogSettings{RoundMinutes: 15, Rounding: "up"}, 45 * 60},
+		{44 * time.Minute, config.WorklogSettings{RoundMinutes: 15, Rounding: "down"}, 30 * 60},
+		{5 * time.Minute, config.WorklogSettings{RoundMinutes: 15}, 0},
+	}
+	for _, tt := range tests {
+		if got := roundWorklog(tt.duration, tt.settings); got != tt.want {
+			t.Errorf("roundWorklog(%v, %+v) = %d, want %d", tt.duration, tt.settings, got, tt.want)
+		}
+	}
+}
+
+func TestPlanAndPushWorklogs(t *testing.T) {
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{ID: "split", Description: "Pairing", TicketIDs: []string{"PROJ-1", "PROJ-2"}, StartTime: start, EndTime: start.Add(2 * time.Hour), Status: "completed",
+			Worklogs: []Worklog{{TicketID: "PROJ-1", ID: "101", Seconds: 3600}}},
+		{ID: "changed", Description: "Fix login", TicketIDs: []string{"PROJ-3"}, StartTime: start, EndTime: start.Add(90 * time.Minute), Status: "completed",
+			Worklogs: []Worklog{{TicketID: "PROJ-3", ID: "103", Seconds: 3600}}},
+		{ID: "active", Description: "Still going", TicketIDs: []string{"PROJ-4"}, StartTime: start, Status: "active"},
+		{ID: "untracked", Description: "No ticket", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+	}
+
+	changes := planWorklogs(trackedWork, config.WorklogSettings{}, time.Time{}, jiraWorklogs)
+	if len(changes) != 2 {
+		t.Fatalf("Expected a new worklog for PROJ-2 and an update for PROJ-3, got %+v", changes)
+	}
+	if changes[0].TicketID != "PROJ-2" || changes[0].Existing != nil || changes[0].Seconds != 3600 {
+		t.Errorf("Unexpected first change %+v", changes[0])
+	}
+	if changes[1].TicketID != "PROJ-3" || changes[1].Existing == nil || changes[1].Seconds != 5400 {
+		t.Errorf("Unexpected second change %+v", changes[1])
+	}
+	if got := describeWorklogChange(changes[1]); got != "~ PROJ-3  2024-03-11  1h -> 1h 30m  Fix login" {
+		t.Errorf("Unexpected change description %q", got)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var worklog map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&worklog)
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue/PROJ-2/worklog":
+			if worklog["started"] != start.Format(jiraTimeLayout) || worklog["timeSpentSeconds"] != float64(3600) {
+				t.Errorf("Unexpected worklog %v", worklog)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "102"}`))
+		case r.Method == "PUT" && r.URL.Path == "/rest/api/3/issue/PROJ-3/worklog/103":
+			w.Write([]byte(`{"id": "103"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newJiraClient(&config.Config{JiraURL: server.URL}, "test-token")
+	for _, change := range changes {
+		if err := pushWorklog(context.Background(), client, change); err != nil {
+			t.Fatalf("Failed to push worklog: %v", err)
+		}
+	}
+	if len(trackedWork[0].Worklogs) != 2 || trackedWork[0].Worklogs[1].ID != "102" {
+		t.Errorf("Expected the new worklog to be recorded, got %+v", trackedWork[0].Worklogs)
+	}
+	if trackedWork[1].Worklogs[0].Seconds != 5400 {
+		t.Errorf("Expected the updated worklog to be recorded, got %+v", trackedWork[1].Worklogs)
+	}
+	if changes := planWorklogs(trackedWork, config.WorklogSettings{}, time.Time{}, jiraWorklogs); len(changes) != 0 {
+		t.Errorf("Expected nothing left to push, got %+v", changes)
+	}
+}
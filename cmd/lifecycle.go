@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// branchEnd describes how the branch of some tracked work ended
+type branchEnd struct {
+	State       string // "merged" or "deleted"
+	MergeCommit string // The commit that merged the branch, if it was merged
+	Tip         string // The last commit of the branch, when known
+}
+
+// checkEndedBranches offers to complete unfinished work whose branch has been
+// merged into the default branch or deleted. Work is only offered once; the
+// outcome is recorded on it whether or not it is completed.
+func checkEndedBranches(cmd *cobra.Command) {
+	if backgroundCheckSkipped[cmd.Name()] || !isInteractive() {
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil || !cfg.GitIntegration || !isGitRepo(".") {
+		return
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		return
+	}
+	var candidates []TrackedWork
+	for _, work := range trackedWork {
+		if work.Status != "completed" && work.Context.Branch != "" && work.Context.StartCommit != "" && work.Context.BranchEnded == "" {
+			candidates = append(candidates, work)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	backend := getGitBackend()
+	base, err := backend.DefaultBranch(".")
+	if err != nil {
+		return
+	}
+
+	for _, work := range candidates {
+		end, ok := detectBranchEnd(backend, ".", base, work, cfg.TicketPrefixes)
+		if !ok {
+			continue
+		}
+		work.Context.BranchEnded = end.State
+		work.Context.MergeCommit = end.MergeCommit
+
+		completed := promptCompleteEndedWork(work, end, base)
+		if completed {
+			completeWork(&work, time.Now())
+			// The work ended at the branch's tip rather than at HEAD
+			if end.Tip != "" {
+				recordDiffStats(&work, end.Tip)
+			}
+		}
+		if err := saveTrackedWork(work); err != nil {
+			printError("Error saving tracked work:", err)
+			return
+		}
+		if completed {
+			fmt.Printf("Completed %s (%s).\n", work.Description, formatDuration(work.Duration()))
+		}
+	}
+}
+
+// detectBranchEnd reports whether the branch of some tracked work was merged
+// into base or deleted. Work that didn't start from base is ignored, which
+// also skips work tracked in other repositories.
+func detectBranchEnd(backend gitBackend, dir, base string, work TrackedWork, prefixes []string) (branchEnd, bool) {
+	branch := work.Context.Branch
+	start := work.Context.StartCommit
+	if branch == baseBranchName(base) || !backend.IsAncestor(dir, start, base) {
+		return branchEnd{}, false
+	}
+
+	if backend.BranchExists(dir, branch) {
+		commits, err := backend.LogRange(dir, start, branch)
+		if err != nil {
+			commits, err = backend.LogRange(dir, start, "origin/"+branch)
+		}
+		// A branch without commits of its own is trivially part of base
+		if err != nil || len(commits) == 0 || !backend.IsAncestor(dir, commits[0].Hash, base) {
+			return branchEnd{}, false
+		}
+		return mergedBranchEnd(backend, dir, base, commits[0].Hash)
+	}
+
+	// The branch is gone; it was merged if its last known commit is part of
+	// base, or squash-merged if base has a commit for the work's ticket
+	if tip := work.Context.CommitHash; tip != "" && tip != start && backend.IsAncestor(dir, tip, base) {
+		return mergedBranchEnd(backend, dir, base, tip)
+	}
+	if commits, err := backend.LogRange(dir, start, base); err == nil {
+		for i := len(commits) - 1; i >= 0; i-- {
+			if ticketID := extractTicketIDFromMessage(commits[i].FullMessage(), prefixes); ticketID != "" && work.HasTicket(ticketID) {
+				return branchEnd{State: "merged", MergeCommit: commits[i].Hash}, true
+			}
+		}
+	}
+	return branchEnd{State: "deleted"}, true
+}
+
+// mergedBranchEnd describes a branch whose last commit tip was merged into base
+func mergedBranchEnd(backend gitBackend, dir, base, tip string) (branchEnd, bool) {
+	mergeCommit, err := backend.MergeCommit(dir, tip, base)
+	if err != nil {
+		return branchEnd{}, false
+	}
+	return branchEnd{State: "merged", MergeCommit: mergeCommit, Tip: tip}, true
+}
+
+// promptCompleteEndedWork asks whether to complete work whose branch ended
+func promptCompleteEndedWork(work TrackedWork, end branchEnd, base string) bool {
+	if end.State == "merged" {
+		fmt.Printf("Branch %s of %s was merged into %s in %s.\n", work.Context.Branch, work.Description, baseBranchName(base), shortHash(end.MergeCommit))
+	} else {
+		fmt.Printf("Branch %s of %s was deleted.\n", work.Context.Branch, work.Description)
+	}
+
+	prompt := promptui.Prompt{
+		Label:     "Complete the work",
+		IsConfirm: true,
+	}
+	_, err := prompt.Run()
+	return err == nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDetectBranchEnd(t *testing.T) {
+	_, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	git := func(args ...string) string {
+		output, err := exec.Command("git", args...).Output()
+		if err != nil {
+			t.Fatalf("git %v failed: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(file, message string) string {
+		if err := os.WriteFile(file, []byte(message), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		git("add", file)
+		git("commit", "-m", message)
+		return git("rev-parse", "HEAD")
+	}
+	// startWork creates a branch from main with one commit and returns work tracked on it
+	startWork := func(branch, ticketID string) TrackedWork {
+		git("checkout", "-q", "main")
+		start := git("rev-parse", "HEAD")
+		git("checkout", "-q", "-b", branch)
+		tip := commit(ticketID+".txt", "Work on "+branch)
+		return TrackedWork{
+			ID:        branch,
+			TicketIDs: []string{ticketID},
+			Status:    "active",
+			Context:   WorkContext{Branch: branch, StartCommit: start, CommitHash: tip},
+		}
+	}
+
+	git("checkout", "-q", "-b", "main")
+	commit("base.txt", "Initial commit")
+	backend := getGitBackend()
+	prefixes := []string{"PROJ-"}
+
+	open := startWork("feature/PROJ-1", "PROJ-1")
+	if _, ok := detectBranchEnd(backend, ".", "main", open, prefixes); ok {
+		t.Error("Expected an unmerged branch not to have ended")
+	}
+
+	merged := startWork("feature/PROJ-2", "PROJ-2")
+	git("checkout", "-q", "main")
+	git("merge", "--no-ff", "-m", "Merge feature/PROJ-2", "feature/PROJ-2")
+	end, ok := detectBranchEnd(backend, ".", "main", merged, prefixes)
+	if !ok || end.State != "merged" || end.MergeCommit != git("rev-parse", "HEAD") || end.Tip != merged.Context.CommitHash {
+		t.Errorf("Expected a merge in the merge commit, got %+v (%v)", end, ok)
+	}
+
+	fastForwarded := startWork("feature/PROJ-3", "PROJ-3")
+	git("checkout", "-q", "main")
+	git("merge", "--ff-only", "feature/PROJ-3")
+	git("branch", "-d", "feature/PROJ-3")
+	end, ok = detectBranchEnd(backend, ".", "main", fastForwarded, prefixes)
+	if !ok || end.State != "merged" || end.MergeCommit != fastForwarded.Context.CommitHash {
+		t.Errorf("Expected a fast-forward merge of the branch tip, got %+v (%v)", end, ok)
+	}
+
+	squashed := startWork("feature/PROJ-4", "PROJ-4")
+	git("checkout", "-q", "main")
+	git("merge", "--squash", "feature/PROJ-4")
+	git("commit", "-m", "Add PROJ-4 work (PROJ-4)")
+	git("branch", "-D", "feature/PROJ-4")
+	end, ok = detectBranchEnd(backend, ".", "main", squashed, prefixes)
+	if !ok || end.State != "merged" || end.MergeCommit != git("rev-parse", "HEAD") {
+		t.Errorf("Expected a squash merge, got %+v (%v)", end, ok)
+	}
+
+	abandoned := startWork("feature/PROJ-5", "PROJ-5")
+	git("checkout", "-q", "main")
+	git("branch", "-D", "feature/PROJ-5")
+	end, ok = detectBranchEnd(backend, ".", "main", abandoned, prefixes)
+	if !ok || end.State != "deleted" || end.MergeCommit != "" {
+		t.Errorf("Expected a deleted branch, got %+v (%v)", end, ok)
+	}
+}
@@ -1,15 +1,16 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/output"
 )
 
 // listCmd represents the list command
@@ -17,111 +18,259 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List tracked work",
 	Long: `List tracked work, showing both git-based and manually tracked work.
-This command gives you a comprehensive view of your work history.`,
+This command gives you a comprehensive view of your work history.
+When work is scoped per repository, use --all to list work from every project.
+
+In a terminal, work is shown in a table of its ID, description, ticket,
+duration and status, with descriptions cut to fit. Choose other columns with
+--columns, from id, description, ticket, duration, status, start, end,
+project, tags, estimate and billable. Otherwise, such as in a pipe, the rows
+are printed with their columns separated by tabs.
+
+Use --format to print each entry with a Go template instead, such as
+'{{.TicketID}} {{.Description}}', with the fields of --output json.
+
+Use --verbose to show each entry in full instead, with its git context: its
+branch and the lines added and removed in each file.
+
+Narrow the list down with --since and --until, --ticket, --tag and
+--status, as with report and export.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runList(args)
+		all, _ := cmd.Flags().GetBool("all")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		filter, err := workFilterFromFlags(cmd)
+		if err != nil {
+			printError("Error:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		runList(args, all, verbose, columns, filter)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	supportJSONOutput(listCmd)
+	listCmd.Flags().BoolP("all", "a", false, "List work from every project")
+	listCmd.Flags().BoolP("verbose", "v", false, "Show the git context and diff statistics of each entry")
+	listCmd.Flags().StringSlice("columns", nil, "Columns of the table, such as id,description,ticket")
+	listCmd.RegisterFlagCompletionFunc("columns", completeWorkColumns)
+	addWorkFilterFlags(listCmd, "")
+	addFormatFlag(listCmd, "Print each entry with a Go template, such as '{{.TicketID}} {{.Description}}'")
 }
 
-func runList(args []string) {
+func runList(args []string, all, verbose bool, columns []string, filter workFilter) {
 	// Load configuration
 	_, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
 	// Get tracked work
-	trackedWork, err := getTrackedWork()
+	var trackedWork []TrackedWork
+	if all {
+		trackedWork, err = getAllTrackedWork()
+	} else {
+		trackedWork, err = getTrackedWork()
+	}
 	if err != nil {
-		fmt.Println("Error getting tracked work:", err)
+		printError("Error getting tracked work:", err)
 		return
 	}
+	trackedWork = filter.apply(trackedWork)
 
 	// Sort tracked work by start time (newest first)
 	sort.Slice(trackedWork, func(i, j int) bool {
 		return trackedWork[i].StartTime.After(trackedWork[j].StartTime)
 	})
 
+	if jsonOutput() {
+		printJSON(workOutputs(trackedWork))
+		return
+	}
+	if templateOutput() {
+		for _, work := range workOutputs(trackedWork) {
+			if err := printFormatted(work); err != nil {
+				fprintError(os.Stderr, "Error:", err)
+				return
+			}
+		}
+		return
+	}
+
+	table, err := workTable(trackedWork, columns)
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+
 	// Display tracked work
 	if len(trackedWork) == 0 {
 		fmt.Println("No tracked work found.")
+		setExitCode(exitNotFound)
 		return
 	}
 
-	fmt.Println("Tracked work:")
-	for _, work := range trackedWork {
-		// Format time
-		startTime := work.StartTime.Format("2006-01-02 15:04")
-		var timeStr string
-		if work.EndTime.IsZero() {
-			timeStr = fmt.Sprintf("%s (ongoing)", startTime)
-		} else {
-			endTime := work.EndTime.Format("2006-01-02 15:04")
-			timeStr = fmt.Sprintf("%s – %s", startTime, endTime)
+	if !verbose {
+		if err := table.Print(); err != nil {
+			printError("Error printing tracked work:", err)
 		}
+		return
+	}
 
-		// Display work
-		fmt.Printf("\n%s\n", timeStr)
-		fmt.Printf("  %s\n", work.Description)
-		if work.TicketID != "" {
-			fmt.Printf("  Ticket: %s\n", work.TicketID)
-		}
-		if len(work.Tags) > 0 {
-			fmt.Printf("  Tags: %s\n", strings.Join(work.Tags, ", "))
+	if !output.Quiet() {
+		fmt.Println(output.Heading("Tracked work:"))
+	}
+	for _, work := range trackedWork {
+		printWorkEntry(work)
+		printWorkContext(work.Context)
+	}
+}
+
+// workOutput is tracked work as --output json prints it, with the project
+// it belongs to and the time spent on it
+type workOutput struct {
+	TrackedWork
+	Project         string `json:"project,omitempty"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+// newWorkOutput returns tracked work as --output json prints it
+func newWorkOutput(work TrackedWork) workOutput {
+	return workOutput{
+		TrackedWork:     work,
+		Project:         work.Project,
+		DurationMinutes: int(work.Duration().Round(time.Minute) / time.Minute),
+	}
+}
+
+// TicketID returns the first ticket of the work, or "" when it has none,
+// for --format templates
+func (w workOutput) TicketID() string {
+	if len(w.TicketIDs) == 0 {
+		return ""
+	}
+	return w.TicketIDs[0]
+}
+
+// workOutputs returns tracked work as --output json prints it
+func workOutputs(trackedWork []TrackedWork) []workOutput {
+	outputs := make([]workOutput, len(trackedWork))
+	for i, work := range trackedWork {
+		outputs[i] = newWorkOutput(work)
+	}
+	return outputs
+}
+
+// printWorkEntry prints a single piece of tracked work in list format
+func printWorkEntry(work TrackedWork) {
+	// Format time
+	startTime := work.StartTime.Format("2006-01-02 15:04")
+	var timeStr string
+	if work.Status == "paused" {
+		timeStr = fmt.Sprintf("%s (paused)", startTime)
+	} else if work.EndTime.IsZero() {
+		timeStr = fmt.Sprintf("%s (ongoing)", startTime)
+	} else {
+		endTime := work.EndTime.Format("2006-01-02 15:04")
+		timeStr = fmt.Sprintf("%s – %s", startTime, endTime)
+	}
+
+	// Display work
+	fmt.Printf("\n%s\n", timeStr)
+	fmt.Printf("  %s\n", work.Description)
+	if work.Project != "" {
+		fmt.Printf("  Project: %s\n", work.Project)
+	}
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("  Tickets: %s\n", output.Ticket(strings.Join(work.TicketIDs, ", ")))
+	}
+	if len(work.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(work.Tags, ", "))
+	}
+	if work.Billable {
+		fmt.Println("  Billable")
+	}
+	if done, total := subtaskProgress(work); total > 0 {
+		fmt.Printf("  Subtasks: %d/%d done\n", done, total)
+	}
+	if len(work.Pomodoros) > 0 {
+		fmt.Printf("  Pomodoros: %d\n", len(work.Pomodoros))
+	}
+	if paused := work.PausedDuration(); paused > 0 {
+		fmt.Printf("  Duration: %s (paused %s)\n", formatDuration(work.Duration()), formatDuration(paused))
+	} else {
+		fmt.Printf("  Duration: %s\n", formatDuration(work.Duration()))
+	}
+	if work.EstimateMinutes > 0 {
+		fmt.Printf("  Estimate: %s\n", formatEstimate(work))
+	}
+}
+
+// printWorkContext prints the git context of tracked work, with the files
+// that changed most first
+func printWorkContext(context WorkContext) {
+	if context.Branch != "" {
+		fmt.Printf("  Branch: %s\n", context.Branch)
+	}
+	switch {
+	case context.MergeCommit != "":
+		fmt.Printf("  Merged in: %s\n", shortHash(context.MergeCommit))
+	case context.BranchEnded == "deleted":
+		fmt.Println("  Branch deleted")
+	}
+	if len(context.FileChurn) == 0 {
+		if len(context.Files) > 0 {
+			fmt.Printf("  Files: %s\n", strings.Join(context.Files, ", "))
 		}
+		return
+	}
+
+	fmt.Printf("  Changes: +%d/-%d in %d file(s)\n", context.LinesAdded, context.LinesRemoved, len(context.FileChurn))
+	churn := append([]FileChurn(nil), context.FileChurn...)
+	sort.SliceStable(churn, func(i, j int) bool {
+		return churn[i].Added+churn[i].Removed > churn[j].Added+churn[j].Removed
+	})
+	for _, file := range churn {
+		fmt.Printf("    +%d/-%d  %s\n", file.Added, file.Removed, file.Path)
 	}
 }
 
 // getTrackedWork gets all tracked work from the database
 func getTrackedWork() ([]TrackedWork, error) {
 	// Get the database directory
-	dbDir, err := getDBDir()
+	dbDir, err := openDB()
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if the database directory exists
-	if _, err := os.Stat(dbDir); os.IsNotExist(err) {
-		return []TrackedWork{}, nil
-	}
+	return readTrackedWorkIn(dbDir)
+}
+
+// readTrackedWorkIn reads all tracked work from the database in dbDir
+func readTrackedWorkIn(dbDir string) ([]TrackedWork, error) {
+	var trackedWork []TrackedWork
 
-	// Read all files in the database directory
-	files, err := os.ReadDir(dbDir)
+	// Read the active work
+	activeWork, err := readActiveWork(dbDir)
 	if err != nil {
 		return nil, err
 	}
+	if activeWork != nil {
+		trackedWork = append(trackedWork, *activeWork)
+	}
 
-	// Read each file
-	var trackedWork []TrackedWork
-	for _, file := range files {
-		if !strings.HasSuffix(file.Name(), ".json") {
-			continue
-		}
-
-		// Read the file
-		filePath := filepath.Join(dbDir, file.Name())
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			fmt.Printf("Error reading file %s: %v\n", filePath, err)
-			continue
-		}
-
-		// Parse the JSON
-		var work TrackedWork
-		err = json.Unmarshal(data, &work)
+	// Read paused and completed work
+	for _, name := range []string{"paused.json", "completed.json"} {
+		work, err := readWorkFile(filepath.Join(dbDir, name))
 		if err != nil {
-			fmt.Printf("Error parsing file %s: %v\n", filePath, err)
-			continue
+			return nil, err
 		}
-
-		trackedWork = append(trackedWork, work)
+		trackedWork = append(trackedWork, work...)
 	}
 
 	return trackedWork, nil
-} 
\ No newline at end of file
+}
\ No newline at end of file
@@ -1,41 +1,35 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"os"
+	"os/signal"
 	"strings"
-	"time"
 
+	"github.com/chzyer/readline"
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
 	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/output"
 	"github.com/plannet-ai/plannet/security"
 	"github.com/spf13/cobra"
 )
 
-// Message represents a message in the conversation
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-// Response represents the response from the LLM API
-type Response struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-}
-
 // llmCmd represents the llm command
 var llmCmd = &cobra.Command{
 	Use:   "llm",
 	Short: "Interact with the LLM",
-	Long:  `Interact with the LLM to get help with your tasks`,
+	Long: `Interact with the LLM to get help with your tasks.
+
+Responses are shown as they arrive. Press Ctrl+C to stop a response.
+
+In an interactive session, the LLM remembers the conversation, as much of
+it as fits in llm_context_tokens. Type /reset to start over, /save to save
+the conversation, and /help for the other commands. Saved conversations
+are listed by 'plannet llm history'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 		prompt, _ := cmd.Flags().GetString("prompt")
@@ -49,6 +43,19 @@ var llmCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(llmCmd)
 	llmCmd.Flags().String("prompt", "", "Single prompt to send to the LLM")
+	llm.OnRateLimitWait = reportRateLimitWait
+	llm.OnRedact = reportRedactions
+	llm.OnFallback = reportLLMFallback
+}
+
+// reportLLMFallback reports that a request is sent to a fallback endpoint
+func reportLLMFallback(failed, next string, err error) {
+	fmt.Fprintf(os.Stderr, "LLM request to %s failed: %v\nTrying %s instead...\n", failed, err, next)
+}
+
+// reportRedactions reports the secrets masked in what was sent to the LLM
+func reportRedactions(found security.Redactions) {
+	fmt.Fprintf(os.Stderr, "Masked %d secret(s) before sending to the LLM: %s\n", found.Total(), found)
 }
 
 // runLLMInteractive starts an interactive session with the LLM
@@ -59,42 +66,67 @@ func runLLMInteractive(ctx context.Context) error {
 		return err
 	}
 
-	if cfg.BaseURL == "" || cfg.Model == "" {
+	if !llm.IsConfigured(cfg) {
 		logger.Error("LLM integration is not configured. Please run 'plannet init' first")
+		setExitCode(exitConfig)
 		return fmt.Errorf("LLM integration not configured")
 	}
 
-	// Get LLM token from config
-	token := cfg.LLMToken
-	if token == "" {
-		fmt.Println("Error: LLM token not found. Please run 'plannet init' to set up LLM integration.")
+	// Local servers run without a token
+	if cfg.LLMToken == "" && llm.NeedsToken(cfg) {
+		printError("Error: LLM token not found. Please run 'plannet init' to set up LLM integration.")
+		setExitCode(exitConfig)
 		return fmt.Errorf("LLM token not found")
 	}
 
-	logger.Info("Starting interactive session with LLM. Type 'exit' to quit.")
+	logger.Info("Starting interactive session with LLM. Type 'exit' to quit, /help for commands, or press Ctrl+C to stop a response.")
 	logger.Info("Type your message and press Enter:")
 
+	rl, err := newLLMLineReader()
+	if err != nil {
+		return fmt.Errorf("failed to start the session: %w", err)
+	}
+	defer rl.Close()
+
+	chat := newLLMChat(cfg)
+	var in llmInput
 	for {
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			return ctx.Err()
-		default:
-			var input string
-			fmt.Print("> ")
-			fmt.Scanln(&input)
+		}
 
-			if strings.ToLower(input) == "exit" {
-				return nil
+		rl.SetPrompt(in.prompt())
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			// Ctrl+C drops the message being typed, rather than ending the session
+			if !in.pending() && line == "" {
+				fmt.Println("Type exit to quit.")
 			}
+			in.discard()
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
 
-			response, err := sendLLMRequest(ctx, cfg, input)
-			if err != nil {
-				logger.Error("Failed to get response: %v", err)
-				continue
-			}
+		input, complete := in.add(line)
+		if !complete || strings.TrimSpace(input) == "" {
+			continue
+		}
+		if handled, quit := chat.runCommand(input); quit {
+			return nil
+		} else if handled {
+			continue
+		}
 
-			logger.Info("LLM: %s", response)
+		reply, err := streamLLMResponse(ctx, cfg, chat.ask(input))
+		if err != nil {
+			logger.Error("Failed to get response: %v", err)
 		}
+		chat.reply(reply)
 	}
 }
 
@@ -106,84 +138,53 @@ func runLLMWithPrompt(ctx context.Context, prompt string) error {
 		return err
 	}
 
-	if cfg.BaseURL == "" || cfg.Model == "" {
+	if !llm.IsConfigured(cfg) {
 		logger.Error("LLM integration is not configured. Please run 'plannet init' first")
+		setExitCode(exitConfig)
 		return fmt.Errorf("LLM integration not configured")
 	}
 
-	// Get LLM token from config
-	token := cfg.LLMToken
-	if token == "" {
-		fmt.Println("Error: LLM token not found. Please run 'plannet init' to set up LLM integration.")
+	// Local servers run without a token
+	if cfg.LLMToken == "" && llm.NeedsToken(cfg) {
+		printError("Error: LLM token not found. Please run 'plannet init' to set up LLM integration.")
+		setExitCode(exitConfig)
 		return fmt.Errorf("LLM token not found")
 	}
 
-	response, err := sendLLMRequest(ctx, cfg, prompt)
-	if err != nil {
+	if _, err := streamLLMResponse(ctx, cfg, llm.UserPrompt(cfg.SystemPrompt, prompt)); err != nil {
 		logger.Error("Failed to get response: %v", err)
 		return err
 	}
-
-	logger.Info("LLM: %s", response)
 	return nil
 }
 
-// sendLLMRequest sends a request to the LLM API
-func sendLLMRequest(ctx context.Context, cfg *config.Config, prompt string) (string, error) {
-	// Get LLM token from config
-	token := cfg.LLMToken
-	if token == "" {
-		fmt.Println("Error: LLM token not found. Please run 'plannet init' to set up LLM integration.")
-		return "", fmt.Errorf("LLM token not found")
-	}
-
-	// Create rate limiter: 5 requests per minute
-	rateLimiter := security.NewHTTPRateLimiter(5, time.Minute)
-	baseClient := &http.Client{}
-	client := rateLimiter.WrapHTTPClient(baseClient, "llm")
-
-	messages := []Message{
-		{
-			Role:    "user",
-			Content: prompt,
-		},
-	}
-
-	requestBody, err := json.Marshal(map[string]interface{}{
-		"model":    cfg.Model,
-		"messages": messages,
+// streamLLMResponse sends a conversation to the LLM and prints the reply as
+// it arrives, then returns it. Ctrl+C stops the reply, rather than plannet;
+// what arrived until then is returned.
+func streamLLMResponse(ctx context.Context, cfg *config.Config, conv llm.Conversation) (string, error) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	fmt.Print("LLM: ")
+	markdown := output.NewMarkdownStream(nil)
+	reply, err := sendLLMRequest(ctx, cfg, conv, func(token string) {
+		fmt.Print(markdown.Render(token))
 	})
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request body: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", cfg.BaseURL, bytes.NewReader(requestBody))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+	fmt.Println(markdown.Flush())
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("(stopped)")
+		return reply, nil
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no response from LLM")
+		return "", err
 	}
+	return reply, nil
+}
 
-	return response.Choices[0].Message.Content, nil
+// sendLLMRequest sends a conversation to the LLM API of the configured provider.
+// With onToken, the response is streamed, and onToken is called with each
+// piece of it as it arrives. Cancelling ctx stops the request; what arrived
+// until then is returned with the context's error.
+func sendLLMRequest(ctx context.Context, cfg *config.Config, conv llm.Conversation, onToken func(string)) (string, error) {
+	return llm.Send(ctx, cfg, conv, onToken)
 }
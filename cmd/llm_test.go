@@ -2,13 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
 )
 
 // llmTestConfig is used to create a test configuration
@@ -389,3 +392,95 @@ func TestLLMResponseParsing(t *testing.T) {
 		})
 	}
 }
+
+// TestSendLLMRequestStream tests that a streamed response is passed on as it arrives
+func TestSendLLMRequestStream(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("Expected a streamed request, got %v", body)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Use a \"}}]}\n\n")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"token bucket.\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Provider: "openai", BaseURL: server.URL, Model: "test-model", LLMToken: "test-token"}
+	var tokens []string
+	response, err := sendLLMRequest(context.Background(), cfg, llm.UserPrompt("", "How do I rate limit?"), func(token string) {
+		tokens = append(tokens, token)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response != "Use a token bucket." {
+		t.Errorf("Expected the whole response, got %q", response)
+	}
+	if len(tokens) != 2 || tokens[0] != "Use a " {
+		t.Errorf("Expected the response in two tokens, got %q", tokens)
+	}
+}
+
+// TestSendLLMRequestCancel tests that cancelling the context stops a streamed response
+func TestSendLLMRequestCancel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Partial\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := &config.Config{Provider: "openai", BaseURL: server.URL, Model: "test-model", LLMToken: "test-token"}
+	response, err := sendLLMRequest(ctx, cfg, llm.UserPrompt("", "Tell me a story"), func(token string) {
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the request to be cancelled, got %v", err)
+	}
+	if response != "Partial" {
+		t.Errorf("Expected what arrived before cancelling, got %q", response)
+	}
+}
+
+// TestRunLLMWithLocalModel tests that a local model found by 'plannet init',
+// which has no token, can be prompted
+func TestRunLLMWithLocalModel(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	resetExitCode(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header, got %q", auth)
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"Hello."}}]}`)
+	}))
+	defer server.Close()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.LLMToken = "old-token"
+	captureStdout(t, func() {
+		useLocalModel(cfg, llm.LocalModel{Server: "LM Studio", Provider: llm.ProviderOpenAI, BaseURL: server.URL + "/v1/chat/completions", Model: "qwen2.5"})
+	})
+
+	var runErr error
+	out := captureStdout(t, func() { runErr = runLLMWithPrompt(context.Background(), "Hi") })
+	if runErr != nil || exitCode != 0 {
+		t.Fatalf("Expected the prompt to be sent without a token, got %v (exit code %d): %s", runErr, exitCode, out)
+	}
+	if !bytes.Contains([]byte(out), []byte("Hello.")) {
+		t.Errorf("Expected the reply, got %q", out)
+	}
+}
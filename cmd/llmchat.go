@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+)
+
+// defaultLLMContextTokens is how many tokens of conversation are sent with
+// each message, unless configured otherwise
+const defaultLLMContextTokens = 8000
+
+// llmChat is the conversation of an interactive session with the LLM
+type llmChat struct {
+	system    string
+	model     string
+	messages  []llm.Message
+	maxTokens int
+	// started is when the conversation started; it is the ID of the
+	// conversation once saved
+	started time.Time
+}
+
+// newLLMChat starts a conversation with the configured system prompt
+func newLLMChat(cfg *config.Config) *llmChat {
+	maxTokens := cfg.LLMContextTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultLLMContextTokens
+	}
+	return &llmChat{system: cfg.SystemPrompt, model: cfg.Model, maxTokens: maxTokens, started: time.Now()}
+}
+
+// ask adds a message from the user, and returns the conversation to send
+// for it: as much of the history as fits in the token window
+func (c *llmChat) ask(message string) llm.Conversation {
+	c.messages = append(c.messages, llm.Message{Role: "user", Content: message})
+	conv := llm.Conversation{System: c.system, Messages: c.messages}
+	return conv.Truncate(c.maxTokens)
+}
+
+// reply adds the LLM's reply to the last message. Without a reply, as when
+// the request failed, the message is dropped so it can be asked again.
+func (c *llmChat) reply(reply string) {
+	if reply == "" {
+		c.messages = c.messages[:len(c.messages)-1]
+		return
+	}
+	c.messages = append(c.messages, llm.Message{Role: "assistant", Content: reply})
+}
+
+// reset forgets the conversation
+func (c *llmChat) reset() {
+	c.messages = nil
+	c.started = time.Now()
+}
+
+// markdown returns the conversation as a markdown document
+func (c *llmChat) markdown() string {
+	return chatMarkdown(c.messages)
+}
+
+// chatMarkdown returns the messages of a conversation as a markdown document
+func chatMarkdown(messages []llm.Message) string {
+	var doc strings.Builder
+	doc.WriteString("# Conversation with the LLM\n")
+	for _, message := range messages {
+		speaker := "You"
+		if message.Role == "assistant" {
+			speaker = "LLM"
+		}
+		fmt.Fprintf(&doc, "\n## %s\n\n%s\n", speaker, strings.TrimSpace(message.Content))
+	}
+	return doc.String()
+}
+
+// save writes the conversation as markdown to path, or to a file named
+// after the time in the current directory, and returns the path
+func (c *llmChat) save(path string) (string, error) {
+	if len(c.messages) == 0 {
+		return "", fmt.Errorf("nothing to save yet")
+	}
+	if path == "" {
+		path = "plannet-llm-" + time.Now().Format("20060102-150405") + ".md"
+	}
+	if err := os.WriteFile(path, []byte(c.markdown()), 0644); err != nil {
+		return "", fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return path, nil
+}
+
+// keep saves the conversation to the history in ~/.plannet/chats, and
+// returns its ID. Saving it again replaces the earlier save.
+func (c *llmChat) keep() (string, error) {
+	if len(c.messages) == 0 {
+		return "", fmt.Errorf("nothing to save yet")
+	}
+	chat := savedChat{
+		ID:       c.started.Format(savedChatIDFormat),
+		Started:  c.started,
+		Updated:  time.Now(),
+		Model:    c.model,
+		System:   c.system,
+		Messages: c.messages,
+	}
+	if err := writeSavedChat(chat); err != nil {
+		return "", err
+	}
+	return chat.ID, nil
+}
+
+// llmChatHelp describes the commands of an interactive session
+const llmChatHelp = `Commands:
+  /reset        Start a new conversation
+  /save         Save the conversation, to see it in 'plannet llm history'
+  /save <file>  Save the conversation as markdown
+  /help         Show these commands
+  exit          End the session
+
+End a line with \ to continue the message on the next line, or wrap
+several lines, such as pasted code, in lines of """.`
+
+// runCommand runs a command of an interactive session, such as /reset. It
+// reports whether input was a command, and whether it ends the session.
+func (c *llmChat) runCommand(input string) (handled, quit bool) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return false, false
+	}
+	switch strings.ToLower(fields[0]) {
+	case "exit", "/exit", "/quit":
+		// A message can start with "exit"; the commands can't
+		if fields[0] == "exit" && len(fields) > 1 {
+			return false, false
+		}
+		return true, true
+	case "/reset":
+		c.reset()
+		fmt.Println("Started a new conversation.")
+	case "/save":
+		path := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), fields[0]))
+		if path == "" {
+			if id, err := c.keep(); err != nil {
+				printError("Error:", err)
+			} else {
+				fmt.Printf("Saved the conversation as %s\n", id)
+			}
+			break
+		}
+		path, err := c.save(path)
+		if err != nil {
+			printError("Error:", err)
+		} else {
+			fmt.Printf("Saved the conversation to %s\n", path)
+		}
+	case "/help":
+		fmt.Println(llmChatHelp)
+	default:
+		if strings.HasPrefix(fields[0], "/") {
+			fmt.Printf("Unknown command %s\n%s\n", fields[0], llmChatHelp)
+			return true, false
+		}
+		return false, false
+	}
+	return true, false
+}
+
+// llmInput puts messages together from the lines typed in an interactive
+// session: a line ending in a backslash continues on the next line, and
+// lines between two lines of """ are taken as they are, for pasting text
+type llmInput struct {
+	lines   []string
+	inBlock bool
+}
+
+// add adds a line, and returns the message once it is complete
+func (in *llmInput) add(line string) (string, bool) {
+	if strings.TrimSpace(line) == `"""` {
+		if in.inBlock {
+			in.inBlock = false
+			return in.message()
+		}
+		in.inBlock = true
+		return "", false
+	}
+	if in.inBlock {
+		in.lines = append(in.lines, line)
+		return "", false
+	}
+	if strings.HasSuffix(line, "\\") {
+		in.lines = append(in.lines, strings.TrimSuffix(line, "\\"))
+		return "", false
+	}
+	in.lines = append(in.lines, line)
+	return in.message()
+}
+
+// message returns the lines added as a message, and starts a new one
+func (in *llmInput) message() (string, bool) {
+	message := strings.Join(in.lines, "\n")
+	in.lines = nil
+	return message, true
+}
+
+// pending reports whether a message has been started but not completed
+func (in *llmInput) pending() bool {
+	return in.inBlock || len(in.lines) > 0
+}
+
+// discard drops the message being typed
+func (in *llmInput) discard() {
+	in.lines = nil
+	in.inBlock = false
+}
+
+// prompt returns the prompt for the next line
+func (in *llmInput) prompt() string {
+	if in.pending() {
+		return "... "
+	}
+	return "> "
+}
+
+// newLLMLineReader returns a line editor for an interactive session, with
+// the history of earlier sessions
+func newLLMLineReader() (*readline.Instance, error) {
+	historyFile := ""
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(homeDir, ".plannet", "llm_history")
+		os.MkdirAll(filepath.Dir(historyFile), 0755)
+	}
+	return readline.NewEx(&readline.Config{
+		Prompt:      "> ",
+		HistoryFile: historyFile,
+	})
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestLLMChatHistory(t *testing.T) {
+	chat := newLLMChat(&config.Config{SystemPrompt: "Be brief."})
+	if chat.maxTokens != defaultLLMContextTokens {
+		t.Errorf("maxTokens = %d, want the default %d", chat.maxTokens, defaultLLMContextTokens)
+	}
+
+	chat.ask("What is a token bucket?")
+	chat.reply("A rate limiting algorithm.")
+	conv := chat.ask("How do I implement it?")
+	if conv.System != "Be brief." || len(conv.Messages) != 3 {
+		t.Fatalf("ask() = %+v, want the system prompt and 3 messages", conv)
+	}
+	if conv.Messages[1].Role != "assistant" || conv.Messages[2].Content != "How do I implement it?" {
+		t.Errorf("ask() messages = %+v", conv.Messages)
+	}
+
+	// A failed request leaves no trace, so the message can be sent again
+	chat.reply("")
+	if len(chat.messages) != 2 {
+		t.Errorf("After a failed request, %d messages, want 2", len(chat.messages))
+	}
+
+	chat.reset()
+	if conv := chat.ask("Hello"); len(conv.Messages) != 1 {
+		t.Errorf("After /reset, ask() sent %d messages, want 1", len(conv.Messages))
+	}
+}
+
+func TestLLMChatWindow(t *testing.T) {
+	chat := newLLMChat(&config.Config{LLMContextTokens: 20})
+	chat.ask(strings.Repeat("a", 40))
+	chat.reply(strings.Repeat("b", 40))
+	conv := chat.ask("Short question")
+	if len(conv.Messages) != 1 || conv.Messages[0].Content != "Short question" {
+		t.Errorf("ask() = %+v, want only the last message to fit", conv.Messages)
+	}
+	if len(chat.messages) != 3 {
+		t.Errorf("History has %d messages, want all 3 kept", len(chat.messages))
+	}
+}
+
+func TestLLMChatCommands(t *testing.T) {
+	chat := newLLMChat(&config.Config{})
+	tests := []struct {
+		input         string
+		handled, quit bool
+	}{
+		{"exit", true, true},
+		{"/quit", true, true},
+		{"exit codes in bash?", false, false},
+		{"/help", true, false},
+		{"/unknown", true, false},
+		{"How do I /reset a branch?", false, false},
+	}
+	for _, tt := range tests {
+		handled, quit := chat.runCommand(tt.input)
+		if handled != tt.handled || quit != tt.quit {
+			t.Errorf("runCommand(%q) = %v, %v, want %v, %v", tt.input, handled, quit, tt.handled, tt.quit)
+		}
+	}
+
+	chat.ask("What is a token bucket?")
+	chat.reply("A rate limiting algorithm.")
+	path := filepath.Join(t.TempDir(), "chat.md")
+	if handled, _ := chat.runCommand("/save " + path); !handled {
+		t.Fatal("runCommand(/save) not handled")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Conversation not saved: %v", err)
+	}
+	want := "# Conversation with the LLM\n\n## You\n\nWhat is a token bucket?\n\n## LLM\n\nA rate limiting algorithm.\n"
+	if string(data) != want {
+		t.Errorf("Saved %q, want %q", data, want)
+	}
+
+	chat.runCommand("/reset")
+	if len(chat.messages) != 0 {
+		t.Errorf("After /reset, %d messages, want none", len(chat.messages))
+	}
+	if _, err := chat.save(path); err == nil {
+		t.Error("save() of an empty conversation succeeded, want an error")
+	}
+}
+
+func TestLLMInput(t *testing.T) {
+	var in llmInput
+	if in.prompt() != "> " {
+		t.Errorf("prompt() = %q, want %q", in.prompt(), "> ")
+	}
+	if message, ok := in.add("How do I rate limit in Go?"); !ok || message != "How do I rate limit in Go?" {
+		t.Errorf("add() = %q, %v, want the line as a message", message, ok)
+	}
+
+	// A trailing backslash continues the message
+	if _, ok := in.add(`First line \`); ok {
+		t.Error("add() completed a message ending in a backslash")
+	}
+	if in.prompt() != "... " {
+		t.Errorf("prompt() = %q while continuing, want %q", in.prompt(), "... ")
+	}
+	if message, ok := in.add("second line"); !ok || message != "First line \nsecond line" {
+		t.Errorf("add() = %q, %v, want both lines", message, ok)
+	}
+
+	// Lines between """ are taken as they are
+	lines := []string{`"""`, "func main() {", `	fmt.Println("hi") \`, "", "}", `"""`}
+	var message string
+	var ok bool
+	for _, line := range lines {
+		if ok {
+			t.Fatalf("add() completed the message before the closing line")
+		}
+		message, ok = in.add(line)
+	}
+	want := "func main() {\n\tfmt.Println(\"hi\") \\\n\n}"
+	if !ok || message != want {
+		t.Errorf("add() = %q, %v, want %q", message, ok, want)
+	}
+
+	in.add(`"""`)
+	in.add("pasted")
+	in.discard()
+	if in.pending() {
+		t.Error("pending() after discard() = true, want false")
+	}
+}
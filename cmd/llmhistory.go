@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/spf13/cobra"
+)
+
+// savedChatIDFormat formats the time a conversation started as its ID
+const savedChatIDFormat = "20060102-150405"
+
+// savedChat is a conversation of an interactive session, saved with /save
+type savedChat struct {
+	ID       string        `json:"id"`
+	Started  time.Time     `json:"started"`
+	Updated  time.Time     `json:"updated"`
+	Model    string        `json:"model,omitempty"`
+	System   string        `json:"system,omitempty"`
+	Messages []llm.Message `json:"messages"`
+}
+
+// llmHistoryCmd represents the llm history command
+var llmHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the conversations saved with /save",
+	Long: `List the conversations of interactive sessions saved with /save, most
+recent first. They are kept in ~/.plannet/chats.
+
+Examples:
+  plannet llm history
+  plannet llm history show 20240503-091500
+  plannet llm history export 20240503-091500 design.md`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chats, err := readSavedChats()
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			if chats == nil {
+				chats = []savedChat{}
+			}
+			printJSON(chats)
+			return nil
+		}
+		printSavedChats(os.Stdout, chats)
+		return nil
+	},
+}
+
+// llmHistoryShowCmd represents the llm history show command
+var llmHistoryShowCmd = &cobra.Command{
+	Use:               "show <id>",
+	Short:             "Show a saved conversation as markdown",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSavedChatIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chat, err := readSavedChat(args[0])
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			printJSON(chat)
+			return nil
+		}
+		fmt.Print(chatMarkdown(chat.Messages))
+		return nil
+	},
+}
+
+// llmHistoryExportCmd represents the llm history export command
+var llmHistoryExportCmd = &cobra.Command{
+	Use:               "export <id> [file]",
+	Short:             "Export a saved conversation to a markdown file",
+	Long:              `Export a saved conversation to a markdown file, plannet-llm-<id>.md unless given.`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeSavedChatIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		chat, err := readSavedChat(args[0])
+		if err != nil {
+			return err
+		}
+		path := "plannet-llm-" + chat.ID + ".md"
+		if len(args) > 1 {
+			path = args[1]
+		}
+		if err := os.WriteFile(path, []byte(chatMarkdown(chat.Messages)), 0644); err != nil {
+			return fmt.Errorf("failed to export conversation: %w", err)
+		}
+		fmt.Printf("Exported the conversation to %s\n", path)
+		return nil
+	},
+}
+
+// llmHistoryDeleteCmd represents the llm history delete command
+var llmHistoryDeleteCmd = &cobra.Command{
+	Use:               "delete <id>",
+	Short:             "Delete a saved conversation",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSavedChatIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := savedChatPath(args[0])
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no saved conversation %q", args[0])
+			}
+			return fmt.Errorf("failed to delete conversation: %w", err)
+		}
+		fmt.Printf("Deleted conversation %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmHistoryCmd)
+	llmHistoryCmd.AddCommand(llmHistoryShowCmd)
+	llmHistoryCmd.AddCommand(llmHistoryExportCmd)
+	llmHistoryCmd.AddCommand(llmHistoryDeleteCmd)
+	supportJSONOutput(llmHistoryCmd)
+	supportJSONOutput(llmHistoryShowCmd)
+}
+
+// getSavedChatsDir returns the directory saved conversations are kept in
+func getSavedChatsDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".plannet", "chats"), nil
+}
+
+// savedChatPath returns the path of the saved conversation with an ID
+func savedChatPath(id string) (string, error) {
+	if _, err := time.Parse(savedChatIDFormat, id); err != nil {
+		return "", fmt.Errorf("invalid conversation ID %q; see 'plannet llm history'", id)
+	}
+	dir, err := getSavedChatsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".json"), nil
+}
+
+// writeSavedChat saves a conversation, replacing an earlier save of it
+func writeSavedChat(chat savedChat) error {
+	path, err := savedChatPath(chat.ID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create chats directory: %w", err)
+	}
+	data, err := json.MarshalIndent(chat, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return nil
+}
+
+// readSavedChat returns the saved conversation with an ID
+func readSavedChat(id string) (savedChat, error) {
+	var chat savedChat
+	path, err := savedChatPath(id)
+	if err != nil {
+		return chat, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return chat, fmt.Errorf("no saved conversation %q; see 'plannet llm history'", id)
+		}
+		return chat, fmt.Errorf("failed to read conversation: %w", err)
+	}
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return chat, fmt.Errorf("failed to parse conversation %s: %w", id, err)
+	}
+	return chat, nil
+}
+
+// readSavedChats returns the saved conversations, most recent first. Files
+// that can't be read are skipped.
+func readSavedChats() ([]savedChat, error) {
+	dir, err := getSavedChatsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read chats directory: %w", err)
+	}
+
+	var chats []savedChat
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		if chat, err := readSavedChat(id); err == nil {
+			chats = append(chats, chat)
+		}
+	}
+	sort.Slice(chats, func(i, j int) bool {
+		return chats[i].Updated.After(chats[j].Updated)
+	})
+	return chats, nil
+}
+
+// title returns the first line of the conversation's first message
+func (c savedChat) title() string {
+	for _, message := range c.Messages {
+		if message.Role != "user" {
+			continue
+		}
+		title, _, _ := strings.Cut(strings.TrimSpace(message.Content), "\n")
+		if len(title) > 60 {
+			title = strings.TrimSpace(title[:57]) + "..."
+		}
+		return title
+	}
+	return ""
+}
+
+// printSavedChats prints the saved conversations as a list
+func printSavedChats(w io.Writer, chats []savedChat) {
+	if len(chats) == 0 {
+		fmt.Fprintln(w, "No saved conversations. Type /save in 'plannet llm' to save one.")
+		return
+	}
+	for _, chat := range chats {
+		fmt.Fprintf(w, "%s  %s  %3d messages  %s\n", chat.ID, chat.Updated.Local().Format("2006-01-02 15:04"), len(chat.Messages), chat.title())
+	}
+}
+
+// completeSavedChatIDs completes the IDs of saved conversations
+func completeSavedChatIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	chats, err := readSavedChats()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, chat := range chats {
+		if strings.HasPrefix(chat.ID, toComplete) {
+			ids = append(ids, chat.ID+"\t"+chat.title())
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestLLMChatKeep(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	chat := newLLMChat(&config.Config{SystemPrompt: "Be brief.", Model: "m"})
+	chat.started = time.Date(2024, 5, 3, 9, 15, 0, 0, time.Local)
+	if _, err := chat.keep(); err == nil {
+		t.Error("keep() of an empty conversation succeeded, want an error")
+	}
+
+	chat.ask("How should sync resolve conflicts?\nWe have two devices.")
+	chat.reply("Keep the most recent edit.")
+	if handled, _ := chat.runCommand("/save"); !handled {
+		t.Fatal("runCommand(/save) not handled")
+	}
+	chat.ask("And deletions?")
+	chat.reply("Keep tombstones.")
+	id, err := chat.keep()
+	if err != nil {
+		t.Fatalf("keep() failed: %v", err)
+	}
+	if id != "20240503-091500" {
+		t.Errorf("keep() = %q, want 20240503-091500", id)
+	}
+
+	chat.reset()
+	chat.started = time.Date(2024, 5, 4, 10, 0, 0, 0, time.Local)
+	chat.ask("Another question")
+	chat.reply("Another answer")
+	if _, err := chat.keep(); err != nil {
+		t.Fatalf("keep() failed: %v", err)
+	}
+
+	chats, err := readSavedChats()
+	if err != nil {
+		t.Fatalf("readSavedChats() failed: %v", err)
+	}
+	if len(chats) != 2 || chats[0].ID != "20240504-100000" || chats[1].ID != id {
+		t.Fatalf("readSavedChats() = %+v, want both conversations, most recent first", chats)
+	}
+	saved := chats[1]
+	if saved.Model != "m" || saved.System != "Be brief." || len(saved.Messages) != 4 {
+		t.Errorf("Saved conversation = %+v, want all 4 messages of the second save", saved)
+	}
+	if saved.title() != "How should sync resolve conflicts?" {
+		t.Errorf("title() = %q", saved.title())
+	}
+
+	var out bytes.Buffer
+	printSavedChats(&out, chats)
+	if !strings.Contains(out.String(), "20240503-091500") || !strings.Contains(out.String(), "  4 messages  How should sync") {
+		t.Errorf("printSavedChats() = %q", out.String())
+	}
+	if !strings.Contains(chatMarkdown(saved.Messages), "## LLM\n\nKeep tombstones.\n") {
+		t.Errorf("chatMarkdown() = %q", chatMarkdown(saved.Messages))
+	}
+
+	if _, err := readSavedChat("../config"); err == nil {
+		t.Error("readSavedChat() of an invalid ID succeeded, want an error")
+	}
+	if _, err := readSavedChat("20200101-000000"); err == nil {
+		t.Error("readSavedChat() of a missing conversation succeeded, want an error")
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// llmUsageCmd represents the llm usage command
+var llmUsageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show this month's LLM token usage and cost",
+	Long: `Show the tokens plannet's LLM requests used this month, by day, and what
+they cost as estimated from llm_pricing, against llm_monthly_budget.
+
+Tokens marked with ~ were estimated from the text, for APIs that don't
+report them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		records, err := llm.LoadUsage()
+		if err != nil {
+			return err
+		}
+		if jsonOutput() {
+			printJSON(newLLMUsageOutput(cfg, records, time.Now()))
+			return nil
+		}
+		printLLMUsage(os.Stdout, cfg, records, time.Now())
+		return nil
+	},
+}
+
+func init() {
+	llmCmd.AddCommand(llmUsageCmd)
+	supportJSONOutput(llmUsageCmd)
+	llm.OnBudgetExceeded = warnBudgetExceeded
+}
+
+// warnBudgetExceeded warns that a request is sent over the monthly budget
+func warnBudgetExceeded(spent, budget float64) {
+	fmt.Fprintln(os.Stderr, output.Warning(fmt.Sprintf("Warning: spent an estimated %.2f of the %.2f monthly LLM budget", spent, budget)))
+}
+
+// llmUsageOutput is the usage in a month as --output json prints it: the
+// usage of each model by day, and their estimated cost
+type llmUsageOutput struct {
+	Month   string            `json:"month"`
+	Records []llm.UsageRecord `json:"records"`
+	Cost    float64           `json:"cost"`
+	Budget  float64           `json:"budget,omitempty"`
+}
+
+// newLLMUsageOutput returns the usage in the month of now
+func newLLMUsageOutput(cfg *config.Config, records []llm.UsageRecord, now time.Time) llmUsageOutput {
+	result := llmUsageOutput{Month: now.Format("2006-01"), Records: []llm.UsageRecord{}, Budget: cfg.LLMMonthlyBudget}
+	for _, record := range records {
+		if strings.HasPrefix(record.Date, result.Month) {
+			result.Records = append(result.Records, record)
+			result.Cost += record.Cost
+		}
+	}
+	return result
+}
+
+// printLLMUsage prints the usage in the month of now, by day, with the
+// month's total against the budget
+func printLLMUsage(w io.Writer, cfg *config.Config, records []llm.UsageRecord, now time.Time) {
+	month := now.Format("2006-01")
+	days := make(map[string]*llm.UsageRecord)
+	for _, record := range records {
+		if !strings.HasPrefix(record.Date, month) {
+			continue
+		}
+		day, ok := days[record.Date]
+		if !ok {
+			day = &llm.UsageRecord{Date: record.Date}
+			days[record.Date] = day
+		}
+		day.Requests += record.Requests
+		day.PromptTokens += record.PromptTokens
+		day.CompletionTokens += record.CompletionTokens
+		day.Cost += record.Cost
+		day.Estimated += record.Estimated
+	}
+	if len(days) == 0 {
+		fmt.Fprintf(w, "No LLM usage in %s.\n", now.Format("January 2006"))
+		return
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var total llm.UsageRecord
+	fmt.Fprintf(w, "%-10s  %8s  %12s  %12s  %10s\n", "Date", "Requests", "Prompt", "Completion", "Cost")
+	for _, date := range dates {
+		day := days[date]
+		printLLMUsageRow(w, date, day)
+		total.Requests += day.Requests
+		total.PromptTokens += day.PromptTokens
+		total.CompletionTokens += day.CompletionTokens
+		total.Cost += day.Cost
+		total.Estimated += day.Estimated
+	}
+	printLLMUsageRow(w, "Total", &total)
+
+	if cfg.LLMMonthlyBudget > 0 {
+		fmt.Fprintf(w, "\nBudget: %.2f of %.2f spent (%.0f%%)\n", total.Cost, cfg.LLMMonthlyBudget, total.Cost/cfg.LLMMonthlyBudget*100)
+	}
+	if len(cfg.LLMPricing) == 0 {
+		fmt.Fprintln(w, "\nSet llm_pricing in your configuration to estimate costs.")
+	}
+}
+
+// printLLMUsageRow prints a row of the usage table
+func printLLMUsageRow(w io.Writer, label string, usage *llm.UsageRecord) {
+	mark := ""
+	if usage.Estimated > 0 {
+		mark = "~"
+	}
+	fmt.Fprintf(w, "%-10s  %8d  %12s  %12s  %10.4f\n", label, usage.Requests,
+		mark+fmt.Sprint(usage.PromptTokens), mark+fmt.Sprint(usage.CompletionTokens), usage.Cost)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+)
+
+func TestPrintLLMUsage(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.Local)
+	records := []llm.UsageRecord{
+		{Date: "2024-02-29", Model: "a", Requests: 9, PromptTokens: 9000, Cost: 9},
+		{Date: "2024-03-02", Model: "a", Requests: 1, PromptTokens: 100, CompletionTokens: 50, Cost: 1.5},
+		{Date: "2024-03-02", Model: "b", Requests: 2, PromptTokens: 200, CompletionTokens: 20, Cost: 0.5},
+		{Date: "2024-03-10", Model: "a", Requests: 1, PromptTokens: 10, CompletionTokens: 5, Cost: 1, Estimated: 1},
+	}
+	cfg := &config.Config{LLMMonthlyBudget: 10, LLMPricing: map[string]config.LLMPrice{"a": {}}}
+
+	var out bytes.Buffer
+	printLLMUsage(&out, cfg, records, now)
+	got := out.String()
+
+	for _, want := range []string{
+		"2024-03-02         3           300            70      2.0000",
+		"2024-03-10         1           ~10            ~5      1.0000",
+		"Total              4          ~310           ~75      3.0000",
+		"Budget: 3.00 of 10.00 spent (30%)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Usage is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "2024-02-29") {
+		t.Errorf("Usage shows last month:\n%s", got)
+	}
+
+	out.Reset()
+	printLLMUsage(&out, cfg, nil, now)
+	if !strings.Contains(out.String(), "No LLM usage in March 2024") {
+		t.Errorf("Usage without records = %q", out.String())
+	}
+}
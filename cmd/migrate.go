@@ -0,0 +1,517 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaFile is the name of the file recording the database schema version
+const schemaFile = "schema.json"
+
+// rawWork is a tracked work record as stored on disk. Migrations work on raw
+// records rather than TrackedWork so that they keep working as the struct evolves.
+type rawWork map[string]interface{}
+
+// migration upgrades the database in dbDir from version-1 to version
+type migration struct {
+	version     int
+	description string
+	migrate     func(dbDir string) error
+}
+
+// migrations lists every schema migration in order. Append new migrations
+// here when the storage format changes; never edit a released one.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "Consolidate legacy work files into active, paused and completed lists",
+		migrate:     migrateConsolidateWorkFiles,
+	},
+	{
+		version:     2,
+		description: "Link work to a list of tickets instead of a single ticket",
+		migrate:     migrateTicketLists,
+	},
+}
+
+// currentSchemaVersion is the schema version written by this version of plannet
+var currentSchemaVersion = migrations[len(migrations)-1].version
+
+// schemaInfo is the content of the schema file
+type schemaInfo struct {
+	Version    int       `json:"version"`
+	MigratedAt time.Time `json:"migrated_at,omitempty"`
+}
+
+// checkedSchemaDirs records database directories already known to be current
+var checkedSchemaDirs = map[string]bool{}
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the tracked work database",
+	Long: `Upgrade the tracked work database to the current schema version.
+Older versions of plannet stored work in a different layout. This command
+backs up the database directory and then upgrades the data files in place.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		runMigrate(dryRun)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().Bool("dry-run", false, "Show pending migrations without applying them")
+}
+
+func runMigrate(dryRun bool) {
+	dbDir, err := getDBDir()
+	if err != nil {
+		printError("Error getting database directory:", err)
+		return
+	}
+
+	version, err := readSchemaVersion(dbDir)
+	if err != nil {
+		printError("Error reading schema version:", err)
+		return
+	}
+
+	if version > currentSchemaVersion {
+		fmt.Printf("The database uses schema version %d, which is newer than this version of plannet supports (%d).\n", version, currentSchemaVersion)
+		fmt.Println("Please upgrade plannet.")
+		return
+	}
+
+	pending := pendingMigrations(version)
+	if len(pending) == 0 {
+		fmt.Printf("Database is up to date (schema version %d).\n", version)
+		return
+	}
+
+	fmt.Printf("Database schema version: %d, current version: %d\n", version, currentSchemaVersion)
+	fmt.Println("Pending migrations:")
+	for _, m := range pending {
+		fmt.Printf("  %d: %s\n", m.version, m.description)
+	}
+
+	if dryRun {
+		return
+	}
+
+	backupDir, err := backupDBDir(dbDir, version)
+	if err != nil {
+		printError("Error backing up database:", err)
+		return
+	}
+	fmt.Printf("Backed up database to %s\n", backupDir)
+
+	if err := runMigrations(dbDir, version); err != nil {
+		printError("Error migrating database:", err)
+		fmt.Printf("Your data is unchanged in %s\n", backupDir)
+		return
+	}
+
+	fmt.Printf("Database migrated to schema version %d.\n", currentSchemaVersion)
+}
+
+// openDB returns the database directory after making sure its schema is current
+func openDB() (string, error) {
+	dbDir, err := getDBDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get database directory: %w", err)
+	}
+
+	if err := checkDBDir(dbDir); err != nil {
+		return "", err
+	}
+	return dbDir, nil
+}
+
+// checkDBDir makes sure the schema of the database in dbDir is current
+func checkDBDir(dbDir string) error {
+	if checkedSchemaDirs[dbDir] {
+		return nil
+	}
+
+	version, err := readSchemaVersion(dbDir)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case version > currentSchemaVersion:
+		return fmt.Errorf("database schema version %d is newer than this version of plannet supports (%d); please upgrade plannet", version, currentSchemaVersion)
+	case version < currentSchemaVersion:
+		return fmt.Errorf("database schema version %d is out of date; run 'plannet migrate' to upgrade it", version)
+	}
+
+	// New databases are stamped with the current version on first use
+	if _, err := os.Stat(filepath.Join(dbDir, schemaFile)); os.IsNotExist(err) {
+		if err := writeSchemaVersion(dbDir, currentSchemaVersion); err != nil {
+			return err
+		}
+	}
+
+	checkedSchemaDirs[dbDir] = true
+	return nil
+}
+
+// readSchemaVersion returns the schema version of the database in dbDir.
+// A database without a schema file is either new (and therefore current)
+// or was written before versioning existed (version 0).
+func readSchemaVersion(dbDir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dbDir, schemaFile))
+	if err == nil {
+		var info schemaInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", schemaFile, err)
+		}
+		return info.Version, nil
+	}
+	if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read %s: %w", schemaFile, err)
+	}
+
+	hasData, err := hasWorkFiles(dbDir)
+	if err != nil {
+		return 0, err
+	}
+	if hasData {
+		return 0, nil
+	}
+	return currentSchemaVersion, nil
+}
+
+// writeSchemaVersion records the schema version of the database in dbDir
+func writeSchemaVersion(dbDir string, version int) error {
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(schemaInfo{Version: version, MigratedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema info: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dbDir, schemaFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", schemaFile, err)
+	}
+	return nil
+}
+
+// hasWorkFiles reports whether dbDir contains any tracked work data
+func hasWorkFiles(dbDir string) (bool, error) {
+	entries, err := os.ReadDir(dbDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read database directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") && entry.Name() != schemaFile {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// pendingMigrations returns the migrations needed to upgrade from version
+func pendingMigrations(version int) []migration {
+	var pending []migration
+	for _, m := range migrations {
+		if m.version > version {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// runMigrations applies all pending migrations in order, recording the
+// schema version after each one so a failure leaves a consistent state
+func runMigrations(dbDir string, version int) error {
+	for _, m := range pendingMigrations(version) {
+		if err := m.migrate(dbDir); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.description, err)
+		}
+		if err := writeSchemaVersion(dbDir, m.version); err != nil {
+			return err
+		}
+	}
+
+	delete(checkedSchemaDirs, dbDir)
+	return nil
+}
+
+// backupDBDir copies the database directory next to itself before migrating
+func backupDBDir(dbDir string, version int) (string, error) {
+	backupDir := fmt.Sprintf("%s-backup-v%d-%s", dbDir, version, time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(dbDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read database directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := copyFile(filepath.Join(dbDir, entry.Name()), filepath.Join(backupDir, entry.Name())); err != nil {
+			return "", err
+		}
+	}
+	return backupDir, nil
+}
+
+// copyFile copies a single file, preserving its permissions
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
+
+// readRawWorkFile reads a list of raw work records. A missing file is an empty list.
+func readRawWorkFile(path string) ([]rawWork, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []rawWork{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var records []rawWork
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return records, nil
+}
+
+// writeRawWorkFile writes a list of raw work records
+func writeRawWorkFile(path string, records []rawWork) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// rawString returns a string field of a raw record
+func rawString(record rawWork, key string) string {
+	s, _ := record[key].(string)
+	return s
+}
+
+// hasEndTime reports whether a raw record has a non-zero end time
+func hasEndTime(record rawWork) bool {
+	end := rawString(record, "end_time")
+	if end == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, end)
+	return err == nil && !t.IsZero()
+}
+
+// migrateConsolidateWorkFiles upgrades the original storage layout. Early versions
+// stored stray single-record JSON files in the database directory, appended
+// duplicate entries to completed.json and left completed work in active.json
+// with its status unchanged. Every record is sorted into active.json,
+// paused.json or completed.json, with one entry per ID.
+func migrateConsolidateWorkFiles(dbDir string) error {
+	var active rawWork
+	paused := map[string]rawWork{}
+	completed := map[string]rawWork{}
+	seen := map[string]bool{}
+	var order []string
+
+	place := func(record rawWork) {
+		id := rawString(record, "id")
+		if id == "" {
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			order = append(order, id)
+		}
+		if active != nil && rawString(active, "id") == id {
+			active = nil
+		}
+		delete(paused, id)
+		delete(completed, id)
+
+		switch {
+		case hasEndTime(record) || rawString(record, "status") == "completed":
+			record["status"] = "completed"
+			completed[id] = record
+		case rawString(record, "status") == "paused":
+			paused[id] = record
+		default:
+			if active != nil && rawString(active, "id") != id {
+				// Only one piece of work can be active; keep the older one paused
+				active["status"] = "paused"
+				paused[rawString(active, "id")] = active
+			}
+			record["status"] = "active"
+			active = record
+		}
+	}
+
+	// Known list files first, so that later duplicates win
+	for _, name := range []string{"completed.json", "paused.json"} {
+		records, err := readRawWorkFile(filepath.Join(dbDir, name))
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			place(record)
+		}
+	}
+
+	// Stray single-record files, including active.json
+	entries, err := os.ReadDir(dbDir)
+	if err != nil {
+		return fmt.Errorf("failed to read database directory: %w", err)
+	}
+	var strayFiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		if name == schemaFile || name == "completed.json" || name == "paused.json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dbDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		var record rawWork
+		if err := json.Unmarshal(data, &record); err != nil {
+			fmt.Printf("Skipping %s: not a tracked work record (%v)\n", name, err)
+			continue
+		}
+		place(record)
+		if name != "active.json" {
+			strayFiles = append(strayFiles, name)
+		}
+	}
+
+	// Write the consolidated lists, keeping the original order
+	var pausedList, completedList []rawWork
+	for _, id := range order {
+		if record, ok := paused[id]; ok {
+			pausedList = append(pausedList, record)
+		}
+		if record, ok := completed[id]; ok {
+			completedList = append(completedList, record)
+		}
+	}
+
+	if err := writeRawWorkFile(filepath.Join(dbDir, "completed.json"), completedList); err != nil {
+		return err
+	}
+	if err := writeRawWorkFile(filepath.Join(dbDir, "paused.json"), pausedList); err != nil {
+		return err
+	}
+
+	activeFile := filepath.Join(dbDir, "active.json")
+	if active != nil {
+		data, err := json.MarshalIndent(active, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal active work: %w", err)
+		}
+		if err := os.WriteFile(activeFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write active work file: %w", err)
+		}
+	} else if err := os.Remove(activeFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove active work file: %w", err)
+	}
+
+	for _, name := range strayFiles {
+		if err := os.Remove(filepath.Join(dbDir, name)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// migrateTicketLists replaces the single ticket_id of every record with a
+// ticket_ids list, so that work can be linked to several tickets
+func migrateTicketLists(dbDir string) error {
+	convert := func(record rawWork) {
+		if ticketID := rawString(record, "ticket_id"); ticketID != "" {
+			record["ticket_ids"] = []string{ticketID}
+		}
+		delete(record, "ticket_id")
+	}
+
+	for _, name := range []string{"completed.json", "paused.json"} {
+		path := filepath.Join(dbDir, name)
+		records, err := readRawWorkFile(path)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			convert(record)
+		}
+		if err := writeRawWorkFile(path, records); err != nil {
+			return err
+		}
+	}
+
+	activeFile := filepath.Join(dbDir, "active.json")
+	data, err := os.ReadFile(activeFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read active work file: %w", err)
+	}
+	var active rawWork
+	if err := json.Unmarshal(data, &active); err != nil {
+		return fmt.Errorf("failed to parse active work data: %w", err)
+	}
+	convert(active)
+	data, err = json.MarshalIndent(active, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal active work: %w", err)
+	}
+	if err := os.WriteFile(activeFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write active work file: %w", err)
+	}
+	return nil
+}
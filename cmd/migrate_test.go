@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	dbDir := filepath.Join(tempDir, ".plannet", "db")
+	files := map[string]string{
+		// Old saves appended duplicates to completed.json
+		"completed.json": `[
+			{"id": "tw-1", "description": "First", "start_time": "2024-05-01T09:00:00Z", "end_time": "2024-05-01T10:00:00Z", "status": "completed"},
+			{"id": "tw-1", "description": "First (again)", "start_time": "2024-05-01T09:00:00Z", "end_time": "2024-05-01T10:30:00Z", "status": "completed"}
+		]`,
+		// Old complete left finished work in active.json
+		"active.json": `{"id": "tw-2", "description": "Finished", "start_time": "2024-05-02T09:00:00Z", "end_time": "2024-05-02T11:00:00Z", "status": "active"}`,
+		// Stray single-record files were read by the original list command
+		"tw-3.json": `{"id": "tw-3", "description": "Paused", "start_time": "2024-05-03T09:00:00Z", "status": "paused"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dbDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// The legacy database must be migrated before use
+	if _, err := getTrackedWork(); err == nil {
+		t.Fatal("Expected an error for a database that needs migrating")
+	}
+
+	version, err := readSchemaVersion(dbDir)
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if version != 0 {
+		t.Fatalf("Expected legacy schema version 0, got %d", version)
+	}
+
+	if err := runMigrations(dbDir, version); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work after migrating: %v", err)
+	}
+
+	statuses := map[string]string{}
+	for _, work := range trackedWork {
+		if _, dup := statuses[work.ID]; dup {
+			t.Errorf("Duplicate work %s after migrating", work.ID)
+		}
+		statuses[work.ID] = work.Status
+	}
+
+	want := map[string]string{"tw-1": "completed", "tw-2": "completed", "tw-3": "paused"}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("Expected %s to be %s, got %q", id, status, statuses[id])
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dbDir, "tw-3.json")); !os.IsNotExist(err) {
+		t.Error("Expected stray work file to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dbDir, "active.json")); !os.IsNotExist(err) {
+		t.Error("Expected active.json to be removed once its work was completed")
+	}
+}
+
+func TestNewDatabaseIsCurrent(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	if _, err := openDB(); err != nil {
+		t.Fatalf("Failed to open new database: %v", err)
+	}
+
+	version, err := readSchemaVersion(filepath.Join(tempDir, ".plannet", "db"))
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if version != currentSchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", currentSchemaVersion, version)
+	}
+}
+
+func TestMigrateTicketLists(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	dbDir := filepath.Join(tempDir, ".plannet", "db")
+	files := map[string]string{
+		"completed.json": `[{"id": "tw-1", "description": "Fix", "ticket_id": "PROJ-1", "start_time": "2024-05-01T09:00:00Z", "end_time": "2024-05-01T10:00:00Z", "status": "completed"}]`,
+		"active.json":    `{"id": "tw-2", "description": "Meeting", "start_time": "2024-05-02T09:00:00Z", "status": "active"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dbDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := writeSchemaVersion(dbDir, 1); err != nil {
+		t.Fatalf("Failed to write schema version: %v", err)
+	}
+
+	if err := runMigrations(dbDir, 1); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work after migrating: %v", err)
+	}
+	tickets := map[string][]string{}
+	for _, work := range trackedWork {
+		tickets[work.ID] = work.TicketIDs
+	}
+	if len(tickets["tw-1"]) != 1 || tickets["tw-1"][0] != "PROJ-1" {
+		t.Errorf("Expected tw-1 to be linked to PROJ-1, got %v", tickets["tw-1"])
+	}
+	if len(tickets["tw-2"]) != 0 {
+		t.Errorf("Expected tw-2 to have no tickets, got %v", tickets["tw-2"])
+	}
+}
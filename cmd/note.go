@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note [text]",
+	Short: "Add a note to the active work",
+	Long: `Add a timestamped note to the active work, to record findings and
+decisions mid-task. Notes are shown by 'plannet now' and included in exports.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runNote(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+}
+
+func runNote(args []string) {
+	// Load configuration
+	_, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil {
+		printError("Error getting active work:", err)
+		return
+	}
+	if activeWork == nil {
+		fmt.Println("No active work to add a note to. Start tracking with 'plannet track'.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	// Get note text from args or prompt
+	var text string
+	if len(args) > 0 {
+		text = strings.Join(args, " ")
+	} else {
+		prompt := promptui.Prompt{
+			Label: "Note",
+			Validate: func(input string) error {
+				if strings.TrimSpace(input) == "" {
+					return fmt.Errorf("note cannot be empty")
+				}
+				return nil
+			},
+		}
+		result, err := prompt.Run()
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error getting note:", err)
+			return
+		}
+		text = result
+	}
+
+	if err := addNote(activeWork, text, time.Now()); err != nil {
+		fmt.Println("Invalid note:", err)
+		setExitCode(exitUsage)
+		return
+	}
+	if err := saveTrackedWork(*activeWork); err != nil {
+		printError("Error saving note:", err)
+		return
+	}
+
+	fmt.Printf("Added note to %s\n", activeWork.Description)
+}
+
+// addNote appends a timestamped note to tracked work
+func addNote(work *TrackedWork, text string, at time.Time) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("note cannot be empty")
+	}
+	work.Notes = append(work.Notes, Note{Time: at, Text: text})
+	return nil
+}
+
+// formatNoteTime formats the time of a note, including the date only when
+// it differs from the day the work started
+func formatNoteTime(t, start time.Time) string {
+	if t.Format("2006-01-02") == start.Format("2006-01-02") {
+		return t.Format("15:04")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// formatNotes formats all notes of tracked work as one line per note
+func formatNotes(notes []Note) string {
+	lines := make([]string, 0, len(notes))
+	for _, note := range notes {
+		lines = append(lines, fmt.Sprintf("%s %s", note.Time.Format("2006-01-02 15:04"), note.Text))
+	}
+	return strings.Join(lines, "\n")
+}
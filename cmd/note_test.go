@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddNote(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	work := TrackedWork{ID: "test-1", Description: "Debug outage", StartTime: start, Status: "active"}
+
+	if err := addNote(&work, "Found the root cause", start.Add(90*time.Minute)); err != nil {
+		t.Fatalf("Failed to add note: %v", err)
+	}
+	if err := addNote(&work, "Deployed the fix", start.Add(24*time.Hour)); err != nil {
+		t.Fatalf("Failed to add note: %v", err)
+	}
+	if err := addNote(&work, " ", start); err == nil {
+		t.Error("Expected an error for an empty note")
+	}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	saved, err := getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	if len(saved.Notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(saved.Notes))
+	}
+
+	if got := formatNoteTime(saved.Notes[0].Time, start); got != "10:30" {
+		t.Errorf("Expected a note on the start day to show only the time, got %s", got)
+	}
+	if got := formatNoteTime(saved.Notes[1].Time, start); got != "2024-05-02 09:00" {
+		t.Errorf("Expected a note on a later day to show the date, got %s", got)
+	}
+
+	want := "2024-05-01 10:30 Found the root cause\n2024-05-02 09:00 Deployed the fix"
+	if got := formatNotes(saved.Notes); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
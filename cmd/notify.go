@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/notify"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/spf13/cobra"
+)
+
+// Events that show desktop notifications, as notifications.muted names them
+const (
+	notifyPomodoro    = "pomodoro"
+	notifyLongRunning = "long_running"
+	notifySync        = "sync"
+)
+
+// defaultLongRunningHours is how long work can stay active without a break
+// before a notification warns about it, unless configured otherwise
+const defaultLongRunningHours = 4
+
+// longRunningFile records the work a long-running notification was last
+// shown for, so it is shown once
+const longRunningFile = "long_running_notified"
+
+// sendNotification shows a desktop notification; tests replace it
+var sendNotification = notify.Send
+
+// notificationsEnabled reports whether an event shows desktop notifications
+func notificationsEnabled(cfg *config.Config, event string) bool {
+	if cfg.Notifications.Disabled {
+		return false
+	}
+	for _, muted := range cfg.Notifications.Muted {
+		if strings.EqualFold(muted, event) {
+			return false
+		}
+	}
+	return true
+}
+
+// notifyDesktop shows a desktop notification of an event, unless the
+// configuration turns it off. Notifications that can't be shown are only
+// logged at debug level.
+func notifyDesktop(event, title, message string) {
+	cfg, err := config.Load()
+	if err != nil || !notificationsEnabled(cfg, event) {
+		return
+	}
+	if err := sendNotification(title, message); err != nil {
+		logger.Debug("Failed to show desktop notification: %v", err)
+	}
+}
+
+// longRunningWindow returns how long work can stay active without a break
+// before a notification warns about it, or zero if the warning is disabled
+func longRunningWindow(cfg *config.Config) time.Duration {
+	switch {
+	case cfg.Notifications.LongRunningHours < 0:
+		return 0
+	case cfg.Notifications.LongRunningHours == 0:
+		return defaultLongRunningHours * time.Hour
+	default:
+		return time.Duration(cfg.Notifications.LongRunningHours) * time.Hour
+	}
+}
+
+// checkLongRunningWork warns with a desktop notification when the active
+// work has run without a break for longer than the long-running window
+func checkLongRunningWork(cmd *cobra.Command) {
+	if backgroundCheckSkipped[cmd.Name()] {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	notifyLongRunningWork(cfg, time.Now())
+}
+
+// notifyLongRunningWork shows the long-running notification of the active
+// work, once for each stretch of work since it was last started or resumed
+func notifyLongRunningWork(cfg *config.Config, now time.Time) {
+	window := longRunningWindow(cfg)
+	if window == 0 || !notificationsEnabled(cfg, notifyLongRunning) {
+		return
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil || activeWork == nil {
+		return
+	}
+	since := lastActivity(*activeWork)
+	if now.Sub(since) < window {
+		return
+	}
+
+	path, err := getLongRunningPath()
+	if err != nil {
+		return
+	}
+	record := fmt.Sprintf("%s %s", activeWork.ID, since.Format(time.RFC3339))
+	if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) == record {
+		return
+	}
+
+	message := fmt.Sprintf("%s has been active for %s without a break", activeWork.Description, formatDuration(now.Sub(since)))
+	if err := sendNotification("Still working?", message); err != nil {
+		logger.Debug("Failed to show desktop notification: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, []byte(record), 0644); err != nil {
+		logger.Debug("Failed to record long-running notification: %v", err)
+	}
+}
+
+// getLongRunningPath returns the path of the file recording the last
+// long-running notification
+func getLongRunningPath() (string, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create plannet directory: %w", err)
+	}
+	return filepath.Join(dataDir, longRunningFile), nil
+}
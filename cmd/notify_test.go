@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestNotificationsEnabled(t *testing.T) {
+	cfg := &config.Config{}
+	if !notificationsEnabled(cfg, notifyPomodoro) {
+		t.Error("notifications are off by default")
+	}
+
+	cfg.Notifications.Muted = []string{"Sync"}
+	if notificationsEnabled(cfg, notifySync) || !notificationsEnabled(cfg, notifyPomodoro) {
+		t.Error("muting sync notifications didn't mute only them")
+	}
+
+	cfg.Notifications = config.NotificationSettings{Disabled: true}
+	if notificationsEnabled(cfg, notifyPomodoro) {
+		t.Error("disabled notifications are still enabled")
+	}
+}
+
+func TestNotifyLongRunningWork(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	var sent []string
+	original := sendNotification
+	sendNotification = func(title, message string) error {
+		sent = append(sent, message)
+		return nil
+	}
+	defer func() { sendNotification = original }()
+
+	now := time.Now()
+	work := TrackedWork{ID: "work-1", Description: "Fix login", StartTime: now.Add(-5 * time.Hour), Status: "active"}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	notifyLongRunningWork(cfg, now)
+	notifyLongRunningWork(cfg, now.Add(time.Minute))
+	if len(sent) != 1 {
+		t.Fatalf("sent %d notifications for one stretch of work, want 1: %q", len(sent), sent)
+	}
+
+	// A new stretch after a break is warned about again once it runs long
+	work.Pauses = []PauseInterval{{Start: now.Add(-3 * time.Hour), End: now.Add(-2 * time.Hour)}}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatal(err)
+	}
+	notifyLongRunningWork(cfg, now)
+	if len(sent) != 1 {
+		t.Errorf("warned about work resumed 2 hours ago: %q", sent)
+	}
+	notifyLongRunningWork(cfg, now.Add(3*time.Hour))
+	if len(sent) != 2 {
+		t.Errorf("sent %d notifications after the new stretch ran long, want 2", len(sent))
+	}
+
+	cfg.Notifications.LongRunningHours = -1
+	if err := saveTrackedWork(TrackedWork{ID: "work-2", Description: "Review", StartTime: now.Add(-10 * time.Hour), Status: "active"}); err != nil {
+		t.Fatal(err)
+	}
+	notifyLongRunningWork(cfg, now)
+	if len(sent) != 2 {
+		t.Errorf("warned with the warning disabled: %q", sent)
+	}
+}
@@ -3,9 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/output"
 )
 
 // nowCmd represents the now command
@@ -15,7 +17,11 @@ var nowCmd = &cobra.Command{
 	Long: `Show what you're currently working on based on your git activity.
 This command looks at your current branch and recent commits to determine
 what you're focused on, including any "side quests" that aren't tracked
-in your ticketing system.`,
+in your ticketing system.
+Use --format to print it with a Go template instead, such as
+'{{.TicketID}} {{.Description}}' for a shell prompt: TicketID is the ticket
+of the current branch, Description that of the active work, and Active the
+active work itself, if any.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runNow()
 	},
@@ -23,17 +29,42 @@ in your ticketing system.`,
 
 func init() {
 	rootCmd.AddCommand(nowCmd)
+	supportJSONOutput(nowCmd)
+	addFormatFlag(nowCmd, "Print with a Go template, such as '{{.TicketID}} {{.Description}}'")
 }
 
 func runNow() {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
+	// Display the active work and its notes
+	activeWork, err := getActiveWork()
+	if err != nil {
+		printError("Error getting active work:", err)
+		return
+	}
+	if jsonOutput() || templateOutput() {
+		result, err := gatherNow(cfg, activeWork)
+		if err != nil {
+			fprintError(os.Stderr, "Error:", err)
+		}
+		if jsonOutput() {
+			printJSON(result)
+		} else if err := printFormatted(result); err != nil {
+			fprintError(os.Stderr, "Error:", err)
+		}
+		return
+	}
+	if activeWork != nil {
+		printActiveWork(*activeWork)
+		fmt.Println()
+	}
+
 	// Check if git integration is enabled
 	if !cfg.GitIntegration {
 		fmt.Println("Git integration is disabled. Enable it in your configuration.")
@@ -43,7 +74,7 @@ func runNow() {
 	// Get current directory
 	currentDir, err := os.Getwd()
 	if err != nil {
-		fmt.Println("Error getting current directory:", err)
+		printError("Error getting current directory:", err)
 		return
 	}
 
@@ -56,7 +87,7 @@ func runNow() {
 	// Get current branch
 	branchName, err := getCurrentBranch()
 	if err != nil {
-		fmt.Println("Error getting current branch:", err)
+		printError("Error getting current branch:", err)
 		return
 	}
 
@@ -66,37 +97,155 @@ func runNow() {
 	// Get recent commits
 	commits, err := getRecentCommits(5)
 	if err != nil {
-		fmt.Println("Error getting recent commits:", err)
+		printError("Error getting recent commits:", err)
 		return
 	}
 
 	// Display current focus
-	fmt.Println("Current focus:")
+	fmt.Println(output.Heading("Current focus:"))
 	if ticketID != "" {
-		fmt.Printf("  Branch: %s (%s)\n", branchName, ticketID)
+		fmt.Printf("  Branch: %s (%s)\n", branchName, output.Ticket(ticketID))
 	} else {
-		fmt.Printf("  Branch: %s (untracked work)\n", branchName)
+		fmt.Printf("  Branch: %s (%s)\n", branchName, output.SideQuest("untracked work"))
+	}
+
+	// Display work that hasn't been committed yet
+	if wip, err := getWorkInProgress(currentDir); err == nil && !wip.IsEmpty() {
+		fmt.Printf("\n%s\n", describeWorkInProgress(branchName, wip))
+		if details := wipDetails(wip); details != "" {
+			fmt.Printf("  %s\n", details)
+		}
 	}
 
 	// Display recent activity
-	fmt.Println("\nRecent activity:")
+	fmt.Println("\n" + output.Heading("Recent activity:"))
 	for _, commit := range commits {
 		// Check if commit has a ticket ID
-		commitTicketID := extractTicketIDFromMessage(commit.Message, cfg.TicketPrefixes)
+		commitTicketID := extractTicketIDFromMessage(commit.FullMessage(), cfg.TicketPrefixes)
 		
 		if commitTicketID != "" {
-			fmt.Printf("  %s: %s\n", commitTicketID, commit.Message)
+			fmt.Printf("  %s: %s\n", output.Ticket(commitTicketID), commit.Message)
 		} else {
-			fmt.Printf("  [untracked]: %s\n", commit.Message)
+			fmt.Printf("  %s: %s\n", output.SideQuest("[untracked]"), commit.Message)
 		}
 	}
 
 	// Find and display side quests
 	sideQuests := findSideQuests(commits, cfg.TicketPrefixes)
 	if len(sideQuests) > 0 {
-		fmt.Println("\nSide quests:")
+		fmt.Println("\n" + output.Heading("Side quests:"))
 		for _, quest := range sideQuests {
-			fmt.Printf("  %s\n", quest.Message)
+			fmt.Printf("  %s\n", output.SideQuest(quest.Message))
+		}
+	}
+}
+
+// nowOutput is what you're working on, as --output json prints it
+type nowOutput struct {
+	Active         *workOutput     `json:"active"`
+	Branch         string          `json:"branch,omitempty"`
+	TicketID       string          `json:"ticket_id,omitempty"`
+	WorkInProgress *WorkInProgress `json:"work_in_progress,omitempty"`
+	RecentCommits  []nowCommit     `json:"recent_commits,omitempty"`
+	SideQuests     []nowCommit     `json:"side_quests,omitempty"`
+}
+
+// Description returns the description of the active work, or "" when none
+// is active, for --format templates
+func (n nowOutput) Description() string {
+	if n.Active == nil {
+		return ""
+	}
+	return n.Active.Description
+}
+
+// nowCommit is a recent commit, with the ticket it references
+type nowCommit struct {
+	Hash     string `json:"hash"`
+	Message  string `json:"message"`
+	TicketID string `json:"ticket_id,omitempty"`
+}
+
+// gatherNow collects what 'plannet now' shows. The git activity is left
+// out when git integration is disabled or outside a repository, and when
+// reading it fails, what was read before is returned with the error.
+func gatherNow(cfg *config.Config, activeWork *TrackedWork) (nowOutput, error) {
+	var result nowOutput
+	if activeWork != nil {
+		active := newWorkOutput(*activeWork)
+		result.Active = &active
+	}
+	currentDir, err := os.Getwd()
+	if err != nil || !cfg.GitIntegration || !isGitRepo(currentDir) {
+		return result, nil
+	}
+
+	if result.Branch, err = getCurrentBranch(); err != nil {
+		return result, fmt.Errorf("failed to get current branch: %w", err)
+	}
+	result.TicketID = extractTicketID(result.Branch, cfg.TicketPrefixes)
+	if wip, err := getWorkInProgress(currentDir); err == nil && !wip.IsEmpty() {
+		result.WorkInProgress = &wip
+	}
+
+	commits, err := getRecentCommits(5)
+	if err != nil {
+		return result, fmt.Errorf("failed to get recent commits: %w", err)
+	}
+	for _, commit := range commits {
+		result.RecentCommits = append(result.RecentCommits, nowCommit{
+			Hash:     commit.Hash,
+			Message:  commit.Message,
+			TicketID: extractTicketIDFromMessage(commit.FullMessage(), cfg.TicketPrefixes),
+		})
+	}
+	for _, quest := range findSideQuests(commits, cfg.TicketPrefixes) {
+		result.SideQuests = append(result.SideQuests, nowCommit{Hash: quest.Hash, Message: quest.Message})
+	}
+	return result, nil
+}
+
+// printActiveWork prints the active work along with its notes
+func printActiveWork(work TrackedWork) {
+	fmt.Printf("%s %s (%s)\n", output.Heading("Tracking:"), work.Description, formatDuration(work.Duration()))
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("  Tickets: %s\n", output.Ticket(strings.Join(work.TicketIDs, ", ")))
+	}
+	if len(work.Notes) > 0 {
+		fmt.Println("  Notes:")
+		for _, note := range work.Notes {
+			fmt.Printf("    %s  %s\n", formatNoteTime(note.Time, work.StartTime), note.Text)
 		}
 	}
-} 
\ No newline at end of file
+}
+
+// describeWorkInProgress summarizes uncommitted work on a branch
+func describeWorkInProgress(branch string, wip WorkInProgress) string {
+	files := len(wip.Files())
+	if files == 0 {
+		return fmt.Sprintf("Uncommitted work in progress on %s: %d stash(es)", branch, wip.Stashes)
+	}
+	unit := "files"
+	if files == 1 {
+		unit = "file"
+	}
+	return fmt.Sprintf("Uncommitted work in progress on %s: %d %s, +%d/-%d", branch, files, unit, wip.LinesAdded, wip.LinesRemoved)
+}
+
+// wipDetails breaks uncommitted work down into staged, unstaged and untracked files and stashes
+func wipDetails(wip WorkInProgress) string {
+	var parts []string
+	if len(wip.Staged) > 0 {
+		parts = append(parts, fmt.Sprintf("%d staged", len(wip.Staged)))
+	}
+	if len(wip.Unstaged) > 0 {
+		parts = append(parts, fmt.Sprintf("%d unstaged", len(wip.Unstaged)))
+	}
+	if len(wip.Untracked) > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked", len(wip.Untracked)))
+	}
+	if wip.Stashes > 0 && len(wip.Files()) > 0 {
+		parts = append(parts, fmt.Sprintf("%d stash(es)", wip.Stashes))
+	}
+	return strings.Join(parts, ", ")
+}
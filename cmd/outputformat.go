@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// outputFlag is the format given with --output
+var outputFlag string
+
+// outputFormat is the format commands print their results in, from --output
+var outputFormat = output.Text
+
+// setOutputFormat selects the format of the --output flag
+func setOutputFormat(name string) error {
+	format, err := output.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+	outputFormat = format
+	return nil
+}
+
+// formatFlag is the template given with --format, on the commands that take it
+var formatFlag string
+
+// outputTemplate is the template commands print each result with, from
+// --format, or nil to print them as the output format says
+var outputTemplate *template.Template
+
+// setOutputTemplate parses the template of the --format flag
+func setOutputTemplate(text string) error {
+	outputTemplate = nil
+	if text == "" {
+		return nil
+	}
+	if jsonOutput() {
+		return fmt.Errorf("use either --format or --output json, not both")
+	}
+	tmpl, err := output.ParseTemplate(text)
+	if err != nil {
+		return err
+	}
+	outputTemplate = tmpl
+	return nil
+}
+
+// addFormatFlag adds the --format flag to a command
+func addFormatFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringVar(&formatFlag, "format", "", usage)
+}
+
+// templateOutput reports whether results are printed with a template from
+// --format
+func templateOutput() bool {
+	return outputTemplate != nil
+}
+
+// printFormatted prints a result with the --format template on stdout
+func printFormatted(result interface{}) error {
+	return output.WriteTemplate(os.Stdout, outputTemplate, result)
+}
+
+// jsonOutputAnnotation marks the commands that print their results as JSON
+// with --output json
+const jsonOutputAnnotation = "json_output"
+
+// supportJSONOutput marks a command as printing its results as JSON with
+// --output json, which the other commands reject
+func supportJSONOutput(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[jsonOutputAnnotation] = "true"
+}
+
+// checkJSONOutput rejects --output json for commands that don't print JSON,
+// rather than printing text scripts would fail to parse
+func checkJSONOutput(cmd *cobra.Command) error {
+	if jsonOutput() && cmd.Annotations[jsonOutputAnnotation] == "" {
+		return fmt.Errorf("'%s' can't print JSON; use --output json with commands that list or show results, such as 'plannet list'", cmd.CommandPath())
+	}
+	return nil
+}
+
+// jsonOutput reports whether results are printed as JSON, for scripts
+func jsonOutput() bool {
+	return outputFormat == output.JSON
+}
+
+// printJSON prints a command's result as JSON on stdout
+func printJSON(result interface{}) {
+	if err := output.WriteJSON(os.Stdout, result); err != nil {
+		fprintError(os.Stderr, "Error printing output:", err)
+	}
+}
+
+// toStderr runs f with what it prints to stdout sent to stderr instead, so
+// that messages, such as those of the background checks, don't mix with
+// JSON or template output
+func toStderr(f func()) {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+	f()
+}
+
+// setOutputStyle turns colored output and logs on or off, from --no-color,
+// the color setting and the environment, and selects the configured theme
+func setOutputStyle(noColor bool) {
+	setting := output.ColorAuto
+	var themeSettings config.ThemeSettings
+	if cfg, err := config.Load(); err == nil {
+		if cfg.Color != "" {
+			setting = cfg.Color
+		}
+		themeSettings = cfg.Theme
+	}
+	if noColor {
+		setting = output.ColorNever
+	}
+	output.SetColors(output.ColorEnabled(setting, os.Stdout))
+	logger.SetColors(output.ColorEnabled(setting, os.Stderr))
+
+	theme, err := output.LoadTheme(themeSettings)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: using the default theme:", err)
+	}
+	output.SetTheme(theme)
+}
+
+// completeOutputFormats completes the formats of --output
+func completeOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := make([]string, len(output.Formats))
+	for i, format := range output.Formats {
+		names[i] = string(format)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
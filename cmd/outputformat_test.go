@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// captureStdout returns what f prints to stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	f()
+	w.Close()
+	return string(<-done)
+}
+
+// useJSONOutput selects --output json for the rest of a test
+func useJSONOutput(t *testing.T) {
+	t.Helper()
+	if err := setOutputFormat("json"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { outputFormat = output.Text })
+}
+
+func TestSetOutputFormat(t *testing.T) {
+	t.Cleanup(func() { outputFormat = output.Text })
+	if err := setOutputFormat("yaml"); err == nil {
+		t.Error("setOutputFormat(yaml) succeeded, want an error")
+	}
+	if jsonOutput() {
+		t.Error("jsonOutput() = true after an unknown format")
+	}
+	if err := setOutputFormat("json"); err != nil || !jsonOutput() {
+		t.Errorf("setOutputFormat(json) = %v, jsonOutput() = %v", err, jsonOutput())
+	}
+}
+
+func TestCheckJSONOutput(t *testing.T) {
+	useJSONOutput(t)
+	for _, cmd := range []*cobra.Command{listCmd, searchCmd, generateCmd, sidequestsCmd, ticketViewCmd} {
+		if err := checkJSONOutput(cmd); err != nil {
+			t.Errorf("checkJSONOutput(%s) = %v, want nil", cmd.Name(), err)
+		}
+	}
+	for _, cmd := range []*cobra.Command{trackCmd, completeCmd, pauseCmd, importCmd, exportCmd} {
+		if err := checkJSONOutput(cmd); err == nil {
+			t.Errorf("checkJSONOutput(%s) = nil, want an error", cmd.Name())
+		}
+	}
+
+	outputFormat = output.Text
+	if err := checkJSONOutput(trackCmd); err != nil {
+		t.Errorf("checkJSONOutput(track) of text output = %v, want nil", err)
+	}
+}
+
+func TestListJSON(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	useJSONOutput(t)
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	work := TrackedWork{
+		ID:          "work-1",
+		Description: "Fix login",
+		TicketIDs:   []string{"JIRA-1"},
+		StartTime:   start,
+		EndTime:     start.Add(90 * time.Minute),
+		Status:      "completed",
+	}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() { runList(nil, false, false, nil, workFilter{}) })
+	var listed []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("list printed invalid JSON: %v\n%s", err, out)
+	}
+	if len(listed) != 1 || listed[0]["id"] != "work-1" || listed[0]["description"] != "Fix login" || listed[0]["duration_minutes"] != 90.0 {
+		t.Errorf("list printed %s", out)
+	}
+}
+
+func TestSetOutputTemplate(t *testing.T) {
+	t.Cleanup(func() { outputTemplate = nil })
+	if err := setOutputTemplate("{{.TicketID"); err == nil {
+		t.Error("setOutputTemplate() of an invalid template succeeded")
+	}
+	if err := setOutputTemplate("{{.TicketID}}"); err != nil || !templateOutput() {
+		t.Errorf("setOutputTemplate() = %v, templateOutput() = %v", err, templateOutput())
+	}
+	if err := setOutputTemplate(""); err != nil || templateOutput() {
+		t.Errorf("setOutputTemplate(\"\") = %v, templateOutput() = %v", err, templateOutput())
+	}
+
+	useJSONOutput(t)
+	if err := setOutputTemplate("{{.TicketID}}"); err == nil {
+		t.Error("setOutputTemplate() with --output json succeeded")
+	}
+}
+
+func TestListFormat(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	if err := setOutputTemplate("{{.TicketID}} {{.Description}} {{.DurationMinutes}}"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { outputTemplate = nil })
+
+	start := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	for _, work := range []TrackedWork{
+		{ID: "work-1", Description: "Fix login", TicketIDs: []string{"JIRA-1", "JIRA-2"}, StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "work-2", Description: "Review", StartTime: start.Add(2 * time.Hour), EndTime: start.Add(150 * time.Minute), Status: "completed"},
+	} {
+		if err := saveTrackedWork(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := captureStdout(t, func() { runList(nil, false, false, nil, workFilter{}) })
+	if want := " Review 30\nJIRA-1 Fix login 60\n"; out != want {
+		t.Errorf("list printed %q, want %q", out, want)
+	}
+}
+
+func TestWorkOutputJSON(t *testing.T) {
+	start := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+	work := TrackedWork{ID: "w", Description: "Review", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed", Project: "app"}
+	data, err := json.Marshal(semanticOutput{workOutput: newWorkOutput(work), Similarity: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	json.Unmarshal(data, &got)
+	if got["id"] != "w" || got["project"] != "app" || got["duration_minutes"] != 60.0 || got["similarity"] != 0.5 {
+		t.Errorf("Marshalled %s", data)
+	}
+}
+
+func TestToStderr(t *testing.T) {
+	out := captureStdout(t, func() {
+		toStderr(func() {
+			if os.Stdout != os.Stderr {
+				t.Error("stdout isn't stderr inside toStderr()")
+			}
+		})
+		os.Stdout.WriteString("after")
+	})
+	if out != "after" {
+		t.Errorf("stdout = %q, want it restored after toStderr()", out)
+	}
+}
@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// pauseCmd represents the pause command
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause the active work",
+	Long: `Pause the work you're currently tracking.
+The time spent paused is not counted towards the work's duration.
+Use 'plannet resume' to pick it back up.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runPause()
+	},
+}
+
+// resumeCmd represents the resume command
+var resumeCmd = &cobra.Command{
+	Use:   "resume [id]",
+	Short: "Resume paused work",
+	Long: `Resume a piece of paused work.
+If no ID is given and more than one piece of work is paused,
+you will be asked to choose which one to resume.
+
+With --last, the most recently paused or completed work is started again
+as a new session with the same description, ticket and tags. This suits
+recurring work like standing meetings or long-running tickets.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		last, _ := cmd.Flags().GetBool("last")
+		if last {
+			if len(args) > 0 {
+				fmt.Println("Specify either a work ID or --last, not both.")
+				return
+			}
+			runResumeLast()
+			return
+		}
+		runResume(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+	resumeCmd.Flags().BoolP("last", "l", false, "Start a new session of the most recently paused or completed work")
+}
+
+func runPause() {
+	// Load configuration
+	_, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil {
+		printError("Error getting active work:", err)
+		return
+	}
+
+	if activeWork == nil {
+		fmt.Println("No active work to pause.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	pauseWork(activeWork, time.Now())
+	if err := saveTrackedWork(*activeWork); err != nil {
+		printError("Error pausing work:", err)
+		return
+	}
+
+	fmt.Println("Work paused.")
+	fmt.Printf("ID: %s\n", activeWork.ID)
+	fmt.Printf("Description: %s\n", activeWork.Description)
+	fmt.Printf("Time tracked so far: %s\n", formatDuration(activeWork.Duration()))
+}
+
+func runResume(args []string) {
+	// Load configuration
+	_, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	pausedWork, err := getPausedWork()
+	if err != nil {
+		printError("Error getting paused work:", err)
+		return
+	}
+
+	if len(pausedWork) == 0 {
+		fmt.Println("No paused work found.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	// Find the work to resume
+	var work *TrackedWork
+	if len(args) > 0 {
+		for i := range pausedWork {
+			if pausedWork[i].ID == args[0] {
+				work = &pausedWork[i]
+				break
+			}
+		}
+		if work == nil {
+			fmt.Printf("Paused work with ID %s not found.\n", args[0])
+			return
+		}
+	} else if len(pausedWork) == 1 {
+		work = &pausedWork[0]
+	} else {
+		var items []string
+		for _, w := range pausedWork {
+			items = append(items, fmt.Sprintf("%s: %s", w.ID, w.Description))
+		}
+
+		prompt := promptui.Select{
+			Label: "Select work to resume",
+			Items: items,
+		}
+
+		index, _, err := prompt.Run()
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error selecting work:", err)
+			return
+		}
+		work = &pausedWork[index]
+	}
+
+	// Only one piece of work can be active at a time
+	activeWork, err := getActiveWork()
+	if err != nil {
+		printError("Error getting active work:", err)
+		return
+	}
+
+	if activeWork != nil {
+		fmt.Printf("Pausing current work: %s\n", activeWork.Description)
+		pauseWork(activeWork, time.Now())
+		if err := saveTrackedWork(*activeWork); err != nil {
+			printError("Error pausing current work:", err)
+			return
+		}
+	}
+
+	resumeWork(work, time.Now())
+	if err := saveTrackedWork(*work); err != nil {
+		printError("Error resuming work:", err)
+		return
+	}
+
+	fmt.Println("Work resumed.")
+	fmt.Printf("ID: %s\n", work.ID)
+	fmt.Printf("Description: %s\n", work.Description)
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(work.TicketIDs, ", "))
+	}
+	if len(work.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(work.Tags, ", "))
+	}
+	fmt.Printf("Time tracked so far: %s\n", formatDuration(work.Duration()))
+}
+
+func runResumeLast() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	last := findLastStoppedWork(trackedWork)
+	if last == nil {
+		fmt.Println("No paused or completed work found.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	// Only one piece of work can be active at a time
+	activeWork, err := getActiveWork()
+	if err != nil {
+		printError("Error getting active work:", err)
+		return
+	}
+
+	now := time.Now()
+	if activeWork != nil {
+		fmt.Printf("Pausing current work: %s\n", activeWork.Description)
+		pauseWork(activeWork, now)
+		if err := saveTrackedWork(*activeWork); err != nil {
+			printError("Error pausing current work:", err)
+			return
+		}
+	}
+
+	work := reopenWork(*last, now)
+	work.Context = getWorkContext(cfg)
+	if err := saveTrackedWork(work); err != nil {
+		printError("Error starting work:", err)
+		return
+	}
+
+	fmt.Println("Started a new session of previous work.")
+	fmt.Printf("ID: %s\n", work.ID)
+	fmt.Printf("Description: %s\n", work.Description)
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(work.TicketIDs, ", "))
+	}
+	if len(work.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(work.Tags, ", "))
+	}
+	if work.Billable {
+		fmt.Println("Billable: yes")
+	}
+}
+
+// findLastStoppedWork returns the paused or completed work that was most
+// recently worked on, or nil if there is none
+func findLastStoppedWork(trackedWork []TrackedWork) *TrackedWork {
+	var last *TrackedWork
+	for i := range trackedWork {
+		work := &trackedWork[i]
+		if work.Status != "paused" && work.Status != "completed" {
+			continue
+		}
+		if last == nil || lastActivity(*work).After(lastActivity(*last)) {
+			last = work
+		}
+	}
+	return last
+}
+
+// reopenWork starts a new active session of previous work, keeping what it
+// is about but none of its recorded time, notes or progress
+func reopenWork(previous TrackedWork, at time.Time) TrackedWork {
+	return TrackedWork{
+		ID:          generateID(),
+		Description: previous.Description,
+		TicketIDs:   append([]string(nil), previous.TicketIDs...),
+		StartTime:   at,
+		Tags:        append([]string(nil), previous.Tags...),
+		Status:      "active",
+		Billable:    previous.Billable,
+	}
+}
+
+// getPausedWork returns all paused work
+func getPausedWork() ([]TrackedWork, error) {
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		return nil, err
+	}
+
+	var paused []TrackedWork
+	for _, work := range trackedWork {
+		if work.Status == "paused" {
+			paused = append(paused, work)
+		}
+	}
+	return paused, nil
+}
+
+// pauseWork marks work as paused and opens a pause interval
+func pauseWork(work *TrackedWork, at time.Time) {
+	if work.Status == "paused" {
+		return
+	}
+	work.Status = "paused"
+	work.Pauses = append(work.Pauses, PauseInterval{Start: at})
+}
+
+// resumeWork marks work as active and closes any open pause interval
+func resumeWork(work *TrackedWork, at time.Time) {
+	closeOpenPause(work, at)
+	work.Status = "active"
+}
+
+// completeWork marks work as completed, closing any open pause interval
+// and recording the diff statistics of the commits made for it, up to the
+// HEAD of the repository it was tracked in
+func completeWork(work *TrackedWork, at time.Time) {
+	closeOpenPause(work, at)
+	work.EndTime = at
+	work.Status = "completed"
+	recordDiffStats(work, "HEAD")
+}
+
+// closeOpenPause ends the most recent pause interval if it is still open
+func closeOpenPause(work *TrackedWork, at time.Time) {
+	if n := len(work.Pauses); n > 0 && work.Pauses[n-1].End.IsZero() {
+		work.Pauses[n-1].End = at
+	}
+}
+
+// formatDuration formats a duration for display, e.g. "1h 20m"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "<1m"
+	}
+
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
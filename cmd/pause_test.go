@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationExcludesPauses(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	work := TrackedWork{
+		ID:        "test-1",
+		StartTime: start,
+		EndTime:   start.Add(2 * time.Hour),
+		Status:    "completed",
+		Pauses: []PauseInterval{
+			{Start: start.Add(30 * time.Minute), End: start.Add(45 * time.Minute)},
+			{Start: start.Add(90 * time.Minute), End: start.Add(105 * time.Minute)},
+		},
+	}
+
+	if got := work.PausedDuration(); got != 30*time.Minute {
+		t.Errorf("PausedDuration() = %v, want %v", got, 30*time.Minute)
+	}
+	if got := work.Duration(); got != 90*time.Minute {
+		t.Errorf("Duration() = %v, want %v", got, 90*time.Minute)
+	}
+}
+
+func TestPauseAndResumeWork(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Now().Add(-time.Hour)
+	work := TrackedWork{
+		ID:          "test-1",
+		Description: "Pausable work",
+		StartTime:   start,
+		Status:      "active",
+	}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	// Pause the work
+	pauseWork(&work, start.Add(10*time.Minute))
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to pause work: %v", err)
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	if activeWork != nil {
+		t.Errorf("Expected no active work after pausing, got %s", activeWork.ID)
+	}
+
+	paused, err := getPausedWork()
+	if err != nil {
+		t.Fatalf("Failed to get paused work: %v", err)
+	}
+	if len(paused) != 1 || paused[0].ID != work.ID {
+		t.Fatalf("Expected paused work %s, got %v", work.ID, paused)
+	}
+
+	// Resume the work
+	resumeWork(&paused[0], start.Add(40*time.Minute))
+	if err := saveTrackedWork(paused[0]); err != nil {
+		t.Fatalf("Failed to resume work: %v", err)
+	}
+
+	activeWork, err = getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	if activeWork == nil || activeWork.ID != work.ID {
+		t.Fatalf("Expected active work %s after resuming", work.ID)
+	}
+	if got := activeWork.PausedDuration(); got != 30*time.Minute {
+		t.Errorf("PausedDuration() = %v, want %v", got, 30*time.Minute)
+	}
+
+	paused, err = getPausedWork()
+	if err != nil {
+		t.Fatalf("Failed to get paused work: %v", err)
+	}
+	if len(paused) != 0 {
+		t.Errorf("Expected no paused work after resuming, got %d", len(paused))
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{20 * time.Second, "<1m"},
+		{45 * time.Minute, "45m"},
+		{2 * time.Hour, "2h"},
+		{80 * time.Minute, "1h 20m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDuration(tt.in); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFindLastStoppedWork(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	trackedWork := []TrackedWork{
+		{ID: "completed", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "paused", StartTime: start.Add(30 * time.Minute), Status: "paused", Pauses: []PauseInterval{{Start: start.Add(2 * time.Hour)}}},
+		{ID: "active", StartTime: start.Add(3 * time.Hour), Status: "active"},
+	}
+
+	last := findLastStoppedWork(trackedWork)
+	if last == nil || last.ID != "paused" {
+		t.Fatalf("Expected the paused work to be the most recent, got %+v", last)
+	}
+
+	if last := findLastStoppedWork(trackedWork[2:]); last != nil {
+		t.Errorf("Expected no stopped work, got %s", last.ID)
+	}
+}
+
+func TestReopenWork(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	previous := TrackedWork{
+		ID:          "standup",
+		Description: "Daily standup",
+		TicketIDs:   []string{"TEAM-1"},
+		StartTime:   start,
+		EndTime:     start.Add(15 * time.Minute),
+		Tags:        []string{"meeting"},
+		Status:      "completed",
+		Notes:       []Note{{Time: start, Text: "Discussed release"}},
+	}
+
+	now := start.Add(24 * time.Hour)
+	work := reopenWork(previous, now)
+	if work.ID == previous.ID {
+		t.Error("Expected the new session to get its own ID")
+	}
+	if work.Description != previous.Description || !work.HasTicket("TEAM-1") {
+		t.Errorf("Expected description and ticket to be kept, got %q and %v", work.Description, work.TicketIDs)
+	}
+	if len(work.Tags) != 1 || work.Tags[0] != "meeting" {
+		t.Errorf("Expected tags to be kept, got %v", work.Tags)
+	}
+	if work.Status != "active" || !work.StartTime.Equal(now) || !work.EndTime.IsZero() {
+		t.Errorf("Expected a new active session starting now, got %+v", work)
+	}
+	if len(work.Notes) != 0 {
+		t.Errorf("Expected notes not to be carried over, got %v", work.Notes)
+	}
+}
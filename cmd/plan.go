@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/calendar"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/spf13/cobra"
+)
+
+// planTemplatePrefix starts the names of the templates a plan is saved as,
+// which are numbered in the plan's order: plan-1, plan-2...
+const planTemplatePrefix = "plan-"
+
+// planItem is a piece of work in a plan for the day, as the LLM writes it
+type planItem struct {
+	Description string `json:"description"`
+	Ticket      string `json:"ticket"` // Empty for work without a ticket
+	Minutes     int    `json:"minutes"`
+	Reason      string `json:"reason"`
+}
+
+// dayPlan is a plan for the day, most important work first
+type dayPlan struct {
+	Items []planItem `json:"items"`
+}
+
+// dayPlanSchema is the JSON schema of the plans the LLM writes
+var dayPlanSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "items": {
+      "type": "array",
+      "description": "The work to do today, most important first",
+      "items": {
+        "type": "object",
+        "properties": {
+          "description": {"type": "string", "description": "What to do, as a short task description"},
+          "ticket": {"type": "string", "description": "The key of the ticket the work is for, or an empty string"},
+          "minutes": {"type": "integer", "description": "How long to spend on it"},
+          "reason": {"type": "string", "description": "Why it has this priority, in one sentence"}
+        },
+        "required": ["description", "ticket", "minutes", "reason"],
+        "additionalProperties": false
+      }
+    }
+  },
+  "required": ["items"],
+  "additionalProperties": false
+}`)
+
+// planInputs is what a plan for the day is made from
+type planInputs struct {
+	Now        time.Time
+	Tickets    []systems.Ticket
+	Unfinished []TrackedWork
+	Events     []calendar.Event
+}
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plan your day with the LLM",
+	Long: `Ask the LLM to propose a prioritized plan for today, from your open tickets,
+the work you left unfinished, and the events of your calendar, if one is set
+in the calendar setting.
+
+Accepted plans are saved as templates named plan-1, plan-2 and so on, in the
+order of the plan, replacing those of the previous plan. Start a piece of
+the plan with 'plannet template apply plan-1'.
+
+Examples:
+  plannet plan
+  plannet plan --sprint`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sprint, _ := cmd.Flags().GetBool("sprint")
+		accept, _ := cmd.Flags().GetBool("accept")
+		runPlan(cmd.Context(), sprint, accept)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.Flags().Bool("sprint", false, "Plan from your issues in the active Jira sprints, rather than your open tickets")
+	planCmd.Flags().Bool("accept", false, "Save the plan as templates without asking")
+}
+
+// runPlan proposes a plan for today, and saves it as templates once accepted
+func runPlan(ctx context.Context, sprint, accept bool) {
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+	if !llm.IsConfigured(cfg) {
+		fmt.Println("LLM integration is not configured. Please run 'plannet init' first.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	inputs := gatherPlanInputs(ctx, cfg, sprint, time.Now())
+	if len(inputs.Tickets) == 0 && len(inputs.Unfinished) == 0 {
+		fmt.Println("No open tickets or unfinished work to plan.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	printInfo("Planning your day...")
+	conv := llm.UserPrompt(cfg.SystemPrompt, planPrompt(inputs))
+	conv.Schema = dayPlanSchema
+	sendCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	reply, err := llm.SendJSON(sendCtx, cfg, conv)
+	stop()
+	if errors.Is(err, context.Canceled) {
+		fmt.Println("Stopped.")
+		return
+	}
+	if err != nil {
+		printError("Error generating plan:", err)
+		return
+	}
+	plan, err := parseDayPlan(reply, inputs.ticketKeys())
+	if err != nil {
+		printError("Error reading the plan:", err)
+		return
+	}
+
+	for {
+		fmt.Printf("\n%s\n", plan)
+		if accept {
+			break
+		}
+		if !isInteractive() {
+			fmt.Println("Run with --accept to save the plan as templates.")
+			return
+		}
+
+		selectPrompt := promptui.Select{
+			Label: "Accept this plan?",
+			Items: []string{"Save as templates", "Edit", "Cancel"},
+		}
+		index, _, err := selectPrompt.Run()
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error getting confirmation:", err)
+			return
+		}
+		if index == 0 {
+			break
+		}
+		if index == 2 {
+			fmt.Println("Plan not saved.")
+			return
+		}
+		plan = editDayPlan(cfg, plan, inputs.ticketKeys())
+	}
+
+	templates, err := readTemplates()
+	if err != nil {
+		printError("Error reading templates:", err)
+		return
+	}
+	if err := writeTemplates(replacePlanTemplates(templates, plan)); err != nil {
+		printError("Error saving templates:", err)
+		return
+	}
+	fmt.Printf("Saved the plan as %d template(s). Start with 'plannet template apply %s1'.\n", len(plan.Items), planTemplatePrefix)
+}
+
+// gatherPlanInputs collects what a plan is made from. Sources that fail
+// are reported and left out, so that the plan is made from the others.
+func gatherPlanInputs(ctx context.Context, cfg *config.Config, sprint bool, now time.Time) planInputs {
+	inputs := planInputs{Now: now}
+
+	if sprint {
+		issues, err := fetchSprintIssues(ctx, cfg, true)
+		if err != nil {
+			fmt.Println("Could not get your sprint issues:", err)
+		}
+		for _, issue := range issues {
+			if !strings.EqualFold(issue.Ticket.Status, "Done") {
+				inputs.Tickets = append(inputs.Tickets, issue.Ticket.systemTicket())
+			}
+		}
+	} else if system, err := systems.New(cfg); err != nil {
+		fmt.Println("Could not get your tickets:", err)
+	} else if tickets, err := system.List(ctx); err != nil {
+		fmt.Println("Could not get your tickets:", err)
+	} else {
+		inputs.Tickets = tickets
+	}
+
+	if trackedWork, err := getTrackedWork(); err != nil {
+		fmt.Println("Could not get your tracked work:", err)
+	} else {
+		for _, work := range trackedWork {
+			if work.Status == "active" || work.Status == "paused" {
+				inputs.Unfinished = append(inputs.Unfinished, work)
+			}
+		}
+	}
+
+	if cfg.Calendar != "" {
+		if events, err := calendar.Load(ctx, cfg.Calendar); err != nil {
+			fmt.Println("Could not read your calendar:", err)
+		} else {
+			inputs.Events = calendar.On(events, now)
+		}
+	}
+	return inputs
+}
+
+// ticketKeys returns the keys of the tickets the plan can be for: the open
+// tickets, and those of the unfinished work
+func (in planInputs) ticketKeys() []string {
+	var keys []string
+	for _, ticket := range in.Tickets {
+		keys = append(keys, ticket.Key)
+	}
+	for _, work := range in.Unfinished {
+		keys = append(keys, work.TicketIDs...)
+	}
+	return keys
+}
+
+// planPrompt asks the LLM for a plan for the rest of the day
+func planPrompt(inputs planInputs) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "It is %s. Propose a prioritized plan for the rest of my working day, ", inputs.Now.Format("Monday 2 January 2006, 15:04"))
+	b.WriteString("most important work first. Finish what I left unfinished when it still matters, ")
+	b.WriteString("leave time for my calendar's events, and don't plan more than fits in the day. ")
+	b.WriteString("Only use the ticket keys listed below.\n")
+
+	if len(inputs.Tickets) > 0 {
+		b.WriteString("\nMy open tickets:\n")
+		for _, ticket := range inputs.Tickets {
+			fmt.Fprintf(&b, "- %s: %s", ticket.Key, ticket.Summary)
+			if ticket.Status != "" {
+				fmt.Fprintf(&b, " (%s)", ticket.Status)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(inputs.Unfinished) > 0 {
+		b.WriteString("\nWork I left unfinished:\n")
+		for _, work := range inputs.Unfinished {
+			fmt.Fprintf(&b, "- %s", work.Description)
+			if len(work.TicketIDs) > 0 {
+				fmt.Fprintf(&b, " [%s]", strings.Join(work.TicketIDs, ", "))
+			}
+			fmt.Fprintf(&b, " (%s, %s spent so far)\n", work.Status, formatDuration(work.Duration()))
+			for _, subtask := range work.Subtasks {
+				if !subtask.Done {
+					fmt.Fprintf(&b, "  - To do: %s\n", subtask.Text)
+				}
+			}
+		}
+	}
+
+	if len(inputs.Events) > 0 {
+		b.WriteString("\nMy calendar today:\n")
+		for _, event := range inputs.Events {
+			if event.AllDay {
+				fmt.Fprintf(&b, "- All day: %s\n", event.Summary)
+				continue
+			}
+			fmt.Fprintf(&b, "- %s–%s: %s\n", event.Start.Local().Format("15:04"), event.End.Local().Format("15:04"), event.Summary)
+		}
+	}
+	return b.String()
+}
+
+// parseDayPlan reads a plan the LLM or the user wrote. Tickets are written
+// as their keys among the given ones, and those that aren't among them are
+// dropped, as the LLM may make them up.
+func parseDayPlan(text string, tickets []string) (dayPlan, error) {
+	var plan dayPlan
+	if err := llm.DecodeJSON(text, &plan); err != nil {
+		return plan, err
+	}
+
+	known := make(map[string]string, len(tickets))
+	for _, ticket := range tickets {
+		known[strings.ToUpper(ticket)] = ticket
+	}
+	var items []planItem
+	for _, item := range plan.Items {
+		item.Description = strings.TrimSpace(item.Description)
+		if item.Description == "" {
+			continue
+		}
+		item.Ticket = known[strings.ToUpper(strings.TrimSpace(item.Ticket))]
+		if item.Minutes < 0 {
+			item.Minutes = 0
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return plan, fmt.Errorf("the plan has no work in it")
+	}
+	plan.Items = items
+	return plan, nil
+}
+
+// String shows the plan for review
+func (p dayPlan) String() string {
+	var b strings.Builder
+	var total time.Duration
+	for i, item := range p.Items {
+		duration := time.Duration(item.Minutes) * time.Minute
+		total += duration
+		fmt.Fprintf(&b, "%d. ", i+1)
+		if item.Ticket != "" {
+			fmt.Fprintf(&b, "[%s] ", item.Ticket)
+		}
+		b.WriteString(item.Description)
+		if duration > 0 {
+			fmt.Fprintf(&b, " (%s)", formatDuration(duration))
+		}
+		b.WriteString("\n")
+		if item.Reason != "" {
+			fmt.Fprintf(&b, "   %s\n", item.Reason)
+		}
+	}
+	fmt.Fprintf(&b, "\nTotal: %s\n", formatDuration(total))
+	return b.String()
+}
+
+// editDayPlan opens a plan in the user's editor as JSON, and returns it
+// edited, or unchanged if the edit fails
+func editDayPlan(cfg *config.Config, plan dayPlan, tickets []string) dayPlan {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		printError("Error preparing the plan for editing:", err)
+		return plan
+	}
+	text, err := editTextInEditor(string(data)+"\n", cfg.Editor, "plannet-plan-*.json")
+	if err != nil {
+		printError("Error:", err)
+		return plan
+	}
+	edited, err := parseDayPlan(text, tickets)
+	if err != nil {
+		printError("Error reading the edited plan, keeping the previous one:", err)
+		return plan
+	}
+	return edited
+}
+
+// replacePlanTemplates returns the templates with those of the previous
+// plan replaced by the plan's, tagged "plan"
+func replacePlanTemplates(templates []WorkTemplate, plan dayPlan) []WorkTemplate {
+	var kept []WorkTemplate
+	for _, template := range templates {
+		if !strings.HasPrefix(template.Name, planTemplatePrefix) {
+			kept = append(kept, template)
+		}
+	}
+	for i, item := range plan.Items {
+		template := WorkTemplate{
+			Name:            fmt.Sprintf("%s%d", planTemplatePrefix, i+1),
+			Description:     item.Description,
+			Tags:            []string{"plan"},
+			DurationMinutes: item.Minutes,
+		}
+		if item.Ticket != "" {
+			template.TicketIDs = []string{item.Ticket}
+		}
+		kept = append(kept, template)
+	}
+	return kept
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/internal/calendar"
+	"github.com/plannet-ai/plannet/internal/systems"
+)
+
+func TestPlanPrompt(t *testing.T) {
+	now := time.Date(2024, 5, 3, 9, 0, 0, 0, time.Local)
+	prompt := planPrompt(planInputs{
+		Now:     now,
+		Tickets: []systems.Ticket{{Key: "PROJ-1", Summary: "Fix login", Status: "In Progress"}},
+		Unfinished: []TrackedWork{{
+			Description: "Write migration",
+			TicketIDs:   []string{"PROJ-2"},
+			Status:      "paused",
+			StartTime:   now.Add(-24 * time.Hour),
+			EndTime:     now.Add(-23 * time.Hour),
+			Subtasks:    []Subtask{{Text: "Backfill", Done: false}, {Text: "Schema", Done: true}},
+		}},
+		Events: []calendar.Event{
+			{Summary: "Standup", Start: now.Add(30 * time.Minute), End: now.Add(45 * time.Minute)},
+			{Summary: "Company offsite", AllDay: true},
+		},
+	})
+
+	for _, want := range []string{
+		"Friday 3 May 2024, 09:00",
+		"- PROJ-1: Fix login (In Progress)",
+		"- Write migration [PROJ-2] (paused, 1h spent so far)",
+		"  - To do: Backfill",
+		"- 09:30–09:45: Standup",
+		"- All day: Company offsite",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Prompt doesn't contain %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "Schema") {
+		t.Errorf("Prompt contains a done subtask:\n%s", prompt)
+	}
+}
+
+func TestParseDayPlan(t *testing.T) {
+	if !json.Valid(dayPlanSchema) {
+		t.Fatal("dayPlanSchema is not valid JSON")
+	}
+	tickets := []string{"PROJ-1"}
+	reply := `{"items": [
+		{"description": "Fix login", "ticket": "proj-1", "minutes": 90, "reason": "It blocks the release."},
+		{"description": "Review PRs", "ticket": "PROJ-99", "minutes": 30, "reason": ""},
+		{"description": " ", "ticket": "", "minutes": 10, "reason": ""}
+	]}`
+	plan, err := parseDayPlan(reply, tickets)
+	if err != nil {
+		t.Fatalf("parseDayPlan() failed: %v", err)
+	}
+	if len(plan.Items) != 2 {
+		t.Fatalf("parseDayPlan() = %+v, want 2 items", plan.Items)
+	}
+	if plan.Items[0].Ticket != "PROJ-1" || plan.Items[1].Ticket != "" {
+		t.Errorf("Tickets = %q, %q; want PROJ-1 and none", plan.Items[0].Ticket, plan.Items[1].Ticket)
+	}
+
+	shown := plan.String()
+	for _, want := range []string{"1. [PROJ-1] Fix login (1h 30m)\n   It blocks the release.\n", "2. Review PRs (30m)\n", "Total: 2h\n"} {
+		if !strings.Contains(shown, want) {
+			t.Errorf("String() doesn't contain %q:\n%s", want, shown)
+		}
+	}
+
+	if _, err := parseDayPlan(`{"items": []}`, tickets); err == nil {
+		t.Error("parseDayPlan() of an empty plan succeeded, want an error")
+	}
+}
+
+func TestReplacePlanTemplates(t *testing.T) {
+	templates := []WorkTemplate{
+		{Name: "standup", Description: "Daily standup"},
+		{Name: "plan-1", Description: "Yesterday's plan"},
+		{Name: "plan-2", Description: "Yesterday's plan"},
+	}
+	plan := dayPlan{Items: []planItem{
+		{Description: "Fix login", Ticket: "PROJ-1", Minutes: 90},
+		{Description: "Review PRs"},
+	}}
+
+	got := replacePlanTemplates(templates, plan)
+	if len(got) != 3 || got[0].Name != "standup" {
+		t.Fatalf("replacePlanTemplates() = %+v", got)
+	}
+	if got[1].Name != "plan-1" || got[1].Description != "Fix login" || got[1].DurationMinutes != 90 ||
+		len(got[1].TicketIDs) != 1 || got[1].TicketIDs[0] != "PROJ-1" || len(got[1].Tags) != 1 || got[1].Tags[0] != "plan" {
+		t.Errorf("First plan template = %+v", got[1])
+	}
+	if got[2].Name != "plan-2" || got[2].TicketIDs != nil || got[2].DurationMinutes != 0 {
+		t.Errorf("Second plan template = %+v", got[2])
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/manifoldco/promptui"
+)
+
+// runPomodoro runs focus timers on the given work until the user stops
+func runPomodoro(workID string, length time.Duration) {
+	for {
+		start := time.Now()
+		interrupted := runCountdown(length)
+		end := time.Now()
+
+		// Reload the work, which may have changed while the timer ran
+		trackedWork, err := getTrackedWork()
+		if err != nil {
+			printError("Error getting tracked work:", err)
+			return
+		}
+		work := findWorkByID(trackedWork, workID)
+		if work == nil {
+			fmt.Println("The tracked work was removed while the timer was running.")
+			return
+		}
+
+		recordPomodoro(work, start, end, length, interrupted)
+		if err := saveTrackedWork(*work); err != nil {
+			printError("Error saving pomodoro:", err)
+			return
+		}
+
+		if interrupted {
+			fmt.Printf("Pomodoro stopped after %s. The work is still being tracked.\n", formatDuration(end.Sub(start)))
+			return
+		}
+
+		fmt.Print("\a")
+		notifyDesktop(notifyPomodoro, "Pomodoro complete", fmt.Sprintf("%s: time for a break", work.Description))
+		fmt.Printf("Pomodoro complete! %d so far on %s.\n", len(work.Pomodoros), work.Description)
+
+		prompt := promptui.Select{
+			Label: "What next?",
+			Items: []string{
+				"Start another pomodoro",
+				"Pause work for a break",
+				"Complete work",
+				"Keep tracking without a timer",
+			},
+		}
+		index, _, err := prompt.Run()
+		if err != nil {
+			return
+		}
+
+		switch index {
+		case 0:
+			continue
+		case 1:
+			pauseWork(work, time.Now())
+			if err := saveTrackedWork(*work); err != nil {
+				printError("Error pausing work:", err)
+				return
+			}
+			fmt.Println("Work paused. Run 'plannet resume' when you're back.")
+		case 2:
+			completeWork(work, time.Now())
+			if err := saveTrackedWork(*work); err != nil {
+				printError("Error completing work:", err)
+				return
+			}
+			fmt.Printf("Completed: %s (%s)\n", work.Description, formatDuration(work.Duration()))
+		}
+		return
+	}
+}
+
+// runCountdown shows a countdown in the terminal. It reports whether the
+// countdown was interrupted with Ctrl+C before the time ran out.
+func runCountdown(length time.Duration) bool {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	deadline := time.Now().Add(length)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			fmt.Print("\r\033[K")
+			return false
+		}
+		remaining = remaining.Round(time.Second)
+		fmt.Printf("\r\033[KFocus: %02d:%02d remaining (Ctrl+C to stop)", int(remaining.Minutes()), int(remaining.Seconds())%60)
+
+		select {
+		case <-interrupt:
+			fmt.Println()
+			return true
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordPomodoro records a focus interval on tracked work
+func recordPomodoro(work *TrackedWork, start, end time.Time, length time.Duration, interrupted bool) {
+	work.Pomodoros = append(work.Pomodoros, Pomodoro{
+		Start:       start,
+		End:         end,
+		Minutes:     int(length.Minutes()),
+		Interrupted: interrupted,
+	})
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPomodoroIsRecorded(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Now().Add(-30 * time.Minute)
+	work := TrackedWork{ID: "test-1", Description: "Focus work", StartTime: start, Status: "active"}
+
+	if interrupted := runCountdown(50 * time.Millisecond); interrupted {
+		t.Fatal("Expected a short countdown to run out without interruption")
+	}
+
+	recordPomodoro(&work, start, start.Add(25*time.Minute), 25*time.Minute, false)
+	recordPomodoro(&work, start.Add(25*time.Minute), start.Add(30*time.Minute), 25*time.Minute, true)
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	saved, err := getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	if len(saved.Pomodoros) != 2 {
+		t.Fatalf("Expected 2 pomodoros, got %d", len(saved.Pomodoros))
+	}
+	if saved.Pomodoros[0].Minutes != 25 || saved.Pomodoros[0].Interrupted {
+		t.Errorf("Expected a completed 25 minute pomodoro, got %+v", saved.Pomodoros[0])
+	}
+	if !saved.Pomodoros[1].Interrupted {
+		t.Error("Expected the second pomodoro to be marked as interrupted")
+	}
+}
@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/plannet-ai/plannet/security"
+	"github.com/spf13/cobra"
+)
+
+// maxPRDraftFiles limits how many changed files are listed in the PR prompt
+const maxPRDraftFiles = 100
+
+// prDraft is a pull request title and description drafted by the LLM
+type prDraft struct {
+	Title string
+	Body  string
+}
+
+// forgeRepo is a repository hosted on GitHub or GitLab
+type forgeRepo struct {
+	Forge  string // "github" or "gitlab"
+	Host   string
+	Path   string // owner/name, or group/subgroup/name on GitLab
+	APIURL string
+}
+
+// prDraftCmd represents the pr-draft command
+var prDraftCmd = &cobra.Command{
+	Use:   "pr-draft",
+	Short: "Draft a pull request description for the current branch",
+	Long: `Draft a pull request description from the commits and changed files on the
+current branch since it left the base branch. The LLM writes a title and a
+description with a summary, the changes made and notes on testing.
+
+The draft is shown and copied like other generated content. With --create it
+is opened as a pull request on GitHub or a merge request on GitLab, using
+github_token or gitlab_token from your configuration (or the GITHUB_TOKEN and
+GITLAB_TOKEN environment variables). Push the branch before creating it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		base, _ := cmd.Flags().GetString("base")
+		create, _ := cmd.Flags().GetBool("create")
+		runPRDraft(cmd.Context(), base, create)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prDraftCmd)
+
+	prDraftCmd.Flags().String("base", "", "Branch the pull request merges into (defaults to the repository's default branch)")
+	prDraftCmd.Flags().Bool("create", false, "Create the pull request on GitHub or GitLab")
+}
+
+func runPRDraft(ctx context.Context, base string, create bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if !llm.IsConfigured(cfg) {
+		fmt.Println("LLM integration is not configured. Run 'plannet init' to set it up.")
+		setExitCode(exitConfig)
+		return
+	}
+	if !isGitRepo(".") {
+		fmt.Println("Not in a git repository.")
+		return
+	}
+
+	backend := getGitBackend()
+	branch, err := getCurrentBranch()
+	if err != nil || branch == "HEAD" {
+		fmt.Println("Check out the branch to draft a pull request for.")
+		return
+	}
+	if base == "" {
+		base, err = backend.DefaultBranch(".")
+		if err != nil {
+			fmt.Println("Could not find the base branch; pass it with --base.")
+			return
+		}
+	}
+	if baseBranchName(base) == branch {
+		fmt.Printf("The current branch is the base branch %s.\n", branch)
+		return
+	}
+
+	mergeBase, err := backend.MergeBase(".", base, "HEAD")
+	if err != nil {
+		printErrorf("Error finding where %s left %s: %v\n", branch, base, err)
+		return
+	}
+	commits, err := backend.LogRange(".", mergeBase, "HEAD")
+	if err != nil {
+		printError("Error getting branch commits:", err)
+		return
+	}
+	if len(commits) == 0 {
+		fmt.Printf("No commits on %s since it left %s.\n", branch, base)
+		return
+	}
+	churn, err := backend.DiffStats(".", mergeBase, "HEAD")
+	if err != nil {
+		printError("Error getting changed files:", err)
+		return
+	}
+
+	ticketID := extractTicketID(branch, cfg.TicketPrefixes)
+
+	printInfo("Drafting pull request...")
+	response, err := llm.NewGenerator(cfg).Generate(prDraftPrompt(branch, base, ticketID, commits, churn))
+	if err != nil {
+		printError("Error generating pull request description:", err)
+		return
+	}
+	draft := parsePRDraft(response)
+	if draft.Title == "" {
+		// Commits are newest first, so the last one started the branch
+		draft.Title = commits[len(commits)-1].Message
+	}
+	draft.Title = addTicketToMessage(draft.Title, ticketID, cfg.TicketPrefixes)
+
+	if !create {
+		if err := output.HandleOutput(draft.Title+"\n\n"+draft.Body, cfg); err != nil {
+			printError("Error handling output:", err)
+		}
+		return
+	}
+
+	remote, err := backend.RemoteURL(".", "origin")
+	if err != nil {
+		printError("Error finding the repository's remote:", err)
+		return
+	}
+	repo, err := parseForgeRemote(remote)
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+	token := forgeToken(cfg, repo.Forge)
+	if token == "" {
+		fmt.Printf("No %s token configured. Set %s_token in your configuration or the %s_TOKEN environment variable.\n",
+			repo.Forge, repo.Forge, strings.ToUpper(repo.Forge))
+		return
+	}
+
+	fmt.Printf("%s\n\n%s\n\n", draft.Title, draft.Body)
+	prURL, err := createPullRequest(ctx, repo, token, draft, branch, baseBranchName(base))
+	if err != nil {
+		printError("Error creating pull request:", err)
+		return
+	}
+	fmt.Println("Created", prURL)
+}
+
+// prDraftPrompt builds the LLM prompt for drafting a pull request description
+func prDraftPrompt(branch, base, ticketID string, commits []Commit, churn []FileChurn) string {
+	var b strings.Builder
+	b.WriteString("Write a pull request description for the changes on a git branch.\n")
+	b.WriteString("Reply with a line starting with \"Title:\" holding a title of at most 72 characters, ")
+	b.WriteString("followed by a description in Markdown with the sections \"## Summary\" (why the change is needed), ")
+	b.WriteString("\"## Changes\" (a list of what changed) and \"## Testing\" (how the change was or should be tested).\n\n")
+
+	fmt.Fprintf(&b, "Branch %s, merging into %s\n", branch, baseBranchName(base))
+	if ticketID != "" {
+		fmt.Fprintf(&b, "Ticket: %s\n", ticketID)
+	}
+
+	b.WriteString("\nCommits, oldest first:\n")
+	for i := len(commits) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "- %s\n", commits[i].Message)
+		if commits[i].Body != "" {
+			for _, line := range strings.Split(commits[i].Body, "\n") {
+				fmt.Fprintf(&b, "  %s\n", line)
+			}
+		}
+	}
+
+	added, removed := 0, 0
+	b.WriteString("\nFiles changed:\n")
+	for i, file := range churn {
+		added += file.Added
+		removed += file.Removed
+		if i < maxPRDraftFiles {
+			fmt.Fprintf(&b, "  %s (+%d/-%d)\n", file.Path, file.Added, file.Removed)
+		}
+	}
+	if len(churn) > maxPRDraftFiles {
+		fmt.Fprintf(&b, "  ...and %d more\n", len(churn)-maxPRDraftFiles)
+	}
+	fmt.Fprintf(&b, "%d files changed, +%d/-%d lines\n", len(churn), added, removed)
+	return b.String()
+}
+
+// parsePRDraft reads the title and description from an LLM response
+func parsePRDraft(response string) prDraft {
+	var draft prDraft
+	lines := strings.Split(strings.TrimSpace(response), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Title:") {
+			draft.Title = strings.TrimSpace(strings.TrimPrefix(trimmed, "Title:"))
+			lines = lines[i+1:]
+		}
+		break
+	}
+	draft.Body = strings.TrimSpace(strings.Join(lines, "\n"))
+	return draft
+}
+
+// baseBranchName returns the branch name of a base ref, without the origin/ of a remote-tracking branch
+func baseBranchName(base string) string {
+	return strings.TrimPrefix(base, "origin/")
+}
+
+// parseForgeRemote works out the GitHub or GitLab repository behind a remote
+// URL, in either the https or the scp-like ssh form
+func parseForgeRemote(remote string) (forgeRepo, error) {
+	var repo forgeRepo
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return repo, fmt.Errorf("invalid remote URL %s: %w", remote, err)
+		}
+		repo.Host = u.Hostname()
+		repo.Path = u.Path
+	} else if i := strings.Index(remote, ":"); i >= 0 {
+		repo.Host = remote[:i]
+		if at := strings.LastIndex(repo.Host, "@"); at >= 0 {
+			repo.Host = repo.Host[at+1:]
+		}
+		repo.Path = remote[i+1:]
+	}
+	repo.Path = strings.TrimSuffix(strings.Trim(repo.Path, "/"), ".git")
+	if repo.Host == "" || !strings.Contains(repo.Path, "/") {
+		return repo, fmt.Errorf("cannot tell the repository from remote %s", remote)
+	}
+
+	switch {
+	case repo.Host == "github.com":
+		repo.Forge = "github"
+		repo.APIURL = "https://api.github.com"
+	case strings.Contains(repo.Host, "github"):
+		// GitHub Enterprise serves its API under /api/v3
+		repo.Forge = "github"
+		repo.APIURL = "https://" + repo.Host + "/api/v3"
+	case strings.Contains(repo.Host, "gitlab"):
+		repo.Forge = "gitlab"
+		repo.APIURL = "https://" + repo.Host + "/api/v4"
+	default:
+		return repo, fmt.Errorf("remote %s is not on GitHub or GitLab", remote)
+	}
+	return repo, nil
+}
+
+// forgeToken returns the API token for a forge, from the configuration or the environment
+func forgeToken(cfg *config.Config, forge string) string {
+	switch forge {
+	case "github":
+		if cfg.GitHubToken != "" {
+			return cfg.GitHubToken
+		}
+		return os.Getenv("GITHUB_TOKEN")
+	case "gitlab":
+		if cfg.GitLabToken != "" {
+			return cfg.GitLabToken
+		}
+		return os.Getenv("GITLAB_TOKEN")
+	}
+	return ""
+}
+
+// createPullRequest opens a pull request on GitHub, or a merge request on
+// GitLab, and returns its URL
+func createPullRequest(ctx context.Context, repo forgeRepo, token string, draft prDraft, head, base string) (string, error) {
+	var endpoint string
+	var payload map[string]string
+	switch repo.Forge {
+	case "github":
+		endpoint = repo.APIURL + "/repos/" + repo.Path + "/pulls"
+		payload = map[string]string{"title": draft.Title, "body": draft.Body, "head": head, "base": base}
+	case "gitlab":
+		endpoint = repo.APIURL + "/projects/" + url.PathEscape(repo.Path) + "/merge_requests"
+		payload = map[string]string{"title": draft.Title, "description": draft.Body, "source_branch": head, "target_branch": base}
+	default:
+		return "", fmt.Errorf("unsupported forge %q", repo.Forge)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal pull request: %w", err)
+	}
+
+	// Create HTTP client with rate limiting
+	rateLimiter := security.NewHTTPRateLimiter(10, time.Minute) // 10 requests per minute
+	rateLimiter.OnWait = reportRateLimitWait
+	client := rateLimiter.WrapHTTPClient(&http.Client{}, repo.Forge)
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s API request: %w", repo.Forge, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if repo.Forge == "github" {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	} else {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to send %s API request: %w", repo.Forge, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("%s API returned status %d: %s", repo.Forge, resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		HTMLURL string `json:"html_url"` // GitHub
+		WebURL  string `json:"web_url"`  // GitLab
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse %s API response: %w", repo.Forge, err)
+	}
+	if result.HTMLURL != "" {
+		return result.HTMLURL, nil
+	}
+	return result.WebURL, nil
+}
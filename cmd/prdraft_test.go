@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestParseForgeRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   forgeRepo
+	}{
+		{"git@github.com:acme/app.git", forgeRepo{Forge: "github", Host: "github.com", Path: "acme/app", APIURL: "https://api.github.com"}},
+		{"https://github.com/acme/app", forgeRepo{Forge: "github", Host: "github.com", Path: "acme/app", APIURL: "https://api.github.com"}},
+		{"ssh://git@github.example.com/acme/app.git", forgeRepo{Forge: "github", Host: "github.example.com", Path: "acme/app", APIURL: "https://github.example.com/api/v3"}},
+		{"https://gitlab.com/group/sub/app.git", forgeRepo{Forge: "gitlab", Host: "gitlab.com", Path: "group/sub/app", APIURL: "https://gitlab.com/api/v4"}},
+	}
+	for _, tt := range tests {
+		got, err := parseForgeRemote(tt.remote)
+		if err != nil {
+			t.Errorf("parseForgeRemote(%q) failed: %v", tt.remote, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseForgeRemote(%q) = %+v, want %+v", tt.remote, got, tt.want)
+		}
+	}
+
+	if _, err := parseForgeRemote("https://bitbucket.org/acme/app.git"); err == nil {
+		t.Error("Expected an error for a remote that isn't on GitHub or GitLab")
+	}
+}
+
+func TestParsePRDraft(t *testing.T) {
+	draft := parsePRDraft("\nTitle: Fix login timeout\n\n## Summary\nLogins timed out.\n")
+	if draft.Title != "Fix login timeout" || draft.Body != "## Summary\nLogins timed out." {
+		t.Errorf("Unexpected draft %+v", draft)
+	}
+
+	draft = parsePRDraft("## Summary\nLogins timed out.")
+	if draft.Title != "" || draft.Body != "## Summary\nLogins timed out." {
+		t.Errorf("Expected the whole response as the body without a title, got %+v", draft)
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/app/pulls" || r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["head"] != "feature/PROJ-1-login" || payload["base"] != "main" || payload["title"] != "PROJ-1: Fix login" {
+			t.Errorf("Unexpected payload %v", payload)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"html_url": "https://github.com/acme/app/pull/7"}`))
+	}))
+	defer server.Close()
+
+	repo := forgeRepo{Forge: "github", Host: "github.com", Path: "acme/app", APIURL: server.URL}
+	prURL, err := createPullRequest(context.Background(), repo, "secret", prDraft{Title: "PROJ-1: Fix login", Body: "## Summary"}, "feature/PROJ-1-login", "main")
+	if err != nil {
+		t.Fatalf("Failed to create pull request: %v", err)
+	}
+	if prURL != "https://github.com/acme/app/pull/7" {
+		t.Errorf("Unexpected pull request URL %s", prURL)
+	}
+}
+
+func TestBranchCommitRange(t *testing.T) {
+	_, cleanup := setupGitRepo(t)
+	defer cleanup()
+
+	commit := func(file, message string) {
+		if err := os.WriteFile(file, []byte(message), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		exec.Command("git", "add", file).Run()
+		if err := exec.Command("git", "commit", "-m", message).Run(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	exec.Command("git", "checkout", "-b", "main").Run()
+	commit("base.txt", "Initial commit")
+	exec.Command("git", "checkout", "-b", "feature/PROJ-1-login").Run()
+	commit("login.go", "Add login")
+	commit("login_test.go", "Test login")
+
+	backend := getGitBackend()
+	base, err := backend.DefaultBranch(".")
+	if err != nil || base != "main" {
+		t.Fatalf("Expected default branch main, got %q (%v)", base, err)
+	}
+	mergeBase, err := backend.MergeBase(".", base, "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to find merge base: %v", err)
+	}
+	commits, err := backend.LogRange(".", mergeBase, "HEAD")
+	if err != nil {
+		t.Fatalf("Failed to get branch commits: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Message != "Test login" || commits[1].Message != "Add login" {
+		t.Errorf("Unexpected branch commits %+v", commits)
+	}
+	churn, err := backend.DiffStats(".", mergeBase, "HEAD")
+	if err != nil || len(churn) != 2 {
+		t.Errorf("Expected two changed files, got %+v (%v)", churn, err)
+	}
+}
@@ -0,0 +1,478 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// reportGroupings are what the totals of a report can be grouped by
+var reportGroupings = []string{"ticket", "tag", "day", "project"}
+
+// reportPublishTargets are where a report can be published
+var reportPublishTargets = []string{"confluence"}
+
+// Names of the groups of work without a ticket, tag or project
+const (
+	noTicketGroup  = "(no ticket)"
+	noTagGroup     = "(untagged)"
+	noProjectGroup = "(no project)"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Total the time spent on your work",
+	Long: `Total the time spent on your work since a given time, grouped by ticket,
+tag, day or project, with each group's share of the total.
+
+Completed tracked work is counted, along with the time between commits
+that no tracked work covers: like auto-track, the time from one commit to
+the next, when it is less than 30 minutes, is counted for the ticket of
+the later commit. Work with several tickets or tags is split evenly
+between them.
+
+  plannet report                        # This week, by ticket
+  plannet report --since 2024-05-01 --until 2024-05-31 --group-by tag
+  plannet report --group-by day --csv > week.csv
+
+Use --all to report on every project and the commits of every repository
+'plannet status --all' shows, and --no-git to count tracked work only.
+--ticket, --tag and --status narrow the report down as they do the list;
+commits have no tags, so --tag leaves out the time between them.
+
+Use --publish confluence to publish the report as a page in the Confluence
+space configured in the confluence section of your configuration, with your
+Jira credentials. Publishing a report of the same days again, such as every
+Friday for the week, updates its page:
+
+  plannet report --publish confluence`,
+	Run: func(cmd *cobra.Command, args []string) {
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		all, _ := cmd.Flags().GetBool("all")
+		noGit, _ := cmd.Flags().GetBool("no-git")
+		csvOutput, _ := cmd.Flags().GetBool("csv")
+		publishing := reportPublishing{}
+		publishing.Target, _ = cmd.Flags().GetString("publish")
+		publishing.Title, _ = cmd.Flags().GetString("title")
+		filter, err := workFilterFromFlags(cmd)
+		if err != nil {
+			printError("Error:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		runReport(cmd.Context(), groupBy, all, noGit, csvOutput, publishing, filter)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	supportJSONOutput(reportCmd)
+	reportCmd.Flags().String("group-by", "ticket", "Group the totals by ticket, tag, day or project")
+	reportCmd.Flags().BoolP("all", "a", false, "Report on every project and repository")
+	reportCmd.Flags().Bool("no-git", false, "Leave out the time between commits")
+	reportCmd.Flags().Bool("csv", false, "Print the totals as CSV")
+	reportCmd.Flags().String("publish", "", "Publish the report as a page, to confluence")
+	reportCmd.Flags().String("title", "", "The title of the published page (default the configured title and the dates of the report)")
+	reportCmd.RegisterFlagCompletionFunc("group-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return reportGroupings, cobra.ShellCompDirectiveNoFileComp
+	})
+	reportCmd.RegisterFlagCompletionFunc("publish", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return reportPublishTargets, cobra.ShellCompDirectiveNoFileComp
+	})
+	addWorkFilterFlags(reportCmd, "monday")
+}
+
+// reportEntry is time counted in a report: completed tracked work, or the
+// time between two commits
+type reportEntry struct {
+	Start    time.Time
+	Duration time.Duration
+	Tickets  []string
+	Tags     []string
+	Project  string
+}
+
+// reportGroup is the total time of one group of a report
+type reportGroup struct {
+	Name            string        `json:"name"`
+	DurationMinutes int           `json:"duration_minutes"`
+	Percent         float64       `json:"percent"`
+	duration        time.Duration // Unrounded, for the total
+}
+
+// reportPublishing is where a report is published, if anywhere, and the
+// title of its page
+type reportPublishing struct {
+	Target string // Empty to print the report
+	Title  string // Empty for one from the dates of the report
+}
+
+// reportOutput is a report as --output json prints it
+type reportOutput struct {
+	Since        time.Time     `json:"since"` // Zero for any time
+	Until        *time.Time    `json:"until,omitempty"`
+	GroupBy      string        `json:"group_by"`
+	TotalMinutes int           `json:"total_minutes"`
+	Groups       []reportGroup `json:"groups"`
+}
+
+func runReport(ctx context.Context, groupBy string, all, noGit, csvOutput bool, publishing reportPublishing, filter workFilter) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if !containsString(reportGroupings, groupBy) {
+		printErrorf("Error: invalid grouping %q, expected one of %s\n", groupBy, strings.Join(reportGroupings, ", "))
+		setExitCode(exitUsage)
+		return
+	}
+	if publishing.Target != "" && !containsString(reportPublishTargets, publishing.Target) {
+		printErrorf("Error: cannot publish to %q, expected one of %s\n", publishing.Target, strings.Join(reportPublishTargets, ", "))
+		setExitCode(exitUsage)
+		return
+	}
+	var trackedWork []TrackedWork
+	if all {
+		trackedWork, err = getAllTrackedWork()
+	} else {
+		trackedWork, err = getTrackedWork()
+	}
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	project := currentProjectName()
+	entries := workReportEntries(filter.apply(trackedWork), project)
+	if cfg.GitIntegration && !noGit {
+		commitEntries, err := reportCommitEntries(cfg, all, filter.From, project)
+		if err != nil {
+			printError("Error getting commits:", err)
+			return
+		}
+		// Commits are left out of the time of any tracked work, not only
+		// of the work the filter selects
+		entries = append(entries, filterReportEntries(uncoveredEntries(commitEntries, trackedWork), filter)...)
+	}
+
+	report := buildReport(entries, groupBy)
+	result := reportOutput{Since: filter.From, GroupBy: groupBy, Groups: report}
+	if !filter.To.IsZero() {
+		result.Until = &filter.To
+	}
+	var total time.Duration
+	for _, group := range report {
+		total += group.duration
+	}
+	result.TotalMinutes = int(total.Minutes())
+
+	if publishing.Target != "" {
+		if len(report) == 0 {
+			fmt.Printf("No work found%s, so there is nothing to publish.\n", describeReportRange(filter))
+			setExitCode(exitNotFound)
+			return
+		}
+		title := publishing.Title
+		if title == "" {
+			title = reportPageTitle(cfg, filter, time.Now())
+		}
+		pageURL, err := publishReport(ctx, cfg, title, groupBy, report, filter)
+		if err != nil {
+			printError("Error publishing report:", err)
+			return
+		}
+		fmt.Printf("Published %q to %s\n", title, pageURL)
+		return
+	}
+
+	switch {
+	case jsonOutput():
+		if result.Groups == nil {
+			result.Groups = []reportGroup{}
+		}
+		printJSON(result)
+	case len(report) == 0:
+		fmt.Printf("No work found%s.\n", describeReportRange(filter))
+		setExitCode(exitNotFound)
+	case csvOutput:
+		if err := writeReportCSV(os.Stdout, groupBy, report); err != nil {
+			printError("Error writing CSV:", err)
+		}
+	default:
+		if !output.Quiet() {
+			fmt.Printf("Time spent%s, by %s:\n\n", describeReportRange(filter), groupBy)
+		}
+		if err := reportTable(groupBy, report).Print(); err != nil {
+			printError("Error printing report:", err)
+			return
+		}
+		fmt.Printf("\nTotal: %s\n", formatDuration(total))
+	}
+}
+
+// describeReportRange describes the time a report covers, for messages
+func describeReportRange(filter workFilter) string {
+	var description string
+	if !filter.From.IsZero() {
+		description += " since " + filter.From.Format("Monday, 2006-01-02 15:04")
+	}
+	if !filter.To.IsZero() {
+		description += " until " + filter.To.Format("Monday, 2006-01-02 15:04")
+	}
+	return description
+}
+
+// workReportEntries returns the report entries of the completed work. Work
+// without a project of its own is taken to belong to defaultProject.
+func workReportEntries(trackedWork []TrackedWork, defaultProject string) []reportEntry {
+	var entries []reportEntry
+	for _, work := range trackedWork {
+		if work.Status != "completed" {
+			continue
+		}
+		entries = append(entries, reportEntry{
+			Start:    work.StartTime,
+			Duration: work.Duration(),
+			Tickets:  work.TicketIDs,
+			Tags:     work.Tags,
+			Project:  workProject(work, defaultProject),
+		})
+	}
+	return entries
+}
+
+// reportRepos returns the current repository, named after the project work
+// is tracked in, if any, or with all, every repository
+func reportRepos(cfg *config.Config, all bool, project string) ([]statusRepo, error) {
+	if all {
+		return statusRepos(cfg)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if !isGitRepo(dir) {
+		return nil, nil
+	}
+	name := project
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	return []statusRepo{{Name: name, Path: dir}}, nil
+}
+
+// reportCommitEntries returns the report entries of the commits since the
+// given time, in the current repository or, with all, in every repository
+func reportCommitEntries(cfg *config.Config, all bool, since time.Time, project string) ([]reportEntry, error) {
+	repos, err := reportRepos(cfg, all, project)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []reportEntry
+	// Clones of the same repository share commits, which are counted once
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		commits, err := getCommitsAfter(repo.Path, since, commitFilterFor(cfg, false, nil))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits of %s: %w", repo.Name, err)
+		}
+		entries = append(entries, commitReportEntries(dedupeCommits(commits, seen), cfg.TicketPrefixes, repo.Name)...)
+	}
+	return entries, nil
+}
+
+// commitReportEntries returns an entry for the time from each commit to the
+// next, when it is less than the auto-track gap, for the ticket of the later
+// commit. Commits are newest first, as git lists them.
+func commitReportEntries(commits []Commit, prefixes []string, project string) []reportEntry {
+	var entries []reportEntry
+	for i := 0; i+1 < len(commits); i++ {
+		commit, previous := commits[i], commits[i+1]
+		gap := commit.Time.Sub(previous.Time)
+		if gap <= 0 || gap > autoTrackGap {
+			continue
+		}
+		var tickets []string
+		if ticketID := extractTicketIDFromMessage(commit.FullMessage(), prefixes); ticketID != "" {
+			tickets = []string{ticketID}
+		}
+		entries = append(entries, reportEntry{Start: previous.Time, Duration: gap, Tickets: tickets, Project: project})
+	}
+	return entries
+}
+
+// filterReportEntries returns the entries of the time between commits that
+// pass the filter. Commits count as completed work without tags.
+func filterReportEntries(entries []reportEntry, filter workFilter) []reportEntry {
+	if len(filter.Tags) > 0 || (filter.Status != "" && filter.Status != "completed") {
+		return nil
+	}
+	var matched []reportEntry
+	for _, entry := range entries {
+		if entry.Start.Before(filter.From) || (!filter.To.IsZero() && !entry.Start.Before(filter.To)) {
+			continue
+		}
+		if len(filter.Tickets) > 0 && !containsFold(entry.Tickets, filter.Tickets) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	return matched
+}
+
+// uncoveredEntries shortens entries by the time tracked work covers, so it
+// isn't counted twice, and drops those it covers completely
+func uncoveredEntries(entries []reportEntry, trackedWork []TrackedWork) []reportEntry {
+	// The intervals of tracked work, sorted and merged where they overlap
+	type interval struct{ start, end time.Time }
+	var covered []interval
+	for _, work := range trackedWork {
+		end := work.EndTime
+		if end.IsZero() {
+			end = time.Now()
+		}
+		covered = append(covered, interval{work.StartTime, end})
+	}
+	sort.Slice(covered, func(i, j int) bool { return covered[i].start.Before(covered[j].start) })
+	var merged []interval
+	for _, c := range covered {
+		if n := len(merged); n > 0 && !c.start.After(merged[n-1].end) {
+			if c.end.After(merged[n-1].end) {
+				merged[n-1].end = c.end
+			}
+			continue
+		}
+		merged = append(merged, c)
+	}
+
+	var uncovered []reportEntry
+	for _, entry := range entries {
+		end := entry.Start.Add(entry.Duration)
+		for _, c := range merged {
+			start, stop := c.start, c.end
+			if start.Before(entry.Start) {
+				start = entry.Start
+			}
+			if stop.After(end) {
+				stop = end
+			}
+			if stop.After(start) {
+				entry.Duration -= stop.Sub(start)
+			}
+		}
+		if entry.Duration > 0 {
+			uncovered = append(uncovered, entry)
+		}
+	}
+	return uncovered
+}
+
+// buildReport totals the time of the entries in each group, sorted by day
+// for days and by time spent otherwise
+func buildReport(entries []reportEntry, groupBy string) []reportGroup {
+	totals := make(map[string]time.Duration)
+	var total time.Duration
+	for _, entry := range entries {
+		names := reportGroupNames(entry, groupBy)
+		share := entry.Duration / time.Duration(len(names))
+		for _, name := range names {
+			totals[name] += share
+		}
+		total += share * time.Duration(len(names))
+	}
+
+	var groups []reportGroup
+	for name, d := range totals {
+		if d <= 0 {
+			continue
+		}
+		groups = append(groups, reportGroup{
+			Name:            name,
+			DurationMinutes: int(d.Minutes()),
+			Percent:         math.Round(float64(d)/float64(total)*1000) / 10,
+			duration:        d,
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groupBy != "day" && groups[i].duration != groups[j].duration {
+			return groups[i].duration > groups[j].duration
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// reportGroupNames returns the groups an entry's time is split between
+func reportGroupNames(entry reportEntry, groupBy string) []string {
+	switch groupBy {
+	case "tag":
+		if len(entry.Tags) == 0 {
+			return []string{noTagGroup}
+		}
+		return entry.Tags
+	case "day":
+		return []string{entry.Start.Format(dateInputLayout)}
+	case "project":
+		if entry.Project == "" {
+			return []string{noProjectGroup}
+		}
+		return []string{entry.Project}
+	default:
+		if len(entry.Tickets) == 0 {
+			return []string{noTicketGroup}
+		}
+		return entry.Tickets
+	}
+}
+
+// reportTable returns the table of a report
+func reportTable(groupBy string, report []reportGroup) *output.Table {
+	heading := output.Column{Heading: groupBy, Shrink: true}
+	if groupBy == "ticket" {
+		heading.Style = output.Ticket
+	}
+	table := output.NewTable(heading, output.Column{Heading: "time"}, output.Column{Heading: "share"})
+	for _, group := range report {
+		table.AddRow(group.Name, formatDuration(group.duration), fmt.Sprintf("%.1f%%", group.Percent))
+	}
+	return table
+}
+
+// writeReportCSV writes a report as CSV
+func writeReportCSV(w io.Writer, groupBy string, report []reportGroup) error {
+	writer := csv.NewWriter(w)
+	heading := strings.ToUpper(groupBy[:1]) + groupBy[1:]
+	if err := writer.Write([]string{heading, "Duration (minutes)", "Share (%)"}); err != nil {
+		return err
+	}
+	for _, group := range report {
+		err := writer.Write([]string{
+			group.Name,
+			strconv.Itoa(group.DurationMinutes),
+			strconv.FormatFloat(group.Percent, 'f', 1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildReport(t *testing.T) {
+	monday := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	entries := []reportEntry{
+		{Start: monday, Duration: 2 * time.Hour, Tickets: []string{"JIRA-1", "JIRA-2"}, Tags: []string{"backend"}},
+		{Start: monday.AddDate(0, 0, 1), Duration: 3 * time.Hour, Tickets: []string{"JIRA-1"}, Project: "api"},
+		{Start: monday, Duration: time.Hour},
+	}
+
+	byTicket := buildReport(entries, "ticket")
+	want := []struct {
+		name    string
+		minutes int
+		percent float64
+	}{
+		{"JIRA-1", 240, 66.7},
+		{noTicketGroup, 60, 16.7},
+		{"JIRA-2", 60, 16.7},
+	}
+	if len(byTicket) != len(want) {
+		t.Fatalf("buildReport() by ticket = %+v, want %d groups", byTicket, len(want))
+	}
+	for i, w := range want {
+		if g := byTicket[i]; g.Name != w.name || g.DurationMinutes != w.minutes || g.Percent != w.percent {
+			t.Errorf("group %d = %+v, want %s %dm %.1f%%", i, g, w.name, w.minutes, w.percent)
+		}
+	}
+
+	var days []string
+	for _, g := range buildReport(entries, "day") {
+		days = append(days, g.Name)
+	}
+	if want := []string{"2024-05-06", "2024-05-07"}; !reflect.DeepEqual(days, want) {
+		t.Errorf("buildReport() by day = %v, want %v", days, want)
+	}
+
+	var projects []string
+	for _, g := range buildReport(entries, "project") {
+		projects = append(projects, g.Name)
+	}
+	if want := []string{noProjectGroup, "api"}; !reflect.DeepEqual(projects, want) {
+		t.Errorf("buildReport() by project = %v, want %v", projects, want)
+	}
+}
+
+func TestCommitReportEntries(t *testing.T) {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	// Newest first, as git lists them
+	commits := []Commit{
+		{Message: "JIRA-2: docs", Time: start.Add(3 * time.Hour)},
+		{Message: "JIRA-1: tests", Time: start.Add(20 * time.Minute)},
+		{Message: "JIRA-1: start", Time: start},
+	}
+
+	entries := commitReportEntries(commits, []string{"JIRA-"}, "api")
+	if len(entries) != 1 {
+		t.Fatalf("commitReportEntries() = %+v, want one entry, without the gap of 2h 40m", entries)
+	}
+	want := reportEntry{Start: start, Duration: 20 * time.Minute, Tickets: []string{"JIRA-1"}, Project: "api"}
+	if !reflect.DeepEqual(entries[0], want) {
+		t.Errorf("commitReportEntries() = %+v, want %+v", entries[0], want)
+	}
+}
+
+func TestUncoveredEntries(t *testing.T) {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{StartTime: start.Add(10 * time.Minute), EndTime: start.Add(20 * time.Minute), Status: "completed"},
+		{StartTime: start.Add(15 * time.Minute), EndTime: start.Add(25 * time.Minute), Status: "completed"},
+		{StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour), Status: "completed"},
+	}
+	entries := []reportEntry{
+		{Start: start, Duration: 30 * time.Minute},
+		{Start: start.Add(70 * time.Minute), Duration: 20 * time.Minute},
+	}
+
+	got := uncoveredEntries(entries, trackedWork)
+	if len(got) != 1 || got[0].Duration != 15*time.Minute {
+		t.Errorf("uncoveredEntries() = %+v, want 15m left of the first entry only", got)
+	}
+}
+
+func TestFilterReportEntries(t *testing.T) {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	entries := []reportEntry{
+		{Start: start, Duration: time.Hour, Tickets: []string{"JIRA-1"}},
+		{Start: start.AddDate(0, 0, 1), Duration: time.Hour, Tickets: []string{"JIRA-2"}},
+		{Start: start.AddDate(0, 0, 2), Duration: time.Hour},
+	}
+
+	tests := []struct {
+		name   string
+		filter workFilter
+		want   int
+	}{
+		{"everything", workFilter{}, 3},
+		{"range", workFilter{From: start.Add(time.Hour), To: start.AddDate(0, 0, 2)}, 1},
+		{"ticket", workFilter{Tickets: []string{"jira-1"}}, 1},
+		{"completed", workFilter{Status: "completed"}, 3},
+		{"active", workFilter{Status: "active"}, 0},
+		{"tag", workFilter{Tags: []string{"backend"}}, 0},
+	}
+	for _, tt := range tests {
+		if got := filterReportEntries(entries, tt.filter); len(got) != tt.want {
+			t.Errorf("%s: filterReportEntries() = %+v, want %d entries", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	report := []reportGroup{{Name: "backend", DurationMinutes: 90, Percent: 75}, {Name: noTagGroup, DurationMinutes: 30, Percent: 25}}
+	if err := writeReportCSV(&buf, "tag", report); err != nil {
+		t.Fatal(err)
+	}
+	want := "Tag,Duration (minutes),Share (%)\nbackend,90,75.0\n(untagged),30,25.0\n"
+	if buf.String() != want {
+		t.Errorf("writeReportCSV() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunReportJSON(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+	useJSONOutput(t)
+
+	start := time.Now().Add(-3 * time.Hour)
+	for _, work := range []TrackedWork{
+		{ID: "done", Description: "Login", TicketIDs: []string{"JIRA-1"}, StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "old", Description: "Old", TicketIDs: []string{"JIRA-2"}, StartTime: start.AddDate(0, 0, -30), EndTime: start.AddDate(0, 0, -30).Add(time.Hour), Status: "completed"},
+	} {
+		if err := saveTrackedWork(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := captureStdout(t, func() {
+		runReport(context.Background(), "ticket", false, true, false, reportPublishing{}, workFilter{From: time.Now().Add(-24 * time.Hour)})
+	})
+	var result reportOutput
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("report output isn't JSON: %v\n%s", err, out)
+	}
+	if result.TotalMinutes != 60 || len(result.Groups) != 1 || result.Groups[0].Name != "JIRA-1" || result.Groups[0].Percent != 100 {
+		t.Errorf("report = %+v, want an hour on JIRA-1", result)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/output"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +15,17 @@ var (
 	Version = "0.1.0"
 	// Debug mode flag
 	debug bool
+	// Workspace flag
+	workspaceFlag string
+	// No color flag
+	noColor bool
+	// Raw flag, which prints markdown without rendering it
+	raw bool
+	// Quiet flag, which leaves out decorative output
+	quiet bool
+	// commandStarted is set once the arguments and flags of the command
+	// were accepted and it started running
+	commandStarted bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -27,6 +39,8 @@ It tracks what you're working on, even when it doesn't make it into Jira or othe
 ticketing systems. No more un-tracked side quests.`,
 	Version: Version,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		commandStarted = true
+
 		// Create a context with trace ID
 		ctx := context.WithValue(cmd.Context(), "trace_id", uuid.New().String())
 		cmd.SetContext(ctx)
@@ -36,6 +50,44 @@ ticketing systems. No more un-tracked side quests.`,
 			logger.SetLevel(logger.DebugLevel)
 			logger.Debug("Debug mode enabled")
 		}
+
+		if err := setOutputFormat(outputFlag); err != nil {
+			fprintError(os.Stderr, "Error:", err)
+			os.Exit(exitUsage)
+		}
+		if err := checkJSONOutput(cmd); err != nil {
+			fprintError(os.Stderr, "Error:", err)
+			os.Exit(exitUsage)
+		}
+		if err := setOutputTemplate(formatFlag); err != nil {
+			fprintError(os.Stderr, "Error:", err)
+			os.Exit(exitUsage)
+		}
+
+		// Select the workspace before anything reads the configuration
+		if err := selectWorkspace(workspaceFlag); err != nil {
+			printError("Error selecting workspace:", err)
+			os.Exit(exitUsage)
+		}
+		beginCommandScope()
+		setOutputStyle(noColor)
+		output.SetMarkdown(!raw)
+		output.SetQuiet(quiet)
+
+		// Complete work left active by mistake or whose branch was merged,
+		// then pause active work left running while the user was away and
+		// warn about work that has run for long without a break
+		backgroundChecks := func() {
+			checkStaleWork(cmd)
+			checkEndedBranches(cmd)
+			checkIdle(cmd)
+			checkLongRunningWork(cmd)
+		}
+		if jsonOutput() || templateOutput() {
+			toStderr(backgroundChecks)
+		} else {
+			backgroundChecks()
+		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		log := logger.WithContext(cmd.Context())
@@ -53,7 +105,13 @@ func Execute() error {
 
 	if err := rootCmd.Execute(); err != nil {
 		logger.WithContext(ctx).Error("Failed to execute command: %v", err)
-		os.Exit(1)
+		// Errors from before the command started are of its arguments or flags
+		if !commandStarted {
+			setExitCode(exitUsage)
+		}
+	}
+	if exitCode != exitOK {
+		os.Exit(exitCode)
 	}
 	return nil
 }
@@ -61,6 +119,16 @@ func Execute() error {
 func init() {
 	// Add global flags
 	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug mode")
+	rootCmd.PersistentFlags().StringVarP(&workspaceFlag, "workspace", "w", "", "Workspace to use (defaults to $PLANNET_WORKSPACE or 'plannet workspace use')")
+	rootCmd.RegisterFlagCompletionFunc("workspace", completeWorkspaceNames)
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", string(output.Text), "Output format: text, or json for scripts")
+	rootCmd.RegisterFlagCompletionFunc("output", completeOutputFormats)
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().BoolVar(&raw, "raw", false, "Print ticket descriptions and LLM replies as raw markdown")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Leave out decorative output, such as hints, progress and separators")
+
+	// Errors logged by commands decide the exit code
+	logger.SetErrorHook(setExitCodeFor)
 
 	// Add version flag
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// projectFile holds the details of a per-repository database
+const projectFile = "project.json"
+
+// projectInfo identifies the git repository a database belongs to
+type projectInfo struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Remote string `json:"remote,omitempty"`
+}
+
+// projectDB is a tracked work database and the project it belongs to
+type projectDB struct {
+	Project projectInfo // Zero for the global database
+	Dir     string
+}
+
+var unsafeKeyChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// getProjectsDir returns the directory holding per-repository databases
+func getProjectsDir() (string, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "projects"), nil
+}
+
+// commandScope caches the project and database directory of the running
+// command, so the configuration and repository are looked up once rather
+// than on every access. It's only active once a command starts; outside one,
+// such as in tests, nothing is cached.
+var commandScope struct {
+	active          bool
+	projectResolved bool
+	project         *projectInfo
+	dbDir           string
+}
+
+// beginCommandScope starts caching the project and database directory for
+// the command about to run
+func beginCommandScope() {
+	commandScope.active = true
+	commandScope.projectResolved = false
+	commandScope.project = nil
+	commandScope.dbDir = ""
+}
+
+// scopedProject returns the repository work is tracked in, or nil when work
+// isn't scoped per repository or the current directory isn't in one
+func scopedProject() *projectInfo {
+	if commandScope.active && commandScope.projectResolved {
+		return commandScope.project
+	}
+	var project *projectInfo
+	if scopeByRepo() {
+		project = currentProject()
+	}
+	if commandScope.active {
+		commandScope.project = project
+		commandScope.projectResolved = true
+	}
+	return project
+}
+
+// scopeByRepo reports whether tracked work is stored per repository
+func scopeByRepo() bool {
+	cfg, err := config.Get()
+	return err == nil && cfg.ScopeByRepo
+}
+
+// currentProject returns the repository the current directory belongs to,
+// or nil outside a git repository
+func currentProject() *projectInfo {
+	backend := getGitBackend()
+	topLevel, err := backend.TopLevel(".")
+	if err != nil {
+		return nil
+	}
+	project := &projectInfo{Path: topLevel}
+	project.Name = filepath.Base(project.Path)
+
+	if remote, err := backend.RemoteURL(".", "origin"); err == nil {
+		project.Remote = remote
+		name := strings.TrimSuffix(strings.TrimRight(project.Remote, "/"), ".git")
+		if i := strings.LastIndexAny(name, "/:"); i >= 0 && i < len(name)-1 {
+			project.Name = name[i+1:]
+		}
+	}
+	return project
+}
+
+// projectKey returns the directory name for a project's database.
+// Projects are keyed by their remote so every clone shares a database,
+// falling back to the top-level directory for repositories without one.
+func projectKey(project projectInfo) string {
+	id := project.Remote
+	if id == "" {
+		id = project.Path
+	}
+	sum := sha256.Sum256([]byte(id))
+	name := unsafeKeyChars.ReplaceAllString(project.Name, "-")
+	return fmt.Sprintf("%s-%s", name, hex.EncodeToString(sum[:4]))
+}
+
+// projectDBDir returns the database directory for a project,
+// recording the project details alongside it
+func projectDBDir(project projectInfo) (string, error) {
+	projectsDir, err := getProjectsDir()
+	if err != nil {
+		return "", err
+	}
+	projectDir := filepath.Join(projectsDir, projectKey(project))
+
+	infoFile := filepath.Join(projectDir, projectFile)
+	if _, err := os.Stat(infoFile); os.IsNotExist(err) {
+		if err := os.MkdirAll(projectDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create project directory: %w", err)
+		}
+		data, err := json.MarshalIndent(project, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal project info: %w", err)
+		}
+		if err := os.WriteFile(infoFile, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", projectFile, err)
+		}
+	}
+
+	return filepath.Join(projectDir, "db"), nil
+}
+
+// listProjectDBs returns the global database followed by every per-repository database
+func listProjectDBs() ([]projectDB, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return nil, err
+	}
+	dbs := []projectDB{{Dir: filepath.Join(dataDir, "db")}}
+
+	projectsDir, err := getProjectsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(projectsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dbs, nil
+		}
+		return nil, fmt.Errorf("failed to read projects directory: %w", err)
+	}
+
+	var projects []projectDB
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		projectDir := filepath.Join(projectsDir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(projectDir, projectFile))
+		if err != nil {
+			continue
+		}
+		var project projectInfo
+		if err := json.Unmarshal(data, &project); err != nil {
+			return nil, fmt.Errorf("failed to parse %s for %s: %w", projectFile, entry.Name(), err)
+		}
+		projects = append(projects, projectDB{Project: project, Dir: filepath.Join(projectDir, "db")})
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Project.Name < projects[j].Project.Name
+	})
+
+	return append(dbs, projects...), nil
+}
+
+// getAllTrackedWork gets tracked work from every database, with each
+// entry's Project set to the repository it was tracked in
+func getAllTrackedWork() ([]TrackedWork, error) {
+	dbs, err := listProjectDBs()
+	if err != nil {
+		return nil, err
+	}
+
+	var trackedWork []TrackedWork
+	for _, db := range dbs {
+		if _, err := os.Stat(db.Dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := checkDBDir(db.Dir); err != nil {
+			return nil, err
+		}
+		work, err := readTrackedWorkIn(db.Dir)
+		if err != nil {
+			return nil, err
+		}
+		for i := range work {
+			work[i].Project = db.Project.Name
+		}
+		trackedWork = append(trackedWork, work...)
+	}
+	return trackedWork, nil
+}
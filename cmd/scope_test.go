@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestProjectKey(t *testing.T) {
+	https := projectKey(projectInfo{Name: "plannet", Path: "/home/a/plannet", Remote: "https://github.com/plannet-dev/plannet.git"})
+	clone := projectKey(projectInfo{Name: "plannet", Path: "/home/a/other-clone", Remote: "https://github.com/plannet-dev/plannet.git"})
+	local := projectKey(projectInfo{Name: "plannet", Path: "/home/a/plannet"})
+
+	if https != clone {
+		t.Errorf("Expected clones of the same remote to share a key, got %s and %s", https, clone)
+	}
+	if https == local {
+		t.Error("Expected repositories without a remote to be keyed by path")
+	}
+	if !strings.HasPrefix(https, "plannet-") {
+		t.Errorf("Expected key to start with the project name, got %s", https)
+	}
+}
+
+func TestPerRepoScoping(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	cfg.ScopeByRepo = true
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	// Track work outside any repository, which goes to the global database
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	start := time.Now().Add(-time.Hour)
+	global := TrackedWork{ID: "global-1", Description: "Global", StartTime: start, EndTime: start.Add(time.Minute), Status: "completed"}
+	if err := saveTrackedWork(global); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	// Track work inside a repository
+	repoDir := filepath.Join(tempDir, "webapp")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatalf("Failed to create repo dir: %v", err)
+	}
+	if err := exec.Command("git", "-C", repoDir, "init").Run(); err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	if err := os.Chdir(repoDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	scoped := TrackedWork{ID: "repo-1", Description: "Scoped", StartTime: start, EndTime: start.Add(time.Minute), Status: "completed"}
+	if err := saveTrackedWork(scoped); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(trackedWork) != 1 || trackedWork[0].ID != "repo-1" {
+		t.Errorf("Expected only the repository's work, got %v", trackedWork)
+	}
+
+	allWork, err := getAllTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get all tracked work: %v", err)
+	}
+	projects := map[string]string{}
+	for _, w := range allWork {
+		projects[w.ID] = w.Project
+	}
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 work items across projects, got %d", len(projects))
+	}
+	if projects["global-1"] != "" {
+		t.Errorf("Expected global work to have no project, got %q", projects["global-1"])
+	}
+	if projects["repo-1"] != "webapp" {
+		t.Errorf("Expected repository work to belong to webapp, got %q", projects["repo-1"])
+	}
+}
+
+func TestCommandScopeResolvesTheDatabaseOnce(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+	beginCommandScope()
+	defer func() { commandScope.active = false }()
+
+	dbDir, err := getDBDir()
+	if err != nil {
+		t.Fatalf("Failed to get db dir: %v", err)
+	}
+	if want := filepath.Join(tempDir, ".plannet", "db"); dbDir != want {
+		t.Fatalf("getDBDir() = %q, want %q", dbDir, want)
+	}
+
+	// Later accesses in the same command reuse the directory
+	os.Setenv("HOME", t.TempDir())
+	if again, err := getDBDir(); err != nil || again != dbDir {
+		t.Errorf("getDBDir() again = %q, %v, want %q", again, err, dbDir)
+	}
+
+	// The next command resolves it afresh
+	beginCommandScope()
+	if next, err := getDBDir(); err != nil || next == dbDir {
+		t.Errorf("getDBDir() in the next command = %q, %v, want a new directory", next, err)
+	}
+}
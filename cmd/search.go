@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// dateInputLayout is the layout used for date flags
+const dateInputLayout = "2006-01-02"
+
+// workFilter selects tracked work by text, date range and status
+type workFilter struct {
+	Terms   []string  // Lowercased terms that must all match
+	From    time.Time // Zero for no lower bound
+	To      time.Time // Zero for no upper bound (exclusive)
+	Status  string    // Empty for any status
+	Tickets []string  // Work must have one of these tickets, if any are given
+	Tags    []string  // Work must have one of these tags, if any are given
+}
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search tracked work",
+	Long: `Search descriptions, tags, ticket IDs and branch names across all
+tracked work. Every word in the query must match (case-insensitively) for
+work to be shown. Results can be narrowed by when the work started, its
+tickets, tags and status.
+
+With --semantic, work is found by meaning rather than by words, using an
+embedding model: descriptions, notes and commit messages that are about
+the query are found even when they use other words. The first search
+indexes all tracked work, and later ones the work that changed.
+
+Examples:
+  plannet search login bug
+  plannet search PROJ-123 --status completed
+  plannet search refactor --since 2024-05-01 --until 2024-05-31
+  plannet search --semantic "that auth race condition"`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, err := searchFilterFromFlags(cmd)
+		if err != nil {
+			fmt.Println("Invalid search:", err)
+			setExitCode(exitUsage)
+			return
+		}
+		if semantic, _ := cmd.Flags().GetBool("semantic"); semantic {
+			limit, _ := cmd.Flags().GetInt("limit")
+			runSemanticSearch(cmd.Context(), strings.Join(args, " "), filter, limit)
+			return
+		}
+		filter.Terms = strings.Fields(strings.ToLower(strings.Join(args, " ")))
+		runSearch(filter)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	supportJSONOutput(searchCmd)
+	addWorkFilterFlags(searchCmd, "")
+	searchCmd.Flags().String("from", "", "Only show work started on or after this date (YYYY-MM-DD)")
+	searchCmd.Flags().String("to", "", "Only show work started on or before this date (YYYY-MM-DD)")
+	searchCmd.Flags().MarkDeprecated("from", "use --since instead")
+	searchCmd.Flags().MarkDeprecated("to", "use --until instead")
+	searchCmd.Flags().Bool("semantic", false, "Find work by meaning, using an embedding model")
+	searchCmd.Flags().Int("limit", 10, "Most results to show with --semantic")
+}
+
+// searchFilterFromFlags returns the filter the flags of search select, with
+// the deprecated --from and --to standing in for --since and --until
+func searchFilterFromFlags(cmd *cobra.Command) (workFilter, error) {
+	for alias, name := range map[string]string{"from": "since", "to": "until"} {
+		if cmd.Flags().Changed(alias) && !cmd.Flags().Changed(name) {
+			value, _ := cmd.Flags().GetString(alias)
+			if err := cmd.Flags().Set(name, value); err != nil {
+				return workFilter{}, err
+			}
+		}
+	}
+	return workFilterFromFlags(cmd)
+}
+
+func runSearch(filter workFilter) {
+	// Load configuration
+	_, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	results := filter.apply(trackedWork)
+
+	// Sort results by start time (newest first)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].StartTime.After(results[j].StartTime)
+	})
+
+	if jsonOutput() {
+		printJSON(workOutputs(results))
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println("No matching work found.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	fmt.Printf("Found %d matching work item(s):\n", len(results))
+	for _, work := range results {
+		printWorkEntry(work)
+		if work.Context.Branch != "" {
+			fmt.Printf("  Branch: %s\n", work.Context.Branch)
+		}
+	}
+}
+
+// newWorkFilter builds a filter from a query and a status flag
+func newWorkFilter(query, status string) (workFilter, error) {
+	filter := workFilter{
+		Terms:  strings.Fields(strings.ToLower(query)),
+		Status: strings.ToLower(status),
+	}
+
+	switch filter.Status {
+	case "", "active", "paused", "completed":
+	default:
+		return filter, fmt.Errorf("unknown status %q (use active, paused or completed)", status)
+	}
+	return filter, nil
+}
+
+// matches reports whether a piece of work passes the filter
+func (f workFilter) matches(work TrackedWork) bool {
+	if f.Status != "" && work.Status != f.Status {
+		return false
+	}
+	if !f.From.IsZero() && work.StartTime.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !work.StartTime.Before(f.To) {
+		return false
+	}
+	if len(f.Tickets) > 0 && !containsFold(work.TicketIDs, f.Tickets) {
+		return false
+	}
+	if len(f.Tags) > 0 && !containsFold(work.Tags, f.Tags) {
+		return false
+	}
+
+	if len(f.Terms) == 0 {
+		return true
+	}
+	fields := []string{work.Description, work.Context.Branch}
+	fields = append(fields, work.TicketIDs...)
+	fields = append(fields, work.Tags...)
+	text := strings.ToLower(strings.Join(fields, "\n"))
+	for _, term := range f.Terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the work that passes the filter
+func (f workFilter) apply(trackedWork []TrackedWork) []TrackedWork {
+	var matched []TrackedWork
+	for _, work := range trackedWork {
+		if f.matches(work) {
+			matched = append(matched, work)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWorkFilter(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 10, 0, 0, 0, time.Local)
+	}
+	work := []TrackedWork{
+		{ID: "1", Description: "Fix login bug", TicketIDs: []string{"PROJ-123"}, StartTime: day(1), Status: "completed"},
+		{ID: "2", Description: "Refactor auth", Tags: []string{"Backend"}, StartTime: day(10), Status: "paused"},
+		{ID: "3", Description: "Write docs", StartTime: day(20), Status: "active", Context: WorkContext{Branch: "feature/PROJ-456-login"}},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		status string
+		want   []string
+	}{
+		{"description", "login bug", "", []string{"1"}},
+		{"ticket", "proj-123", "", []string{"1"}},
+		{"tag", "backend", "", []string{"2"}},
+		{"branch", "login", "", []string{"1", "3"}},
+		{"status", "login", "active", []string{"3"}},
+		{"no match", "deploy", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newWorkFilter(tt.query, tt.status)
+			if err != nil {
+				t.Fatalf("Failed to create filter: %v", err)
+			}
+
+			var got []string
+			for _, w := range filter.apply(work) {
+				got = append(got, w.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	if _, err := newWorkFilter("", "done"); err == nil {
+		t.Error("Expected an error for an unknown status")
+	}
+}
+
+func TestSearchFilterFromDeprecatedFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	addWorkFilterFlags(cmd, "")
+	cmd.Flags().String("from", "", "")
+	cmd.Flags().String("to", "", "")
+	if err := cmd.ParseFlags([]string{"--from", "2024-05-01", "--to", "2024-05-10", "--tag", "backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := searchFilterFromFlags(cmd)
+	if err != nil {
+		t.Fatalf("Failed to create filter: %v", err)
+	}
+	if want := time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local); !filter.From.Equal(want) {
+		t.Errorf("From = %v, want %v", filter.From, want)
+	}
+	if want := time.Date(2024, 5, 11, 0, 0, 0, 0, time.Local); !filter.To.Equal(want) {
+		t.Errorf("To = %v, want %v", filter.To, want)
+	}
+	if len(filter.Tags) != 1 || filter.Tags[0] != "backend" {
+		t.Errorf("Tags = %v, want [backend]", filter.Tags)
+	}
+}
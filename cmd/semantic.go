@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+)
+
+// semanticIndexPath is where the embeddings of tracked work are kept, in
+// the database directory. The index is in a directory of its own, as the
+// database's own files are the JSON files at its top, and it isn't backed
+// up, as it can be rebuilt.
+var semanticIndexPath = filepath.Join("index", "embeddings.json")
+
+// semanticIndex holds the embedding vector of each piece of tracked work
+type semanticIndex struct {
+	// Model is the embedder the vectors are from; vectors of another
+	// model can't be compared with them
+	Model   string                   `json:"model"`
+	Entries map[string]semanticEntry `json:"entries"` // By work ID
+}
+
+// semanticEntry is the embedding of a piece of tracked work
+type semanticEntry struct {
+	// Hash is that of what the work's text is made of, to tell when the
+	// work has changed since it was embedded
+	Hash   string    `json:"hash"`
+	Vector []float32 `json:"vector"`
+}
+
+// semanticResult is tracked work found by meaning, with how alike it is
+// to the query
+type semanticResult struct {
+	Work  TrackedWork
+	Score float64
+}
+
+// semanticOutput is a result of a semantic search as --output json prints it
+type semanticOutput struct {
+	workOutput
+	Similarity float64 `json:"similarity"`
+}
+
+// loadSemanticIndex reads the index of the database in dbDir. An index
+// of another model is left for a new one, as its vectors can't be used.
+func loadSemanticIndex(dbDir, model string) (*semanticIndex, error) {
+	index := &semanticIndex{Model: model, Entries: map[string]semanticEntry{}}
+	data, err := os.ReadFile(filepath.Join(dbDir, semanticIndexPath))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	var saved semanticIndex
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Model != model || saved.Entries == nil {
+		return index, nil
+	}
+	return &saved, nil
+}
+
+// save writes the index of the database in dbDir
+func (idx *semanticIndex) save(dbDir string) error {
+	path := filepath.Join(dbDir, semanticIndexPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create search index directory: %w", err)
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// workText returns the text of tracked work its embedding is made from:
+// its description, tickets, tags, branch, notes and subtasks
+func workText(work TrackedWork) string {
+	lines := []string{work.Description}
+	if len(work.TicketIDs) > 0 {
+		lines = append(lines, "Tickets: "+strings.Join(work.TicketIDs, ", "))
+	}
+	if len(work.Tags) > 0 {
+		lines = append(lines, "Tags: "+strings.Join(work.Tags, ", "))
+	}
+	if work.Context.Branch != "" {
+		lines = append(lines, "Branch: "+work.Context.Branch)
+	}
+	for _, note := range work.Notes {
+		lines = append(lines, note.Text)
+	}
+	for _, subtask := range work.Subtasks {
+		lines = append(lines, subtask.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// workHash returns the hash of what the embedding of tracked work is made
+// of, including its last commit, whose messages are embedded too
+func workHash(work TrackedWork) string {
+	sum := sha256.Sum256([]byte(workText(work) + "\n" + work.Context.CommitHash))
+	return hex.EncodeToString(sum[:])
+}
+
+// workCommitMessages returns the messages of the commits made for tracked
+// work, when they can be read from the current repository
+func workCommitMessages(work TrackedWork) []string {
+	if work.Context.StartCommit == "" || work.Context.CommitHash == "" {
+		return nil
+	}
+	commits, err := getGitBackend().LogRange(".", work.Context.StartCommit, work.Context.CommitHash)
+	if err != nil {
+		return nil
+	}
+	messages := make([]string, len(commits))
+	for i, commit := range commits {
+		messages[i] = commit.FullMessage()
+	}
+	return messages
+}
+
+// update embeds the work that is new or changed since it was indexed, and
+// drops the work that is gone. It returns how much work it embedded.
+func (idx *semanticIndex) update(ctx context.Context, embedder llm.Embedder, trackedWork []TrackedWork, commitMessages func(TrackedWork) []string) (int, error) {
+	var stale []TrackedWork
+	var texts, hashes []string
+	current := make(map[string]bool, len(trackedWork))
+	for _, work := range trackedWork {
+		current[work.ID] = true
+		hash := workHash(work)
+		if entry, ok := idx.Entries[work.ID]; ok && entry.Hash == hash {
+			continue
+		}
+		text := workText(work)
+		if messages := commitMessages(work); len(messages) > 0 {
+			text += "\nCommits:\n" + strings.Join(messages, "\n")
+		}
+		stale = append(stale, work)
+		texts = append(texts, text)
+		hashes = append(hashes, hash)
+	}
+	for id := range idx.Entries {
+		if !current[id] {
+			delete(idx.Entries, id)
+		}
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return 0, err
+	}
+	for i, work := range stale {
+		idx.Entries[work.ID] = semanticEntry{Hash: hashes[i], Vector: vectors[i]}
+	}
+	return len(stale), nil
+}
+
+// rank returns the indexed work most alike to the query's vector, most
+// alike first, up to limit results
+func (idx *semanticIndex) rank(query []float32, trackedWork []TrackedWork, limit int) []semanticResult {
+	var results []semanticResult
+	for _, work := range trackedWork {
+		entry, ok := idx.Entries[work.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, semanticResult{Work: work, Score: llm.CosineSimilarity(query, entry.Vector)})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// runSemanticSearch finds the tracked work whose meaning is closest to the
+// query, bringing the index up to date first
+func runSemanticSearch(ctx context.Context, query string, filter workFilter, limit int) {
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// The query's words select by meaning, not by matching, so the filter
+	// has no terms
+	embedder, err := llm.NewEmbedder(cfg)
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+	dbDir, err := openDB()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+	trackedWork, err := readTrackedWorkIn(dbDir)
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	index, err := loadSemanticIndex(dbDir, embedder.Name())
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+	indexed, err := index.update(ctx, embedder, trackedWork, workCommitMessages)
+	if err != nil {
+		printError("Error indexing tracked work:", err)
+		return
+	}
+	if indexed > 0 {
+		if !jsonOutput() {
+			fmt.Printf("Indexed %d work item(s).\n", indexed)
+		}
+		if err := index.save(dbDir); err != nil {
+			printError("Error:", err)
+		}
+	}
+
+	vectors, err := embedder.Embed(ctx, []string{query})
+	if err != nil {
+		printError("Error embedding the query:", err)
+		return
+	}
+
+	results := index.rank(vectors[0], filter.apply(trackedWork), limit)
+	if jsonOutput() {
+		outputs := make([]semanticOutput, len(results))
+		for i, result := range results {
+			outputs[i] = semanticOutput{workOutput: newWorkOutput(result.Work), Similarity: result.Score}
+		}
+		printJSON(outputs)
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println("No matching work found.")
+		setExitCode(exitNotFound)
+		return
+	}
+	fmt.Printf("Closest %d work item(s):\n", len(results))
+	for _, result := range results {
+		printWorkEntry(result.Work)
+		fmt.Printf("  Similarity: %.2f\n", result.Score)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// fakeEmbedder embeds texts by which of a few words they contain
+type fakeEmbedder struct {
+	embedded []string
+}
+
+var fakeEmbeddingWords = []string{"auth", "race", "csv", "export"}
+
+func (e *fakeEmbedder) Name() string {
+	return "fake/words"
+}
+
+func (e *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		e.embedded = append(e.embedded, text)
+		vectors[i] = make([]float32, len(fakeEmbeddingWords))
+		for j, word := range fakeEmbeddingWords {
+			if strings.Contains(strings.ToLower(text), word) {
+				vectors[i][j] = 1
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func TestSemanticIndex(t *testing.T) {
+	dbDir := t.TempDir()
+	trackedWork := []TrackedWork{
+		{ID: "1", Description: "Fix token refresh", Notes: []Note{{Text: "Two requests race to refresh auth"}}},
+		{ID: "2", Description: "Export entries", Tags: []string{"csv"}},
+		{ID: "3", Description: "Write docs"},
+	}
+	commits := func(work TrackedWork) []string {
+		if work.ID == "3" {
+			return []string{"Document the CSV export"}
+		}
+		return nil
+	}
+
+	embedder := &fakeEmbedder{}
+	index, err := loadSemanticIndex(dbDir, embedder.Name())
+	if err != nil {
+		t.Fatalf("loadSemanticIndex() failed: %v", err)
+	}
+	if n, err := index.update(context.Background(), embedder, trackedWork, commits); err != nil || n != 3 {
+		t.Fatalf("update() = %d, %v, want all 3 indexed", n, err)
+	}
+	if !strings.Contains(embedder.embedded[2], "Commits:\nDocument the CSV export") {
+		t.Errorf("Embedded %q without its commits", embedder.embedded[2])
+	}
+	if err := index.save(dbDir); err != nil {
+		t.Fatalf("save() failed: %v", err)
+	}
+
+	// Only the work that changed or is new is embedded again, and work
+	// that is gone is dropped
+	index, _ = loadSemanticIndex(dbDir, embedder.Name())
+	embedder.embedded = nil
+	trackedWork[1].Description = "Export entries as a spreadsheet"
+	trackedWork = append(trackedWork[:2], TrackedWork{ID: "4", Description: "Auth login race"})
+	if n, _ := index.update(context.Background(), embedder, trackedWork, commits); n != 2 {
+		t.Errorf("update() embedded %d work items, want the changed and new 2: %q", n, embedder.embedded)
+	}
+	if _, ok := index.Entries["3"]; ok {
+		t.Error("update() kept the index of work that is gone")
+	}
+
+	query, _ := embedder.Embed(context.Background(), []string{"that auth race condition"})
+	results := index.rank(query[0], trackedWork, 2)
+	if len(results) != 2 || results[0].Score < 0.99 || results[1].Work.ID == "2" {
+		t.Errorf("rank() = %+v, want the two auth race items first", results)
+	}
+
+	// An index of another model starts over
+	index, _ = loadSemanticIndex(dbDir, "other/model")
+	if len(index.Entries) != 0 {
+		t.Errorf("loadSemanticIndex() of another model kept %d entries", len(index.Entries))
+	}
+}
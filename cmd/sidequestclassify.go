@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/llm"
+)
+
+// sideQuestKinds are the kinds of work side quests are classified as
+var sideQuestKinds = []string{"feature", "fix", "refactor", "test", "docs", "chore"}
+
+// sideQuestSchema is the JSON schema of the classifications the LLM writes
+var sideQuestSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "commits": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "hash": {"type": "string", "description": "The hash of the commit, as given"},
+          "kind": {"type": "string", "enum": ["feature", "fix", "refactor", "test", "docs", "chore"]},
+          "summary": {"type": "string", "description": "A one-line ticket summary of the work"},
+          "ticket_worthy": {"type": "boolean", "description": "Whether the work deserves a ticket of its own"}
+        },
+        "required": ["hash", "kind", "summary", "ticket_worthy"],
+        "additionalProperties": false
+      }
+    }
+  },
+  "required": ["commits"],
+  "additionalProperties": false
+}`)
+
+// sideQuestClassification is a side quest as 'plannet sidequests --output json'
+// prints it. The LLM's classification is left out when none is configured.
+type sideQuestClassification struct {
+	Hash         string `json:"hash"`
+	Message      string `json:"message"`
+	Kind         string `json:"kind,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+	TicketWorthy *bool  `json:"ticket_worthy,omitempty"`
+}
+
+// sideQuestClassificationPrompt asks the LLM to classify side quests
+func sideQuestClassificationPrompt(commits []Commit) string {
+	var b strings.Builder
+	b.WriteString("Classify the work done in each of these git commits, which were made without a ticket, ")
+	fmt.Fprintf(&b, "as one of %s. Say whether it deserves a ticket of its own, ", strings.Join(sideQuestKinds, ", "))
+	b.WriteString("and write a one-line ticket summary of it.\n\n")
+	for _, commit := range commits {
+		fmt.Fprintf(&b, "Commit %s\n%s\n\n", commit.Hash, commit.FullMessage())
+	}
+	return b.String()
+}
+
+// classifySideQuests classifies side quests with the LLM. Commits the LLM
+// left out are returned without a classification.
+func classifySideQuests(ctx context.Context, cfg *config.Config, commits []Commit) ([]sideQuestClassification, error) {
+	conv := llm.UserPrompt(cfg.SystemPrompt, sideQuestClassificationPrompt(commits))
+	conv.Schema = sideQuestSchema
+	reply, err := llm.SendJSON(ctx, cfg, conv)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Commits []sideQuestClassification `json:"commits"`
+	}
+	if err := json.Unmarshal([]byte(reply), &result); err != nil {
+		return nil, fmt.Errorf("the LLM replied with invalid JSON: %w", err)
+	}
+	return mergeSideQuestClassifications(commits, result.Commits), nil
+}
+
+// mergeSideQuestClassifications returns the commits with the LLM's
+// classification of each, matched by hash or by its abbreviation
+func mergeSideQuestClassifications(commits []Commit, classified []sideQuestClassification) []sideQuestClassification {
+	merged := make([]sideQuestClassification, len(commits))
+	for i, commit := range commits {
+		merged[i] = sideQuestClassification{Hash: commit.Hash, Message: commit.Message}
+		for _, c := range classified {
+			if c.Hash != "" && strings.HasPrefix(commit.Hash, c.Hash) {
+				merged[i].Kind = c.Kind
+				merged[i].Summary = strings.TrimSpace(c.Summary)
+				merged[i].TicketWorthy = c.TicketWorthy
+				break
+			}
+		}
+	}
+	return merged
+}
+
+// printSideQuestsJSON prints side quests as a JSON array
+func printSideQuestsJSON(w io.Writer, sideQuests []sideQuestClassification) error {
+	if sideQuests == nil {
+		sideQuests = []sideQuestClassification{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sideQuests)
+}
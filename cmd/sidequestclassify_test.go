@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSideQuestClassification(t *testing.T) {
+	if !json.Valid(sideQuestSchema) {
+		t.Fatal("sideQuestSchema is not valid JSON")
+	}
+	commits := []Commit{
+		{Hash: "abc1234def", Message: "Fix flaky test", Body: "It depended on the time zone."},
+		{Hash: "0123456789", Message: "Bump deps"},
+	}
+	prompt := sideQuestClassificationPrompt(commits)
+	if !strings.Contains(prompt, "Commit abc1234def\nFix flaky test\n\nIt depended on the time zone.") {
+		t.Errorf("Prompt doesn't contain the first commit:\n%s", prompt)
+	}
+
+	worthy := true
+	merged := mergeSideQuestClassifications(commits, []sideQuestClassification{
+		{Hash: "abc1234", Kind: "fix", Summary: " Make the test independent of the time zone ", TicketWorthy: &worthy},
+	})
+	if merged[0].Kind != "fix" || merged[0].Summary != "Make the test independent of the time zone" || merged[0].TicketWorthy == nil {
+		t.Errorf("First side quest = %+v, want the classification matched by abbreviated hash", merged[0])
+	}
+	if merged[1].Kind != "" || merged[1].TicketWorthy != nil || merged[1].Message != "Bump deps" {
+		t.Errorf("Second side quest = %+v, want no classification", merged[1])
+	}
+
+	var out bytes.Buffer
+	if err := printSideQuestsJSON(&out, merged); err != nil {
+		t.Fatal(err)
+	}
+	var printed []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &printed); err != nil {
+		t.Fatalf("printSideQuestsJSON() printed invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(printed) != 2 || printed[0]["ticket_worthy"] != true {
+		t.Errorf("printSideQuestsJSON() = %s", out.String())
+	}
+	if _, ok := printed[1]["kind"]; ok {
+		t.Errorf("Unclassified side quest printed with a kind: %s", out.String())
+	}
+
+	out.Reset()
+	printSideQuestsJSON(&out, nil)
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("printSideQuestsJSON(nil) = %q, want []", out.String())
+	}
+}
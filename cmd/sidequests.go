@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/llm"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultSideQuestCommits is how many recent commits are searched for side quests
+const defaultSideQuestCommits = 20
+
+// ticketDraft is a ticket drafted from the commits of a side quest
+type ticketDraft struct {
+	Summary     string
+	Description string
+}
+
+// sidequestsCmd represents the sidequests command
+var sidequestsCmd = &cobra.Command{
+	Use:   "sidequests",
+	Short: "List recent commits that aren't linked to a ticket",
+	Long: `List recent commits that don't reference a ticket. Use 'plannet sidequests
+promote' to turn them into a ticket of their own.
+
+With --output json, they are printed as JSON for scripts, each classified
+by the LLM, when one is configured: its kind of work, a ticket summary,
+and whether it deserves a ticket of its own.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, _ := cmd.Flags().GetInt("count")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		runSidequests(cmd.Context(), count, asJSON || jsonOutput())
+	},
+}
+
+// sidequestsPromoteCmd represents the sidequests promote command
+var sidequestsPromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Create a ticket from side quest commits",
+	Long: `Select one or more side quest commits and create a ticket for them in
+your ticket system.
+The ticket is drafted by the LLM from the commit messages and the files they
+changed (or from the commit messages alone when no LLM is configured), and you
+can edit the summary before it is created. Tracked work recorded for the
+selected commits can then be linked to the new ticket.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		count, _ := cmd.Flags().GetInt("count")
+		project, _ := cmd.Flags().GetString("project")
+		issueType, _ := cmd.Flags().GetString("type")
+		runSidequestsPromote(cmd.Context(), count, project, issueType)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sidequestsCmd)
+	supportJSONOutput(sidequestsCmd)
+	sidequestsCmd.AddCommand(sidequestsPromoteCmd)
+
+	sidequestsCmd.PersistentFlags().IntP("count", "n", defaultSideQuestCommits, "Number of recent commits to search")
+	sidequestsCmd.Flags().Bool("json", false, "Print the side quests as JSON, classified by the LLM")
+	sidequestsCmd.Flags().MarkDeprecated("json", "use --output json instead")
+	sidequestsPromoteCmd.Flags().String("project", "", "Where to create the ticket, such as a Jira project key (asked for when Jira needs one)")
+	sidequestsPromoteCmd.Flags().String("type", "", "Type of the new ticket, for systems that have types")
+}
+
+func runSidequests(ctx context.Context, count int, asJSON bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	sideQuests, err := recentSideQuests(cfg, count)
+	if err != nil {
+		printError("Error getting recent commits:", err)
+		return
+	}
+	if asJSON {
+		runSidequestsJSON(ctx, cfg, sideQuests)
+		return
+	}
+	if len(sideQuests) == 0 {
+		fmt.Printf("No side quests in the last %d commits.\n", count)
+		setExitCode(exitNotFound)
+		return
+	}
+
+	if !output.Quiet() {
+		fmt.Println(output.Heading("Side quests:"))
+	}
+	for _, commit := range sideQuests {
+		fmt.Printf("  %s\n", output.SideQuest(describeCommit(commit)))
+	}
+}
+
+// runSidequestsJSON prints side quests as JSON, classified by the LLM when
+// one is configured. Errors go to stderr, to keep stdout parseable.
+func runSidequestsJSON(ctx context.Context, cfg *config.Config, sideQuests []Commit) {
+	classified := mergeSideQuestClassifications(sideQuests, nil)
+	if len(sideQuests) > 0 && llm.IsConfigured(cfg) {
+		result, err := classifySideQuests(ctx, cfg, sideQuests)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Could not classify the side quests with the LLM:", err)
+		} else {
+			classified = result
+		}
+	}
+	if err := printSideQuestsJSON(os.Stdout, classified); err != nil {
+		fprintError(os.Stderr, "Error printing side quests:", err)
+	}
+}
+
+func runSidequestsPromote(ctx context.Context, count int, projectKey, issueType string) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	system, err := systems.New(cfg)
+	if err != nil {
+		printError("Error:", err)
+		setExitCode(exitConfig)
+		return
+	}
+	if !isInteractive() {
+		fmt.Println("Promoting side quests needs an interactive terminal.")
+		return
+	}
+
+	sideQuests, err := recentSideQuests(cfg, count)
+	if err != nil {
+		printError("Error getting recent commits:", err)
+		return
+	}
+	if len(sideQuests) == 0 {
+		fmt.Printf("No side quests in the last %d commits.\n", count)
+		setExitCode(exitNotFound)
+		return
+	}
+
+	items := make([]string, len(sideQuests))
+	for i, commit := range sideQuests {
+		items[i] = describeCommit(commit)
+	}
+	indexes, err := selectMany("Select side quests to promote", items)
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			fmt.Println("\nOperation cancelled by user.")
+			return
+		}
+		printError("Error selecting side quests:", err)
+		return
+	}
+	if len(indexes) == 0 {
+		fmt.Println("No side quests selected.")
+		return
+	}
+	var selected []Commit
+	for _, i := range indexes {
+		selected = append(selected, sideQuests[i])
+	}
+
+	draft := draftSideQuestTicket(cfg, selected)
+
+	summaryPrompt := promptui.Prompt{
+		Label:     "Summary",
+		Default:   draft.Summary,
+		AllowEdit: true,
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("summary cannot be empty")
+			}
+			return nil
+		},
+	}
+
+	fmt.Printf("\nDescription:\n%s\n\n", draft.Description)
+	draft.Summary, err = summaryPrompt.Run()
+	if err != nil {
+		if err == promptui.ErrInterrupt {
+			fmt.Println("\nOperation cancelled by user.")
+			return
+		}
+		printError("Error getting ticket details:", err)
+		return
+	}
+
+	// The ticket system asks for what else it needs, such as Jira's project
+	// key and custom fields
+	created, ok := createTicket(ctx, system, systems.NewTicket{
+		Project:     strings.TrimSpace(projectKey),
+		Type:        issueType,
+		Summary:     strings.TrimSpace(draft.Summary),
+		Description: draft.Description,
+	})
+	if !ok {
+		return
+	}
+
+	linkPromotedWork(selected, created.Key)
+}
+
+// recentSideQuests returns the side quests among the given number of recent commits
+func recentSideQuests(cfg *config.Config, count int) ([]Commit, error) {
+	commits, err := getRecentCommits(count)
+	if err != nil {
+		return nil, err
+	}
+	return findSideQuests(commits, cfg.TicketPrefixes), nil
+}
+
+// describeCommit formats a commit as its short hash and subject
+func describeCommit(commit Commit) string {
+	return fmt.Sprintf("%s %s", shortHash(commit.Hash), commit.Message)
+}
+
+// draftSideQuestTicket drafts a ticket for side quest commits, using the LLM
+// when one is configured and falling back to the commit messages otherwise
+func draftSideQuestTicket(cfg *config.Config, commits []Commit) ticketDraft {
+	fallback := commitTicketDraft(commits)
+	if !llm.IsConfigured(cfg) {
+		return fallback
+	}
+
+	printInfo("Drafting ticket...")
+	response, err := llm.NewGenerator(cfg).Generate(sideQuestPrompt(commits))
+	if err != nil {
+		fmt.Println("Could not draft the ticket with the LLM, using the commit messages:", err)
+		return fallback
+	}
+	draft, ok := parseTicketDraft(response)
+	if !ok {
+		fmt.Println("Could not understand the LLM's draft, using the commit messages.")
+		return fallback
+	}
+	return draft
+}
+
+// sideQuestPrompt builds the LLM prompt for drafting a ticket from commits
+func sideQuestPrompt(commits []Commit) string {
+	var b strings.Builder
+	b.WriteString("Write a ticket describing the work done in these git commits, which were made without a ticket.\n")
+	b.WriteString("Reply with a line starting with \"Summary:\" holding a one-line summary of at most 80 characters, ")
+	b.WriteString("followed by a line \"Description:\" and a short description of what was changed and why.\n\n")
+
+	backend := getGitBackend()
+	for _, commit := range commits {
+		fmt.Fprintf(&b, "Commit %s\n%s\n", commit.Hash, commit.FullMessage())
+		if churn, err := backend.DiffStats(".", commit.Hash+"^", commit.Hash); err == nil && len(churn) > 0 {
+			b.WriteString("Files changed:\n")
+			for _, file := range churn {
+				fmt.Fprintf(&b, "  %s (+%d/-%d)\n", file.Path, file.Added, file.Removed)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseTicketDraft reads the summary and description from an LLM response
+func parseTicketDraft(response string) (ticketDraft, bool) {
+	var draft ticketDraft
+	var description []string
+	inDescription := false
+	for _, line := range strings.Split(response, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case draft.Summary == "" && strings.HasPrefix(trimmed, "Summary:"):
+			draft.Summary = strings.TrimSpace(strings.TrimPrefix(trimmed, "Summary:"))
+		case !inDescription && strings.HasPrefix(trimmed, "Description:"):
+			inDescription = true
+			if rest := strings.TrimSpace(strings.TrimPrefix(trimmed, "Description:")); rest != "" {
+				description = append(description, rest)
+			}
+		case inDescription:
+			description = append(description, line)
+		}
+	}
+	draft.Description = strings.TrimSpace(strings.Join(description, "\n"))
+	return draft, draft.Summary != ""
+}
+
+// commitTicketDraft drafts a ticket from the commit messages alone
+func commitTicketDraft(commits []Commit) ticketDraft {
+	var lines []string
+	for _, commit := range commits {
+		lines = append(lines, "- "+commit.Message)
+	}
+	return ticketDraft{
+		Summary:     commits[0].Message,
+		Description: "Work done in these commits:\n" + strings.Join(lines, "\n"),
+	}
+}
+
+// linkPromotedWork offers to link the tracked work recorded for promoted
+// commits to the new ticket
+func linkPromotedWork(commits []Commit, ticketID string) {
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return
+	}
+
+	var matches []TrackedWork
+	for _, work := range trackedWork {
+		for _, commit := range commits {
+			if work.Context.CommitHash == commit.Hash && !work.HasTicket(ticketID) {
+				matches = append(matches, work)
+				break
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Link %d tracked work entries to %s", len(matches), ticketID),
+		IsConfirm: true,
+	}
+	if _, err := prompt.Run(); err != nil {
+		return
+	}
+
+	for _, work := range matches {
+		work.TicketIDs = append(work.TicketIDs, ticketID)
+		if err := saveTrackedWork(work); err != nil {
+			printError("Error linking tracked work:", err)
+			return
+		}
+	}
+	fmt.Printf("Linked %d tracked work entries to %s\n", len(matches), ticketID)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTicketDraft(t *testing.T) {
+	response := `Here is a draft:
+
+Summary: Speed up the CSV export
+Description:
+Streams rows to the writer instead of building the file in memory.
+
+Also drops an unused helper.`
+
+	draft, ok := parseTicketDraft(response)
+	if !ok {
+		t.Fatal("Expected the draft to be parsed")
+	}
+	if draft.Summary != "Speed up the CSV export" {
+		t.Errorf("Unexpected summary %q", draft.Summary)
+	}
+	if !strings.HasPrefix(draft.Description, "Streams rows") || !strings.HasSuffix(draft.Description, "unused helper.") {
+		t.Errorf("Unexpected description %q", draft.Description)
+	}
+
+	if _, ok := parseTicketDraft("I can't help with that."); ok {
+		t.Error("Expected a response without a summary to be rejected")
+	}
+}
+
+func TestCommitTicketDraft(t *testing.T) {
+	draft := commitTicketDraft([]Commit{
+		{Hash: "a1", Message: "Fix typo in README"},
+		{Hash: "b2", Message: "Fix broken link"},
+	})
+	if draft.Summary != "Fix typo in README" {
+		t.Errorf("Expected the first commit as summary, got %q", draft.Summary)
+	}
+	if !strings.Contains(draft.Description, "- Fix broken link") {
+		t.Errorf("Expected every commit in the description, got %q", draft.Description)
+	}
+}
@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/spf13/cobra"
+)
+
+// smartCommitsFile records the smart-commit directives already replayed
+const smartCommitsFile = "smartcommits.json"
+
+var (
+	// smartCommitKeyPattern matches the Jira issue keys directives apply to
+	smartCommitKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+	// smartCommitCommandPattern matches a #command
+	smartCommitCommandPattern = regexp.MustCompile(`(?:^|\s)#([A-Za-z][\w-]*)`)
+	// smartCommitTimePattern matches a unit of time spent, such as 1h or 30m
+	smartCommitTimePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[wdhm]$`)
+)
+
+// smartCommitAction is a smart-commit directive for one ticket
+type smartCommitAction struct {
+	TicketID string
+	Command  string // "comment", "time" or "transition"
+	Argument string // The comment, the time spent, or the transition
+	Comment  string // The worklog comment of a time directive
+}
+
+// smartCommitReplay is a directive of a commit, identified by the commit's
+// author time and subject and the directive's position in it, so rebasing
+// the commit doesn't replay it again
+type smartCommitReplay struct {
+	ID     string
+	Commit Commit
+	Action smartCommitAction
+}
+
+// jiraSmartCommitsCmd represents the jira smart-commits command
+var jiraSmartCommitsCmd = &cobra.Command{
+	Use:   "smart-commits",
+	Short: "Replay smart-commit directives from commit messages",
+	Long: `Replay the Jira smart-commit directives in the messages of your recent
+commits in this repository, such as
+
+  PROJ-123 #time 30m #comment Fixed the flaky test #transition Done
+
+#comment adds a comment, #time logs work (w, d, h and m units, followed by
+an optional worklog comment), and #transition, or any other #command such
+as #in-review, moves the ticket through that transition. Directives apply
+to every issue key before them on the same line. Each directive is only
+replayed once.
+
+Set smart_commits to "replay" in your configuration to replay them during
+'plannet status' and 'plannet auto-track', or to "dry-run" to only show them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		since, _ := cmd.Flags().GetString("since")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		runJiraSmartCommits(cmd.Context(), since, dryRun)
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraSmartCommitsCmd)
+	jiraSmartCommitsCmd.Flags().String("since", "midnight", "Replay commits since a date, a date and time, or a duration ago")
+	jiraSmartCommitsCmd.Flags().Bool("dry-run", false, "Show the directives without replaying them")
+}
+
+// runJiraSmartCommits replays the smart-commit directives of recent commits
+func runJiraSmartCommits(ctx context.Context, since string, dryRun bool) {
+	log := logger.WithContext(ctx)
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Check if Jira integration is configured
+	if cfg.JiraURL == "" || cfg.JiraUser == "" {
+		log.Error("Jira integration is not configured")
+		setExitCode(exitConfig)
+		log.Info("Run 'plannet init' to set up Jira integration.")
+		return
+	}
+	if cfg.JiraToken == "" {
+		printError("Error: Jira token not found. Please run 'plannet init' to set up Jira integration.")
+		setExitCode(exitConfig)
+		return
+	}
+
+	if !isGitRepo(".") {
+		log.Error("Not in a git repository")
+		return
+	}
+	commits, err := getCommitsSince(".", since, commitFilterFor(cfg, false, nil))
+	if err != nil {
+		log.Error("Failed to get commits: %v", err)
+		return
+	}
+
+	if err := replaySmartCommits(ctx, cfg, commits, dryRun); err != nil {
+		log.Error("%v", err)
+	}
+}
+
+// autoReplaySmartCommits replays the directives of commits as the
+// smart_commits setting asks, when Jira is configured
+func autoReplaySmartCommits(cfg *config.Config, commits []Commit) {
+	if cfg.SmartCommits == "" || cfg.JiraURL == "" || cfg.JiraToken == "" {
+		return
+	}
+	if err := replaySmartCommits(context.Background(), cfg, commits, cfg.SmartCommits == config.SmartCommitsDryRun); err != nil {
+		printError("Error replaying smart commits:", err)
+	}
+}
+
+// replaySmartCommits replays the directives of the commits that haven't been
+// replayed yet, or only lists them for a dry run
+func replaySmartCommits(ctx context.Context, cfg *config.Config, commits []Commit, dryRun bool) error {
+	replayed, err := readReplayedSmartCommits()
+	if err != nil {
+		return err
+	}
+	pending := pendingSmartCommits(commits, replayed)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if dryRun {
+		fmt.Println("Smart-commit directives to replay (dry run):")
+		for _, replay := range pending {
+			fmt.Println("  " + describeSmartCommit(replay))
+		}
+		return nil
+	}
+
+	client := newJiraClient(cfg, cfg.JiraToken)
+	failed := 0
+	for _, replay := range pending {
+		if err := applySmartCommit(ctx, cfg, client, replay); err != nil {
+			fmt.Printf("  Failed: %s: %v\n", describeSmartCommit(replay), err)
+			failed++
+			continue
+		}
+		fmt.Println("  Replayed: " + describeSmartCommit(replay))
+		replayed[replay.ID] = time.Now()
+	}
+	if err := writeReplayedSmartCommits(replayed); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d smart-commit directive(s) failed and will be retried", failed)
+	}
+	return nil
+}
+
+// pendingSmartCommits returns the directives of the commits that aren't
+// among those replayed, oldest commit first
+func pendingSmartCommits(commits []Commit, replayed map[string]time.Time) []smartCommitReplay {
+	var pending []smartCommitReplay
+	for i := len(commits) - 1; i >= 0; i-- {
+		for j, action := range parseSmartCommit(commits[i].FullMessage()) {
+			id := fmt.Sprintf("%s:%d", commitKey(commits[i]), j)
+			if _, ok := replayed[id]; ok {
+				continue
+			}
+			// Directives used to be recorded by commit hash
+			if _, ok := replayed[fmt.Sprintf("%s:%d", commits[i].Hash, j)]; ok {
+				continue
+			}
+			pending = append(pending, smartCommitReplay{ID: id, Commit: commits[i], Action: action})
+		}
+	}
+	return pending
+}
+
+// parseSmartCommit returns the smart-commit directives in a commit message.
+// A directive applies to every issue key before the first directive on its line.
+func parseSmartCommit(message string) []smartCommitAction {
+	var actions []smartCommitAction
+	for _, line := range strings.Split(message, "\n") {
+		commands := smartCommitCommandPattern.FindAllStringSubmatchIndex(line, -1)
+		if len(commands) == 0 {
+			continue
+		}
+		keys := smartCommitKeyPattern.FindAllString(line[:commands[0][0]], -1)
+		if len(keys) == 0 {
+			continue
+		}
+
+		for i, command := range commands {
+			end := len(line)
+			if i+1 < len(commands) {
+				end = commands[i+1][0]
+			}
+			name := strings.ToLower(line[command[2]:command[3]])
+			argument := strings.TrimSpace(line[command[1]:end])
+
+			var parsed []smartCommitAction
+			switch name {
+			case "comment":
+				if argument != "" {
+					parsed = append(parsed, smartCommitAction{Command: "comment", Argument: argument})
+				}
+			case "time":
+				if spent, comment := parseSmartCommitTime(argument); spent != "" {
+					parsed = append(parsed, smartCommitAction{Command: "time", Argument: spent, Comment: comment})
+				}
+			case "transition":
+				if argument != "" {
+					parsed = append(parsed, smartCommitAction{Command: "transition", Argument: argument})
+				}
+			default:
+				// Like Jira, any other command is a transition, and its text a comment
+				parsed = append(parsed, smartCommitAction{Command: "transition", Argument: strings.ReplaceAll(name, "-", " ")})
+				if argument != "" {
+					parsed = append(parsed, smartCommitAction{Command: "comment", Argument: argument})
+				}
+			}
+
+			for _, key := range keys {
+				for _, action := range parsed {
+					action.TicketID = key
+					actions = append(actions, action)
+				}
+			}
+		}
+	}
+	return actions
+}
+
+// parseSmartCommitTime splits the argument of #time into the time spent,
+// such as "1h 30m", and the worklog comment that follows it
+func parseSmartCommitTime(argument string) (string, string) {
+	words := strings.Fields(argument)
+	n := 0
+	for n < len(words) && smartCommitTimePattern.MatchString(words[n]) {
+		n++
+	}
+	return strings.Join(words[:n], " "), strings.Join(words[n:], " ")
+}
+
+// applySmartCommit replays a directive against the Jira API
+func applySmartCommit(ctx context.Context, cfg *config.Config, client *jiraClient, replay smartCommitReplay) error {
+	action := replay.Action
+	switch action.Command {
+	case "comment":
+		return addJiraComment(ctx, cfg, cfg.JiraToken, action.TicketID, action.Argument)
+	case "time":
+		worklog := map[string]interface{}{
+			"timeSpent": action.Argument,
+			"started":   replay.Commit.Time.Format(jiraTimeLayout),
+		}
+		if action.Comment != "" {
+			worklog["comment"] = client.richText(action.Comment)
+		}
+		return client.do(ctx, "POST", client.api("/issue/"+action.TicketID+"/worklog"), worklog, nil)
+	case "transition":
+		_, err := transitionJiraTicket(ctx, client, action.TicketID, action.Argument)
+		return err
+	}
+	return fmt.Errorf("unknown smart-commit command %q", action.Command)
+}
+
+// describeSmartCommit describes a directive and the commit it comes from
+func describeSmartCommit(replay smartCommitReplay) string {
+	action := replay.Action
+	text := fmt.Sprintf("%s #%s %s", action.TicketID, action.Command, action.Argument)
+	if action.Comment != "" {
+		text += " " + action.Comment
+	}
+	return fmt.Sprintf("%s  (%s %s)", text, shortHash(replay.Commit.Hash), replay.Commit.Message)
+}
+
+// getSmartCommitsPath returns the path of the record of replayed directives
+func getSmartCommitsPath() (string, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, smartCommitsFile), nil
+}
+
+// readReplayedSmartCommits returns when each replayed directive was replayed,
+// by ID. A missing file means none were.
+func readReplayedSmartCommits() (map[string]time.Time, error) {
+	path, err := getSmartCommitsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]time.Time{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", smartCommitsFile, err)
+	}
+
+	replayed := make(map[string]time.Time)
+	if err := json.Unmarshal(data, &replayed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", smartCommitsFile, err)
+	}
+	return replayed, nil
+}
+
+// writeReplayedSmartCommits saves the record of replayed directives
+func writeReplayedSmartCommits(replayed map[string]time.Time) error {
+	path, err := getSmartCommitsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create plannet directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(replayed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal replayed smart commits: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", smartCommitsFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestParseSmartCommit(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []smartCommitAction
+	}{
+		{
+			"Every command",
+			"PROJ-123 #time 1h 30m Debugging #comment fixed flaky test #transition In Review",
+			[]smartCommitAction{
+				{TicketID: "PROJ-123", Command: "time", Argument: "1h 30m", Comment: "Debugging"},
+				{TicketID: "PROJ-123", Command: "comment", Argument: "fixed flaky test"},
+				{TicketID: "PROJ-123", Command: "transition", Argument: "In Review"},
+			},
+		},
+		{
+			"Several keys",
+			"PROJ-1 PROJ-2 #comment shared fix",
+			[]smartCommitAction{
+				{TicketID: "PROJ-1", Command: "comment", Argument: "shared fix"},
+				{TicketID: "PROJ-2", Command: "comment", Argument: "shared fix"},
+			},
+		},
+		{
+			"Transition command with comment",
+			"PROJ-7 #resolve-issue Works now",
+			[]smartCommitAction{
+				{TicketID: "PROJ-7", Command: "transition", Argument: "resolve issue"},
+				{TicketID: "PROJ-7", Command: "comment", Argument: "Works now"},
+			},
+		},
+		{
+			"Directives in the body",
+			"Fix login\n\nPROJ-4 #done\nSee #42",
+			[]smartCommitAction{
+				{TicketID: "PROJ-4", Command: "transition", Argument: "done"},
+			},
+		},
+		{"No key", "Fix the build #comment oops", nil},
+		{"No directive", "PROJ-1 Fix the build (#12)", nil},
+		{"Time without units", "PROJ-1 #time soon", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSmartCommit(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSmartCommit(%q) = %+v, want %+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaySmartCommits(t *testing.T) {
+	setupTest(t)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/PROJ-1/comment":
+			var body struct {
+				Body string `json:"body"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, "comment "+body.Body)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/PROJ-1/worklog":
+			var body struct {
+				TimeSpent string `json:"timeSpent"`
+				Started   string `json:"started"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, "worklog "+body.TimeSpent+" "+body.Started)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "GET" && r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			w.Write([]byte(`{"transitions": [{"id": "31", "name": "Finish", "to": {"name": "Done"}}]}`))
+		case r.Method == "POST" && r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			requests = append(requests, "transition "+body.Transition.ID)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{JiraURL: server.URL, JiraAPIVersion: 2, JiraToken: "test-token"}
+	commitTime := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	commits := []Commit{{
+		Hash:       "abc1234def",
+		Message:    "PROJ-1 #time 30m #comment fixed flaky test #transition Done",
+		Time:       commitTime,
+		AuthorTime: commitTime,
+	}}
+
+	// A dry run replays nothing
+	if err := replaySmartCommits(context.Background(), cfg, commits, true); err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("Dry run sent requests %v", requests)
+	}
+
+	if err := replaySmartCommits(context.Background(), cfg, commits, false); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	want := []string{
+		"worklog 30m " + commitTime.Format(jiraTimeLayout),
+		"comment fixed flaky test",
+		"transition 31",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests = %v, want %v", requests, want)
+	}
+
+	// Directives already replayed are not replayed again
+	if err := replaySmartCommits(context.Background(), cfg, commits, false); err != nil {
+		t.Fatalf("Second replay failed: %v", err)
+	}
+	if len(requests) != len(want) {
+		t.Errorf("Directives were replayed twice: %v", requests)
+	}
+
+	// Nor are they when the commit is rebased, which changes its hash
+	rebased := []Commit{commits[0]}
+	rebased[0].Hash = "fed4321cba"
+	rebased[0].Time = commitTime.Add(time.Hour)
+	if err := replaySmartCommits(context.Background(), cfg, rebased, false); err != nil {
+		t.Fatalf("Replay after a rebase failed: %v", err)
+	}
+	if len(requests) != len(want) {
+		t.Errorf("Directives of a rebased commit were replayed again: %v", requests)
+	}
+}
+
+func TestPendingSmartCommitsLegacyIDs(t *testing.T) {
+	commit := Commit{
+		Hash:       "abc1234def",
+		Message:    "PROJ-1 #comment fixed",
+		AuthorTime: time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+	}
+	// Directives recorded by hash before they were keyed on the author time
+	replayed := map[string]time.Time{"abc1234def:0": time.Now()}
+	if pending := pendingSmartCommits([]Commit{commit}, replayed); len(pending) != 0 {
+		t.Errorf("Expected directives recorded by hash to stay replayed, got %+v", pending)
+	}
+}
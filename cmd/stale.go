@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// defaultStaleActiveHours is how long work can stay active before it is
+// treated as forgotten, unless configured otherwise
+const defaultStaleActiveHours = 12
+
+// staleWindow returns how long work can stay active before it is treated as
+// forgotten, or zero if the check is disabled
+func staleWindow(cfg *config.Config) time.Duration {
+	switch {
+	case cfg.StaleActiveHours < 0:
+		return 0
+	case cfg.StaleActiveHours == 0:
+		return defaultStaleActiveHours * time.Hour
+	default:
+		return time.Duration(cfg.StaleActiveHours) * time.Hour
+	}
+}
+
+// checkStaleWork completes active work that was left running for longer than
+// the stale window. Interactive users are asked when the work really ended;
+// otherwise it is completed at the last sign of activity.
+func checkStaleWork(cmd *cobra.Command) {
+	if backgroundCheckSkipped[cmd.Name()] {
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	window := staleWindow(cfg)
+	if window == 0 {
+		return
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil || activeWork == nil {
+		return
+	}
+
+	now := time.Now()
+	since := lastActivity(*activeWork)
+	if now.Sub(since) < window {
+		return
+	}
+
+	heartbeat, _ := readHeartbeat()
+	suggested := suggestedEndTime(*activeWork, heartbeat, lastCommitTime(), now)
+
+	end := suggested
+	if isInteractive() {
+		var ok bool
+		end, ok = promptStaleEndTime(*activeWork, suggested, now)
+		if !ok {
+			return
+		}
+	}
+
+	completeWork(activeWork, end)
+	if err := saveTrackedWork(*activeWork); err != nil {
+		printError("Error completing stale work:", err)
+		return
+	}
+	fmt.Printf("Completed %s, which was left active since %s, at %s (%s).\n",
+		activeWork.Description, since.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"), formatDuration(activeWork.Duration()))
+}
+
+// suggestedEndTime returns the last sign of activity on stale work, never
+// earlier than when the work was last started and never in the future
+func suggestedEndTime(work TrackedWork, heartbeat, lastCommit, now time.Time) time.Time {
+	end := lastActiveTime(work, heartbeat, lastCommit)
+	if end.After(now) {
+		return now
+	}
+	return end
+}
+
+// promptStaleEndTime asks when stale work really ended. It reports false if
+// the work should be left running.
+func promptStaleEndTime(work TrackedWork, suggested, now time.Time) (time.Time, bool) {
+	fmt.Printf("%s has been active since %s.\n", work.Description, lastActivity(work).Format("2006-01-02 15:04"))
+
+	prompt := promptui.Select{
+		Label: "Did you forget to complete it?",
+		Items: []string{
+			fmt.Sprintf("Complete it at %s (last activity)", suggested.Format("2006-01-02 15:04")),
+			"Complete it at another time",
+			"Complete it now",
+			"Keep it running",
+		},
+	}
+	index, _, err := prompt.Run()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch index {
+	case 0:
+		return suggested, true
+	case 1:
+		input := promptui.Prompt{
+			Label:   "End time (HH:MM or YYYY-MM-DD HH:MM)",
+			Default: suggested.Format(timeInputLayout),
+			Validate: func(s string) error {
+				end, err := parseClockInput(s, now)
+				if err != nil {
+					return err
+				}
+				if !end.After(work.StartTime) || end.After(now) {
+					return fmt.Errorf("end time must be between the start time and now")
+				}
+				return nil
+			},
+		}
+		result, err := input.Run()
+		if err != nil {
+			return time.Time{}, false
+		}
+		end, _ := parseClockInput(result, now)
+		return end, true
+	case 2:
+		return now, true
+	default:
+		return time.Time{}, false
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestCheckStaleWorkCompletesForgottenWork(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+
+	// Run outside any repository so commits don't count as activity
+	originalDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	defer os.Chdir(originalDir)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	work := TrackedWork{ID: "test-1", Description: "Forgotten", StartTime: now.Add(-20 * time.Hour), Status: "active"}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+	lastSeen := now.Add(-18 * time.Hour)
+	if err := recordHeartbeat(lastSeen); err != nil {
+		t.Fatalf("Failed to record heartbeat: %v", err)
+	}
+
+	// Stdin is not a terminal under go test, so the work is completed without asking
+	if !isInteractive() {
+		checkStaleWork(nowCmd)
+
+		trackedWork, err := getTrackedWork()
+		if err != nil {
+			t.Fatalf("Failed to get tracked work: %v", err)
+		}
+		if len(trackedWork) != 1 || trackedWork[0].Status != "completed" {
+			t.Fatalf("Expected stale work to be completed, got %+v", trackedWork)
+		}
+		if !trackedWork[0].EndTime.Equal(lastSeen) {
+			t.Errorf("Expected work to end at the last activity %v, got %v", lastSeen, trackedWork[0].EndTime)
+		}
+	}
+}
+
+func TestStaleWindow(t *testing.T) {
+	tests := []struct {
+		hours int
+		want  time.Duration
+	}{
+		{0, 12 * time.Hour},
+		{4, 4 * time.Hour},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := staleWindow(&config.Config{StaleActiveHours: tt.hours}); got != tt.want {
+			t.Errorf("Expected %v for %d hours, got %v", tt.want, tt.hours, got)
+		}
+	}
+}
+
+func TestSuggestedEndTime(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	work := TrackedWork{StartTime: now.Add(-20 * time.Hour), Status: "active"}
+
+	if got := suggestedEndTime(work, time.Time{}, now.Add(-15*time.Hour), now); !got.Equal(now.Add(-15 * time.Hour)) {
+		t.Errorf("Expected the last commit to be suggested, got %v", got)
+	}
+	if got := suggestedEndTime(work, time.Time{}, time.Time{}, now); !got.Equal(work.StartTime) {
+		t.Errorf("Expected the start time without any activity, got %v", got)
+	}
+	if got := suggestedEndTime(work, now.Add(time.Hour), time.Time{}, now); !got.Equal(now) {
+		t.Errorf("Expected the suggestion never to be in the future, got %v", got)
+	}
+}
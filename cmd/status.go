@@ -3,34 +3,78 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/output"
 	"github.com/spf13/cobra"
 )
 
+// repoDiscoveryDepth is how many directories deep repository roots are searched
+const repoDiscoveryDepth = 3
+
 // statusCmd represents the status command
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show a timeline overview of your work",
 	Long: `Show a timeline overview of your work based on your git activity.
 This command looks at your recent commits and organizes them by time blocks
-to give you a clear picture of what you've been working on.`,
+to give you a clear picture of what you've been working on.
+Today's commits are shown by default. Use --week for the current week, or
+--since for any other range (a date, a date and time, or a duration such as
+72h); ranges covering several days are broken down per day, with each day's
+totals and the ticket most of its commits were for.
+Use --all to combine the commits from several repositories into one
+timeline: every repository listed in repositories, every repository found
+under repository_roots, and, when work is scoped per repository, every
+project you have tracked work in.
+Only the checked out branch is scanned unless --all-branches (or
+scan_all_branches) is set, and --author (or author_emails) limits the
+timeline to your own commits. A commit found on several branches or in
+several clones is shown once.
+Use --format to print each time block, or each day, with a Go template,
+such as '{{.StartTime.Format "15:04"}} {{.Focus}}'. Days have the Date,
+Blocks, Commits, Tickets and TicketID of the day's main ticket.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runStatus()
+		all, _ := cmd.Flags().GetBool("all")
+		allBranches, _ := cmd.Flags().GetBool("all-branches")
+		authors, _ := cmd.Flags().GetStringSlice("author")
+		since, _ := cmd.Flags().GetString("since")
+		if week, _ := cmd.Flags().GetBool("week"); week {
+			since = startOfWeek(time.Now()).Format(dateInputLayout)
+		}
+		runStatus(all, allBranches, authors, since)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(statusCmd)
+	supportJSONOutput(statusCmd)
+	statusCmd.Flags().BoolP("all", "a", false, "Show a combined timeline of every repository")
+	statusCmd.Flags().Bool("all-branches", false, "Include commits on every local branch")
+	statusCmd.Flags().StringSlice("author", nil, "Only include commits by this author email (can be repeated)")
+	statusCmd.Flags().Bool("week", false, "Show the current week, day by day")
+	statusCmd.Flags().String("since", "midnight", "Show commits since a date, a date and time, or a duration ago")
+	statusCmd.MarkFlagsMutuallyExclusive("week", "since")
+	addFormatFlag(statusCmd, "Print each time block or day with a Go template, such as '{{.Focus}}'")
+}
+
+// statusRepoCommits are the commits of one repository shown in the timeline
+type statusRepoCommits struct {
+	Repo    string // Empty when only the current repository is shown
+	Dir     string
+	Commits []Commit
 }
 
-func runStatus() {
+func runStatus(all, allBranches bool, authors []string, since string) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		fmt.Println("Error loading configuration:", err)
-		fmt.Println("Run 'plannet init' to set up your configuration.")
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
 		return
 	}
 
@@ -40,10 +84,23 @@ func runStatus() {
 		return
 	}
 
+	now := time.Now()
+	sinceTime, err := parseSince(since, now)
+	if err != nil {
+		printError("Error:", err)
+		return
+	}
+
+	filter := commitFilterFor(cfg, allBranches, authors)
+	if all {
+		runStatusAll(cfg, filter, since, sinceTime)
+		return
+	}
+
 	// Get current directory
 	currentDir, err := os.Getwd()
 	if err != nil {
-		fmt.Println("Error getting current directory:", err)
+		printError("Error getting current directory:", err)
 		return
 	}
 
@@ -53,25 +110,385 @@ func runStatus() {
 		return
 	}
 
-	// Get commits from today
-	commits, err := getCommitsSince(currentDir, "midnight")
+	commits, err := getCommitsSince(currentDir, since, filter)
 	if err != nil {
-		fmt.Println("Error getting commits:", err)
+		printError("Error getting commits:", err)
+		return
+	}
+
+	if jsonOutput() || templateOutput() {
+		result := statusOutput{Since: sinceTime}
+		if len(commits) > 0 && startOfDay(sinceTime).Before(startOfDay(now)) {
+			result.Days = buildStatusDays([]statusRepoCommits{{Dir: currentDir, Commits: commits}}, cfg.TicketPrefixes)
+		} else if len(commits) > 0 {
+			result.Blocks = groupCommitsByTimeBlock(currentDir, commits)
+		}
+		if jsonOutput() {
+			printJSON(result)
+		} else if err := printStatusTemplate(result); err != nil {
+			fprintError(os.Stderr, "Error:", err)
+		}
+		toStderr(func() { autoReplaySmartCommits(cfg, commits) })
 		return
 	}
 
 	if len(commits) == 0 {
-		fmt.Println("No commits found today.")
+		fmt.Printf("No commits found %s.\n", describeStatusRange(sinceTime, now))
+		setExitCode(exitNotFound)
+		return
+	}
+
+	if startOfDay(sinceTime).Before(startOfDay(now)) {
+		printStatusDays(buildStatusDays([]statusRepoCommits{{Dir: currentDir, Commits: commits}}, cfg.TicketPrefixes))
+	} else {
+		fmt.Println(statusMapTitle(sinceTime))
+		printTimeline(groupCommitsByTimeBlock(currentDir, commits))
+	}
+	autoReplaySmartCommits(cfg, commits)
+}
+
+// runStatusAll shows a single timeline of the commits across every repository
+func runStatusAll(cfg *config.Config, filter commitFilter, since string, sinceTime time.Time) {
+	repos, err := statusRepos(cfg)
+	if err != nil {
+		printError("Error listing repositories:", err)
+		return
+	}
+
+	var repoCommits []statusRepoCommits
+	// Clones of the same repository share commits, which are shown once
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		commits, err := getCommitsSince(repo.Path, since, filter)
+		if err != nil {
+			printErrorf("Error getting commits for %s: %v\n", repo.Name, err)
+			continue
+		}
+		commits = dedupeCommits(commits, seen)
+		if len(commits) == 0 {
+			continue
+		}
+		repoCommits = append(repoCommits, statusRepoCommits{Repo: repo.Name, Dir: repo.Path, Commits: commits})
+	}
+
+	now := time.Now()
+	if len(repoCommits) == 0 && !jsonOutput() && !templateOutput() {
+		fmt.Printf("No commits found %s in any repository.\n", describeStatusRange(sinceTime, now))
+		setExitCode(exitNotFound)
+		return
+	}
+
+	result := statusOutput{Since: sinceTime, Repositories: make(map[string]int)}
+	if startOfDay(sinceTime).Before(startOfDay(now)) {
+		result.Days = buildStatusDays(repoCommits, cfg.TicketPrefixes)
+	} else {
+		for _, rc := range repoCommits {
+			for _, block := range groupCommitsByTimeBlock(rc.Dir, rc.Commits) {
+				block.Repo = rc.Repo
+				result.Blocks = append(result.Blocks, block)
+			}
+		}
+		sort.SliceStable(result.Blocks, func(i, j int) bool {
+			return result.Blocks[i].StartTime.Before(result.Blocks[j].StartTime)
+		})
+	}
+	var active []string
+	for _, rc := range repoCommits {
+		if !containsString(active, rc.Repo) {
+			active = append(active, rc.Repo)
+		}
+		result.Repositories[rc.Repo] += len(rc.Commits)
+	}
+
+	replay := func() {
+		for _, rc := range repoCommits {
+			autoReplaySmartCommits(cfg, rc.Commits)
+		}
+	}
+	if jsonOutput() {
+		printJSON(result)
+		toStderr(replay)
+		return
+	}
+	if templateOutput() {
+		if err := printStatusTemplate(result); err != nil {
+			fprintError(os.Stderr, "Error:", err)
+		}
+		toStderr(replay)
 		return
 	}
 
-	// Group commits by time blocks
-	timeBlocks := groupCommitsByTimeBlock(commits)
+	if result.Days != nil {
+		printStatusDays(result.Days)
+	} else {
+		fmt.Println(statusMapTitle(sinceTime))
+		printTimeline(result.Blocks)
+	}
+	fmt.Println("\nBy repository:")
+	for _, name := range active {
+		fmt.Printf("  %s: %d commit(s)\n", name, result.Repositories[name])
+	}
+	replay()
+}
+
+// statusDay is one day of a timeline covering several days
+type statusDay struct {
+	Date    time.Time      `json:"date"`
+	Blocks  []TimeBlock    `json:"blocks"`
+	Commits int            `json:"commits"`
+	Tickets map[string]int `json:"tickets,omitempty"` // Number of commits referencing each ticket
+}
+
+// statusOutput is the timeline of 'plannet status' as --output json prints
+// it: Blocks for a timeline of part of today, and Days for one covering
+// several days
+type statusOutput struct {
+	Since  time.Time   `json:"since"`
+	Blocks []TimeBlock `json:"blocks,omitempty"`
+	Days   []statusDay `json:"days,omitempty"`
+	// Repositories is the number of commits in each repository, with --all
+	Repositories map[string]int `json:"repositories,omitempty"`
+}
+
+// TicketID returns the ticket most of the day's commits referenced, for
+// --format templates
+func (d statusDay) TicketID() string {
+	ticket, _ := d.DominantTicket()
+	return ticket
+}
+
+// printStatusTemplate prints each time block of a timeline with the
+// --format template, or each day of one covering several days
+func printStatusTemplate(result statusOutput) error {
+	if result.Days != nil {
+		for _, day := range result.Days {
+			if err := printFormatted(day); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, block := range result.Blocks {
+		if err := printFormatted(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DominantTicket returns the ticket most of the day's commits referenced,
+// and how many did, or "" if none referenced a ticket
+func (d statusDay) DominantTicket() (string, int) {
+	var ticket string
+	count := 0
+	for id, n := range d.Tickets {
+		// Ties go to the alphabetically first ticket so the output is stable
+		if n > count || (n == count && id < ticket) {
+			ticket, count = id, n
+		}
+	}
+	return ticket, count
+}
 
-	// Display timeline
-	fmt.Println("Today's map:")
+// buildStatusDays sorts commits into the days they were made on, oldest day
+// first, grouping each day's commits into time blocks
+func buildStatusDays(repoCommits []statusRepoCommits, prefixes []string) []statusDay {
+	byDate := make(map[time.Time]*statusDay)
+	for _, rc := range repoCommits {
+		// Commits are newest first, so each day's commits stay together
+		var dayCommits []Commit
+		flush := func() {
+			if len(dayCommits) == 0 {
+				return
+			}
+			date := startOfDay(dayCommits[0].Time)
+			day, ok := byDate[date]
+			if !ok {
+				day = &statusDay{Date: date, Tickets: make(map[string]int)}
+				byDate[date] = day
+			}
+			for _, block := range groupCommitsByTimeBlock(rc.Dir, dayCommits) {
+				block.Repo = rc.Repo
+				day.Blocks = append(day.Blocks, block)
+			}
+			for _, commit := range dayCommits {
+				if ticketID := extractTicketIDFromMessage(commit.FullMessage(), prefixes); ticketID != "" {
+					day.Tickets[ticketID]++
+				}
+			}
+			day.Commits += len(dayCommits)
+			dayCommits = nil
+		}
+		for _, commit := range rc.Commits {
+			if len(dayCommits) > 0 && !startOfDay(commit.Time).Equal(startOfDay(dayCommits[0].Time)) {
+				flush()
+			}
+			dayCommits = append(dayCommits, commit)
+		}
+		flush()
+	}
+
+	var days []statusDay
+	for _, day := range byDate {
+		sort.SliceStable(day.Blocks, func(i, j int) bool {
+			return day.Blocks[i].StartTime.Before(day.Blocks[j].StartTime)
+		})
+		days = append(days, *day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days
+}
+
+// printStatusDays displays a timeline day by day, with each day's totals
+func printStatusDays(days []statusDay) {
+	total := 0
+	for _, day := range days {
+		fmt.Printf("\n%s\n", output.Heading("=== "+day.Date.Format("Monday, 2006-01-02")+" ==="))
+		printTimeline(day.Blocks)
+
+		first, last := day.Blocks[0].StartTime, day.Blocks[0].EndTime
+		for _, block := range day.Blocks {
+			if block.EndTime.After(last) {
+				last = block.EndTime
+			}
+		}
+		fmt.Printf("\nTotal: %d commit(s) in %d block(s), %s - %s\n", day.Commits, len(day.Blocks), first.Format("15:04"), last.Format("15:04"))
+		if ticket, count := day.DominantTicket(); ticket != "" {
+			fmt.Printf("Main ticket: %s (%d of %d commits)\n", output.Ticket(ticket), count, day.Commits)
+		}
+		total += day.Commits
+	}
+	fmt.Printf("\n%d commit(s) over %d day(s)\n", total, len(days))
+}
+
+// statusMapTitle returns the heading of a timeline covering part of today
+func statusMapTitle(sinceTime time.Time) string {
+	if sinceTime.Equal(startOfDay(sinceTime)) {
+		return "Today's map:"
+	}
+	return fmt.Sprintf("Map since %s:", sinceTime.Format("15:04"))
+}
+
+// describeStatusRange describes the range of a timeline for messages
+func describeStatusRange(sinceTime, now time.Time) string {
+	if sinceTime.Equal(startOfDay(now)) {
+		return "today"
+	}
+	if startOfDay(sinceTime).Equal(startOfDay(now)) {
+		return "since " + sinceTime.Format("15:04")
+	}
+	return "since " + sinceTime.Format("2006-01-02 15:04")
+}
+
+// startOfDay returns midnight at the start of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns midnight at the start of the Monday of t's week
+func startOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return startOfDay(t).AddDate(0, 0, -daysSinceMonday)
+}
+
+// statusRepo is a git repository included in the combined timeline
+type statusRepo struct {
+	Name string
+	Path string
+}
+
+// statusRepos returns the configured repositories, the repositories found
+// under the configured roots and the projects with tracked work, without
+// duplicates
+func statusRepos(cfg *config.Config) ([]statusRepo, error) {
+	var paths []string
+	paths = append(paths, cfg.Repositories...)
+	for _, root := range cfg.RepositoryRoots {
+		found, err := discoverRepos(expandHome(root), repoDiscoveryDepth)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, found...)
+	}
+
+	dbs, err := listProjectDBs()
+	if err != nil {
+		return nil, err
+	}
+	for _, db := range dbs {
+		if db.Project.Path != "" {
+			paths = append(paths, db.Project.Path)
+		}
+	}
+
+	var repos []statusRepo
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		path = filepath.Clean(expandHome(path))
+		if seen[path] || !isGitRepo(path) {
+			continue
+		}
+		seen[path] = true
+		repos = append(repos, statusRepo{Name: filepath.Base(path), Path: path})
+	}
+	return repos, nil
+}
+
+// discoverRepos returns the git repositories under root, searching at most
+// depth directories deep. Hidden directories and the contents of
+// repositories are not searched.
+func discoverRepos(root string, depth int) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+		return []string{root}, nil
+	}
+	if depth == 0 {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+
+	var repos []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		found, err := discoverRepos(filepath.Join(root, entry.Name()), depth-1)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, found...)
+	}
+	return repos, nil
+}
+
+// expandHome replaces a leading ~ in a path with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// printTimeline displays time blocks of focused work
+func printTimeline(timeBlocks []TimeBlock) {
 	for _, block := range timeBlocks {
-		fmt.Printf("\n%s - %s\n", block.StartTime.Format("15:04"), block.EndTime.Format("15:04"))
+		if block.Repo != "" {
+			fmt.Printf("\n%s [%s]\n", output.Heading(block.StartTime.Format("15:04")+" - "+block.EndTime.Format("15:04")), block.Repo)
+		} else {
+			fmt.Printf("\n%s\n", output.Heading(block.StartTime.Format("15:04")+" - "+block.EndTime.Format("15:04")))
+		}
 		fmt.Printf("Focus: %s\n", block.Focus)
 		if len(block.Files) > 0 {
 			fmt.Println("Files changed:")
@@ -84,14 +501,15 @@ func runStatus() {
 
 // TimeBlock represents a period of focused work
 type TimeBlock struct {
-	StartTime time.Time
-	EndTime   time.Time
-	Focus     string
-	Files     []string
+	Repo      string    `json:"repo,omitempty"` // Set when the timeline combines several repositories
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Focus     string    `json:"focus"`
+	Files     []string  `json:"files,omitempty"`
 }
 
 // groupCommitsByTimeBlock groups commits into time blocks of focused work
-func groupCommitsByTimeBlock(commits []Commit) []TimeBlock {
+func groupCommitsByTimeBlock(dir string, commits []Commit) []TimeBlock {
 	if len(commits) == 0 {
 		return []TimeBlock{}
 	}
@@ -104,7 +522,7 @@ func groupCommitsByTimeBlock(commits []Commit) []TimeBlock {
 	}
 
 	// Get files changed in the first commit
-	if files, err := getFilesChanged(".", commits[0].Hash); err == nil {
+	if files, err := getFilesChanged(dir, commits[0].Hash); err == nil {
 		currentBlock.Files = files
 	}
 
@@ -118,7 +536,7 @@ func groupCommitsByTimeBlock(commits []Commit) []TimeBlock {
 			currentBlock.Focus = commit.Message
 
 			// Add files changed in this commit
-			if files, err := getFilesChanged(".", commit.Hash); err == nil {
+			if files, err := getFilesChanged(dir, commit.Hash); err == nil {
 				currentBlock.Files = append(currentBlock.Files, files...)
 			}
 		} else {
@@ -131,7 +549,7 @@ func groupCommitsByTimeBlock(commits []Commit) []TimeBlock {
 			}
 
 			// Get files changed in this commit
-			if files, err := getFilesChanged(".", commit.Hash); err == nil {
+			if files, err := getFilesChanged(dir, commit.Hash); err == nil {
 				currentBlock.Files = files
 			}
 		}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiscoverRepos(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{
+		"api/.git",
+		"clients/web/.git",
+		"clients/web/vendor/lib/.git", // Nested in a repository
+		".cache/tool/.git",            // Hidden
+		"a/b/c/deep/.git",             // Too deep
+		"notes",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	repos, err := discoverRepos(root, repoDiscoveryDepth)
+	if err != nil {
+		t.Fatalf("Failed to discover repositories: %v", err)
+	}
+	expected := []string{filepath.Join(root, "api"), filepath.Join(root, "clients", "web")}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Errorf("Expected %v, got %v", expected, repos)
+	}
+
+	if repos, err := discoverRepos(filepath.Join(root, "missing"), repoDiscoveryDepth); err != nil || len(repos) != 0 {
+		t.Errorf("Expected no repositories under a missing root, got %v (%v)", repos, err)
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	for _, day := range []int{11, 13, 17} { // Monday, Wednesday and Sunday
+		got := startOfWeek(time.Date(2024, 3, day, 15, 30, 0, 0, time.Local))
+		if want := time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+			t.Errorf("startOfWeek(2024-03-%d) = %v, want %v", day, got, want)
+		}
+	}
+}
+
+func TestBuildStatusDays(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, time.Local)
+	}
+	// Commits are newest first, as git log returns them
+	repoCommits := []statusRepoCommits{
+		{Repo: "api", Dir: t.TempDir(), Commits: []Commit{
+			{Hash: "e", Message: "PROJ-2 Fix typo", Time: at(12, 16, 0)},
+			{Hash: "d", Message: "Tidy up", Time: at(12, 9, 10)},
+			{Hash: "c", Message: "Add retries", Body: "Refs: PROJ-1", Time: at(11, 14, 20)},
+			{Hash: "b", Message: "PROJ-1 Add client", Time: at(11, 14, 0)},
+		}},
+		{Repo: "web", Dir: t.TempDir(), Commits: []Commit{
+			{Hash: "a", Message: "PROJ-2 Show errors", Time: at(11, 10, 0)},
+		}},
+	}
+
+	days := buildStatusDays(repoCommits, []string{"PROJ-"})
+	if len(days) != 2 {
+		t.Fatalf("Expected two days, got %d", len(days))
+	}
+
+	monday := days[0]
+	if !monday.Date.Equal(at(11, 0, 0)) || monday.Commits != 3 || len(monday.Blocks) != 2 {
+		t.Errorf("Unexpected first day %+v", monday)
+	}
+	if monday.Blocks[0].Repo != "web" || monday.Blocks[1].Repo != "api" {
+		t.Errorf("Expected the day's blocks in time order across repositories, got %+v", monday.Blocks)
+	}
+	if ticket, count := monday.DominantTicket(); ticket != "PROJ-1" || count != 2 {
+		t.Errorf("Expected PROJ-1 to dominate the first day, got %s (%d)", ticket, count)
+	}
+
+	tuesday := days[1]
+	if tuesday.Commits != 2 || len(tuesday.Blocks) != 2 {
+		t.Errorf("Unexpected second day %+v", tuesday)
+	}
+	if ticket, count := tuesday.DominantTicket(); ticket != "PROJ-2" || count != 1 {
+		t.Errorf("Expected PROJ-2 to dominate the second day, got %s (%d)", ticket, count)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// switchCmd represents the switch command
+var switchCmd = &cobra.Command{
+	Use:   "switch [description]",
+	Short: "Switch to new work in one step",
+	Long: `Switch from the active work to something new in one step.
+The active work is paused (or completed with --complete) and a new entry is
+started immediately. If git integration is enabled, the ticket ID is inferred
+from the branch you are on, so checking out a branch and running
+'plannet switch' is all it takes to follow a side quest.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		complete, _ := cmd.Flags().GetBool("complete")
+		ticketIDs, _ := cmd.Flags().GetStringSlice("ticket")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		estimate, _ := cmd.Flags().GetDuration("estimate")
+		billable, _ := cmd.Flags().GetBool("billable")
+		runSwitch(args, complete, ticketIDs, tags, estimate, billable)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(switchCmd)
+	switchCmd.Flags().BoolP("complete", "c", false, "Complete the active work instead of pausing it")
+	switchCmd.Flags().StringSliceP("ticket", "t", nil, "Ticket ID for the new work (can be repeated; inferred from the branch by default)")
+	switchCmd.Flags().StringSlice("tag", nil, "Tag for the new work (can be repeated)")
+	switchCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	switchCmd.Flags().Duration("estimate", 0, "How long you expect the new work to take (e.g. 2h)")
+	switchCmd.Flags().BoolP("billable", "b", false, "Mark the new work as billable")
+}
+
+func runSwitch(args []string, complete bool, ticketIDs []string, tags []string, estimate time.Duration, billable bool) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	// Get description from args or prompt
+	var description string
+	if len(args) > 0 {
+		description = strings.Join(args, " ")
+	} else {
+		prompt := promptui.Prompt{
+			Label: "What are you switching to?",
+			Validate: func(input string) error {
+				if strings.TrimSpace(input) == "" {
+					return fmt.Errorf("description cannot be empty")
+				}
+				return nil
+			},
+		}
+		result, err := prompt.Run()
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error getting work description:", err)
+			return
+		}
+		description = result
+	}
+
+	if len(ticketIDs) > 0 {
+		for _, ticketID := range ticketIDs {
+			if err := validateTicketID(ticketID); err != nil {
+				fmt.Println("Invalid ticket ID:", err)
+				setExitCode(exitUsage)
+				return
+			}
+		}
+	} else {
+		ticketID, err := inferTicketID(cfg)
+		if err != nil {
+			printError("Error inferring ticket ID:", err)
+			return
+		}
+		if ticketID != "" {
+			ticketIDs = []string{ticketID}
+		}
+	}
+
+	activeWork, err := getActiveWork()
+	if err != nil {
+		printError("Error getting active work:", err)
+		return
+	}
+
+	now := time.Now()
+	work := TrackedWork{
+		ID:          generateID(),
+		Description: description,
+		TicketIDs:   ticketIDs,
+		StartTime:   now,
+		Tags:        tags,
+		Status:      "active",
+		Context:     getWorkContext(cfg),
+		// Estimates are kept to the minute
+		EstimateMinutes: int(estimate.Round(time.Minute).Minutes()),
+		Billable:        billable,
+	}
+
+	// Set the active work aside, keeping a copy so it can be restored
+	if activeWork != nil {
+		previous := *activeWork
+		if complete {
+			completeWork(activeWork, now)
+		} else {
+			pauseWork(activeWork, now)
+		}
+		if err := saveTrackedWork(*activeWork); err != nil {
+			printError("Error updating active work:", err)
+			return
+		}
+
+		if err := saveTrackedWork(work); err != nil {
+			printError("Error starting new work:", err)
+			if err := restoreSwitchedWork(previous); err != nil {
+				printError("Error restoring previous work:", err)
+			}
+			return
+		}
+
+		if complete {
+			fmt.Printf("Completed: %s (%s)\n", activeWork.Description, formatDuration(activeWork.Duration()))
+		} else {
+			fmt.Printf("Paused: %s (%s so far)\n", activeWork.Description, formatDuration(activeWork.Duration()))
+		}
+	} else if err := saveTrackedWork(work); err != nil {
+		printError("Error starting new work:", err)
+		return
+	}
+
+	fmt.Printf("Now working on: %s\n", work.Description)
+	fmt.Printf("ID: %s\n", work.ID)
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(work.TicketIDs, ", "))
+	}
+	if len(work.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(work.Tags, ", "))
+	}
+	if work.Context.Branch != "" {
+		fmt.Printf("Branch: %s\n", work.Context.Branch)
+	}
+	if work.EstimateMinutes > 0 {
+		fmt.Printf("Estimate: %s\n", formatDuration(work.Estimate()))
+	}
+	if work.Billable {
+		fmt.Println("Billable: yes")
+	}
+}
+
+// restoreSwitchedWork puts the previously active work back after a failed switch
+func restoreSwitchedWork(previous TrackedWork) error {
+	if err := deleteTrackedWork(previous.ID); err != nil {
+		return err
+	}
+	return saveTrackedWork(previous)
+}
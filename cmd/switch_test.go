@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunSwitch(t *testing.T) {
+	tests := []struct {
+		name     string
+		complete bool
+		want     string
+	}{
+		{"pauses the active work", false, "paused"},
+		{"completes the active work", true, "completed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cleanup := setupTest(t)
+			defer cleanup()
+			resetExitCode(t)
+
+			previous := TrackedWork{ID: "work-1", Description: "Fix login", TicketIDs: []string{"JIRA-1"}, StartTime: time.Now().Add(-time.Hour), Status: "active"}
+			if err := saveTrackedWork(previous); err != nil {
+				t.Fatal(err)
+			}
+
+			captureStdout(t, func() { runSwitch([]string{"Review", "PR"}, tt.complete, []string{"JIRA-2"}, nil, 0, false) })
+
+			active, err := getActiveWork()
+			if err != nil || active == nil {
+				t.Fatalf("Expected new active work, got %v, %v", active, err)
+			}
+			if active.Description != "Review PR" || !active.HasTicket("JIRA-2") {
+				t.Errorf("Unexpected active work %+v", active)
+			}
+
+			all, err := getTrackedWork()
+			if err != nil {
+				t.Fatal(err)
+			}
+			switched := findWorkByID(all, "work-1")
+			if switched == nil || switched.Status != tt.want {
+				t.Fatalf("Expected the previous work to be %s, got %+v", tt.want, switched)
+			}
+			if tt.complete && switched.EndTime.IsZero() {
+				t.Error("Expected the completed work to have an end time")
+			}
+		})
+	}
+}
+
+func TestRestoreSwitchedWork(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	previous := TrackedWork{ID: "work-1", Description: "Fix login", StartTime: time.Now().Add(-time.Hour), Status: "active"}
+	paused := previous
+	pauseWork(&paused, time.Now())
+	if err := saveTrackedWork(paused); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restoreSwitchedWork(previous); err != nil {
+		t.Fatalf("Failed to restore switched work: %v", err)
+	}
+
+	active, err := getActiveWork()
+	if err != nil || active == nil || active.ID != "work-1" || len(active.Pauses) != 0 {
+		t.Fatalf("Expected the previous work to be active again without its pause, got %+v, %v", active, err)
+	}
+	pausedWork, err := getPausedWork()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findWorkByID(pausedWork, "work-1") != nil {
+		t.Error("Expected the previous work to no longer be paused")
+	}
+}
@@ -0,0 +1,456 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// syncBranch is the branch tracked work is synced on
+	syncBranch = "main"
+	// syncRemoteName is the name of the git remote used for syncing
+	syncRemoteName = "origin"
+	// syncRemoteRef is the local ref of the synced remote branch
+	syncRemoteRef = "refs/remotes/" + syncRemoteName + "/" + syncBranch
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync tracked work with other devices",
+	Long: `Sync tracked work with other devices through a git remote.
+The database directory is kept in a git repository which is pushed to and
+pulled from the remote set with --remote (stored as sync_remote in your
+configuration). When two devices have both changed tracked work, the changes
+are merged entry by entry using the work IDs:
+
+- Work added on either device is kept
+- Work deleted on one device and left unchanged on the other is deleted
+- Work changed on both devices keeps the most progressed version
+  (completed over paused over active), then the most recently changed one
+- If both devices have active work, the older one is paused`,
+	Run: func(cmd *cobra.Command, args []string) {
+		remote, _ := cmd.Flags().GetString("remote")
+		runSync(remote)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().String("remote", "", "Git remote URL to sync with (saved to your configuration)")
+}
+
+func runSync(remote string) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if remote != "" && remote != cfg.SyncRemote {
+		cfg.SyncRemote = remote
+		if err := config.Save(cfg); err != nil {
+			printError("Error saving configuration:", err)
+			return
+		}
+	}
+	if cfg.SyncRemote == "" {
+		fmt.Println("No sync remote configured.")
+		setExitCode(exitConfig)
+		printInfo("Run 'plannet sync --remote <git-url>' to set one up.")
+		return
+	}
+
+	dbDir, err := openDB()
+	if err != nil {
+		printError("Error opening database:", err)
+		return
+	}
+
+	result, err := syncDB(dbDir, cfg.SyncRemote)
+	if err != nil {
+		printError("Error syncing tracked work:", err)
+		return
+	}
+	fmt.Println(result)
+	notifyDesktop(notifySync, "Sync complete", result)
+}
+
+// syncDB commits local changes to the database, merges in the remote
+// changes and pushes the result. It returns a short summary of what happened.
+func syncDB(dbDir, remote string) (string, error) {
+	if err := ensureSyncRepo(dbDir, remote); err != nil {
+		return "", err
+	}
+
+	host, _ := os.Hostname()
+	if _, err := commitDBChanges(dbDir, fmt.Sprintf("Update tracked work on %s", host)); err != nil {
+		return "", err
+	}
+
+	if _, err := dbGit(dbDir, "fetch", syncRemoteName); err != nil {
+		return "", fmt.Errorf("failed to fetch from %s: %w", remote, err)
+	}
+
+	hasRemote := dbGitOK(dbDir, "rev-parse", "--verify", "--quiet", syncRemoteRef)
+	hasLocal := dbGitOK(dbDir, "rev-parse", "--verify", "--quiet", "HEAD")
+
+	summary := "Tracked work is up to date."
+	switch {
+	case !hasRemote && !hasLocal:
+		return "Nothing to sync yet.", nil
+	case !hasRemote:
+		summary = "Pushed tracked work to the remote."
+	default:
+		if err := checkRemoteSchema(dbDir); err != nil {
+			return "", err
+		}
+
+		switch {
+		case !hasLocal:
+			if _, err := dbGit(dbDir, "reset", "--hard", syncRemoteRef); err != nil {
+				return "", err
+			}
+			summary = "Pulled tracked work from the remote."
+		case dbGitOK(dbDir, "merge-base", "--is-ancestor", syncRemoteRef, "HEAD"):
+			if dbGitOK(dbDir, "merge-base", "--is-ancestor", "HEAD", syncRemoteRef) {
+				return summary, nil
+			}
+			summary = "Pushed tracked work to the remote."
+		case dbGitOK(dbDir, "merge-base", "--is-ancestor", "HEAD", syncRemoteRef):
+			if _, err := dbGit(dbDir, "merge", "--ff-only", syncRemoteRef); err != nil {
+				return "", err
+			}
+			return "Pulled tracked work from the remote.", nil
+		default:
+			if err := mergeRemoteWork(dbDir); err != nil {
+				return "", err
+			}
+			summary = "Merged tracked work with the remote."
+		}
+	}
+
+	if _, err := dbGit(dbDir, "push", syncRemoteName, "HEAD:"+syncBranch); err != nil {
+		return "", fmt.Errorf("failed to push to %s (run 'plannet sync' again if another device synced in the meantime): %w", remote, err)
+	}
+	return summary, nil
+}
+
+// ensureSyncRepo makes sure the database directory is a git repository
+// pointing at the given remote
+func ensureSyncRepo(dbDir, remote string) error {
+	if _, err := os.Stat(filepath.Join(dbDir, ".git")); os.IsNotExist(err) {
+		if _, err := dbGit(dbDir, "init"); err != nil {
+			return fmt.Errorf("failed to initialize sync repository: %w", err)
+		}
+		if _, err := dbGit(dbDir, "symbolic-ref", "HEAD", "refs/heads/"+syncBranch); err != nil {
+			return err
+		}
+		// Temporary files from interrupted writes are never synced
+		if err := os.WriteFile(filepath.Join(dbDir, ".gitignore"), []byte("*.tmp\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write .gitignore: %w", err)
+		}
+	}
+
+	current, err := dbGit(dbDir, "remote", "get-url", syncRemoteName)
+	switch {
+	case err != nil:
+		_, err = dbGit(dbDir, "remote", "add", syncRemoteName, remote)
+	case current != remote:
+		_, err = dbGit(dbDir, "remote", "set-url", syncRemoteName, remote)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to configure sync remote: %w", err)
+	}
+	return nil
+}
+
+// commitDBChanges commits any changes in the database directory.
+// It reports whether a commit was made.
+func commitDBChanges(dbDir, message string) (bool, error) {
+	if _, err := dbGit(dbDir, "add", "-A"); err != nil {
+		return false, err
+	}
+	status, err := dbGit(dbDir, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	if status == "" {
+		return false, nil
+	}
+	if _, err := dbGit(dbDir, "commit", "-m", message); err != nil {
+		return false, fmt.Errorf("failed to commit tracked work: %w", err)
+	}
+	return true, nil
+}
+
+// checkRemoteSchema makes sure the remote database uses the same schema version
+func checkRemoteSchema(dbDir string) error {
+	data, err := dbGit(dbDir, "show", syncRemoteRef+":"+schemaFile)
+	if err != nil {
+		// Databases synced before versioning have no schema file
+		return nil
+	}
+
+	var info schemaInfo
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		return fmt.Errorf("failed to parse remote %s: %w", schemaFile, err)
+	}
+	switch {
+	case info.Version > currentSchemaVersion:
+		return fmt.Errorf("remote database schema version %d is newer than this version of plannet supports (%d); please upgrade plannet", info.Version, currentSchemaVersion)
+	case info.Version < currentSchemaVersion:
+		return fmt.Errorf("remote database schema version %d is out of date; run 'plannet migrate' on the other device and sync it first", info.Version)
+	}
+	return nil
+}
+
+// mergeRemoteWork merges diverged local and remote databases entry by entry
+// and records the result as a merge commit
+func mergeRemoteWork(dbDir string) error {
+	base := map[string]TrackedWork{}
+	if mergeBase, err := dbGit(dbDir, "merge-base", "HEAD", syncRemoteRef); err == nil {
+		if base, err = readWorkAt(dbDir, mergeBase); err != nil {
+			return err
+		}
+	}
+	local, err := readWorkAt(dbDir, "HEAD")
+	if err != nil {
+		return err
+	}
+	remote, err := readWorkAt(dbDir, syncRemoteRef)
+	if err != nil {
+		return err
+	}
+
+	merged := mergeWorkSets(base, local, remote)
+
+	// Record the merge but keep our files; they are replaced with the merged work below
+	if _, err := dbGit(dbDir, "merge", "-s", "ours", "--no-commit", "--allow-unrelated-histories", syncRemoteRef); err != nil {
+		return fmt.Errorf("failed to merge remote changes: %w", err)
+	}
+	if err := writeDBWork(dbDir, merged); err != nil {
+		dbGit(dbDir, "merge", "--abort")
+		return err
+	}
+	if _, err := dbGit(dbDir, "add", "-A"); err != nil {
+		return err
+	}
+	if _, err := dbGit(dbDir, "commit", "-m", "Merge tracked work from "+syncRemoteName); err != nil {
+		return fmt.Errorf("failed to commit merged work: %w", err)
+	}
+	return nil
+}
+
+// readWorkAt reads all tracked work stored at a git revision, keyed by ID
+func readWorkAt(dbDir, rev string) (map[string]TrackedWork, error) {
+	names, err := dbGit(dbDir, "ls-tree", "--name-only", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	work := map[string]TrackedWork{}
+	for _, name := range strings.Split(names, "\n") {
+		var records []TrackedWork
+		switch name {
+		case "active.json":
+			data, err := dbGit(dbDir, "show", rev+":"+name)
+			if err != nil {
+				return nil, err
+			}
+			var active TrackedWork
+			if err := json.Unmarshal([]byte(data), &active); err != nil {
+				return nil, fmt.Errorf("failed to parse %s at %s: %w", name, rev, err)
+			}
+			records = []TrackedWork{active}
+		case "paused.json", "completed.json":
+			data, err := dbGit(dbDir, "show", rev+":"+name)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal([]byte(data), &records); err != nil {
+				return nil, fmt.Errorf("failed to parse %s at %s: %w", name, rev, err)
+			}
+		}
+		for _, w := range records {
+			work[w.ID] = w
+		}
+	}
+	return work, nil
+}
+
+// mergeWorkSets performs a three-way merge of tracked work keyed by ID
+func mergeWorkSets(base, local, remote map[string]TrackedWork) []TrackedWork {
+	var merged []TrackedWork
+	for id, l := range local {
+		r, inRemote := remote[id]
+		b, inBase := base[id]
+		switch {
+		case !inRemote:
+			// Deleted remotely; keep it only if it changed locally
+			if !inBase || !sameWork(l, b) {
+				merged = append(merged, l)
+			}
+		case sameWork(l, r):
+			merged = append(merged, l)
+		case inBase && sameWork(l, b):
+			merged = append(merged, r)
+		case inBase && sameWork(r, b):
+			merged = append(merged, l)
+		default:
+			merged = append(merged, resolveWorkConflict(l, r))
+		}
+	}
+	for id, r := range remote {
+		if _, inLocal := local[id]; inLocal {
+			continue
+		}
+		// Deleted locally; keep it only if it changed remotely
+		if b, inBase := base[id]; !inBase || !sameWork(r, b) {
+			merged = append(merged, r)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if !merged[i].StartTime.Equal(merged[j].StartTime) {
+			return merged[i].StartTime.Before(merged[j].StartTime)
+		}
+		return merged[i].ID < merged[j].ID
+	})
+
+	// Only one piece of work can be active; pause the others from the
+	// moment the most recent one became active
+	var current *TrackedWork
+	for i := range merged {
+		if merged[i].Status != "active" {
+			continue
+		}
+		if current == nil || lastActivity(merged[i]).After(lastActivity(*current)) {
+			current = &merged[i]
+		}
+	}
+	for i := range merged {
+		if merged[i].Status == "active" && &merged[i] != current {
+			pauseWork(&merged[i], lastActivity(*current))
+		}
+	}
+
+	return merged
+}
+
+// resolveWorkConflict picks between two versions of work changed on both sides.
+// The most progressed version wins, then the most recently changed one.
+func resolveWorkConflict(local, remote TrackedWork) TrackedWork {
+	rank := map[string]int{"active": 0, "paused": 1, "completed": 2}
+	if rank[local.Status] != rank[remote.Status] {
+		if rank[remote.Status] > rank[local.Status] {
+			return remote
+		}
+		return local
+	}
+	if lastActivity(remote).After(lastActivity(local)) {
+		return remote
+	}
+	return local
+}
+
+// lastActivity returns the most recent time recorded on a piece of work
+func lastActivity(w TrackedWork) time.Time {
+	latest := w.StartTime
+	later := func(t time.Time) {
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	later(w.EndTime)
+	for _, p := range w.Pauses {
+		later(p.Start)
+		later(p.End)
+	}
+	return latest
+}
+
+// sameWork reports whether two versions of work are identical
+func sameWork(a, b TrackedWork) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+// writeDBWork replaces the database files with the given work
+func writeDBWork(dbDir string, work []TrackedWork) error {
+	paused := []TrackedWork{}
+	completed := []TrackedWork{}
+	var active *TrackedWork
+	for i := range work {
+		switch work[i].Status {
+		case "active":
+			active = &work[i]
+		case "paused":
+			paused = append(paused, work[i])
+		case "completed":
+			completed = append(completed, work[i])
+		default:
+			return fmt.Errorf("unknown work status: %s", work[i].Status)
+		}
+	}
+
+	activeFile := filepath.Join(dbDir, "active.json")
+	if active != nil {
+		data, err := json.MarshalIndent(active, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal work data: %w", err)
+		}
+		if err := os.WriteFile(activeFile, data, 0644); err != nil {
+			return fmt.Errorf("failed to write active work file: %w", err)
+		}
+	} else if err := os.Remove(activeFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove active work file: %w", err)
+	}
+
+	if err := writeWorkFile(filepath.Join(dbDir, "paused.json"), paused); err != nil {
+		return err
+	}
+	return writeWorkFile(filepath.Join(dbDir, "completed.json"), completed)
+}
+
+// dbGit runs a git command in the database directory and returns its trimmed output.
+// Commits are attributed to plannet so syncing works without a git identity.
+func dbGit(dbDir string, args ...string) (string, error) {
+	host, _ := os.Hostname()
+	gitArgs := append([]string{
+		"-C", dbDir,
+		"-c", "user.name=plannet",
+		"-c", fmt.Sprintf("user.email=plannet@%s", host),
+	}, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", gitArgs...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// dbGitOK reports whether a git command in the database directory succeeds
+func dbGitOK(dbDir string, args ...string) bool {
+	_, err := dbGit(dbDir, args...)
+	return err == nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMergeWorkSets(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	work := func(id, status string, started time.Time) TrackedWork {
+		return TrackedWork{ID: id, Description: id, StartTime: started, Status: status}
+	}
+	completed := func(w TrackedWork, at time.Time) TrackedWork {
+		w.Status = "completed"
+		w.EndTime = at
+		return w
+	}
+
+	shared := work("shared", "paused", start)
+	base := map[string]TrackedWork{
+		"shared":         shared,
+		"deleted-remote": work("deleted-remote", "completed", start),
+		"edited-local":   work("edited-local", "completed", start),
+	}
+
+	editedLocal := base["edited-local"]
+	editedLocal.Description = "edited locally"
+
+	local := map[string]TrackedWork{
+		"shared":         completed(shared, start.Add(time.Hour)),
+		"deleted-remote": base["deleted-remote"],
+		"edited-local":   editedLocal,
+		"local-active":   work("local-active", "active", start.Add(2*time.Hour)),
+	}
+	remote := map[string]TrackedWork{
+		"shared":        work("shared", "active", start),
+		"edited-local":  base["edited-local"],
+		"remote-active": work("remote-active", "active", start.Add(3*time.Hour)),
+	}
+
+	merged := mergeWorkSets(base, local, remote)
+
+	byID := map[string]TrackedWork{}
+	for _, w := range merged {
+		byID[w.ID] = w
+	}
+
+	tests := []struct {
+		id     string
+		status string
+	}{
+		{"shared", "completed"},
+		{"edited-local", "completed"},
+		{"local-active", "paused"},
+		{"remote-active", "active"},
+	}
+	for _, tt := range tests {
+		w, ok := byID[tt.id]
+		if !ok {
+			t.Errorf("Expected %s to be kept", tt.id)
+			continue
+		}
+		if w.Status != tt.status {
+			t.Errorf("Expected %s to be %s, got %s", tt.id, tt.status, w.Status)
+		}
+	}
+
+	if _, ok := byID["deleted-remote"]; ok {
+		t.Error("Expected work deleted on the remote to stay deleted")
+	}
+	if byID["edited-local"].Description != "edited locally" {
+		t.Errorf("Expected local edit to be kept, got %q", byID["edited-local"].Description)
+	}
+	if paused := byID["local-active"]; len(paused.Pauses) != 1 || !paused.Pauses[0].Start.Equal(start.Add(3*time.Hour)) {
+		t.Errorf("Expected local-active to be paused when remote-active started, got %v", paused.Pauses)
+	}
+}
+
+func TestSyncBetweenDevices(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tempDir := t.TempDir()
+	remote := filepath.Join(tempDir, "remote.git")
+	if err := exec.Command("git", "init", "--bare", remote).Run(); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	laptop := filepath.Join(tempDir, "laptop")
+	desktop := filepath.Join(tempDir, "desktop")
+	devices := map[string]TrackedWork{
+		laptop:  {ID: "laptop-1", Description: "Laptop work", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		desktop: {ID: "desktop-1", Description: "Desktop work", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+	}
+	for dir, w := range devices {
+		if err := writeSchemaVersion(dir, currentSchemaVersion); err != nil {
+			t.Fatalf("Failed to create database: %v", err)
+		}
+		if err := writeDBWork(dir, []TrackedWork{w}); err != nil {
+			t.Fatalf("Failed to write work: %v", err)
+		}
+	}
+
+	// Both devices sync once, then the laptop picks up the desktop's work
+	for _, dir := range []string{laptop, desktop, laptop} {
+		if _, err := syncDB(dir, remote); err != nil {
+			t.Fatalf("Failed to sync %s: %v", filepath.Base(dir), err)
+		}
+	}
+
+	for _, dir := range []string{laptop, desktop} {
+		completed, err := readWorkFile(filepath.Join(dir, "completed.json"))
+		if err != nil {
+			t.Fatalf("Failed to read completed work: %v", err)
+		}
+		if len(completed) != 2 {
+			t.Errorf("Expected 2 completed items on %s, got %d", filepath.Base(dir), len(completed))
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// workColumn is a column of the table of tracked work that --columns selects
+type workColumn struct {
+	name   string
+	shrink bool                     // Truncated to fit the terminal
+	style  func(text string) string // Styles the cells, if set
+	value  func(work TrackedWork) string
+}
+
+// workColumns are the columns the table of tracked work can show
+var workColumns = []workColumn{
+	{"id", false, nil, func(work TrackedWork) string { return work.ID }},
+	{"description", true, nil, func(work TrackedWork) string { return work.Description }},
+	{"ticket", false, output.Ticket, func(work TrackedWork) string { return strings.Join(work.TicketIDs, ", ") }},
+	{"duration", false, nil, func(work TrackedWork) string { return formatDuration(work.Duration()) }},
+	{"status", false, nil, func(work TrackedWork) string { return work.Status }},
+	{"start", false, nil, func(work TrackedWork) string { return work.StartTime.Format("2006-01-02 15:04") }},
+	{"end", false, nil, func(work TrackedWork) string {
+		if work.EndTime.IsZero() {
+			return ""
+		}
+		return work.EndTime.Format("2006-01-02 15:04")
+	}},
+	{"project", false, nil, func(work TrackedWork) string { return work.Project }},
+	{"tags", true, nil, func(work TrackedWork) string { return strings.Join(work.Tags, ", ") }},
+	{"estimate", false, nil, func(work TrackedWork) string {
+		if work.EstimateMinutes == 0 {
+			return ""
+		}
+		return formatEstimate(work)
+	}},
+	{"billable", false, nil, func(work TrackedWork) string {
+		if work.Billable {
+			return "yes"
+		}
+		return ""
+	}},
+}
+
+// defaultWorkColumns are the columns shown without --columns
+var defaultWorkColumns = []string{"id", "description", "ticket", "duration", "status"}
+
+// ticketColumn is a column of the table of tickets that --columns selects
+type ticketColumn struct {
+	name   string
+	shrink bool                     // Truncated to fit the terminal
+	style  func(text string) string // Styles the cells, if set
+	value  func(ticket JiraTicket) string
+}
+
+// ticketColumns are the columns the table of tickets can show
+var ticketColumns = []ticketColumn{
+	{"key", false, output.Ticket, func(ticket JiraTicket) string { return ticket.Key }},
+	{"status", false, nil, func(ticket JiraTicket) string { return ticket.Status }},
+	{"summary", true, nil, func(ticket JiraTicket) string { return ticket.Summary }},
+	{"type", false, nil, func(ticket JiraTicket) string { return ticket.Type }},
+	{"priority", false, nil, func(ticket JiraTicket) string { return ticket.Priority }},
+	{"assignee", false, nil, func(ticket JiraTicket) string { return ticket.Assignee }},
+	{"url", false, nil, func(ticket JiraTicket) string { return ticket.URL }},
+}
+
+// defaultTicketColumns are the columns shown without --columns
+var defaultTicketColumns = []string{"key", "status", "summary"}
+
+// workTable returns a table of tracked work with the named columns, or the
+// default ones when names is empty
+func workTable(trackedWork []TrackedWork, names []string) (*output.Table, error) {
+	if len(names) == 0 {
+		names = defaultWorkColumns
+	}
+	var columns []workColumn
+	for _, name := range names {
+		column, ok := findWorkColumn(name)
+		if !ok {
+			return nil, unknownColumnError(name, workColumnNames())
+		}
+		columns = append(columns, column)
+	}
+
+	headings := make([]output.Column, len(columns))
+	for i, column := range columns {
+		headings[i] = output.Column{Heading: column.name, Shrink: column.shrink, Style: column.style}
+	}
+	table := output.NewTable(headings...)
+	for _, work := range trackedWork {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = column.value(work)
+		}
+		table.AddRow(cells...)
+	}
+	return table, nil
+}
+
+// ticketTable returns a table of tickets with the named columns, or the
+// default ones when names is empty
+func ticketTable(tickets []JiraTicket, names []string) (*output.Table, error) {
+	if len(names) == 0 {
+		names = defaultTicketColumns
+	}
+	var columns []ticketColumn
+	for _, name := range names {
+		column, ok := findTicketColumn(name)
+		if !ok {
+			return nil, unknownColumnError(name, ticketColumnNames())
+		}
+		columns = append(columns, column)
+	}
+
+	headings := make([]output.Column, len(columns))
+	for i, column := range columns {
+		headings[i] = output.Column{Heading: column.name, Shrink: column.shrink, Style: column.style}
+	}
+	table := output.NewTable(headings...)
+	for _, ticket := range tickets {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = column.value(ticket)
+		}
+		table.AddRow(cells...)
+	}
+	return table, nil
+}
+
+// findWorkColumn returns the column of the table of tracked work with a name
+func findWorkColumn(name string) (workColumn, bool) {
+	for _, column := range workColumns {
+		if column.name == strings.ToLower(strings.TrimSpace(name)) {
+			return column, true
+		}
+	}
+	return workColumn{}, false
+}
+
+// findTicketColumn returns the column of the table of tickets with a name
+func findTicketColumn(name string) (ticketColumn, bool) {
+	for _, column := range ticketColumns {
+		if column.name == strings.ToLower(strings.TrimSpace(name)) {
+			return column, true
+		}
+	}
+	return ticketColumn{}, false
+}
+
+// workColumnNames returns the names of the columns of the table of tracked work
+func workColumnNames() []string {
+	names := make([]string, len(workColumns))
+	for i, column := range workColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// ticketColumnNames returns the names of the columns of the table of tickets
+func ticketColumnNames() []string {
+	names := make([]string, len(ticketColumns))
+	for i, column := range ticketColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// unknownColumnError reports a column --columns can't select
+func unknownColumnError(name string, names []string) error {
+	return fmt.Errorf("unknown column %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// completeWorkColumns completes the columns of the table of tracked work
+func completeWorkColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return workColumnNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTicketColumns completes the columns of the table of tickets
+func completeTicketColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return ticketColumnNames(), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWorkTable(t *testing.T) {
+	start := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{ID: "tw-2", Description: "Review the release notes", Status: "active", StartTime: start.Add(2 * time.Hour)},
+		{ID: "tw-1", Description: "Fix login timeout", TicketIDs: []string{"PROJ-1", "PROJ-2"}, Status: "completed",
+			StartTime: start, EndTime: start.Add(90 * time.Minute), Billable: true},
+	}
+
+	table, err := workTable(trackedWork, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	table.RenderPlain(&out)
+	rows := out.String()
+	if want := "tw-1\tFix login timeout\tPROJ-1, PROJ-2\t1h 30m\tcompleted\n"; !bytes.Contains(out.Bytes(), []byte(want)) {
+		t.Errorf("default columns = %q, want a row %q", rows, want)
+	}
+
+	table, err = workTable(trackedWork, []string{"ID", "start", "end", "billable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	table.RenderPlain(&out)
+	want := "tw-2\t2024-05-06 11:00\t\t\ntw-1\t2024-05-06 09:00\t2024-05-06 10:30\tyes\n"
+	if out.String() != want {
+		t.Errorf("chosen columns = %q, want %q", out.String(), want)
+	}
+
+	if _, err := workTable(trackedWork, []string{"id", "colour"}); err == nil {
+		t.Error("workTable() with an unknown column succeeded")
+	}
+}
+
+func TestTicketTable(t *testing.T) {
+	tickets := []JiraTicket{{Key: "PROJ-1", Summary: "Fix login timeout", Status: "In Progress", Priority: "High"}}
+
+	table, err := ticketTable(tickets, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	table.Render(&out, 0)
+	want := "KEY     STATUS       SUMMARY\nPROJ-1  In Progress  Fix login timeout\n"
+	if out.String() != want {
+		t.Errorf("default columns =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	table, err = ticketTable(tickets, []string{"key", "priority"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	table.RenderPlain(&out)
+	if want := "PROJ-1\tHigh\n"; out.String() != want {
+		t.Errorf("chosen columns = %q, want %q", out.String(), want)
+	}
+
+	if _, err := ticketTable(nil, []string{"sprint"}); err == nil {
+		t.Error("ticketTable() with an unknown column succeeded")
+	}
+}
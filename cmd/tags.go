@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// tagCount is the number of tracked work entries using a tag
+type tagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage tags on tracked work",
+	Long: `Manage the tags used on tracked work. List the tags in use, rename a
+tag everywhere it is used, or merge tags that drifted apart into one.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagsList()
+	},
+}
+
+// tagsListCmd represents the tags list command
+var tagsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags in use",
+	Long:  `List every tag in use on tracked work, most used first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagsList()
+	},
+}
+
+// tagsRenameCmd represents the tags rename command
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag",
+	Long:  `Rename a tag on all tracked work. Use 'plannet tags merge' if the new name is already in use.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagsRename(args[0], args[1])
+	},
+}
+
+// tagsMergeCmd represents the tags merge command
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge <tag>... <into>",
+	Short: "Merge tags into one",
+	Long: `Merge one or more tags into another on all tracked work.
+For example, 'plannet tags merge bugfix bug-fix bug' replaces bugfix and
+bug-fix with bug.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTagsMerge(args[:len(args)-1], args[len(args)-1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsListCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+	tagsCmd.AddCommand(tagsMergeCmd)
+	supportJSONOutput(tagsCmd)
+	supportJSONOutput(tagsListCmd)
+}
+
+func runTagsList() {
+	trackedWork, ok := loadWorkForTags()
+	if !ok {
+		return
+	}
+
+	counts := countTags(trackedWork)
+	if jsonOutput() {
+		if counts == nil {
+			counts = []tagCount{}
+		}
+		printJSON(counts)
+		return
+	}
+	if len(counts) == 0 {
+		fmt.Println("No tags found.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	fmt.Println("Tags:")
+	for _, c := range counts {
+		fmt.Printf("  %-20s %d\n", c.Tag, c.Count)
+	}
+}
+
+func runTagsRename(oldTag, newTag string) {
+	trackedWork, ok := loadWorkForTags()
+	if !ok {
+		return
+	}
+
+	newTag = strings.TrimSpace(newTag)
+	if newTag == "" {
+		fmt.Println("The new tag cannot be empty.")
+		return
+	}
+	for _, c := range countTags(trackedWork) {
+		if c.Tag == newTag {
+			fmt.Printf("Tag %q is already in use. Use 'plannet tags merge %s %s' to combine them.\n", newTag, oldTag, newTag)
+			return
+		}
+	}
+
+	changed := retagWork(trackedWork, []string{oldTag}, newTag)
+	if len(changed) == 0 {
+		fmt.Printf("No tracked work is tagged %q.\n", oldTag)
+		setExitCode(exitNotFound)
+		return
+	}
+	if err := saveRetaggedWork(changed); err != nil {
+		printError("Error saving tracked work:", err)
+		return
+	}
+
+	fmt.Printf("Renamed %q to %q on %d tracked work item(s).\n", oldTag, newTag, len(changed))
+}
+
+func runTagsMerge(sources []string, target string) {
+	trackedWork, ok := loadWorkForTags()
+	if !ok {
+		return
+	}
+
+	target = strings.TrimSpace(target)
+	if target == "" {
+		fmt.Println("The tag to merge into cannot be empty.")
+		return
+	}
+
+	changed := retagWork(trackedWork, sources, target)
+	if len(changed) == 0 {
+		fmt.Printf("No tracked work is tagged %s.\n", strings.Join(sources, ", "))
+		setExitCode(exitNotFound)
+		return
+	}
+	if err := saveRetaggedWork(changed); err != nil {
+		printError("Error saving tracked work:", err)
+		return
+	}
+
+	fmt.Printf("Merged %s into %q on %d tracked work item(s).\n", strings.Join(sources, ", "), target, len(changed))
+}
+
+// loadWorkForTags loads all tracked work for a tags command, printing any problem
+func loadWorkForTags() ([]TrackedWork, bool) {
+	// Load configuration
+	if _, err := config.Load(); err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return nil, false
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return nil, false
+	}
+	return trackedWork, true
+}
+
+// countTags counts how often each tag is used, most used first
+func countTags(trackedWork []TrackedWork) []tagCount {
+	counts := map[string]int{}
+	for _, work := range trackedWork {
+		for _, tag := range work.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]tagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, tagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// retagWork replaces the source tags with the target tag and returns the
+// work that changed. Tags are never duplicated on a single entry.
+func retagWork(trackedWork []TrackedWork, sources []string, target string) []TrackedWork {
+	var changed []TrackedWork
+	for _, work := range trackedWork {
+		var tags []string
+		modified := false
+		for _, tag := range work.Tags {
+			if containsString(sources, tag) {
+				tag = target
+				modified = true
+			}
+			if !containsString(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if modified {
+			work.Tags = tags
+			changed = append(changed, work)
+		}
+	}
+	return changed
+}
+
+// saveRetaggedWork saves work whose tags were changed
+func saveRetaggedWork(changed []TrackedWork) error {
+	for _, work := range changed {
+		if err := saveTrackedWork(work); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// existingTags returns the tags in use, most used first
+func existingTags() []string {
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, c := range countTags(trackedWork) {
+		tags = append(tags, c.Tag)
+	}
+	return tags
+}
+
+// completeTags completes tag flags in shell completion
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return existingTags(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// tagCompleter completes tags from the tags already in use
+type tagCompleter []string
+
+// Do implements readline.AutoCompleter
+func (c tagCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	prefix := line[:pos]
+	var candidates [][]rune
+	for _, tag := range c {
+		runes := []rune(tag)
+		if len(runes) >= len(prefix) && strings.EqualFold(string(runes[:len(prefix)]), string(prefix)) {
+			candidates = append(candidates, runes[len(prefix):])
+		}
+	}
+	return candidates, len(prefix)
+}
+
+// promptTags asks for tags one at a time, with Tab completing tags already in use
+func promptTags(known []string) ([]string, error) {
+	label := "Add a tag (leave empty to finish): "
+	if len(known) > 0 {
+		label = "Add a tag (Tab to complete, leave empty to finish): "
+	}
+
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:       label,
+		AutoComplete: tagCompleter(known),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rl.Close()
+
+	var tags []string
+	for {
+		result, err := rl.Readline()
+		if err == readline.ErrInterrupt || err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tag := strings.TrimSpace(result)
+		if tag == "" {
+			break
+		}
+		if !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
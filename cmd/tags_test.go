@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRetagWork(t *testing.T) {
+	trackedWork := []TrackedWork{
+		{ID: "1", Tags: []string{"bugfix", "backend"}},
+		{ID: "2", Tags: []string{"bug-fix", "bug"}},
+		{ID: "3", Tags: []string{"docs"}},
+	}
+
+	changed := retagWork(trackedWork, []string{"bugfix", "bug-fix"}, "bug")
+	if len(changed) != 2 {
+		t.Fatalf("Expected 2 changed items, got %d", len(changed))
+	}
+	if want := []string{"bug", "backend"}; !reflect.DeepEqual(changed[0].Tags, want) {
+		t.Errorf("Expected %v, got %v", want, changed[0].Tags)
+	}
+	if want := []string{"bug"}; !reflect.DeepEqual(changed[1].Tags, want) {
+		t.Errorf("Expected merged tags not to be duplicated, got %v", changed[1].Tags)
+	}
+	if trackedWork[0].Tags[0] != "bugfix" {
+		t.Error("Expected the original work to be left untouched")
+	}
+}
+
+func TestCountTags(t *testing.T) {
+	trackedWork := []TrackedWork{
+		{Tags: []string{"meeting", "backend"}},
+		{Tags: []string{"backend"}},
+		{Tags: []string{"api"}},
+	}
+
+	want := []tagCount{{"backend", 2}, {"api", 1}, {"meeting", 1}}
+	if got := countTags(trackedWork); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestTagCompleter(t *testing.T) {
+	completer := tagCompleter{"backend", "Bug", "meeting"}
+
+	candidates, length := completer.Do([]rune("b"), 1)
+	if length != 1 {
+		t.Errorf("Expected completion to replace 1 character, got %d", length)
+	}
+
+	var got []string
+	for _, c := range candidates {
+		got = append(got, string(c))
+	}
+	if want := []string{"ackend", "ug"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
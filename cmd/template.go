@@ -0,0 +1,420 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// templatesFile holds the saved work templates
+const templatesFile = "templates.json"
+
+// WorkTemplate is a saved recipe for recurring work
+type WorkTemplate struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	TicketIDs       []string `json:"ticket_ids,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	DurationMinutes int      `json:"duration_minutes,omitempty"` // 0 starts the work as active
+	Billable        bool     `json:"billable,omitempty"`
+}
+
+// Duration returns the default length of work created from the template
+func (t WorkTemplate) Duration() time.Duration {
+	return time.Duration(t.DurationMinutes) * time.Minute
+}
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage templates for recurring work",
+	Long: `Save common entries such as a daily standup, sprint planning or on-call
+triage as templates, then track them with one command.
+
+Examples:
+  plannet template add standup "Daily standup" --tag meeting --duration 15m
+  plannet template apply standup
+  plannet template apply standup --from 09:30`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runTemplateList()
+	},
+}
+
+// templateAddCmd represents the template add command
+var templateAddCmd = &cobra.Command{
+	Use:   "add <name> [description]",
+	Short: "Save a template",
+	Long: `Save a template for recurring work. A template with the same name is replaced.
+Templates with a duration are tracked as completed work ending now (or
+starting at --from when applied); without one, the work is started as active.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ticketIDs, _ := cmd.Flags().GetStringSlice("ticket")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		billable, _ := cmd.Flags().GetBool("billable")
+		runTemplateAdd(args, ticketIDs, tags, duration, billable)
+	},
+}
+
+// templateApplyCmd represents the template apply command
+var templateApplyCmd = &cobra.Command{
+	Use:               "apply <name>",
+	Short:             "Track work from a template",
+	Long:              `Track work pre-filled with a template's description, tickets, tags and duration.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		runTemplateApply(args[0], from, duration)
+	},
+}
+
+// templateListCmd represents the template list command
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved templates",
+	Run: func(cmd *cobra.Command, args []string) {
+		runTemplateList()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateAddCmd)
+	templateCmd.AddCommand(templateApplyCmd)
+	templateCmd.AddCommand(templateListCmd)
+	supportJSONOutput(templateCmd)
+	supportJSONOutput(templateListCmd)
+
+	templateAddCmd.Flags().StringSliceP("ticket", "t", nil, "Ticket ID for the work (can be repeated)")
+	templateAddCmd.Flags().StringSlice("tag", nil, "Tag for the work (can be repeated)")
+	templateAddCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	templateAddCmd.Flags().Duration("duration", 0, "Default length of the work (e.g. 15m)")
+	templateAddCmd.Flags().BoolP("billable", "b", false, "Mark the work as billable")
+
+	templateApplyCmd.Flags().String("from", "", "When the work started (HH:MM or YYYY-MM-DD HH:MM)")
+	templateApplyCmd.Flags().Duration("duration", 0, "Override the template's duration")
+}
+
+func runTemplateAdd(args []string, ticketIDs, tags []string, duration time.Duration, billable bool) {
+	// Load configuration
+	_, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	name := strings.TrimSpace(args[0])
+	if name == "" {
+		fmt.Println("The template name cannot be empty.")
+		return
+	}
+
+	// Get description from args or prompt
+	var description string
+	if len(args) > 1 {
+		description = strings.Join(args[1:], " ")
+	} else {
+		prompt := promptui.Prompt{
+			Label: "Description",
+			Validate: func(input string) error {
+				if strings.TrimSpace(input) == "" {
+					return fmt.Errorf("description cannot be empty")
+				}
+				return nil
+			},
+		}
+		result, err := prompt.Run()
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error getting work description:", err)
+			return
+		}
+		description = result
+	}
+
+	for _, ticketID := range ticketIDs {
+		if err := validateTicketID(ticketID); err != nil {
+			fmt.Println("Invalid ticket ID:", err)
+			setExitCode(exitUsage)
+			return
+		}
+	}
+	if duration < 0 {
+		fmt.Println("The duration cannot be negative.")
+		return
+	}
+
+	templates, err := readTemplates()
+	if err != nil {
+		printError("Error reading templates:", err)
+		return
+	}
+
+	template := WorkTemplate{
+		Name:        name,
+		Description: description,
+		TicketIDs:   ticketIDs,
+		Tags:        tags,
+		// Durations are kept to the minute
+		DurationMinutes: int(duration.Round(time.Minute).Minutes()),
+		Billable:        billable,
+	}
+	replaced := findTemplate(templates, name) != nil
+	templates = upsertTemplate(templates, template)
+	if err := writeTemplates(templates); err != nil {
+		printError("Error saving template:", err)
+		return
+	}
+
+	if replaced {
+		fmt.Printf("Template %q updated.\n", name)
+	} else {
+		fmt.Printf("Template %q saved.\n", name)
+	}
+	printTemplate(template)
+}
+
+func runTemplateApply(name, from string, duration time.Duration) {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	templates, err := readTemplates()
+	if err != nil {
+		printError("Error reading templates:", err)
+		return
+	}
+	template := findTemplate(templates, name)
+	if template == nil {
+		fmt.Printf("Template %q not found. Run 'plannet template list' to see saved templates.\n", name)
+		setExitCode(exitNotFound)
+		return
+	}
+
+	if duration == 0 {
+		duration = template.Duration()
+	}
+	now := time.Now()
+	startTime, endTime, err := resolveTrackTimes(from, "", duration, now)
+	if err != nil {
+		fmt.Println("Invalid time:", err)
+		setExitCode(exitUsage)
+		return
+	}
+
+	work := workFromTemplate(*template, startTime, endTime)
+	work.Context = getWorkContext(cfg)
+
+	// Only one piece of work can be active at a time
+	if work.Status == "active" {
+		activeWork, err := getActiveWork()
+		if err != nil {
+			printError("Error getting active work:", err)
+			return
+		}
+		if activeWork != nil {
+			fmt.Printf("Pausing current work: %s\n", activeWork.Description)
+			pauseWork(activeWork, now)
+			if err := saveTrackedWork(*activeWork); err != nil {
+				printError("Error pausing current work:", err)
+				return
+			}
+		}
+	}
+
+	if err := saveTrackedWork(work); err != nil {
+		printError("Error saving tracked work:", err)
+		return
+	}
+
+	fmt.Println("Work tracked successfully!")
+	fmt.Printf("ID: %s\n", work.ID)
+	fmt.Printf("Description: %s\n", work.Description)
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(work.TicketIDs, ", "))
+	}
+	if len(work.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(work.Tags, ", "))
+	}
+	if work.Billable {
+		fmt.Println("Billable: yes")
+	}
+	if !work.EndTime.IsZero() {
+		fmt.Printf("Logged: %s – %s (%s)\n", work.StartTime.Format("2006-01-02 15:04"), work.EndTime.Format("15:04"), formatDuration(work.Duration()))
+	} else {
+		fmt.Printf("Started: %s\n", work.StartTime.Format("2006-01-02 15:04"))
+	}
+}
+
+func runTemplateList() {
+	templates, err := readTemplates()
+	if err != nil {
+		printError("Error reading templates:", err)
+		return
+	}
+
+	if jsonOutput() {
+		printJSON(templates)
+		return
+	}
+	if len(templates) == 0 {
+		fmt.Println("No templates found. Use 'plannet template add' to save one.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	for i, template := range templates {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", template.Name)
+		printTemplate(template)
+	}
+}
+
+// printTemplate prints the details of a template
+func printTemplate(template WorkTemplate) {
+	fmt.Printf("  Description: %s\n", template.Description)
+	if len(template.TicketIDs) > 0 {
+		fmt.Printf("  Tickets: %s\n", strings.Join(template.TicketIDs, ", "))
+	}
+	if len(template.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(template.Tags, ", "))
+	}
+	if template.DurationMinutes > 0 {
+		fmt.Printf("  Duration: %s\n", formatDuration(template.Duration()))
+	}
+	if template.Billable {
+		fmt.Println("  Billable: yes")
+	}
+}
+
+// workFromTemplate creates tracked work from a template. A zero end time
+// starts the work as active.
+func workFromTemplate(template WorkTemplate, start, end time.Time) TrackedWork {
+	work := TrackedWork{
+		ID:          generateID(),
+		Description: template.Description,
+		TicketIDs:   append([]string(nil), template.TicketIDs...),
+		StartTime:   start,
+		EndTime:     end,
+		Tags:        append([]string(nil), template.Tags...),
+		Status:      "active",
+		Billable:    template.Billable,
+	}
+	if !end.IsZero() {
+		work.Status = "completed"
+	}
+	return work
+}
+
+// findTemplate returns the template with the given name, or nil if there is none
+func findTemplate(templates []WorkTemplate, name string) *WorkTemplate {
+	for i := range templates {
+		if templates[i].Name == name {
+			return &templates[i]
+		}
+	}
+	return nil
+}
+
+// upsertTemplate adds a template or replaces the one with the same name,
+// keeping the templates sorted by name
+func upsertTemplate(templates []WorkTemplate, template WorkTemplate) []WorkTemplate {
+	if existing := findTemplate(templates, template.Name); existing != nil {
+		*existing = template
+	} else {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates
+}
+
+// completeTemplateNames completes template names for shell completion
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	templates, err := readTemplates()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, template := range templates {
+		if strings.HasPrefix(template.Name, toComplete) {
+			names = append(names, template.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// getTemplatesPath returns the path of the templates file
+func getTemplatesPath() (string, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, templatesFile), nil
+}
+
+// readTemplates returns the saved templates. A missing file means no templates.
+func readTemplates() ([]WorkTemplate, error) {
+	path, err := getTemplatesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []WorkTemplate{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", templatesFile, err)
+	}
+
+	var templates []WorkTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", templatesFile, err)
+	}
+	return templates, nil
+}
+
+// writeTemplates saves the templates
+func writeTemplates(templates []WorkTemplate) error {
+	path, err := getTemplatesPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create plannet directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal templates: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", templatesFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSaveAndApplyTemplate(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	standup := WorkTemplate{Name: "standup", Description: "Daily standup", Tags: []string{"meeting"}, DurationMinutes: 15}
+	triage := WorkTemplate{Name: "oncall", Description: "On-call triage", TicketIDs: []string{"OPS-1"}}
+
+	templates, err := readTemplates()
+	if err != nil {
+		t.Fatalf("Failed to read templates: %v", err)
+	}
+	templates = upsertTemplate(templates, standup)
+	templates = upsertTemplate(templates, triage)
+	standup.DurationMinutes = 20
+	templates = upsertTemplate(templates, standup)
+	if err := writeTemplates(templates); err != nil {
+		t.Fatalf("Failed to write templates: %v", err)
+	}
+
+	templates, err = readTemplates()
+	if err != nil {
+		t.Fatalf("Failed to read templates: %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "oncall" || templates[1].Name != "standup" {
+		t.Fatalf("Expected templates oncall and standup, got %+v", templates)
+	}
+	if templates[1].DurationMinutes != 20 {
+		t.Errorf("Expected the replaced template to have 20 minutes, got %d", templates[1].DurationMinutes)
+	}
+
+	now := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	work := workFromTemplate(templates[1], now.Add(-templates[1].Duration()), now)
+	if work.Status != "completed" || work.Duration() != 20*time.Minute {
+		t.Errorf("Expected 20m of completed work, got %s for %v", work.Status, work.Duration())
+	}
+	if work.Description != "Daily standup" || len(work.Tags) != 1 || work.Tags[0] != "meeting" {
+		t.Errorf("Expected the template's description and tags, got %+v", work)
+	}
+
+	work = workFromTemplate(templates[0], now, time.Time{})
+	if work.Status != "active" || !work.HasTicket("OPS-1") {
+		t.Errorf("Expected active work linked to OPS-1, got %+v", work)
+	}
+}
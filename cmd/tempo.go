@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/timelog"
+)
+
+func init() {
+	timelog.OnRateLimitWait = reportRateLimitWait
+}
+
+// tempoWorklogs returns the worklogs of work pushed to Tempo
+func tempoWorklogs(work *TrackedWork) *[]Worklog {
+	return &work.TempoWorklogs
+}
+
+// exportTempo logs completed work as Tempo worklogs on its Jira tickets, in
+// the account and with the work attributes its tags are mapped to. Worklogs
+// are only created once, and updated when the time of the work changes.
+func exportTempo(ctx context.Context, cfg *config.Config, trackedWork []TrackedWork, settings config.WorklogSettings, dryRun bool) error {
+	// Tempo identifies issues and authors by their Jira IDs, which are looked up in Jira
+	if cfg.JiraURL == "" || cfg.JiraUser == "" || cfg.JiraToken == "" {
+		setExitCode(exitConfig)
+		return fmt.Errorf("Jira integration is not configured. Run 'plannet init' to set up Jira integration")
+	}
+	tempo, err := timelog.NewTempo(cfg)
+	if err != nil {
+		setExitCode(exitConfig)
+		return err
+	}
+
+	changes := planWorklogs(trackedWork, settings, time.Time{}, tempoWorklogs)
+	if len(changes) == 0 {
+		fmt.Println("All completed work is already logged in Tempo.")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(describeWorklogChange(change))
+	}
+	if dryRun {
+		fmt.Printf("\n%d worklog(s) would be pushed to Tempo (dry run).\n", len(changes))
+		return nil
+	}
+
+	client := newJiraClient(cfg, cfg.JiraToken)
+	var author jiraUser
+	if err := client.do(ctx, "GET", client.api("/myself"), nil, &author); err != nil {
+		return fmt.Errorf("failed to get your Jira account: %w", err)
+	}
+
+	issueIDs := make(map[string]int)
+	pushed, failed := 0, 0
+	for _, change := range changes {
+		issueID, ok := issueIDs[change.TicketID]
+		if !ok {
+			if issueID, err = jiraIssueID(ctx, client, change.TicketID); err != nil {
+				printErrorf("Failed to log time to %s: %v\n", change.TicketID, err)
+				failed++
+				continue
+			}
+			issueIDs[change.TicketID] = issueID
+		}
+		if err := pushTempoWorklog(ctx, tempo, change, author.AccountID, issueID); err != nil {
+			printErrorf("Failed to log time to %s: %v\n", change.TicketID, err)
+			failed++
+			continue
+		}
+		if err := saveTrackedWork(*change.Work); err != nil {
+			return fmt.Errorf("failed to save tracked work: %w", err)
+		}
+		pushed++
+	}
+	fmt.Printf("Pushed %d worklog(s) to Tempo\n", pushed)
+	if failed > 0 {
+		return fmt.Errorf("%d worklog(s) failed", failed)
+	}
+	return nil
+}
+
+// jiraIssueID returns the numeric ID of a Jira issue
+func jiraIssueID(ctx context.Context, client *jiraClient, key string) (int, error) {
+	var issue struct {
+		ID string `json:"id"`
+	}
+	if err := client.do(ctx, "GET", client.api("/issue/"+key+"?fields=id"), nil, &issue); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(issue.ID)
+}
+
+// pushTempoWorklog creates or updates a worklog in Tempo and records it on
+// the work
+func pushTempoWorklog(ctx context.Context, tempo *timelog.Tempo, change worklogChange, authorAccountID string, issueID int) error {
+	worklog := timelog.NewTempoWorklog(authorAccountID, issueID, change.Work.StartTime, change.Seconds, change.Work.Description)
+	worklog.Attributes = tempo.Attributes(change.Work.Tags)
+
+	if change.Existing != nil {
+		if err := tempo.UpdateWorklog(ctx, change.Existing.ID, worklog); err != nil {
+			return err
+		}
+		// Worklogs added for other tickets may have moved the slice, so the
+		// entry is looked up again rather than updated through Existing
+		findWorklog(change.Work.TempoWorklogs, change.TicketID).Seconds = change.Seconds
+		return nil
+	}
+
+	id, err := tempo.CreateWorklog(ctx, worklog)
+	if err != nil {
+		return err
+	}
+	change.Work.TempoWorklogs = append(change.Work.TempoWorklogs, Worklog{TicketID: change.TicketID, ID: id, Seconds: change.Seconds})
+	return nil
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/timelog"
+)
+
+func TestExportTempo(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	jira := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/myself":
+			w.Write([]byte(`{"accountId": "acc-1", "displayName": "Me"}`))
+		case "/rest/api/3/issue/PROJ-1":
+			w.Write([]byte(`{"id": "10001", "key": "PROJ-1"}`))
+		default:
+			t.Errorf("Unexpected Jira request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer jira.Close()
+
+	var sent []timelog.TempoWorklog
+	var requests []string
+	tempo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var worklog timelog.TempoWorklog
+		json.NewDecoder(r.Body).Decode(&worklog)
+		sent = append(sent, worklog)
+		w.Write([]byte(`{"tempoWorklogId": 501}`))
+	}))
+	defer tempo.Close()
+
+	cfg := &config.Config{
+		JiraURL: jira.URL, JiraUser: "me@example.com", JiraToken: "jira-token", TempoToken: "tempo-token",
+		Tempo: config.TempoSettings{URL: tempo.URL, Accounts: map[string]string{"acme": "ACME"}},
+	}
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{ID: "w1", Description: "Fix login", TicketIDs: []string{"PROJ-1"}, Tags: []string{"acme"},
+			StartTime: start, EndTime: start.Add(90 * time.Minute), Status: "completed"},
+	}
+
+	captureStdout(t, func() {
+		if err := exportTempo(context.Background(), cfg, trackedWork, config.WorklogSettings{}, false); err != nil {
+			t.Fatalf("Failed to push to Tempo: %v", err)
+		}
+	})
+	if len(sent) != 1 || requests[0] != "POST /worklogs" {
+		t.Fatalf("Expected a new worklog, got %v", requests)
+	}
+	want := timelog.TempoWorklog{AuthorAccountID: "acc-1", IssueID: 10001, TimeSpentSeconds: 5400, StartDate: "2024-03-11",
+		StartTime: "09:00:00", Description: "Fix login", Attributes: []timelog.TempoAttribute{{Key: "_Account_", Value: "ACME"}}}
+	if !reflect.DeepEqual(sent[0], want) {
+		t.Errorf("Sent %+v, want %+v", sent[0], want)
+	}
+
+	saved, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(saved) != 1 || len(saved[0].TempoWorklogs) != 1 || saved[0].TempoWorklogs[0].ID != "501" {
+		t.Fatalf("Expected the Tempo worklog to be recorded, got %+v", saved)
+	}
+	if len(saved[0].Worklogs) != 0 {
+		t.Errorf("Expected no Jira worklogs, got %+v", saved[0].Worklogs)
+	}
+
+	// A longer session updates the worklog instead of creating another
+	saved[0].EndTime = start.Add(2 * time.Hour)
+	captureStdout(t, func() {
+		if err := exportTempo(context.Background(), cfg, saved, config.WorklogSettings{}, false); err != nil {
+			t.Fatalf("Failed to push to Tempo: %v", err)
+		}
+	})
+	if len(requests) != 2 || requests[1] != "PUT /worklogs/501" || sent[1].TimeSpentSeconds != 7200 {
+		t.Errorf("Expected the worklog to be updated, got %v", requests)
+	}
+}
+
+func TestExportTempoWithoutJira(t *testing.T) {
+	resetExitCode(t)
+	err := exportTempo(context.Background(), &config.Config{TempoToken: "tempo-token"}, nil, config.WorklogSettings{}, false)
+	if err == nil || exitCode != exitConfig {
+		t.Errorf("Expected a configuration error, got %v (exit code %d)", err, exitCode)
+	}
+}
@@ -0,0 +1,468 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/logger"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// ticketCmd represents the ticket command. Its Long text, which lists the
+// ticket systems, is set in init once they have registered.
+var ticketCmd = &cobra.Command{
+	Use:   "ticket",
+	Short: "Work with tickets in your ticket system",
+	Run: func(cmd *cobra.Command, args []string) {
+		log := logger.WithContext(cmd.Context())
+		log.Info("Use one of the subcommands: list, view, create, comment, transition")
+		cmd.Help()
+	},
+}
+
+// ticketListCmd represents the ticket list command
+var ticketListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your open tickets",
+	Long: `List the open tickets assigned to you. In a terminal, they are shown in
+a searchable picker; pick one to start tracking work on it or view its
+details. Use --plain to print them in a table of their key, status and
+summary instead, or choose its columns with --columns, from key, status,
+summary, type, priority, assignee and url. Outside a terminal, the rows are
+printed with their columns separated by tabs. Use --format to print each
+ticket with a Go template, such as '{{.Key}} {{.Summary}}'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		plain, _ := cmd.Flags().GetBool("plain")
+		columns, _ := cmd.Flags().GetStringSlice("columns")
+		runTicketList(cmd.Context(), plain || !canPickInteractively(), columns)
+	},
+}
+
+// ticketViewCmd represents the ticket view command
+var ticketViewCmd = &cobra.Command{
+	Use:   "view [ticket]",
+	Short: "View a ticket",
+	Long: `View the details of a ticket. For systems with attachments, such as Jira,
+use --download to save them, or only those named with --attachment, into
+the current directory or the one given with --dir.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		download, _ := cmd.Flags().GetBool("download")
+		names, _ := cmd.Flags().GetStringSlice("attachment")
+		dir, _ := cmd.Flags().GetString("dir")
+		runTicketView(cmd.Context(), args[0], download, names, dir)
+	},
+}
+
+// ticketCreateCmd represents the ticket create command
+var ticketCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a ticket",
+	Long: `Create a ticket. Without --summary, it is asked for. --project is where
+the ticket goes, depending on the system: a Jira, YouTrack or Redmine
+project, a GitHub or GitLab repository, a Linear team, an Asana project, a
+Trello or ClickUp list, or whatever the mapping file or plugin of the system
+takes. It defaults to the one in your configuration, and Notion tickets
+always go to the configured database.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var ticket systems.NewTicket
+		ticket.Project, _ = cmd.Flags().GetString("project")
+		ticket.Type, _ = cmd.Flags().GetString("type")
+		ticket.Summary, _ = cmd.Flags().GetString("summary")
+		ticket.Description, _ = cmd.Flags().GetString("description")
+		ticket.Labels, _ = cmd.Flags().GetStringSlice("label")
+		runTicketCreate(cmd.Context(), ticket)
+	},
+}
+
+// ticketCommentCmd represents the ticket comment command
+var ticketCommentCmd = &cobra.Command{
+	Use:   "comment [ticket] [text]",
+	Short: "Comment on a ticket",
+	Long: `Add a comment to a ticket. The comment is taken from the arguments,
+from standard input when the text is "-" or input is piped in, or written
+in your editor with --editor.
+With --work, a summary of the latest work tracked on the ticket is appended,
+or used as the whole comment when no text is given.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		useEditor, _ := cmd.Flags().GetBool("editor")
+		withWork, _ := cmd.Flags().GetBool("work")
+		runTicketComment(cmd.Context(), args[0], strings.Join(args[1:], " "), useEditor, withWork)
+	},
+}
+
+// ticketTransitionCmd represents the ticket transition command
+var ticketTransitionCmd = &cobra.Command{
+	Use:   "transition [ticket] [status]",
+	Short: "Move a ticket to another status",
+	Long: `Move a ticket to another status, such as "In Progress" or "Done". What
+a status can be depends on the system: a Jira transition or status, open or
+closed for GitHub and GitLab issues, a Linear workflow state, an Asana
+section or completed, a Trello list, a YouTrack state, a status of the
+ClickUp task's list, an option of the Notion status property, a Redmine
+issue status, or whatever the mapping file or plugin of the system takes.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runTicketTransition(cmd.Context(), args[0], strings.Join(args[1:], " "))
+	},
+}
+
+func init() {
+	ticketCmd.Long = `Work with tickets in the ticket system the ticket_system setting selects:
+
+` + ticketSystemList() + `
+or a plugin: a ` + systems.PluginPrefix + `<name> executable on your PATH provides
+the system <name>. Tickets are given by the key the system uses, such as
+PROJ-123, owner/repo#42 or ENG-7.
+
+'plannet jira' offers more for Jira, such as JQL searches, sprints and
+worklogs.`
+	rootCmd.AddCommand(ticketCmd)
+	ticketCmd.AddCommand(ticketListCmd)
+	ticketCmd.AddCommand(ticketViewCmd)
+	ticketCmd.AddCommand(ticketCreateCmd)
+	ticketCmd.AddCommand(ticketCommentCmd)
+	ticketCmd.AddCommand(ticketTransitionCmd)
+	supportJSONOutput(ticketListCmd)
+	supportJSONOutput(ticketViewCmd)
+	ticketListCmd.Flags().Bool("plain", false, "Print the tickets instead of picking one")
+	ticketListCmd.Flags().StringSlice("columns", nil, "Columns of the printed table, such as key,status,summary")
+	ticketListCmd.RegisterFlagCompletionFunc("columns", completeTicketColumns)
+	addFormatFlag(ticketListCmd, "Print each ticket with a Go template, such as '{{.Key}} {{.Summary}}'")
+	ticketViewCmd.Flags().Bool("download", false, "Download the ticket's attachments, for systems that have them")
+	ticketViewCmd.Flags().StringSlice("attachment", nil, "Only download the attachment with this file name (can be repeated)")
+	ticketViewCmd.Flags().String("dir", ".", "Directory to download attachments into")
+	ticketCreateCmd.Flags().String("project", "", "Where to create the ticket, instead of the configured default")
+	ticketCreateCmd.Flags().String("type", "", "Type of the ticket, for systems that have types")
+	ticketCreateCmd.Flags().String("summary", "", "Summary of the ticket")
+	ticketCreateCmd.Flags().String("description", "", "Description of the ticket")
+	ticketCreateCmd.Flags().StringSlice("label", nil, "Label to add to the ticket (can be repeated)")
+	ticketCommentCmd.Flags().BoolP("editor", "e", false, "Write the comment in your editor")
+	ticketCommentCmd.Flags().Bool("work", false, "Append a summary of the latest work tracked on the ticket")
+}
+
+// loadTicketSystem loads the configuration and creates the ticket system it
+// selects, logging why if either fails
+func loadTicketSystem(ctx context.Context) (*config.Config, systems.TicketSystem, bool) {
+	log := logger.WithContext(ctx)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error("Failed to load configuration: %v", err)
+		log.Info("Run 'plannet init' to set up your configuration.")
+		return nil, nil, false
+	}
+	system, err := systems.New(cfg)
+	if err != nil {
+		log.Error("%v", err)
+		return nil, nil, false
+	}
+	return cfg, system, true
+}
+
+// ticketSystemList lists the ticket systems built into plannet, one per line
+// with the name ticket_system selects it by and its title. Jira registers in
+// jirasystem.go, whose init runs before this file's.
+func ticketSystemList() string {
+	var b strings.Builder
+	for _, reg := range systems.Default.List() {
+		if reg.Plugin == "" {
+			fmt.Fprintf(&b, "  %-9s %s\n", reg.Name, reg.Title)
+		}
+	}
+	return b.String()
+}
+
+// ticketSystemTitle returns the name of a ticket system shown to users
+func ticketSystemTitle(system systems.TicketSystem) string {
+	if reg, ok := systems.Default.Lookup(system.Name()); ok {
+		return reg.Title
+	}
+	return system.Name()
+}
+
+// ticketDetailsPrompter is implemented by ticket systems that ask for more
+// about a new ticket than its summary and description, such as the project,
+// issue type and custom fields of a Jira ticket
+type ticketDetailsPrompter interface {
+	// promptTicketDetails asks for the details the ticket doesn't have yet
+	promptTicketDetails(ctx context.Context, ticket *systems.NewTicket) error
+}
+
+// runTicketList lists the open tickets assigned to you. Unless plain, they
+// are shown in an interactive picker; otherwise they are printed in a table
+// with the given columns.
+func runTicketList(ctx context.Context, plain bool, columns []string) {
+	log := logger.WithContext(ctx)
+	if _, err := ticketTable(nil, columns); err != nil {
+		log.Error("%v", err)
+		return
+	}
+	_, system, ok := loadTicketSystem(ctx)
+	if !ok {
+		return
+	}
+
+	tickets, err := system.List(ctx)
+	if err != nil {
+		log.Error("Failed to list tickets: %v", err)
+		return
+	}
+	showTickets(ctx, fmt.Sprintf("Your %s tickets", ticketSystemTitle(system)), tickets, plain, columns)
+}
+
+// showTickets shows tickets as the output format asks: as JSON, with a
+// template, in an interactive picker titled title, or unless plain, in a
+// table with the given columns
+func showTickets(ctx context.Context, title string, tickets []systems.Ticket, plain bool, columns []string) {
+	log := logger.WithContext(ctx)
+	if jsonOutput() {
+		if tickets == nil {
+			tickets = []systems.Ticket{}
+		}
+		printJSON(tickets)
+		return
+	}
+	picked := make([]JiraTicket, len(tickets))
+	for i, ticket := range tickets {
+		picked[i] = jiraTicketFromSystem(ticket)
+	}
+	if templateOutput() {
+		for _, ticket := range picked {
+			if err := printFormatted(ticket); err != nil {
+				log.Error("%v", err)
+				return
+			}
+		}
+		return
+	}
+	if len(tickets) == 0 {
+		log.Info("No tickets found.")
+		return
+	}
+	if !plain {
+		if err := pickAndActOnJiraTicket(ctx, title, picked); err != nil && err != promptui.ErrInterrupt {
+			log.Error("Error: %v", err)
+		}
+		return
+	}
+
+	table, _ := ticketTable(picked, columns)
+	if err := table.Print(); err != nil {
+		log.Error("Failed to print tickets: %v", err)
+	}
+}
+
+// runTicketView views a ticket, and downloads its attachments if asked to:
+// those with the given names, or all of them, into dir
+func runTicketView(ctx context.Context, key string, download bool, names []string, dir string) {
+	log := logger.WithContext(ctx)
+	_, system, ok := loadTicketSystem(ctx)
+	if !ok {
+		return
+	}
+
+	key, err := system.ResolveKey(key)
+	if err != nil {
+		log.Error("Invalid ticket key: %v", err)
+		setExitCode(exitUsage)
+		return
+	}
+	downloader, canDownload := system.(systems.AttachmentDownloader)
+	if download && !canDownload {
+		log.Error("Attachments can't be downloaded from %s", ticketSystemTitle(system))
+		setExitCode(exitUsage)
+		return
+	}
+	ticket, err := system.View(ctx, key)
+	if err != nil {
+		log.Error("Failed to get ticket %s: %v", key, err)
+		return
+	}
+
+	if jsonOutput() {
+		printJSON(ticket)
+	} else {
+		printTicket(ctx, ticket)
+	}
+
+	if !download {
+		return
+	}
+	attachments, err := selectAttachments(ticket.Attachments, names)
+	if err != nil {
+		log.Error("%v", err)
+		return
+	}
+	for _, attachment := range attachments {
+		path, err := downloader.DownloadAttachment(ctx, attachment, dir)
+		if err != nil {
+			log.Error("Failed to download %s: %v", attachment.Filename, err)
+			continue
+		}
+		log.Info("Downloaded %s", path)
+	}
+}
+
+// printTicket prints the details of a ticket, leaving out those its system
+// doesn't have
+func printTicket(ctx context.Context, ticket *systems.Ticket) {
+	log := logger.WithContext(ctx)
+	log.Info("Ticket: %s", ticket.Key)
+	log.Info("Summary: %s", ticket.Summary)
+	log.Info("Status: %s", ticket.Status)
+	log.Info("Type: %s", ticket.Type)
+	if ticket.Priority != "" {
+		log.Info("Priority: %s", ticket.Priority)
+	}
+	log.Info("Assignee: %s", ticket.Assignee)
+	if ticket.Parent != nil {
+		if ticket.Parent.Summary != "" {
+			log.Info("Parent: %s %s (%s)", ticket.Parent.Key, ticket.Parent.Summary, ticket.Parent.Type)
+		} else {
+			log.Info("Parent: %s (%s)", ticket.Parent.Key, ticket.Parent.Type)
+		}
+	}
+	if len(ticket.Labels) > 0 {
+		log.Info("Labels: %s", strings.Join(ticket.Labels, ", "))
+	}
+	log.Info("URL: %s", ticket.URL)
+	for _, field := range ticket.Fields {
+		log.Info("%s: %s", field.Name, field.Value)
+	}
+	printDescription(ticket.Description)
+
+	if len(ticket.Links) > 0 {
+		log.Info("\nLinks:")
+		for _, link := range ticket.Links {
+			log.Info("  %s %s: %s (%s)", link.Relation, link.Key, link.Summary, link.Status)
+		}
+	}
+
+	if len(ticket.Attachments) > 0 {
+		log.Info("\nAttachments:")
+		for _, attachment := range ticket.Attachments {
+			log.Info("  %s (%s, %s)", attachment.Filename, attachment.MimeType, formatFileSize(attachment.Size))
+		}
+	}
+}
+
+// runTicketCreate creates a ticket, asking for its summary if not given, and
+// for whatever else the ticket system needs
+func runTicketCreate(ctx context.Context, ticket systems.NewTicket) {
+	log := logger.WithContext(ctx)
+	_, system, ok := loadTicketSystem(ctx)
+	if !ok {
+		return
+	}
+
+	if ticket.Summary == "" {
+		if !isInteractive() {
+			log.Error("Give the ticket's summary with --summary")
+			return
+		}
+		summaryPrompt := promptui.Prompt{
+			Label: "Enter summary",
+			Validate: func(input string) error {
+				if strings.TrimSpace(input) == "" {
+					return fmt.Errorf("summary cannot be empty")
+				}
+				return nil
+			},
+		}
+		summary, err := summaryPrompt.Run()
+		if err != nil {
+			log.Error("Error: %v", err)
+			return
+		}
+		ticket.Summary = summary
+	}
+	createTicket(ctx, system, ticket)
+}
+
+// createTicket asks for the details the ticket system needs that the ticket
+// doesn't have, when in a terminal, then creates it
+func createTicket(ctx context.Context, system systems.TicketSystem, ticket systems.NewTicket) (*systems.Ticket, bool) {
+	log := logger.WithContext(ctx)
+	if prompter, ok := system.(ticketDetailsPrompter); ok && isInteractive() {
+		if err := prompter.promptTicketDetails(ctx, &ticket); err != nil {
+			if err == promptui.ErrInterrupt {
+				log.Info("Operation cancelled by user.")
+			} else {
+				log.Error("Error: %v", err)
+			}
+			return nil, false
+		}
+	}
+
+	created, err := system.Create(ctx, ticket)
+	if err != nil {
+		log.Error("Failed to create ticket: %v", err)
+		return nil, false
+	}
+	log.Info("Successfully created ticket %s", created.Key)
+	if created.URL != "" {
+		log.Info("URL: %s", created.URL)
+	}
+	return created, true
+}
+
+// runTicketComment adds a comment to a ticket
+func runTicketComment(ctx context.Context, key, text string, useEditor, withWork bool) {
+	log := logger.WithContext(ctx)
+	cfg, system, ok := loadTicketSystem(ctx)
+	if !ok {
+		return
+	}
+
+	key, err := system.ResolveKey(key)
+	if err != nil {
+		log.Error("Invalid ticket key: %v", err)
+		setExitCode(exitUsage)
+		return
+	}
+	text, err = readCommentText(cfg, key, text, useEditor, withWork)
+	if err != nil {
+		log.Error("%v", err)
+		return
+	}
+	if err := system.Comment(ctx, key, text); err != nil {
+		log.Error("Failed to comment on %s: %v", key, err)
+		return
+	}
+	log.Info("Commented on %s", key)
+}
+
+// runTicketTransition moves a ticket to another status
+func runTicketTransition(ctx context.Context, key, status string) {
+	log := logger.WithContext(ctx)
+	_, system, ok := loadTicketSystem(ctx)
+	if !ok {
+		return
+	}
+
+	key, err := system.ResolveKey(key)
+	if err != nil {
+		log.Error("Invalid ticket key: %v", err)
+		setExitCode(exitUsage)
+		return
+	}
+	if err := system.Transition(ctx, key, status); err != nil {
+		log.Error("Failed to move %s to %s: %v", key, status, err)
+		return
+	}
+	log.Info("Moved %s to %s", key, status)
+}
+
+// printDescription prints the description of a ticket, rendering its markdown
+func printDescription(description string) {
+	fmt.Printf("\n%s\n%s\n", output.Heading("Description:"), output.Markdown(description))
+}
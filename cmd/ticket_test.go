@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/internal/systems"
+)
+
+func TestTicketHelpListsEverySystem(t *testing.T) {
+	for _, reg := range systems.Default.List() {
+		if reg.Plugin != "" {
+			continue
+		}
+		if !strings.Contains(ticketCmd.Long, reg.Name) || !strings.Contains(ticketCmd.Long, reg.Title) {
+			t.Errorf("ticket help doesn't list %s (%s):\n%s", reg.Name, reg.Title, ticketCmd.Long)
+		}
+	}
+	if !strings.Contains(ticketCmd.Long, "  jira ") {
+		t.Errorf("ticket help doesn't list Jira:\n%s", ticketCmd.Long)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+)
+
+// leaveTicketAsIs is the choice that leaves a ticket's status unchanged
+const leaveTicketAsIs = "Leave as is"
+
+// findTransition returns the transition with the given name, or leading to
+// the status with that name, ignoring case, hyphens and underscores
+func findTransition(transitions []systems.Transition, name string) (*systems.Transition, bool) {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(strings.TrimSpace(s)))
+	}
+	name = normalize(name)
+	for i, t := range transitions {
+		if normalize(t.Name) == name || normalize(t.To) == name {
+			return &transitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// describeTransitions lists the names of transitions
+func describeTransitions(transitions []systems.Transition) string {
+	if len(transitions) == 0 {
+		return "(none)"
+	}
+	names := make([]string, len(transitions))
+	for i, t := range transitions {
+		names[i] = fmt.Sprintf("%q", t.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// offerTicketTransitions offers to move the tickets of work that reached a
+// status to the status jira_transitions maps it to, in the configured ticket
+// system. For systems with workflows, such as Jira, the transitions each
+// ticket can currently take are shown, with the configured one preselected.
+// Nothing changes unless a move is picked.
+func offerTicketTransitions(ctx context.Context, cfg *config.Config, status string, works []TrackedWork) {
+	target := cfg.JiraTransitions[status]
+	if target == "" || !isInteractive() {
+		return
+	}
+	system, err := systems.New(cfg)
+	if err != nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, work := range works {
+		for _, ticketID := range work.TicketIDs {
+			key, err := system.ResolveKey(ticketID)
+			if err != nil || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			// Without workflows, a ticket can move to any status
+			transitions := []systems.Transition{{Name: target, To: target}}
+			if lister, ok := system.(systems.TransitionLister); ok {
+				if transitions, err = lister.Transitions(ctx, key); err != nil {
+					printErrorf("Error getting the transitions of %s: %v\n", key, err)
+					continue
+				}
+			}
+			items, cursor := transitionChoices(transitions, target)
+			if items[cursor] == leaveTicketAsIs {
+				fmt.Printf("%s can't move to %q from its current status.\n", key, target)
+			}
+
+			prompt := promptui.Select{
+				Label: fmt.Sprintf("Move %s to a new status?", key),
+				Items: items,
+			}
+			index, _, err := prompt.RunCursorAt(cursor, 0)
+			if err != nil || items[index] == leaveTicketAsIs {
+				continue
+			}
+			if err := system.Transition(ctx, key, transitions[index].Name); err != nil {
+				printErrorf("Error moving %s: %v\n", key, err)
+				continue
+			}
+			fmt.Printf("Moved %s to %s\n", key, transitions[index].To)
+		}
+	}
+}
+
+// transitionChoices returns the choices of transitions, followed by the
+// choice to leave the ticket as is, and the index of the transition matching
+// the target, or of leaving the ticket as is when none does
+func transitionChoices(transitions []systems.Transition, target string) ([]string, int) {
+	items := make([]string, 0, len(transitions)+1)
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, t.To) {
+			items = append(items, t.Name)
+		} else {
+			items = append(items, fmt.Sprintf("%s (to %s)", t.Name, t.To))
+		}
+	}
+	items = append(items, leaveTicketAsIs)
+
+	cursor := len(transitions)
+	if match, ok := findTransition(transitions, target); ok {
+		for i := range transitions {
+			if transitions[i].ID == match.ID && transitions[i].Name == match.Name {
+				cursor = i
+			}
+		}
+	}
+	return items, cursor
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/internal/systems"
+)
+
+func TestFindTransition(t *testing.T) {
+	transitions := []systems.Transition{
+		{ID: "11", Name: "Start Progress", To: "In Progress"},
+		{ID: "21", Name: "Request review", To: "In Review"},
+		{ID: "31", Name: "Finish", To: "Done"},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Finish", "31"},
+		{"done", "31"},
+		{"in-review", "21"},
+		{"start_progress", "11"},
+		{"Blocked", ""},
+	}
+	for _, tt := range tests {
+		got, ok := findTransition(transitions, tt.name)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("findTransition(%q) found %s, want none", tt.name, got.ID)
+			}
+			continue
+		}
+		if !ok || got.ID != tt.want {
+			t.Errorf("findTransition(%q) = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionChoices(t *testing.T) {
+	transitions := []systems.Transition{{ID: "21", Name: "Request review", To: "In Review"}, {ID: "31", Name: "Done", To: "Done"}}
+
+	items, cursor := transitionChoices(transitions, "In Review")
+	want := []string{"Request review (to In Review)", "Done", leaveTicketAsIs}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("Choices = %v, want %v", items, want)
+	}
+	if cursor != 0 {
+		t.Errorf("Expected the In Review transition to be preselected, got %d", cursor)
+	}
+
+	// Without a matching transition, leaving the ticket as is is preselected
+	if _, cursor := transitionChoices(transitions, "Closed"); cursor != 2 {
+		t.Errorf("Expected leaving the ticket as is to be preselected, got %d", cursor)
+	}
+}
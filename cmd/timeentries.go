@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/timelog"
+)
+
+// timeEntryChange is a time entry to create, or to update when Existing is
+// set, for a piece of completed work
+type timeEntryChange struct {
+	Work     *TrackedWork
+	Target   config.TimeEntryTarget
+	Seconds  int
+	Existing *TimeEntry
+}
+
+// timeEntryServiceNames are the names of the time tracking services shown
+// in messages, by the name their time entries are recorded under
+var timeEntryServiceNames = map[string]string{"toggl": "Toggl", "harvest": "Harvest"}
+
+// newTimeEntryService returns the client of a time tracking service that
+// work is pushed to as time entries
+func newTimeEntryService(cfg *config.Config, name string) (timelog.TimeEntryService, error) {
+	switch name {
+	case "toggl":
+		return timelog.NewToggl(cfg)
+	case "harvest":
+		return timelog.NewHarvest(cfg)
+	}
+	return nil, fmt.Errorf("unknown time tracking service %q", name)
+}
+
+// exportTimeEntries pushes completed work as time entries to a time
+// tracking service, in the project and task its tags or tickets are mapped
+// to. Time entries are only created once, and updated when the time of the
+// work changes.
+func exportTimeEntries(ctx context.Context, service timelog.TimeEntryService, trackedWork []TrackedWork, settings config.WorklogSettings, dryRun bool) error {
+	name := timeEntryServiceNames[service.Name()]
+	changes, unmapped := planTimeEntries(service, trackedWork, settings)
+	if unmapped > 0 {
+		printInfo(fmt.Sprintf("Skipping %d piece(s) of work without a %s project and task. Map their tags or ticket prefixes in the %s section of your configuration.",
+			unmapped, name, service.Name()))
+	}
+	if len(changes) == 0 {
+		fmt.Printf("All completed work is already logged in %s.\n", name)
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Println(describeTimeEntryChange(change))
+	}
+	if dryRun {
+		fmt.Printf("\n%d time entries would be pushed to %s (dry run).\n", len(changes), name)
+		return nil
+	}
+
+	pushed, failed := 0, 0
+	for _, change := range changes {
+		if err := pushTimeEntry(ctx, service, change); err != nil {
+			printErrorf("Failed to push %q: %v\n", change.Work.Description, err)
+			failed++
+			continue
+		}
+		if err := saveTrackedWork(*change.Work); err != nil {
+			return fmt.Errorf("failed to save tracked work: %w", err)
+		}
+		pushed++
+	}
+	fmt.Printf("Pushed %d time entries to %s\n", pushed, name)
+	if failed > 0 {
+		return fmt.Errorf("%d time entries failed", failed)
+	}
+	return nil
+}
+
+// planTimeEntries returns the time entries needed to bring a service in
+// line with the completed work, and how much work has no target the service
+// can log it to
+func planTimeEntries(service timelog.TimeEntryService, trackedWork []TrackedWork, settings config.WorklogSettings) ([]timeEntryChange, int) {
+	var changes []timeEntryChange
+	unmapped := 0
+	for i := range trackedWork {
+		work := &trackedWork[i]
+		if work.Status != "completed" {
+			continue
+		}
+		seconds := roundWorklog(work.Duration(), settings)
+		existing := findTimeEntry(work.TimeEntries, service.Name())
+		// Services reject entries without time, so rounded-away work is skipped
+		if seconds == 0 || (existing != nil && existing.Seconds == seconds) {
+			continue
+		}
+		target, ok := service.Target(work.TicketIDs, work.Tags)
+		if !ok {
+			unmapped++
+			continue
+		}
+		changes = append(changes, timeEntryChange{Work: work, Target: target, Seconds: seconds, Existing: existing})
+	}
+	return changes, unmapped
+}
+
+// findTimeEntry returns the time entry created in a service, or nil
+func findTimeEntry(entries []TimeEntry, service string) *TimeEntry {
+	for i := range entries {
+		if entries[i].Service == service {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// describeTimeEntryChange formats a time entry change as a line of a diff
+func describeTimeEntryChange(change timeEntryChange) string {
+	marker, logged := "+", formatDuration(time.Duration(change.Seconds)*time.Second)
+	if change.Existing != nil {
+		marker = "~"
+		logged = formatDuration(time.Duration(change.Existing.Seconds)*time.Second) + " -> " + logged
+	}
+	return fmt.Sprintf("%s %s  %s  %s", marker, change.Work.StartTime.Format("2006-01-02"), logged, timeEntryDescription(*change.Work))
+}
+
+// timeEntryDescription describes work in a time entry, led by its tickets
+// so they can be found in the service
+func timeEntryDescription(work TrackedWork) string {
+	if len(work.TicketIDs) == 0 {
+		return work.Description
+	}
+	return strings.Join(work.TicketIDs, " ") + " " + work.Description
+}
+
+// pushTimeEntry creates or updates a time entry and records it on the work
+func pushTimeEntry(ctx context.Context, service timelog.TimeEntryService, change timeEntryChange) error {
+	entry := timelog.TimeEntry{
+		Description: timeEntryDescription(*change.Work),
+		Start:       change.Work.StartTime,
+		Seconds:     change.Seconds,
+		Tags:        change.Work.Tags,
+		Target:      change.Target,
+	}
+
+	if change.Existing != nil {
+		if err := service.UpdateTimeEntry(ctx, change.Existing.ID, entry); err != nil {
+			return err
+		}
+		change.Existing.Seconds = change.Seconds
+		return nil
+	}
+
+	id, err := service.CreateTimeEntry(ctx, entry)
+	if err != nil {
+		return err
+	}
+	change.Work.TimeEntries = append(change.Work.TimeEntries, TimeEntry{Service: service.Name(), ID: id, Seconds: change.Seconds})
+	return nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/timelog"
+)
+
+// fakeTimeEntryService records the time entries pushed to it, and logs
+// work mapped to a project only
+type fakeTimeEntryService struct {
+	created []timelog.TimeEntry
+	updated map[string]timelog.TimeEntry
+}
+
+func (f *fakeTimeEntryService) Name() string { return "harvest" }
+
+func (f *fakeTimeEntryService) Target(ticketIDs, tags []string) (config.TimeEntryTarget, bool) {
+	if len(ticketIDs) == 0 {
+		return config.TimeEntryTarget{}, false
+	}
+	return config.TimeEntryTarget{Project: 7, Task: 70}, true
+}
+
+func (f *fakeTimeEntryService) CreateTimeEntry(ctx context.Context, entry timelog.TimeEntry) (string, error) {
+	f.created = append(f.created, entry)
+	return fmt.Sprint(len(f.created)), nil
+}
+
+func (f *fakeTimeEntryService) UpdateTimeEntry(ctx context.Context, id string, entry timelog.TimeEntry) error {
+	if f.updated == nil {
+		f.updated = make(map[string]timelog.TimeEntry)
+	}
+	f.updated[id] = entry
+	return nil
+}
+
+func TestPlanTimeEntries(t *testing.T) {
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{ID: "new", Description: "Fix login", TicketIDs: []string{"PROJ-1"}, StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "changed", Description: "Review", TicketIDs: []string{"PROJ-2"}, StartTime: start, EndTime: start.Add(90 * time.Minute), Status: "completed",
+			TimeEntries: []TimeEntry{{Service: "harvest", ID: "9", Seconds: 3600}, {Service: "toggl", ID: "5", Seconds: 5400}}},
+		{ID: "pushed", Description: "Deploy", TicketIDs: []string{"PROJ-3"}, StartTime: start, EndTime: start.Add(time.Hour), Status: "completed",
+			TimeEntries: []TimeEntry{{Service: "harvest", ID: "10", Seconds: 3600}}},
+		{ID: "unmapped", Description: "Lunch and learn", StartTime: start, EndTime: start.Add(time.Hour), Status: "completed"},
+		{ID: "active", Description: "Still going", TicketIDs: []string{"PROJ-4"}, StartTime: start, Status: "active"},
+	}
+
+	changes, unmapped := planTimeEntries(&fakeTimeEntryService{}, trackedWork, config.WorklogSettings{})
+	if len(changes) != 2 || unmapped != 1 {
+		t.Fatalf("Expected a new and an updated entry and one unmapped, got %+v and %d", changes, unmapped)
+	}
+	if changes[0].Work.ID != "new" || changes[0].Existing != nil || changes[0].Seconds != 3600 {
+		t.Errorf("Unexpected first change %+v", changes[0])
+	}
+	if changes[1].Work.ID != "changed" || changes[1].Existing == nil || changes[1].Existing.ID != "9" || changes[1].Seconds != 5400 {
+		t.Errorf("Unexpected second change %+v", changes[1])
+	}
+	if got := describeTimeEntryChange(changes[1]); got != "~ 2024-03-11  1h -> 1h 30m  PROJ-2 Review" {
+		t.Errorf("Unexpected change description %q", got)
+	}
+}
+
+func TestExportTimeEntries(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{ID: "w1", Description: "Fix login", TicketIDs: []string{"PROJ-1"}, Tags: []string{"backend"},
+			StartTime: start, EndTime: start.Add(40 * time.Minute), Status: "completed"},
+	}
+	service := &fakeTimeEntryService{}
+	captureStdout(t, func() {
+		if err := exportTimeEntries(context.Background(), service, trackedWork, config.WorklogSettings{RoundMinutes: 15}, false); err != nil {
+			t.Fatalf("Failed to push time entries: %v", err)
+		}
+	})
+	if len(service.created) != 1 {
+		t.Fatalf("Expected a time entry, got %+v", service.created)
+	}
+	entry := service.created[0]
+	if entry.Description != "PROJ-1 Fix login" || entry.Seconds != 45*60 || entry.Target.Project != 7 || !entry.Start.Equal(start) {
+		t.Errorf("Unexpected time entry %+v", entry)
+	}
+
+	saved, err := getTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get tracked work: %v", err)
+	}
+	if len(saved) != 1 || len(saved[0].TimeEntries) != 1 || saved[0].TimeEntries[0] != (TimeEntry{Service: "harvest", ID: "1", Seconds: 45 * 60}) {
+		t.Fatalf("Expected the time entry to be recorded, got %+v", saved)
+	}
+
+	// Pushing again with nothing changed doesn't touch the service
+	captureStdout(t, func() {
+		if err := exportTimeEntries(context.Background(), service, saved, config.WorklogSettings{RoundMinutes: 15}, false); err != nil {
+			t.Fatalf("Failed to push time entries: %v", err)
+		}
+	})
+	if len(service.created) != 1 || len(service.updated) != 0 {
+		t.Errorf("Expected nothing to be pushed, got %+v and %+v", service.created, service.updated)
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// timesheetRow is the time spent on one ticket on one day
+type timesheetRow struct {
+	Date         time.Time
+	TicketID     string // Empty for work without a ticket
+	Duration     time.Duration
+	Descriptions []string
+}
+
+// buildTimesheet totals the completed work per day and ticket, oldest day
+// first, and rounds each total as settings say. Work linked to several
+// tickets has its time split evenly between them.
+func buildTimesheet(trackedWork []TrackedWork, settings config.TimesheetSettings) []timesheetRow {
+	type key struct {
+		date     time.Time
+		ticketID string
+	}
+	rows := make(map[key]*timesheetRow)
+	for _, work := range trackedWork {
+		if work.Status != "completed" {
+			continue
+		}
+		tickets := work.TicketIDs
+		if len(tickets) == 0 {
+			tickets = []string{""}
+		}
+		share := work.Duration() / time.Duration(len(tickets))
+		date := startOfDay(work.StartTime)
+		for _, ticketID := range tickets {
+			k := key{date, ticketID}
+			row, ok := rows[k]
+			if !ok {
+				row = &timesheetRow{Date: date, TicketID: ticketID}
+				rows[k] = row
+			}
+			row.Duration += share
+			if !containsString(row.Descriptions, work.Description) {
+				row.Descriptions = append(row.Descriptions, work.Description)
+			}
+		}
+	}
+
+	var timesheet []timesheetRow
+	for _, row := range rows {
+		row.Duration = roundTimesheet(row.Duration, settings)
+		if row.Duration > 0 {
+			timesheet = append(timesheet, *row)
+		}
+	}
+	sort.Slice(timesheet, func(i, j int) bool {
+		if !timesheet[i].Date.Equal(timesheet[j].Date) {
+			return timesheet[i].Date.Before(timesheet[j].Date)
+		}
+		return timesheet[i].TicketID < timesheet[j].TicketID
+	})
+	return timesheet
+}
+
+// roundTimesheet rounds the time spent on a ticket on a day, recording at
+// least the minimum for any time spent
+func roundTimesheet(d time.Duration, settings config.TimesheetSettings) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	rounded := roundDuration(d, settings.RoundMinutes, settings.Rounding)
+	if minimum := time.Duration(settings.MinimumMinutes) * time.Minute; rounded < minimum {
+		return minimum
+	}
+	return rounded
+}
+
+// exportTimesheet exports the time spent on each ticket each day as CSV
+func exportTimesheet(work []TrackedWork, outputPath string, settings config.TimesheetSettings) error {
+	if outputPath == "" {
+		return writeTimesheet(os.Stdout, buildTimesheet(work, settings))
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return writeTimesheet(file, buildTimesheet(work, settings))
+}
+
+// writeTimesheet writes a timesheet as CSV, with the time in hours as well
+// as minutes for timesheet tools that expect either
+func writeTimesheet(w io.Writer, timesheet []timesheetRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Date", "Ticket", "Hours", "Minutes", "Description"}); err != nil {
+		return err
+	}
+	for _, row := range timesheet {
+		err := writer.Write([]string{
+			row.Date.Format(dateInputLayout),
+			row.TicketID,
+			strconv.FormatFloat(row.Duration.Hours(), 'f', 2, 64),
+			strconv.Itoa(int(row.Duration.Minutes())),
+			strings.Join(row.Descriptions, "; "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
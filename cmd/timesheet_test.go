@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestBuildTimesheet(t *testing.T) {
+	monday := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	trackedWork := []TrackedWork{
+		{Description: "Login form", TicketIDs: []string{"JIRA-1"}, StartTime: monday, EndTime: monday.Add(50 * time.Minute), Status: "completed"},
+		{Description: "Login tests", TicketIDs: []string{"JIRA-1"}, StartTime: monday.Add(2 * time.Hour), EndTime: monday.Add(2*time.Hour + 20*time.Minute), Status: "completed"},
+		{Description: "Standup", StartTime: monday.Add(time.Hour), EndTime: monday.Add(time.Hour + 5*time.Minute), Status: "completed"},
+		{Description: "Review", TicketIDs: []string{"JIRA-1", "JIRA-2"}, StartTime: monday.AddDate(0, 0, 1), EndTime: monday.AddDate(0, 0, 1).Add(40 * time.Minute), Status: "completed"},
+		{Description: "Still going", TicketIDs: []string{"JIRA-3"}, StartTime: monday, Status: "active"},
+	}
+
+	timesheet := buildTimesheet(trackedWork, config.TimesheetSettings{RoundMinutes: 15, Rounding: "up", MinimumMinutes: 30})
+	want := []struct {
+		date     string
+		ticketID string
+		duration time.Duration
+	}{
+		{"2024-05-06", "", 30 * time.Minute},
+		{"2024-05-06", "JIRA-1", 75 * time.Minute},
+		{"2024-05-07", "JIRA-1", 30 * time.Minute},
+		{"2024-05-07", "JIRA-2", 30 * time.Minute},
+	}
+	if len(timesheet) != len(want) {
+		t.Fatalf("buildTimesheet() = %+v, want %d rows", timesheet, len(want))
+	}
+	for i, w := range want {
+		row := timesheet[i]
+		if row.Date.Format(dateInputLayout) != w.date || row.TicketID != w.ticketID || row.Duration != w.duration {
+			t.Errorf("row %d = %s %q %v, want %s %q %v", i, row.Date.Format(dateInputLayout), row.TicketID, row.Duration, w.date, w.ticketID, w.duration)
+		}
+	}
+	if got := timesheet[1].Descriptions; len(got) != 2 {
+		t.Errorf("descriptions of JIRA-1 on Monday = %v, want both pieces of work", got)
+	}
+}
+
+func TestRoundTimesheet(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		settings config.TimesheetSettings
+		want     time.Duration
+	}{
+		{0, config.TimesheetSettings{MinimumMinutes: 15}, 0},
+		{7*time.Minute + 20*time.Second, config.TimesheetSettings{}, 7 * time.Minute},
+		{7 * time.Minute, config.TimesheetSettings{RoundMinutes: 15}, 0},
+		{7 * time.Minute, config.TimesheetSettings{RoundMinutes: 15, MinimumMinutes: 15}, 15 * time.Minute},
+		{61 * time.Minute, config.TimesheetSettings{RoundMinutes: 15, Rounding: "up"}, 75 * time.Minute},
+		{74 * time.Minute, config.TimesheetSettings{RoundMinutes: 15, Rounding: "down"}, 60 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := roundTimesheet(tt.duration, tt.settings); got != tt.want {
+			t.Errorf("roundTimesheet(%v, %+v) = %v, want %v", tt.duration, tt.settings, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTimesheet(t *testing.T) {
+	var buf bytes.Buffer
+	timesheet := []timesheetRow{
+		{Date: time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local), TicketID: "JIRA-1", Duration: 75 * time.Minute, Descriptions: []string{"Login form", "Login tests"}},
+	}
+	if err := writeTimesheet(&buf, timesheet); err != nil {
+		t.Fatal(err)
+	}
+	want := "Date,Ticket,Hours,Minutes,Description\n2024-05-06,JIRA-1,1.25,75,Login form; Login tests\n"
+	if buf.String() != want {
+		t.Errorf("writeTimesheet() = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// todoCmd represents the todo command
+var todoCmd = &cobra.Command{
+	Use:   "todo",
+	Short: "Manage the checklist of tracked work",
+	Long: `Manage a checklist of subtasks on tracked work, so a single entry can
+represent a multi-step piece of work. Subtasks are added to the active work
+unless another entry is chosen with --id.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+		runTodoList(id)
+	},
+}
+
+// todoAddCmd represents the todo add command
+var todoAddCmd = &cobra.Command{
+	Use:   "add <text>",
+	Short: "Add a subtask",
+	Long:  `Add a subtask to the checklist of tracked work.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+		runTodoAdd(id, strings.Join(args, " "))
+	},
+}
+
+// todoDoneCmd represents the todo done command
+var todoDoneCmd = &cobra.Command{
+	Use:   "done <number>...",
+	Short: "Mark subtasks as done",
+	Long:  `Mark one or more subtasks as done, by their number in 'plannet todo list'.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+		runTodoDone(id, args)
+	},
+}
+
+// todoListCmd represents the todo list command
+var todoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List subtasks",
+	Long:  `List the checklist of tracked work.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		id, _ := cmd.Flags().GetString("id")
+		runTodoList(id)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(todoCmd)
+	todoCmd.AddCommand(todoAddCmd)
+	todoCmd.AddCommand(todoDoneCmd)
+	todoCmd.AddCommand(todoListCmd)
+	supportJSONOutput(todoCmd)
+	supportJSONOutput(todoListCmd)
+	todoCmd.PersistentFlags().String("id", "", "ID of the tracked work (defaults to the active work)")
+}
+
+func runTodoAdd(id string, text string) {
+	work, ok := loadTodoWork(id)
+	if !ok {
+		return
+	}
+
+	if err := addSubtask(work, text); err != nil {
+		fmt.Println("Invalid subtask:", err)
+		setExitCode(exitUsage)
+		return
+	}
+	if err := saveTrackedWork(*work); err != nil {
+		printError("Error saving work:", err)
+		return
+	}
+
+	fmt.Printf("Added subtask %d to %s\n", len(work.Subtasks), work.Description)
+}
+
+func runTodoDone(id string, args []string) {
+	work, ok := loadTodoWork(id)
+	if !ok {
+		return
+	}
+
+	numbers, err := parseSubtaskNumbers(args)
+	if err != nil {
+		fmt.Println("Invalid subtask number:", err)
+		setExitCode(exitUsage)
+		return
+	}
+	if err := completeSubtasks(work, numbers); err != nil {
+		printError("Error completing subtasks:", err)
+		return
+	}
+	if err := saveTrackedWork(*work); err != nil {
+		printError("Error saving work:", err)
+		return
+	}
+
+	printSubtasks(*work)
+}
+
+func runTodoList(id string) {
+	work, ok := loadTodoWork(id)
+	if !ok {
+		return
+	}
+
+	if jsonOutput() {
+		subtasks := work.Subtasks
+		if subtasks == nil {
+			subtasks = []Subtask{}
+		}
+		printJSON(subtasks)
+		return
+	}
+	if len(work.Subtasks) == 0 {
+		fmt.Printf("No subtasks on %s. Add one with 'plannet todo add <text>'.\n", work.Description)
+		setExitCode(exitNotFound)
+		return
+	}
+	printSubtasks(*work)
+}
+
+// loadTodoWork loads the work a todo command applies to, printing any problem
+func loadTodoWork(id string) (*TrackedWork, bool) {
+	// Load configuration
+	if _, err := config.Load(); err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return nil, false
+	}
+
+	if id == "" {
+		activeWork, err := getActiveWork()
+		if err != nil {
+			printError("Error getting active work:", err)
+			return nil, false
+		}
+		if activeWork == nil {
+			fmt.Println("No active work. Use --id to choose tracked work.")
+			setExitCode(exitNotFound)
+			return nil, false
+		}
+		return activeWork, true
+	}
+
+	trackedWork, err := getTrackedWork()
+	if err != nil {
+		printError("Error getting tracked work:", err)
+		return nil, false
+	}
+	work := findWorkByID(trackedWork, id)
+	if work == nil {
+		fmt.Printf("No tracked work found with ID %s\n", id)
+		setExitCode(exitNotFound)
+		return nil, false
+	}
+	return work, true
+}
+
+// addSubtask appends a subtask to the checklist of tracked work
+func addSubtask(work *TrackedWork, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("text cannot be empty")
+	}
+	work.Subtasks = append(work.Subtasks, Subtask{Text: text})
+	return nil
+}
+
+// completeSubtasks marks the subtasks with the given 1-based numbers as done
+func completeSubtasks(work *TrackedWork, numbers []int) error {
+	for _, n := range numbers {
+		if n < 1 || n > len(work.Subtasks) {
+			return fmt.Errorf("no subtask %d (there are %d)", n, len(work.Subtasks))
+		}
+	}
+	for _, n := range numbers {
+		work.Subtasks[n-1].Done = true
+	}
+	return nil
+}
+
+// parseSubtaskNumbers parses 1-based subtask numbers from arguments
+func parseSubtaskNumbers(args []string) ([]int, error) {
+	numbers := make([]int, 0, len(args))
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", arg)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+// subtaskProgress returns how many subtasks of tracked work are done
+func subtaskProgress(work TrackedWork) (done, total int) {
+	for _, s := range work.Subtasks {
+		if s.Done {
+			done++
+		}
+	}
+	return done, len(work.Subtasks)
+}
+
+// printSubtasks prints the checklist of tracked work
+func printSubtasks(work TrackedWork) {
+	done, total := subtaskProgress(work)
+	fmt.Printf("%s (%d/%d done)\n", work.Description, done, total)
+	for i, s := range work.Subtasks {
+		mark := " "
+		if s.Done {
+			mark = "x"
+		}
+		fmt.Printf("  [%s] %d. %s\n", mark, i+1, s.Text)
+	}
+}
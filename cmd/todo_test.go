@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubtasks(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	work := TrackedWork{ID: "test-1", Description: "Release", StartTime: time.Now(), Status: "active"}
+	for _, text := range []string{"Bump version", "Write changelog", "Tag release"} {
+		if err := addSubtask(&work, text); err != nil {
+			t.Fatalf("Failed to add subtask: %v", err)
+		}
+	}
+	if err := addSubtask(&work, "  "); err == nil {
+		t.Error("Expected an error for an empty subtask")
+	}
+
+	if err := completeSubtasks(&work, []int{1, 4}); err == nil {
+		t.Error("Expected an error for a subtask number out of range")
+	}
+	if work.Subtasks[0].Done {
+		t.Error("Expected no subtasks to change when a number is out of range")
+	}
+
+	if err := completeSubtasks(&work, []int{1, 3}); err != nil {
+		t.Fatalf("Failed to complete subtasks: %v", err)
+	}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	saved, err := getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	done, total := subtaskProgress(*saved)
+	if done != 2 || total != 3 {
+		t.Errorf("Expected 2/3 subtasks done, got %d/%d", done, total)
+	}
+	if saved.Subtasks[1].Done {
+		t.Error("Expected subtask 2 to still be open")
+	}
+
+	if _, err := parseSubtaskNumbers([]string{"1", "two"}); err == nil {
+		t.Error("Expected an error for a non-numeric subtask number")
+	}
+}
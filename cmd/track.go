@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -15,21 +16,136 @@ import (
 
 // WorkContext represents the git context of tracked work
 type WorkContext struct {
+	// Repo is the top-level directory of the repository the work was tracked in
+	Repo       string   `json:"repo,omitempty"`
 	Branch     string   `json:"branch,omitempty"`
 	Files      []string `json:"files,omitempty"`
 	CommitHash string   `json:"commit_hash,omitempty"`
+	// StartCommit is the commit the work started from; the diff from it to
+	// the last commit of the work gives the diff statistics below
+	StartCommit  string      `json:"start_commit,omitempty"`
+	LinesAdded   int         `json:"lines_added,omitempty"`
+	LinesRemoved int         `json:"lines_removed,omitempty"`
+	FileChurn    []FileChurn `json:"file_churn,omitempty"`
+	// BranchEnded is "merged" or "deleted" once the branch is found to have
+	// been merged or deleted, and MergeCommit the commit that merged it
+	BranchEnded string `json:"branch_ended,omitempty"`
+	MergeCommit string `json:"merge_commit,omitempty"`
+}
+
+// FileChurn represents the lines added and removed in one file
+type FileChurn struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+// PauseInterval represents a period during which tracked work was paused
+type PauseInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"` // Zero while the work is still paused
+}
+
+// Subtask represents one step of a piece of tracked work
+type Subtask struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Note represents a timestamped journal entry on a piece of tracked work
+type Note struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// Pomodoro represents a focus interval timed with 'plannet track --pomodoro'
+type Pomodoro struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Minutes     int       `json:"minutes"`               // Planned length
+	Interrupted bool      `json:"interrupted,omitempty"` // Stopped before the timer ran out
 }
 
 // TrackedWork represents a piece of work tracked by the user
 type TrackedWork struct {
-	ID          string      `json:"id"`
-	Description string      `json:"description"`
-	TicketID    string      `json:"ticket_id,omitempty"`
-	StartTime   time.Time   `json:"start_time"`
-	EndTime     time.Time   `json:"end_time,omitempty"`
-	Tags        []string    `json:"tags,omitempty"`
-	Status      string      `json:"status"` // "active", "paused", "completed"
-	Context     WorkContext `json:"context,omitempty"`
+	ID          string          `json:"id"`
+	Description string          `json:"description"`
+	TicketIDs   []string        `json:"ticket_ids,omitempty"`
+	StartTime   time.Time       `json:"start_time"`
+	EndTime     time.Time       `json:"end_time,omitempty"`
+	Tags        []string        `json:"tags,omitempty"`
+	Status      string          `json:"status"` // "active", "paused", "completed"
+	Context     WorkContext     `json:"context,omitempty"`
+	Pauses      []PauseInterval `json:"pauses,omitempty"`
+	Subtasks    []Subtask       `json:"subtasks,omitempty"`
+	Notes       []Note          `json:"notes,omitempty"`
+	Pomodoros   []Pomodoro      `json:"pomodoros,omitempty"`
+	// EstimateMinutes is how long the work was expected to take (0 for no estimate)
+	EstimateMinutes int    `json:"estimate_minutes,omitempty"`
+	Billable        bool   `json:"billable,omitempty"`
+	Project         string `json:"-"` // Set when reading work across all projects
+	// Worklogs records the time logged to Jira for the work's tickets
+	Worklogs []Worklog `json:"worklogs,omitempty"`
+	// TempoWorklogs records the time logged to Tempo for the work's tickets
+	TempoWorklogs []Worklog `json:"tempo_worklogs,omitempty"`
+	// TimeEntries records the time entries created for the work in time
+	// tracking services, such as Toggl Track and Harvest
+	TimeEntries []TimeEntry `json:"time_entries,omitempty"`
+}
+
+// Worklog is a Jira or Tempo worklog entry created for tracked work
+type Worklog struct {
+	TicketID string `json:"ticket_id"`
+	ID       string `json:"id"`
+	Seconds  int    `json:"seconds"`
+}
+
+// TimeEntry is a time entry created for tracked work in a time tracking service
+type TimeEntry struct {
+	Service string `json:"service"` // Such as "toggl" or "harvest"
+	ID      string `json:"id"`
+	Seconds int    `json:"seconds"`
+}
+
+// PausedDuration returns the total time the work has spent paused
+func (w TrackedWork) PausedDuration() time.Duration {
+	var paused time.Duration
+	for _, p := range w.Pauses {
+		end := p.End
+		if end.IsZero() {
+			end = w.EndTime
+		}
+		if end.IsZero() {
+			end = time.Now()
+		}
+		if end.After(p.Start) {
+			paused += end.Sub(p.Start)
+		}
+	}
+	return paused
+}
+
+// HasTicket reports whether the work is linked to the given ticket
+func (w TrackedWork) HasTicket(ticketID string) bool {
+	return containsString(w.TicketIDs, ticketID)
+}
+
+// Estimate returns how long the work was expected to take, or zero without an estimate
+func (w TrackedWork) Estimate() time.Duration {
+	return time.Duration(w.EstimateMinutes) * time.Minute
+}
+
+// Duration returns the time spent on the work, excluding any paused time
+func (w TrackedWork) Duration() time.Duration {
+	end := w.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	elapsed := end.Sub(w.StartTime) - w.PausedDuration()
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
 }
 
 // trackCmd represents the track command
@@ -38,17 +154,53 @@ var trackCmd = &cobra.Command{
 	Short: "Track a piece of work manually",
 	Long: `Track a piece of work manually that isn't captured by git.
 This command allows you to record work that doesn't involve code changes,
-such as meetings, documentation, or research.`,
+such as meetings, documentation, or research.
+
+Work can also be back-filled after the fact. Times are HH:MM for today or
+YYYY-MM-DD HH:MM:
+
+  plannet track --from 13:00 --to 14:30 "incident call"
+  plannet track --duration 45m "code review"   # ended just now
+  plannet track --from 09:15 "standup notes"   # still ongoing
+
+Use --pomodoro to work in timed focus intervals; you are notified when each
+one ends and can start another, take a break or complete the work.
+
+Use --sprint to pick the ticket, and by default the description, from the
+issues assigned to you in the active Jira sprints.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		runTrack(args)
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		pomodoro, _ := cmd.Flags().GetDuration("pomodoro")
+		estimate, _ := cmd.Flags().GetDuration("estimate")
+		billable, _ := cmd.Flags().GetBool("billable")
+		sprint, _ := cmd.Flags().GetBool("sprint")
+		var pick ticketPicker
+		if sprint {
+			pick = func(cfg *config.Config) (*JiraTicket, error) {
+				return pickSprintTicket(context.Background(), cfg)
+			}
+		}
+		runTrack(args, from, to, duration, pomodoro, estimate, billable, pick)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(trackCmd)
+	trackCmd.Flags().String("from", "", "When the work started (HH:MM or YYYY-MM-DD HH:MM)")
+	trackCmd.Flags().String("to", "", "When the work ended (HH:MM or YYYY-MM-DD HH:MM)")
+	trackCmd.Flags().Duration("duration", 0, "How long the work took (e.g. 45m, 1h30m)")
+	trackCmd.Flags().Duration("pomodoro", 0, "Run a focus timer of this length (e.g. 25m)")
+	trackCmd.Flags().Duration("estimate", 0, "How long you expect the work to take (e.g. 2h)")
+	trackCmd.Flags().BoolP("billable", "b", false, "Mark the work as billable")
+	trackCmd.Flags().Bool("sprint", false, "Pick the ticket from your issues in the active Jira sprints")
 }
 
-func runTrack(args []string) {
+// ticketPicker picks the ticket to track work on
+type ticketPicker func(cfg *config.Config) (*JiraTicket, error)
+
+func runTrack(args []string, from, to string, duration, pomodoro, estimate time.Duration, billable bool, pick ticketPicker) {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -56,6 +208,37 @@ func runTrack(args []string) {
 		return
 	}
 
+	// Work back-filled with an end time is recorded as completed and
+	// leaves the active work alone
+	startTime, endTime, err := resolveTrackTimes(from, to, duration, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid time range: %v\n", err)
+		setExitCode(exitUsage)
+		return
+	}
+	if pomodoro < 0 || (pomodoro > 0 && !endTime.IsZero()) {
+		fmt.Println("A pomodoro timer can only be used for work that is starting now.")
+		return
+	}
+	if estimate < 0 {
+		fmt.Println("An estimate cannot be negative.")
+		return
+	}
+
+	// Pick the ticket before touching the active work, so a failed lookup changes nothing
+	var pickedTicket *JiraTicket
+	if pick != nil {
+		pickedTicket, err = pick(cfg)
+		if err != nil {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			fmt.Printf("Failed to pick a ticket: %v\n", err)
+			return
+		}
+	}
+
 	// Check for active work
 	activeWork, err := getActiveWork()
 	if err != nil {
@@ -63,11 +246,11 @@ func runTrack(args []string) {
 		return
 	}
 
-	if activeWork != nil {
+	if activeWork != nil && endTime.IsZero() {
 		fmt.Println("You have active work:")
 		fmt.Printf("Description: %s\n", activeWork.Description)
-		if activeWork.TicketID != "" {
-			fmt.Printf("Ticket: %s\n", activeWork.TicketID)
+		if len(activeWork.TicketIDs) > 0 {
+			fmt.Printf("Tickets: %s\n", strings.Join(activeWork.TicketIDs, ", "))
 		}
 		fmt.Printf("Started: %s\n", activeWork.StartTime.Format("15:04"))
 
@@ -93,14 +276,13 @@ func runTrack(args []string) {
 
 		switch index {
 		case 0: // Complete current work
-			activeWork.EndTime = time.Now()
-			activeWork.Status = "completed"
+			completeWork(activeWork, time.Now())
 			if err := saveTrackedWork(*activeWork); err != nil {
 				fmt.Printf("Failed to complete work: %v\n", err)
 				return
 			}
 		case 1: // Pause current work
-			activeWork.Status = "paused"
+			pauseWork(activeWork, time.Now())
 			if err := saveTrackedWork(*activeWork); err != nil {
 				fmt.Printf("Failed to pause work: %v\n", err)
 				return
@@ -114,6 +296,8 @@ func runTrack(args []string) {
 	var description string
 	if len(args) > 0 {
 		description = strings.Join(args, " ")
+	} else if pickedTicket != nil {
+		description = pickedTicket.Summary
 	} else {
 		prompt := promptui.Prompt{
 			Label: "What are you working on?",
@@ -137,28 +321,25 @@ func runTrack(args []string) {
 	}
 
 	// Try to infer ticket ID from current branch if git integration is enabled
-	var ticketID string
-	if cfg.GitIntegration {
-		currentDir, err := os.Getwd()
+	var ticketIDs []string
+	if pickedTicket != nil {
+		ticketIDs = []string{pickedTicket.Key}
+	} else {
+		ticketID, err := inferTicketID(cfg)
 		if err != nil {
-			fmt.Printf("Failed to get current directory: %v\n", err)
+			fmt.Printf("Failed to infer ticket ID: %v\n", err)
 			return
 		}
-		if isGitRepo(currentDir) {
-			branch, err := getCurrentBranch()
-			if err != nil {
-				fmt.Printf("Failed to get current branch: %v\n", err)
-				return
-			}
-			ticketID = extractTicketID(branch, cfg.TicketPrefixes)
+		if ticketID != "" {
+			ticketIDs = []string{ticketID}
 		}
 	}
 
-	// If no ticket ID found, ask for one
-	if ticketID == "" && len(cfg.TicketPrefixes) > 0 {
+	// If no ticket ID found, ask for any number of them
+	if len(ticketIDs) == 0 && (len(cfg.TicketPrefixes) > 0 || len(cfg.TicketPatterns) > 0) {
 		prompt := promptui.Prompt{
-			Label:    "Ticket ID (optional)",
-			Validate: validateTicketID,
+			Label:    "Ticket IDs (optional, comma-separated)",
+			Validate: validateTicketIDs,
 		}
 		result, err := prompt.Run()
 		if err != nil {
@@ -168,73 +349,47 @@ func runTrack(args []string) {
 			}
 			// If user cancelled, just continue without a ticket ID
 		} else {
-			ticketID = result
+			ticketIDs = parseTicketIDs(result)
 		}
 	}
 
-	// Ask for tags
-	var tags []string
-	for {
-		prompt := promptui.Prompt{
-			Label: "Add a tag (leave empty to finish)",
-		}
-		result, err := prompt.Run()
-		if err != nil {
-			if err == promptui.ErrInterrupt {
-				break
-			}
-			fmt.Println("Error getting tag:", err)
-			return
-		}
-		if result == "" {
-			break
-		}
-		tags = append(tags, strings.TrimSpace(result))
-	}
-
-	// Get context if in git repo and git integration is enabled
-	var context WorkContext
-	if cfg.GitIntegration {
-		currentDir, err := os.Getwd()
-		if err == nil && isGitRepo(currentDir) {
-			// Get current branch
-			if branch, err := getCurrentBranch(); err == nil {
-				context.Branch = branch
-			}
-
-			// Get most recent commit and changed files
-			if commits, err := getRecentCommits(1); err == nil && len(commits) > 0 {
-				context.CommitHash = commits[0].Hash
-				if files, err := getFilesChanged(currentDir, commits[0].Hash); err == nil {
-					context.Files = files
-				}
-			}
-		}
+	// Ask for tags, completing from the tags already in use
+	tags, err := promptTags(existingTags())
+	if err != nil {
+		printError("Error getting tag:", err)
+		return
 	}
 
 	// Create tracked work
 	work := TrackedWork{
 		ID:          generateID(),
 		Description: description,
-		TicketID:    ticketID,
-		StartTime:   time.Now(),
+		TicketIDs:   ticketIDs,
+		StartTime:   startTime,
+		EndTime:     endTime,
 		Tags:        tags,
 		Status:      "active",
-		Context:     context,
+		Context:     getWorkContext(cfg),
+		// Estimates are kept to the minute
+		EstimateMinutes: int(estimate.Round(time.Minute).Minutes()),
+		Billable:        billable,
+	}
+	if !endTime.IsZero() {
+		work.Status = "completed"
 	}
 
 	// Save tracked work
 	err = saveTrackedWork(work)
 	if err != nil {
-		fmt.Println("Error saving tracked work:", err)
+		printError("Error saving tracked work:", err)
 		return
 	}
 
 	fmt.Println("\nWork tracked successfully!")
 	fmt.Printf("ID: %s\n", work.ID)
 	fmt.Printf("Description: %s\n", work.Description)
-	if work.TicketID != "" {
-		fmt.Printf("Ticket ID: %s\n", work.TicketID)
+	if len(work.TicketIDs) > 0 {
+		fmt.Printf("Tickets: %s\n", strings.Join(work.TicketIDs, ", "))
 	}
 	if len(work.Tags) > 0 {
 		fmt.Printf("Tags: %s\n", strings.Join(work.Tags, ", "))
@@ -242,15 +397,147 @@ func runTrack(args []string) {
 	if work.Context.Branch != "" {
 		fmt.Printf("Branch: %s\n", work.Context.Branch)
 	}
+	if work.EstimateMinutes > 0 {
+		fmt.Printf("Estimate: %s\n", formatEstimate(work))
+	}
+	if work.Billable {
+		fmt.Println("Billable: yes")
+	}
+	if !work.EndTime.IsZero() {
+		fmt.Printf("Logged: %s – %s (%s)\n", work.StartTime.Format("2006-01-02 15:04"), work.EndTime.Format("15:04"), formatDuration(work.Duration()))
+	} else if from != "" {
+		fmt.Printf("Started: %s\n", work.StartTime.Format("2006-01-02 15:04"))
+	}
+
+	if pomodoro > 0 {
+		fmt.Println()
+		runPomodoro(work.ID, pomodoro)
+	}
+}
+
+// resolveTrackTimes works out when tracked work started and ended from the
+// --from, --to and --duration flags. A zero end time means the work is ongoing.
+func resolveTrackTimes(from, to string, duration time.Duration, now time.Time) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+
+	if from != "" {
+		if start, err = parseClockInput(from, now); err != nil {
+			return start, end, err
+		}
+	}
+	if to != "" {
+		if end, err = parseClockInput(to, now); err != nil {
+			return start, end, err
+		}
+	}
+	if duration < 0 {
+		return start, end, fmt.Errorf("duration cannot be negative")
+	}
+
+	switch {
+	case from != "" && to != "" && duration > 0:
+		return start, end, fmt.Errorf("use at most two of --from, --to and --duration")
+	case from == "" && to == "" && duration == 0:
+		return now, time.Time{}, nil
+	case from == "" && to != "" && duration == 0:
+		return start, end, fmt.Errorf("--to needs --from or --duration")
+	case duration > 0 && from != "":
+		end = start.Add(duration)
+	case duration > 0:
+		if end.IsZero() {
+			end = now
+		}
+		start = end.Add(-duration)
+	}
+
+	if start.After(now) || end.After(now) {
+		return start, end, fmt.Errorf("work cannot be tracked in the future")
+	}
+	if !end.IsZero() && !end.After(start) {
+		return start, end, fmt.Errorf("end time must be after start time")
+	}
+	return start, end, nil
+}
+
+// parseClockInput parses a time given as HH:MM (today) or YYYY-MM-DD HH:MM
+func parseClockInput(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if t, err := time.ParseInLocation("15:04", input, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+	}
+	if t, err := time.ParseInLocation(timeInputLayout, input, now.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM or YYYY-MM-DD HH:MM", input)
+}
+
+// inferTicketID infers a ticket ID from the current branch when git integration is enabled
+func inferTicketID(cfg *config.Config) (string, error) {
+	if !cfg.GitIntegration {
+		return "", nil
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if !isGitRepo(currentDir) {
+		return "", nil
+	}
+
+	branch, err := getCurrentBranch()
+	if err != nil {
+		return "", err
+	}
+	return extractTicketID(branch, cfg.TicketPrefixes), nil
+}
+
+// getWorkContext captures the git context of new work when git integration is enabled
+func getWorkContext(cfg *config.Config) WorkContext {
+	var context WorkContext
+	if !cfg.GitIntegration {
+		return context
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil || !isGitRepo(currentDir) {
+		return context
+	}
+
+	if topLevel, err := getGitBackend().TopLevel(currentDir); err == nil {
+		context.Repo = topLevel
+	}
+
+	// Get current branch
+	if branch, err := getCurrentBranch(); err == nil {
+		context.Branch = branch
+	}
+
+	// Get most recent commit and changed files
+	if commits, err := getRecentCommits(1); err == nil && len(commits) > 0 {
+		context.CommitHash = commits[0].Hash
+		context.StartCommit = commits[0].Hash
+		if files, err := getFilesChanged(currentDir, commits[0].Hash); err == nil {
+			context.Files = files
+		}
+	}
+
+	return context
 }
 
 // getActiveWork returns the currently active work, if any
 func getActiveWork() (*TrackedWork, error) {
-	dbDir, err := getDBDir()
+	dbDir, err := openDB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get database directory: %w", err)
+		return nil, err
 	}
 
+	return readActiveWork(dbDir)
+}
+
+// readActiveWork reads the active work from the database in dbDir, if any
+func readActiveWork(dbDir string) (*TrackedWork, error) {
 	activeFile := filepath.Join(dbDir, "active.json")
 	if _, err := os.Stat(activeFile); os.IsNotExist(err) {
 		return nil, nil
@@ -269,7 +556,7 @@ func getActiveWork() (*TrackedWork, error) {
 	return &work, nil
 }
 
-// validateTicketID validates a ticket ID against the configured prefixes
+// validateTicketID validates a ticket ID against the configured prefixes and patterns
 func validateTicketID(input string) error {
 	if input == "" {
 		return nil
@@ -285,20 +572,49 @@ func validateTicketID(input string) error {
 			return nil
 		}
 	}
+	if cfg.MatchesTicketPattern(input) {
+		return nil
+	}
 
+	if len(cfg.TicketPatterns) > 0 {
+		return fmt.Errorf("ticket ID must start with one of: %s, or match one of the ticket patterns", strings.Join(cfg.TicketPrefixes, ", "))
+	}
 	return fmt.Errorf("ticket ID must start with one of: %s", strings.Join(cfg.TicketPrefixes, ", "))
 }
 
+// validateTicketIDs validates a comma-separated list of ticket IDs
+func validateTicketIDs(input string) error {
+	for _, ticketID := range parseTicketIDs(input) {
+		if err := validateTicketID(ticketID); err != nil {
+			return fmt.Errorf("%s: %w", ticketID, err)
+		}
+	}
+	return nil
+}
+
+// parseTicketIDs parses a comma-separated list of ticket IDs, dropping duplicates
+func parseTicketIDs(input string) []string {
+	var ticketIDs []string
+	for _, ticketID := range parseTagList(input) {
+		if !containsString(ticketIDs, ticketID) {
+			ticketIDs = append(ticketIDs, ticketID)
+		}
+	}
+	return ticketIDs
+}
+
 // generateID generates a unique ID for tracked work
 func generateID() string {
 	return fmt.Sprintf("tw-%d", time.Now().UnixNano())
 }
 
-// saveTrackedWork saves a piece of tracked work to the database
+// saveTrackedWork saves a piece of tracked work to the database.
+// Active work lives in active.json, paused work in paused.json and
+// completed work in completed.json; saving moves the work between them.
 func saveTrackedWork(work TrackedWork) error {
-	dbDir, err := getDBDir()
+	dbDir, err := openDB()
 	if err != nil {
-		return fmt.Errorf("failed to get database directory: %w", err)
+		return err
 	}
 
 	// Create directory if it doesn't exist
@@ -306,61 +622,223 @@ func saveTrackedWork(work TrackedWork) error {
 		return fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Save to active.json if work is active or paused
-	if work.Status == "active" || work.Status == "paused" {
-		activeFile := filepath.Join(dbDir, "active.json")
+	activeFile := filepath.Join(dbDir, "active.json")
+	pausedFile := filepath.Join(dbDir, "paused.json")
+	completedFile := filepath.Join(dbDir, "completed.json")
+
+	switch work.Status {
+	case "active":
 		data, err := json.MarshalIndent(work, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal work data: %w", err)
 		}
 
-		if err := os.WriteFile(activeFile, data, 0644); err != nil {
+		if err := writeFileAtomically(activeFile, data); err != nil {
 			return fmt.Errorf("failed to write active work file: %w", err)
 		}
+
+		// Work that was resumed is no longer paused
+		if err := removeWorkFromFile(pausedFile, work.ID); err != nil {
+			return err
+		}
+	case "paused":
+		if err := upsertWorkInFile(pausedFile, work); err != nil {
+			return err
+		}
+		if err := clearActiveWork(activeFile, work.ID); err != nil {
+			return err
+		}
+	case "completed":
+		if err := upsertWorkInFile(completedFile, work); err != nil {
+			return err
+		}
+		if err := removeWorkFromFile(pausedFile, work.ID); err != nil {
+			return err
+		}
+		if err := clearActiveWork(activeFile, work.ID); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown work status: %s", work.Status)
 	}
 
-	// Save to completed.json if work is completed
-	if work.Status == "completed" {
-		completedFile := filepath.Join(dbDir, "completed.json")
-		var completed []TrackedWork
+	return nil
+}
 
-		// Read existing completed work
-		if data, err := os.ReadFile(completedFile); err == nil {
-			if err := json.Unmarshal(data, &completed); err != nil {
-				return fmt.Errorf("failed to parse completed work data: %w", err)
-			}
+// readWorkFile reads a list of tracked work from a JSON file.
+// A missing file is treated as an empty list.
+func readWorkFile(path string) ([]TrackedWork, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []TrackedWork{}, nil
 		}
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var work []TrackedWork
+	if err := json.Unmarshal(data, &work); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	return work, nil
+}
 
-		// Append new work
-		completed = append(completed, work)
+// writeWorkFile writes a list of tracked work to a JSON file
+func writeWorkFile(path string, work []TrackedWork) error {
+	data, err := json.MarshalIndent(work, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal work data: %w", err)
+	}
 
-		// Write back to file
-		data, err := json.MarshalIndent(completed, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal completed work data: %w", err)
+	if err := writeFileAtomically(path, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// writeFileAtomically writes a database file through a temporary .tmp file
+// in the same directory, synced and then renamed into place, so a crash
+// mid-write never leaves the file truncated. sync ignores the .tmp files.
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpFile := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpFile)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpFile)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	if err := os.Chmod(tmpFile, 0644); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile)
+		return err
+	}
+	return nil
+}
+
+// upsertWorkInFile replaces the work with the same ID in a list file, or appends it
+func upsertWorkInFile(path string, work TrackedWork) error {
+	existing, err := readWorkFile(path)
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range existing {
+		if existing[i].ID == work.ID {
+			existing[i] = work
+			replaced = true
+			break
 		}
+	}
+	if !replaced {
+		existing = append(existing, work)
+	}
+
+	return writeWorkFile(path, existing)
+}
+
+// removeWorkFromFile removes the work with the given ID from a list file
+func removeWorkFromFile(path string, id string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
 
-		if err := os.WriteFile(completedFile, data, 0644); err != nil {
-			return fmt.Errorf("failed to write completed work file: %w", err)
+	existing, err := readWorkFile(path)
+	if err != nil {
+		return err
+	}
+
+	remaining := existing[:0]
+	for _, w := range existing {
+		if w.ID != id {
+			remaining = append(remaining, w)
 		}
+	}
+	if len(remaining) == len(existing) {
+		return nil
+	}
+
+	return writeWorkFile(path, remaining)
+}
 
-		// Remove from active.json if it exists
-		activeFile := filepath.Join(dbDir, "active.json")
-		if err := os.Remove(activeFile); err != nil && !os.IsNotExist(err) {
-			return fmt.Errorf("failed to remove active work file: %w", err)
+// clearActiveWork removes active.json if it holds the work with the given ID
+func clearActiveWork(activeFile string, id string) error {
+	data, err := os.ReadFile(activeFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
 		}
+		return fmt.Errorf("failed to read active work file: %w", err)
+	}
+
+	var active TrackedWork
+	if err := json.Unmarshal(data, &active); err == nil && active.ID != id {
+		return nil
 	}
 
+	if err := os.Remove(activeFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove active work file: %w", err)
+	}
 	return nil
 }
 
-// getDBDir gets the directory for the tracked work database
-func getDBDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
+// deleteTrackedWork removes the work with the given ID from the database
+func deleteTrackedWork(id string) error {
+	dbDir, err := openDB()
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+		return err
+	}
+
+	if err := clearActiveWork(filepath.Join(dbDir, "active.json"), id); err != nil {
+		return err
+	}
+	for _, name := range []string{"paused.json", "completed.json"} {
+		if err := removeWorkFromFile(filepath.Join(dbDir, name), id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDBDir gets the directory for the tracked work database, resolving it
+// once per command
+func getDBDir() (string, error) {
+	if commandScope.active && commandScope.dbDir != "" {
+		return commandScope.dbDir, nil
+	}
+	dbDir, err := resolveDBDir()
+	if err == nil && commandScope.active {
+		commandScope.dbDir = dbDir
 	}
+	return dbDir, err
+}
 
-	dbDir := filepath.Join(homeDir, ".plannet", "db")
-	return dbDir, nil
+// resolveDBDir works out the directory for the tracked work database from
+// the configuration and the current repository
+func resolveDBDir() (string, error) {
+	// Work tracked inside a repository gets its own database when scoping is enabled
+	if project := scopedProject(); project != nil {
+		return projectDBDir(*project)
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "db"), nil
 }
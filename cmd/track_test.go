@@ -180,3 +180,88 @@ func TestValidateTicketID(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTicketIDs(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	if err := validateTicketIDs("JIRA-1, DEV-2"); err != nil {
+		t.Errorf("Expected a list of valid tickets to pass, got %v", err)
+	}
+	if err := validateTicketIDs("JIRA-1, INVALID-3"); err == nil {
+		t.Error("Expected an error for a list containing an invalid ticket")
+	}
+
+	got := parseTicketIDs("JIRA-1, DEV-2,, JIRA-1")
+	if len(got) != 2 || got[0] != "JIRA-1" || got[1] != "DEV-2" {
+		t.Errorf("Expected [JIRA-1 DEV-2], got %v", got)
+	}
+}
+
+func TestResolveTrackTimes(t *testing.T) {
+	now := time.Date(2024, 5, 1, 16, 0, 0, 0, time.Local)
+	at := func(hour, min int) time.Time {
+		return time.Date(2024, 5, 1, hour, min, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		duration  time.Duration
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{"now", "", "", 0, now, time.Time{}, false},
+		{"range", "13:00", "14:30", 0, at(13, 0), at(14, 30), false},
+		{"duration ending now", "", "", 45 * time.Minute, at(15, 15), now, false},
+		{"from and duration", "13:00", "", 30 * time.Minute, at(13, 0), at(13, 30), false},
+		{"to and duration", "", "12:00", time.Hour, at(11, 0), at(12, 0), false},
+		{"ongoing from", "2024-05-01 09:15", "", 0, at(9, 15), time.Time{}, false},
+		{"to only", "", "14:00", 0, time.Time{}, time.Time{}, true},
+		{"all three", "13:00", "14:00", time.Hour, time.Time{}, time.Time{}, true},
+		{"end before start", "14:00", "13:00", 0, time.Time{}, time.Time{}, true},
+		{"future", "17:00", "", 0, time.Time{}, time.Time{}, true},
+		{"invalid", "1pm", "", 0, time.Time{}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := resolveTrackTimes(tt.from, tt.to, tt.duration, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error: %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Expected %v – %v, got %v – %v", tt.wantStart, tt.wantEnd, start, end)
+			}
+		})
+	}
+}
+
+func TestWriteWorkFileReplacesTheFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "completed.json")
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	work := []TrackedWork{{ID: "work-1", Description: "Fix login", Status: "completed"}}
+	if err := writeWorkFile(path, work); err != nil {
+		t.Fatalf("Failed to write work file: %v", err)
+	}
+
+	got, err := readWorkFile(path)
+	if err != nil || len(got) != 1 || got[0].ID != "work-1" {
+		t.Fatalf("readWorkFile() = %+v, %v, want work-1", got, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Expected the file to be readable like the others, got %v, %v", info.Mode(), err)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(leftovers) != 0 {
+		t.Errorf("Expected no temporary files to be left behind, got %v", leftovers)
+	}
+}
@@ -0,0 +1,576 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/internal/systems"
+	"github.com/plannet-ai/plannet/output"
+	"github.com/spf13/cobra"
+)
+
+// uiRefreshInterval is how often the dashboard updates durations and
+// today's timeline
+const uiRefreshInterval = time.Minute
+
+// uiCmd represents the ui command
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Open a dashboard of your work",
+	Long: `Open an interactive dashboard of your current focus, today's timeline,
+your active and paused work and the tickets assigned to you, in one screen.
+
+Keys:
+  tab        switch between your work and your tickets
+  ↑/↓, k/j   move
+  s, enter   resume the selected work, or start work on the selected ticket
+  p          pause the active work
+  c          complete the selected work
+  o          open the selected ticket, or the ticket of the selected work
+  r          refresh
+  q          quit`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runUI(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
+
+func runUI(ctx context.Context) {
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+	if !canPickInteractively() {
+		fmt.Println("The dashboard needs an interactive terminal.")
+		return
+	}
+
+	program := tea.NewProgram(newDashboard(ctx, cfg), tea.WithAltScreen(), tea.WithContext(ctx))
+	if _, err := program.Run(); err != nil {
+		printError("Error running the dashboard:", err)
+	}
+}
+
+// dashboardPane is the list of the dashboard that keys act on
+type dashboardPane int
+
+const (
+	workPane dashboardPane = iota
+	ticketPane
+)
+
+// dashboard is the model of 'plannet ui'
+type dashboard struct {
+	ctx   context.Context
+	cfg   *config.Config
+	width int
+
+	pane         dashboardPane
+	workCursor   int
+	ticketCursor int
+
+	focus   nowOutput
+	blocks  []TimeBlock
+	work    []TrackedWork // The active work first, then paused work
+	tickets []JiraTicket
+
+	loadingTickets bool
+	ticketsErr     error
+	message        string
+}
+
+// dashboardTicketsMsg carries the tickets assigned to the user once loaded
+type dashboardTicketsMsg struct {
+	tickets []JiraTicket
+	err     error
+}
+
+// dashboardRefreshMsg carries the work, the current focus and today's
+// timeline once read again
+type dashboardRefreshMsg struct {
+	work   []TrackedWork // The active work first, then paused work
+	focus  nowOutput
+	blocks []TimeBlock
+	gitErr error // Why the git activity of the focus couldn't all be read
+}
+
+// dashboardErrMsg carries why the work couldn't be read again
+type dashboardErrMsg struct {
+	err error
+}
+
+// dashboardTickMsg refreshes the dashboard
+type dashboardTickMsg time.Time
+
+// newDashboard creates the dashboard, which reads the work once started
+func newDashboard(ctx context.Context, cfg *config.Config) *dashboard {
+	return &dashboard{ctx: ctx, cfg: cfg, loadingTickets: true}
+}
+
+// Init reads the work, loads the tickets and starts the refresh timer
+func (d *dashboard) Init() tea.Cmd {
+	return tea.Batch(d.refresh(), d.loadTickets(), dashboardTick())
+}
+
+// loadTickets lists the tickets assigned to the user in the background
+func (d *dashboard) loadTickets() tea.Cmd {
+	ctx, cfg := d.ctx, d.cfg
+	return func() tea.Msg {
+		system, err := systems.New(cfg)
+		if err != nil {
+			return dashboardTicketsMsg{err: err}
+		}
+		listed, err := system.List(ctx)
+		if err != nil {
+			return dashboardTicketsMsg{err: err}
+		}
+		tickets := make([]JiraTicket, len(listed))
+		for i, ticket := range listed {
+			tickets[i] = jiraTicketFromSystem(ticket)
+		}
+		return dashboardTicketsMsg{tickets: tickets}
+	}
+}
+
+// dashboardTick waits for the next refresh
+func dashboardTick() tea.Cmd {
+	return tea.Tick(uiRefreshInterval, func(t time.Time) tea.Msg {
+		return dashboardTickMsg(t)
+	})
+}
+
+// refresh reads the work, the current focus and today's timeline again in
+// the background
+func (d *dashboard) refresh() tea.Cmd {
+	cfg := d.cfg
+	return func() tea.Msg {
+		msg, err := readDashboard(cfg)
+		if err != nil {
+			return dashboardErrMsg{err: err}
+		}
+		return msg
+	}
+}
+
+// readDashboard reads the work, the current focus and today's timeline
+func readDashboard(cfg *config.Config) (dashboardRefreshMsg, error) {
+	var msg dashboardRefreshMsg
+	activeWork, err := getActiveWork()
+	if err != nil {
+		return msg, fmt.Errorf("failed to get active work: %w", err)
+	}
+	pausedWork, err := getPausedWork()
+	if err != nil {
+		return msg, fmt.Errorf("failed to get paused work: %w", err)
+	}
+	sort.Slice(pausedWork, func(i, j int) bool {
+		return pausedWork[i].StartTime.After(pausedWork[j].StartTime)
+	})
+	if activeWork != nil {
+		msg.work = append(msg.work, *activeWork)
+	}
+	msg.work = append(msg.work, pausedWork...)
+
+	msg.focus, msg.gitErr = gatherNow(cfg, activeWork)
+	if dir, err := os.Getwd(); err == nil && cfg.GitIntegration && isGitRepo(dir) {
+		commits, err := getCommitsSince(dir, "midnight", commitFilterFor(cfg, false, nil))
+		if err == nil && len(commits) > 0 {
+			msg.blocks = groupCommitsByTimeBlock(dir, commits)
+		}
+	}
+	return msg, nil
+}
+
+// Update handles keys, loaded tickets and the refresh timer
+func (d *dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.width = msg.Width
+	case dashboardTicketsMsg:
+		d.loadingTickets = false
+		d.tickets, d.ticketsErr = msg.tickets, msg.err
+		d.ticketCursor = clampCursor(d.ticketCursor, len(d.tickets))
+	case dashboardRefreshMsg:
+		d.work, d.focus, d.blocks = msg.work, msg.focus, msg.blocks
+		d.workCursor = clampCursor(d.workCursor, len(d.work))
+		if msg.gitErr != nil {
+			d.message = fmt.Sprintf("Error reading git: %v", msg.gitErr)
+		}
+	case dashboardErrMsg:
+		d.message = fmt.Sprintf("Error: %v", msg.err)
+	case dashboardTickMsg:
+		notifyLongRunningWork(d.cfg, time.Time(msg))
+		return d, tea.Batch(d.refresh(), dashboardTick())
+	case tea.KeyMsg:
+		return d, d.handleKey(msg.String())
+	}
+	return d, nil
+}
+
+// handleKey acts on a key press
+func (d *dashboard) handleKey(key string) tea.Cmd {
+	d.message = ""
+	switch key {
+	case "q", "esc", "ctrl+c":
+		return tea.Quit
+	case "tab", "shift+tab":
+		if d.pane == workPane {
+			d.pane = ticketPane
+		} else {
+			d.pane = workPane
+		}
+	case "up", "k":
+		d.moveCursor(-1)
+	case "down", "j":
+		d.moveCursor(1)
+	case "s", "enter":
+		if d.pane == workPane {
+			d.resumeSelected()
+		} else {
+			d.startSelectedTicket()
+		}
+		return d.refresh()
+	case "p":
+		d.pauseActive()
+		return d.refresh()
+	case "c":
+		if d.pane == workPane {
+			d.completeSelected()
+			return d.refresh()
+		}
+	case "o":
+		d.openSelected()
+	case "r":
+		d.loadingTickets = true
+		return tea.Batch(d.refresh(), d.loadTickets())
+	}
+	return nil
+}
+
+// moveCursor moves the cursor of the current pane by delta rows
+func (d *dashboard) moveCursor(delta int) {
+	if d.pane == workPane {
+		d.workCursor = clampCursor(d.workCursor+delta, len(d.work))
+	} else {
+		d.ticketCursor = clampCursor(d.ticketCursor+delta, len(d.tickets))
+	}
+}
+
+// clampCursor keeps a cursor within a list of n rows
+func clampCursor(cursor, n int) int {
+	if cursor >= n {
+		cursor = n - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
+
+// selectedWork returns the selected work, or nil when there is none
+func (d *dashboard) selectedWork() *TrackedWork {
+	if d.workCursor < len(d.work) {
+		return &d.work[d.workCursor]
+	}
+	return nil
+}
+
+// selectedTicket returns the selected ticket, or nil when there is none
+func (d *dashboard) selectedTicket() *JiraTicket {
+	if d.ticketCursor < len(d.tickets) {
+		return &d.tickets[d.ticketCursor]
+	}
+	return nil
+}
+
+// pauseActiveWork pauses the active work, if any, before other work starts
+func pauseActiveWork(now time.Time) (*TrackedWork, error) {
+	activeWork, err := getActiveWork()
+	if err != nil || activeWork == nil {
+		return nil, err
+	}
+	pauseWork(activeWork, now)
+	if err := saveTrackedWork(*activeWork); err != nil {
+		return nil, err
+	}
+	return activeWork, nil
+}
+
+// pauseActive pauses the active work
+func (d *dashboard) pauseActive() {
+	paused, err := pauseActiveWork(time.Now())
+	switch {
+	case err != nil:
+		d.message = fmt.Sprintf("Error pausing work: %v", err)
+	case paused == nil:
+		d.message = "No active work to pause."
+	default:
+		d.message = fmt.Sprintf("Paused %s.", paused.Description)
+	}
+}
+
+// resumeSelected resumes the selected paused work, pausing the active work
+func (d *dashboard) resumeSelected() {
+	work := d.selectedWork()
+	if work == nil {
+		return
+	}
+	if work.Status == "active" {
+		d.message = fmt.Sprintf("Already tracking %s.", work.Description)
+		return
+	}
+	selected := *work
+	now := time.Now()
+	if _, err := pauseActiveWork(now); err != nil {
+		d.message = fmt.Sprintf("Error pausing work: %v", err)
+		return
+	}
+	resumeWork(&selected, now)
+	if err := saveTrackedWork(selected); err != nil {
+		d.message = fmt.Sprintf("Error resuming work: %v", err)
+	} else {
+		d.message = fmt.Sprintf("Resumed %s.", selected.Description)
+	}
+	d.workCursor = 0
+}
+
+// completeSelected completes the selected work
+func (d *dashboard) completeSelected() {
+	work := d.selectedWork()
+	if work == nil {
+		return
+	}
+	selected := *work
+	completeWork(&selected, time.Now())
+	if err := saveTrackedWork(selected); err != nil {
+		d.message = fmt.Sprintf("Error completing work: %v", err)
+	} else {
+		d.message = fmt.Sprintf("Completed %s (%s).", selected.Description, formatDuration(selected.Duration()))
+	}
+}
+
+// startSelectedTicket starts work on the selected ticket, pausing the
+// active work
+func (d *dashboard) startSelectedTicket() {
+	ticket := d.selectedTicket()
+	if ticket == nil {
+		return
+	}
+	for _, work := range d.work {
+		if work.Status == "active" && work.HasTicket(ticket.Key) {
+			d.message = fmt.Sprintf("Already tracking %s.", ticket.Key)
+			return
+		}
+	}
+
+	now := time.Now()
+	if _, err := pauseActiveWork(now); err != nil {
+		d.message = fmt.Sprintf("Error pausing work: %v", err)
+		return
+	}
+	work := TrackedWork{
+		ID:          generateID(),
+		Description: ticket.Summary,
+		TicketIDs:   []string{ticket.Key},
+		StartTime:   now,
+		Status:      "active",
+	}
+	if d.cfg.GitIntegration {
+		work.Context = getWorkContext(d.cfg)
+	}
+	if err := saveTrackedWork(work); err != nil {
+		d.message = fmt.Sprintf("Error saving tracked work: %v", err)
+	} else {
+		d.message = fmt.Sprintf("Started work on %s.", ticket.Key)
+	}
+	d.pane, d.workCursor = workPane, 0
+}
+
+// openSelected opens the selected ticket, or the first ticket of the
+// selected work, in the browser
+func (d *dashboard) openSelected() {
+	var key, link string
+	if d.pane == ticketPane {
+		if ticket := d.selectedTicket(); ticket != nil {
+			key, link = ticket.Key, ticket.URL
+		}
+	} else if work := d.selectedWork(); work != nil && len(work.TicketIDs) > 0 {
+		key, link = work.TicketIDs[0], d.ticketURL(work.TicketIDs[0])
+	}
+	switch {
+	case key == "":
+		d.message = "No ticket to open."
+	case link == "":
+		d.message = fmt.Sprintf("No link to %s.", key)
+	default:
+		if err := openBrowser(link); err != nil {
+			d.message = fmt.Sprintf("Error opening %s: %v", key, err)
+		} else {
+			d.message = fmt.Sprintf("Opened %s.", key)
+		}
+	}
+}
+
+// ticketURL returns the address of a ticket, from the tickets listed or
+// the Jira URL
+func (d *dashboard) ticketURL(key string) string {
+	for _, ticket := range d.tickets {
+		if strings.EqualFold(ticket.Key, key) && ticket.URL != "" {
+			return ticket.URL
+		}
+	}
+	return jiraTicketURL(d.cfg, key)
+}
+
+// openBrowser opens a web address in the default browser
+func openBrowser(link string) error {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("not a web address: %s", link)
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	return cmd.Start()
+}
+
+// View draws the dashboard
+func (d *dashboard) View() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n\n", output.Heading("plannet"), time.Now().Format("Monday 2 January 15:04"))
+
+	b.WriteString(output.Heading("Current focus") + "\n")
+	d.viewFocus(&b)
+
+	b.WriteString("\n" + output.Heading("Today") + "\n")
+	if len(d.blocks) == 0 {
+		b.WriteString("  No commits yet today.\n")
+	}
+	for _, block := range d.blocks {
+		fmt.Fprintf(&b, "  %s - %s  %s\n", block.StartTime.Format("15:04"), block.EndTime.Format("15:04"), block.Focus)
+	}
+
+	b.WriteString("\n" + d.paneTitle(workPane, "Work") + "\n")
+	d.viewWork(&b)
+
+	b.WriteString("\n" + d.paneTitle(ticketPane, "My tickets") + "\n")
+	d.viewTickets(&b)
+
+	if d.message != "" {
+		b.WriteString("\n" + d.message + "\n")
+	}
+	b.WriteString("\ntab switch · ↑/↓ move · s start/resume · p pause · c complete · o open ticket · r refresh · q quit\n")
+	return b.String()
+}
+
+// viewFocus draws the current focus: the active work and the branch
+func (d *dashboard) viewFocus(b *strings.Builder) {
+	if active := d.focus.Active; active != nil {
+		fmt.Fprintf(b, "  Tracking: %s (%s)", active.Description, formatDuration(active.Duration()))
+		if len(active.TicketIDs) > 0 {
+			fmt.Fprintf(b, "  %s", output.Ticket(strings.Join(active.TicketIDs, ", ")))
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString("  Not tracking any work.\n")
+	}
+	if d.focus.Branch != "" {
+		ticket := output.SideQuest("untracked work")
+		if d.focus.TicketID != "" {
+			ticket = output.Ticket(d.focus.TicketID)
+		}
+		fmt.Fprintf(b, "  Branch: %s (%s)\n", d.focus.Branch, ticket)
+	}
+	if wip := d.focus.WorkInProgress; wip != nil {
+		fmt.Fprintf(b, "  %s\n", describeWorkInProgress(d.focus.Branch, *wip))
+	}
+	if n := len(d.focus.SideQuests); n > 0 {
+		fmt.Fprintf(b, "  %s\n", output.SideQuest(fmt.Sprintf("%d side quest(s) in recent commits", n)))
+	}
+}
+
+// paneTitle returns the title of a pane, marked when keys act on it
+func (d *dashboard) paneTitle(pane dashboardPane, title string) string {
+	if d.pane == pane {
+		return output.Heading("▸ " + title)
+	}
+	return "  " + title
+}
+
+// viewWork draws the active and paused work
+func (d *dashboard) viewWork(b *strings.Builder) {
+	if len(d.work) == 0 {
+		b.WriteString("  No active or paused work.\n")
+		return
+	}
+	table := output.NewTable(
+		output.Column{},
+		output.Column{Heading: "description", Shrink: true},
+		output.Column{Heading: "ticket", Style: output.Ticket},
+		output.Column{Heading: "duration"},
+		output.Column{Heading: "status"},
+	)
+	for i, work := range d.work {
+		table.AddRow(d.cursorMark(workPane, i), work.Description, strings.Join(work.TicketIDs, ", "), formatDuration(work.Duration()), work.Status)
+	}
+	table.Render(b, d.width)
+}
+
+// viewTickets draws the tickets assigned to the user
+func (d *dashboard) viewTickets(b *strings.Builder) {
+	switch {
+	case d.loadingTickets && len(d.tickets) == 0:
+		b.WriteString("  Loading...\n")
+		return
+	case d.ticketsErr != nil:
+		fmt.Fprintf(b, "  %s\n", output.Warning(fmt.Sprintf("Could not list your tickets: %v", d.ticketsErr)))
+		return
+	case len(d.tickets) == 0:
+		b.WriteString("  No tickets assigned to you.\n")
+		return
+	}
+	table := output.NewTable(
+		output.Column{},
+		output.Column{Heading: "key", Style: output.Ticket},
+		output.Column{Heading: "status"},
+		output.Column{Heading: "summary", Shrink: true},
+	)
+	for i, ticket := range d.tickets {
+		table.AddRow(d.cursorMark(ticketPane, i), ticket.Key, ticket.Status, ticket.Summary)
+	}
+	table.Render(b, d.width)
+}
+
+// cursorMark returns the mark of a row: an arrow on the selected row of
+// the pane keys act on
+func (d *dashboard) cursorMark(pane dashboardPane, row int) string {
+	cursor := d.workCursor
+	if pane == ticketPane {
+		cursor = d.ticketCursor
+	}
+	if d.pane == pane && row == cursor {
+		return "▸"
+	}
+	return " "
+}
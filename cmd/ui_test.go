@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/plannet-ai/plannet/config"
+)
+
+// updateDashboard sends a message to the dashboard and applies the refresh
+// it asks for, as the program would
+func updateDashboard(t *testing.T, d *dashboard, msg tea.Msg) {
+	t.Helper()
+	if _, cmd := d.Update(msg); cmd != nil {
+		d.Update(cmd())
+	}
+}
+
+func TestDashboardActions(t *testing.T) {
+	_, cleanup := setupTest(t)
+	defer cleanup()
+
+	start := time.Now().Add(-2 * time.Hour)
+	for _, work := range []TrackedWork{
+		{ID: "work-1", Description: "Fix login", TicketIDs: []string{"JIRA-1"}, StartTime: start, Status: "active"},
+		{ID: "work-2", Description: "Review", StartTime: start.Add(-time.Hour), Status: "paused", Pauses: []PauseInterval{{Start: start}}},
+	} {
+		if err := saveTrackedWork(work); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := newDashboard(context.Background(), &config.Config{})
+	d.Update(d.refresh()())
+	if len(d.work) != 2 || d.work[0].ID != "work-1" {
+		t.Fatalf("dashboard work = %+v, want the active work first", d.work)
+	}
+	if view := d.View(); !strings.Contains(view, "Tracking: Fix login") || !strings.Contains(view, "Review") {
+		t.Errorf("View() =\n%s", view)
+	}
+
+	// Resuming the paused work pauses the active work
+	updateDashboard(t, d, tea.KeyMsg{Type: tea.KeyDown})
+	updateDashboard(t, d, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if active, _ := getActiveWork(); active == nil || active.ID != "work-2" {
+		t.Fatalf("active work after resuming = %+v, want work-2", active)
+	}
+	if d.work[0].ID != "work-2" || d.work[1].Status != "paused" {
+		t.Errorf("dashboard work after resuming = %+v", d.work)
+	}
+
+	// Completing the selected work takes it off the dashboard
+	updateDashboard(t, d, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if len(d.work) != 1 || d.work[0].ID != "work-1" {
+		t.Errorf("dashboard work after completing = %+v, want only work-1", d.work)
+	}
+	if !strings.Contains(d.message, "Completed Review") {
+		t.Errorf("message = %q", d.message)
+	}
+
+	// Starting work on a ticket pauses nothing when nothing is active
+	d.Update(dashboardTicketsMsg{tickets: []JiraTicket{{Key: "JIRA-7", Summary: "Add SSO"}}})
+	updateDashboard(t, d, tea.KeyMsg{Type: tea.KeyTab})
+	updateDashboard(t, d, tea.KeyMsg{Type: tea.KeyEnter})
+	active, _ := getActiveWork()
+	if active == nil || active.Description != "Add SSO" || !active.HasTicket("JIRA-7") {
+		t.Fatalf("active work after starting a ticket = %+v", active)
+	}
+	if d.pane != workPane || len(d.work) != 2 {
+		t.Errorf("pane = %v, work = %+v after starting a ticket", d.pane, d.work)
+	}
+
+	updateDashboard(t, d, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if active, _ := getActiveWork(); active != nil {
+		t.Errorf("active work after pausing = %+v, want none", active)
+	}
+}
+
+func TestDashboardTicketURL(t *testing.T) {
+	d := &dashboard{
+		cfg:     &config.Config{JiraURL: "https://example.atlassian.net/"},
+		tickets: []JiraTicket{{Key: "JIRA-1", URL: "https://tracker.example.com/JIRA-1"}},
+	}
+	if got := d.ticketURL("jira-1"); got != "https://tracker.example.com/JIRA-1" {
+		t.Errorf("ticketURL() of a listed ticket = %q", got)
+	}
+	if got := d.ticketURL("JIRA-2"); got != "https://example.atlassian.net/browse/JIRA-2" {
+		t.Errorf("ticketURL() of another ticket = %q", got)
+	}
+}
+
+func TestOpenBrowserRejectsOtherSchemes(t *testing.T) {
+	if err := openBrowser("file:///etc/passwd"); err == nil {
+		t.Error("openBrowser() of a file URL succeeded")
+	}
+}
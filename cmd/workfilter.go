@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// addWorkFilterFlags adds the flags that select tracked work by when it was
+// started, its tickets, tags and status, shared by list, report and export.
+// defaultSince is the --since of the command, such as "" for any time.
+func addWorkFilterFlags(cmd *cobra.Command, defaultSince string) {
+	cmd.Flags().String("since", defaultSince, "Only include work started since a weekday, a date, a date and time, or a duration ago")
+	cmd.Flags().String("until", "", "Only include work started before a date and time, or on or before a date")
+	cmd.Flags().StringSlice("ticket", nil, "Only include work on this ticket (can be repeated)")
+	cmd.Flags().StringSlice("tag", nil, "Only include work with this tag (can be repeated)")
+	cmd.Flags().String("status", "", "Only include work with this status (active, paused, completed)")
+	cmd.RegisterFlagCompletionFunc("tag", completeTags)
+	cmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"active", "paused", "completed"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// workFilterFromFlags returns the filter the flags of addWorkFilterFlags select
+func workFilterFromFlags(cmd *cobra.Command) (workFilter, error) {
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	status, _ := cmd.Flags().GetString("status")
+	filter, err := newWorkFilter("", status)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tickets, _ = cmd.Flags().GetStringSlice("ticket")
+	filter.Tags, _ = cmd.Flags().GetStringSlice("tag")
+
+	now := time.Now()
+	if since != "" {
+		if filter.From, err = parseSince(since, now); err != nil {
+			return filter, err
+		}
+	}
+	if until != "" {
+		if filter.To, err = parseUntil(until, now); err != nil {
+			return filter, err
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("--since must be before --until")
+	}
+	return filter, nil
+}
+
+// parseUntil parses the end of a range of work relative to now, as
+// parseSince does. A date includes the whole day.
+func parseUntil(until string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateInputLayout, strings.TrimSpace(until), now.Location()); err == nil {
+		return t.AddDate(0, 0, 1), nil
+	}
+	return parseSince(until, now)
+}
+
+// containsFold reports whether list contains any of values, ignoring case
+func containsFold(list, values []string) bool {
+	for _, item := range list {
+		for _, value := range values {
+			if strings.EqualFold(item, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWorkFilterFromFlags(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 5, d, 10, 0, 0, 0, time.Local)
+	}
+	work := []TrackedWork{
+		{ID: "1", TicketIDs: []string{"PROJ-1"}, Tags: []string{"client-a"}, StartTime: day(1), Status: "completed"},
+		{ID: "2", TicketIDs: []string{"PROJ-2"}, Tags: []string{"client-b"}, StartTime: day(10), Status: "completed"},
+		{ID: "3", TicketIDs: []string{"PROJ-1", "PROJ-3"}, StartTime: day(20), Status: "active"},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"no filters", nil, []string{"1", "2", "3"}},
+		{"since and until", []string{"--since", "2024-05-02", "--until", "2024-05-10"}, []string{"2"}},
+		{"inclusive until date", []string{"--since", "2024-05-01", "--until", "2024-05-10"}, []string{"1", "2"}},
+		{"since date", []string{"--since", "2024-05-11"}, []string{"3"}},
+		{"ticket", []string{"--ticket", "proj-1"}, []string{"1", "3"}},
+		{"tickets", []string{"--ticket", "PROJ-2,PROJ-3"}, []string{"2", "3"}},
+		{"tag", []string{"--tag", "client-b", "--tag", "client-c"}, []string{"2"}},
+		{"status", []string{"--status", "completed", "--ticket", "PROJ-1"}, []string{"1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addWorkFilterFlags(cmd, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			filter, err := workFilterFromFlags(cmd)
+			if err != nil {
+				t.Fatalf("workFilterFromFlags() returned error: %v", err)
+			}
+			var got []string
+			for _, w := range filter.apply(work) {
+				got = append(got, w.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filtered work = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, args := range [][]string{
+		{"--status", "done"},
+		{"--since", "someday"},
+		{"--since", "2024-05-10", "--until", "2024-05-01"},
+	} {
+		cmd := &cobra.Command{}
+		addWorkFilterFlags(cmd, "")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := workFilterFromFlags(cmd); err == nil {
+			t.Errorf("workFilterFromFlags(%v) returned no error", args)
+		}
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.Local)
+	tests := []struct {
+		until string
+		want  time.Time
+	}{
+		{"2024-03-10", time.Date(2024, 3, 11, 0, 0, 0, 0, time.Local)},
+		{"2024-03-10 12:00", time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)},
+		{"midnight", time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseUntil(tt.until, now)
+		if err != nil {
+			t.Errorf("parseUntil(%q) returned error: %v", tt.until, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseUntil(%q) = %v, want %v", tt.until, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/plannet-ai/plannet/config"
+	"github.com/spf13/cobra"
+)
+
+// defaultWorkspaceFile records the workspace selected with 'plannet workspace use'
+const defaultWorkspaceFile = "workspace"
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Manage workspaces for separate clients or projects",
+	Long: `Workspaces keep the tracked work, templates and settings of different
+clients apart. Each workspace has its own database and can override any
+configuration setting, such as ticket prefixes or the Jira instance. Jira
+settings and credentials are never shared between workspaces.
+
+Select a workspace for one command with --workspace, for a shell session with
+PLANNET_WORKSPACE, or by default with 'plannet workspace use':
+
+  plannet workspace create clientA --ticket-prefix ACME- --jira-url https://acme.atlassian.net
+  plannet --workspace clientA track "Sprint planning"
+  plannet workspace use clientA`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runWorkspaceList()
+	},
+}
+
+// workspaceListCmd represents the workspace list command
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workspaces",
+	Run: func(cmd *cobra.Command, args []string) {
+		runWorkspaceList()
+	},
+}
+
+// workspaceCreateCmd represents the workspace create command
+var workspaceCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a workspace",
+	Long: `Create a workspace. Settings not given here are inherited from your base
+configuration, except for Jira settings. To override other settings, edit
+config.json in the workspace directory.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		prefixes, _ := cmd.Flags().GetStringSlice("ticket-prefix")
+		jiraURL, _ := cmd.Flags().GetString("jira-url")
+		jiraUser, _ := cmd.Flags().GetString("jira-user")
+		runWorkspaceCreate(args[0], prefixes, jiraURL, jiraUser)
+	},
+}
+
+// workspaceUseCmd represents the workspace use command
+var workspaceUseCmd = &cobra.Command{
+	Use:               "use [name]",
+	Short:             "Select the default workspace",
+	Long:              `Select the workspace used when neither --workspace nor PLANNET_WORKSPACE is given.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorkspaceNames,
+	Run: func(cmd *cobra.Command, args []string) {
+		none, _ := cmd.Flags().GetBool("none")
+		runWorkspaceUse(args, none)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+	workspaceCmd.AddCommand(workspaceCreateCmd)
+	workspaceCmd.AddCommand(workspaceUseCmd)
+	supportJSONOutput(workspaceCmd)
+	supportJSONOutput(workspaceListCmd)
+
+	workspaceCreateCmd.Flags().StringSlice("ticket-prefix", nil, "Ticket prefix used in the workspace (can be repeated)")
+	workspaceCreateCmd.Flags().String("jira-url", "", "URL of the workspace's Jira instance")
+	workspaceCreateCmd.Flags().String("jira-user", "", "Jira username for the workspace")
+
+	workspaceUseCmd.Flags().Bool("none", false, "Use the base configuration by default")
+}
+
+func runWorkspaceList() {
+	names, err := config.ListWorkspaces()
+	if err != nil {
+		printError("Error listing workspaces:", err)
+		return
+	}
+
+	if jsonOutput() {
+		printJSON(workspaceOutputs(names, config.Workspace()))
+		return
+	}
+	if len(names) == 0 {
+		fmt.Println("No workspaces found. Use 'plannet workspace create' to create one.")
+		setExitCode(exitNotFound)
+		return
+	}
+
+	active := config.Workspace()
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, name)
+	}
+	if active == "" {
+		fmt.Println("Using the base configuration.")
+	}
+}
+
+// workspaceOutput is a workspace as --output json prints it
+type workspaceOutput struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// workspaceOutputs returns the workspaces as --output json prints them
+func workspaceOutputs(names []string, active string) []workspaceOutput {
+	outputs := make([]workspaceOutput, len(names))
+	for i, name := range names {
+		outputs[i] = workspaceOutput{Name: name, Active: name == active}
+	}
+	return outputs
+}
+
+func runWorkspaceCreate(name string, prefixes []string, jiraURL, jiraUser string) {
+	// Load the base configuration
+	previous := config.Workspace()
+	if err := config.SetWorkspace(""); err != nil {
+		printError("Error selecting workspace:", err)
+		return
+	}
+	defer config.SetWorkspace(previous)
+	if _, err := config.Load(); err != nil {
+		printError("Error loading configuration:", err)
+		printInfo("Run 'plannet init' to set up your configuration.")
+		return
+	}
+
+	if err := config.CreateWorkspace(name); err != nil {
+		printError("Error creating workspace:", err)
+		return
+	}
+	if err := config.SetWorkspace(name); err != nil {
+		printError("Error selecting workspace:", err)
+		return
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		printError("Error loading workspace configuration:", err)
+		return
+	}
+	if len(prefixes) > 0 {
+		cfg.TicketPrefixes = prefixes
+	}
+	cfg.JiraURL = jiraURL
+	cfg.JiraUser = jiraUser
+
+	// The Jira token is asked for rather than passed as a flag, so it stays out of shell history
+	if jiraURL != "" && isInteractive() {
+		prompt := promptui.Prompt{
+			Label: "Jira API Token",
+			Mask:  '*',
+		}
+		token, err := prompt.Run()
+		if err != nil && err != promptui.ErrAbort {
+			if err == promptui.ErrInterrupt {
+				fmt.Println("\nOperation cancelled by user.")
+				return
+			}
+			printError("Error getting Jira API token:", err)
+			return
+		}
+		cfg.JiraToken = strings.TrimSpace(token)
+	}
+
+	if err := config.Save(cfg); err != nil {
+		printError("Error saving workspace configuration:", err)
+		return
+	}
+
+	fmt.Printf("Workspace %s created in %s\n", name, config.WorkspaceDir(name))
+	fmt.Printf("Use it with 'plannet --workspace %s <command>' or 'plannet workspace use %s'.\n", name, name)
+}
+
+func runWorkspaceUse(args []string, none bool) {
+	if none == (len(args) > 0) {
+		fmt.Println("Specify either a workspace name or --none.")
+		return
+	}
+
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+		if !config.WorkspaceExists(name) {
+			fmt.Printf("Workspace %s not found. Run 'plannet workspace create %s' to create it.\n", name, name)
+			setExitCode(exitNotFound)
+			return
+		}
+	}
+
+	if err := writeDefaultWorkspace(name); err != nil {
+		printError("Error saving default workspace:", err)
+		return
+	}
+
+	if name == "" {
+		fmt.Println("Using the base configuration by default.")
+	} else {
+		fmt.Printf("Using workspace %s by default.\n", name)
+	}
+	if env := os.Getenv("PLANNET_WORKSPACE"); env != "" {
+		fmt.Printf("Note: PLANNET_WORKSPACE is set to %s, which takes precedence in this shell.\n", env)
+	}
+}
+
+// selectWorkspace selects the workspace given by the --workspace flag, the
+// PLANNET_WORKSPACE environment variable or 'plannet workspace use', in that order
+func selectWorkspace(flag string) error {
+	name := flag
+	if name == "" {
+		name = os.Getenv("PLANNET_WORKSPACE")
+	}
+	if name == "" {
+		var err error
+		if name, err = readDefaultWorkspace(); err != nil {
+			return err
+		}
+	}
+	return config.SetWorkspace(name)
+}
+
+// getDataDir returns the directory holding tracked work data, which is
+// separate for each workspace
+func getDataDir() (string, error) {
+	if name := config.Workspace(); name != "" {
+		return config.WorkspaceDir(name), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".plannet"), nil
+}
+
+// getDefaultWorkspacePath returns the path of the default workspace file
+func getDefaultWorkspacePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".plannet", defaultWorkspaceFile), nil
+}
+
+// readDefaultWorkspace returns the default workspace, or "" for the base configuration
+func readDefaultWorkspace() (string, error) {
+	path, err := getDefaultWorkspacePath()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read default workspace: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writeDefaultWorkspace records the default workspace; "" selects the base configuration
+func writeDefaultWorkspace(name string) error {
+	path, err := getDefaultWorkspacePath()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove default workspace: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create plannet directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write default workspace: %w", err)
+	}
+	return nil
+}
+
+// completeWorkspaceNames completes workspace names for shell completion
+func completeWorkspaceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names, err := config.ListWorkspaces()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
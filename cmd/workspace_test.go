@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestWorkspaceKeepsWorkSeparate(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+	defer config.SetWorkspace("")
+
+	if err := config.CreateWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to create workspace: %v", err)
+	}
+	if err := writeDefaultWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to write default workspace: %v", err)
+	}
+
+	// The flag wins over the default workspace
+	if err := selectWorkspace(""); err != nil || config.Workspace() != "clientA" {
+		t.Fatalf("Expected the default workspace clientA, got %q (%v)", config.Workspace(), err)
+	}
+	if err := selectWorkspace("other"); err != nil || config.Workspace() != "other" {
+		t.Fatalf("Expected the flag to select workspace other, got %q (%v)", config.Workspace(), err)
+	}
+	if err := selectWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to select workspace: %v", err)
+	}
+
+	work := TrackedWork{ID: "client-1", Description: "Client work", StartTime: time.Now(), Status: "active"}
+	if err := saveTrackedWork(work); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, ".plannet", "workspaces", "clientA", "db", "active.json")); err != nil {
+		t.Errorf("Expected work to be stored in the workspace: %v", err)
+	}
+
+	// The base configuration doesn't see the workspace's work
+	if err := writeDefaultWorkspace(""); err != nil {
+		t.Fatalf("Failed to clear default workspace: %v", err)
+	}
+	if err := selectWorkspace(""); err != nil {
+		t.Fatalf("Failed to select base configuration: %v", err)
+	}
+	activeWork, err := getActiveWork()
+	if err != nil {
+		t.Fatalf("Failed to get active work: %v", err)
+	}
+	if activeWork != nil {
+		t.Errorf("Expected no active work outside the workspace, got %s", activeWork.ID)
+	}
+}
+
+func TestAllWorkStaysInTheWorkspace(t *testing.T) {
+	tempDir, cleanup := setupTest(t)
+	defer cleanup()
+	defer config.SetWorkspace("")
+
+	base := TrackedWork{ID: "base-1", Description: "Base work", StartTime: time.Now(), Status: "active"}
+	if err := saveTrackedWork(base); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	if err := config.CreateWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to create workspace: %v", err)
+	}
+	if err := selectWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to select workspace: %v", err)
+	}
+	client := TrackedWork{ID: "client-1", Description: "Client work", StartTime: time.Now(), Status: "active"}
+	if err := saveTrackedWork(client); err != nil {
+		t.Fatalf("Failed to save work: %v", err)
+	}
+
+	dbs, err := listProjectDBs()
+	if err != nil {
+		t.Fatalf("Failed to list databases: %v", err)
+	}
+	baseDB := filepath.Join(tempDir, ".plannet", "db")
+	for _, db := range dbs {
+		if db.Dir == baseDB {
+			t.Errorf("Expected the base database not to be listed in a workspace, got %v", dbs)
+		}
+	}
+
+	allWork, err := getAllTrackedWork()
+	if err != nil {
+		t.Fatalf("Failed to get all tracked work: %v", err)
+	}
+	if len(allWork) != 1 || allWork[0].ID != "client-1" {
+		t.Errorf("Expected only the workspace's work, got %+v", allWork)
+	}
+}
@@ -2,9 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/plannet-ai/plannet/security"
 )
@@ -21,11 +23,559 @@ type Config struct {
 	JiraURL        string            `json:"jira_url,omitempty"`
 	JiraUser       string            `json:"jira_user,omitempty"`
 	CopyPreference CopyPreference    `json:"copy_preference,omitempty"`
+	// Color is when output is colored: "auto" (the default) in terminals
+	// unless NO_COLOR is set, "always" or "never"
+	Color string `json:"color,omitempty"`
+	// Theme sets the colors of each kind of text plannet prints
+	Theme ThemeSettings `json:"theme,omitempty"`
+	// Provider is the API the LLM is reached through: "completions",
+	// "openai", "anthropic" or "ollama". Without it, the API is told from
+	// the base URL.
+	Provider string `json:"provider,omitempty"`
+	// LLMContextTokens is the most tokens of conversation 'plannet llm' sends
+	// with each message; older messages are left out (8000 when unset)
+	LLMContextTokens int `json:"llm_context_tokens,omitempty"`
+	// LLMTimeoutSeconds is how long to wait for the LLM to start responding
+	// before the request is retried (60 when unset)
+	LLMTimeoutSeconds int `json:"llm_timeout_seconds,omitempty"`
+	// LLMMaxRetries is how many times LLM requests that fail for reasons
+	// likely to pass are retried (3 when unset, negative to never retry)
+	LLMMaxRetries int `json:"llm_max_retries,omitempty"`
+	// LLMPricing is the price of each model, by name, to estimate what LLM
+	// requests cost
+	LLMPricing map[string]LLMPrice `json:"llm_pricing,omitempty"`
+	// LLMMonthlyBudget is the most LLM requests should cost in a calendar
+	// month, in the currency of LLMPricing (0 for no budget)
+	LLMMonthlyBudget float64 `json:"llm_monthly_budget,omitempty"`
+	// LLMBudgetAction is what happens to requests once the budget is
+	// spent: "warn" (the default) or "refuse"
+	LLMBudgetAction string `json:"llm_budget_action,omitempty"`
+	// LLMRedactPatterns are regular expressions of secrets to mask in what
+	// is sent to the LLM, besides common API key formats
+	LLMRedactPatterns []string `json:"llm_redact_patterns,omitempty"`
+	// LLMFallbacks are the LLM endpoints to try, in order, when a request
+	// to the configured one fails
+	LLMFallbacks []LLMEndpoint `json:"llm_fallbacks,omitempty"`
+	// Embeddings configures the embedding model 'plannet search --semantic'
+	// compares work with
+	Embeddings EmbeddingSettings `json:"embeddings,omitempty"`
+	// JiraAPIVersion is the version of the Jira REST API to use: 3 (the
+	// default) for Jira Cloud, or 2 for Jira Server and Data Center
+	JiraAPIVersion int `json:"jira_api_version,omitempty"`
+	// JiraBoards are the IDs of the Jira boards whose active sprints
+	// 'plannet jira sprint' shows; without them, every open sprint is searched
+	JiraBoards []int `json:"jira_boards,omitempty"`
+	// JiraQueries are saved JQL searches, run by name with 'plannet jira list'
+	JiraQueries map[string]string `json:"jira_queries,omitempty"`
+	// JiraFields are the custom fields 'plannet jira create' asks for and
+	// 'plannet jira view' shows, such as Team or Story Points
+	JiraFields []JiraField `json:"jira_fields,omitempty"`
+	// JiraEpicField is the ID of the Epic Link field, such as
+	// "customfield_10014", on Jira instances that link epics through it
+	// rather than the parent field
+	JiraEpicField string `json:"jira_epic_field,omitempty"`
+	// JiraStoryPointsField is the ID of the field holding story points,
+	// "customfield_10016" unless set, which 'plannet jira sprint report'
+	// compares tracked time with
+	JiraStoryPointsField string `json:"jira_story_points_field,omitempty"`
+	// JiraHoursPerPoint converts the story points of issues without an
+	// original estimate into hours, such as 4 for half a day per point
+	JiraHoursPerPoint float64 `json:"jira_hours_per_point,omitempty"`
+	// JiraTransitions maps work statuses, such as "completed", to the status
+	// or Jira transition their tickets are offered to move to in the
+	// configured ticket system, such as "In Review"
+	JiraTransitions map[string]string `json:"jira_transitions,omitempty"`
+	// SmartCommits replays the Jira smart-commit directives of commit
+	// messages during 'plannet status' and auto-tracking: "replay" applies
+	// them, "dry-run" only shows them, and empty (the default) ignores them
+	SmartCommits string `json:"smart_commits,omitempty"`
+	// TicketSystem is the ticket system plannet works with: "jira" (the
+	// default) or another system registered in internal/systems
+	TicketSystem string `json:"ticket_system,omitempty"`
+	// GitHub configures GitHub Issues as a ticket system
+	GitHub GitHubSettings `json:"github,omitempty"`
+	// GitLab configures GitLab issues as a ticket system
+	GitLab GitLabSettings `json:"gitlab,omitempty"`
+	// Linear configures Linear as a ticket system
+	Linear LinearSettings `json:"linear,omitempty"`
+	// Asana configures Asana as a ticket system
+	Asana AsanaSettings `json:"asana,omitempty"`
+	// Trello configures Trello as a ticket system
+	Trello TrelloSettings `json:"trello,omitempty"`
+	// YouTrack configures YouTrack as a ticket system
+	YouTrack YouTrackSettings `json:"youtrack,omitempty"`
+	// ClickUp configures ClickUp as a ticket system
+	ClickUp ClickUpSettings `json:"clickup,omitempty"`
+	// Notion configures a Notion database as a ticket system
+	Notion NotionSettings `json:"notion,omitempty"`
+	// Redmine configures Redmine as a ticket system
+	Redmine RedmineSettings `json:"redmine,omitempty"`
+	// Generic configures a ticket system with a REST API described by a
+	// mapping file
+	Generic GenericSettings `json:"generic,omitempty"`
+	// SystemPlugins holds the settings passed to ticket system plugins, by
+	// plugin name. Plugins read secrets from the environment instead.
+	SystemPlugins map[string]map[string]string `json:"system_plugins,omitempty"`
+	// TicketPatterns are regular expressions matching ticket IDs that have no
+	// fixed prefix; the first capture group, if any, is the ticket ID
+	TicketPatterns []string `json:"ticket_patterns,omitempty"`
+	// SyncRemote is the git remote used to sync tracked work between devices
+	SyncRemote string `json:"sync_remote,omitempty"`
+	// ScopeByRepo keeps a separate database for each git repository
+	ScopeByRepo bool `json:"scope_by_repo,omitempty"`
+	// IdleThresholdMinutes pauses active work after this long without activity (0 disables)
+	IdleThresholdMinutes int `json:"idle_threshold_minutes,omitempty"`
+	// StaleActiveHours is how long work can stay active before it is treated as
+	// forgotten (0 for the default of 12 hours, negative to disable)
+	StaleActiveHours int `json:"stale_active_hours,omitempty"`
+	// Repositories lists git repositories included in 'plannet status --all'
+	Repositories []string `json:"repositories,omitempty"`
+	// RepositoryRoots are directories searched for git repositories to include
+	// in 'plannet status --all'
+	RepositoryRoots []string `json:"repository_roots,omitempty"`
+	// ScanAllBranches makes 'plannet status' include commits on every local
+	// branch rather than only the checked out one
+	ScanAllBranches bool `json:"scan_all_branches,omitempty"`
+	// AuthorEmails limits 'plannet status' to commits by these authors
+	AuthorEmails []string `json:"author_emails,omitempty"`
+	// GitBackend selects how repositories are read; "go-git" (the default)
+	// reads them in-process and "exec" runs the git binary
+	GitBackend string `json:"git_backend,omitempty"`
+	// CommitPrompt is the template of the prompt 'plannet commit' sends to the
+	// LLM; it can use {{.Diff}}, {{.Branch}} and {{.Ticket}}
+	CommitPrompt string `json:"commit_prompt,omitempty"`
+	// AutoTrack records tracked work automatically from commits
+	AutoTrack bool `json:"auto_track,omitempty"`
+	// Rates holds the hourly rates used to value billable work
+	Rates Rates `json:"rates,omitempty"`
+	// Confluence configures where 'plannet report --publish confluence'
+	// publishes reports
+	Confluence ConfluenceSettings `json:"confluence,omitempty"`
+	// Invoice holds the details 'plannet invoice' puts on invoices
+	Invoice InvoiceSettings `json:"invoice,omitempty"`
+	// Worklog controls how tracked time is logged to Jira
+	Worklog WorklogSettings `json:"worklog,omitempty"`
+	// Timesheet controls how 'plannet export timesheet' rounds tracked time
+	Timesheet TimesheetSettings `json:"timesheet,omitempty"`
+	// Tempo configures pushing tracked work to Tempo Timesheets
+	Tempo TempoSettings `json:"tempo,omitempty"`
+	// Toggl configures pushing tracked work to Toggl Track
+	Toggl TogglSettings `json:"toggl,omitempty"`
+	// Harvest configures pushing tracked work to Harvest
+	Harvest HarvestSettings `json:"harvest,omitempty"`
+	// Notifications control the desktop notifications plannet shows
+	Notifications NotificationSettings `json:"notifications,omitempty"`
+	// Calendar is the iCalendar file or URL whose events 'plannet plan'
+	// plans around. It is kept out of backups, as a calendar's secret
+	// address is as good as a token.
+	Calendar string `json:"calendar,omitempty"`
 	// API tokens stored in the config file
-	JiraToken string `json:"jira_token,omitempty"`
-	LLMToken  string `json:"llm_token,omitempty"`
+	JiraToken     string `json:"jira_token,omitempty"`
+	LLMToken      string `json:"llm_token,omitempty"`
+	GitHubToken   string `json:"github_token,omitempty"`
+	GitLabToken   string `json:"gitlab_token,omitempty"`
+	LinearToken   string `json:"linear_token,omitempty"`
+	AsanaToken    string `json:"asana_token,omitempty"`
+	TrelloToken   string `json:"trello_token,omitempty"`
+	GenericToken  string `json:"generic_token,omitempty"`
+	YouTrackToken string `json:"youtrack_token,omitempty"`
+	ClickUpToken  string `json:"clickup_token,omitempty"`
+	NotionToken   string `json:"notion_token,omitempty"`
+	RedmineToken  string `json:"redmine_token,omitempty"`
+	TempoToken    string `json:"tempo_token,omitempty"`
+	TogglToken    string `json:"toggl_token,omitempty"`
+	HarvestToken  string `json:"harvest_token,omitempty"`
+
+	// ticketPatterns are the TicketPatterns compiled when the configuration
+	// is loaded or saved, and wholeTicketPatterns the same anchored to match
+	// only whole ticket IDs
+	ticketPatterns      []*regexp.Regexp
+	wholeTicketPatterns []*regexp.Regexp
+}
+
+// CredentialFields returns pointers to the settings that hold credentials:
+// the API tokens, the embedding provider's token and the calendar, whose
+// secret address is as good as a token. Backups and workspaces clear and
+// copy credentials through it, so that none can be left out of one of them.
+func (c *Config) CredentialFields() []*string {
+	return []*string{
+		&c.JiraToken,
+		&c.LLMToken,
+		&c.GitHubToken,
+		&c.GitLabToken,
+		&c.LinearToken,
+		&c.AsanaToken,
+		&c.TrelloToken,
+		&c.GenericToken,
+		&c.YouTrackToken,
+		&c.ClickUpToken,
+		&c.NotionToken,
+		&c.RedmineToken,
+		&c.TempoToken,
+		&c.TogglToken,
+		&c.HarvestToken,
+		&c.Embeddings.Token,
+		&c.Calendar,
+	}
+}
+
+// CompiledTicketPatterns returns the ticket patterns compiled, as they were
+// when the configuration was last loaded or saved
+func (c *Config) CompiledTicketPatterns() []*regexp.Regexp {
+	return c.ticketPatterns
+}
+
+// MatchesTicketPattern reports whether id is, as a whole, a ticket ID of one
+// of the ticket patterns, rather than only containing one
+func (c *Config) MatchesTicketPattern(id string) bool {
+	for _, re := range c.wholeTicketPatterns {
+		if re.MatchString(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// setTicketPatterns compiles the ticket patterns of the configuration
+func (c *Config) setTicketPatterns() error {
+	patterns, err := compileTicketPatterns(c.TicketPatterns)
+	if err != nil {
+		return err
+	}
+	whole := make([]string, len(c.TicketPatterns))
+	for i, pattern := range c.TicketPatterns {
+		whole[i] = `^(?:` + pattern + `)$`
+	}
+	wholePatterns, err := compileTicketPatterns(whole)
+	if err != nil {
+		return err
+	}
+	c.ticketPatterns, c.wholeTicketPatterns = patterns, wholePatterns
+	return nil
+}
+
+// compileTicketPatterns compiles the ticket patterns
+func compileTicketPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ticket pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Rates holds hourly rates for billable work. A rate for one of the work's
+// tags takes precedence over a rate for its project, which takes precedence
+// over the default rate.
+type Rates struct {
+	Currency string             `json:"currency,omitempty"`
+	Default  float64            `json:"default,omitempty"`
+	Projects map[string]float64 `json:"projects,omitempty"`
+	Tags     map[string]float64 `json:"tags,omitempty"`
+}
+
+// ConfluenceSettings configure where reports are published in Confluence.
+// Confluence is reached with the Jira credentials, as Atlassian Cloud sites
+// share them.
+type ConfluenceSettings struct {
+	// URL is the URL of Confluence, the Jira URL followed by /wiki by default
+	URL string `json:"url,omitempty"`
+	// Space is the key of the space reports are published in
+	Space string `json:"space,omitempty"`
+	// Parent is the ID of the page reports are published under, at the top of the space if empty
+	Parent string `json:"parent,omitempty"`
+	// Title starts the title of report pages, "Work report" by default,
+	// and is followed by the dates the report covers
+	Title string `json:"title,omitempty"`
+}
+
+// InvoiceSettings hold the details 'plannet invoice' puts on invoices
+type InvoiceSettings struct {
+	// From is who the invoice is from, such as your name and address, one line each
+	From string `json:"from,omitempty"`
+	// To is who the invoice is to, such as the client's name and address
+	To string `json:"to,omitempty"`
+	// TaxPercent adds a tax line of this percentage of the subtotal (0 for none)
+	TaxPercent float64 `json:"tax_percent,omitempty"`
+	// TaxLabel names the tax line, "Tax" by default, such as "VAT"
+	TaxLabel string `json:"tax_label,omitempty"`
+	// DueDays is how many days after the invoice date payment is due (0 for no due date)
+	DueDays int `json:"due_days,omitempty"`
+	// Notes are printed at the end of the invoice, such as payment details
+	Notes string `json:"notes,omitempty"`
+}
+
+// WorklogSettings control how 'plannet jira worklog push' rounds tracked
+// time before logging it to Jira
+type WorklogSettings struct {
+	// RoundMinutes rounds logged time to a multiple of this many minutes (0 or 1 disables)
+	RoundMinutes int `json:"round_minutes,omitempty"`
+	// Rounding is "nearest" (the default), "up" or "down"
+	Rounding string `json:"rounding,omitempty"`
+}
+
+// TimesheetSettings control how 'plannet export timesheet' rounds the time
+// spent on each ticket each day
+type TimesheetSettings struct {
+	// RoundMinutes rounds time to a multiple of this many minutes (0 or 1 disables)
+	RoundMinutes int `json:"round_minutes,omitempty"`
+	// Rounding is "nearest" (the default), "up" or "down"
+	Rounding string `json:"rounding,omitempty"`
+	// MinimumMinutes is the least time recorded for a ticket on a day it was worked on
+	MinimumMinutes int `json:"minimum_minutes,omitempty"`
+}
+
+// TempoSettings configure pushing tracked work to Tempo Timesheets
+type TempoSettings struct {
+	// URL is the URL of the Tempo API, https://api.tempo.io/4 by default
+	URL string `json:"url,omitempty"`
+	// Accounts maps tags to the key of the Tempo account work with the tag is logged to
+	Accounts map[string]string `json:"accounts,omitempty"`
+	// AccountAttribute is the key of the work attribute holding the account, "_Account_" by default
+	AccountAttribute string `json:"account_attribute,omitempty"`
+	// Attributes maps tags to the work attributes set on work with the tag, by attribute key
+	Attributes map[string]map[string]string `json:"attributes,omitempty"`
+}
+
+// TogglSettings configure pushing tracked work to Toggl Track
+type TogglSettings struct {
+	// URL is the URL of the Toggl API, https://api.track.toggl.com/api/v9 by default
+	URL string `json:"url,omitempty"`
+	// Workspace is the ID of the workspace time entries are created in
+	Workspace int64 `json:"workspace,omitempty"`
+	// Prefixes maps ticket prefixes, such as "PROJ-", to the project and task of work on their tickets
+	Prefixes map[string]TimeEntryTarget `json:"prefixes,omitempty"`
+	// Tags maps tags to the project and task of work with the tag, taking precedence over Prefixes
+	Tags map[string]TimeEntryTarget `json:"tags,omitempty"`
+}
+
+// HarvestSettings configure pushing tracked work to Harvest
+type HarvestSettings struct {
+	// URL is the URL of the Harvest API, https://api.harvestapp.com/v2 by default
+	URL string `json:"url,omitempty"`
+	// AccountID is the ID of the Harvest account time entries are created in
+	AccountID string `json:"account_id,omitempty"`
+	// Prefixes maps ticket prefixes, such as "PROJ-", to the project and task of work on their tickets
+	Prefixes map[string]TimeEntryTarget `json:"prefixes,omitempty"`
+	// Tags maps tags to the project and task of work with the tag, taking precedence over Prefixes
+	Tags map[string]TimeEntryTarget `json:"tags,omitempty"`
+}
+
+// TimeEntryTarget is the project, and optionally the task, of a time
+// tracking service that tracked work is logged to
+type TimeEntryTarget struct {
+	Project int64 `json:"project"`
+	Task    int64 `json:"task,omitempty"`
+}
+
+// NotificationSettings control the desktop notifications shown when a
+// pomodoro ends, when work has been active for long, and when a sync completes
+type NotificationSettings struct {
+	// Disabled turns every desktop notification off
+	Disabled bool `json:"disabled,omitempty"`
+	// Muted turns off the notifications of some events: "pomodoro",
+	// "long_running" or "sync"
+	Muted []string `json:"muted,omitempty"`
+	// LongRunningHours is how long work can stay active without a break
+	// before a notification warns about it (0 for the default of 4 hours,
+	// negative to disable)
+	LongRunningHours int `json:"long_running_hours,omitempty"`
+}
+
+// ThemeSettings select a built-in theme and override its colors, each
+// given as words such as "bold hi-cyan"
+type ThemeSettings struct {
+	// Name is the built-in theme: "default", "high-contrast" or "monochrome"
+	Name      string `json:"name,omitempty"`
+	Heading   string `json:"heading,omitempty"`
+	Ticket    string `json:"ticket,omitempty"`
+	Warning   string `json:"warning,omitempty"`
+	SideQuest string `json:"side_quest,omitempty"`
+	Success   string `json:"success,omitempty"`
+	Prompt    string `json:"prompt,omitempty"`
+	Output    string `json:"output,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
+
+// GitHubSettings configure GitHub Issues as a ticket system
+type GitHubSettings struct {
+	// APIURL is the URL of the GitHub API, for GitHub Enterprise Server
+	// (https://api.github.com by default)
+	APIURL string `json:"api_url,omitempty"`
+	// Repo is the repository, as owner/name, of issues given by number and
+	// of new issues
+	Repo string `json:"repo,omitempty"`
+	// ClientID is the client ID of the GitHub OAuth app used to log in with
+	// the device flow instead of a personal access token
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// GitLabSettings configure GitLab issues as a ticket system
+type GitLabSettings struct {
+	// URL is the URL of the GitLab instance (https://gitlab.com by default)
+	URL string `json:"url,omitempty"`
+	// Project is the path of the project, such as group/project, of issues
+	// given by number, of new issues, and of merge requests looked up by branch
+	Project string `json:"project,omitempty"`
+}
+
+// LinearSettings configure Linear as a ticket system
+type LinearSettings struct {
+	// APIURL is the URL of the Linear API (https://api.linear.app by default)
+	APIURL string `json:"api_url,omitempty"`
+	// Team is the key of the team new issues are created in, such as "ENG"
+	Team string `json:"team,omitempty"`
+}
+
+// AsanaSettings configure Asana as a ticket system
+type AsanaSettings struct {
+	// APIURL is the URL of the Asana API (https://app.asana.com/api/1.0 by default)
+	APIURL string `json:"api_url,omitempty"`
+	// Workspace is the ID of the workspace whose tasks are listed
+	Workspace string `json:"workspace,omitempty"`
+	// Project is the ID of the project new tasks are added to; without it,
+	// they are created in the workspace
+	Project string `json:"project,omitempty"`
+}
+
+// TrelloSettings configure Trello as a ticket system
+type TrelloSettings struct {
+	// APIURL is the URL of the Trello API (https://api.trello.com/1 by default)
+	APIURL string `json:"api_url,omitempty"`
+	// APIKey is the key of the Trello Power-Up the token was authorized for
+	APIKey string `json:"api_key,omitempty"`
+	// Board is the ID of the board whose cards are listed; without it, cards
+	// on every board are
+	Board string `json:"board,omitempty"`
+	// List is the ID of the list new cards are added to
+	List string `json:"list,omitempty"`
+}
+
+// YouTrackSettings configure YouTrack as a ticket system
+type YouTrackSettings struct {
+	// URL is the URL of the YouTrack instance, such as https://acme.youtrack.cloud
+	URL string `json:"url,omitempty"`
+	// Project is the short name of the project new issues are created in
+	Project string `json:"project,omitempty"`
+	// StateField is the field commands set to change an issue's state
+	// ("State" by default)
+	StateField string `json:"state_field,omitempty"`
+}
+
+// ClickUpSettings configure ClickUp as a ticket system
+type ClickUpSettings struct {
+	// APIURL is the URL of the ClickUp API (https://api.clickup.com/api/v2 by default)
+	APIURL string `json:"api_url,omitempty"`
+	// Workspace is the ID of the workspace whose tasks are listed
+	Workspace string `json:"workspace,omitempty"`
+	// Space is the ID of the space tasks are listed from; without it, tasks
+	// in every space of the workspace are
+	Space string `json:"space,omitempty"`
+	// List is the ID of the list new tasks are created in
+	List string `json:"list,omitempty"`
+}
+
+// NotionSettings configure a Notion database as a ticket system, with the
+// names of the properties that hold each part of a ticket
+type NotionSettings struct {
+	// APIURL is the URL of the Notion API (https://api.notion.com/v1 by default)
+	APIURL string `json:"api_url,omitempty"`
+	// Database is the ID of the database whose pages are the tickets
+	Database string `json:"database,omitempty"`
+	// User is the ID of the Notion user whose pages are listed
+	User string `json:"user,omitempty"`
+	// TitleProperty holds the summary (the database's title property by default)
+	TitleProperty string `json:"title_property,omitempty"`
+	// StatusProperty is a status or select property ("Status" by default)
+	StatusProperty string `json:"status_property,omitempty"`
+	// AssigneeProperty is a person property ("Assignee" by default)
+	AssigneeProperty string `json:"assignee_property,omitempty"`
+	// DescriptionProperty is a text property holding the description, if any
+	DescriptionProperty string `json:"description_property,omitempty"`
+	// IDProperty is a unique ID property, such as TASK-12, that pages are
+	// referred to by instead of their page IDs
+	IDProperty string `json:"id_property,omitempty"`
+	// DoneStatus is the status of finished pages, which are not listed
+	// ("Done" by default)
+	DoneStatus string `json:"done_status,omitempty"`
+}
+
+// LLMEndpoint is an LLM endpoint to fall back to. Its settings are those
+// of the main LLM settings of the same names.
+type LLMEndpoint struct {
+	Provider string            `json:"provider,omitempty"`
+	BaseURL  string            `json:"base_url,omitempty"`
+	Model    string            `json:"model"`
+	Token    string            `json:"token,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+}
+
+// EmbeddingSettings configure the embedding model. Unset settings are
+// taken from the LLM settings, for providers that serve embeddings too.
+type EmbeddingSettings struct {
+	// Provider is the API embeddings are requested from: "openai" for an
+	// OpenAI-compatible /v1/embeddings, or "ollama"
+	Provider string `json:"provider,omitempty"`
+	// BaseURL is the embeddings endpoint, if not the provider's usual one
+	BaseURL string `json:"base_url,omitempty"`
+	Model   string `json:"model,omitempty"`
+	// Token authenticates with the provider, if it needs one other than
+	// the LLM token
+	Token string `json:"token,omitempty"`
+}
+
+// LLMPrice is the price of a model, per million tokens
+type LLMPrice struct {
+	// Prompt is the price of a million tokens sent to the model
+	Prompt float64 `json:"prompt"`
+	// Completion is the price of a million tokens the model generates
+	Completion float64 `json:"completion"`
+}
+
+// RedmineSettings configure Redmine as a ticket system
+type RedmineSettings struct {
+	// URL is the URL of the Redmine instance
+	URL string `json:"url,omitempty"`
+	// Project is the identifier of the project new issues are created in
+	Project string `json:"project,omitempty"`
+}
+
+// GenericSettings configure the generic REST ticket system
+type GenericSettings struct {
+	// Mapping is the path of the YAML file describing the system's API:
+	// its endpoints, authentication, search and the JSON fields of tickets
+	Mapping string `json:"mapping,omitempty"`
+}
+
+// JiraField describes a Jira field that is set when creating tickets
+type JiraField struct {
+	// Name labels the field in 'plannet jira view'
+	Name string `json:"name"`
+	// ID is the field's ID in Jira, such as "customfield_10016" or "components"
+	ID string `json:"id"`
+	// Type is how the value is sent: "text" (the default), "number",
+	// "option", "options", "labels", "components" or "user"
+	Type string `json:"type,omitempty"`
+	// Prompt is the question asked for the value (the name by default)
+	Prompt string `json:"prompt,omitempty"`
+	// Options are the values to pick from, for any type
+	Options []string `json:"options,omitempty"`
+	// Required fields can't be left empty
+	Required bool `json:"required,omitempty"`
 }
 
+// The smart_commits modes
+const (
+	SmartCommitsReplay = "replay"
+	SmartCommitsDryRun = "dry-run"
+)
+
+// JiraFieldTypes are the supported types of Jira fields
+var JiraFieldTypes = []string{"text", "number", "option", "options", "labels", "components", "user"}
+
 var (
 	// Global config instance
 	globalConfig *Config
@@ -47,6 +597,23 @@ func init() {
 	baseDir = homeDir
 }
 
+// ErrNotFound is returned by Load when there is no configuration file
+var ErrNotFound = errors.New("configuration file not found. Run 'plannet init' to create one")
+
+// LoadError is returned by Load when the configuration is missing, can't
+// be read or is invalid
+type LoadError struct {
+	Err error
+}
+
+func (e *LoadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
 // Load loads the configuration from the .plannetrc file
 func Load() (*Config, error) {
 	// If config is already loaded, return it
@@ -54,9 +621,65 @@ func Load() (*Config, error) {
 		return globalConfig, nil
 	}
 
+	config, err := load()
+	if err != nil {
+		return nil, &LoadError{Err: err}
+	}
+
+	// Store the config globally
+	globalConfig = config
+	return config, nil
+}
+
+// load reads the configuration and checks it
+func load() (*Config, error) {
+	config, err := loadBase()
+	if err != nil {
+		return nil, err
+	}
+
+	// The active workspace overrides the base configuration
+	if workspace != "" {
+		if err := applyWorkspace(config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.setTicketPatterns(); err != nil {
+		return nil, err
+	}
+	for _, field := range config.JiraFields {
+		if err := validateJiraField(field); err != nil {
+			return nil, err
+		}
+	}
+	if config.SmartCommits != "" && config.SmartCommits != SmartCommitsReplay && config.SmartCommits != SmartCommitsDryRun {
+		return nil, fmt.Errorf("invalid smart_commits %q: expected %q or %q", config.SmartCommits, SmartCommitsReplay, SmartCommitsDryRun)
+	}
+	return config, nil
+}
+
+// validateJiraField checks the definition of a Jira field
+func validateJiraField(field JiraField) error {
+	if field.Name == "" || field.ID == "" {
+		return fmt.Errorf("invalid Jira field %q: a name and an ID are required", field.Name)
+	}
+	if field.Type == "" {
+		return nil
+	}
+	for _, t := range JiraFieldTypes {
+		if field.Type == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid Jira field %q: unknown type %q", field.Name, field.Type)
+}
+
+// loadBase loads the base configuration, without any workspace overrides
+func loadBase() (*Config, error) {
 	// Check if config exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("configuration file not found. Run 'plannet init' to create one")
+		return nil, ErrNotFound
 	}
 
 	// Read the config file safely
@@ -70,14 +693,21 @@ func Load() (*Config, error) {
 	if err := json.Unmarshal(configData, config); err != nil {
 		return nil, fmt.Errorf("error parsing configuration: %w", err)
 	}
-
-	// Store the config globally
-	globalConfig = config
 	return config, nil
 }
 
-// Save saves the configuration to the .plannetrc file
+// Save saves the configuration to the .plannetrc file. While a workspace is
+// active, the settings that differ from the base configuration are saved as
+// the workspace's overrides instead.
 func Save(config *Config) error {
+	if err := config.setTicketPatterns(); err != nil {
+		return err
+	}
+
+	if workspace != "" {
+		return saveWorkspace(config)
+	}
+
 	// Convert config to JSON
 	configJSON, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
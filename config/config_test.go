@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -115,4 +117,61 @@ func TestConfigValidation(t *testing.T) {
 	if err == nil {
 		t.Error("Load should return an error for invalid JSON")
 	}
-} 
\ No newline at end of file
+} 
+func TestValidateJiraField(t *testing.T) {
+	valid := []JiraField{
+		{Name: "Story Points", ID: "customfield_10016", Type: "number"},
+		{Name: "Team", ID: "customfield_10001"},
+	}
+	for _, field := range valid {
+		if err := validateJiraField(field); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", field.Name, err)
+		}
+	}
+
+	invalid := []JiraField{
+		{Name: "Team"},
+		{ID: "customfield_10001"},
+		{Name: "Sprint", ID: "customfield_10020", Type: "sprint"},
+	}
+	for _, field := range invalid {
+		if err := validateJiraField(field); err == nil {
+			t.Errorf("Expected %+v to be invalid", field)
+		}
+	}
+}
+
+func TestCompileTicketPatterns(t *testing.T) {
+	patterns, err := compileTicketPatterns([]string{`#(\d+)`, `(?i)\bgh-\d+\b`})
+	if err != nil {
+		t.Fatalf("Expected valid patterns to compile, got %v", err)
+	}
+	if len(patterns) != 2 || !patterns[0].MatchString("#42") {
+		t.Errorf("Expected both patterns compiled in order, got %v", patterns)
+	}
+	if _, err := compileTicketPatterns([]string{`#(\d+`}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestCredentialFieldsHaveEveryToken(t *testing.T) {
+	var cfg Config
+	fields := make(map[*string]bool)
+	for _, field := range cfg.CredentialFields() {
+		fields[field] = true
+	}
+
+	value := reflect.ValueOf(&cfg).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name := value.Type().Field(i).Name
+		if !strings.HasSuffix(name, "Token") || value.Field(i).Kind() != reflect.String {
+			continue
+		}
+		if !fields[value.Field(i).Addr().Interface().(*string)] {
+			t.Errorf("Expected %s to be a credential field", name)
+		}
+	}
+	if !fields[&cfg.Embeddings.Token] || !fields[&cfg.Calendar] {
+		t.Error("Expected the embedding token and calendar to be credential fields")
+	}
+}
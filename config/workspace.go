@@ -0,0 +1,212 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// workspaceConfigFile is the name of a workspace's configuration overrides file
+const workspaceConfigFile = "config.json"
+
+// workspace is the name of the active workspace, empty for none
+var workspace string
+
+var validWorkspaceName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// SetWorkspace selects the workspace whose configuration overrides the base
+// configuration. An empty name selects the base configuration.
+func SetWorkspace(name string) error {
+	if name != "" && !validWorkspaceName.MatchString(name) {
+		return fmt.Errorf("invalid workspace name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	workspace = name
+	globalConfig = nil // Reset the global config to force a reload
+	return nil
+}
+
+// Workspace returns the name of the active workspace, or "" for none
+func Workspace() string {
+	return workspace
+}
+
+// WorkspaceDir returns the directory holding a workspace's configuration and data
+func WorkspaceDir(name string) string {
+	return filepath.Join(filepath.Dir(configPath), ".plannet", "workspaces", name)
+}
+
+// ListWorkspaces returns the names of all workspaces
+func ListWorkspaces() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(WorkspaceDir("x")))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading workspaces: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && WorkspaceExists(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// WorkspaceExists reports whether a workspace has been created
+func WorkspaceExists(name string) bool {
+	_, err := os.Stat(workspaceConfigPath(name))
+	return err == nil
+}
+
+// CreateWorkspace creates a workspace without any configuration overrides
+func CreateWorkspace(name string) error {
+	if name == "" || !validWorkspaceName.MatchString(name) {
+		return fmt.Errorf("invalid workspace name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	if WorkspaceExists(name) {
+		return fmt.Errorf("workspace %q already exists", name)
+	}
+	if err := os.MkdirAll(WorkspaceDir(name), 0755); err != nil {
+		return fmt.Errorf("error creating workspace directory: %w", err)
+	}
+	// Overrides may include credentials, so keep them private
+	if err := os.WriteFile(workspaceConfigPath(name), []byte("{}\n"), 0600); err != nil {
+		return fmt.Errorf("error writing workspace configuration: %w", err)
+	}
+	return nil
+}
+
+// workspaceConfigPath returns the path of a workspace's configuration overrides
+func workspaceConfigPath(name string) string {
+	return filepath.Join(WorkspaceDir(name), workspaceConfigFile)
+}
+
+// isolateCredentials clears the credentials inherited from the base
+// configuration, along with the settings of every ticket system and time
+// tracking service, so that one client's instances and credentials are
+// never used in another client's workspace
+func isolateCredentials(config *Config) {
+	config.JiraURL = ""
+	config.JiraUser = ""
+	config.JiraAPIVersion = 0
+	config.JiraBoards = nil
+	config.JiraQueries = nil
+	config.JiraFields = nil
+	config.JiraEpicField = ""
+	config.JiraStoryPointsField = ""
+	config.JiraHoursPerPoint = 0
+	config.JiraTransitions = nil
+	config.SmartCommits = ""
+
+	config.TicketSystem = ""
+	config.GitHub = GitHubSettings{}
+	config.GitLab = GitLabSettings{}
+	config.Linear = LinearSettings{}
+	config.Asana = AsanaSettings{}
+	config.Trello = TrelloSettings{}
+	config.YouTrack = YouTrackSettings{}
+	config.ClickUp = ClickUpSettings{}
+	config.Notion = NotionSettings{}
+	config.Redmine = RedmineSettings{}
+	config.Generic = GenericSettings{}
+	config.SystemPlugins = nil
+	config.Confluence = ConfluenceSettings{}
+	config.Tempo = TempoSettings{}
+	config.Toggl = TogglSettings{}
+	config.Harvest = HarvestSettings{}
+
+	// The LLM settings are shared, but not its credentials, and fallbacks
+	// carry their own
+	config.Headers = nil
+	config.LLMFallbacks = nil
+
+	// Every token, and the calendar, whose secret address is as good as one
+	for _, field := range config.CredentialFields() {
+		*field = ""
+	}
+}
+
+// applyWorkspace overlays the active workspace's overrides on the base configuration
+func applyWorkspace(config *Config) error {
+	data, err := os.ReadFile(workspaceConfigPath(workspace))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("workspace %q not found. Run 'plannet workspace create %s' to create it", workspace, workspace)
+		}
+		return fmt.Errorf("error reading workspace configuration: %w", err)
+	}
+
+	isolateCredentials(config)
+	if err := json.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("error parsing workspace configuration: %w", err)
+	}
+	return nil
+}
+
+// saveWorkspace saves the settings of config that differ from the base
+// configuration as the active workspace's overrides
+func saveWorkspace(config *Config) error {
+	base, err := loadBase()
+	if err != nil {
+		return err
+	}
+	isolateCredentials(base)
+
+	overrides, err := diffConfig(base, config)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(overrides, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error creating workspace configuration: %w", err)
+	}
+
+	if err := os.MkdirAll(WorkspaceDir(workspace), 0755); err != nil {
+		return fmt.Errorf("error creating workspace directory: %w", err)
+	}
+	if err := os.WriteFile(workspaceConfigPath(workspace), data, 0600); err != nil {
+		return fmt.Errorf("error writing workspace configuration: %w", err)
+	}
+
+	globalConfig = config
+	return nil
+}
+
+// diffConfig returns the JSON fields of config whose values differ from base
+func diffConfig(base, config *Config) (map[string]json.RawMessage, error) {
+	fields := func(c *Config) (map[string]json.RawMessage, error) {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return nil, fmt.Errorf("error creating configuration: %w", err)
+		}
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("error creating configuration: %w", err)
+		}
+		return m, nil
+	}
+
+	baseFields, err := fields(base)
+	if err != nil {
+		return nil, err
+	}
+	configFields, err := fields(config)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := map[string]json.RawMessage{}
+	for key, value := range configFields {
+		if !bytes.Equal(baseFields[key], value) {
+			overrides[key] = value
+		}
+	}
+	return overrides, nil
+}
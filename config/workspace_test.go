@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWorkspaceOverrides(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "plannet-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Override the config path for testing
+	originalConfigPath := configPath
+	configPath = filepath.Join(tempDir, ".plannetrc")
+	defer func() { configPath = originalConfigPath }()
+	defer SetWorkspace("")
+
+	if err := SetWorkspace("../escape"); err == nil {
+		t.Error("Expected an error for a workspace name with a path in it")
+	}
+
+	if err := CreateWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to create workspace: %v", err)
+	}
+	if err := CreateWorkspace("clientA"); err == nil {
+		t.Error("Expected an error when creating an existing workspace")
+	}
+	names, err := ListWorkspaces()
+	if err != nil {
+		t.Fatalf("Failed to list workspaces: %v", err)
+	}
+	if len(names) != 1 || names[0] != "clientA" {
+		t.Errorf("Expected workspace clientA, got %v", names)
+	}
+
+	if err := os.WriteFile(workspaceConfigPath("clientA"), []byte(`{"ticket_prefixes": ["ACME-"], "jira_url": "https://acme.example.com"}`), 0600); err != nil {
+		t.Fatalf("Failed to write workspace config: %v", err)
+	}
+	if err := SetWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to select workspace: %v", err)
+	}
+
+	newBase := func() *Config {
+		return &Config{
+			TicketPrefixes: []string{"JIRA-"},
+			Editor:         "vim",
+			JiraURL:        "https://base.example.com",
+			JiraToken:      "base-token",
+		}
+	}
+	merged := *newBase()
+	if err := applyWorkspace(&merged); err != nil {
+		t.Fatalf("Failed to apply workspace: %v", err)
+	}
+	if len(merged.TicketPrefixes) != 1 || merged.TicketPrefixes[0] != "ACME-" {
+		t.Errorf("Expected workspace ticket prefixes, got %v", merged.TicketPrefixes)
+	}
+	if merged.Editor != "vim" {
+		t.Errorf("Expected the editor to be inherited, got %q", merged.Editor)
+	}
+	if merged.JiraURL != "https://acme.example.com" || merged.JiraToken != "" {
+		t.Errorf("Expected Jira settings not to be inherited, got %q and %q", merged.JiraURL, merged.JiraToken)
+	}
+
+	base := newBase()
+	isolateCredentials(base)
+	overrides, err := diffConfig(base, &merged)
+	if err != nil {
+		t.Fatalf("Failed to diff config: %v", err)
+	}
+	if _, ok := overrides["editor"]; ok {
+		t.Error("Expected inherited settings not to be saved as overrides")
+	}
+	if _, ok := overrides["ticket_prefixes"]; !ok {
+		t.Error("Expected overridden settings to be saved")
+	}
+}
+
+func TestWorkspaceIsolatesCredentials(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "plannet-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	originalConfigPath := configPath
+	configPath = filepath.Join(tempDir, ".plannetrc")
+	defer func() { configPath = originalConfigPath }()
+	defer SetWorkspace("")
+
+	if err := CreateWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to create workspace: %v", err)
+	}
+	if err := SetWorkspace("clientA"); err != nil {
+		t.Fatalf("Failed to select workspace: %v", err)
+	}
+
+	// Every token of the base configuration is set
+	base := &Config{
+		Headers:      map[string]string{"Authorization": "Bearer secret"},
+		LLMFallbacks: []LLMEndpoint{{Model: "backup", Token: "secret"}},
+		Embeddings:   EmbeddingSettings{Model: "embed", Token: "secret"},
+		Calendar:     "https://calendar.example.com/private-secret/basic.ics",
+		TicketSystem: "github",
+		GitHub:       GitHubSettings{Repo: "acme/app"},
+		Tempo:        TempoSettings{Accounts: map[string]string{"acme": "ACME"}},
+		Model:        "shared-model",
+	}
+	value := reflect.ValueOf(base).Elem()
+	var tokens []string
+	for i := 0; i < value.NumField(); i++ {
+		if name := value.Type().Field(i).Name; strings.HasSuffix(name, "Token") && value.Field(i).Kind() == reflect.String {
+			value.Field(i).SetString("secret")
+			tokens = append(tokens, name)
+		}
+	}
+	if len(tokens) < 15 {
+		t.Fatalf("Expected the token fields of Config, found %v", tokens)
+	}
+
+	if err := applyWorkspace(base); err != nil {
+		t.Fatalf("Failed to apply workspace: %v", err)
+	}
+	for _, name := range tokens {
+		if got := value.FieldByName(name).String(); got != "" {
+			t.Errorf("Expected %s not to be inherited, got %q", name, got)
+		}
+	}
+	if base.Headers != nil || base.LLMFallbacks != nil || base.Embeddings.Token != "" || base.Calendar != "" {
+		t.Errorf("Expected LLM credentials and the calendar not to be inherited, got %+v", base)
+	}
+	if base.TicketSystem != "" || base.GitHub.Repo != "" || base.Tempo.Accounts != nil {
+		t.Errorf("Expected ticket system and time tracking settings not to be inherited, got %+v", base)
+	}
+	if base.Model != "shared-model" || base.Embeddings.Model != "embed" {
+		t.Errorf("Expected the other LLM settings to be inherited, got %q and %q", base.Model, base.Embeddings.Model)
+	}
+}
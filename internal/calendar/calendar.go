@@ -0,0 +1,175 @@
+// Package calendar reads the events of a calendar in iCalendar (.ics)
+// format, as calendar apps export and publish them, to plan around them.
+package calendar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event is an event of a calendar
+type Event struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+	// AllDay is set for events that take whole days, rather than a time
+	AllDay bool
+}
+
+// Load reads the events of a calendar from a file, or from an http(s) URL
+// such as the secret address of a Google or Outlook calendar
+func Load(ctx context.Context, source string) ([]Event, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		req, err := http.NewRequestWithContext(ctx, "GET", source, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid calendar URL: %w", err)
+		}
+		resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get calendar: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to get calendar: status %d", resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open calendar: %w", err)
+		}
+		r = file
+	}
+	defer r.Close()
+	return Parse(r)
+}
+
+// Parse reads the events of a calendar. Times without a time zone are
+// taken as local time. Recurring events are only read as their first
+// occurrence.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var event *Event
+	for _, line := range lines {
+		name, params, value := parseLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &Event{}
+		case name == "END" && value == "VEVENT" && event != nil:
+			if !event.Start.IsZero() {
+				if event.End.IsZero() {
+					event.End = event.Start
+					if event.AllDay {
+						event.End = event.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, *event)
+			}
+			event = nil
+		case event == nil:
+		case name == "SUMMARY":
+			event.Summary = unescape(value)
+		case name == "DTSTART", name == "DTEND":
+			t, allDay, err := parseTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", name, value, err)
+			}
+			if name == "DTSTART" {
+				event.Start, event.AllDay = t, allDay
+			} else {
+				event.End = t
+			}
+		}
+	}
+	return events, nil
+}
+
+// On returns the events that take place on the day of t, in the order
+// they start
+func On(events []Event, t time.Time) []Event {
+	dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	var day []Event
+	for _, event := range events {
+		if event.Start.Before(dayEnd) && event.End.After(dayStart) {
+			day = append(day, event)
+		}
+	}
+	sort.SliceStable(day, func(i, j int) bool {
+		return day[i].Start.Before(day[j].Start)
+	})
+	return day
+}
+
+// unfold returns the lines of a calendar, joining the lines that long
+// lines are folded into, which start with a space or tab
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseLine splits a line such as "DTSTART;TZID=Europe/Paris:20240501T090000"
+// into its name, parameters and value
+func parseLine(line string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if key, val, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime parses a date or date-time value, reporting whether it was a
+// date, in UTC, its TZID parameter's time zone or local time
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, time.Local)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			loc = zone
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// unescape undoes the escaping of text values
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Sprint planning\\, team A\r\n" +
+	"DTSTART;TZID=UTC:20240501T140000\r\n" +
+	"DTEND;TZID=UTC:20240501T150000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Stand\r\n" +
+	" up\r\n" +
+	"DTSTART:20240501T090000Z\r\n" +
+	"DTEND:20240501T091500Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Public holiday\r\n" +
+	"DTSTART;VALUE=DATE:20240503\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := Parse(strings.NewReader(testCalendar))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Parse() returned %d events, want 3", len(events))
+	}
+	if events[0].Summary != "Sprint planning, team A" || !events[0].End.Equal(time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse() event 1 = %+v", events[0])
+	}
+	if events[1].Summary != "Standup" || !events[1].Start.Equal(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse() event 2 = %+v, want the folded summary unfolded", events[1])
+	}
+	if !events[2].AllDay || events[2].End.Sub(events[2].Start) != 24*time.Hour {
+		t.Errorf("Parse() event 3 = %+v, want a whole day", events[2])
+	}
+
+	day := On(events, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	if len(day) != 2 || day[0].Summary != "Standup" || day[1].Summary != "Sprint planning, team A" {
+		t.Errorf("On() = %+v, want the day's two events in order", day)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	calendar := "BEGIN:VEVENT\nSUMMARY:Broken\nDTSTART:tomorrow\nEND:VEVENT\n"
+	if _, err := Parse(strings.NewReader(calendar)); err == nil {
+		t.Error("Parse() of an invalid date succeeded, want an error")
+	}
+}
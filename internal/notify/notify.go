@@ -0,0 +1,79 @@
+// Package notify shows desktop notifications with the notification command
+// of the operating system: osascript on macOS, notify-send on Linux and the
+// BSDs, and a PowerShell toast on Windows.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// ErrUnsupported is returned when no notification command is available
+var ErrUnsupported = errors.New("desktop notifications are not supported on this system")
+
+// appleScript shows a notification from the arguments of osascript, so the
+// title and message need no quoting
+const appleScript = `on run argv
+display notification (item 2 of argv) with title (item 1 of argv)
+end run`
+
+// toastScript shows a Windows toast notification. The title and message
+// are read from the environment, so they need no quoting.
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:PLANNET_NOTIFY_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:PLANNET_NOTIFY_MESSAGE)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('plannet').Show($toast)`
+
+// command is a command that shows a notification
+type command struct {
+	name string
+	args []string
+	// env is added to the environment of the command
+	env []string
+}
+
+// commandFor returns the command that shows a notification on an operating
+// system, as runtime.GOOS names it
+func commandFor(goos, title, message string) (command, bool) {
+	switch goos {
+	case "darwin":
+		return command{name: "osascript", args: []string{"-e", appleScript, title, message}}, true
+	case "windows":
+		return command{
+			name: "powershell",
+			args: []string{"-NoProfile", "-NonInteractive", "-Command", toastScript},
+			env:  []string{"PLANNET_NOTIFY_TITLE=" + title, "PLANNET_NOTIFY_MESSAGE=" + message},
+		}, true
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return command{name: "notify-send", args: []string{"--app-name=plannet", title, message}}, true
+	default:
+		return command{}, false
+	}
+}
+
+// Send shows a desktop notification. It returns ErrUnsupported when the
+// notification command of the system is not installed.
+func Send(title, message string) error {
+	cmd, ok := commandFor(runtime.GOOS, title, message)
+	if !ok {
+		return ErrUnsupported
+	}
+	if _, err := exec.LookPath(cmd.name); err != nil {
+		return ErrUnsupported
+	}
+
+	c := exec.Command(cmd.name, cmd.args...)
+	if len(cmd.env) > 0 {
+		c.Env = append(os.Environ(), cmd.env...)
+	}
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", cmd.name, err, out)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandFor(t *testing.T) {
+	title, message := `Pomodoro "done"`, "Fix login: time for a break"
+
+	cmd, ok := commandFor("darwin", title, message)
+	if !ok || cmd.name != "osascript" {
+		t.Fatalf("commandFor(darwin) = %+v, %v", cmd, ok)
+	}
+	// The title and message are passed as arguments rather than quoted in the script
+	if got := cmd.args[len(cmd.args)-2:]; !reflect.DeepEqual(got, []string{title, message}) {
+		t.Errorf("osascript arguments end with %q", got)
+	}
+
+	cmd, ok = commandFor("linux", title, message)
+	if !ok || cmd.name != "notify-send" || !reflect.DeepEqual(cmd.args[1:], []string{title, message}) {
+		t.Errorf("commandFor(linux) = %+v, %v", cmd, ok)
+	}
+
+	cmd, ok = commandFor("windows", title, message)
+	if !ok || cmd.name != "powershell" {
+		t.Fatalf("commandFor(windows) = %+v, %v", cmd, ok)
+	}
+	if strings.Contains(strings.Join(cmd.args, " "), title) {
+		t.Error("the title is part of the PowerShell script")
+	}
+	if !reflect.DeepEqual(cmd.env, []string{"PLANNET_NOTIFY_TITLE=" + title, "PLANNET_NOTIFY_MESSAGE=" + message}) {
+		t.Errorf("PowerShell environment = %q", cmd.env)
+	}
+
+	if _, ok := commandFor("plan9", title, message); ok {
+		t.Error("commandFor(plan9) found a command")
+	}
+}
@@ -0,0 +1,296 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultAsanaAPIURL is the URL of the Asana API unless configured otherwise
+const defaultAsanaAPIURL = "https://app.asana.com/api/1.0"
+
+// asanaTaskFields are the fields of a task requested from Asana
+const asanaTaskFields = "name,notes,completed,permalink_url,assignee.name,tags.name,memberships.project.gid,memberships.section.name"
+
+func init() {
+	Register(Registration{
+		Name:  "asana",
+		Title: "Asana",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewAsana(cfg)
+		},
+	})
+}
+
+// Asana is Asana as a ticket system. Tickets are referred to by the ID of
+// the task, the number at the end of its URL.
+type Asana struct {
+	api       *restClient
+	workspace string
+	project   string
+}
+
+// AsanaWorkspace is a workspace the user belongs to
+type AsanaWorkspace struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+// asanaTask is a task as returned by the Asana API
+type asanaTask struct {
+	GID          string `json:"gid"`
+	Name         string `json:"name"`
+	Notes        string `json:"notes"`
+	Completed    bool   `json:"completed"`
+	PermalinkURL string `json:"permalink_url"`
+	Assignee     *struct {
+		Name string `json:"name"`
+	} `json:"assignee"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Memberships []struct {
+		Project struct {
+			GID string `json:"gid"`
+		} `json:"project"`
+		Section *struct {
+			Name string `json:"name"`
+		} `json:"section"`
+	} `json:"memberships"`
+}
+
+// NewAsana creates the Asana system, authenticated with the configured
+// personal access token or the ASANA_ACCESS_TOKEN environment variable
+func NewAsana(cfg *config.Config) (*Asana, error) {
+	token := cfg.AsanaToken
+	if token == "" {
+		token = os.Getenv("ASANA_ACCESS_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Asana token not found. Run 'plannet init' to set up Asana")
+	}
+	apiURL := cfg.Asana.APIURL
+	if apiURL == "" {
+		apiURL = defaultAsanaAPIURL
+	}
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	return &Asana{
+		api:       newRESTClient("Asana", strings.TrimSuffix(apiURL, "/"), headers, 150),
+		workspace: cfg.Asana.Workspace,
+		project:   cfg.Asana.Project,
+	}, nil
+}
+
+// Name returns "asana"
+func (a *Asana) Name() string {
+	return "asana"
+}
+
+// List returns the incomplete tasks assigned to the current user in the
+// configured workspace
+func (a *Asana) List(ctx context.Context) ([]Ticket, error) {
+	if a.workspace == "" {
+		return nil, fmt.Errorf("no Asana workspace configured; set asana.workspace in your configuration")
+	}
+	query := url.Values{
+		"assignee":        {"me"},
+		"workspace":       {a.workspace},
+		"completed_since": {"now"},
+		"opt_fields":      {asanaTaskFields},
+		"limit":           {"100"},
+	}
+	var result struct {
+		Data []asanaTask `json:"data"`
+	}
+	if err := a.api.do(ctx, "GET", "/tasks?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(result.Data))
+	for i, task := range result.Data {
+		tickets[i] = task.ticket()
+	}
+	return tickets, nil
+}
+
+// View returns a task
+func (a *Asana) View(ctx context.Context, key string) (*Ticket, error) {
+	task, err := a.task(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	ticket := task.ticket()
+	return &ticket, nil
+}
+
+// Create creates a task assigned to the current user, in the project given
+// by the ticket's project or the configured one, or else in the workspace
+func (a *Asana) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	data := map[string]interface{}{
+		"name":     ticket.Summary,
+		"assignee": "me",
+	}
+	if ticket.Description != "" {
+		data["notes"] = ticket.Description
+	}
+	project := ticket.Project
+	if project == "" {
+		project = a.project
+	}
+	switch {
+	case project != "":
+		data["projects"] = []string{project}
+	case a.workspace != "":
+		data["workspace"] = a.workspace
+	default:
+		return nil, fmt.Errorf("no project or workspace given for the new task; set asana.workspace in your configuration")
+	}
+
+	var result struct {
+		Data asanaTask `json:"data"`
+	}
+	path := "/tasks?opt_fields=" + url.QueryEscape(asanaTaskFields)
+	if err := a.api.do(ctx, "POST", path, map[string]interface{}{"data": data}, &result); err != nil {
+		return nil, err
+	}
+	created := result.Data.ticket()
+	return &created, nil
+}
+
+// Comment adds a comment to a task
+func (a *Asana) Comment(ctx context.Context, key, body string) error {
+	gid, err := parseAsanaKey(key)
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{"data": map[string]string{"text": body}}
+	return a.api.do(ctx, "POST", "/tasks/"+gid+"/stories", data, nil)
+}
+
+// Transition completes a task for "completed", "complete", "done" or
+// "closed", and marks it incomplete for "open" or "incomplete". Any other
+// status moves the task to the section of that name in its project, as on
+// a board.
+func (a *Asana) Transition(ctx context.Context, key, status string) error {
+	gid, err := parseAsanaKey(key)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "completed", "complete", "done", "closed":
+		return a.setCompleted(ctx, gid, true)
+	case "open", "incomplete", "reopen":
+		return a.setCompleted(ctx, gid, false)
+	}
+
+	task, err := a.task(ctx, gid)
+	if err != nil {
+		return err
+	}
+	if len(task.Memberships) == 0 {
+		return fmt.Errorf("Asana task %s is in no project, so it can only be completed or reopened", gid)
+	}
+	project := task.Memberships[0].Project.GID
+
+	var sections struct {
+		Data []struct {
+			GID  string `json:"gid"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := a.api.do(ctx, "GET", "/projects/"+project+"/sections", nil, &sections); err != nil {
+		return err
+	}
+	var names []string
+	for _, section := range sections.Data {
+		if strings.EqualFold(section.Name, strings.TrimSpace(status)) {
+			data := map[string]interface{}{"data": map[string]string{"task": gid}}
+			return a.api.do(ctx, "POST", "/sections/"+section.GID+"/addTask", data, nil)
+		}
+		names = append(names, section.Name)
+	}
+	return fmt.Errorf("no section %q in the task's Asana project, expected completed, open, or one of: %s", status, strings.Join(names, ", "))
+}
+
+// ResolveKey returns the ID of a task, from the ID or the task's URL
+func (a *Asana) ResolveKey(key string) (string, error) {
+	return parseAsanaKey(key)
+}
+
+// Workspaces returns the workspaces the current user belongs to
+func (a *Asana) Workspaces(ctx context.Context) ([]AsanaWorkspace, error) {
+	var result struct {
+		Data []AsanaWorkspace `json:"data"`
+	}
+	if err := a.api.do(ctx, "GET", "/workspaces", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// task returns a task by key
+func (a *Asana) task(ctx context.Context, key string) (*asanaTask, error) {
+	gid, err := parseAsanaKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Data asanaTask `json:"data"`
+	}
+	if err := a.api.do(ctx, "GET", "/tasks/"+gid+"?opt_fields="+url.QueryEscape(asanaTaskFields), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// setCompleted completes or reopens a task
+func (a *Asana) setCompleted(ctx context.Context, gid string, completed bool) error {
+	data := map[string]interface{}{"data": map[string]bool{"completed": completed}}
+	return a.api.do(ctx, "PUT", "/tasks/"+gid, data, nil)
+}
+
+// parseAsanaKey returns the ID of a task key, which may be given with a
+// leading '#' or as the task's URL
+func parseAsanaKey(key string) (string, error) {
+	gid := strings.TrimSpace(key)
+	if strings.Contains(gid, "/") {
+		// Task URLs end in the task ID, optionally followed by /f
+		parts := strings.Split(strings.TrimSuffix(strings.TrimSuffix(gid, "/"), "/f"), "/")
+		gid = parts[len(parts)-1]
+	}
+	gid = strings.TrimPrefix(gid, "#")
+	if gid == "" || strings.Trim(gid, "0123456789") != "" {
+		return "", fmt.Errorf("invalid Asana task %q, expected a task ID", key)
+	}
+	return gid, nil
+}
+
+// ticket converts a task to a ticket. The status is the task's section,
+// or "completed" once it is done.
+func (t asanaTask) ticket() Ticket {
+	ticket := Ticket{
+		Key:         t.GID,
+		Summary:     t.Name,
+		Description: t.Notes,
+		Status:      "open",
+		Type:        "Task",
+		URL:         t.PermalinkURL,
+	}
+	if len(t.Memberships) > 0 && t.Memberships[0].Section != nil {
+		ticket.Status = t.Memberships[0].Section.Name
+	}
+	if t.Completed {
+		ticket.Status = "completed"
+	}
+	if t.Assignee != nil {
+		ticket.Assignee = t.Assignee.Name
+	}
+	for _, tag := range t.Tags {
+		ticket.Labels = append(ticket.Labels, tag.Name)
+	}
+	return ticket
+}
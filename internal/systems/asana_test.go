@@ -0,0 +1,101 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestAsana returns an Asana system whose API is served by handler
+func newTestAsana(t *testing.T, handler http.HandlerFunc) *Asana {
+	return newTestServer(t, handler, NewAsana, func(url string) *config.Config {
+		return &config.Config{AsanaToken: "test-token", Asana: config.AsanaSettings{APIURL: url, Workspace: "100"}}
+	})
+}
+
+func TestAsanaList(t *testing.T) {
+	asana := newTestAsana(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		query := r.URL.Query()
+		if r.URL.Path != "/tasks" || query.Get("assignee") != "me" || query.Get("workspace") != "100" || query.Get("completed_since") != "now" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"data": [{"gid": "1201", "name": "Write docs", "permalink_url": "https://app.asana.com/0/0/1201",
+			"assignee": {"name": "Sam"}, "tags": [{"name": "docs"}],
+			"memberships": [{"project": {"gid": "300"}, "section": {"name": "Doing"}}]}]}`))
+	})
+
+	tickets, err := asana.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	want := []Ticket{{Key: "1201", Summary: "Write docs", Status: "Doing", Type: "Task", Assignee: "Sam",
+		URL: "https://app.asana.com/0/0/1201", Labels: []string{"docs"}}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+}
+
+func TestAsanaTransition(t *testing.T) {
+	var requests []string
+	asana := newTestAsana(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(data))
+		switch r.URL.Path {
+		case "/tasks/1201":
+			w.Write([]byte(`{"data": {"gid": "1201", "memberships": [{"project": {"gid": "300"}}]}}`))
+		case "/projects/300/sections":
+			w.Write([]byte(`{"data": [{"gid": "401", "name": "To do"}, {"gid": "402", "name": "In review"}]}`))
+		default:
+			w.Write([]byte(`{"data": {}}`))
+		}
+	})
+	ctx := context.Background()
+
+	if err := asana.Transition(ctx, "1201", "Done"); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if err := asana.Transition(ctx, "https://app.asana.com/0/300/1201/f", "in review"); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	want := []string{
+		`PUT /tasks/1201 {"data":{"completed":true}}`,
+		`GET /tasks/1201 null`,
+		`GET /projects/300/sections null`,
+		`POST /sections/402/addTask {"data":{"task":"1201"}}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests = %v, want %v", requests, want)
+	}
+
+	err := asana.Transition(ctx, "1201", "Blocked")
+	if err == nil || !strings.Contains(err.Error(), "To do, In review") {
+		t.Errorf("Expected an error listing the sections, got %v", err)
+	}
+}
+
+func TestParseAsanaKey(t *testing.T) {
+	tests := map[string]string{
+		"1201":                               "1201",
+		"#1201":                              "1201",
+		"https://app.asana.com/0/300/1201":   "1201",
+		"https://app.asana.com/0/300/1201/f": "1201",
+	}
+	for key, want := range tests {
+		if got, err := parseAsanaKey(key); err != nil || got != want {
+			t.Errorf("parseAsanaKey(%q) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+	if _, err := parseAsanaKey("ENG-12"); err == nil {
+		t.Error("Expected an error for a key that is not a task ID")
+	}
+}
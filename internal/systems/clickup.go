@@ -0,0 +1,331 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultClickUpAPIURL is the URL of the ClickUp API unless configured otherwise
+const defaultClickUpAPIURL = "https://api.clickup.com/api/v2"
+
+func init() {
+	Register(Registration{
+		Name:  "clickup",
+		Title: "ClickUp",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewClickUp(cfg)
+		},
+	})
+}
+
+// ClickUp is ClickUp as a ticket system. Tasks are referred to by their ID,
+// such as 86a1b2c3d, or their custom ID, such as DEV-42, where the
+// workspace has custom task IDs.
+type ClickUp struct {
+	api       *restClient
+	workspace string
+	space     string
+	list      string
+}
+
+// ClickUpItem is a workspace, space or list the user can choose from
+type ClickUpItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// clickupTask is a task as returned by the ClickUp API
+type clickupTask struct {
+	ID          string  `json:"id"`
+	CustomID    *string `json:"custom_id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	URL         string  `json:"url"`
+	Status      struct {
+		Status string `json:"status"`
+	} `json:"status"`
+	Assignees []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	List struct {
+		ID string `json:"id"`
+	} `json:"list"`
+}
+
+// NewClickUp creates the ClickUp system, authenticated with the configured
+// personal API token or the CLICKUP_TOKEN environment variable
+func NewClickUp(cfg *config.Config) (*ClickUp, error) {
+	token := cfg.ClickUpToken
+	if token == "" {
+		token = os.Getenv("CLICKUP_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("ClickUp token not found. Run 'plannet init' to set up ClickUp")
+	}
+	apiURL := cfg.ClickUp.APIURL
+	if apiURL == "" {
+		apiURL = defaultClickUpAPIURL
+	}
+	// Personal tokens are sent as they are, without a scheme
+	headers := map[string]string{"Authorization": token}
+	return &ClickUp{
+		api:       newRESTClient("ClickUp", strings.TrimSuffix(apiURL, "/"), headers, 100),
+		workspace: cfg.ClickUp.Workspace,
+		space:     cfg.ClickUp.Space,
+		list:      cfg.ClickUp.List,
+	}, nil
+}
+
+// Name returns "clickup"
+func (c *ClickUp) Name() string {
+	return "clickup"
+}
+
+// List returns the open tasks assigned to the current user in the
+// configured workspace, and space if one is configured
+func (c *ClickUp) List(ctx context.Context) ([]Ticket, error) {
+	if c.workspace == "" {
+		return nil, fmt.Errorf("no ClickUp workspace configured; set clickup.workspace in your configuration")
+	}
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"assignees[]": {userID},
+		"subtasks":    {"true"},
+	}
+	if c.space != "" {
+		query.Set("space_ids[]", c.space)
+	}
+	var result struct {
+		Tasks []clickupTask `json:"tasks"`
+	}
+	if err := c.api.do(ctx, "GET", "/team/"+c.workspace+"/task?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(result.Tasks))
+	for i, task := range result.Tasks {
+		tickets[i] = task.ticket()
+	}
+	return tickets, nil
+}
+
+// View returns a task
+func (c *ClickUp) View(ctx context.Context, key string) (*Ticket, error) {
+	task, err := c.task(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	ticket := task.ticket()
+	return &ticket, nil
+}
+
+// Create creates a task assigned to the current user in the list given by
+// the ticket's project, or the configured list
+func (c *ClickUp) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	list := ticket.Project
+	if list == "" {
+		list = c.list
+	}
+	if list == "" {
+		return nil, fmt.Errorf("no list given for the new task; set clickup.list in your configuration")
+	}
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"name":      ticket.Summary,
+		"assignees": []string{userID},
+	}
+	if ticket.Description != "" {
+		body["description"] = ticket.Description
+	}
+	if len(ticket.Labels) > 0 {
+		body["tags"] = ticket.Labels
+	}
+	var task clickupTask
+	if err := c.api.do(ctx, "POST", "/list/"+list+"/task", body, &task); err != nil {
+		return nil, err
+	}
+	created := task.ticket()
+	return &created, nil
+}
+
+// Comment adds a comment to a task
+func (c *ClickUp) Comment(ctx context.Context, key, body string) error {
+	path, err := c.taskPath(key, "/comment")
+	if err != nil {
+		return err
+	}
+	return c.api.do(ctx, "POST", path, map[string]string{"comment_text": body}, nil)
+}
+
+// Transition changes a task's status to one of the statuses of its list
+func (c *ClickUp) Transition(ctx context.Context, key, status string) error {
+	task, err := c.task(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var list struct {
+		Statuses []struct {
+			Status string `json:"status"`
+		} `json:"statuses"`
+	}
+	if err := c.api.do(ctx, "GET", "/list/"+task.List.ID, nil, &list); err != nil {
+		return err
+	}
+	var names []string
+	for _, s := range list.Statuses {
+		if strings.EqualFold(s.Status, strings.TrimSpace(status)) {
+			return c.api.do(ctx, "PUT", "/task/"+task.ID, map[string]string{"status": s.Status}, nil)
+		}
+		names = append(names, s.Status)
+	}
+	return fmt.Errorf("no status %q in the task's ClickUp list, expected one of: %s", status, strings.Join(names, ", "))
+}
+
+// ResolveKey returns a task's ID or custom ID, from either one or the
+// task's URL
+func (c *ClickUp) ResolveKey(key string) (string, error) {
+	match := clickupKeyPattern.FindStringSubmatch(strings.TrimSpace(key))
+	if match == nil {
+		return "", fmt.Errorf("invalid ClickUp task %q, expected a task ID or custom ID", key)
+	}
+	if strings.Contains(match[1], "-") {
+		return strings.ToUpper(match[1]), nil
+	}
+	return match[1], nil
+}
+
+// clickupKeyPattern matches a task ID or custom ID, alone or in the task's URL
+var clickupKeyPattern = regexp.MustCompile(`^(?:https://app\.clickup\.com/t/(?:[0-9]+/)?)?#?([0-9a-z]+|[A-Za-z][A-Za-z0-9]*-[0-9]+)/?$`)
+
+// Workspaces returns the workspaces of the current user
+func (c *ClickUp) Workspaces(ctx context.Context) ([]ClickUpItem, error) {
+	var result struct {
+		Teams []ClickUpItem `json:"teams"`
+	}
+	if err := c.api.do(ctx, "GET", "/team", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Teams, nil
+}
+
+// Spaces returns the spaces of a workspace
+func (c *ClickUp) Spaces(ctx context.Context, workspace string) ([]ClickUpItem, error) {
+	var result struct {
+		Spaces []ClickUpItem `json:"spaces"`
+	}
+	if err := c.api.do(ctx, "GET", "/team/"+workspace+"/space?archived=false", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Spaces, nil
+}
+
+// Lists returns the lists of a space, those in folders named with their folder
+func (c *ClickUp) Lists(ctx context.Context, space string) ([]ClickUpItem, error) {
+	var folderless struct {
+		Lists []ClickUpItem `json:"lists"`
+	}
+	if err := c.api.do(ctx, "GET", "/space/"+space+"/list?archived=false", nil, &folderless); err != nil {
+		return nil, err
+	}
+	var folders struct {
+		Folders []struct {
+			Name  string        `json:"name"`
+			Lists []ClickUpItem `json:"lists"`
+		} `json:"folders"`
+	}
+	if err := c.api.do(ctx, "GET", "/space/"+space+"/folder?archived=false", nil, &folders); err != nil {
+		return nil, err
+	}
+
+	lists := folderless.Lists
+	for _, folder := range folders.Folders {
+		for _, list := range folder.Lists {
+			lists = append(lists, ClickUpItem{ID: list.ID, Name: folder.Name + " / " + list.Name})
+		}
+	}
+	return lists, nil
+}
+
+// userID returns the ID of the current user
+func (c *ClickUp) userID(ctx context.Context) (string, error) {
+	var result struct {
+		User struct {
+			ID int `json:"id"`
+		} `json:"user"`
+	}
+	if err := c.api.do(ctx, "GET", "/user", nil, &result); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result.User.ID), nil
+}
+
+// task returns a task by key
+func (c *ClickUp) task(ctx context.Context, key string) (*clickupTask, error) {
+	path, err := c.taskPath(key, "")
+	if err != nil {
+		return nil, err
+	}
+	var task clickupTask
+	if err := c.api.do(ctx, "GET", path, nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// taskPath returns the path of a resource of a task. Custom IDs need the
+// workspace they belong to.
+func (c *ClickUp) taskPath(key, resource string) (string, error) {
+	key, err := c.ResolveKey(key)
+	if err != nil {
+		return "", err
+	}
+	path := "/task/" + key + resource
+	if strings.Contains(key, "-") {
+		if c.workspace == "" {
+			return "", fmt.Errorf("custom task IDs need a workspace; set clickup.workspace in your configuration")
+		}
+		path += "?custom_task_ids=true&team_id=" + c.workspace
+	}
+	return path, nil
+}
+
+// ticket converts a task to a ticket, keyed by its custom ID if it has one
+func (t clickupTask) ticket() Ticket {
+	ticket := Ticket{
+		Key:         t.ID,
+		Summary:     t.Name,
+		Description: t.Description,
+		Status:      t.Status.Status,
+		Type:        "Task",
+		URL:         t.URL,
+	}
+	if t.CustomID != nil && *t.CustomID != "" {
+		ticket.Key = *t.CustomID
+	}
+	var assignees []string
+	for _, assignee := range t.Assignees {
+		assignees = append(assignees, assignee.Username)
+	}
+	ticket.Assignee = strings.Join(assignees, ", ")
+	for _, tag := range t.Tags {
+		ticket.Labels = append(ticket.Labels, tag.Name)
+	}
+	return ticket
+}
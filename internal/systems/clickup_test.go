@@ -0,0 +1,103 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestClickUp returns a ClickUp system whose API is served by handler
+func newTestClickUp(t *testing.T, handler http.HandlerFunc) *ClickUp {
+	return newTestServer(t, handler, NewClickUp, func(url string) *config.Config {
+		return &config.Config{
+			ClickUpToken: "pk_test",
+			ClickUp:      config.ClickUpSettings{APIURL: url, Workspace: "900", Space: "55"},
+		}
+	})
+}
+
+func TestClickUpList(t *testing.T) {
+	clickup := newTestClickUp(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "pk_test" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"user": {"id": 42}}`))
+		case "/team/900/task":
+			query := r.URL.Query()
+			if query.Get("assignees[]") != "42" || query.Get("space_ids[]") != "55" {
+				t.Errorf("Unexpected search %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"tasks": [{"id": "86a1b2c3d", "custom_id": "DEV-7", "name": "Fix login",
+				"status": {"status": "in progress"}, "url": "https://app.clickup.com/t/86a1b2c3d",
+				"assignees": [{"username": "sam"}], "tags": [{"name": "auth"}]}]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	})
+
+	tickets, err := clickup.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	want := []Ticket{{Key: "DEV-7", Summary: "Fix login", Status: "in progress", Type: "Task",
+		Assignee: "sam", URL: "https://app.clickup.com/t/86a1b2c3d", Labels: []string{"auth"}}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+}
+
+func TestClickUpTransition(t *testing.T) {
+	var update map[string]string
+	clickup := newTestClickUp(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/task/DEV-7":
+			if r.URL.Query().Get("custom_task_ids") != "true" || r.URL.Query().Get("team_id") != "900" {
+				t.Errorf("Expected a custom ID lookup, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"id": "86a1b2c3d", "list": {"id": "300"}}`))
+		case r.URL.Path == "/list/300":
+			w.Write([]byte(`{"statuses": [{"status": "to do"}, {"status": "in review"}]}`))
+		case r.Method == "PUT" && r.URL.Path == "/task/86a1b2c3d":
+			json.NewDecoder(r.Body).Decode(&update)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	ctx := context.Background()
+
+	if err := clickup.Transition(ctx, "dev-7", "In Review"); err != nil {
+		t.Fatalf("Failed to transition task: %v", err)
+	}
+	if update["status"] != "in review" {
+		t.Errorf("Expected the list's status name, got %v", update)
+	}
+
+	err := clickup.Transition(ctx, "DEV-7", "Blocked")
+	if err == nil || !strings.Contains(err.Error(), "to do, in review") {
+		t.Errorf("Expected an error listing the statuses, got %v", err)
+	}
+}
+
+func TestClickUpResolveKey(t *testing.T) {
+	clickup := &ClickUp{}
+	tests := map[string]string{
+		"86a1b2c3d":                           "86a1b2c3d",
+		"#86a1b2c3d":                          "86a1b2c3d",
+		"https://app.clickup.com/t/86a1b2c3d": "86a1b2c3d",
+		"https://app.clickup.com/t/900/DEV-7": "DEV-7",
+		"dev-7":                               "DEV-7",
+	}
+	for key, want := range tests {
+		if got, err := clickup.ResolveKey(key); err != nil || got != want {
+			t.Errorf("ResolveKey(%q) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+}
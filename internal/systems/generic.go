@@ -0,0 +1,372 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register(Registration{
+		Name:  "generic",
+		Title: "Another tracker with a REST API (mapping file)",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewGeneric(cfg)
+		},
+	})
+}
+
+// GenericMapping describes the REST API of a ticket system, read from the
+// YAML file of the generic.mapping setting. Endpoints are a method and a
+// path, such as "GET /issues/{{key}}", in which {{key}} is replaced by the
+// ticket's key and {{query}} by the search. Bodies are JSON in which
+// {{summary}}, {{description}}, {{project}}, {{type}}, {{labels}}, {{body}}
+// and {{status}} are replaced by their values as JSON.
+type GenericMapping struct {
+	// Name names the system in errors, such as "Tracker"
+	Name    string `yaml:"name"`
+	BaseURL string `yaml:"base_url"`
+	Auth    struct {
+		// Header is the header that authenticates requests, such as "Authorization"
+		Header string `yaml:"header"`
+		// Value is the header's value, in which {{token}} is replaced by
+		// generic_token or the PLANNET_GENERIC_TOKEN environment variable
+		Value string `yaml:"value"`
+	} `yaml:"auth"`
+	// Query is the search of the list endpoint for the user's open tickets,
+	// as a query string such as "assignee=me&state=open"
+	Query string `yaml:"query"`
+	// KeyPattern is a regular expression keys must match, if set
+	KeyPattern string `yaml:"key_pattern"`
+	// Endpoints are the endpoints of list, view, create, comment and
+	// transition; operations without one are not supported
+	Endpoints map[string]string `yaml:"endpoints"`
+	// Bodies are the request bodies of create, comment and transition
+	Bodies map[string]string `yaml:"bodies"`
+	// Fields are the paths of a ticket's fields in responses, such as
+	// "fields.status.name"; a path through an array takes the field of
+	// each element, as for labels
+	Fields struct {
+		// List is the path of the tickets in the list response, empty when
+		// the response is an array of tickets
+		List string `yaml:"list"`
+		// Item is the path of the ticket in the view and create responses,
+		// empty when the response is the ticket
+		Item        string `yaml:"item"`
+		Key         string `yaml:"key"`
+		Summary     string `yaml:"summary"`
+		Description string `yaml:"description"`
+		Status      string `yaml:"status"`
+		Type        string `yaml:"type"`
+		Assignee    string `yaml:"assignee"`
+		URL         string `yaml:"url"`
+		Labels      string `yaml:"labels"`
+	} `yaml:"fields"`
+}
+
+// defaultGenericBodies are the request bodies of operations whose body the
+// mapping leaves out
+var defaultGenericBodies = map[string]string{
+	"create":     `{"summary": {{summary}}, "description": {{description}}, "project": {{project}}, "type": {{type}}, "labels": {{labels}}}`,
+	"comment":    `{"body": {{body}}}`,
+	"transition": `{"status": {{status}}}`,
+}
+
+// genericPlaceholderPattern matches the {{placeholders}} of endpoints and bodies
+var genericPlaceholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// Generic is a ticket system whose REST API is described by a mapping file
+type Generic struct {
+	api        *restClient
+	mapping    *GenericMapping
+	keyPattern *regexp.Regexp
+}
+
+// NewGeneric creates the generic system from the mapping file of the
+// configuration
+func NewGeneric(cfg *config.Config) (*Generic, error) {
+	if cfg.Generic.Mapping == "" {
+		return nil, fmt.Errorf("no mapping file for the generic ticket system; set generic.mapping in your configuration")
+	}
+	mapping, err := LoadGenericMapping(cfg.Generic.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{}
+	if mapping.Auth.Header != "" {
+		token := cfg.GenericToken
+		if token == "" {
+			token = os.Getenv("PLANNET_GENERIC_TOKEN")
+		}
+		headers[mapping.Auth.Header] = strings.ReplaceAll(mapping.Auth.Value, "{{token}}", token)
+	}
+
+	generic := &Generic{
+		api:     newRESTClient(mapping.Name, strings.TrimSuffix(mapping.BaseURL, "/"), headers, 60),
+		mapping: mapping,
+	}
+	if mapping.KeyPattern != "" {
+		generic.keyPattern, err = regexp.Compile(mapping.KeyPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key_pattern in %s: %w", cfg.Generic.Mapping, err)
+		}
+	}
+	return generic, nil
+}
+
+// LoadGenericMapping reads a mapping file, in which a leading ~/ stands
+// for the home directory
+func LoadGenericMapping(path string) (*GenericMapping, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, rest)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	var mapping GenericMapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file %s: %w", path, err)
+	}
+	if mapping.BaseURL == "" {
+		return nil, fmt.Errorf("mapping file %s has no base_url", path)
+	}
+	if mapping.Fields.Key == "" {
+		return nil, fmt.Errorf("mapping file %s has no fields.key", path)
+	}
+	if mapping.Name == "" {
+		mapping.Name = "Ticket system"
+	}
+	return &mapping, nil
+}
+
+// Name returns "generic"
+func (g *Generic) Name() string {
+	return "generic"
+}
+
+// List returns the tickets the mapping's query finds
+func (g *Generic) List(ctx context.Context) ([]Ticket, error) {
+	var response interface{}
+	if err := g.request(ctx, "list", nil, &response); err != nil {
+		return nil, err
+	}
+	items, ok := genericLookup(response, g.mapping.Fields.List).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s list response has no array of tickets at %q", g.mapping.Name, g.mapping.Fields.List)
+	}
+	tickets := make([]Ticket, len(items))
+	for i, item := range items {
+		tickets[i] = g.ticket(item)
+	}
+	return tickets, nil
+}
+
+// View returns a ticket
+func (g *Generic) View(ctx context.Context, key string) (*Ticket, error) {
+	var response interface{}
+	if err := g.request(ctx, "view", map[string]interface{}{"key": key}, &response); err != nil {
+		return nil, err
+	}
+	ticket := g.ticket(genericLookup(response, g.mapping.Fields.Item))
+	return &ticket, nil
+}
+
+// Create creates a ticket
+func (g *Generic) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	labels := ticket.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	values := map[string]interface{}{
+		"summary":     ticket.Summary,
+		"description": ticket.Description,
+		"project":     ticket.Project,
+		"type":        ticket.Type,
+		"labels":      labels,
+	}
+	var response interface{}
+	if err := g.request(ctx, "create", values, &response); err != nil {
+		return nil, err
+	}
+	created := g.ticket(genericLookup(response, g.mapping.Fields.Item))
+	return &created, nil
+}
+
+// Comment adds a comment to a ticket
+func (g *Generic) Comment(ctx context.Context, key, body string) error {
+	return g.request(ctx, "comment", map[string]interface{}{"key": key, "body": body}, nil)
+}
+
+// Transition changes a ticket's status
+func (g *Generic) Transition(ctx context.Context, key, status string) error {
+	return g.request(ctx, "transition", map[string]interface{}{"key": key, "status": status}, nil)
+}
+
+// ResolveKey returns a key as it is, checking it against the mapping's
+// key_pattern
+func (g *Generic) ResolveKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("ticket key cannot be empty")
+	}
+	if g.keyPattern != nil && !g.keyPattern.MatchString(key) {
+		return "", fmt.Errorf("invalid %s ticket %q, expected it to match %s", g.mapping.Name, key, g.keyPattern)
+	}
+	return key, nil
+}
+
+// request sends the request of an operation, with the values of its
+// placeholders, and decodes the response into out, which may be nil
+func (g *Generic) request(ctx context.Context, operation string, values map[string]interface{}, out interface{}) error {
+	endpoint := g.mapping.Endpoints[operation]
+	if endpoint == "" {
+		return ErrNotSupported
+	}
+	method, path, ok := strings.Cut(strings.TrimSpace(endpoint), " ")
+	if !ok {
+		return fmt.Errorf("invalid %s endpoint %q, expected a method and a path", operation, endpoint)
+	}
+	method = strings.ToUpper(method)
+	path = genericPlaceholderPattern.ReplaceAllStringFunc(strings.TrimSpace(path), func(placeholder string) string {
+		switch name := genericPlaceholderPattern.FindStringSubmatch(placeholder)[1]; name {
+		case "query":
+			return g.mapping.Query
+		default:
+			value, _ := values[name].(string)
+			return url.PathEscape(value)
+		}
+	})
+
+	var body interface{}
+	if method != "GET" && method != "DELETE" {
+		template := g.mapping.Bodies[operation]
+		if template == "" {
+			template = defaultGenericBodies[operation]
+		}
+		if template != "" {
+			filled, err := fillGenericBody(template, values)
+			if err != nil {
+				return fmt.Errorf("invalid %s body in the mapping file: %w", operation, err)
+			}
+			body = filled
+		}
+	}
+	return g.api.do(ctx, method, path, body, out)
+}
+
+// fillGenericBody replaces the placeholders of a body with their values as
+// JSON, and checks that the result is JSON
+func fillGenericBody(template string, values map[string]interface{}) (json.RawMessage, error) {
+	var err error
+	filled := genericPlaceholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := genericPlaceholderPattern.FindStringSubmatch(placeholder)[1]
+		data, marshalErr := json.Marshal(values[name])
+		if marshalErr != nil {
+			err = marshalErr
+		}
+		return string(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid([]byte(filled)) {
+		return nil, fmt.Errorf("not JSON once filled in: %s", filled)
+	}
+	return json.RawMessage(filled), nil
+}
+
+// ticket converts a ticket in a response to a Ticket, by the mapping's
+// fields; fields the mapping leaves out are empty
+func (g *Generic) ticket(item interface{}) Ticket {
+	field := func(path string) interface{} {
+		if path == "" {
+			return nil
+		}
+		return genericLookup(item, path)
+	}
+	fields := g.mapping.Fields
+	return Ticket{
+		Key:         genericString(field(fields.Key)),
+		Summary:     genericString(field(fields.Summary)),
+		Description: genericString(field(fields.Description)),
+		Status:      genericString(field(fields.Status)),
+		Type:        genericString(field(fields.Type)),
+		Assignee:    genericString(field(fields.Assignee)),
+		URL:         genericString(field(fields.URL)),
+		Labels:      genericStrings(field(fields.Labels)),
+	}
+}
+
+// genericLookup returns the value at a dotted path of decoded JSON, or the
+// value itself for an empty path. A path through an array returns the
+// values at the rest of the path in each element.
+func genericLookup(value interface{}, path string) interface{} {
+	if path == "" {
+		return value
+	}
+	name, rest, _ := strings.Cut(path, ".")
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return genericLookup(v[name], rest)
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		for _, element := range v {
+			if found := genericLookup(element, path); found != nil {
+				values = append(values, found)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// genericString returns a value of decoded JSON as a string
+func genericString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		return strings.Join(genericStrings(v), ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// genericStrings returns a value of decoded JSON as a list of strings
+func genericStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var values []string
+		for _, element := range v {
+			if s := genericString(element); s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return []string{genericString(v)}
+	}
+}
@@ -0,0 +1,123 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// testGenericMapping describes a tracker whose tickets are nested in "data"
+const testGenericMapping = `
+name: Tracker
+base_url: %s
+auth:
+  header: X-Api-Key
+  value: "key {{token}}"
+query: assignee=me&state=open
+key_pattern: '^T-[0-9]+$'
+endpoints:
+  list: GET /tickets?{{query}}
+  view: get /tickets/{{key}}
+  create: POST /tickets
+  transition: PUT /tickets/{{key}}/state
+bodies:
+  create: '{"title": {{summary}}, "queue": {{project}}, "tags": {{labels}}}'
+fields:
+  list: data
+  item: data
+  key: id
+  summary: title
+  status: state.name
+  assignee: owner.login
+  url: links.html
+  labels: tags.name
+`
+
+// newTestGeneric returns a generic system whose API is served by handler
+func newTestGeneric(t *testing.T, handler http.HandlerFunc) *Generic {
+	path := filepath.Join(t.TempDir(), "tracker.yaml")
+	return newTestServer(t, handler, NewGeneric, func(url string) *config.Config {
+		mapping := strings.Replace(testGenericMapping, "%s", url, 1)
+		if err := os.WriteFile(path, []byte(mapping), 0644); err != nil {
+			t.Fatalf("Failed to write mapping: %v", err)
+		}
+		return &config.Config{GenericToken: "secret", Generic: config.GenericSettings{Mapping: path}}
+	})
+}
+
+func TestGenericList(t *testing.T) {
+	generic := newTestGeneric(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key secret" {
+			t.Errorf("Unexpected authentication %q", r.Header.Get("X-Api-Key"))
+		}
+		if r.URL.Path != "/tickets" || r.URL.RawQuery != "assignee=me&state=open" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"data": [{"id": "T-12", "title": "Fix login", "state": {"name": "open"},
+			"owner": {"login": "sam"}, "links": {"html": "https://tracker/T-12"},
+			"tags": [{"name": "bug"}, {"name": "auth"}]}]}`))
+	})
+
+	tickets, err := generic.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list tickets: %v", err)
+	}
+	want := []Ticket{{Key: "T-12", Summary: "Fix login", Status: "open", Assignee: "sam",
+		URL: "https://tracker/T-12", Labels: []string{"bug", "auth"}}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+}
+
+func TestGenericRequests(t *testing.T) {
+	var requests []string
+	generic := newTestGeneric(t, func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(data))
+		w.Write([]byte(`{"data": {"id": 42}}`))
+	})
+	ctx := context.Background()
+
+	created, err := generic.Create(ctx, NewTicket{Project: "ops", Summary: `Say "hi"`})
+	if err != nil {
+		t.Fatalf("Failed to create ticket: %v", err)
+	}
+	if created.Key != "42" {
+		t.Errorf("Expected the numeric ID as the key, got %q", created.Key)
+	}
+	if err := generic.Transition(ctx, "T-1", "closed"); err != nil {
+		t.Fatalf("Failed to transition ticket: %v", err)
+	}
+	if err := generic.Comment(ctx, "T-1", "Done"); err != ErrNotSupported {
+		t.Errorf("Expected commenting without an endpoint to be unsupported, got %v", err)
+	}
+
+	want := []string{
+		`POST /tickets {"queue":"ops","tags":[],"title":"Say \"hi\""}`,
+		`PUT /tickets/T-1/state {"status":"closed"}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests = %v, want %v", requests, want)
+	}
+
+	if _, err := generic.ResolveKey("PROJ-1"); err == nil {
+		t.Error("Expected keys not matching key_pattern to be rejected")
+	}
+}
+
+func TestLoadGenericMappingRequiresFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracker.yaml")
+	os.WriteFile(path, []byte("base_url: https://tracker\n"), 0644)
+	if _, err := LoadGenericMapping(path); err == nil || !strings.Contains(err.Error(), "fields.key") {
+		t.Errorf("Expected an error for a mapping without fields.key, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultGitHubAPIURL is the URL of the GitHub API unless configured otherwise
+const defaultGitHubAPIURL = "https://api.github.com"
+
+func init() {
+	Register(Registration{
+		Name:  "github",
+		Title: "GitHub Issues",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewGitHub(cfg)
+		},
+	})
+}
+
+// GitHub is GitHub Issues as a ticket system. Tickets are referred to as
+// owner/repo#number, or by number alone in the configured repository.
+type GitHub struct {
+	api  *restClient
+	repo string
+}
+
+// githubIssue is an issue as returned by the GitHub API
+type githubIssue struct {
+	Number        int    `json:"number"`
+	Title         string `json:"title"`
+	Body          string `json:"body"`
+	State         string `json:"state"`
+	HTMLURL       string `json:"html_url"`
+	RepositoryURL string `json:"repository_url"`
+	Assignee      *struct {
+		Login string `json:"login"`
+	} `json:"assignee"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	// PullRequest is set for pull requests, which the issues API also returns
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+// NewGitHub creates the GitHub Issues system, authenticated with the
+// configured token or the GITHUB_TOKEN environment variable
+func NewGitHub(cfg *config.Config) (*GitHub, error) {
+	token := cfg.GitHubToken
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("GitHub token not found. Run 'plannet init' to set up GitHub Issues")
+	}
+	apiURL := cfg.GitHub.APIURL
+	if apiURL == "" {
+		apiURL = defaultGitHubAPIURL
+	}
+	headers := map[string]string{
+		"Authorization":        "Bearer " + token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	return &GitHub{
+		api:  newRESTClient("GitHub", strings.TrimSuffix(apiURL, "/"), headers, 60),
+		repo: cfg.GitHub.Repo,
+	}, nil
+}
+
+// Name returns "github"
+func (g *GitHub) Name() string {
+	return "github"
+}
+
+// List returns the open issues assigned to the current user, across every
+// repository; pull requests are left out
+func (g *GitHub) List(ctx context.Context) ([]Ticket, error) {
+	var issues []githubIssue
+	if err := g.api.do(ctx, "GET", "/issues?filter=assigned&state=open&per_page=100", nil, &issues); err != nil {
+		return nil, err
+	}
+	var tickets []Ticket
+	for _, issue := range issues {
+		if issue.PullRequest == nil {
+			tickets = append(tickets, issue.ticket())
+		}
+	}
+	return tickets, nil
+}
+
+// View returns an issue
+func (g *GitHub) View(ctx context.Context, key string) (*Ticket, error) {
+	repo, number, err := g.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var issue githubIssue
+	if err := g.api.do(ctx, "GET", fmt.Sprintf("/repos/%s/issues/%d", repo, number), nil, &issue); err != nil {
+		return nil, err
+	}
+	ticket := issue.ticket()
+	return &ticket, nil
+}
+
+// Create opens an issue in the ticket's project, as owner/repo, or in the
+// configured repository
+func (g *GitHub) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	repo := ticket.Project
+	if repo == "" {
+		repo = g.repo
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("no repository given for the new issue; set github.repo in your configuration")
+	}
+
+	body := map[string]interface{}{"title": ticket.Summary}
+	if ticket.Description != "" {
+		body["body"] = ticket.Description
+	}
+	if len(ticket.Labels) > 0 {
+		body["labels"] = ticket.Labels
+	}
+	var issue githubIssue
+	if err := g.api.do(ctx, "POST", "/repos/"+repo+"/issues", body, &issue); err != nil {
+		return nil, err
+	}
+	created := issue.ticket()
+	return &created, nil
+}
+
+// Comment adds a comment to an issue
+func (g *GitHub) Comment(ctx context.Context, key, body string) error {
+	repo, number, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+	return g.api.do(ctx, "POST", fmt.Sprintf("/repos/%s/issues/%d/comments", repo, number), map[string]string{"body": body}, nil)
+}
+
+// Transition opens or closes an issue. "closed", "done" and "completed"
+// close it as completed, "not planned" closes it as not planned, and
+// "open" reopens it.
+func (g *GitHub) Transition(ctx context.Context, key, status string) error {
+	repo, number, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+
+	var body map[string]string
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "closed", "close", "done", "completed":
+		body = map[string]string{"state": "closed", "state_reason": "completed"}
+	case "not planned", "not_planned":
+		body = map[string]string{"state": "closed", "state_reason": "not_planned"}
+	case "open", "reopen", "reopened":
+		body = map[string]string{"state": "open"}
+	default:
+		return fmt.Errorf("GitHub issues can only be open or closed, not %q", status)
+	}
+	return g.api.do(ctx, "PATCH", fmt.Sprintf("/repos/%s/issues/%d", repo, number), body, nil)
+}
+
+// ResolveKey returns an issue key as owner/repo#number
+func (g *GitHub) ResolveKey(key string) (string, error) {
+	repo, number, err := g.parseKey(key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s#%d", repo, number), nil
+}
+
+// parseKey returns the repository and number of an issue key, using the
+// configured repository for keys without one
+func (g *GitHub) parseKey(key string) (string, int, error) {
+	return parseProjectKey(key, g.repo, "GitHub", "github.repo")
+}
+
+// ticket converts an issue to a ticket
+func (i githubIssue) ticket() Ticket {
+	ticket := Ticket{
+		Key:         fmt.Sprintf("%s#%d", i.repo(), i.Number),
+		Summary:     i.Title,
+		Description: i.Body,
+		Status:      i.State,
+		Type:        "Issue",
+		URL:         i.HTMLURL,
+	}
+	if i.Assignee != nil {
+		ticket.Assignee = i.Assignee.Login
+	}
+	for _, label := range i.Labels {
+		ticket.Labels = append(ticket.Labels, label.Name)
+	}
+	return ticket
+}
+
+// repo returns the owner/name of the issue's repository
+func (i githubIssue) repo() string {
+	if _, repo, ok := strings.Cut(i.RepositoryURL, "/repos/"); ok {
+		return repo
+	}
+	return ""
+}
+
+// GitHubDeviceCode is what the user needs to authorize a device flow login
+type GitHubDeviceCode struct {
+	UserCode        string `json:"user_code"`
+	VerificationURI string `json:"verification_uri"`
+	DeviceCode      string `json:"device_code"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// GitHubDeviceLogin logs in to GitHub with the OAuth device flow of an OAuth
+// app: show is given the code the user enters at the verification URL, and
+// the access token is returned once they have authorized it. apiURL selects
+// GitHub Enterprise Server, and is empty for github.com.
+func GitHubDeviceLogin(ctx context.Context, apiURL, clientID string, show func(code GitHubDeviceCode)) (string, error) {
+	login := newRESTClient("GitHub", githubWebURL(apiURL), nil, 60)
+
+	var code GitHubDeviceCode
+	form := url.Values{"client_id": {clientID}, "scope": {"repo"}}
+	if err := login.do(ctx, "POST", "/login/device/code?"+form.Encode(), nil, &code); err != nil {
+		return "", err
+	}
+	show(code)
+
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	poll := url.Values{
+		"client_id":   {clientID},
+		"device_code": {code.DeviceCode},
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+	}
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var result struct {
+			AccessToken string `json:"access_token"`
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if err := login.do(ctx, "POST", "/login/oauth/access_token?"+poll.Encode(), nil, &result); err != nil {
+			return "", err
+		}
+		switch result.Error {
+		case "":
+			return result.AccessToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default:
+			// The OAuth endpoints report errors in the body of a 200 response
+			return "", fmt.Errorf("GitHub login failed: %s", result.Description)
+		}
+	}
+	return "", fmt.Errorf("GitHub login timed out; run 'plannet init' to try again")
+}
+
+// githubWebURL returns the URL of the GitHub web site of an API URL
+func githubWebURL(apiURL string) string {
+	if apiURL == "" || apiURL == defaultGitHubAPIURL {
+		return "https://github.com"
+	}
+	// GitHub Enterprise Server serves its API under /api/v3
+	if u, err := url.Parse(apiURL); err == nil {
+		return u.Scheme + "://" + u.Host
+	}
+	return strings.TrimSuffix(apiURL, "/")
+}
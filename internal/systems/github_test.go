@@ -0,0 +1,124 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestGitHub returns a GitHub system whose API is served by handler
+func newTestGitHub(t *testing.T, handler http.HandlerFunc) *GitHub {
+	return newTestServer(t, handler, NewGitHub, func(url string) *config.Config {
+		return &config.Config{
+			GitHubToken: "test-token",
+			GitHub:      config.GitHubSettings{APIURL: url, Repo: "octo/app"},
+		}
+	})
+}
+
+func TestGitHubList(t *testing.T) {
+	github := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/issues" || r.URL.Query().Get("filter") != "assigned" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`[
+			{"number": 42, "title": "Fix login", "state": "open", "html_url": "https://github.com/octo/app/issues/42",
+			 "repository_url": "https://api.github.com/repos/octo/app", "assignee": {"login": "sam"}, "labels": [{"name": "bug"}]},
+			{"number": 43, "title": "Add login", "state": "open", "repository_url": "https://api.github.com/repos/octo/app",
+			 "pull_request": {"url": "https://api.github.com/repos/octo/app/pulls/43"}}
+		]`))
+	})
+
+	tickets, err := github.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list issues: %v", err)
+	}
+	if len(tickets) != 1 {
+		t.Fatalf("Expected pull requests to be left out, got %d tickets", len(tickets))
+	}
+	ticket := tickets[0]
+	if ticket.Key != "octo/app#42" || ticket.Summary != "Fix login" || ticket.Assignee != "sam" || len(ticket.Labels) != 1 {
+		t.Errorf("Unexpected ticket %+v", ticket)
+	}
+}
+
+func TestGitHubUpdates(t *testing.T) {
+	var requests []string
+	github := newTestGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(data))
+		w.Write([]byte(`{"number": 7, "title": "New", "state": "open", "repository_url": "https://api.github.com/repos/octo/app"}`))
+	})
+	ctx := context.Background()
+
+	created, err := github.Create(ctx, NewTicket{Summary: "New", Description: "Details"})
+	if err != nil || created.Key != "octo/app#7" {
+		t.Fatalf("Unexpected created issue %+v, %v", created, err)
+	}
+	if err := github.Comment(ctx, "#7", "Looking into it"); err != nil {
+		t.Fatalf("Failed to comment: %v", err)
+	}
+	if err := github.Transition(ctx, "other/lib#3", "done"); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+	if err := github.Transition(ctx, "7", "In Review"); err == nil {
+		t.Error("Expected an error for a status GitHub doesn't have")
+	}
+
+	want := []string{
+		`POST /repos/octo/app/issues {"body":"Details","title":"New"}`,
+		`POST /repos/octo/app/issues/7/comments {"body":"Looking into it"}`,
+		`PATCH /repos/other/lib/issues/3 {"state":"closed","state_reason":"completed"}`,
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("Requests = %v, want %v", requests, want)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("Request %d = %s, want %s", i, requests[i], want[i])
+		}
+	}
+}
+
+func TestGitHubParseKey(t *testing.T) {
+	github := &GitHub{repo: "octo/app"}
+	tests := []struct {
+		key    string
+		repo   string
+		number int
+	}{
+		{"octo/app#42", "octo/app", 42},
+		{"other/lib#3", "other/lib", 3},
+		{"#12", "octo/app", 12},
+		{"12", "octo/app", 12},
+	}
+	for _, tt := range tests {
+		repo, number, err := github.parseKey(tt.key)
+		if err != nil || repo != tt.repo || number != tt.number {
+			t.Errorf("parseKey(%q) = %s, %d, %v", tt.key, repo, number, err)
+		}
+	}
+	if _, _, err := github.parseKey("PROJ-1"); err == nil {
+		t.Error("Expected an error for a Jira key")
+	}
+	if _, _, err := (&GitHub{}).parseKey("#12"); err == nil {
+		t.Error("Expected an error for a number without a configured repository")
+	}
+}
+
+func TestGitHubWebURL(t *testing.T) {
+	if got := githubWebURL(""); got != "https://github.com" {
+		t.Errorf("githubWebURL(\"\") = %s", got)
+	}
+	if got := githubWebURL("https://github.example.com/api/v3"); got != "https://github.example.com" {
+		t.Errorf("githubWebURL(enterprise) = %s", got)
+	}
+}
@@ -0,0 +1,227 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultGitLabURL is the URL of GitLab unless configured otherwise
+const defaultGitLabURL = "https://gitlab.com"
+
+func init() {
+	Register(Registration{
+		Name:  "gitlab",
+		Title: "GitLab",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewGitLab(cfg)
+		},
+	})
+}
+
+// GitLab is GitLab issues as a ticket system, on gitlab.com or a self-hosted
+// instance. Tickets are referred to as group/project#number, or by number
+// alone in the configured project.
+type GitLab struct {
+	api     *restClient
+	project string
+}
+
+// gitlabIssue is an issue as returned by the GitLab API
+type gitlabIssue struct {
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	WebURL      string   `json:"web_url"`
+	Labels      []string `json:"labels"`
+	IssueType   string   `json:"issue_type"`
+	Assignee    *struct {
+		Username string `json:"username"`
+	} `json:"assignee"`
+	References struct {
+		Full string `json:"full"`
+	} `json:"references"`
+}
+
+// NewGitLab creates the GitLab system, authenticated with the configured
+// token or the GITLAB_TOKEN environment variable
+func NewGitLab(cfg *config.Config) (*GitLab, error) {
+	token := cfg.GitLabToken
+	if token == "" {
+		token = os.Getenv("GITLAB_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("GitLab token not found. Run 'plannet init' to set up GitLab")
+	}
+	baseURL := cfg.GitLab.URL
+	if baseURL == "" {
+		baseURL = defaultGitLabURL
+	}
+	headers := map[string]string{"PRIVATE-TOKEN": token}
+	return &GitLab{
+		api:     newRESTClient("GitLab", strings.TrimSuffix(baseURL, "/")+"/api/v4", headers, 60),
+		project: cfg.GitLab.Project,
+	}, nil
+}
+
+// Name returns "gitlab"
+func (g *GitLab) Name() string {
+	return "gitlab"
+}
+
+// List returns the open issues assigned to the current user, across every project
+func (g *GitLab) List(ctx context.Context) ([]Ticket, error) {
+	var issues []gitlabIssue
+	if err := g.api.do(ctx, "GET", "/issues?scope=assigned_to_me&state=opened&per_page=100", nil, &issues); err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(issues))
+	for i, issue := range issues {
+		tickets[i] = issue.ticket()
+	}
+	return tickets, nil
+}
+
+// View returns an issue
+func (g *GitLab) View(ctx context.Context, key string) (*Ticket, error) {
+	project, number, err := g.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var issue gitlabIssue
+	if err := g.api.do(ctx, "GET", fmt.Sprintf("/projects/%s/issues/%d", url.PathEscape(project), number), nil, &issue); err != nil {
+		return nil, err
+	}
+	ticket := issue.ticket()
+	return &ticket, nil
+}
+
+// Create opens an issue in the ticket's project, or in the configured project
+func (g *GitLab) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	project := ticket.Project
+	if project == "" {
+		project = g.project
+	}
+	if project == "" {
+		return nil, fmt.Errorf("no project given for the new issue; set gitlab.project in your configuration")
+	}
+
+	body := map[string]interface{}{"title": ticket.Summary}
+	if ticket.Description != "" {
+		body["description"] = ticket.Description
+	}
+	if len(ticket.Labels) > 0 {
+		body["labels"] = strings.Join(ticket.Labels, ",")
+	}
+	if ticket.Type != "" {
+		body["issue_type"] = strings.ToLower(ticket.Type)
+	}
+	var issue gitlabIssue
+	if err := g.api.do(ctx, "POST", "/projects/"+url.PathEscape(project)+"/issues", body, &issue); err != nil {
+		return nil, err
+	}
+	created := issue.ticket()
+	return &created, nil
+}
+
+// Comment adds a note to an issue
+func (g *GitLab) Comment(ctx context.Context, key, body string) error {
+	project, number, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+	return g.api.do(ctx, "POST", fmt.Sprintf("/projects/%s/issues/%d/notes", url.PathEscape(project), number), map[string]string{"body": body}, nil)
+}
+
+// Transition closes or reopens an issue: "closed", "done" and "completed"
+// close it, and "open" reopens it
+func (g *GitLab) Transition(ctx context.Context, key, status string) error {
+	project, number, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+
+	var event string
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "closed", "close", "done", "completed":
+		event = "close"
+	case "open", "opened", "reopen", "reopened":
+		event = "reopen"
+	default:
+		return fmt.Errorf("GitLab issues can only be open or closed, not %q", status)
+	}
+	return g.api.do(ctx, "PUT", fmt.Sprintf("/projects/%s/issues/%d", url.PathEscape(project), number), map[string]string{"state_event": event}, nil)
+}
+
+// BranchTickets returns the issues the open merge requests of a branch, in
+// the configured project, close or mention
+func (g *GitLab) BranchTickets(ctx context.Context, branch string) ([]string, error) {
+	if g.project == "" {
+		return nil, fmt.Errorf("set gitlab.project in your configuration to look up merge requests")
+	}
+	project := url.PathEscape(g.project)
+
+	var mergeRequests []struct {
+		IID int `json:"iid"`
+	}
+	path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&source_branch=%s", project, url.QueryEscape(branch))
+	if err := g.api.do(ctx, "GET", path, nil, &mergeRequests); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, mr := range mergeRequests {
+		var issues []gitlabIssue
+		if err := g.api.do(ctx, "GET", fmt.Sprintf("/projects/%s/merge_requests/%d/closes_issues", project, mr.IID), nil, &issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if key := issue.ticket().Key; !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// ResolveKey returns an issue key as group/project#number
+func (g *GitLab) ResolveKey(key string) (string, error) {
+	project, number, err := g.parseKey(key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s#%d", project, number), nil
+}
+
+// parseKey returns the project and number of an issue key, using the
+// configured project for keys without one
+func (g *GitLab) parseKey(key string) (string, int, error) {
+	return parseProjectKey(key, g.project, "GitLab", "gitlab.project")
+}
+
+// ticket converts an issue to a ticket
+func (i gitlabIssue) ticket() Ticket {
+	ticket := Ticket{
+		Key:         i.References.Full,
+		Summary:     i.Title,
+		Description: i.Description,
+		Status:      i.State,
+		Type:        "Issue",
+		URL:         i.WebURL,
+		Labels:      i.Labels,
+	}
+	if i.IssueType != "" {
+		ticket.Type = strings.ToUpper(i.IssueType[:1]) + i.IssueType[1:]
+	}
+	if i.Assignee != nil {
+		ticket.Assignee = i.Assignee.Username
+	}
+	return ticket
+}
@@ -0,0 +1,100 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestGitLab returns a GitLab system whose API is served by handler
+func newTestGitLab(t *testing.T, handler http.HandlerFunc) *GitLab {
+	return newTestServer(t, handler, NewGitLab, func(url string) *config.Config {
+		return &config.Config{
+			GitLabToken: "test-token",
+			GitLab:      config.GitLabSettings{URL: url, Project: "group/app"},
+		}
+	})
+}
+
+func TestGitLabView(t *testing.T) {
+	gitlab := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fapp/issues/12" {
+			t.Errorf("Unexpected request %s", r.URL.EscapedPath())
+		}
+		if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+			t.Errorf("Unexpected token %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		w.Write([]byte(`{"iid": 12, "title": "Fix login", "state": "opened", "issue_type": "incident",
+			"labels": ["bug"], "assignee": {"username": "sam"}, "references": {"full": "group/app#12"}}`))
+	})
+
+	ticket, err := gitlab.View(context.Background(), "#12")
+	if err != nil {
+		t.Fatalf("Failed to view issue: %v", err)
+	}
+	want := Ticket{Key: "group/app#12", Summary: "Fix login", Status: "opened", Type: "Incident", Assignee: "sam", Labels: []string{"bug"}}
+	if !reflect.DeepEqual(*ticket, want) {
+		t.Errorf("View() = %+v, want %+v", *ticket, want)
+	}
+}
+
+func TestGitLabUpdates(t *testing.T) {
+	var requests []string
+	gitlab := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body)
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+string(data))
+		w.Write([]byte(`{"iid": 5, "references": {"full": "group/app#5"}}`))
+	})
+	ctx := context.Background()
+
+	created, err := gitlab.Create(ctx, NewTicket{Summary: "New", Labels: []string{"bug", "ui"}})
+	if err != nil || created.Key != "group/app#5" {
+		t.Fatalf("Unexpected created issue %+v, %v", created, err)
+	}
+	if err := gitlab.Comment(ctx, "group/sub/lib#3", "Looking into it"); err != nil {
+		t.Fatalf("Failed to comment: %v", err)
+	}
+	if err := gitlab.Transition(ctx, "5", "closed"); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	want := []string{
+		`POST /api/v4/projects/group%2Fapp/issues {"labels":"bug,ui","title":"New"}`,
+		`POST /api/v4/projects/group%2Fsub%2Flib/issues/3/notes {"body":"Looking into it"}`,
+		`PUT /api/v4/projects/group%2Fapp/issues/5 {"state_event":"close"}`,
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests = %v, want %v", requests, want)
+	}
+}
+
+func TestGitLabBranchTickets(t *testing.T) {
+	gitlab := newTestGitLab(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fapp/merge_requests":
+			if r.URL.Query().Get("source_branch") != "feature/login" {
+				t.Errorf("Unexpected source branch %q", r.URL.Query().Get("source_branch"))
+			}
+			w.Write([]byte(`[{"iid": 8}]`))
+		case "/api/v4/projects/group%2Fapp/merge_requests/8/closes_issues":
+			w.Write([]byte(`[{"iid": 12, "references": {"full": "group/app#12"}}, {"iid": 14, "references": {"full": "group/app#14"}}]`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.EscapedPath())
+		}
+	})
+
+	var resolver BranchResolver = gitlab
+	keys, err := resolver.BranchTickets(context.Background(), "feature/login")
+	if err != nil {
+		t.Fatalf("Failed to look up branch tickets: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"group/app#12", "group/app#14"}) {
+		t.Errorf("BranchTickets() = %v", keys)
+	}
+}
@@ -0,0 +1,309 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultLinearAPIURL is the URL of the Linear GraphQL API unless configured otherwise
+const defaultLinearAPIURL = "https://api.linear.app"
+
+func init() {
+	Register(Registration{
+		Name:  "linear",
+		Title: "Linear",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewLinear(cfg)
+		},
+	})
+}
+
+// Linear is Linear as a ticket system. Tickets are referred to by their
+// identifier, such as ENG-123, which the team keys configured as ticket
+// prefixes find in branch names and commit messages.
+type Linear struct {
+	api  *restClient
+	team string
+}
+
+// linearIssueFields are the fields of an issue queried from Linear
+const linearIssueFields = `id identifier title description url
+	state { name }
+	assignee { name }
+	labels { nodes { name } }`
+
+// linearIssue is an issue as returned by the Linear API
+type linearIssue struct {
+	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	State       struct {
+		Name string `json:"name"`
+	} `json:"state"`
+	Assignee *struct {
+		Name string `json:"name"`
+	} `json:"assignee"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// linearState is a workflow state of a Linear team
+type linearState struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // backlog, unstarted, started, completed or canceled
+}
+
+// NewLinear creates the Linear system, authenticated with the configured
+// API key or the LINEAR_API_KEY environment variable
+func NewLinear(cfg *config.Config) (*Linear, error) {
+	token := cfg.LinearToken
+	if token == "" {
+		token = os.Getenv("LINEAR_API_KEY")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Linear API key not found. Run 'plannet init' to set up Linear")
+	}
+	apiURL := cfg.Linear.APIURL
+	if apiURL == "" {
+		apiURL = defaultLinearAPIURL
+	}
+	// Personal API keys are sent as they are, without a scheme
+	headers := map[string]string{"Authorization": token}
+	return &Linear{
+		api:  newRESTClient("Linear", strings.TrimSuffix(apiURL, "/"), headers, 60),
+		team: cfg.Linear.Team,
+	}, nil
+}
+
+// Name returns "linear"
+func (l *Linear) Name() string {
+	return "linear"
+}
+
+// List returns the unfinished issues assigned to the current user
+func (l *Linear) List(ctx context.Context) ([]Ticket, error) {
+	var data struct {
+		Viewer struct {
+			AssignedIssues struct {
+				Nodes []linearIssue `json:"nodes"`
+			} `json:"assignedIssues"`
+		} `json:"viewer"`
+	}
+	query := `query {
+		viewer {
+			assignedIssues(first: 100, filter: {state: {type: {nin: ["completed", "canceled"]}}}) {
+				nodes { ` + linearIssueFields + ` }
+			}
+		}
+	}`
+	if err := l.api.graphql(ctx, "/graphql", query, nil, &data); err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(data.Viewer.AssignedIssues.Nodes))
+	for i, issue := range data.Viewer.AssignedIssues.Nodes {
+		tickets[i] = issue.ticket()
+	}
+	return tickets, nil
+}
+
+// View returns an issue by identifier
+func (l *Linear) View(ctx context.Context, key string) (*Ticket, error) {
+	issue, err := l.issue(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	ticket := issue.ticket()
+	return &ticket, nil
+}
+
+// Create creates an issue in the team whose key is the ticket's project, or
+// in the configured team
+func (l *Linear) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	team := ticket.Project
+	if team == "" {
+		team = l.team
+	}
+	if team == "" {
+		return nil, fmt.Errorf("no team given for the new issue; set linear.team in your configuration")
+	}
+
+	var teams struct {
+		Teams struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+	query := `query($key: String!) { teams(filter: {key: {eq: $key}}) { nodes { id } } }`
+	if err := l.api.graphql(ctx, "/graphql", query, map[string]interface{}{"key": strings.ToUpper(team)}, &teams); err != nil {
+		return nil, err
+	}
+	if len(teams.Teams.Nodes) == 0 {
+		return nil, fmt.Errorf("Linear team %q not found", team)
+	}
+
+	input := map[string]interface{}{
+		"teamId": teams.Teams.Nodes[0].ID,
+		"title":  ticket.Summary,
+	}
+	if ticket.Description != "" {
+		input["description"] = ticket.Description
+	}
+	var created struct {
+		IssueCreate struct {
+			Issue linearIssue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	mutation := `mutation($input: IssueCreateInput!) {
+		issueCreate(input: $input) { issue { ` + linearIssueFields + ` } }
+	}`
+	if err := l.api.graphql(ctx, "/graphql", mutation, map[string]interface{}{"input": input}, &created); err != nil {
+		return nil, err
+	}
+	result := created.IssueCreate.Issue.ticket()
+	return &result, nil
+}
+
+// Comment adds a comment to an issue
+func (l *Linear) Comment(ctx context.Context, key, body string) error {
+	issue, err := l.issue(ctx, key)
+	if err != nil {
+		return err
+	}
+	mutation := `mutation($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`
+	input := map[string]interface{}{"issueId": issue.ID, "body": body}
+	return l.api.graphql(ctx, "/graphql", mutation, map[string]interface{}{"input": input}, nil)
+}
+
+// Transition moves an issue to the workflow state of its team with the
+// given name, or of the given type, such as "completed"
+func (l *Linear) Transition(ctx context.Context, key, status string) error {
+	var data struct {
+		Issue struct {
+			ID   string `json:"id"`
+			Team struct {
+				States struct {
+					Nodes []linearState `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		} `json:"issue"`
+	}
+	query := `query($id: String!) { issue(id: $id) { id team { states { nodes { id name type } } } } }`
+	if err := l.api.graphql(ctx, "/graphql", query, map[string]interface{}{"id": normalizeLinearKey(key)}, &data); err != nil {
+		return err
+	}
+
+	state, ok := findLinearState(data.Issue.Team.States.Nodes, status)
+	if !ok {
+		var names []string
+		for _, s := range data.Issue.Team.States.Nodes {
+			names = append(names, s.Name)
+		}
+		return fmt.Errorf("no state %q in Linear, expected one of: %s", status, strings.Join(names, ", "))
+	}
+	mutation := `mutation($id: String!, $input: IssueUpdateInput!) { issueUpdate(id: $id, input: $input) { success } }`
+	variables := map[string]interface{}{"id": data.Issue.ID, "input": map[string]string{"stateId": state.ID}}
+	return l.api.graphql(ctx, "/graphql", mutation, variables, nil)
+}
+
+// TeamPrefixes returns the ticket prefixes of the current user's teams, such
+// as "ENG-", for finding Linear issues in branch names and commit messages
+func (l *Linear) TeamPrefixes(ctx context.Context) ([]string, error) {
+	var data struct {
+		Viewer struct {
+			Teams struct {
+				Nodes []struct {
+					Key string `json:"key"`
+				} `json:"nodes"`
+			} `json:"teams"`
+		} `json:"viewer"`
+	}
+	if err := l.api.graphql(ctx, "/graphql", `query { viewer { teams { nodes { key } } } }`, nil, &data); err != nil {
+		return nil, err
+	}
+	prefixes := make([]string, len(data.Viewer.Teams.Nodes))
+	for i, team := range data.Viewer.Teams.Nodes {
+		prefixes[i] = team.Key + "-"
+	}
+	return prefixes, nil
+}
+
+// ResolveKey returns an issue identifier in upper case, such as ENG-123,
+// from the identifier or the issue's URL
+func (l *Linear) ResolveKey(key string) (string, error) {
+	match := linearKeyPattern.FindStringSubmatch(strings.TrimSpace(key))
+	if match == nil {
+		return "", fmt.Errorf("invalid Linear issue %q, expected an identifier such as ENG-123", key)
+	}
+	return strings.ToUpper(match[1]), nil
+}
+
+// linearKeyPattern matches an issue identifier, alone or in the issue's URL
+var linearKeyPattern = regexp.MustCompile(`^(?:https://linear\.app/[^/]+/issue/)?([A-Za-z0-9]+-[0-9]+)(?:/.*)?$`)
+
+// issue returns an issue by identifier
+func (l *Linear) issue(ctx context.Context, key string) (*linearIssue, error) {
+	var data struct {
+		Issue *linearIssue `json:"issue"`
+	}
+	query := `query($id: String!) { issue(id: $id) { ` + linearIssueFields + ` } }`
+	if err := l.api.graphql(ctx, "/graphql", query, map[string]interface{}{"id": normalizeLinearKey(key)}, &data); err != nil {
+		return nil, err
+	}
+	if data.Issue == nil {
+		return nil, fmt.Errorf("Linear issue %s not found", key)
+	}
+	return data.Issue, nil
+}
+
+// normalizeLinearKey upper-cases an identifier, which Linear's branch names
+// use in lower case, such as eng-123
+func normalizeLinearKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
+
+// findLinearState returns the state with the given name, or else the first
+// state of the given type, ignoring case
+func findLinearState(states []linearState, status string) (linearState, bool) {
+	for _, state := range states {
+		if strings.EqualFold(state.Name, status) {
+			return state, true
+		}
+	}
+	for _, state := range states {
+		if strings.EqualFold(state.Type, status) {
+			return state, true
+		}
+	}
+	return linearState{}, false
+}
+
+// ticket converts an issue to a ticket
+func (i linearIssue) ticket() Ticket {
+	ticket := Ticket{
+		Key:         i.Identifier,
+		Summary:     i.Title,
+		Description: i.Description,
+		Status:      i.State.Name,
+		Type:        "Issue",
+		URL:         i.URL,
+	}
+	if i.Assignee != nil {
+		ticket.Assignee = i.Assignee.Name
+	}
+	for _, label := range i.Labels.Nodes {
+		ticket.Labels = append(ticket.Labels, label.Name)
+	}
+	return ticket
+}
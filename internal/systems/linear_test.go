@@ -0,0 +1,108 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// linearRequest is a GraphQL request as sent to Linear
+type linearRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// newTestLinear returns a Linear system whose API is served by handler
+func newTestLinear(t *testing.T, handler func(req linearRequest) string) *Linear {
+	return newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != "POST" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "lin_api_test" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		var req linearRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Write([]byte(handler(req)))
+	}), NewLinear, func(url string) *config.Config {
+		return &config.Config{LinearToken: "lin_api_test", Linear: config.LinearSettings{APIURL: url, Team: "ENG"}}
+	})
+}
+
+func TestLinearView(t *testing.T) {
+	linear := newTestLinear(t, func(req linearRequest) string {
+		if req.Variables["id"] != "ENG-123" {
+			t.Errorf("Unexpected issue %v", req.Variables["id"])
+		}
+		return `{"data": {"issue": {"id": "uuid-1", "identifier": "ENG-123", "title": "Fix login",
+			"url": "https://linear.app/acme/issue/ENG-123", "state": {"name": "In Progress"},
+			"assignee": {"name": "Sam"}, "labels": {"nodes": [{"name": "Bug"}]}}}}`
+	})
+
+	ticket, err := linear.View(context.Background(), "eng-123")
+	if err != nil {
+		t.Fatalf("Failed to view issue: %v", err)
+	}
+	want := Ticket{Key: "ENG-123", Summary: "Fix login", Status: "In Progress", Type: "Issue",
+		Assignee: "Sam", URL: "https://linear.app/acme/issue/ENG-123", Labels: []string{"Bug"}}
+	if !reflect.DeepEqual(*ticket, want) {
+		t.Errorf("View() = %+v, want %+v", *ticket, want)
+	}
+}
+
+func TestLinearTransition(t *testing.T) {
+	var update map[string]interface{}
+	linear := newTestLinear(t, func(req linearRequest) string {
+		if strings.Contains(req.Query, "issueUpdate") {
+			update = req.Variables
+			return `{"data": {"issueUpdate": {"success": true}}}`
+		}
+		return `{"data": {"issue": {"id": "uuid-1", "team": {"states": {"nodes": [
+			{"id": "s1", "name": "Todo", "type": "unstarted"},
+			{"id": "s2", "name": "Shipped", "type": "completed"}]}}}}}`
+	})
+
+	if err := linear.Transition(context.Background(), "ENG-1", "completed"); err != nil {
+		t.Fatalf("Failed to transition issue: %v", err)
+	}
+	if update["id"] != "uuid-1" || !reflect.DeepEqual(update["input"], map[string]interface{}{"stateId": "s2"}) {
+		t.Errorf("Unexpected update %v", update)
+	}
+
+	err := linear.Transition(context.Background(), "ENG-1", "Archived")
+	if err == nil || !strings.Contains(err.Error(), "Todo, Shipped") {
+		t.Errorf("Expected an error listing the states, got %v", err)
+	}
+}
+
+func TestLinearErrors(t *testing.T) {
+	linear := newTestLinear(t, func(req linearRequest) string {
+		return `{"errors": [{"message": "Entity not found"}]}`
+	})
+
+	_, err := linear.View(context.Background(), "ENG-9")
+	if err == nil || !strings.Contains(err.Error(), "Linear API error: Entity not found") {
+		t.Errorf("Expected the GraphQL error, got %v", err)
+	}
+}
+
+func TestLinearTeamPrefixes(t *testing.T) {
+	linear := newTestLinear(t, func(req linearRequest) string {
+		return `{"data": {"viewer": {"teams": {"nodes": [{"key": "ENG"}, {"key": "OPS"}]}}}}`
+	})
+
+	prefixes, err := linear.TeamPrefixes(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch prefixes: %v", err)
+	}
+	if !reflect.DeepEqual(prefixes, []string{"ENG-", "OPS-"}) {
+		t.Errorf("TeamPrefixes() = %v", prefixes)
+	}
+}
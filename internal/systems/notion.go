@@ -0,0 +1,465 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultNotionAPIURL is the URL of the Notion API unless configured otherwise
+const defaultNotionAPIURL = "https://api.notion.com/v1"
+
+func init() {
+	Register(Registration{
+		Name:  "notion",
+		Title: "Notion database",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewNotion(cfg)
+		},
+	})
+}
+
+// Notion is a Notion database as a ticket system: its pages are tickets,
+// whose summary, status and assignee are held by configured properties.
+// Pages are referred to by their unique ID property, such as TASK-12, if
+// one is configured, and otherwise by page ID.
+type Notion struct {
+	api      *restClient
+	settings config.NotionSettings
+}
+
+// NotionUser is a person in the Notion workspace
+type NotionUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"` // "person" or "bot"
+}
+
+// notionPage is a page as returned by the Notion API
+type notionPage struct {
+	ID         string                    `json:"id"`
+	URL        string                    `json:"url"`
+	Properties map[string]notionProperty `json:"properties"`
+}
+
+// notionProperty is the value of a page property, of which the field
+// named by its type is set
+type notionProperty struct {
+	Type     string          `json:"type"`
+	Title    []notionText    `json:"title"`
+	RichText []notionText    `json:"rich_text"`
+	Status   *notionOption   `json:"status"`
+	Select   *notionOption   `json:"select"`
+	People   []NotionUser    `json:"people"`
+	UniqueID *notionUniqueID `json:"unique_id"`
+	Multi    []notionOption  `json:"multi_select"`
+}
+
+// notionText is a run of rich text
+type notionText struct {
+	PlainText string `json:"plain_text"`
+}
+
+// notionOption is an option of a status or select property
+type notionOption struct {
+	Name string `json:"name"`
+}
+
+// notionUniqueID is the value of a unique ID property, such as TASK-12
+type notionUniqueID struct {
+	Prefix string `json:"prefix"`
+	Number int    `json:"number"`
+}
+
+// notionSchema is a database's properties, by name
+type notionSchema map[string]struct {
+	Type   string `json:"type"`
+	Status *struct {
+		Options []notionOption `json:"options"`
+	} `json:"status"`
+	Select *struct {
+		Options []notionOption `json:"options"`
+	} `json:"select"`
+}
+
+// NewNotion creates the Notion system, authenticated with the configured
+// internal integration token or the NOTION_TOKEN environment variable
+func NewNotion(cfg *config.Config) (*Notion, error) {
+	token := cfg.NotionToken
+	if token == "" {
+		token = os.Getenv("NOTION_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Notion token not found. Run 'plannet init' to set up Notion")
+	}
+	settings := cfg.Notion
+	if settings.StatusProperty == "" {
+		settings.StatusProperty = "Status"
+	}
+	if settings.AssigneeProperty == "" {
+		settings.AssigneeProperty = "Assignee"
+	}
+	if settings.DoneStatus == "" {
+		settings.DoneStatus = "Done"
+	}
+	apiURL := cfg.Notion.APIURL
+	if apiURL == "" {
+		apiURL = defaultNotionAPIURL
+	}
+	headers := map[string]string{
+		"Authorization":  "Bearer " + token,
+		"Notion-Version": "2022-06-28",
+	}
+	// Notion allows an average of three requests a second
+	return &Notion{api: newRESTClient("Notion", strings.TrimSuffix(apiURL, "/"), headers, 180), settings: settings}, nil
+}
+
+// Name returns "notion"
+func (n *Notion) Name() string {
+	return "notion"
+}
+
+// List returns the pages assigned to the configured user that are not done
+func (n *Notion) List(ctx context.Context) ([]Ticket, error) {
+	if n.settings.User == "" {
+		return nil, fmt.Errorf("no Notion user configured; set notion.user in your configuration")
+	}
+	schema, err := n.schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statusType := schema[n.settings.StatusProperty].Type
+	if statusType != "status" && statusType != "select" {
+		return nil, fmt.Errorf("the Notion database has no status or select property %q", n.settings.StatusProperty)
+	}
+
+	filter := map[string]interface{}{
+		"and": []interface{}{
+			map[string]interface{}{
+				"property": n.settings.AssigneeProperty,
+				"people":   map[string]string{"contains": n.settings.User},
+			},
+			map[string]interface{}{
+				"property": n.settings.StatusProperty,
+				statusType: map[string]string{"does_not_equal": n.settings.DoneStatus},
+			},
+		},
+	}
+	pages, err := n.query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(pages))
+	for i, page := range pages {
+		tickets[i] = n.ticket(page)
+	}
+	return tickets, nil
+}
+
+// View returns a page
+func (n *Notion) View(ctx context.Context, key string) (*Ticket, error) {
+	page, err := n.page(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	ticket := n.ticket(*page)
+	return &ticket, nil
+}
+
+// Create creates a page in the database, assigned to the configured user.
+// The description goes in the description property if one is configured,
+// and otherwise in the page's content.
+func (n *Notion) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	if n.settings.Database == "" {
+		return nil, fmt.Errorf("no Notion database configured; set notion.database in your configuration")
+	}
+	schema, err := n.schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	title := n.titleProperty(schema)
+	if title == "" {
+		return nil, fmt.Errorf("the Notion database has no title property")
+	}
+
+	properties := map[string]interface{}{
+		title: map[string]interface{}{"title": notionRichText(ticket.Summary)},
+	}
+	if n.settings.User != "" {
+		properties[n.settings.AssigneeProperty] = map[string]interface{}{
+			"people": []map[string]string{{"id": n.settings.User}},
+		}
+	}
+	body := map[string]interface{}{
+		"parent":     map[string]string{"database_id": n.settings.Database},
+		"properties": properties,
+	}
+	if ticket.Description != "" {
+		if n.settings.DescriptionProperty != "" {
+			properties[n.settings.DescriptionProperty] = map[string]interface{}{"rich_text": notionRichText(ticket.Description)}
+		} else {
+			body["children"] = []interface{}{map[string]interface{}{
+				"object":    "block",
+				"type":      "paragraph",
+				"paragraph": map[string]interface{}{"rich_text": notionRichText(ticket.Description)},
+			}}
+		}
+	}
+
+	var page notionPage
+	if err := n.api.do(ctx, "POST", "/pages", body, &page); err != nil {
+		return nil, err
+	}
+	created := n.ticket(page)
+	return &created, nil
+}
+
+// Comment adds a comment to a page
+func (n *Notion) Comment(ctx context.Context, key, body string) error {
+	page, err := n.page(ctx, key)
+	if err != nil {
+		return err
+	}
+	comment := map[string]interface{}{
+		"parent":    map[string]string{"page_id": page.ID},
+		"rich_text": notionRichText(body),
+	}
+	return n.api.do(ctx, "POST", "/comments", comment, nil)
+}
+
+// Transition sets a page's status property to one of its options
+func (n *Notion) Transition(ctx context.Context, key, status string) error {
+	schema, err := n.schema(ctx)
+	if err != nil {
+		return err
+	}
+	property := schema[n.settings.StatusProperty]
+	var options []notionOption
+	switch {
+	case property.Status != nil:
+		options = property.Status.Options
+	case property.Select != nil:
+		options = property.Select.Options
+	default:
+		return fmt.Errorf("the Notion database has no status or select property %q", n.settings.StatusProperty)
+	}
+
+	var names []string
+	for _, option := range options {
+		if !strings.EqualFold(option.Name, strings.TrimSpace(status)) {
+			names = append(names, option.Name)
+			continue
+		}
+		page, err := n.page(ctx, key)
+		if err != nil {
+			return err
+		}
+		update := map[string]interface{}{
+			"properties": map[string]interface{}{
+				n.settings.StatusProperty: map[string]interface{}{property.Type: map[string]string{"name": option.Name}},
+			},
+		}
+		return n.api.do(ctx, "PATCH", "/pages/"+page.ID, update, nil)
+	}
+	return fmt.Errorf("no status %q in the Notion database, expected one of: %s", status, strings.Join(names, ", "))
+}
+
+// ResolveKey returns a page's unique ID in upper case, such as TASK-12, or
+// its page ID without dashes, from either one or the page's URL
+func (n *Notion) ResolveKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if match := notionPageIDPattern.FindStringSubmatch(strings.ReplaceAll(key, "-", "")); match != nil {
+		return strings.ToLower(match[1]), nil
+	}
+	if n.settings.IDProperty != "" && notionUniqueIDPattern.MatchString(key) {
+		return strings.ToUpper(key), nil
+	}
+	return "", fmt.Errorf("invalid Notion page %q, expected a page ID, URL or unique ID", key)
+}
+
+// notionPageIDPattern matches a page ID without dashes, alone or at the end
+// of the page's URL
+var notionPageIDPattern = regexp.MustCompile(`^(?:https://(?:www\.)?notion\.so/\S*?)?([0-9a-fA-F]{32})(?:\?.*)?$`)
+
+// notionUniqueIDPattern matches the value of a unique ID property
+var notionUniqueIDPattern = regexp.MustCompile(`^[A-Za-z]+-[0-9]+$`)
+
+// Users returns the people in the Notion workspace, for choosing whose
+// pages are listed
+func (n *Notion) Users(ctx context.Context) ([]NotionUser, error) {
+	var result struct {
+		Results []NotionUser `json:"results"`
+	}
+	if err := n.api.do(ctx, "GET", "/users?page_size=100", nil, &result); err != nil {
+		return nil, err
+	}
+	var people []NotionUser
+	for _, user := range result.Results {
+		if user.Type == "person" {
+			people = append(people, user)
+		}
+	}
+	return people, nil
+}
+
+// page returns a page by key, looking unique IDs up in the database
+func (n *Notion) page(ctx context.Context, key string) (*notionPage, error) {
+	key, err := n.ResolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !notionUniqueIDPattern.MatchString(key) {
+		var page notionPage
+		if err := n.api.do(ctx, "GET", "/pages/"+key, nil, &page); err != nil {
+			return nil, err
+		}
+		return &page, nil
+	}
+
+	number, _ := strconv.Atoi(key[strings.LastIndex(key, "-")+1:])
+	pages, err := n.query(ctx, map[string]interface{}{
+		"property":  n.settings.IDProperty,
+		"unique_id": map[string]int{"equals": number},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("Notion page %s not found", key)
+	}
+	return &pages[0], nil
+}
+
+// query returns the database's pages matching a filter
+func (n *Notion) query(ctx context.Context, filter interface{}) ([]notionPage, error) {
+	if n.settings.Database == "" {
+		return nil, fmt.Errorf("no Notion database configured; set notion.database in your configuration")
+	}
+	var result struct {
+		Results []notionPage `json:"results"`
+	}
+	body := map[string]interface{}{"filter": filter, "page_size": 100}
+	if err := n.api.do(ctx, "POST", "/databases/"+n.settings.Database+"/query", body, &result); err != nil {
+		return nil, err
+	}
+	return result.Results, nil
+}
+
+// schema returns the properties of the database
+func (n *Notion) schema(ctx context.Context) (notionSchema, error) {
+	if n.settings.Database == "" {
+		return nil, fmt.Errorf("no Notion database configured; set notion.database in your configuration")
+	}
+	var database struct {
+		Properties notionSchema `json:"properties"`
+	}
+	if err := n.api.do(ctx, "GET", "/databases/"+n.settings.Database, nil, &database); err != nil {
+		return nil, err
+	}
+	return database.Properties, nil
+}
+
+// titleProperty returns the name of the property holding the summary
+func (n *Notion) titleProperty(schema notionSchema) string {
+	if n.settings.TitleProperty != "" {
+		return n.settings.TitleProperty
+	}
+	for name, property := range schema {
+		if property.Type == "title" {
+			return name
+		}
+	}
+	return ""
+}
+
+// ticket converts a page to a ticket, by the configured properties
+func (n *Notion) ticket(page notionPage) Ticket {
+	ticket := Ticket{
+		Key:         strings.ReplaceAll(page.ID, "-", ""),
+		Status:      page.Properties[n.settings.StatusProperty].text(),
+		Assignee:    page.Properties[n.settings.AssigneeProperty].text(),
+		Description: page.Properties[n.settings.DescriptionProperty].text(),
+		Type:        "Page",
+		URL:         page.URL,
+	}
+	if id := page.Properties[n.settings.IDProperty].text(); id != "" {
+		ticket.Key = id
+	}
+	for name, property := range page.Properties {
+		if name == n.settings.TitleProperty || (n.settings.TitleProperty == "" && property.Type == "title") {
+			ticket.Summary = property.text()
+		}
+	}
+	return ticket
+}
+
+// text returns a property's value as text
+func (p notionProperty) text() string {
+	switch p.Type {
+	case "title":
+		return notionPlainText(p.Title)
+	case "rich_text":
+		return notionPlainText(p.RichText)
+	case "status":
+		if p.Status != nil {
+			return p.Status.Name
+		}
+	case "select":
+		if p.Select != nil {
+			return p.Select.Name
+		}
+	case "multi_select":
+		names := make([]string, len(p.Multi))
+		for i, option := range p.Multi {
+			names[i] = option.Name
+		}
+		return strings.Join(names, ", ")
+	case "people":
+		names := make([]string, len(p.People))
+		for i, person := range p.People {
+			names[i] = person.Name
+		}
+		return strings.Join(names, ", ")
+	case "unique_id":
+		if p.UniqueID != nil {
+			if p.UniqueID.Prefix == "" {
+				return strconv.Itoa(p.UniqueID.Number)
+			}
+			return fmt.Sprintf("%s-%d", p.UniqueID.Prefix, p.UniqueID.Number)
+		}
+	}
+	return ""
+}
+
+// notionPlainText joins runs of rich text
+func notionPlainText(runs []notionText) string {
+	var b strings.Builder
+	for _, run := range runs {
+		b.WriteString(run.PlainText)
+	}
+	return b.String()
+}
+
+// notionRichText returns text as rich text to send to Notion, in runs of
+// at most the 2000 characters Notion accepts
+func notionRichText(text string) []interface{} {
+	var runs []interface{}
+	chars := []rune(text)
+	for len(chars) > 0 {
+		size := len(chars)
+		if size > 2000 {
+			size = 2000
+		}
+		runs = append(runs, map[string]interface{}{"text": map[string]string{"content": string(chars[:size])}})
+		chars = chars[size:]
+	}
+	if runs == nil {
+		runs = []interface{}{}
+	}
+	return runs
+}
@@ -0,0 +1,123 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// testNotionSchema is a database with a status property and unique IDs
+const testNotionSchema = `{"properties": {
+	"Task": {"type": "title"},
+	"Status": {"type": "status", "status": {"options": [{"name": "Not started"}, {"name": "In progress"}, {"name": "Done"}]}},
+	"Owner": {"type": "people"},
+	"ID": {"type": "unique_id"}}}`
+
+// testNotionPage is a page of the test database
+const testNotionPage = `{"id": "0123456789abcdef0123456789abcdef", "url": "https://www.notion.so/Fix-login-0123456789abcdef0123456789abcdef",
+	"properties": {
+		"Task": {"type": "title", "title": [{"plain_text": "Fix "}, {"plain_text": "login"}]},
+		"Status": {"type": "status", "status": {"name": "In progress"}},
+		"Owner": {"type": "people", "people": [{"id": "u1", "name": "Sam"}]},
+		"ID": {"type": "unique_id", "unique_id": {"prefix": "TASK", "number": 12}}}}`
+
+// newTestNotion returns a Notion system whose API is served by handler
+func newTestNotion(t *testing.T, handler http.HandlerFunc) *Notion {
+	return newTestServer(t, handler, NewNotion, func(url string) *config.Config {
+		return &config.Config{
+			NotionToken: "secret_test",
+			Notion:      config.NotionSettings{APIURL: url, Database: "db1", User: "u1", AssigneeProperty: "Owner", IDProperty: "ID"},
+		}
+	})
+}
+
+func TestNotionList(t *testing.T) {
+	var filter interface{}
+	notion := newTestNotion(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Notion-Version") == "" || r.Header.Get("Authorization") != "Bearer secret_test" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+		switch r.URL.Path {
+		case "/databases/db1":
+			w.Write([]byte(testNotionSchema))
+		case "/databases/db1/query":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			filter = body["filter"]
+			w.Write([]byte(`{"results": [` + testNotionPage + `]}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	})
+
+	tickets, err := notion.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list pages: %v", err)
+	}
+	want := []Ticket{{Key: "TASK-12", Summary: "Fix login", Status: "In progress", Type: "Page", Assignee: "Sam",
+		URL: "https://www.notion.so/Fix-login-0123456789abcdef0123456789abcdef"}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+	data, _ := json.Marshal(filter)
+	for _, part := range []string{`"people":{"contains":"u1"}`, `"status":{"does_not_equal":"Done"}`} {
+		if !strings.Contains(string(data), part) {
+			t.Errorf("Expected the filter to contain %s, got %s", part, data)
+		}
+	}
+}
+
+func TestNotionTransition(t *testing.T) {
+	var update string
+	notion := newTestNotion(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/databases/db1":
+			w.Write([]byte(testNotionSchema))
+		case r.URL.Path == "/databases/db1/query":
+			w.Write([]byte(`{"results": [` + testNotionPage + `]}`))
+		case r.Method == "PATCH" && r.URL.Path == "/pages/0123456789abcdef0123456789abcdef":
+			var body interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			data, _ := json.Marshal(body)
+			update = string(data)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	ctx := context.Background()
+
+	if err := notion.Transition(ctx, "task-12", "done"); err != nil {
+		t.Fatalf("Failed to transition page: %v", err)
+	}
+	if update != `{"properties":{"Status":{"status":{"name":"Done"}}}}` {
+		t.Errorf("Unexpected update %s", update)
+	}
+
+	err := notion.Transition(ctx, "TASK-12", "Blocked")
+	if err == nil || !strings.Contains(err.Error(), "Not started, In progress, Done") {
+		t.Errorf("Expected an error listing the statuses, got %v", err)
+	}
+}
+
+func TestNotionResolveKey(t *testing.T) {
+	notion := &Notion{settings: config.NotionSettings{IDProperty: "ID"}}
+	tests := map[string]string{
+		"task-12":                              "TASK-12",
+		"01234567-89ab-cdef-0123-456789ABCDEF": "0123456789abcdef0123456789abcdef",
+		"https://www.notion.so/acme/Fix-login-0123456789abcdef0123456789abcdef?pvs=4": "0123456789abcdef0123456789abcdef",
+	}
+	for key, want := range tests {
+		if got, err := notion.ResolveKey(key); err != nil || got != want {
+			t.Errorf("ResolveKey(%q) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+	if _, err := (&Notion{}).ResolveKey("TASK-12"); err == nil {
+		t.Error("Expected unique IDs to need an ID property")
+	}
+}
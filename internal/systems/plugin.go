@@ -0,0 +1,240 @@
+package systems
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// PluginPrefix starts the names of the executables of ticket system
+// plugins: plannet-system-<name> on the PATH provides the system <name>
+const PluginPrefix = "plannet-system-"
+
+// pluginProtocolVersion is the version of the protocol sent to plugins,
+// bumped on incompatible changes
+const pluginProtocolVersion = 1
+
+// Plugin is a ticket system provided by an executable. Each operation runs
+// it once, with a request as JSON on stdin, and reads the response as JSON
+// from stdout:
+//
+//	{"version": 1, "method": "view", "params": {"key": "OPS-7"}, "settings": {...}}
+//	{"result": {"key": "OPS-7", "summary": "Rotate certificates", ...}}
+//
+// Methods are list, view, create, comment, transition and resolve_key, with
+// the parameters and results of the TicketSystem methods. Failures are
+// reported as {"error": {"code": "...", "message": "..."}}; the code
+// "not_supported" is ErrNotSupported. The settings are those under the
+// plugin's name in system_plugins, and anything written to stderr is shown
+// to the user.
+type Plugin struct {
+	name     string
+	path     string
+	settings map[string]string
+}
+
+// pluginRequest is a request sent to a plugin
+type pluginRequest struct {
+	Version  int               `json:"version"`
+	Method   string            `json:"method"`
+	Params   interface{}       `json:"params,omitempty"`
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// pluginResponse is a plugin's response to a request
+type pluginResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewPlugin creates the plugin system of an executable, with its settings
+// from the configuration
+func NewPlugin(name, path string, cfg *config.Config) *Plugin {
+	return &Plugin{name: name, path: path, settings: cfg.SystemPlugins[name]}
+}
+
+// Name returns the name of the plugin, the executable's name without the prefix
+func (p *Plugin) Name() string {
+	return p.name
+}
+
+// List returns the open tickets assigned to the current user
+func (p *Plugin) List(ctx context.Context) ([]Ticket, error) {
+	var tickets []Ticket
+	if err := p.call(ctx, "list", nil, &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// View returns a ticket by key
+func (p *Plugin) View(ctx context.Context, key string) (*Ticket, error) {
+	var ticket Ticket
+	if err := p.call(ctx, "view", map[string]string{"key": key}, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// Create creates a ticket and returns it
+func (p *Plugin) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	var created Ticket
+	if err := p.call(ctx, "create", ticket, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Comment adds a comment to a ticket
+func (p *Plugin) Comment(ctx context.Context, key, body string) error {
+	return p.call(ctx, "comment", map[string]string{"key": key, "body": body}, nil)
+}
+
+// Transition moves a ticket to a status
+func (p *Plugin) Transition(ctx context.Context, key, status string) error {
+	return p.call(ctx, "transition", map[string]string{"key": key, "status": status}, nil)
+}
+
+// ResolveKey returns the key of a ticket as the plugin refers to it. Keys
+// are taken as they are by plugins that don't support resolving them.
+func (p *Plugin) ResolveKey(key string) (string, error) {
+	var resolved string
+	err := p.call(context.Background(), "resolve_key", map[string]string{"key": key}, &resolved)
+	if err == ErrNotSupported {
+		return strings.TrimSpace(key), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// call runs the plugin with a request, and decodes the result into out,
+// which may be nil
+func (p *Plugin) call(ctx context.Context, method string, params, out interface{}) error {
+	request, err := json.Marshal(pluginRequest{
+		Version:  pluginProtocolVersion,
+		Method:   method,
+		Params:   params,
+		Settings: p.settings,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s plugin request: %w", p.name, err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	// A plugin may exit with an error after reporting it in its response
+	var response pluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("%s plugin failed: %w", p.name, runErr)
+		}
+		return fmt.Errorf("failed to parse %s plugin response: %w", p.name, err)
+	}
+	if response.Error != nil {
+		if response.Error.Code == "not_supported" {
+			return ErrNotSupported
+		}
+		return fmt.Errorf("%s plugin error: %s", p.name, response.Error.Message)
+	}
+	if runErr != nil {
+		return fmt.Errorf("%s plugin failed: %w", p.name, runErr)
+	}
+
+	if out == nil || len(response.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, out); err != nil {
+		return fmt.Errorf("failed to parse %s plugin response: %w", p.name, err)
+	}
+	return nil
+}
+
+// pluginsOnce makes the default registry look for plugins once
+var pluginsOnce sync.Once
+
+// discoverPlugins registers the plugins on the PATH in the default registry
+func discoverPlugins() {
+	pluginsOnce.Do(func() {
+		DiscoverPlugins(Default, os.Getenv("PATH"))
+	})
+}
+
+// DiscoverPlugins registers the plugins found in the directories of a PATH
+// list. A plugin is skipped if a system of its name is already registered,
+// so that plugins can't replace the built-in systems, and earlier
+// directories take precedence as they do for commands.
+func DiscoverPlugins(registry *Registry, pathList string) {
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			if _, exists := registry.Lookup(name); exists {
+				continue
+			}
+			registry.Register(Registration{
+				Name:   name,
+				Title:  name + " (plugin)",
+				Plugin: path,
+				New: func(cfg *config.Config) (TicketSystem, error) {
+					return NewPlugin(name, path, cfg), nil
+				},
+			})
+		}
+	}
+}
+
+// pluginName returns the name of the system a file provides, if it is a plugin
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(strings.ToLower(file), ".exe")
+	}
+	name := strings.TrimPrefix(file, PluginPrefix)
+	if name == file || name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// isExecutable reports whether a file can be run
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0111 != 0
+}
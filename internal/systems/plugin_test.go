@@ -0,0 +1,102 @@
+package systems
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// testPluginScript answers requests by method, echoing the settings it got
+const testPluginScript = `#!/bin/sh
+request=$(cat)
+case "$request" in
+*'"method":"view"'*)
+	echo '{"result": {"key": "OPS-7", "summary": "Rotate certificates", "status": "Open"}}' ;;
+*'"method":"list"'*)
+	case "$request" in
+	*'"team":"ops"'*) echo '{"result": [{"key": "OPS-7", "summary": "Rotate certificates"}]}' ;;
+	*) echo '{"result": []}' ;;
+	esac ;;
+*'"method":"comment"'*)
+	echo '{"error": {"code": "failed", "message": "ticket is locked"}}'
+	exit 1 ;;
+*)
+	echo '{"error": {"code": "not_supported", "message": "unsupported"}}' ;;
+esac
+`
+
+// writeTestPlugin writes an executable plugin script to dir
+func writeTestPlugin(t *testing.T, dir, name string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin scripts need a Unix shell")
+	}
+	path := filepath.Join(dir, PluginPrefix+name)
+	if err := os.WriteFile(path, []byte(testPluginScript), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	return path
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	path := writeTestPlugin(t, first, "tracker")
+	writeTestPlugin(t, second, "tracker")
+	writeTestPlugin(t, second, "github")
+	// Files without the executable bit are not plugins
+	os.WriteFile(filepath.Join(second, PluginPrefix+"notes"), []byte("#!/bin/sh\n"), 0644)
+
+	registry := NewRegistry()
+	registry.Register(Registration{Name: "github", Title: "GitHub Issues"})
+	DiscoverPlugins(registry, strings.Join([]string{first, second, filepath.Join(first, "missing")}, string(os.PathListSeparator)))
+
+	var names []string
+	for _, reg := range registry.List() {
+		names = append(names, reg.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"github", "tracker"}) {
+		t.Errorf("Registered %v, want github and tracker", names)
+	}
+	if reg, _ := registry.Lookup("tracker"); reg.Plugin != path {
+		t.Errorf("Expected the plugin earlier on the PATH, got %s", reg.Plugin)
+	}
+	if reg, _ := registry.Lookup("github"); reg.Plugin != "" {
+		t.Error("Expected the built-in system not to be replaced by a plugin")
+	}
+}
+
+func TestPluginCalls(t *testing.T) {
+	path := writeTestPlugin(t, t.TempDir(), "tracker")
+	plugin := NewPlugin("tracker", path, &config.Config{
+		SystemPlugins: map[string]map[string]string{"tracker": {"team": "ops"}},
+	})
+	ctx := context.Background()
+
+	ticket, err := plugin.View(ctx, "OPS-7")
+	if err != nil {
+		t.Fatalf("Failed to view ticket: %v", err)
+	}
+	if want := (Ticket{Key: "OPS-7", Summary: "Rotate certificates", Status: "Open"}); !reflect.DeepEqual(*ticket, want) {
+		t.Errorf("Unexpected ticket %+v", *ticket)
+	}
+
+	tickets, err := plugin.List(ctx)
+	if err != nil || len(tickets) != 1 {
+		t.Errorf("Expected the settings to reach the plugin, got %v, %v", tickets, err)
+	}
+
+	if err := plugin.Comment(ctx, "OPS-7", "Done"); err == nil || !strings.Contains(err.Error(), "ticket is locked") {
+		t.Errorf("Expected the plugin's error, got %v", err)
+	}
+	if err := plugin.Transition(ctx, "OPS-7", "Done"); err != ErrNotSupported {
+		t.Errorf("Expected ErrNotSupported, got %v", err)
+	}
+	if key, err := plugin.ResolveKey(" OPS-7 "); err != nil || key != "OPS-7" {
+		t.Errorf("Expected keys to be taken as they are, got %q, %v", key, err)
+	}
+}
@@ -0,0 +1,238 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func init() {
+	Register(Registration{
+		Name:  "redmine",
+		Title: "Redmine",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewRedmine(cfg)
+		},
+	})
+}
+
+// Redmine is Redmine as a ticket system. Issues are referred to by number,
+// such as 1234, with or without a leading '#'.
+type Redmine struct {
+	api     *restClient
+	url     string
+	project string
+}
+
+// redmineNamed is a status, tracker, user or category of an issue
+type redmineNamed struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// redmineIssue is an issue as returned by the Redmine API
+type redmineIssue struct {
+	ID          int           `json:"id"`
+	Subject     string        `json:"subject"`
+	Description string        `json:"description"`
+	Status      redmineNamed  `json:"status"`
+	Tracker     redmineNamed  `json:"tracker"`
+	AssignedTo  *redmineNamed `json:"assigned_to"`
+	Category    *redmineNamed `json:"category"`
+}
+
+// NewRedmine creates the Redmine system, authenticated with the configured
+// REST API key or the REDMINE_API_KEY environment variable
+func NewRedmine(cfg *config.Config) (*Redmine, error) {
+	if cfg.Redmine.URL == "" {
+		return nil, fmt.Errorf("no Redmine URL configured. Run 'plannet init' to set up Redmine")
+	}
+	token := cfg.RedmineToken
+	if token == "" {
+		token = os.Getenv("REDMINE_API_KEY")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Redmine API key not found. Run 'plannet init' to set up Redmine")
+	}
+	baseURL := strings.TrimSuffix(cfg.Redmine.URL, "/")
+	headers := map[string]string{"X-Redmine-API-Key": token}
+	return &Redmine{
+		api:     newRESTClient("Redmine", baseURL, headers, 60),
+		url:     baseURL,
+		project: cfg.Redmine.Project,
+	}, nil
+}
+
+// Name returns "redmine"
+func (r *Redmine) Name() string {
+	return "redmine"
+}
+
+// List returns the open issues assigned to the current user
+func (r *Redmine) List(ctx context.Context) ([]Ticket, error) {
+	var result struct {
+		Issues []redmineIssue `json:"issues"`
+	}
+	path := "/issues.json?assigned_to_id=me&status_id=open&sort=updated_on:desc&limit=100"
+	if err := r.api.do(ctx, "GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(result.Issues))
+	for i, issue := range result.Issues {
+		tickets[i] = r.ticket(issue)
+	}
+	return tickets, nil
+}
+
+// View returns an issue
+func (r *Redmine) View(ctx context.Context, key string) (*Ticket, error) {
+	key, err := r.ResolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Issue redmineIssue `json:"issue"`
+	}
+	if err := r.api.do(ctx, "GET", "/issues/"+key+".json", nil, &result); err != nil {
+		return nil, err
+	}
+	ticket := r.ticket(result.Issue)
+	return &ticket, nil
+}
+
+// Create creates an issue assigned to the current user in the project
+// given by the ticket's project, or the configured one. The ticket's type
+// selects the tracker by name; without it, the project's default is used.
+func (r *Redmine) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	project := ticket.Project
+	if project == "" {
+		project = r.project
+	}
+	if project == "" {
+		return nil, fmt.Errorf("no project given for the new issue; set redmine.project in your configuration")
+	}
+
+	var user struct {
+		User redmineNamed `json:"user"`
+	}
+	if err := r.api.do(ctx, "GET", "/users/current.json", nil, &user); err != nil {
+		return nil, err
+	}
+	issue := map[string]interface{}{
+		"project_id":     project,
+		"subject":        ticket.Summary,
+		"assigned_to_id": user.User.ID,
+	}
+	if ticket.Description != "" {
+		issue["description"] = ticket.Description
+	}
+	if ticket.Type != "" {
+		var trackers struct {
+			Trackers []redmineNamed `json:"trackers"`
+		}
+		if err := r.api.do(ctx, "GET", "/trackers.json", nil, &trackers); err != nil {
+			return nil, err
+		}
+		tracker, ok := findRedmineNamed(trackers.Trackers, ticket.Type)
+		if !ok {
+			return nil, fmt.Errorf("no tracker %q in Redmine, expected one of: %s", ticket.Type, redmineNames(trackers.Trackers))
+		}
+		issue["tracker_id"] = tracker.ID
+	}
+
+	var result struct {
+		Issue redmineIssue `json:"issue"`
+	}
+	if err := r.api.do(ctx, "POST", "/issues.json", map[string]interface{}{"issue": issue}, &result); err != nil {
+		return nil, err
+	}
+	created := r.ticket(result.Issue)
+	return &created, nil
+}
+
+// Comment adds a note to an issue
+func (r *Redmine) Comment(ctx context.Context, key, body string) error {
+	key, err := r.ResolveKey(key)
+	if err != nil {
+		return err
+	}
+	update := map[string]interface{}{"issue": map[string]string{"notes": body}}
+	return r.api.do(ctx, "PUT", "/issues/"+key+".json", update, nil)
+}
+
+// Transition sets an issue's status by name
+func (r *Redmine) Transition(ctx context.Context, key, status string) error {
+	key, err := r.ResolveKey(key)
+	if err != nil {
+		return err
+	}
+	var statuses struct {
+		Statuses []redmineNamed `json:"issue_statuses"`
+	}
+	if err := r.api.do(ctx, "GET", "/issue_statuses.json", nil, &statuses); err != nil {
+		return err
+	}
+	found, ok := findRedmineNamed(statuses.Statuses, status)
+	if !ok {
+		return fmt.Errorf("no status %q in Redmine, expected one of: %s", status, redmineNames(statuses.Statuses))
+	}
+	update := map[string]interface{}{"issue": map[string]int{"status_id": found.ID}}
+	return r.api.do(ctx, "PUT", "/issues/"+key+".json", update, nil)
+}
+
+// ResolveKey returns an issue's number, from the number, with or without a
+// leading '#', or the issue's URL
+func (r *Redmine) ResolveKey(key string) (string, error) {
+	match := redmineKeyPattern.FindStringSubmatch(strings.TrimSpace(key))
+	if match == nil {
+		return "", fmt.Errorf("invalid Redmine issue %q, expected its number", key)
+	}
+	return match[1], nil
+}
+
+// redmineKeyPattern matches an issue number, alone or in the issue's URL
+var redmineKeyPattern = regexp.MustCompile(`^(?:https?://\S+/issues/|#)?([0-9]+)(?:[?#].*)?$`)
+
+// ticket converts an issue to a ticket
+func (r *Redmine) ticket(issue redmineIssue) Ticket {
+	key := strconv.Itoa(issue.ID)
+	ticket := Ticket{
+		Key:         key,
+		Summary:     issue.Subject,
+		Description: issue.Description,
+		Status:      issue.Status.Name,
+		Type:        issue.Tracker.Name,
+		URL:         r.url + "/issues/" + key,
+	}
+	if issue.AssignedTo != nil {
+		ticket.Assignee = issue.AssignedTo.Name
+	}
+	if issue.Category != nil {
+		ticket.Labels = []string{issue.Category.Name}
+	}
+	return ticket
+}
+
+// findRedmineNamed returns the item with the given name, ignoring case
+func findRedmineNamed(items []redmineNamed, name string) (redmineNamed, bool) {
+	for _, item := range items {
+		if strings.EqualFold(item.Name, strings.TrimSpace(name)) {
+			return item, true
+		}
+	}
+	return redmineNamed{}, false
+}
+
+// redmineNames lists the names of items
+func redmineNames(items []redmineNamed) string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return strings.Join(names, ", ")
+}
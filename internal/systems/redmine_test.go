@@ -0,0 +1,130 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestRedmine returns a Redmine system whose API is served by handler
+func newTestRedmine(t *testing.T, handler http.HandlerFunc) *Redmine {
+	return newTestServer(t, handler, NewRedmine, func(url string) *config.Config {
+		return &config.Config{
+			RedmineToken: "secret",
+			Redmine:      config.RedmineSettings{URL: url + "/", Project: "web"},
+		}
+	})
+}
+
+func TestRedmineList(t *testing.T) {
+	redmine := newTestRedmine(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Redmine-API-Key") != "secret" {
+			t.Errorf("Unexpected API key %q", r.Header.Get("X-Redmine-API-Key"))
+		}
+		query := r.URL.Query()
+		if r.URL.Path != "/issues.json" || query.Get("assigned_to_id") != "me" || query.Get("status_id") != "open" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"issues": [{"id": 1234, "subject": "Fix login", "status": {"id": 2, "name": "In Progress"},
+			"tracker": {"id": 1, "name": "Bug"}, "assigned_to": {"id": 5, "name": "Sam Lee"},
+			"category": {"id": 3, "name": "auth"}}], "total_count": 1}`))
+	})
+
+	tickets, err := redmine.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list issues: %v", err)
+	}
+	want := []Ticket{{Key: "1234", Summary: "Fix login", Status: "In Progress", Type: "Bug",
+		Assignee: "Sam Lee", URL: redmine.url + "/issues/1234", Labels: []string{"auth"}}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+}
+
+func TestRedmineCreate(t *testing.T) {
+	var issue map[string]interface{}
+	redmine := newTestRedmine(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /users/current.json":
+			w.Write([]byte(`{"user": {"id": 5, "login": "sam"}}`))
+		case "GET /trackers.json":
+			w.Write([]byte(`{"trackers": [{"id": 1, "name": "Bug"}, {"id": 2, "name": "Feature"}]}`))
+		case "POST /issues.json":
+			var body struct {
+				Issue map[string]interface{} `json:"issue"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			issue = body.Issue
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"issue": {"id": 1235, "subject": "Add export", "status": {"id": 1, "name": "New"},
+				"tracker": {"id": 2, "name": "Feature"}}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	created, err := redmine.Create(context.Background(), NewTicket{Type: "feature", Summary: "Add export"})
+	if err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	want := map[string]interface{}{"project_id": "web", "subject": "Add export", "assigned_to_id": 5.0, "tracker_id": 2.0}
+	if !reflect.DeepEqual(issue, want) {
+		t.Errorf("Issue = %v, want %v", issue, want)
+	}
+	if created.Key != "1235" || created.Status != "New" {
+		t.Errorf("Create() = %+v", created)
+	}
+}
+
+func TestRedmineTransition(t *testing.T) {
+	var update map[string]interface{}
+	redmine := newTestRedmine(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /issue_statuses.json":
+			w.Write([]byte(`{"issue_statuses": [{"id": 1, "name": "New"}, {"id": 5, "name": "Closed", "is_closed": true}]}`))
+		case "PUT /issues/1234.json":
+			json.NewDecoder(r.Body).Decode(&update)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	if err := redmine.Transition(context.Background(), "#1234", "closed"); err != nil {
+		t.Fatalf("Failed to transition issue: %v", err)
+	}
+	want := map[string]interface{}{"issue": map[string]interface{}{"status_id": 5.0}}
+	if !reflect.DeepEqual(update, want) {
+		t.Errorf("Update = %v, want %v", update, want)
+	}
+
+	if err := redmine.Transition(context.Background(), "1234", "Shipped"); err == nil {
+		t.Error("Expected an error for an unknown status")
+	}
+}
+
+func TestRedmineResolveKey(t *testing.T) {
+	redmine := &Redmine{}
+	tests := map[string]string{
+		"1234":  "1234",
+		"#1234": "1234",
+		"https://redmine.example.com/issues/1234":           "1234",
+		"https://redmine.example.com/issues/1234#note-3":    "1234",
+		"https://redmine.example.com/redmine/issues/1234?x": "1234",
+	}
+	for key, want := range tests {
+		got, err := redmine.ResolveKey(key)
+		if err != nil || got != want {
+			t.Errorf("ResolveKey(%q) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+	for _, key := range []string{"", "ABC-12", "#", "12a"} {
+		if _, err := redmine.ResolveKey(key); err == nil {
+			t.Errorf("ResolveKey(%q) succeeded, want an error", key)
+		}
+	}
+}
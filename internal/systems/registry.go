@@ -0,0 +1,111 @@
+package systems
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// Registration describes a ticket system that can be selected by name
+type Registration struct {
+	// Name selects the system in the ticket_system setting, such as "github"
+	Name string
+	// Title is the name shown to users, such as "GitHub Issues"
+	Title string
+	// New creates the system from the configuration
+	New func(cfg *config.Config) (TicketSystem, error)
+	// Plugin is the path of the executable of a plugin system, and empty
+	// for the systems built into plannet
+	Plugin string
+}
+
+// Registry holds the ticket systems that can be selected by name
+type Registry struct {
+	mu      sync.RWMutex
+	systems map[string]Registration
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{systems: make(map[string]Registration)}
+}
+
+// Register adds a ticket system. Registering two systems under the same
+// name is a programming error, and panics.
+func (r *Registry) Register(reg Registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.systems[reg.Name]; ok {
+		panic(fmt.Sprintf("ticket system %q registered twice", reg.Name))
+	}
+	r.systems[reg.Name] = reg
+}
+
+// Lookup returns the ticket system registered under a name
+func (r *Registry) Lookup(name string) (Registration, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reg, ok := r.systems[name]
+	return reg, ok
+}
+
+// List returns the registered ticket systems, by name
+func (r *Registry) List() []Registration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	regs := make([]Registration, 0, len(r.systems))
+	for _, reg := range r.systems {
+		regs = append(regs, reg)
+	}
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].Name < regs[j].Name
+	})
+	return regs
+}
+
+// New creates the ticket system registered under a name
+func (r *Registry) New(name string, cfg *config.Config) (TicketSystem, error) {
+	reg, ok := r.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown ticket system %q", name)
+	}
+	return reg.New(cfg)
+}
+
+// Default is the registry the built-in ticket systems register in
+var Default = NewRegistry()
+
+// Register adds a ticket system to the default registry
+func Register(reg Registration) {
+	Default.Register(reg)
+}
+
+// List returns the ticket systems in the default registry, by name,
+// including the plugins on the PATH
+func List() []Registration {
+	discoverPlugins()
+	return Default.List()
+}
+
+// DefaultSystem is the ticket system used when the configuration selects
+// none. Jira is registered by the cmd package, which holds its client.
+const DefaultSystem = "jira"
+
+// SelectedName returns the name of the ticket system the configuration selects
+func SelectedName(cfg *config.Config) string {
+	if cfg.TicketSystem == "" {
+		return DefaultSystem
+	}
+	return cfg.TicketSystem
+}
+
+// New creates the ticket system the configuration selects from the default registry
+func New(cfg *config.Config) (TicketSystem, error) {
+	discoverPlugins()
+	return Default.New(SelectedName(cfg), cfg)
+}
@@ -0,0 +1,57 @@
+package systems
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// fakeSystem is a ticket system that does nothing
+type fakeSystem struct{ name string }
+
+func (f fakeSystem) Name() string                                  { return f.name }
+func (f fakeSystem) List(context.Context) ([]Ticket, error)        { return nil, nil }
+func (f fakeSystem) View(context.Context, string) (*Ticket, error) { return nil, ErrNotSupported }
+func (f fakeSystem) Create(context.Context, NewTicket) (*Ticket, error) {
+	return nil, ErrNotSupported
+}
+func (f fakeSystem) Comment(context.Context, string, string) error    { return ErrNotSupported }
+func (f fakeSystem) Transition(context.Context, string, string) error { return ErrNotSupported }
+func (f fakeSystem) ResolveKey(key string) (string, error)            { return key, nil }
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"zebra", "alpha"} {
+		name := name
+		registry.Register(Registration{Name: name, New: func(*config.Config) (TicketSystem, error) {
+			return fakeSystem{name}, nil
+		}})
+	}
+
+	regs := registry.List()
+	if len(regs) != 2 || regs[0].Name != "alpha" || regs[1].Name != "zebra" {
+		t.Errorf("Expected the systems sorted by name, got %v", regs)
+	}
+
+	system, err := registry.New("zebra", &config.Config{})
+	if err != nil || system.Name() != "zebra" {
+		t.Errorf("Expected the zebra system, got %v, %v", system, err)
+	}
+	if _, err := registry.New("missing", &config.Config{}); err == nil {
+		t.Error("Expected an error for an unknown system")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a name twice to panic")
+		}
+	}()
+	registry.Register(Registration{Name: "alpha"})
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	if _, ok := Default.Lookup("github"); !ok {
+		t.Error("Expected GitHub to be registered")
+	}
+}
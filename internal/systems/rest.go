@@ -0,0 +1,138 @@
+package systems
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/security"
+)
+
+// OnRateLimitWait, if set, is called when a request waits for a rate limit,
+// to show progress
+var OnRateLimitWait func(key string, delay time.Duration)
+
+// restClient sends JSON requests to the REST API of a ticket system
+type restClient struct {
+	name    string // Names the API in errors, such as "GitHub"
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+// newRESTClient returns a client for an API that allows limit requests a minute
+func newRESTClient(name, baseURL string, headers map[string]string, limit int) *restClient {
+	rateLimiter := security.NewHTTPRateLimiter(limit, time.Minute)
+	rateLimiter.OnWait = OnRateLimitWait
+	return &restClient{
+		name:    name,
+		baseURL: baseURL,
+		headers: headers,
+		client:  rateLimiter.WrapHTTPClient(&http.Client{Timeout: 30 * time.Second}, name),
+	}
+}
+
+// do sends a request to a path of the API with body encoded as JSON, and
+// decodes the JSON response into out. Either may be nil. Any status other
+// than 2xx is returned as an error.
+func (c *restClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s API request: %w", c.name, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create %s API request: %w", c.name, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s API request: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s API returned status %d: %s", c.name, resp.StatusCode, string(data))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s API response: %w", c.name, err)
+	}
+	return nil
+}
+
+// projectKeyPattern matches project#number, #number or number, where the
+// project is a path such as owner/repo or group/subgroup/project
+var projectKeyPattern = regexp.MustCompile(`^(?:([\w.-]+(?:/[\w.-]+)+))?#?([0-9]+)$`)
+
+// parseProjectKey returns the project and number of an issue key of a
+// system that numbers issues per project, using defaultProject for keys
+// without one. setting names the configuration setting of the default.
+func parseProjectKey(key, defaultProject, system, setting string) (string, int, error) {
+	match := projectKeyPattern.FindStringSubmatch(strings.TrimSpace(key))
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid %s issue %q, expected project#number", system, key)
+	}
+	project := match[1]
+	if project == "" {
+		project = defaultProject
+	}
+	if project == "" {
+		return "", 0, fmt.Errorf("issue %q has no project; use project#number or set %s in your configuration", key, setting)
+	}
+	number, _ := strconv.Atoi(match[2])
+	return project, number, nil
+}
+
+// graphql sends a GraphQL query with its variables to the API's path, and
+// decodes the data of the response into out. Errors in the response are
+// returned as an error.
+func (c *restClient) graphql(ctx context.Context, path, query string, variables map[string]interface{}, out interface{}) error {
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]interface{}{"query": query, "variables": variables}
+	if err := c.do(ctx, "POST", path, body, &result); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("%s API error: %s", c.name, strings.Join(messages, "; "))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to parse %s API response: %w", c.name, err)
+	}
+	return nil
+}
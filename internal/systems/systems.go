@@ -0,0 +1,135 @@
+// Package systems provides the ticket systems plannet can work with, behind
+// a common interface, and a registry to select them by name. Jira, whose
+// client is in the cmd package, registers itself from there.
+package systems
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotSupported is returned for operations a ticket system can't perform
+var ErrNotSupported = errors.New("not supported by this ticket system")
+
+// Ticket is a ticket, issue or task in a ticket system
+type Ticket struct {
+	Key         string   `json:"key"` // The ID users refer to the ticket by, such as "octo/app#42"
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	Status      string   `json:"status,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Priority    string   `json:"priority,omitempty"`
+	Assignee    string   `json:"assignee,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+
+	// The details below are only filled in by View, by the systems that have them
+	Parent      *TicketLink  `json:"parent,omitempty"` // The epic or ticket the ticket belongs to
+	Links       []TicketLink `json:"links,omitempty"`
+	Fields      []Field      `json:"fields,omitempty"` // Other fields, such as Jira's custom fields
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// TicketLink is a ticket another ticket refers to
+type TicketLink struct {
+	Relation string `json:"relation,omitempty"` // How the tickets relate, such as "blocks"
+	Key      string `json:"key"`
+	Summary  string `json:"summary,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+// Field is a named value of a ticket
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Attachment is a file attached to a ticket
+type Attachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type,omitempty"`
+	URL      string `json:"url,omitempty"` // Where the file is downloaded from
+}
+
+// NewTicket describes a ticket to create
+type NewTicket struct {
+	// Project is where the ticket is created: a project, repository, board or
+	// list, depending on the system. Empty for the system's default.
+	Project     string   `json:"project,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	// Parent is the key of the epic or ticket the new ticket belongs to, for
+	// systems with parents
+	Parent string `json:"parent,omitempty"`
+	// Fields are any other fields to set, by the system's field ID, such as
+	// Jira's components and custom fields
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// TicketSystem is a ticket system plannet can read and update tickets in
+type TicketSystem interface {
+	// Name returns the name the system is registered under
+	Name() string
+	// List returns the open tickets assigned to the current user
+	List(ctx context.Context) ([]Ticket, error)
+	// View returns a ticket by key
+	View(ctx context.Context, key string) (*Ticket, error)
+	// Create creates a ticket and returns it
+	Create(ctx context.Context, ticket NewTicket) (*Ticket, error)
+	// Comment adds a comment to a ticket
+	Comment(ctx context.Context, key, body string) error
+	// Transition moves a ticket to a status, such as "closed"
+	Transition(ctx context.Context, key, status string) error
+	// ResolveKey returns the key of a ticket as the system refers to it,
+	// from a key as users type it, such as in lower case or without the
+	// default project. It fails for keys that can't be the system's.
+	ResolveKey(key string) (string, error)
+}
+
+// BranchResolver is implemented by ticket systems that can tell which
+// tickets a branch is for, such as from the merge request it belongs to
+type BranchResolver interface {
+	// BranchTickets returns the keys of the tickets a branch is for
+	BranchTickets(ctx context.Context, branch string) ([]string, error)
+}
+
+// Searcher is implemented by ticket systems that search tickets with a query
+// in their own language, such as Jira's JQL
+type Searcher interface {
+	// Search returns the tickets matching a query
+	Search(ctx context.Context, query string) ([]Ticket, error)
+}
+
+// ChildLister is implemented by ticket systems whose tickets can have
+// children, such as the tickets of a Jira epic
+type ChildLister interface {
+	// Children returns the children of a ticket
+	Children(ctx context.Context, key string) ([]Ticket, error)
+}
+
+// Transition is a move a ticket can make from its current status
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   string `json:"to"` // The status the ticket moves to
+}
+
+// TransitionLister is implemented by ticket systems whose tickets can only
+// move to some statuses from their current one, such as through the
+// transitions of a Jira workflow
+type TransitionLister interface {
+	// Transitions returns the transitions a ticket can currently take
+	Transitions(ctx context.Context, key string) ([]Transition, error)
+}
+
+// AttachmentDownloader is implemented by ticket systems that can download
+// the files attached to tickets
+type AttachmentDownloader interface {
+	// DownloadAttachment saves an attachment into dir and returns its path
+	DownloadAttachment(ctx context.Context, attachment Attachment, dir string) (string, error)
+}
@@ -0,0 +1,290 @@
+package systems
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// defaultTrelloAPIURL is the URL of the Trello API unless configured otherwise
+const defaultTrelloAPIURL = "https://api.trello.com/1"
+
+// trelloCardFields are the fields of a card requested from Trello
+const trelloCardFields = "fields=name,desc,shortLink,shortUrl,idList,idBoard,labels,closed"
+
+func init() {
+	Register(Registration{
+		Name:  "trello",
+		Title: "Trello",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewTrello(cfg)
+		},
+	})
+}
+
+// Trello is Trello as a ticket system. Tickets are cards, referred to by
+// their short link or URL, and their status is the list they are in.
+type Trello struct {
+	api   *restClient
+	board string
+	list  string
+}
+
+// TrelloBoard is a board and its open lists
+type TrelloBoard struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Lists []TrelloList `json:"lists"`
+}
+
+// TrelloList is a list of cards on a board
+type TrelloList struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// trelloCard is a card as returned by the Trello API
+type trelloCard struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Desc      string `json:"desc"`
+	ShortLink string `json:"shortLink"`
+	ShortURL  string `json:"shortUrl"`
+	IDList    string `json:"idList"`
+	IDBoard   string `json:"idBoard"`
+	Closed    bool   `json:"closed"`
+	Labels    []struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+}
+
+// NewTrello creates the Trello system, authenticated with the configured
+// API key and token, or the TRELLO_API_KEY and TRELLO_TOKEN environment
+// variables
+func NewTrello(cfg *config.Config) (*Trello, error) {
+	key := cfg.Trello.APIKey
+	if key == "" {
+		key = os.Getenv("TRELLO_API_KEY")
+	}
+	token := cfg.TrelloToken
+	if token == "" {
+		token = os.Getenv("TRELLO_TOKEN")
+	}
+	if key == "" || token == "" {
+		return nil, fmt.Errorf("Trello API key or token not found. Run 'plannet init' to set up Trello")
+	}
+	apiURL := cfg.Trello.APIURL
+	if apiURL == "" {
+		apiURL = defaultTrelloAPIURL
+	}
+	headers := map[string]string{
+		"Authorization": fmt.Sprintf(`OAuth oauth_consumer_key="%s", oauth_token="%s"`, key, token),
+	}
+	return &Trello{
+		// Trello allows 100 requests per 10 seconds for each token; stay well under it
+		api:   newRESTClient("Trello", strings.TrimSuffix(apiURL, "/"), headers, 300),
+		board: cfg.Trello.Board,
+		list:  cfg.Trello.List,
+	}, nil
+}
+
+// Name returns "trello"
+func (t *Trello) Name() string {
+	return "trello"
+}
+
+// List returns the open cards the current user is a member of, on the
+// configured board or on every board
+func (t *Trello) List(ctx context.Context) ([]Ticket, error) {
+	boards, err := t.Boards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lists := make(map[string]string)
+	for _, board := range boards {
+		for _, list := range board.Lists {
+			lists[list.ID] = list.Name
+		}
+	}
+
+	var cards []trelloCard
+	if err := t.api.do(ctx, "GET", "/members/me/cards?filter=open&"+trelloCardFields, nil, &cards); err != nil {
+		return nil, err
+	}
+	var tickets []Ticket
+	for _, card := range cards {
+		if t.board == "" || card.IDBoard == t.board {
+			tickets = append(tickets, card.ticket(lists[card.IDList]))
+		}
+	}
+	return tickets, nil
+}
+
+// View returns a card
+func (t *Trello) View(ctx context.Context, key string) (*Ticket, error) {
+	card, err := t.card(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var list TrelloList
+	if err := t.api.do(ctx, "GET", "/lists/"+card.IDList+"?fields=name", nil, &list); err != nil {
+		return nil, err
+	}
+	ticket := card.ticket(list.Name)
+	return &ticket, nil
+}
+
+// Create adds a card, with the current user as a member, to the list given
+// by the ticket's project, as an ID or a name on the configured board, or
+// to the configured list
+func (t *Trello) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	list := ticket.Project
+	if list == "" {
+		list = t.list
+	}
+	if list == "" {
+		return nil, fmt.Errorf("no list given for the new card; set trello.list in your configuration")
+	}
+	if !trelloIDPattern.MatchString(list) {
+		id, err := t.findList(ctx, t.board, list)
+		if err != nil {
+			return nil, err
+		}
+		list = id
+	}
+
+	var me struct {
+		ID string `json:"id"`
+	}
+	if err := t.api.do(ctx, "GET", "/members/me?fields=id", nil, &me); err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"idList":    list,
+		"name":      ticket.Summary,
+		"idMembers": []string{me.ID},
+	}
+	if ticket.Description != "" {
+		body["desc"] = ticket.Description
+	}
+	var card trelloCard
+	if err := t.api.do(ctx, "POST", "/cards", body, &card); err != nil {
+		return nil, err
+	}
+	created := card.ticket("")
+	return &created, nil
+}
+
+// Comment adds a comment to a card
+func (t *Trello) Comment(ctx context.Context, key, body string) error {
+	id, err := parseTrelloKey(key)
+	if err != nil {
+		return err
+	}
+	return t.api.do(ctx, "POST", "/cards/"+id+"/actions/comments", map[string]string{"text": body}, nil)
+}
+
+// Transition moves a card to the list with the given name on its board
+func (t *Trello) Transition(ctx context.Context, key, status string) error {
+	card, err := t.card(ctx, key)
+	if err != nil {
+		return err
+	}
+	list, err := t.findList(ctx, card.IDBoard, status)
+	if err != nil {
+		return err
+	}
+	return t.api.do(ctx, "PUT", "/cards/"+card.ID, map[string]string{"idList": list}, nil)
+}
+
+// ResolveKey returns the short link or ID of a card, from either one or
+// the card's URL
+func (t *Trello) ResolveKey(key string) (string, error) {
+	return parseTrelloKey(key)
+}
+
+// Boards returns the open boards of the current user, with their open lists
+func (t *Trello) Boards(ctx context.Context) ([]TrelloBoard, error) {
+	var boards []TrelloBoard
+	if err := t.api.do(ctx, "GET", "/members/me/boards?filter=open&fields=name&lists=open", nil, &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// card returns a card by key
+func (t *Trello) card(ctx context.Context, key string) (*trelloCard, error) {
+	id, err := parseTrelloKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var card trelloCard
+	if err := t.api.do(ctx, "GET", "/cards/"+id+"?"+trelloCardFields, nil, &card); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// findList returns the ID of the open list of a board with the given
+// name, ignoring case
+func (t *Trello) findList(ctx context.Context, board, name string) (string, error) {
+	if board == "" {
+		return "", fmt.Errorf("no board to find the list %q on; set trello.board in your configuration", name)
+	}
+	var lists []TrelloList
+	if err := t.api.do(ctx, "GET", "/boards/"+board+"/lists?filter=open&fields=name", nil, &lists); err != nil {
+		return "", err
+	}
+	var names []string
+	for _, list := range lists {
+		if strings.EqualFold(list.Name, strings.TrimSpace(name)) {
+			return list.ID, nil
+		}
+		names = append(names, list.Name)
+	}
+	return "", fmt.Errorf("no list %q on the Trello board, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// trelloIDPattern matches the IDs of Trello objects
+var trelloIDPattern = regexp.MustCompile(`^[0-9a-f]{24}$`)
+
+// trelloKeyPattern matches a card's short link or ID, alone or in its URL
+var trelloKeyPattern = regexp.MustCompile(`^(?:https?://trello\.com/c/)?([0-9A-Za-z]{8}|[0-9a-f]{24})(?:/.*)?$`)
+
+// parseTrelloKey returns the short link or ID of a card key
+func parseTrelloKey(key string) (string, error) {
+	match := trelloKeyPattern.FindStringSubmatch(strings.TrimSpace(key))
+	if match == nil {
+		return "", fmt.Errorf("invalid Trello card %q, expected its short link or URL", key)
+	}
+	return match[1], nil
+}
+
+// ticket converts a card in the named list to a ticket
+func (c trelloCard) ticket(list string) Ticket {
+	ticket := Ticket{
+		Key:         c.ShortLink,
+		Summary:     c.Name,
+		Description: c.Desc,
+		Status:      list,
+		Type:        "Card",
+		URL:         c.ShortURL,
+	}
+	if c.Closed {
+		ticket.Status = "archived"
+	}
+	for _, label := range c.Labels {
+		// Labels may have only a color
+		name := label.Name
+		if name == "" {
+			name = label.Color
+		}
+		ticket.Labels = append(ticket.Labels, name)
+	}
+	return ticket
+}
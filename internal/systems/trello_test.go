@@ -0,0 +1,98 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestTrello returns a Trello system whose API is served by handler
+func newTestTrello(t *testing.T, handler http.HandlerFunc) *Trello {
+	return newTestServer(t, handler, NewTrello, func(url string) *config.Config {
+		return &config.Config{
+			TrelloToken: "test-token",
+			Trello:      config.TrelloSettings{APIURL: url, APIKey: "test-key", Board: "b1"},
+		}
+	})
+}
+
+func TestTrelloList(t *testing.T) {
+	trello := newTestTrello(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), `oauth_token="test-token"`) {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/members/me/boards":
+			w.Write([]byte(`[{"id": "b1", "name": "Team", "lists": [{"id": "l1", "name": "Doing"}]}]`))
+		case "/members/me/cards":
+			w.Write([]byte(`[
+				{"id": "c1", "name": "Fix login", "shortLink": "AbCd1234", "shortUrl": "https://trello.com/c/AbCd1234",
+					"idList": "l1", "idBoard": "b1", "labels": [{"name": "", "color": "red"}]},
+				{"id": "c2", "name": "Elsewhere", "shortLink": "ZyXw9876", "idList": "l9", "idBoard": "b2"}]`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+	})
+
+	tickets, err := trello.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list cards: %v", err)
+	}
+	want := []Ticket{{Key: "AbCd1234", Summary: "Fix login", Status: "Doing", Type: "Card",
+		URL: "https://trello.com/c/AbCd1234", Labels: []string{"red"}}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+}
+
+func TestTrelloTransition(t *testing.T) {
+	var moved map[string]string
+	trello := newTestTrello(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/cards/AbCd1234":
+			w.Write([]byte(`{"id": "c1", "idBoard": "b2", "idList": "l1"}`))
+		case r.URL.Path == "/boards/b2/lists":
+			w.Write([]byte(`[{"id": "l1", "name": "Doing"}, {"id": "l2", "name": "Done"}]`))
+		case r.Method == "PUT" && r.URL.Path == "/cards/c1":
+			json.NewDecoder(r.Body).Decode(&moved)
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+	})
+	ctx := context.Background()
+
+	if err := trello.Transition(ctx, "https://trello.com/c/AbCd1234/12-fix-login", "done"); err != nil {
+		t.Fatalf("Failed to move card: %v", err)
+	}
+	if moved["idList"] != "l2" {
+		t.Errorf("Card moved to %v, want l2", moved)
+	}
+
+	err := trello.Transition(ctx, "AbCd1234", "Blocked")
+	if err == nil || !strings.Contains(err.Error(), "Doing, Done") {
+		t.Errorf("Expected an error listing the lists, got %v", err)
+	}
+}
+
+func TestParseTrelloKey(t *testing.T) {
+	tests := map[string]string{
+		"AbCd1234":                              "AbCd1234",
+		"https://trello.com/c/AbCd1234":         "AbCd1234",
+		"https://trello.com/c/AbCd1234/7-title": "AbCd1234",
+		"5f2b1c3d4e5f6a7b8c9d0e1f":              "5f2b1c3d4e5f6a7b8c9d0e1f",
+	}
+	for key, want := range tests {
+		if got, err := parseTrelloKey(key); err != nil || got != want {
+			t.Errorf("parseTrelloKey(%q) = %q, %v, want %q", key, got, err, want)
+		}
+	}
+	if _, err := parseTrelloKey("ENG-12"); err == nil {
+		t.Error("Expected an error for a key that is not a card")
+	}
+}
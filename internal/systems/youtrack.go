@@ -0,0 +1,271 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// youtrackIssueFields are the fields of an issue requested from YouTrack
+const youtrackIssueFields = "idReadable,summary,description,resolved,tags(name),customFields(name,value(name,fullName))"
+
+func init() {
+	Register(Registration{
+		Name:  "youtrack",
+		Title: "YouTrack",
+		New: func(cfg *config.Config) (TicketSystem, error) {
+			return NewYouTrack(cfg)
+		},
+	})
+}
+
+// YouTrack is YouTrack as a ticket system. Issues are referred to by their
+// readable ID, such as ABC-12, and change state through commands.
+type YouTrack struct {
+	api        *restClient
+	url        string
+	project    string
+	stateField string
+}
+
+// youtrackIssue is an issue as returned by the YouTrack API
+type youtrackIssue struct {
+	IDReadable  string `json:"idReadable"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Tags        []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	CustomFields []struct {
+		Name string `json:"name"`
+		// Value is an object, a list of objects, a string or null,
+		// depending on the field
+		Value json.RawMessage `json:"value"`
+	} `json:"customFields"`
+}
+
+// NewYouTrack creates the YouTrack system, authenticated with the
+// configured permanent token or the YOUTRACK_TOKEN environment variable
+func NewYouTrack(cfg *config.Config) (*YouTrack, error) {
+	if cfg.YouTrack.URL == "" {
+		return nil, fmt.Errorf("no YouTrack URL configured. Run 'plannet init' to set up YouTrack")
+	}
+	token := cfg.YouTrackToken
+	if token == "" {
+		token = os.Getenv("YOUTRACK_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("YouTrack token not found. Run 'plannet init' to set up YouTrack")
+	}
+	stateField := cfg.YouTrack.StateField
+	if stateField == "" {
+		stateField = "State"
+	}
+	baseURL := strings.TrimSuffix(cfg.YouTrack.URL, "/")
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	return &YouTrack{
+		api:        newRESTClient("YouTrack", baseURL+"/api", headers, 60),
+		url:        baseURL,
+		project:    cfg.YouTrack.Project,
+		stateField: stateField,
+	}, nil
+}
+
+// Name returns "youtrack"
+func (y *YouTrack) Name() string {
+	return "youtrack"
+}
+
+// List returns the unresolved issues assigned to the current user
+func (y *YouTrack) List(ctx context.Context) ([]Ticket, error) {
+	query := url.Values{
+		"query":  {"for: me #Unresolved sort by: updated desc"},
+		"fields": {youtrackIssueFields},
+		"$top":   {"100"},
+	}
+	var issues []youtrackIssue
+	if err := y.api.do(ctx, "GET", "/issues?"+query.Encode(), nil, &issues); err != nil {
+		return nil, err
+	}
+	tickets := make([]Ticket, len(issues))
+	for i, issue := range issues {
+		tickets[i] = y.ticket(issue)
+	}
+	return tickets, nil
+}
+
+// View returns an issue
+func (y *YouTrack) View(ctx context.Context, key string) (*Ticket, error) {
+	key, err := y.ResolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var issue youtrackIssue
+	path := "/issues/" + key + "?fields=" + url.QueryEscape(youtrackIssueFields)
+	if err := y.api.do(ctx, "GET", path, nil, &issue); err != nil {
+		return nil, err
+	}
+	ticket := y.ticket(issue)
+	return &ticket, nil
+}
+
+// Create creates an issue in the project whose short name is the ticket's
+// project, or in the configured project. The type is set with a command,
+// as YouTrack projects name their types differently.
+func (y *YouTrack) Create(ctx context.Context, ticket NewTicket) (*Ticket, error) {
+	project := ticket.Project
+	if project == "" {
+		project = y.project
+	}
+	if project == "" {
+		return nil, fmt.Errorf("no project given for the new issue; set youtrack.project in your configuration")
+	}
+
+	var projects []struct {
+		ID        string `json:"id"`
+		ShortName string `json:"shortName"`
+	}
+	if err := y.api.do(ctx, "GET", "/admin/projects?fields=id,shortName&$top=500", nil, &projects); err != nil {
+		return nil, err
+	}
+	projectID := ""
+	for _, p := range projects {
+		if strings.EqualFold(p.ShortName, project) {
+			projectID = p.ID
+		}
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("YouTrack project %q not found", project)
+	}
+
+	body := map[string]interface{}{
+		"project": map[string]string{"id": projectID},
+		"summary": ticket.Summary,
+	}
+	if ticket.Description != "" {
+		body["description"] = ticket.Description
+	}
+	var issue youtrackIssue
+	if err := y.api.do(ctx, "POST", "/issues?fields="+url.QueryEscape(youtrackIssueFields), body, &issue); err != nil {
+		return nil, err
+	}
+
+	var commands []string
+	if ticket.Type != "" {
+		commands = append(commands, "Type "+ticket.Type)
+	}
+	for _, label := range ticket.Labels {
+		commands = append(commands, "tag "+label)
+	}
+	if len(commands) > 0 {
+		if err := y.command(ctx, issue.IDReadable, strings.Join(commands, " ")); err != nil {
+			return nil, fmt.Errorf("created %s, but failed to set its type and tags: %w", issue.IDReadable, err)
+		}
+	}
+	created := y.ticket(issue)
+	return &created, nil
+}
+
+// Comment adds a comment to an issue
+func (y *YouTrack) Comment(ctx context.Context, key, body string) error {
+	key, err := y.ResolveKey(key)
+	if err != nil {
+		return err
+	}
+	return y.api.do(ctx, "POST", "/issues/"+key+"/comments", map[string]string{"text": body}, nil)
+}
+
+// Transition sets an issue's state with a command, such as "State Fixed"
+func (y *YouTrack) Transition(ctx context.Context, key, status string) error {
+	key, err := y.ResolveKey(key)
+	if err != nil {
+		return err
+	}
+	return y.command(ctx, key, y.stateField+" "+strings.TrimSpace(status))
+}
+
+// ResolveKey returns an issue's readable ID in upper case, from the ID or
+// the issue's URL
+func (y *YouTrack) ResolveKey(key string) (string, error) {
+	match := youtrackKeyPattern.FindStringSubmatch(strings.TrimSpace(key))
+	if match == nil {
+		return "", fmt.Errorf("invalid YouTrack issue %q, expected an ID such as ABC-12", key)
+	}
+	return strings.ToUpper(match[1]), nil
+}
+
+// youtrackKeyPattern matches an issue's readable ID, alone or in its URL
+var youtrackKeyPattern = regexp.MustCompile(`^(?:https?://[^/]+(?:/youtrack)?/issue/)?([A-Za-z][A-Za-z0-9_]*-[0-9]+)(?:/.*)?$`)
+
+// command applies a YouTrack command, such as "State Fixed", to an issue
+func (y *YouTrack) command(ctx context.Context, key, query string) error {
+	body := map[string]interface{}{
+		"query":  query,
+		"issues": []map[string]string{{"idReadable": key}},
+	}
+	return y.api.do(ctx, "POST", "/commands", body, nil)
+}
+
+// ticket converts an issue to a ticket, reading the state, type and
+// assignee from its custom fields
+func (y *YouTrack) ticket(issue youtrackIssue) Ticket {
+	ticket := Ticket{
+		Key:         issue.IDReadable,
+		Summary:     issue.Summary,
+		Description: issue.Description,
+		URL:         y.url + "/issue/" + issue.IDReadable,
+	}
+	for _, field := range issue.CustomFields {
+		switch {
+		case strings.EqualFold(field.Name, y.stateField):
+			ticket.Status = youtrackFieldValue(field.Value)
+		case strings.EqualFold(field.Name, "Type"):
+			ticket.Type = youtrackFieldValue(field.Value)
+		case strings.EqualFold(field.Name, "Assignee"):
+			ticket.Assignee = youtrackFieldValue(field.Value)
+		}
+	}
+	for _, tag := range issue.Tags {
+		ticket.Labels = append(ticket.Labels, tag.Name)
+	}
+	return ticket
+}
+
+// youtrackFieldValue returns the value of a custom field as text: the name
+// of an enum, state or user, the names of a list joined, or a plain value
+func youtrackFieldValue(raw json.RawMessage) string {
+	type named struct {
+		Name     string `json:"name"`
+		FullName string `json:"fullName"`
+	}
+	name := func(v named) string {
+		if v.FullName != "" {
+			return v.FullName
+		}
+		return v.Name
+	}
+
+	var one named
+	if err := json.Unmarshal(raw, &one); err == nil {
+		return name(one)
+	}
+	var many []named
+	if err := json.Unmarshal(raw, &many); err == nil {
+		names := make([]string, len(many))
+		for i, v := range many {
+			names[i] = name(v)
+		}
+		return strings.Join(names, ", ")
+	}
+	var plain interface{}
+	if err := json.Unmarshal(raw, &plain); err == nil && plain != nil {
+		return fmt.Sprint(plain)
+	}
+	return ""
+}
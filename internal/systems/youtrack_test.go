@@ -0,0 +1,85 @@
+package systems
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestYouTrack returns a YouTrack system whose API is served by handler
+func newTestYouTrack(t *testing.T, handler http.HandlerFunc) *YouTrack {
+	return newTestServer(t, handler, NewYouTrack, func(url string) *config.Config {
+		return &config.Config{
+			YouTrackToken: "perm:test",
+			YouTrack:      config.YouTrackSettings{URL: url, Project: "ABC"},
+		}
+	})
+}
+
+func TestYouTrackList(t *testing.T) {
+	youtrack := newTestYouTrack(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer perm:test" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path != "/api/issues" || r.URL.Query().Get("query") != "for: me #Unresolved sort by: updated desc" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`[{"idReadable": "ABC-12", "summary": "Fix login", "tags": [{"name": "auth"}],
+			"customFields": [
+				{"name": "State", "value": {"name": "In Progress"}},
+				{"name": "Type", "value": {"name": "Bug"}},
+				{"name": "Assignee", "value": {"name": "sam", "fullName": "Sam Lee"}},
+				{"name": "Estimation", "value": null}]}]`))
+	})
+
+	tickets, err := youtrack.List(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list issues: %v", err)
+	}
+	want := []Ticket{{Key: "ABC-12", Summary: "Fix login", Status: "In Progress", Type: "Bug",
+		Assignee: "Sam Lee", URL: youtrack.url + "/issue/ABC-12", Labels: []string{"auth"}}}
+	if !reflect.DeepEqual(tickets, want) {
+		t.Errorf("List() = %+v, want %+v", tickets, want)
+	}
+}
+
+func TestYouTrackTransition(t *testing.T) {
+	var command map[string]interface{}
+	youtrack := newTestYouTrack(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/commands" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&command)
+		w.Write([]byte(`{}`))
+	})
+
+	if err := youtrack.Transition(context.Background(), "https://acme.youtrack.cloud/issue/abc-12/fix-login", "Fixed"); err != nil {
+		t.Fatalf("Failed to transition issue: %v", err)
+	}
+	want := map[string]interface{}{
+		"query":  "State Fixed",
+		"issues": []interface{}{map[string]interface{}{"idReadable": "ABC-12"}},
+	}
+	if !reflect.DeepEqual(command, want) {
+		t.Errorf("Command = %v, want %v", command, want)
+	}
+}
+
+func TestYouTrackFieldValue(t *testing.T) {
+	tests := map[string]string{
+		`{"name": "Open"}`:                 "Open",
+		`[{"name": "v1"}, {"name": "v2"}]`: "v1, v2",
+		`"2h"`:                             "2h",
+		`3`:                                "3",
+		`null`:                             "",
+	}
+	for raw, want := range tests {
+		if got := youtrackFieldValue(json.RawMessage(raw)); got != want {
+			t.Errorf("youtrackFieldValue(%s) = %q, want %q", raw, got, want)
+		}
+	}
+}
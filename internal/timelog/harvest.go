@@ -0,0 +1,98 @@
+package timelog
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// DefaultHarvestURL is the Harvest API, used unless another is configured
+const DefaultHarvestURL = "https://api.harvestapp.com/v2"
+
+// Harvest is a client of the Harvest API
+type Harvest struct {
+	api      *restClient
+	settings config.HarvestSettings
+}
+
+// harvestTimeEntry is a time entry as the Harvest API expects it
+type harvestTimeEntry struct {
+	ProjectID int64   `json:"project_id"`
+	TaskID    int64   `json:"task_id"`
+	SpentDate string  `json:"spent_date"` // YYYY-MM-DD
+	Hours     float64 `json:"hours"`
+	Notes     string  `json:"notes,omitempty"`
+}
+
+// NewHarvest creates a Harvest client authenticated with the configured
+// personal access token or the HARVEST_ACCESS_TOKEN environment variable
+func NewHarvest(cfg *config.Config) (*Harvest, error) {
+	if cfg.Harvest.AccountID == "" {
+		return nil, fmt.Errorf("no Harvest account configured. Set account_id in the harvest section of the config")
+	}
+	token := cfg.HarvestToken
+	if token == "" {
+		token = os.Getenv("HARVEST_ACCESS_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Harvest access token not found. Set harvest_token in the config or HARVEST_ACCESS_TOKEN")
+	}
+	baseURL := cfg.Harvest.URL
+	if baseURL == "" {
+		baseURL = DefaultHarvestURL
+	}
+	headers := map[string]string{
+		"Authorization":      "Bearer " + token,
+		"Harvest-Account-Id": cfg.Harvest.AccountID,
+		// Harvest rejects requests without a User-Agent naming the application
+		"User-Agent": "plannet (https://github.com/plannet-ai/plannet)",
+	}
+	return &Harvest{
+		api:      newRESTClient("Harvest", strings.TrimSuffix(baseURL, "/"), headers, 100),
+		settings: cfg.Harvest,
+	}, nil
+}
+
+// Name returns "harvest"
+func (h *Harvest) Name() string {
+	return "harvest"
+}
+
+// Target returns the project and task work is mapped to. Harvest needs
+// both, so work without them can't be logged.
+func (h *Harvest) Target(ticketIDs, tags []string) (config.TimeEntryTarget, bool) {
+	target, ok := findTarget(ticketIDs, tags, h.settings.Prefixes, h.settings.Tags)
+	return target, ok && target.Project != 0 && target.Task != 0
+}
+
+// CreateTimeEntry creates a time entry and returns its ID
+func (h *Harvest) CreateTimeEntry(ctx context.Context, entry TimeEntry) (string, error) {
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	if err := h.api.do(ctx, "POST", "/time_entries", harvestEntry(entry), &result); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result.ID), nil
+}
+
+// UpdateTimeEntry replaces the time entry with the given ID
+func (h *Harvest) UpdateTimeEntry(ctx context.Context, id string, entry TimeEntry) error {
+	return h.api.do(ctx, "PATCH", "/time_entries/"+id, harvestEntry(entry), nil)
+}
+
+// harvestEntry converts a time entry for the API, which takes the time
+// spent in hours
+func harvestEntry(entry TimeEntry) harvestTimeEntry {
+	return harvestTimeEntry{
+		ProjectID: entry.Target.Project,
+		TaskID:    entry.Target.Task,
+		SpentDate: entry.Start.Format("2006-01-02"),
+		Hours:     math.Round(float64(entry.Seconds)/3600*100) / 100,
+		Notes:     entry.Description,
+	}
+}
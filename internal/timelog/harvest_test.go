@@ -0,0 +1,95 @@
+package timelog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestHarvest returns a Harvest client whose API is served by handler
+func newTestHarvest(t *testing.T, handler http.HandlerFunc) *Harvest {
+	return newTestServer(t, handler, NewHarvest, func(url string) *config.Config {
+		return &config.Config{
+			HarvestToken: "secret",
+			Harvest: config.HarvestSettings{
+				URL:       url,
+				AccountID: "123",
+				Prefixes:  map[string]config.TimeEntryTarget{"PROJ-": {Project: 7, Task: 70}, "DEV-": {Project: 8}},
+				Tags:      map[string]config.TimeEntryTarget{"meetings": {Project: 9, Task: 90}},
+			},
+		}
+	})
+}
+
+func TestNewHarvestWithoutSettings(t *testing.T) {
+	t.Setenv("HARVEST_ACCESS_TOKEN", "")
+	if _, err := NewHarvest(&config.Config{HarvestToken: "secret"}); err == nil {
+		t.Error("Expected an error without an account")
+	}
+	if _, err := NewHarvest(&config.Config{Harvest: config.HarvestSettings{AccountID: "123"}}); err == nil {
+		t.Error("Expected an error without a token")
+	}
+}
+
+func TestHarvestTarget(t *testing.T) {
+	harvest := newTestHarvest(t, nil)
+	tests := []struct {
+		ticketIDs, tags []string
+		want            config.TimeEntryTarget
+		wantOK          bool
+	}{
+		{[]string{"PROJ-1"}, nil, config.TimeEntryTarget{Project: 7, Task: 70}, true},
+		{[]string{"PROJ-1"}, []string{"meetings"}, config.TimeEntryTarget{Project: 9, Task: 90}, true},
+		{[]string{"DEV-1"}, nil, config.TimeEntryTarget{Project: 8}, false}, // No task
+		{nil, []string{"misc"}, config.TimeEntryTarget{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := harvest.Target(tt.ticketIDs, tt.tags)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Target(%v, %v) = %+v, %v, want %+v, %v", tt.ticketIDs, tt.tags, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestHarvestCreateAndUpdateTimeEntry(t *testing.T) {
+	var requests []string
+	var got harvestTimeEntry
+	harvest := newTestHarvest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Harvest-Account-Id") != "123" {
+			t.Errorf("Unexpected headers %v", r.Header)
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id": 636709355}`))
+	})
+
+	entry := TimeEntry{
+		Description: "PROJ-1 Fix login",
+		Start:       time.Date(2024, 3, 11, 9, 0, 0, 0, time.Local),
+		Seconds:     5400,
+		Target:      config.TimeEntryTarget{Project: 7, Task: 70},
+	}
+	id, err := harvest.CreateTimeEntry(context.Background(), entry)
+	if err != nil {
+		t.Fatalf("Failed to create time entry: %v", err)
+	}
+	if id != "636709355" {
+		t.Errorf("CreateTimeEntry() = %q", id)
+	}
+	want := harvestTimeEntry{ProjectID: 7, TaskID: 70, SpentDate: "2024-03-11", Hours: 1.5, Notes: "PROJ-1 Fix login"}
+	if got != want {
+		t.Errorf("Sent %+v, want %+v", got, want)
+	}
+
+	entry.Seconds = 1000
+	if err := harvest.UpdateTimeEntry(context.Background(), id, entry); err != nil {
+		t.Fatalf("Failed to update time entry: %v", err)
+	}
+	if len(requests) != 2 || requests[1] != "PATCH /time_entries/636709355" || got.Hours != 0.28 {
+		t.Errorf("Unexpected update %v %+v", requests, got)
+	}
+}
@@ -0,0 +1,85 @@
+// Package timelog sends tracked time to the time tracking services plannet
+// can push worklogs and time entries to: Tempo Timesheets, Toggl Track and
+// Harvest.
+package timelog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/plannet-ai/plannet/security"
+)
+
+// OnRateLimitWait, if set, is called when a request waits for a rate limit,
+// to show progress
+var OnRateLimitWait func(key string, delay time.Duration)
+
+// restClient sends JSON requests to the REST API of a service
+type restClient struct {
+	name    string // Names the API in errors, such as "Tempo"
+	baseURL string
+	headers map[string]string
+	client  *http.Client
+}
+
+// newRESTClient returns a client for an API that allows limit requests a minute
+func newRESTClient(name, baseURL string, headers map[string]string, limit int) *restClient {
+	rateLimiter := security.NewHTTPRateLimiter(limit, time.Minute)
+	rateLimiter.OnWait = OnRateLimitWait
+	return &restClient{
+		name:    name,
+		baseURL: baseURL,
+		headers: headers,
+		client:  rateLimiter.WrapHTTPClient(&http.Client{Timeout: 30 * time.Second}, name),
+	}
+}
+
+// do sends a request to a path of the API with body encoded as JSON, and
+// decodes the JSON response into out. Either may be nil. Any status other
+// than 2xx is returned as an error.
+func (c *restClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s API request: %w", c.name, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create %s API request: %w", c.name, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s API request: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s API returned status %d: %s", c.name, resp.StatusCode, string(data))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s API response: %w", c.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package timelog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestServer serves handler for the duration of the test and returns the
+// client newClient creates with the configuration cfg returns for the
+// server's URL
+func newTestServer[T any](t *testing.T, handler http.Handler, newClient func(*config.Config) (T, error), cfg func(url string) *config.Config) T {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := newClient(cfg(server.URL))
+	if err != nil {
+		t.Fatalf("Failed to create the client of the test server: %v", err)
+	}
+	return client
+}
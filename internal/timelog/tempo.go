@@ -0,0 +1,123 @@
+package timelog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// DefaultTempoURL is the Tempo Cloud API, used unless another is configured,
+// such as https://api.eu.tempo.io/4 for the EU region
+const DefaultTempoURL = "https://api.tempo.io/4"
+
+// DefaultTempoAccountAttribute is the key of the work attribute that holds
+// the account of a worklog, unless another is configured
+const DefaultTempoAccountAttribute = "_Account_"
+
+// Tempo is a client of the Tempo Timesheets API
+type Tempo struct {
+	api      *restClient
+	settings config.TempoSettings
+}
+
+// TempoWorklog is a worklog to create or update in Tempo
+type TempoWorklog struct {
+	AuthorAccountID  string           `json:"authorAccountId"`
+	IssueID          int              `json:"issueId"`
+	TimeSpentSeconds int              `json:"timeSpentSeconds"`
+	StartDate        string           `json:"startDate"` // YYYY-MM-DD
+	StartTime        string           `json:"startTime"` // HH:MM:SS
+	Description      string           `json:"description,omitempty"`
+	Attributes       []TempoAttribute `json:"attributes,omitempty"`
+}
+
+// TempoAttribute is the value of a work attribute of a worklog, such as
+// its account
+type TempoAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NewTempo creates a Tempo client authenticated with the configured API
+// token or the TEMPO_API_TOKEN environment variable
+func NewTempo(cfg *config.Config) (*Tempo, error) {
+	token := cfg.TempoToken
+	if token == "" {
+		token = os.Getenv("TEMPO_API_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Tempo API token not found. Set tempo_token in the config or TEMPO_API_TOKEN")
+	}
+	baseURL := cfg.Tempo.URL
+	if baseURL == "" {
+		baseURL = DefaultTempoURL
+	}
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	return &Tempo{
+		api:      newRESTClient("Tempo", strings.TrimSuffix(baseURL, "/"), headers, 60),
+		settings: cfg.Tempo,
+	}, nil
+}
+
+// NewTempoWorklog returns a worklog of time spent on an issue from start
+func NewTempoWorklog(authorAccountID string, issueID int, start time.Time, seconds int, description string) TempoWorklog {
+	return TempoWorklog{
+		AuthorAccountID:  authorAccountID,
+		IssueID:          issueID,
+		TimeSpentSeconds: seconds,
+		StartDate:        start.Format("2006-01-02"),
+		StartTime:        start.Format("15:04:05"),
+		Description:      description,
+	}
+}
+
+// CreateWorklog creates a worklog and returns its ID
+func (t *Tempo) CreateWorklog(ctx context.Context, worklog TempoWorklog) (string, error) {
+	var result struct {
+		ID int `json:"tempoWorklogId"`
+	}
+	if err := t.api.do(ctx, "POST", "/worklogs", worklog, &result); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result.ID), nil
+}
+
+// UpdateWorklog replaces the worklog with the given ID
+func (t *Tempo) UpdateWorklog(ctx context.Context, id string, worklog TempoWorklog) error {
+	return t.api.do(ctx, "PUT", "/worklogs/"+id, worklog, nil)
+}
+
+// Attributes returns the work attributes of a worklog of work with the
+// given tags: the account the first of its tags with one is mapped to, and
+// the attributes its tags are mapped to. Earlier tags take precedence.
+func (t *Tempo) Attributes(tags []string) []TempoAttribute {
+	accountAttribute := t.settings.AccountAttribute
+	if accountAttribute == "" {
+		accountAttribute = DefaultTempoAccountAttribute
+	}
+	values := make(map[string]string)
+	for _, tag := range tags {
+		if account, ok := t.settings.Accounts[tag]; ok {
+			if _, set := values[accountAttribute]; !set {
+				values[accountAttribute] = account
+			}
+		}
+		for key, value := range t.settings.Attributes[tag] {
+			if _, set := values[key]; !set {
+				values[key] = value
+			}
+		}
+	}
+
+	var result []TempoAttribute
+	for key, value := range values {
+		result = append(result, TempoAttribute{Key: key, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
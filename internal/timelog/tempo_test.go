@@ -0,0 +1,123 @@
+package timelog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestTempo returns a Tempo client whose API is served by handler
+func newTestTempo(t *testing.T, handler http.HandlerFunc) *Tempo {
+	return newTestServer(t, handler, NewTempo, func(url string) *config.Config {
+		return &config.Config{TempoToken: "secret", Tempo: config.TempoSettings{URL: url + "/"}}
+	})
+}
+
+func TestNewTempoWithoutToken(t *testing.T) {
+	t.Setenv("TEMPO_API_TOKEN", "")
+	if _, err := NewTempo(&config.Config{}); err == nil {
+		t.Error("Expected an error without a token")
+	}
+	t.Setenv("TEMPO_API_TOKEN", "secret")
+	if _, err := NewTempo(&config.Config{}); err != nil {
+		t.Errorf("Failed to create Tempo client with TEMPO_API_TOKEN: %v", err)
+	}
+}
+
+func TestTempoCreateWorklog(t *testing.T) {
+	var got TempoWorklog
+	tempo := newTestTempo(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		if r.Method != "POST" || r.URL.Path != "/worklogs" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode worklog: %v", err)
+		}
+		w.Write([]byte(`{"tempoWorklogId": 4711, "timeSpentSeconds": 5400}`))
+	})
+
+	start := time.Date(2024, 3, 4, 9, 30, 0, 0, time.Local)
+	worklog := NewTempoWorklog("acc-1", 10042, start, 5400, "Fix login")
+	worklog.Attributes = []TempoAttribute{{Key: "_Account_", Value: "ACME"}}
+	id, err := tempo.CreateWorklog(context.Background(), worklog)
+	if err != nil {
+		t.Fatalf("Failed to create worklog: %v", err)
+	}
+	if id != "4711" {
+		t.Errorf("CreateWorklog() = %q, want %q", id, "4711")
+	}
+	want := TempoWorklog{AuthorAccountID: "acc-1", IssueID: 10042, TimeSpentSeconds: 5400, StartDate: "2024-03-04",
+		StartTime: "09:30:00", Description: "Fix login", Attributes: []TempoAttribute{{Key: "_Account_", Value: "ACME"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sent worklog %+v, want %+v", got, want)
+	}
+}
+
+func TestTempoUpdateWorklog(t *testing.T) {
+	var path string
+	tempo := newTestTempo(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		w.Write([]byte(`{"tempoWorklogId": 4711}`))
+	})
+
+	worklog := NewTempoWorklog("acc-1", 10042, time.Now(), 600, "")
+	if err := tempo.UpdateWorklog(context.Background(), "4711", worklog); err != nil {
+		t.Fatalf("Failed to update worklog: %v", err)
+	}
+	if path != "PUT /worklogs/4711" {
+		t.Errorf("Sent %q, want %q", path, "PUT /worklogs/4711")
+	}
+}
+
+func TestTempoError(t *testing.T) {
+	tempo := newTestTempo(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"message": "Issue not found"}]}`))
+	})
+
+	if _, err := tempo.CreateWorklog(context.Background(), TempoWorklog{}); err == nil {
+		t.Error("Expected an error for a bad request")
+	}
+}
+
+func TestTempoAttributes(t *testing.T) {
+	settings := config.TempoSettings{
+		Accounts: map[string]string{"acme": "ACME", "internal": "INT"},
+		Attributes: map[string]map[string]string{
+			"billable": {"_Billable_": "true"},
+			"acme":     {"_Category_": "Development"},
+		},
+	}
+
+	tests := []struct {
+		name             string
+		tags             []string
+		accountAttribute string
+		want             []TempoAttribute
+	}{
+		{"no tags", nil, "", nil},
+		{"unmapped tags", []string{"misc"}, "", nil},
+		{"account", []string{"internal"}, "", []TempoAttribute{{"_Account_", "INT"}}},
+		{"first account wins", []string{"internal", "acme"}, "", []TempoAttribute{{"_Account_", "INT"}, {"_Category_", "Development"}}},
+		{"account and attributes", []string{"billable", "acme"}, "", []TempoAttribute{{"_Account_", "ACME"}, {"_Billable_", "true"}, {"_Category_", "Development"}}},
+		{"custom account attribute", []string{"acme"}, "_Client_", []TempoAttribute{{"_Category_", "Development"}, {"_Client_", "ACME"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings.AccountAttribute = tt.accountAttribute
+			tempo := &Tempo{settings: settings}
+			got := tempo.Attributes(tt.tags)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Attributes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
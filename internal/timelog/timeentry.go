@@ -0,0 +1,55 @@
+package timelog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// TimeEntry is a time entry to create or update in a time tracking service
+type TimeEntry struct {
+	Description string
+	Start       time.Time
+	Seconds     int
+	Tags        []string
+	Target      config.TimeEntryTarget
+}
+
+// TimeEntryService is a time tracking service that tracked work is pushed
+// to as time entries, such as Toggl Track or Harvest
+type TimeEntryService interface {
+	// Name returns the name of the service, such as "toggl"
+	Name() string
+	// Target returns the project and task of work with the given tickets and
+	// tags, and false if the service can't log the work without one
+	Target(ticketIDs, tags []string) (config.TimeEntryTarget, bool)
+	// CreateTimeEntry creates a time entry and returns its ID
+	CreateTimeEntry(ctx context.Context, entry TimeEntry) (string, error)
+	// UpdateTimeEntry replaces the time entry with the given ID
+	UpdateTimeEntry(ctx context.Context, id string, entry TimeEntry) error
+}
+
+// findTarget returns the target of the first tag that has one, or else of
+// the first ticket with a prefix that has one. The longest prefix a ticket
+// starts with is used.
+func findTarget(ticketIDs, tags []string, byPrefix, byTag map[string]config.TimeEntryTarget) (config.TimeEntryTarget, bool) {
+	for _, tag := range tags {
+		if target, ok := byTag[tag]; ok {
+			return target, true
+		}
+	}
+	for _, ticketID := range ticketIDs {
+		longest := ""
+		for prefix := range byPrefix {
+			if strings.HasPrefix(ticketID, prefix) && len(prefix) > len(longest) {
+				longest = prefix
+			}
+		}
+		if longest != "" {
+			return byPrefix[longest], true
+		}
+	}
+	return config.TimeEntryTarget{}, false
+}
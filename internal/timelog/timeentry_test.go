@@ -0,0 +1,37 @@
+package timelog
+
+import (
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestFindTarget(t *testing.T) {
+	byPrefix := map[string]config.TimeEntryTarget{
+		"PROJ-":    {Project: 1, Task: 10},
+		"PROJ-WEB": {Project: 2},
+	}
+	byTag := map[string]config.TimeEntryTarget{"acme": {Project: 3, Task: 30}}
+
+	tests := []struct {
+		name      string
+		ticketIDs []string
+		tags      []string
+		want      config.TimeEntryTarget
+		wantOK    bool
+	}{
+		{"nothing mapped", []string{"DEV-1"}, []string{"misc"}, config.TimeEntryTarget{}, false},
+		{"prefix", []string{"PROJ-12"}, nil, config.TimeEntryTarget{Project: 1, Task: 10}, true},
+		{"longest prefix", []string{"PROJ-WEB-3"}, nil, config.TimeEntryTarget{Project: 2}, true},
+		{"later ticket", []string{"DEV-1", "PROJ-12"}, nil, config.TimeEntryTarget{Project: 1, Task: 10}, true},
+		{"tag before prefix", []string{"PROJ-12"}, []string{"misc", "acme"}, config.TimeEntryTarget{Project: 3, Task: 30}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findTarget(tt.ticketIDs, tt.tags, byPrefix, byTag)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("findTarget() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package timelog
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// DefaultTogglURL is the Toggl Track API, used unless another is configured
+const DefaultTogglURL = "https://api.track.toggl.com/api/v9"
+
+// Toggl is a client of the Toggl Track API
+type Toggl struct {
+	api      *restClient
+	settings config.TogglSettings
+}
+
+// togglTimeEntry is a time entry as the Toggl Track API expects it
+type togglTimeEntry struct {
+	CreatedWith string   `json:"created_with"`
+	Description string   `json:"description"`
+	Start       string   `json:"start"`
+	Duration    int      `json:"duration"` // In seconds
+	WorkspaceID int64    `json:"workspace_id"`
+	ProjectID   int64    `json:"project_id,omitempty"`
+	TaskID      int64    `json:"task_id,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// NewToggl creates a Toggl Track client authenticated with the configured
+// API token or the TOGGL_API_TOKEN environment variable
+func NewToggl(cfg *config.Config) (*Toggl, error) {
+	if cfg.Toggl.Workspace == 0 {
+		return nil, fmt.Errorf("no Toggl workspace configured. Set workspace in the toggl section of the config")
+	}
+	token := cfg.TogglToken
+	if token == "" {
+		token = os.Getenv("TOGGL_API_TOKEN")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Toggl API token not found. Set toggl_token in the config or TOGGL_API_TOKEN")
+	}
+	baseURL := cfg.Toggl.URL
+	if baseURL == "" {
+		baseURL = DefaultTogglURL
+	}
+	// Toggl takes the API token as the user name, with "api_token" as the password
+	auth := base64.StdEncoding.EncodeToString([]byte(token + ":api_token"))
+	headers := map[string]string{"Authorization": "Basic " + auth}
+	return &Toggl{
+		api:      newRESTClient("Toggl", strings.TrimSuffix(baseURL, "/"), headers, 60),
+		settings: cfg.Toggl,
+	}, nil
+}
+
+// Name returns "toggl"
+func (t *Toggl) Name() string {
+	return "toggl"
+}
+
+// Target returns the project and task work is mapped to. Toggl logs work
+// without a project, so all work can be logged.
+func (t *Toggl) Target(ticketIDs, tags []string) (config.TimeEntryTarget, bool) {
+	target, _ := findTarget(ticketIDs, tags, t.settings.Prefixes, t.settings.Tags)
+	return target, true
+}
+
+// CreateTimeEntry creates a time entry in the workspace and returns its ID
+func (t *Toggl) CreateTimeEntry(ctx context.Context, entry TimeEntry) (string, error) {
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	if err := t.api.do(ctx, "POST", t.entriesPath(), t.timeEntry(entry), &result); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result.ID), nil
+}
+
+// UpdateTimeEntry replaces the time entry with the given ID
+func (t *Toggl) UpdateTimeEntry(ctx context.Context, id string, entry TimeEntry) error {
+	return t.api.do(ctx, "PUT", t.entriesPath()+"/"+id, t.timeEntry(entry), nil)
+}
+
+// entriesPath returns the path of the time entries of the workspace
+func (t *Toggl) entriesPath() string {
+	return fmt.Sprintf("/workspaces/%d/time_entries", t.settings.Workspace)
+}
+
+// timeEntry converts a time entry for the API
+func (t *Toggl) timeEntry(entry TimeEntry) togglTimeEntry {
+	return togglTimeEntry{
+		CreatedWith: "plannet",
+		Description: entry.Description,
+		Start:       entry.Start.UTC().Format(time.RFC3339),
+		Duration:    entry.Seconds,
+		WorkspaceID: t.settings.Workspace,
+		ProjectID:   entry.Target.Project,
+		TaskID:      entry.Target.Task,
+		Tags:        entry.Tags,
+	}
+}
@@ -0,0 +1,92 @@
+package timelog
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// newTestToggl returns a Toggl client whose API is served by handler
+func newTestToggl(t *testing.T, handler http.HandlerFunc) *Toggl {
+	return newTestServer(t, handler, NewToggl, func(url string) *config.Config {
+		return &config.Config{
+			TogglToken: "secret",
+			Toggl: config.TogglSettings{
+				URL:       url,
+				Workspace: 42,
+				Prefixes:  map[string]config.TimeEntryTarget{"PROJ-": {Project: 7, Task: 70}},
+			},
+		}
+	})
+}
+
+func TestNewTogglWithoutSettings(t *testing.T) {
+	t.Setenv("TOGGL_API_TOKEN", "")
+	if _, err := NewToggl(&config.Config{TogglToken: "secret"}); err == nil {
+		t.Error("Expected an error without a workspace")
+	}
+	if _, err := NewToggl(&config.Config{Toggl: config.TogglSettings{Workspace: 42}}); err == nil {
+		t.Error("Expected an error without a token")
+	}
+}
+
+func TestTogglCreateTimeEntry(t *testing.T) {
+	var got togglTimeEntry
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte("secret:api_token"))
+		if r.Header.Get("Authorization") != want {
+			t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		if r.Method != "POST" || r.URL.Path != "/workspaces/42/time_entries" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id": 3001, "workspace_id": 42}`))
+	})
+
+	target, ok := toggl.Target([]string{"PROJ-1"}, nil)
+	if !ok || target != (config.TimeEntryTarget{Project: 7, Task: 70}) {
+		t.Fatalf("Target() = %+v, %v", target, ok)
+	}
+	start := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+	id, err := toggl.CreateTimeEntry(context.Background(), TimeEntry{Description: "PROJ-1 Fix login", Start: start, Seconds: 5400, Tags: []string{"backend"}, Target: target})
+	if err != nil {
+		t.Fatalf("Failed to create time entry: %v", err)
+	}
+	if id != "3001" {
+		t.Errorf("CreateTimeEntry() = %q, want %q", id, "3001")
+	}
+	want := togglTimeEntry{CreatedWith: "plannet", Description: "PROJ-1 Fix login", Start: "2024-03-11T09:00:00Z", Duration: 5400,
+		WorkspaceID: 42, ProjectID: 7, TaskID: 70, Tags: []string{"backend"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sent %+v, want %+v", got, want)
+	}
+}
+
+func TestTogglUpdateTimeEntry(t *testing.T) {
+	var path string
+	toggl := newTestToggl(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		w.Write([]byte(`{"id": 3001}`))
+	})
+
+	if err := toggl.UpdateTimeEntry(context.Background(), "3001", TimeEntry{Start: time.Now(), Seconds: 60}); err != nil {
+		t.Fatalf("Failed to update time entry: %v", err)
+	}
+	if path != "PUT /workspaces/42/time_entries/3001" {
+		t.Errorf("Sent %q", path)
+	}
+}
+
+func TestTogglTargetWithoutMapping(t *testing.T) {
+	toggl := &Toggl{}
+	if target, ok := toggl.Target([]string{"DEV-1"}, nil); !ok || target != (config.TimeEntryTarget{}) {
+		t.Errorf("Expected work without a project to be logged, got %+v, %v", target, ok)
+	}
+}
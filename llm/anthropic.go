@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// anthropicVersion is the version of the Anthropic API requests are made for
+const anthropicVersion = "2023-06-01"
+
+// anthropicMaxTokens is the most tokens a reply may have, which the
+// Anthropic API requires
+const anthropicMaxTokens = 4096
+
+// anthropicProvider sends conversations to the Anthropic messages API
+type anthropicProvider struct {
+	baseProvider
+}
+
+// anthropicResponse represents the response from the messages API
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// anthropicUsage is the usage block of the messages API
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicEvent represents an event of a streamed response. The usage of
+// the prompt comes with the message_start event, and that of the reply
+// with the message_delta event that ends it.
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Usage anthropicUsage `json:"usage"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Name returns "anthropic"
+func (p *anthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+// NewRequest creates a messages request, authenticated with the LLM token
+// as the API key
+func (p *anthropicProvider) NewRequest(ctx context.Context, conv Conversation, stream bool) (*http.Request, error) {
+	body := map[string]interface{}{
+		"model":      p.model,
+		"messages":   conv.Messages,
+		"max_tokens": anthropicMaxTokens,
+	}
+	if system := conv.system(); system != "" {
+		body["system"] = system
+	}
+	if stream {
+		body["stream"] = true
+	}
+	auth := map[string]string{"anthropic-version": anthropicVersion}
+	if p.token != "" {
+		auth["x-api-key"] = p.token
+	}
+	return p.newRequest(ctx, body, auth)
+}
+
+// ReadResponse returns the text of the reply's content
+func (p *anthropicProvider) ReadResponse(resp *http.Response, onText func(string)) (string, Usage, error) {
+	var usage Usage
+	if onText == nil || !IsEventStream(resp) {
+		var response anthropicResponse
+		text, err := readWhole(resp, onText, &response, func() (string, error) {
+			usage = Usage{PromptTokens: response.Usage.InputTokens, CompletionTokens: response.Usage.OutputTokens}
+			var text strings.Builder
+			for _, content := range response.Content {
+				if content.Type == "text" {
+					text.WriteString(content.Text)
+				}
+			}
+			if text.Len() == 0 {
+				return "", fmt.Errorf("no response from LLM")
+			}
+			return text.String(), nil
+		})
+		return text, usage, err
+	}
+	text, err := readStream(resp.Body, onText, func(data []byte) (string, error) {
+		var event anthropicEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return "", fmt.Errorf("error parsing response: %w", err)
+		}
+		switch event.Type {
+		case "message_start":
+			usage.PromptTokens = event.Message.Usage.InputTokens
+		case "message_delta":
+			usage.CompletionTokens = event.Usage.OutputTokens
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" {
+				return event.Delta.Text, nil
+			}
+		case "error":
+			return "", fmt.Errorf("API error: %s", event.Error.Message)
+		}
+		return "", nil
+	})
+	return text, usage, err
+}
@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/security"
+)
+
+const (
+	// defaultTimeout is how long to wait for the LLM to start responding,
+	// unless configured otherwise
+	defaultTimeout = 60 * time.Second
+	// defaultMaxRetries is how many times failed requests are retried,
+	// unless configured otherwise
+	defaultMaxRetries = 3
+)
+
+// OnRateLimitWait, if set, is called when an LLM request waits for a rate
+// limit or before it is retried, to show progress
+var OnRateLimitWait func(key string, delay time.Duration)
+
+// NewClient returns a client for LLM requests: limited to 5 requests a
+// minute, retrying with exponential backoff requests that are rate limited
+// or fail for reasons likely to pass, and timing out requests the LLM
+// doesn't start responding to. Streamed responses can take as long as they
+// need once they have started.
+func NewClient(cfg *config.Config) *http.Client {
+	rateLimiter := security.NewHTTPRateLimiter(5, time.Minute)
+	rateLimiter.OnWait = OnRateLimitWait
+	rateLimiter.RetryTransient = true
+	rateLimiter.MaxRetries = defaultMaxRetries
+	if cfg.LLMMaxRetries < 0 {
+		rateLimiter.MaxRetries = 0
+	} else if cfg.LLMMaxRetries > 0 {
+		rateLimiter.MaxRetries = cfg.LLMMaxRetries
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = defaultTimeout
+	if cfg.LLMTimeoutSeconds > 0 {
+		transport.ResponseHeaderTimeout = time.Duration(cfg.LLMTimeoutSeconds) * time.Second
+	}
+	return rateLimiter.WrapHTTPClient(&http.Client{Transport: transport}, "llm")
+}
+
+// ErrUnavailable is returned without sending a request when the provider
+// failed every recent request, even after retrying them
+var ErrUnavailable = errors.New("LLM provider unavailable")
+
+// breakerThreshold is how many requests in a row have to fail for the
+// breaker of a provider to stop sending it requests
+const breakerThreshold = 3
+
+// breakerCooldown is how long a breaker stops sending requests, before it
+// lets one through to see whether the provider is back
+var breakerCooldown = 30 * time.Second
+
+// breaker stops sending requests to an endpoint that keeps failing them, so
+// that each attempt doesn't wait for its timeouts and retries
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	lastErr   error
+	openUntil time.Time
+}
+
+// breakers are the breakers of the endpoints, by URL
+var breakers = struct {
+	sync.Mutex
+	byURL map[string]*breaker
+}{byURL: map[string]*breaker{}}
+
+// breakerFor returns the breaker of an endpoint
+func breakerFor(url string) *breaker {
+	breakers.Lock()
+	defer breakers.Unlock()
+	b, ok := breakers.byURL[url]
+	if !ok {
+		b = &breaker{}
+		breakers.byURL[url] = b
+	}
+	return b
+}
+
+// allow returns an error wrapping ErrUnavailable while the breaker is open
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return fmt.Errorf("%w: the last %d requests failed, the last with: %v; try again in %s",
+			ErrUnavailable, b.failures, b.lastErr, wait.Round(time.Second))
+	}
+	return nil
+}
+
+// succeeded records a request the provider answered
+func (b *breaker) succeeded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.lastErr = nil
+	b.openUntil = time.Time{}
+}
+
+// failed records a request the provider failed, opening the breaker once
+// enough have failed in a row
+func (b *breaker) failed(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastErr = err
+	if b.failures >= breakerThreshold {
+		b.openUntil = time.Now().Add(breakerCooldown)
+	}
+}
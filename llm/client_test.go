@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestCompleteRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(529)
+			return
+		}
+		fmt.Fprint(w, `{"content": [{"type": "text", "text": "Hello"}]}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Provider: "anthropic", BaseURL: server.URL, Model: "m"}
+	provider, _ := NewProvider(cfg)
+	client := NewClient(cfg)
+	text, _, err := Complete(context.Background(), client, provider, UserPrompt("", "Hi"), nil)
+	if err != nil || text != "Hello" {
+		t.Errorf("Complete() = %q, %v, want the reply to the retried request", text, err)
+	}
+	if requests != 2 {
+		t.Errorf("Sent %d requests, want 2", requests)
+	}
+}
+
+func TestCompleteTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := &config.Config{BaseURL: server.URL, Model: "m", LLMTimeoutSeconds: 1, LLMMaxRetries: -1}
+	t.Cleanup(func() { delete(breakers.byURL, server.URL) })
+	provider, _ := NewProvider(cfg)
+	start := time.Now()
+	if _, _, err := Complete(context.Background(), NewClient(cfg), provider, UserPrompt("", "Hi"), nil); err == nil {
+		t.Error("Complete() succeeded, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Complete() took %s, want it to time out after a second", elapsed)
+	}
+}
+
+func TestCompleteBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Provider: "ollama", BaseURL: server.URL, Model: "m", LLMMaxRetries: -1}
+	t.Cleanup(func() { delete(breakers.byURL, server.URL) })
+	provider, _ := NewProvider(cfg)
+	client := NewClient(cfg)
+	for i := 0; i < breakerThreshold; i++ {
+		_, _, err := Complete(context.Background(), client, provider, UserPrompt("", "Hi"), nil)
+		if err == nil || errors.Is(err, ErrUnavailable) {
+			t.Fatalf("Request %d: Complete() error = %v, want the API's error", i+1, err)
+		}
+	}
+
+	// The provider is left alone while the breaker is open
+	_, _, err := Complete(context.Background(), client, provider, UserPrompt("", "Hi"), nil)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Complete() error = %v, want ErrUnavailable", err)
+	}
+	if requests != breakerThreshold {
+		t.Errorf("Sent %d requests, want %d", requests, breakerThreshold)
+	}
+
+	// Once it cools down, a request goes through again
+	breakerFor(server.URL).openUntil = time.Now()
+	Complete(context.Background(), client, provider, UserPrompt("", "Hi"), nil)
+	if requests != breakerThreshold+1 {
+		t.Errorf("Sent %d requests after the cooldown, want %d", requests, breakerThreshold+1)
+	}
+}
@@ -0,0 +1,35 @@
+package llm
+
+// EstimateTokens estimates how many tokens text takes, at about four
+// characters a token, which is close enough for English text and code to
+// keep a conversation within a model's window
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Tokens estimates how many tokens the conversation takes
+func (c Conversation) Tokens() int {
+	tokens := EstimateTokens(c.System)
+	for _, message := range c.Messages {
+		tokens += EstimateTokens(message.Content)
+	}
+	return tokens
+}
+
+// Truncate returns the conversation without its oldest messages, so that it
+// fits in maxTokens. The system prompt and the last message are always
+// kept, and the conversation still starts with a message from the user, as
+// some APIs require. maxTokens of 0 or less keeps every message.
+func (c Conversation) Truncate(maxTokens int) Conversation {
+	if maxTokens <= 0 {
+		return c
+	}
+	messages := c.Messages
+	tokens := c.Tokens()
+	for len(messages) > 1 && (tokens > maxTokens || messages[0].Role != "user") {
+		tokens -= EstimateTokens(messages[0].Content)
+		messages = messages[1:]
+	}
+	c.Messages = messages
+	return c
+}
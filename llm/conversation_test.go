@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConversationTruncate(t *testing.T) {
+	long := strings.Repeat("word ", 20) // 25 tokens
+	conv := Conversation{
+		System: "Be brief.", // 3 tokens
+		Messages: []Message{
+			{Role: "user", Content: long},
+			{Role: "assistant", Content: long},
+			{Role: "user", Content: long},
+			{Role: "assistant", Content: long},
+			{Role: "user", Content: "And now?"}, // 2 tokens
+		},
+	}
+
+	if got := conv.Truncate(0); !reflect.DeepEqual(got, conv) {
+		t.Errorf("Truncate(0) = %+v, want the whole conversation", got)
+	}
+	if got := conv.Truncate(1000); !reflect.DeepEqual(got, conv) {
+		t.Errorf("Truncate(1000) = %+v, want the whole conversation", got)
+	}
+
+	// Dropping the first message would leave the assistant's reply first
+	got := conv.Truncate(80)
+	if !reflect.DeepEqual(got.Messages, conv.Messages[2:]) || got.System != conv.System {
+		t.Errorf("Truncate(80) kept %d messages, want the last 3", len(got.Messages))
+	}
+
+	// The last message is kept even when it doesn't fit
+	got = conv.Truncate(1)
+	if !reflect.DeepEqual(got.Messages, conv.Messages[4:]) {
+		t.Errorf("Truncate(1) kept %d messages, want the last one", len(got.Messages))
+	}
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// LocalModel is a model served by an LLM server running on this machine
+type LocalModel struct {
+	Server   string // The server's name, such as "Ollama"
+	Provider string
+	BaseURL  string
+	Model    string
+}
+
+// localServer is an LLM server that runs on this machine at a well-known
+// address, and lists its models
+type localServer struct {
+	name     string
+	provider string
+	baseURL  string // What requests are sent to
+	modelURL string // Where the models are listed
+	// models reads the names of the models from the listing
+	models func(data []byte) ([]string, error)
+}
+
+// localServers are the local servers DiscoverLocalModels looks for
+var localServers = []localServer{
+	{
+		name:     "Ollama",
+		provider: ProviderOllama,
+		baseURL:  "http://localhost:11434/api/chat",
+		modelURL: "http://localhost:11434/api/tags",
+		models:   ollamaModels,
+	},
+	{
+		// LM Studio serves an OpenAI-compatible API
+		name:     "LM Studio",
+		provider: ProviderOpenAI,
+		baseURL:  "http://localhost:1234/v1/chat/completions",
+		modelURL: "http://localhost:1234/v1/models",
+		models:   openAIModels,
+	},
+}
+
+// ollamaModels reads the names of the models from Ollama's listing
+func ollamaModels(data []byte) ([]string, error) {
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, model := range tags.Models {
+		names = append(names, model.Name)
+	}
+	return names, nil
+}
+
+// openAIModels reads the names of the models from an OpenAI-style listing
+func openAIModels(data []byte) ([]string, error) {
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, model := range list.Data {
+		names = append(names, model.ID)
+	}
+	return names, nil
+}
+
+// discoverTimeout is how long a local server has to list its models;
+// servers that are running answer at once
+const discoverTimeout = time.Second
+
+// DiscoverLocalModels returns the models of the LLM servers running on this
+// machine, such as Ollama and LM Studio, by server and name. Servers that
+// aren't running are left out.
+func DiscoverLocalModels(ctx context.Context) []LocalModel {
+	client := &http.Client{Timeout: discoverTimeout}
+	var found []LocalModel
+	for _, server := range localServers {
+		names, err := server.list(ctx, client)
+		if err != nil {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			found = append(found, LocalModel{
+				Server:   server.name,
+				Provider: server.provider,
+				BaseURL:  server.baseURL,
+				Model:    name,
+			})
+		}
+	}
+	return found
+}
+
+// list returns the names of the server's models
+func (s localServer) list(ctx context.Context, client *http.Client) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.modelURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", s.name, resp.StatusCode)
+	}
+	var data json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	return s.models(data)
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverLocalModels(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"models": [{"name": "qwen2.5:7b"}, {"name": "llama3.1:latest"}]}`)
+	}))
+	defer ollama.Close()
+	lmStudio := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": [{"id": "mistral-7b-instruct"}]}`)
+	}))
+	defer lmStudio.Close()
+	stopped := httptest.NewServer(http.NotFoundHandler())
+	stopped.Close()
+
+	saved := localServers
+	defer func() { localServers = saved }()
+	localServers = []localServer{
+		{name: "Ollama", provider: ProviderOllama, baseURL: ollama.URL + "/api/chat", modelURL: ollama.URL + "/api/tags", models: ollamaModels},
+		{name: "Stopped", provider: ProviderOpenAI, baseURL: stopped.URL, modelURL: stopped.URL + "/v1/models", models: openAIModels},
+		{name: "LM Studio", provider: ProviderOpenAI, baseURL: lmStudio.URL + "/v1/chat/completions", modelURL: lmStudio.URL + "/v1/models", models: openAIModels},
+	}
+
+	want := []LocalModel{
+		{Server: "Ollama", Provider: ProviderOllama, BaseURL: ollama.URL + "/api/chat", Model: "llama3.1:latest"},
+		{Server: "Ollama", Provider: ProviderOllama, BaseURL: ollama.URL + "/api/chat", Model: "qwen2.5:7b"},
+		{Server: "LM Studio", Provider: ProviderOpenAI, BaseURL: lmStudio.URL + "/v1/chat/completions", Model: "mistral-7b-instruct"},
+	}
+	if got := DiscoverLocalModels(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiscoverLocalModels() = %+v, want %+v", got, want)
+	}
+}
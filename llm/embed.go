@@ -0,0 +1,206 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/security"
+)
+
+// Embedder turns text into embedding vectors, whose closeness measures how
+// alike the meaning of the texts is
+type Embedder interface {
+	// Name identifies the model, such as "ollama/nomic-embed-text". Vectors
+	// of different models can't be compared.
+	Name() string
+	// Embed returns the vector of each text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// defaultEmbeddingModels are the embedding models used unless configured
+// otherwise, by provider
+var defaultEmbeddingModels = map[string]string{
+	ProviderOpenAI: "text-embedding-3-small",
+	ProviderOllama: "nomic-embed-text",
+}
+
+// defaultEmbeddingURLs are the embeddings endpoints of the providers
+var defaultEmbeddingURLs = map[string]string{
+	ProviderOpenAI: "https://api.openai.com/v1/embeddings",
+	ProviderOllama: "http://localhost:11434/api/embed",
+}
+
+// embeddingBatchSize is the most texts sent in one request
+const embeddingBatchSize = 64
+
+// NewEmbedder creates the configured embedder. Without an embeddings
+// provider, the LLM's is used if it serves embeddings, at the endpoint
+// next to the LLM's and with its credentials.
+func NewEmbedder(cfg *config.Config) (Embedder, error) {
+	settings := cfg.Embeddings
+	// Completions APIs are OpenAI-compatible, and serve embeddings as OpenAI does
+	llmProvider := ProviderName(cfg)
+	if llmProvider == ProviderCompletions {
+		llmProvider = ProviderOpenAI
+	}
+	provider := strings.ToLower(settings.Provider)
+	if provider == "" {
+		provider = llmProvider
+	}
+	if _, ok := defaultEmbeddingModels[provider]; !ok {
+		return nil, fmt.Errorf("embeddings need an OpenAI-compatible or Ollama provider; set embeddings.provider in your configuration")
+	}
+
+	base := baseProvider{
+		url:   settings.BaseURL,
+		model: settings.Model,
+		token: settings.Token,
+	}
+	if provider == llmProvider {
+		if base.url == "" && cfg.BaseURL != "" {
+			base.url = embeddingURL(cfg.BaseURL)
+		}
+		if base.token == "" {
+			base.token = cfg.LLMToken
+			base.headers = cfg.Headers
+		}
+	}
+	if base.url == "" {
+		base.url = defaultEmbeddingURLs[provider]
+	}
+	if base.model == "" {
+		base.model = defaultEmbeddingModels[provider]
+	}
+
+	redactor, err := security.NewRedactor(cfg.LLMRedactPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid llm_redact_patterns: %w", err)
+	}
+	return &embedder{
+		baseProvider: base,
+		provider:     provider,
+		client:       NewClient(cfg),
+		redactor:     redactor,
+	}, nil
+}
+
+// embeddingURL returns the embeddings endpoint next to an LLM endpoint,
+// such as /v1/embeddings for /v1/chat/completions
+func embeddingURL(llmURL string) string {
+	url := strings.TrimSuffix(llmURL, "/")
+	for _, suffix := range []string{"/chat/completions", "/completions", "/api/chat", "/api/generate"} {
+		if strings.HasSuffix(url, suffix) {
+			if strings.HasPrefix(suffix, "/api/") {
+				return strings.TrimSuffix(url, suffix) + "/api/embed"
+			}
+			return strings.TrimSuffix(url, suffix) + "/embeddings"
+		}
+	}
+	return url
+}
+
+// embedder requests embeddings from an OpenAI-compatible or Ollama API.
+// Secrets are masked in texts before they are sent, as in conversations.
+type embedder struct {
+	baseProvider
+	provider string
+	client   *http.Client
+	redactor *security.Redactor
+}
+
+// Name returns the provider and model
+func (e *embedder) Name() string {
+	return e.provider + "/" + e.model
+}
+
+// Embed requests the vectors of the texts, a batch at a time
+func (e *embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		end := start + embeddingBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batch, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// embedBatch requests the vectors of a batch of texts
+func (e *embedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	found := security.Redactions{}
+	input := make([]string, len(texts))
+	for i, text := range texts {
+		input[i] = e.redactor.Redact(text, found)
+	}
+
+	req, err := e.newRequest(ctx, map[string]interface{}{"model": e.model, "input": input}, e.bearerAuth())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("embeddings request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response struct {
+		// Data is the response of OpenAI-compatible APIs
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+		// Embeddings is the response of Ollama
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing embeddings: %w", err)
+	}
+
+	vectors := response.Embeddings
+	if e.provider == ProviderOpenAI {
+		vectors = make([][]float32, len(response.Data))
+		for _, data := range response.Data {
+			if data.Index < 0 || data.Index >= len(vectors) {
+				return nil, fmt.Errorf("error parsing embeddings: index %d out of range", data.Index)
+			}
+			vectors[data.Index] = data.Embedding
+		}
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(vectors), len(texts))
+	}
+	return vectors, nil
+}
+
+// CosineSimilarity returns how alike two vectors point, from -1 to 1, or 0
+// for vectors of different lengths
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestReadEvents(t *testing.T) {
+	stream := "event: message\ndata: one\n\n: comment\ndata: two\ndata: lines\n\ndata:three\n\ndata: [DONE]\n\ndata: after\n\n"
+	var events []string
+	err := ReadEvents(strings.NewReader(stream), func(data []byte) error {
+		events = append(events, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadEvents() failed: %v", err)
+	}
+	want := []string{"one", "two\nlines", "three"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("ReadEvents() read %q, want %q", events, want)
+	}
+
+	// A stream can end without a blank line after its last event
+	events = nil
+	ReadEvents(strings.NewReader("data: last"), func(data []byte) error {
+		events = append(events, string(data))
+		return nil
+	})
+	if !reflect.DeepEqual(events, []string{"last"}) {
+		t.Errorf("ReadEvents() read %q, want the last event", events)
+	}
+}
+
+func TestGeneratorStream(t *testing.T) {
+	tests := map[string]func(w http.ResponseWriter){
+		"streamed": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"text\":\"Hello\"}]}\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[{\"text\":\", world\"}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		},
+		"not streamed": func(w http.ResponseWriter) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"choices":[{"text":"Hello, world"}]}`)
+		},
+	}
+	for name, respond := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer key" {
+					t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+				}
+				respond(w)
+			}))
+			defer server.Close()
+
+			generator := NewGenerator(&config.Config{
+				BaseURL: server.URL,
+				Model:   "test-model",
+				Headers: map[string]string{"Authorization": "Bearer key"},
+			})
+			var streamed strings.Builder
+			text, err := generator.Stream(context.Background(), "Say hello", func(text string) {
+				streamed.WriteString(text)
+			})
+			if err != nil {
+				t.Fatalf("Stream() failed: %v", err)
+			}
+			if text != "Hello, world" || streamed.String() != text {
+				t.Errorf("Stream() = %q, streamed %q", text, streamed.String())
+			}
+		})
+	}
+}
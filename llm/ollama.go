@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ollamaProvider sends conversations to the chat API of Ollama, which runs
+// models locally
+type ollamaProvider struct {
+	baseProvider
+}
+
+// ollamaResponse represents the response from the chat API. A streamed
+// response is a line of JSON for each piece of the message.
+type ollamaResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Done  bool   `json:"done"`
+	Error string `json:"error"`
+	// The tokens used are reported with the last piece of the response
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+// usage returns the tokens reported
+func (r ollamaResponse) usage() Usage {
+	return Usage{PromptTokens: r.PromptEvalCount, CompletionTokens: r.EvalCount}
+}
+
+// Name returns "ollama"
+func (p *ollamaProvider) Name() string {
+	return ProviderOllama
+}
+
+// NewRequest creates a chat request, with the system prompt as the first
+// message, and the conversation's schema as the format of the reply. Ollama needs no authentication, but the LLM token is sent if
+// set, for servers behind an authenticating proxy.
+func (p *ollamaProvider) NewRequest(ctx context.Context, conv Conversation, stream bool) (*http.Request, error) {
+	var messages []Message
+	if system := conv.system(); system != "" {
+		messages = append(messages, Message{Role: "system", Content: system})
+	}
+	messages = append(messages, conv.Messages...)
+
+	// Ollama streams unless told otherwise
+	body := map[string]interface{}{
+		"model":    p.model,
+		"messages": messages,
+		"stream":   stream,
+	}
+	if conv.Schema != nil {
+		body["format"] = conv.Schema
+	}
+	return p.newRequest(ctx, body, p.bearerAuth())
+}
+
+// ReadResponse returns the reply's message, read line by line when streamed
+func (p *ollamaProvider) ReadResponse(resp *http.Response, onText func(string)) (string, Usage, error) {
+	if onText == nil {
+		var response ollamaResponse
+		text, err := readWhole(resp, onText, &response, func() (string, error) {
+			if response.Error != "" {
+				return "", fmt.Errorf("API error: %s", response.Error)
+			}
+			return response.Message.Content, nil
+		})
+		return text, response.usage(), err
+	}
+
+	var usage Usage
+	var reply strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var chunk ollamaResponse
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return reply.String(), usage, fmt.Errorf("error parsing response: %w", err)
+		}
+		if chunk.Error != "" {
+			return reply.String(), usage, fmt.Errorf("API error: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			reply.WriteString(chunk.Message.Content)
+			onText(chunk.Message.Content)
+		}
+		if chunk.Done {
+			usage = chunk.usage()
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return reply.String(), usage, fmt.Errorf("error reading response: %w", err)
+	}
+	return reply.String(), usage, nil
+}
@@ -0,0 +1,202 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// completionsProvider sends prompts to a text completions API, turning the
+// conversation into a transcript for the model to continue
+type completionsProvider struct {
+	baseProvider
+}
+
+// Request represents the request body for a completions API
+type Request struct {
+	Model     string `json:"model"`
+	Prompt    string `json:"prompt"`
+	MaxTokens int    `json:"max_tokens,omitempty"`
+	Stream    bool   `json:"stream,omitempty"`
+}
+
+// Response represents the response from a completions API
+type Response struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        int    `json:"index"`
+		Text         string `json:"text"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage openAIUsage `json:"usage"`
+}
+
+// openAIUsage is the usage block of OpenAI-style APIs
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// usage returns the tokens reported
+func (u openAIUsage) usage() Usage {
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+// Name returns "completions"
+func (p *completionsProvider) Name() string {
+	return ProviderCompletions
+}
+
+// NewRequest creates a completions request for the conversation's transcript
+func (p *completionsProvider) NewRequest(ctx context.Context, conv Conversation, stream bool) (*http.Request, error) {
+	body := Request{
+		Model:  p.model,
+		Prompt: formatPrompt(conv),
+		Stream: stream,
+	}
+	return p.newRequest(ctx, body, p.bearerAuth())
+}
+
+// ReadResponse returns the text of the first choice
+func (p *completionsProvider) ReadResponse(resp *http.Response, onText func(string)) (string, Usage, error) {
+	var usage Usage
+	if onText == nil || !IsEventStream(resp) {
+		var response Response
+		text, err := readWhole(resp, onText, &response, func() (string, error) {
+			usage = response.Usage.usage()
+			if len(response.Choices) == 0 {
+				return "", fmt.Errorf("no choices in response")
+			}
+			return response.Choices[0].Text, nil
+		})
+		return text, usage, err
+	}
+	text, err := readStream(resp.Body, onText, func(data []byte) (string, error) {
+		var chunk Response
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return "", fmt.Errorf("error parsing response: %w", err)
+		}
+		// Servers that report usage when streaming do so in the last event
+		if chunk.Usage.TotalTokens > 0 {
+			usage = chunk.Usage.usage()
+		}
+		if len(chunk.Choices) == 0 {
+			return "", nil
+		}
+		return chunk.Choices[0].Text, nil
+	})
+	return text, usage, err
+}
+
+// formatPrompt formats a conversation as the transcript a completion model
+// expects, ending where the assistant's reply starts
+func formatPrompt(conv Conversation) string {
+	var prompt strings.Builder
+	if system := conv.system(); system != "" {
+		prompt.WriteString(system + "\n\n")
+	}
+	for _, message := range conv.Messages {
+		role := "User"
+		if message.Role == "assistant" {
+			role = "Assistant"
+		}
+		fmt.Fprintf(&prompt, "%s: %s\n\n", role, message.Content)
+	}
+	prompt.WriteString("Assistant:")
+	return prompt.String()
+}
+
+// openAIProvider sends conversations to the OpenAI chat completions API, or
+// another API compatible with it
+type openAIProvider struct {
+	baseProvider
+}
+
+// chatResponse represents the response from the chat completions API; a
+// streamed response sends the message in deltas
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	// Usage is only sent at the end of a streamed response when the request
+	// asks for it with stream_options
+	Usage *openAIUsage `json:"usage"`
+}
+
+// Name returns "openai"
+func (p *openAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+// NewRequest creates a chat completions request, with the system prompt as
+// the first message, and the conversation's schema as the response format
+func (p *openAIProvider) NewRequest(ctx context.Context, conv Conversation, stream bool) (*http.Request, error) {
+	var messages []Message
+	if system := conv.system(); system != "" {
+		messages = append(messages, Message{Role: "system", Content: system})
+	}
+	messages = append(messages, conv.Messages...)
+
+	body := map[string]interface{}{
+		"model":    p.model,
+		"messages": messages,
+	}
+	if conv.Schema != nil {
+		body["response_format"] = map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   "reply",
+				"schema": conv.Schema,
+				"strict": true,
+			},
+		}
+	}
+	if stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]bool{"include_usage": true}
+	}
+	return p.newRequest(ctx, body, p.bearerAuth())
+}
+
+// ReadResponse returns the message of the first choice
+func (p *openAIProvider) ReadResponse(resp *http.Response, onText func(string)) (string, Usage, error) {
+	var usage Usage
+	if onText == nil || !IsEventStream(resp) {
+		var response chatResponse
+		text, err := readWhole(resp, onText, &response, func() (string, error) {
+			if response.Usage != nil {
+				usage = response.Usage.usage()
+			}
+			if len(response.Choices) == 0 {
+				return "", fmt.Errorf("no response from LLM")
+			}
+			return response.Choices[0].Message.Content, nil
+		})
+		return text, usage, err
+	}
+	text, err := readStream(resp.Body, onText, func(data []byte) (string, error) {
+		var chunk chatResponse
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			return "", fmt.Errorf("error parsing response: %w", err)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.usage()
+		}
+		if len(chunk.Choices) == 0 {
+			return "", nil
+		}
+		return chunk.Choices[0].Delta.Content, nil
+	})
+	return text, usage, err
+}
@@ -0,0 +1,279 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// The LLM providers, as selected by the provider setting
+const (
+	// ProviderCompletions is a text completions API, such as OpenAI's legacy
+	// /v1/completions, which most local servers also offer
+	ProviderCompletions = "completions"
+	// ProviderOpenAI is the OpenAI chat completions API
+	ProviderOpenAI = "openai"
+	// ProviderAnthropic is the Anthropic messages API
+	ProviderAnthropic = "anthropic"
+	// ProviderOllama is the chat API of Ollama
+	ProviderOllama = "ollama"
+)
+
+// Message is a message in a conversation with the LLM
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Conversation is what the LLM is asked to reply to
+type Conversation struct {
+	// System is the system prompt, if any
+	System   string
+	Messages []Message
+	// Schema is the JSON schema the reply must follow, if any. Providers
+	// that can enforce a schema are asked to; the others are told to
+	// follow it in the system prompt.
+	Schema json.RawMessage
+}
+
+// UserPrompt returns a conversation of a single prompt from the user
+func UserPrompt(system, prompt string) Conversation {
+	return Conversation{
+		System:   system,
+		Messages: []Message{{Role: "user", Content: prompt}},
+	}
+}
+
+// Provider sends conversations to the API of an LLM provider, in its format
+type Provider interface {
+	// Name returns the name the provider is selected by
+	Name() string
+	// NewRequest creates a request for the reply to a conversation, with
+	// the provider's authentication. stream asks for the reply to be
+	// streamed.
+	NewRequest(ctx context.Context, conv Conversation, stream bool) (*http.Request, error)
+	// ReadResponse returns the reply of a successful response, and the
+	// tokens it used, if the API reported them. With onText, it is called
+	// with each piece of a streamed reply as it arrives, or with the whole
+	// reply if the API didn't stream it.
+	ReadResponse(resp *http.Response, onText func(string)) (string, Usage, error)
+}
+
+// defaultURLs are the endpoints of the providers whose API has a well-known
+// URL, used when no base URL is configured
+var defaultURLs = map[string]string{
+	ProviderOpenAI:    "https://api.openai.com/v1/chat/completions",
+	ProviderAnthropic: "https://api.anthropic.com/v1/messages",
+	ProviderOllama:    "http://localhost:11434/api/chat",
+}
+
+// ProviderName returns the name of the configured provider. Without one,
+// it is told from the base URL's path, which is a completions API unless
+// it is that of another provider's API.
+func ProviderName(cfg *config.Config) string {
+	if cfg.Provider != "" {
+		return strings.ToLower(cfg.Provider)
+	}
+	switch path := strings.TrimSuffix(cfg.BaseURL, "/"); {
+	case strings.HasSuffix(path, "/chat/completions"):
+		return ProviderOpenAI
+	case strings.HasSuffix(path, "/v1/messages"):
+		return ProviderAnthropic
+	case strings.HasSuffix(path, "/api/chat"):
+		return ProviderOllama
+	default:
+		return ProviderCompletions
+	}
+}
+
+// endpoint returns the configured base URL, or the provider's default
+func endpoint(cfg *config.Config) string {
+	if cfg.BaseURL != "" {
+		return cfg.BaseURL
+	}
+	return defaultURLs[ProviderName(cfg)]
+}
+
+// IsConfigured reports whether the LLM integration is set up: a model, and
+// an endpoint to send prompts to
+func IsConfigured(cfg *config.Config) bool {
+	return cfg.Model != "" && endpoint(cfg) != ""
+}
+
+// NeedsToken reports whether prompts need an LLM token. Ollama and servers
+// on this machine, such as the LM Studio models 'plannet init' finds, run
+// without one.
+func NeedsToken(cfg *config.Config) bool {
+	if ProviderName(cfg) == ProviderOllama {
+		return false
+	}
+	u, err := url.Parse(endpoint(cfg))
+	if err != nil {
+		return true
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || !ip.IsLoopback()
+}
+
+// NewProvider creates the configured provider
+func NewProvider(cfg *config.Config) (Provider, error) {
+	base := baseProvider{
+		url:     endpoint(cfg),
+		model:   cfg.Model,
+		token:   cfg.LLMToken,
+		headers: cfg.Headers,
+	}
+	switch name := ProviderName(cfg); name {
+	case ProviderCompletions:
+		return &completionsProvider{base}, nil
+	case ProviderOpenAI:
+		return &openAIProvider{base}, nil
+	case ProviderAnthropic:
+		return &anthropicProvider{base}, nil
+	case ProviderOllama:
+		return &ollamaProvider{base}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q, expected %s, %s, %s or %s",
+			name, ProviderCompletions, ProviderOpenAI, ProviderAnthropic, ProviderOllama)
+	}
+}
+
+// Complete sends a conversation to the provider with client, such as one
+// from NewClient, and returns the reply. With onText, the reply is
+// streamed, and onText is called with each piece of it as it arrives.
+// Cancelling ctx stops the request, and what arrived until then is
+// returned with the context's error. Once several requests in a row have
+// failed, requests fail with ErrUnavailable for a while without being sent.
+func Complete(ctx context.Context, client *http.Client, provider Provider, conv Conversation, onText func(string)) (string, Usage, error) {
+	req, err := provider.NewRequest(ctx, conv, onText != nil)
+	if err != nil {
+		return "", Usage{}, err
+	}
+
+	breaker := breakerFor(req.URL.String())
+	if err := breaker.allow(); err != nil {
+		return "", Usage{}, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", Usage{}, ctx.Err()
+		}
+		err = fmt.Errorf("error making request: %w", err)
+		breaker.failed(err)
+		return "", Usage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		err := fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		// Only failures of the provider count, not those of the request
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			breaker.failed(err)
+		}
+		return "", Usage{}, err
+	}
+	breaker.succeeded()
+
+	text, usage, err := provider.ReadResponse(resp, onText)
+	if ctx.Err() != nil {
+		return text, usage, ctx.Err()
+	}
+	return text, usage, err
+}
+
+// baseProvider holds the settings every provider uses
+type baseProvider struct {
+	url     string
+	model   string
+	token   string            // The LLM token, sent as the provider authenticates
+	headers map[string]string // Configured headers, sent as they are
+}
+
+// newRequest creates a request posting body as JSON, with the configured
+// headers and then auth, the provider's authentication headers, unless a
+// configured header already sets them
+func (p *baseProvider) newRequest(ctx context.Context, body interface{}, auth map[string]string) (*http.Request, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+	for key, value := range auth {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+	return req, nil
+}
+
+// bearerAuth returns the Authorization header for the LLM token, if any
+func (p *baseProvider) bearerAuth() map[string]string {
+	if p.token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.token}
+}
+
+// readWhole decodes a response that wasn't streamed into out, then passes
+// the reply text returns on to onText, if any
+func readWhole(resp *http.Response, onText func(string), out interface{}, text func() (string, error)) (string, error) {
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("error parsing response: %w", err)
+	}
+	reply, err := text()
+	if err != nil {
+		return "", err
+	}
+	if onText != nil {
+		onText(reply)
+	}
+	return reply, nil
+}
+
+// readStream reads a reply streamed as server-sent events, calling onText
+// with the text parse returns for each event, and returns the whole reply
+func readStream(body io.Reader, onText func(string), parse func(data []byte) (string, error)) (string, error) {
+	var reply strings.Builder
+	err := ReadEvents(body, func(data []byte) error {
+		text, err := parse(data)
+		if err != nil {
+			return err
+		}
+		if text != "" {
+			reply.WriteString(text)
+			onText(text)
+		}
+		return nil
+	})
+	if err != nil {
+		return reply.String(), fmt.Errorf("error reading response: %w", err)
+	}
+	if reply.Len() == 0 {
+		return "", fmt.Errorf("no response from LLM")
+	}
+	return reply.String(), nil
+}
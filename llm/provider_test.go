@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestProviderName(t *testing.T) {
+	tests := []struct {
+		cfg  config.Config
+		want string
+	}{
+		{config.Config{BaseURL: "http://localhost:1234/v1/completions"}, ProviderCompletions},
+		{config.Config{BaseURL: "https://api.openai.com/v1/chat/completions"}, ProviderOpenAI},
+		{config.Config{BaseURL: "https://api.anthropic.com/v1/messages"}, ProviderAnthropic},
+		{config.Config{BaseURL: "http://localhost:11434/api/chat/"}, ProviderOllama},
+		{config.Config{BaseURL: "https://llm.internal/v1/completions", Provider: "OpenAI"}, ProviderOpenAI},
+		{config.Config{Provider: "anthropic"}, ProviderAnthropic},
+	}
+	for _, tt := range tests {
+		if got := ProviderName(&tt.cfg); got != tt.want {
+			t.Errorf("ProviderName(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+
+	if IsConfigured(&config.Config{Provider: "anthropic"}) {
+		t.Error("IsConfigured() without a model = true, want false")
+	}
+	if !IsConfigured(&config.Config{Provider: "ollama", Model: "llama3.1"}) {
+		t.Error("IsConfigured() with a provider's default URL = false, want true")
+	}
+	if _, err := NewProvider(&config.Config{Provider: "mystery"}); err == nil {
+		t.Error("NewProvider() of an unknown provider succeeded, want an error")
+	}
+}
+
+func TestNeedsToken(t *testing.T) {
+	tests := []struct {
+		cfg  config.Config
+		want bool
+	}{
+		{config.Config{Provider: "openai"}, true},
+		{config.Config{Provider: "anthropic", BaseURL: "https://llm.internal/v1/messages"}, true},
+		// LM Studio, as 'plannet init' finds it
+		{config.Config{Provider: "openai", BaseURL: "http://localhost:1234/v1/chat/completions"}, false},
+		{config.Config{BaseURL: "http://127.0.0.1:8080/v1/completions"}, false},
+		{config.Config{BaseURL: "http://[::1]:8080/v1/completions"}, false},
+		// Ollama runs without a token wherever it is
+		{config.Config{Provider: "ollama", BaseURL: "http://gpu-box:11434/api/chat"}, false},
+		{config.Config{Provider: "ollama"}, false},
+	}
+	for _, tt := range tests {
+		if got := NeedsToken(&tt.cfg); got != tt.want {
+			t.Errorf("NeedsToken(%+v) = %v, want %v", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestProviderRequests(t *testing.T) {
+	tests := []struct {
+		provider string
+		headers  map[string]string
+		body     map[string]interface{}
+		response string
+	}{
+		{
+			provider: ProviderCompletions,
+			headers:  map[string]string{"Authorization": "Bearer key"},
+			body: map[string]interface{}{"model": "m",
+				"prompt": "Be brief.\n\nUser: Hi\n\nAssistant:"},
+			response: `{"choices": [{"text": "Hello"}]}`,
+		},
+		{
+			provider: ProviderOpenAI,
+			headers:  map[string]string{"Authorization": "Bearer key"},
+			body: map[string]interface{}{"model": "m", "messages": []interface{}{
+				map[string]interface{}{"role": "system", "content": "Be brief."},
+				map[string]interface{}{"role": "user", "content": "Hi"}}},
+			response: `{"choices": [{"message": {"role": "assistant", "content": "Hello"}}]}`,
+		},
+		{
+			provider: ProviderAnthropic,
+			headers:  map[string]string{"X-Api-Key": "key", "Anthropic-Version": anthropicVersion, "Authorization": ""},
+			body: map[string]interface{}{"model": "m", "system": "Be brief.", "max_tokens": 4096.0,
+				"messages": []interface{}{map[string]interface{}{"role": "user", "content": "Hi"}}},
+			response: `{"content": [{"type": "text", "text": "Hello"}]}`,
+		},
+		{
+			provider: ProviderOllama,
+			headers:  map[string]string{"Authorization": "Bearer key"},
+			body: map[string]interface{}{"model": "m", "stream": false, "messages": []interface{}{
+				map[string]interface{}{"role": "system", "content": "Be brief."},
+				map[string]interface{}{"role": "user", "content": "Hi"}}},
+			response: `{"message": {"role": "assistant", "content": "Hello"}, "done": true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, want := range tt.headers {
+					if got := r.Header.Get(name); got != want {
+						t.Errorf("Header %s = %q, want %q", name, got, want)
+					}
+				}
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				if !reflect.DeepEqual(body, tt.body) {
+					t.Errorf("Request body = %v, want %v", body, tt.body)
+				}
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			generator := NewGenerator(&config.Config{
+				Provider:     tt.provider,
+				BaseURL:      server.URL,
+				Model:        "m",
+				SystemPrompt: "Be brief.",
+				LLMToken:     "key",
+			})
+			text, err := generator.Generate("Hi")
+			if err != nil {
+				t.Fatalf("Generate() failed: %v", err)
+			}
+			if text != "Hello" {
+				t.Errorf("Generate() = %q, want %q", text, "Hello")
+			}
+		})
+	}
+}
+
+func TestProviderStreams(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		stream      string
+	}{
+		ProviderOpenAI: {"text/event-stream", "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\ndata: [DONE]\n\n"},
+		ProviderAnthropic: {"text/event-stream", "event: message_start\ndata: {\"type\":\"message_start\"}\n\n" +
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hel\"}}\n\n" +
+			"event: ping\ndata: {\"type\": \"ping\"}\n\n" +
+			"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"lo\"}}\n\n" +
+			"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n"},
+		ProviderOllama: {"application/x-ndjson", "{\"message\":{\"content\":\"Hel\"},\"done\":false}\n" +
+			"{\"message\":{\"content\":\"lo\"},\"done\":false}\n{\"message\":{\"content\":\"\"},\"done\":true}\n"},
+	}
+	for provider, tt := range tests {
+		t.Run(provider, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				if body["stream"] != true {
+					t.Errorf("Expected a streamed request, got %v", body)
+				}
+				w.Header().Set("Content-Type", tt.contentType)
+				fmt.Fprint(w, tt.stream)
+			}))
+			defer server.Close()
+
+			generator := NewGenerator(&config.Config{Provider: provider, BaseURL: server.URL, Model: "m"})
+			var pieces []string
+			text, err := generator.Stream(context.Background(), "Hi", func(text string) {
+				pieces = append(pieces, text)
+			})
+			if err != nil {
+				t.Fatalf("Stream() failed: %v", err)
+			}
+			if text != "Hello" || strings.Join(pieces, "|") != "Hel|lo" {
+				t.Errorf("Stream() = %q in pieces %q, want \"Hello\" in two", text, pieces)
+			}
+		})
+	}
+
+	// Errors in the stream end it
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+	}))
+	defer server.Close()
+	generator := NewGenerator(&config.Config{Provider: ProviderAnthropic, BaseURL: server.URL, Model: "m"})
+	if _, err := generator.Stream(context.Background(), "Hi", func(string) {}); err == nil || !strings.Contains(err.Error(), "Overloaded") {
+		t.Errorf("Stream() error = %v, want the API's error", err)
+	}
+}
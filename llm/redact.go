@@ -0,0 +1,35 @@
+package llm
+
+import (
+	"fmt"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/security"
+)
+
+// OnRedact, if set, is called with what was masked in a conversation
+// before it was sent, to report it
+var OnRedact func(found security.Redactions)
+
+// redact returns the conversation with the secrets in it masked: common
+// API key formats, and the configured llm_redact_patterns
+func redact(cfg *config.Config, conv Conversation) (Conversation, error) {
+	redactor, err := security.NewRedactor(cfg.LLMRedactPatterns)
+	if err != nil {
+		return conv, fmt.Errorf("invalid llm_redact_patterns: %w", err)
+	}
+
+	found := security.Redactions{}
+	redacted := Conversation{
+		System:   redactor.Redact(conv.System, found),
+		Messages: make([]Message, len(conv.Messages)),
+		Schema:   conv.Schema,
+	}
+	for i, message := range conv.Messages {
+		redacted.Messages[i] = Message{Role: message.Role, Content: redactor.Redact(message.Content, found)}
+	}
+	if len(found) > 0 && OnRedact != nil {
+		OnRedact(found)
+	}
+	return redacted, nil
+}
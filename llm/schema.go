@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// jsonSchema is the part of JSON schema replies are validated against: the
+// keywords the schemas of plannet's structured requests use
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+}
+
+// ValidateJSON checks that data is JSON that follows a schema. Only the
+// type, properties, required, additionalProperties, items and enum
+// keywords are checked.
+func ValidateJSON(schema json.RawMessage, data []byte) error {
+	var s jsonSchema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return s.validate(value, "reply")
+}
+
+// validate checks a decoded JSON value against the schema. path names the
+// value in errors, such as reply.items[0].
+func (s *jsonSchema) validate(value interface{}, path string) error {
+	if s == nil {
+		return nil
+	}
+	if len(s.Enum) > 0 {
+		allowed := false
+		for _, option := range s.Enum {
+			if option == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%s is %v, which isn't one of %v", path, value, s.Enum)
+		}
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s should be an object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s is missing %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s has unexpected property %q", path, name)
+				}
+				continue
+			}
+			if err := property.validate(object[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s should be an array", path)
+		}
+		for i, item := range array {
+			if err := s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s should be a string", path)
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != float64(int64(number)) {
+			return fmt.Errorf("%s should be an integer", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s should be a number", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s should be true or false", path)
+		}
+	case "null":
+		if value != nil {
+			return fmt.Errorf("%s should be null", path)
+		}
+	}
+	return nil
+}
+
+// repairJSON returns the JSON object in a reply, without what models that
+// don't enforce a schema tend to add around or in it: text or a code fence
+// around it, and trailing commas
+func repairJSON(reply string) (string, error) {
+	start := strings.Index(reply, "{")
+	end := strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("the LLM didn't reply with JSON: %q", truncateReply(reply))
+	}
+	object := reply[start : end+1]
+	if !json.Valid([]byte(object)) {
+		object = withoutTrailingCommas(object)
+	}
+	return object, nil
+}
+
+// withoutTrailingCommas removes the commas before the end of an object or
+// array, which models sometimes write, leaving those in strings
+func withoutTrailingCommas(text string) string {
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && c == ',':
+			rest := strings.TrimLeft(text[i+1:], " \t\r\n")
+			if strings.HasPrefix(rest, "}") || strings.HasPrefix(rest, "]") {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateJSON(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"items": {"type": "array", "items": {
+				"type": "object",
+				"properties": {
+					"kind": {"type": "string", "enum": ["fix", "feature"]},
+					"minutes": {"type": "integer"},
+					"done": {"type": "boolean"}
+				},
+				"required": ["kind"],
+				"additionalProperties": false
+			}}
+		},
+		"required": ["items"]
+	}`)
+
+	if err := ValidateJSON(schema, []byte(`{"items": [{"kind": "fix", "minutes": 30, "done": false}], "extra": 1}`)); err != nil {
+		t.Errorf("ValidateJSON() of a valid reply failed: %v", err)
+	}
+	tests := []struct {
+		data, want string
+	}{
+		{`{}`, `reply is missing "items"`},
+		{`{"items": {}}`, "reply.items should be an array"},
+		{`{"items": [{"kind": "chore"}]}`, "reply.items[0].kind is chore"},
+		{`{"items": [{"kind": "fix", "minutes": 1.5}]}`, "reply.items[0].minutes should be an integer"},
+		{`{"items": [{"kind": "fix", "done": "no"}]}`, "reply.items[0].done should be true or false"},
+		{`{"items": [{"kind": "fix", "why": ""}]}`, `reply.items[0] has unexpected property "why"`},
+		{`{"items": [`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		err := ValidateJSON(schema, []byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateJSON(%s) = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		reply, want string
+	}{
+		{`{"a": 1}`, `{"a": 1}`},
+		{"```json\n{\"a\": [1, 2,]}\n```", `{"a": [1, 2]}`},
+		{"Sure:\n{\"a\": {\"b\": \"x, }\",\n},\n}\nHope it helps.", "{\"a\": {\"b\": \"x, }\"\n}\n}"},
+	}
+	for _, tt := range tests {
+		got, err := repairJSON(tt.reply)
+		if err != nil || got != tt.want {
+			t.Errorf("repairJSON(%q) = %q, %v, want %q", tt.reply, got, err, tt.want)
+		}
+	}
+	if _, err := repairJSON("No JSON here"); err == nil {
+		t.Error("repairJSON() of a reply without JSON succeeded, want an error")
+	}
+}
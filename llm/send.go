@@ -0,0 +1,107 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+	"github.com/plannet-ai/plannet/logger"
+)
+
+// OnFallback, if set, is called when a request to an endpoint failed and
+// is sent to the next one instead, to report it
+var OnFallback func(failed, next string, err error)
+
+// Send sends a conversation to the configured provider with a client from
+// NewClient, as Complete does. When the request fails, it is sent to each
+// of the configured llm_fallbacks in turn, until one replies. Secrets in
+// the conversation are masked first, as redact does. Requests are refused
+// or warned about once the month's budget is spent, and the tokens each
+// request used and their estimated cost are recorded; they are estimated
+// from the text when the API doesn't report them.
+func Send(ctx context.Context, cfg *config.Config, conv Conversation, onText func(string)) (string, error) {
+	return send(ctx, cfg, NewClient(cfg), conv, onText)
+}
+
+// send is Send with a given client
+func send(ctx context.Context, cfg *config.Config, client *http.Client, conv Conversation, onText func(string)) (string, error) {
+	if err := checkBudget(cfg, time.Now()); err != nil {
+		return "", err
+	}
+	conv, err := redact(cfg, conv)
+	if err != nil {
+		return "", err
+	}
+
+	chain := fallbackChain(cfg)
+	for i, endpoint := range chain {
+		text, err := sendTo(ctx, endpoint, client, conv, onText)
+		if err == nil {
+			logger.Debug("LLM response from %s", describeEndpoint(endpoint))
+			return text, nil
+		}
+		// A reply that started arriving can't be taken back, and one that
+		// was stopped isn't wanted from another endpoint either
+		if text != "" || ctx.Err() != nil || i == len(chain)-1 {
+			return text, err
+		}
+		if OnFallback != nil {
+			OnFallback(describeEndpoint(endpoint), describeEndpoint(chain[i+1]), err)
+		}
+	}
+	return "", fmt.Errorf("no LLM endpoint configured")
+}
+
+// sendTo sends a conversation to one endpoint and records its usage
+func sendTo(ctx context.Context, cfg *config.Config, client *http.Client, conv Conversation, onText func(string)) (string, error) {
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	text, usage, err := Complete(ctx, client, provider, conv, onText)
+	// Replies stopped part way were still paid for
+	if text != "" {
+		estimated := usage == Usage{}
+		if estimated {
+			usage = Usage{PromptTokens: conv.Tokens(), CompletionTokens: EstimateTokens(text)}
+		}
+		if recordErr := recordUsage(cfg, provider.Name(), usage, estimated, time.Now()); recordErr != nil {
+			logger.Debug("Failed to record LLM usage: %v", recordErr)
+		}
+	}
+	return text, err
+}
+
+// fallbackChain returns the configuration of each endpoint to try, in
+// order: the configured one, then its fallbacks
+func fallbackChain(cfg *config.Config) []*config.Config {
+	chain := []*config.Config{cfg}
+	for _, fallback := range cfg.LLMFallbacks {
+		endpoint := *cfg
+		endpoint.Provider = fallback.Provider
+		endpoint.BaseURL = fallback.BaseURL
+		endpoint.Model = fallback.Model
+		endpoint.LLMToken = fallback.Token
+		endpoint.Headers = fallback.Headers
+		endpoint.LLMFallbacks = nil
+		chain = append(chain, &endpoint)
+	}
+	return chain
+}
+
+// describeEndpoint names an endpoint in messages, such as
+// "ollama llama3.1 at localhost:11434"
+func describeEndpoint(cfg *config.Config) string {
+	description := ProviderName(cfg)
+	if cfg.Model != "" {
+		description += " " + cfg.Model
+	}
+	if u, err := url.Parse(endpoint(cfg)); err == nil && u.Host != "" {
+		description += " at " + u.Host
+	}
+	return description
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestSendFallback(t *testing.T) {
+	resetUsage(t)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	var fallbackAuth string
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"message": {"content": "Hello from the fallback"}, "done": true}`)
+	}))
+	defer fallback.Close()
+
+	var fellBack []string
+	OnFallback = func(failed, next string, err error) {
+		fellBack = append(fellBack, failed+" -> "+next)
+	}
+	defer func() { OnFallback = nil }()
+
+	cfg := &config.Config{
+		Provider:      "openai",
+		BaseURL:       primary.URL,
+		Model:         "big",
+		LLMToken:      "primary-key",
+		LLMMaxRetries: -1,
+		LLMFallbacks: []config.LLMEndpoint{
+			{Provider: "ollama", BaseURL: fallback.URL, Model: "small"},
+		},
+	}
+	text, err := Send(context.Background(), cfg, UserPrompt("", "Hi"), nil)
+	if err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	if text != "Hello from the fallback" {
+		t.Errorf("Send() = %q, want the fallback's reply", text)
+	}
+	if fallbackAuth != "" {
+		t.Errorf("The fallback was sent the primary's token: %q", fallbackAuth)
+	}
+	host := strings.TrimPrefix(fallback.URL, "http://")
+	if len(fellBack) != 1 || !strings.HasSuffix(fellBack[0], "-> ollama small at "+host) {
+		t.Errorf("Fell back %q, want once to the ollama endpoint", fellBack)
+	}
+	records, _ := LoadUsage()
+	if len(records) != 1 || records[0].Provider != ProviderOllama || records[0].Model != "small" {
+		t.Errorf("Recorded usage %+v, want the fallback's", records)
+	}
+
+	// Without a fallback that works, the last error is returned
+	cfg.LLMFallbacks = []config.LLMEndpoint{{Provider: "anthropic", BaseURL: primary.URL, Model: "other"}}
+	if _, err := Send(context.Background(), cfg, UserPrompt("", "Hi"), nil); err == nil || !strings.Contains(err.Error(), "overloaded") {
+		t.Errorf("Send() error = %v, want the last endpoint's error", err)
+	}
+}
+
+func TestSendNoFallbackWhenStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	called := false
+	OnFallback = func(failed, next string, err error) { called = true }
+	defer func() { OnFallback = nil }()
+
+	cfg := &config.Config{
+		Provider:     "openai",
+		BaseURL:      server.URL,
+		Model:        "m",
+		LLMFallbacks: []config.LLMEndpoint{{Provider: "ollama", BaseURL: server.URL, Model: "m"}},
+	}
+	if _, err := Send(ctx, cfg, UserPrompt("", "Hi"), func(string) {}); err != context.Canceled {
+		t.Errorf("Send() error = %v, want context.Canceled", err)
+	}
+	if called {
+		t.Error("Send() fell back after the request was stopped")
+	}
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// IsEventStream reports whether a response is a stream of server-sent
+// events, rather than a single JSON body from an API that doesn't stream
+func IsEventStream(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// ReadEvents reads server-sent events from r, calling fn with the data of
+// each one, until the stream ends or sends the [DONE] event OpenAI-style
+// APIs end a completion with. An error from fn stops reading.
+func ReadEvents(r io.Reader, fn func(data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var data []byte
+	dispatch := func() (bool, error) {
+		if len(data) == 0 {
+			return false, nil
+		}
+		event := data
+		data = nil
+		if string(event) == "[DONE]" {
+			return true, nil
+		}
+		return false, fn(event)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line ends an event
+			if done, err := dispatch(); done || err != nil {
+				return err
+			}
+			continue
+		}
+		// Other fields, such as event and id, and comments are ignored
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if len(data) > 0 {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(value, " ")...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	_, err := dispatch()
+	return err
+}
@@ -0,0 +1,89 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// system returns the conversation's system prompt, telling the model to
+// reply in JSON following the conversation's schema, if it has one
+func (c Conversation) system() string {
+	if c.Schema == nil {
+		return c.System
+	}
+	instruction := "Reply with only a JSON object, without code fences, that follows this JSON schema:\n" + string(c.Schema)
+	if c.System == "" {
+		return instruction
+	}
+	return c.System + "\n\n" + instruction
+}
+
+// DecodeJSON decodes a reply asked for in JSON into out. Models that don't
+// enforce a schema may wrap the JSON in a code fence or in text; only the
+// outermost JSON object is decoded, repaired as repairJSON does.
+func DecodeJSON(reply string, out interface{}) error {
+	object, err := repairJSON(reply)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(object), out); err != nil {
+		return fmt.Errorf("the LLM replied with invalid JSON: %w", err)
+	}
+	return nil
+}
+
+// SendJSON sends a conversation with a Schema as Send does, and returns the
+// JSON object the LLM replied with, once it follows the schema. Replies are
+// repaired as repairJSON does; one that still doesn't follow the schema is
+// sent back to the LLM, once, with what is wrong with it.
+func SendJSON(ctx context.Context, cfg *config.Config, conv Conversation) (string, error) {
+	if conv.Schema == nil {
+		return "", fmt.Errorf("no JSON schema given for the reply")
+	}
+	reply, err := Send(ctx, cfg, conv, nil)
+	if err != nil {
+		return "", err
+	}
+	object, invalid := validReply(conv.Schema, reply)
+	if invalid == nil {
+		return object, nil
+	}
+
+	retry := conv
+	retry.Messages = append(append([]Message{}, conv.Messages...),
+		Message{Role: "assistant", Content: reply},
+		Message{Role: "user", Content: fmt.Sprintf("That reply doesn't follow the JSON schema: %v. Reply again with only the corrected JSON object.", invalid)},
+	)
+	reply, err = Send(ctx, cfg, retry, nil)
+	if err != nil {
+		return "", err
+	}
+	object, invalid = validReply(conv.Schema, reply)
+	if invalid != nil {
+		return "", fmt.Errorf("the LLM's reply doesn't follow the JSON schema: %w", invalid)
+	}
+	return object, nil
+}
+
+// validReply returns the repaired JSON object of a reply, and what is
+// wrong with it if it doesn't follow the schema
+func validReply(schema json.RawMessage, reply string) (string, error) {
+	object, err := repairJSON(reply)
+	if err != nil {
+		return "", err
+	}
+	return object, ValidateJSON(schema, []byte(object))
+}
+
+// truncateReply shortens a reply for an error message
+func truncateReply(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if len(reply) > 200 {
+		return reply[:200] + "..."
+	}
+	return reply
+}
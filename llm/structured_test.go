@@ -0,0 +1,114 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestSchemaRequests(t *testing.T) {
+	schema := json.RawMessage(`{"type":"object","properties":{"title":{"type":"string"}}}`)
+	conv := UserPrompt("Be brief.", "Hi")
+	conv.Schema = schema
+
+	for _, name := range []string{ProviderCompletions, ProviderOpenAI, ProviderAnthropic, ProviderOllama} {
+		t.Run(name, func(t *testing.T) {
+			provider, err := NewProvider(&config.Config{Provider: name, BaseURL: "http://localhost", Model: "m"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := provider.NewRequest(context.Background(), conv, false)
+			if err != nil {
+				t.Fatalf("NewRequest() failed: %v", err)
+			}
+			var body map[string]interface{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			// Every provider is told about the schema
+			if data, _ := json.Marshal(body); !strings.Contains(string(data), `Be brief.\n\nReply with only a JSON object`) {
+				t.Errorf("Request doesn't ask for JSON: %s", data)
+			}
+			var want, got interface{}
+			json.Unmarshal(schema, &want)
+			switch name {
+			case ProviderOpenAI:
+				got = body["response_format"].(map[string]interface{})["json_schema"].(map[string]interface{})["schema"]
+			case ProviderOllama:
+				got = body["format"]
+			default:
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Request enforces schema %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var out struct {
+		Title string `json:"title"`
+	}
+	for _, reply := range []string{
+		`{"title": "Fix login"}`,
+		"```json\n{\"title\": \"Fix login\"}\n```",
+		"Here is the ticket:\n{\"title\": \"Fix login\"}",
+	} {
+		out.Title = ""
+		if err := DecodeJSON(reply, &out); err != nil || out.Title != "Fix login" {
+			t.Errorf("DecodeJSON(%q) = %q, %v", reply, out.Title, err)
+		}
+	}
+	for _, reply := range []string{"No JSON here", `{"title": }`} {
+		if err := DecodeJSON(reply, &out); err == nil {
+			t.Errorf("DecodeJSON(%q) succeeded, want an error", reply)
+		}
+	}
+}
+
+func TestSendJSON(t *testing.T) {
+	resetUsage(t)
+	replies := []string{`{"title": 42}`, "```json\n{\"title\": \"Fix login\",}\n```"}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		reply, _ := json.Marshal(replies[0])
+		replies = replies[1:]
+		fmt.Fprintf(w, `{"message": {"content": %s}, "done": true}`, reply)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Provider: "ollama", BaseURL: server.URL, Model: "m", LLMMaxRetries: -1}
+	conv := UserPrompt("", "Draft a ticket")
+	conv.Schema = json.RawMessage(`{"type":"object","properties":{"title":{"type":"string"}},"required":["title"]}`)
+	got, err := SendJSON(context.Background(), cfg, conv)
+	if err != nil {
+		t.Fatalf("SendJSON() failed: %v", err)
+	}
+	if got != `{"title": "Fix login"}` {
+		t.Errorf("SendJSON() = %q, want the repaired second reply", got)
+	}
+	if len(requests) != 2 || !strings.Contains(requests[1], "reply.title should be a string") {
+		t.Errorf("Requests = %q, want a retry telling what was wrong", requests)
+	}
+
+	// A reply that is still wrong after the retry fails
+	replies = []string{"{}", "{}"}
+	if _, err := SendJSON(context.Background(), cfg, conv); err == nil || !strings.Contains(err.Error(), `missing "title"`) {
+		t.Errorf("SendJSON() error = %v, want the schema violation", err)
+	}
+	if _, err := SendJSON(context.Background(), cfg, UserPrompt("", "Hi")); err == nil {
+		t.Error("SendJSON() without a schema succeeded, want an error")
+	}
+}
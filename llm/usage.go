@@ -0,0 +1,173 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// Usage is the tokens a request used
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// UsageRecord is the usage of a model on a day
+type UsageRecord struct {
+	Date             string  `json:"date"` // In local time, as 2006-01-02
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"` // Estimated from llm_pricing, 0 without a price for the model
+	// Estimated counts the requests whose tokens the API didn't report,
+	// which were estimated from the text instead
+	Estimated int `json:"estimated,omitempty"`
+}
+
+// ErrBudgetExceeded is returned for requests refused because the month's
+// LLM budget is spent
+var ErrBudgetExceeded = errors.New("monthly LLM budget exceeded")
+
+// OnBudgetExceeded, if set, is called before each request sent once the
+// month's budget is spent, to warn about it
+var OnBudgetExceeded func(spent, budget float64)
+
+// UsagePath returns the path of the file LLM usage is recorded in. Usage
+// is shared by every workspace, as the budget is.
+func UsagePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".plannet", "llm_usage.json"), nil
+}
+
+// LoadUsage returns the recorded LLM usage, by day and model
+func LoadUsage() ([]UsageRecord, error) {
+	path, err := UsagePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LLM usage: %w", err)
+	}
+	var records []UsageRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM usage: %w", err)
+	}
+	return records, nil
+}
+
+// saveUsage writes the LLM usage records, replacing the file so that an
+// interrupted write doesn't lose the month's usage
+func saveUsage(records []UsageRecord) error {
+	path, err := UsagePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal LLM usage: %w", err)
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write LLM usage: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to write LLM usage: %w", err)
+	}
+	return nil
+}
+
+// Cost estimates what usage of a model costs, from its configured price.
+// It reports false for models without one.
+func Cost(cfg *config.Config, model string, usage Usage) (float64, bool) {
+	price, ok := cfg.LLMPricing[model]
+	if !ok {
+		return 0, false
+	}
+	return (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / 1e6, true
+}
+
+// recordUsage adds a request's usage to the record of its day and model
+func recordUsage(cfg *config.Config, provider string, usage Usage, estimated bool, now time.Time) error {
+	records, err := LoadUsage()
+	if err != nil {
+		return err
+	}
+	date := now.Format("2006-01-02")
+	var record *UsageRecord
+	for i := range records {
+		if records[i].Date == date && records[i].Provider == provider && records[i].Model == cfg.Model {
+			record = &records[i]
+			break
+		}
+	}
+	if record == nil {
+		records = append(records, UsageRecord{Date: date, Provider: provider, Model: cfg.Model})
+		record = &records[len(records)-1]
+	}
+
+	record.Requests++
+	record.PromptTokens += usage.PromptTokens
+	record.CompletionTokens += usage.CompletionTokens
+	if cost, ok := Cost(cfg, cfg.Model, usage); ok {
+		record.Cost += cost
+	}
+	if estimated {
+		record.Estimated++
+	}
+	return saveUsage(records)
+}
+
+// MonthSpend returns the estimated cost of the usage in the month of now
+func MonthSpend(records []UsageRecord, now time.Time) float64 {
+	month := now.Format("2006-01")
+	var spent float64
+	for _, record := range records {
+		if strings.HasPrefix(record.Date, month) {
+			spent += record.Cost
+		}
+	}
+	return spent
+}
+
+// checkBudget refuses or warns about requests once the month's budget is
+// spent, as configured
+func checkBudget(cfg *config.Config, now time.Time) error {
+	if cfg.LLMMonthlyBudget <= 0 {
+		return nil
+	}
+	records, err := LoadUsage()
+	if err != nil {
+		return err
+	}
+	spent := MonthSpend(records, now)
+	if spent < cfg.LLMMonthlyBudget {
+		return nil
+	}
+	if strings.EqualFold(cfg.LLMBudgetAction, "refuse") {
+		return fmt.Errorf("%w: spent an estimated %.2f of %.2f this month; raise llm_monthly_budget to continue",
+			ErrBudgetExceeded, spent, cfg.LLMMonthlyBudget)
+	}
+	if OnBudgetExceeded != nil {
+		OnBudgetExceeded(spent, cfg.LLMMonthlyBudget)
+	}
+	return nil
+}
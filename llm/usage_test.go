@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/plannet-ai/plannet/config"
+)
+
+// TestMain keeps the usage the tests record out of the user's home
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "plannet-llm-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+// resetUsage removes the recorded usage
+func resetUsage(t *testing.T) {
+	path, err := UsagePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path)
+}
+
+func TestSendRecordsUsage(t *testing.T) {
+	resetUsage(t)
+	responses := []string{
+		`{"choices": [{"message": {"content": "Hello"}}], "usage": {"prompt_tokens": 1000, "completion_tokens": 500, "total_tokens": 1500}}`,
+		// Without a usage block, the usage is estimated from the text
+		`{"choices": [{"message": {"content": "Hello again"}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, responses[0])
+		responses = responses[1:]
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		Provider:   "openai",
+		BaseURL:    server.URL,
+		Model:      "gpt-test",
+		LLMPricing: map[string]config.LLMPrice{"gpt-test": {Prompt: 2, Completion: 8}},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := Send(context.Background(), cfg, UserPrompt("", "Hi there"), nil); err != nil {
+			t.Fatalf("Send() failed: %v", err)
+		}
+	}
+
+	records, err := LoadUsage()
+	if err != nil {
+		t.Fatalf("LoadUsage() failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Recorded %d records, want 1 for the day and model: %+v", len(records), records)
+	}
+	record := records[0]
+	if record.Date != time.Now().Format("2006-01-02") || record.Provider != "openai" || record.Model != "gpt-test" {
+		t.Errorf("Recorded %+v for the wrong day or model", record)
+	}
+	// "Hi there" and "Hello again" are estimated at 2 and 3 tokens
+	if record.Requests != 2 || record.PromptTokens != 1002 || record.CompletionTokens != 503 || record.Estimated != 1 {
+		t.Errorf("Recorded %+v, want 2 requests of 1002 and 503 tokens, 1 estimated", record)
+	}
+	if want := (1002*2.0 + 503*8.0) / 1e6; record.Cost != want {
+		t.Errorf("Recorded a cost of %f, want %f", record.Cost, want)
+	}
+}
+
+func TestSendBudget(t *testing.T) {
+	resetUsage(t)
+	now := time.Now()
+	lastMonth := now.AddDate(0, 0, -now.Day()) // The last day of last month
+	saveUsage([]UsageRecord{
+		{Date: lastMonth.Format("2006-01-02"), Model: "m", Requests: 1, Cost: 50},
+		{Date: now.Format("2006-01-02"), Model: "m", Requests: 1, Cost: 6},
+	})
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"choices": [{"text": "Hello"}]}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{BaseURL: server.URL, Model: "m", LLMMonthlyBudget: 10}
+	if _, err := Send(context.Background(), cfg, UserPrompt("", "Hi"), nil); err != nil {
+		t.Errorf("Send() within the budget failed: %v", err)
+	}
+
+	cfg.LLMMonthlyBudget = 5
+	var warned float64
+	OnBudgetExceeded = func(spent, budget float64) { warned = spent }
+	defer func() { OnBudgetExceeded = nil }()
+	if _, err := Send(context.Background(), cfg, UserPrompt("", "Hi"), nil); err != nil {
+		t.Errorf("Send() over a budget that warns failed: %v", err)
+	}
+	if warned != 6 {
+		t.Errorf("Warned of spending %f, want this month's 6", warned)
+	}
+
+	cfg.LLMBudgetAction = "refuse"
+	if _, err := Send(context.Background(), cfg, UserPrompt("", "Hi"), nil); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Send() over a budget that refuses: error = %v, want ErrBudgetExceeded", err)
+	}
+	if requests != 2 {
+		t.Errorf("Sent %d requests, want the refused one not sent", requests)
+	}
+}
+
+func TestStreamUsage(t *testing.T) {
+	tests := map[string]string{
+		ProviderOpenAI: "data: {\"choices\":[{\"delta\":{\"content\":\"Hello\"}}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":12,\"completion_tokens\":3,\"total_tokens\":15}}\n\ndata: [DONE]\n\n",
+		ProviderAnthropic: "data: {\"type\":\"message_start\",\"message\":{\"usage\":{\"input_tokens\":12,\"output_tokens\":1}}}\n\n" +
+			"data: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hello\"}}\n\n" +
+			"data: {\"type\":\"message_delta\",\"usage\":{\"output_tokens\":3}}\n\n",
+		ProviderOllama: "{\"message\":{\"content\":\"Hello\"},\"done\":false}\n" +
+			"{\"message\":{\"content\":\"\"},\"done\":true,\"prompt_eval_count\":12,\"eval_count\":3}\n",
+	}
+	for name, stream := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				fmt.Fprint(w, stream)
+			}))
+			defer server.Close()
+
+			cfg := &config.Config{Provider: name, BaseURL: server.URL, Model: "m"}
+			provider, _ := NewProvider(cfg)
+			_, usage, err := Complete(context.Background(), NewClient(cfg), provider, UserPrompt("", "Hi"), func(string) {})
+			if err != nil {
+				t.Fatalf("Complete() failed: %v", err)
+			}
+			if want := (Usage{PromptTokens: 12, CompletionTokens: 3}); usage != want {
+				t.Errorf("Complete() usage = %+v, want %+v", usage, want)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/plannet-ai/plannet/output"
 )
 
 // Level represents the logging level
@@ -60,8 +62,10 @@ func New(out io.Writer, level Level, useColors bool) *Logger {
 	}
 }
 
-// DefaultLogger is the default logger instance
-var DefaultLogger = New(os.Stderr, InfoLevel, true)
+// DefaultLogger is the default logger instance. Its colors follow the
+// environment until a color setting is applied with SetColors, so errors
+// logged before then, such as for unknown flags, respect NO_COLOR too.
+var DefaultLogger = New(os.Stderr, InfoLevel, output.ColorEnabled(output.ColorAuto, os.Stderr))
 
 // WithField adds a field to the logger
 func (l *Logger) WithField(key string, value interface{}) *Logger {
@@ -177,6 +181,9 @@ func (l *Logger) Warn(msg string, args ...interface{}) {
 
 // Error logs an error message
 func (l *Logger) Error(msg string, args ...interface{}) {
+	if errorHook != nil {
+		errorHook(args...)
+	}
 	l.log(ErrorLevel, msg, args...)
 }
 
@@ -226,6 +233,20 @@ func SetLevel(level Level) {
 	DefaultLogger.level = level
 }
 
+// errorHook is called with the arguments of each error logged
+var errorHook func(args ...interface{})
+
+// SetErrorHook sets a function called with the arguments of each error
+// logged, such as to choose the exit code from the errors among them
+func SetErrorHook(hook func(args ...interface{})) {
+	errorHook = hook
+}
+
+// SetColors turns the colors of the default logger on or off
+func SetColors(enabled bool) {
+	DefaultLogger.useColors = enabled
+}
+
 // Helper functions
 
 // extractContextFields extracts relevant fields from context
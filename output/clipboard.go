@@ -0,0 +1,70 @@
+package output
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/chzyer/readline"
+)
+
+// systemClipboard reports whether the clipboard of the machine plannet runs
+// on is the user's, given its environment. Over SSH it is the remote
+// machine's, unless a display is forwarded.
+func systemClipboard(getenv func(string) string) bool {
+	if getenv("SSH_CONNECTION") == "" && getenv("SSH_TTY") == "" {
+		return true
+	}
+	return getenv("DISPLAY") != "" || getenv("WAYLAND_DISPLAY") != ""
+}
+
+// writeClipboard copies text to the system clipboard: with pbcopy on macOS,
+// the clipboard API on Windows, and wl-copy, xclip, xsel or Termux on
+// Linux. Without one, such as over SSH, it asks the terminal to copy it
+// with an OSC 52 escape sequence, which works in most terminals, including
+// through tmux.
+func writeClipboard(text string) error {
+	var err error
+	if systemClipboard(os.Getenv) {
+		if err = clipboard.WriteAll(text); err == nil {
+			return nil
+		}
+	}
+	if terminal := clipboardTerminal(); terminal != nil {
+		_, err := io.WriteString(terminal, osc52(text, os.Getenv))
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("no clipboard available over SSH outside a terminal")
+}
+
+// clipboardTerminal returns the terminal to send an OSC 52 sequence to:
+// stderr or stdout, whichever is a terminal, so that output piped
+// elsewhere isn't affected, or nil if neither is
+func clipboardTerminal() io.Writer {
+	for _, f := range []*os.File{os.Stderr, os.Stdout} {
+		if readline.IsTerminal(int(f.Fd())) {
+			return f
+		}
+	}
+	return nil
+}
+
+// osc52 returns the escape sequence that asks the terminal to copy text to
+// the clipboard. Inside tmux and GNU screen, it is wrapped to be passed
+// through to the terminal they run in.
+func osc52(text string, getenv func(string) string) string {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case getenv("TMUX") != "":
+		return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		return "\x1bP" + sequence + "\x1b\\"
+	}
+	return sequence
+}
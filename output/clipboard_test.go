@@ -0,0 +1,47 @@
+package output
+
+import "testing"
+
+// env returns a getenv that looks variables up in vars
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestSystemClipboard(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"local", nil, true},
+		{"SSH", map[string]string{"SSH_CONNECTION": "10.0.0.2 51234 10.0.0.1 22"}, false},
+		{"SSH terminal", map[string]string{"SSH_TTY": "/dev/pts/1"}, false},
+		{"SSH with X forwarding", map[string]string{"SSH_CONNECTION": "x", "DISPLAY": "localhost:10.0"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := systemClipboard(env(tt.env)); got != tt.want {
+				t.Errorf("systemClipboard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOSC52(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"terminal", map[string]string{"TERM": "xterm-256color"}, "\x1b]52;c;aGk=\a"},
+		{"tmux", map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0", "TERM": "screen"}, "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\"},
+		{"screen", map[string]string{"TERM": "screen.xterm-256color"}, "\x1bP\x1b]52;c;aGk=\a\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := osc52("hi", env(tt.env)); got != tt.want {
+				t.Errorf("osc52() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
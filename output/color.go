@@ -0,0 +1,58 @@
+package output
+
+import (
+	"os"
+
+	"github.com/chzyer/readline"
+	"github.com/fatih/color"
+)
+
+// Values of the color setting
+const (
+	// ColorAuto colors output to terminals, unless NO_COLOR is set
+	ColorAuto = "auto"
+	// ColorAlways colors output even when it isn't to a terminal
+	ColorAlways = "always"
+	// ColorNever never colors output
+	ColorNever = "never"
+)
+
+// colors reports whether output is colored, as set by SetColors
+var colors = ColorEnabled(ColorAuto, os.Stdout)
+
+// ColorEnabled reports whether output to f is colored under a color
+// setting. "always" and "never" decide on their own; otherwise, such as for
+// "auto" or no setting, NO_COLOR turns colors off, CLICOLOR_FORCE turns them
+// on, and they are on in terminals other than dumb ones.
+func ColorEnabled(setting string, f *os.File) bool {
+	return colorEnabled(setting, readline.IsTerminal(int(f.Fd())), os.Getenv)
+}
+
+// colorEnabled reports whether output is colored under a color setting,
+// given whether it is to a terminal and the environment
+func colorEnabled(setting string, terminal bool, getenv func(string) string) bool {
+	switch setting {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	return terminal && getenv("TERM") != "dumb"
+}
+
+// SetColors turns colored output on or off
+func SetColors(enabled bool) {
+	colors = enabled
+	color.NoColor = !enabled
+}
+
+// Colors reports whether output is colored
+func Colors() bool {
+	return colors
+}
@@ -0,0 +1,30 @@
+package output
+
+import "testing"
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		setting  string
+		terminal bool
+		env      map[string]string
+		want     bool
+	}{
+		{"terminal", "", true, nil, true},
+		{"pipe", ColorAuto, false, nil, false},
+		{"dumb terminal", "", true, map[string]string{"TERM": "dumb"}, false},
+		{"NO_COLOR", ColorAuto, true, map[string]string{"NO_COLOR": "1"}, false},
+		{"CLICOLOR_FORCE", "", false, map[string]string{"CLICOLOR_FORCE": "1"}, true},
+		{"CLICOLOR_FORCE=0", "", false, map[string]string{"CLICOLOR_FORCE": "0"}, false},
+		{"NO_COLOR over CLICOLOR_FORCE", "", true, map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, false},
+		{"always", ColorAlways, false, map[string]string{"NO_COLOR": "1"}, true},
+		{"never", ColorNever, true, map[string]string{"CLICOLOR_FORCE": "1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := colorEnabled(tt.setting, tt.terminal, env(tt.env)); got != tt.want {
+				t.Errorf("colorEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Format is how commands print their results: as text for people to read,
+// or as JSON for scripts
+type Format string
+
+const (
+	// Text prints results as prose and lists
+	Text Format = "text"
+	// JSON prints results as a JSON document
+	JSON Format = "json"
+)
+
+// Formats are the formats commands can print their results in
+var Formats = []Format{Text, JSON}
+
+// ParseFormat returns the format with a name, such as "json"
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = string(format)
+	}
+	return "", fmt.Errorf("unknown output format %q, expected one of: %s", name, strings.Join(names, ", "))
+}
+
+// WriteJSON writes a result as indented JSON
+func WriteJSON(w io.Writer, result interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(result)
+}
+
+// templateFuncs are the functions templates given with --format can call
+var templateFuncs = template.FuncMap{
+	"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// truncate cuts text to a number of characters, ending it with an ellipsis
+	"truncate": func(width int, text string) string { return truncate(text, width) },
+}
+
+// ParseTemplate parses a Go template that formats a result, such as
+// "{{.TicketID}} {{.Description}}"
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// WriteTemplate writes a result formatted with a template, ending it with a
+// line break unless the template does
+func WriteTemplate(w io.Writer, tmpl *template.Template, result interface{}) error {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, result); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	text := out.String()
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	_, err := io.WriteString(w, text)
+	return err
+}
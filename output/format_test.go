@@ -0,0 +1,55 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"text": Text, "json": JSON, "JSON": JSON} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("ParseFormat(yaml) succeeded, want an error")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteJSON(&out, map[string]string{"focus": "Fix <login> & logout"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"focus\": \"Fix <login> & logout\"\n}\n"
+	if out.String() != want {
+		t.Errorf("WriteJSON() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{.Key}} {{upper .Status}} {{truncate 8 .Summary}} {{join "," .Labels}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := struct {
+		Key, Status, Summary string
+		Labels               []string
+	}{"PROJ-1", "open", "Fix the login timeout", []string{"auth", "web"}}
+
+	var out bytes.Buffer
+	if err := WriteTemplate(&out, tmpl, result); err != nil {
+		t.Fatal(err)
+	}
+	if want := "PROJ-1 OPEN Fix the… auth,web\n"; out.String() != want {
+		t.Errorf("WriteTemplate() = %q, want %q", out.String(), want)
+	}
+
+	if _, err := ParseTemplate("{{.Key"); err == nil {
+		t.Error("ParseTemplate() of an unclosed action succeeded")
+	}
+	tmpl, _ = ParseTemplate("{{.Missing}}")
+	if err := WriteTemplate(&out, tmpl, result); err == nil {
+		t.Error("WriteTemplate() with an unknown field succeeded")
+	}
+}
@@ -0,0 +1,405 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// markdownEnabled is whether markdown is rendered, as set by SetMarkdown
+var markdownEnabled = true
+
+// SetMarkdown sets whether markdown, such as ticket descriptions and LLM
+// replies, is rendered with terminal styling. Markdown is only rendered
+// when colors are on; otherwise it is printed as it is.
+func SetMarkdown(enabled bool) {
+	markdownEnabled = enabled
+}
+
+// renderingMarkdown reports whether markdown is rendered
+func renderingMarkdown() bool {
+	return markdownEnabled && Colors()
+}
+
+// Markdown renders markdown with terminal styling: headings, emphasis,
+// lists, quotes and code. Unless markdown is rendered, text is returned as
+// it is.
+func Markdown(text string) string {
+	if !renderingMarkdown() {
+		return text
+	}
+	r := newMarkdownRenderer(nil)
+	return r.render(text) + r.flush()
+}
+
+// MarkdownStream renders markdown that arrives in pieces, such as a reply
+// streamed from the LLM. Lines are styled as soon as their kind is known,
+// so the text appears as it arrives.
+type MarkdownStream struct {
+	r    *markdownRenderer
+	text func(string) string
+}
+
+// NewMarkdownStream creates a MarkdownStream that styles plain text with
+// text, which may be nil for unstyled text. Unless markdown is rendered,
+// pieces are returned as they are, styled with text.
+func NewMarkdownStream(text func(string) string) *MarkdownStream {
+	if !renderingMarkdown() {
+		return &MarkdownStream{text: text}
+	}
+	return &MarkdownStream{r: newMarkdownRenderer(text)}
+}
+
+// Render returns the rendering of the next piece of markdown. Text at the
+// start of a line may be held back until the kind of line is known.
+func (s *MarkdownStream) Render(piece string) string {
+	switch {
+	case s.r != nil:
+		return s.r.render(piece)
+	case s.text != nil:
+		return s.text(piece)
+	default:
+		return piece
+	}
+}
+
+// Flush returns what is left of the markdown once the last piece arrived
+func (s *MarkdownStream) Flush() string {
+	if s.r == nil {
+		return ""
+	}
+	return s.r.flush()
+}
+
+// markdownLineStart holds the characters that may start a heading, list,
+// quote, rule or code fence, so lines starting with them are held until
+// their kind is known
+const markdownLineStart = " \t#>*+-_`~0123456789.)"
+
+var (
+	markdownHeading = regexp.MustCompile(`^(\s*)#{1,6}\s+`)
+	markdownQuote   = regexp.MustCompile(`^(\s*)>\s?`)
+	markdownBullet  = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	markdownOrdered = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+`)
+	markdownRule    = regexp.MustCompile(`^\s*(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	markdownFence   = regexp.MustCompile("^\\s*(?:```|~~~)")
+)
+
+// markdownRuleWidth is the width of a rendered horizontal rule
+const markdownRuleWidth = 40
+
+// markdownStyle is how a run of rendered text is styled
+type markdownStyle struct {
+	heading, quote, bold, italic, code bool
+}
+
+// markdownRenderer renders markdown a character at a time
+type markdownRenderer struct {
+	text func(string) string // style of plain text
+
+	out      strings.Builder // rendered text not yet returned
+	run      strings.Builder // text in the current style, not yet styled
+	runStyle markdownStyle
+
+	atStart  bool            // at the start of a line
+	start    strings.Builder // the start of a line, until its kind is known
+	skipLine bool            // dropping the rest of a code fence line
+	fence    bool            // in a fenced code block
+
+	heading, quote, bold, italic, code bool
+	pending                            rune // a * or _ held until the next character
+	escaped                            bool // after a backslash
+	prev                               rune // the last character of the line written
+}
+
+// newMarkdownRenderer creates a renderer that styles plain text with text
+func newMarkdownRenderer(text func(string) string) *markdownRenderer {
+	return &markdownRenderer{text: text, atStart: true}
+}
+
+// render renders the next piece of markdown, and returns what can be shown
+func (r *markdownRenderer) render(piece string) string {
+	for _, c := range piece {
+		r.write(c)
+	}
+	r.flushRun()
+	rendered := r.out.String()
+	r.out.Reset()
+	return rendered
+}
+
+// flush renders what is held back at the end of the markdown
+func (r *markdownRenderer) flush() string {
+	if r.atStart && r.start.Len() > 0 {
+		r.classify(r.start.String(), true)
+		r.start.Reset()
+	}
+	r.skipLine = false
+	r.resolvePending(0)
+	r.endLine()
+	r.flushRun()
+	rendered := r.out.String()
+	r.out.Reset()
+	return rendered
+}
+
+// write renders a character
+func (r *markdownRenderer) write(c rune) {
+	switch {
+	case c == '\r':
+		// Lines end at the \n of \r\n
+	case r.skipLine:
+		if c == '\n' {
+			r.skipLine = false
+			r.atStart = true
+		}
+	case r.atStart:
+		r.writeLineStart(c)
+	case c == '\n':
+		r.resolvePending(0)
+		r.endLine()
+		r.emit('\n')
+		r.atStart = true
+	case r.fence:
+		r.emit(c)
+	default:
+		r.inline(c)
+	}
+}
+
+// writeLineStart holds the start of a line until its kind is known
+func (r *markdownRenderer) writeLineStart(c rune) {
+	if c != '\n' && strings.ContainsRune(markdownLineStart, c) {
+		r.start.WriteRune(c)
+		return
+	}
+	line := r.start.String()
+	r.start.Reset()
+	r.atStart = false
+
+	if c == '\n' {
+		r.classify(line, true)
+		if r.skipLine {
+			// A code fence line is dropped with its newline
+			r.skipLine = false
+			r.atStart = true
+			return
+		}
+		r.write(c)
+		return
+	}
+	r.classify(line+string(c), false)
+}
+
+// classify renders the start of a line, which is the whole line when
+// complete, by its kind
+func (r *markdownRenderer) classify(line string, complete bool) {
+	if markdownFence.MatchString(line) {
+		// The fence line, with the language of the code, is left out
+		r.fence = !r.fence
+		r.skipLine = true
+		return
+	}
+	if r.fence {
+		if line == "" {
+			return
+		}
+		r.emitStyled("  ", nil)
+		r.writeAll(line)
+		return
+	}
+
+	if complete && markdownRule.MatchString(line) {
+		r.emitStyled(strings.Repeat("─", markdownRuleWidth), Heading)
+		return
+	}
+	if m := markdownHeading.FindStringSubmatch(line); m != nil {
+		r.heading = true
+		r.emitStyled(m[1], nil)
+		r.writeAll(line[len(m[0]):])
+		return
+	}
+	if m := markdownQuote.FindStringSubmatch(line); m != nil {
+		r.quote = true
+		r.emitStyled(m[1]+"│ ", Heading)
+		r.writeAll(line[len(m[0]):])
+		return
+	}
+	if m := markdownBullet.FindStringSubmatch(line); m != nil {
+		r.emitStyled(m[1]+"• ", Heading)
+		r.writeAll(line[len(m[0]):])
+		return
+	}
+	if m := markdownOrdered.FindStringSubmatch(line); m != nil {
+		r.emitStyled(m[1]+m[2]+". ", Heading)
+		r.writeAll(line[len(m[0]):])
+		return
+	}
+	r.writeAll(line)
+}
+
+// writeAll renders the characters of the rest of a line
+func (r *markdownRenderer) writeAll(text string) {
+	for _, c := range text {
+		if r.fence {
+			r.emit(c)
+		} else {
+			r.inline(c)
+		}
+	}
+}
+
+// inline renders a character of text, toggling emphasis and code spans
+func (r *markdownRenderer) inline(c rune) {
+	if r.escaped {
+		r.escaped = false
+		if !unicode.IsPunct(c) && !unicode.IsSymbol(c) {
+			r.emit('\\')
+		}
+		r.emit(c)
+		return
+	}
+	if r.pending != 0 && r.resolvePending(c) {
+		return
+	}
+
+	switch {
+	case c == '`':
+		r.code = !r.code
+	case r.code:
+		r.emit(c)
+	case c == '\\':
+		r.escaped = true
+	case c == '*' || c == '_':
+		r.pending = c
+	default:
+		r.emit(c)
+	}
+}
+
+// resolvePending decides what the * or _ held back means, now that the
+// next character, c, is known (0 at the end of a line). It reports whether
+// c was used up.
+func (r *markdownRenderer) resolvePending(c rune) bool {
+	marker := r.pending
+	if marker == 0 {
+		return false
+	}
+	r.pending = 0
+
+	if c == marker {
+		r.bold = !r.bold
+		return true
+	}
+
+	// A single marker opens emphasis before text and closes it after
+	// text; underscores inside words, as in snake_case, are kept
+	var toggles bool
+	if r.italic {
+		toggles = r.prev != 0 && !unicode.IsSpace(r.prev) && (marker == '*' || !isWordRune(c))
+	} else {
+		toggles = c != 0 && !unicode.IsSpace(c) && (marker == '*' || !isWordRune(r.prev))
+	}
+	if toggles {
+		r.italic = !r.italic
+	} else {
+		r.emit(marker)
+	}
+	return false
+}
+
+// isWordRune reports whether c is part of a word
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// endLine ends the styles of a line, which don't carry over to the next
+func (r *markdownRenderer) endLine() {
+	if r.escaped {
+		r.emit('\\')
+	}
+	r.heading, r.quote, r.bold, r.italic, r.code, r.escaped = false, false, false, false, false, false
+	r.prev = 0
+}
+
+// current returns the style of the text being written
+func (r *markdownRenderer) current() markdownStyle {
+	return markdownStyle{
+		heading: r.heading,
+		quote:   r.quote,
+		bold:    r.bold,
+		italic:  r.italic,
+		code:    r.code || r.fence,
+	}
+}
+
+// emit writes a character of text in the current style
+func (r *markdownRenderer) emit(c rune) {
+	if style := r.current(); style != r.runStyle {
+		r.flushRun()
+		r.runStyle = style
+	}
+	r.run.WriteRune(c)
+	if c != '\n' {
+		r.prev = c
+	}
+}
+
+// emitStyled writes text in a style of its own, such as a list bullet
+func (r *markdownRenderer) emitStyled(text string, style func(string) string) {
+	if text == "" {
+		return
+	}
+	r.flushRun()
+	if style != nil {
+		text = style(text)
+	}
+	r.out.WriteString(text)
+}
+
+// flushRun styles the text written in the current style
+func (r *markdownRenderer) flushRun() {
+	if r.run.Len() == 0 {
+		return
+	}
+	r.out.WriteString(r.styleRun(r.run.String(), r.runStyle))
+	r.run.Reset()
+}
+
+// styleRun styles a run of text. Newlines are kept outside the styling,
+// so each line's styling ends with it.
+func (r *markdownRenderer) styleRun(text string, style markdownStyle) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = r.styleText(line, style)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// styleText styles text without newlines
+func (r *markdownRenderer) styleText(text string, style markdownStyle) string {
+	switch {
+	case style.code:
+		text = Code(text)
+	case style.heading:
+		text = Heading(text)
+	case r.text != nil:
+		text = r.text(text)
+	}
+
+	var attributes []color.Attribute
+	if style.bold {
+		attributes = append(attributes, color.Bold)
+	}
+	if style.italic || style.quote {
+		attributes = append(attributes, color.Italic)
+	}
+	if len(attributes) > 0 {
+		text = color.New(attributes...).Sprint(text)
+	}
+	return text
+}
@@ -0,0 +1,106 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+// markdownTags shows the styles of rendered markdown as tags, with the
+// heading underlined and code reversed by the theme of the tests
+var markdownTags = strings.NewReplacer(
+	"\x1b[1;3m", "<b><i>", "\x1b[22;23m", "</i></b>",
+	"\x1b[1m", "<b>", "\x1b[22m", "</b>",
+	"\x1b[3m", "<i>", "\x1b[23m", "</i>",
+	"\x1b[4m", "<h>", "\x1b[24m", "</h>",
+	"\x1b[7m", "<code>", "\x1b[27m", "</code>",
+)
+
+// withMarkdownTheme renders markdown with colors on, in the theme of the tests
+func withMarkdownTheme(t *testing.T) {
+	colors := Colors()
+	SetColors(true)
+	if err := SetTheme(Theme{Heading: "underline", Code: "reverse"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		SetColors(colors)
+		SetTheme(Themes[DefaultTheme])
+		SetMarkdown(true)
+	})
+}
+
+func TestMarkdown(t *testing.T) {
+	withMarkdownTheme(t)
+
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"heading", "## Plan\nText", "<h>Plan</h>\nText"},
+		{"emphasis", "A **bold** and *italic* word", "A <b>bold</b> and <i>italic</i> word"},
+		{"underscores", "Use _one_ snake_case name", "Use <i>one</i> snake_case name"},
+		{"multiplication", "2 * 3 * 4", "2 * 3 * 4"},
+		{"escape", `Not \*italic\*`, "Not *italic*"},
+		{"code span", "Run `go test ./...` now", "Run <code>go test ./...</code> now"},
+		{"bullets", "- one\n  * two", "<h>• </h>one\n<h>  • </h>two"},
+		{"ordered", "1. first\n2) second", "<h>1. </h>first\n<h>2. </h>second"},
+		{"quote", "> said", "<h>│ </h><i>said</i>"},
+		{"rule", "---", "<h>" + strings.Repeat("─", markdownRuleWidth) + "</h>"},
+		{"code block", "```go\nx := *p\n\n# no heading\n```\nDone", "  <code>x := *p</code>\n\n  <code># no heading</code>\nDone"},
+		{"dates", "2024-01-01 release", "2024-01-01 release"},
+		{"emphasis ends with the line", "**open\nclosed", "<b>open</b>\nclosed"},
+		{"bold italic", "***both***", "<b><i>both</i></b>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := markdownTags.Replace(Markdown(tt.markdown)); got != tt.want {
+				t.Errorf("Markdown(%q) = %q, want %q", tt.markdown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownStream(t *testing.T) {
+	withMarkdownTheme(t)
+
+	markdown := "# Steps\n\n1. Run `make`\n2. Check the **output**\n\n```\ncode\n```\n"
+	whole := Markdown(markdown)
+
+	// Rendering a character at a time gives the same result, styled a
+	// piece at a time
+	joined := strings.NewReplacer("</h><h>", "", "</b><b>", "", "</code><code>", "")
+	stream := NewMarkdownStream(nil)
+	var streamed strings.Builder
+	for _, c := range markdown {
+		streamed.WriteString(stream.Render(string(c)))
+	}
+	streamed.WriteString(stream.Flush())
+	if got := joined.Replace(markdownTags.Replace(streamed.String())); got != markdownTags.Replace(whole) {
+		t.Errorf("streamed markdown = %q, want %q", got, markdownTags.Replace(whole))
+	}
+
+	// Text shows as it arrives once its line is known to be plain
+	stream = NewMarkdownStream(nil)
+	if got := stream.Render("Hello wor"); got != "Hello wor" {
+		t.Errorf("Render() = %q, want the text so far", got)
+	}
+}
+
+func TestMarkdownOff(t *testing.T) {
+	withMarkdownTheme(t)
+
+	SetMarkdown(false)
+	if got := Markdown("# Raw"); got != "# Raw" {
+		t.Errorf("Markdown() with rendering off = %q", got)
+	}
+	if got := NewMarkdownStream(nil).Render("**raw**"); got != "**raw**" {
+		t.Errorf("Render() with rendering off = %q", got)
+	}
+
+	SetMarkdown(true)
+	SetColors(false)
+	if got := Markdown("# Raw"); got != "# Raw" {
+		t.Errorf("Markdown() with colors off = %q", got)
+	}
+}
@@ -2,11 +2,9 @@ package output
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 	"sync"
 
-	"github.com/fatih/color"
 	"github.com/plannet-ai/plannet/config"
 )
 
@@ -18,6 +16,20 @@ type Manager struct {
 	sessionLock sync.Mutex // Ensures safe concurrent access
 }
 
+// quiet is whether decorative output is left out, as set by SetQuiet
+var quiet bool
+
+// SetQuiet sets whether decorative output, such as the separators around
+// generated text, offers to copy it and the headings of tables, is left out
+func SetQuiet(enabled bool) {
+	quiet = enabled
+}
+
+// Quiet reports whether decorative output is left out
+func Quiet() bool {
+	return quiet
+}
+
 // NewManager creates a new OutputManager instance
 func NewManager(useColors bool, cfg *config.Config) *Manager {
 	return &Manager{
@@ -32,11 +44,59 @@ func (m *Manager) HandleOutput(output string) error {
 	if err := m.displayOutput(output); err != nil {
 		return fmt.Errorf("failed to display output: %w", err)
 	}
+	return m.offerCopy(output)
+}
 
-	if shouldCopy := m.shouldCopyBasedOnPreference(); shouldCopy {
-		if err := m.copyToClipboard(output); err != nil {
-			return fmt.Errorf("failed to copy to clipboard: %w", err)
+// StreamOutput shows output as it is generated: the text given to the
+// returned function is shown as it arrives, and done ends the output and
+// offers to copy the whole of it
+func (m *Manager) StreamOutput() (write func(text string), done func() error) {
+	var output strings.Builder
+	var markdown *MarkdownStream
+	if m.useColors {
+		markdown = NewMarkdownStream(Generated)
+		printSeparator()
+	} else if !quiet {
+		fmt.Println()
+	}
+	write = func(text string) {
+		output.WriteString(text)
+		if m.useColors {
+			fmt.Print(markdown.Render(text))
+		} else {
+			fmt.Print(text)
+		}
+	}
+	done = func() error {
+		switch {
+		case m.useColors:
+			fmt.Println(markdown.Flush())
+			printSeparator()
+		case quiet:
+			fmt.Println()
+		default:
+			fmt.Print("\n\n")
 		}
+		return m.offerCopy(output.String())
+	}
+	return write, done
+}
+
+// offerCopy copies output to the clipboard if the copy preference says so.
+// When quiet, output is only copied when the preference is to copy
+// automatically, without a confirmation.
+func (m *Manager) offerCopy(output string) error {
+	if quiet {
+		if m.config.CopyPreference != config.CopyAutomatically {
+			return nil
+		}
+	} else if !m.shouldCopyBasedOnPreference() {
+		return nil
+	}
+	if err := m.copyToClipboard(output); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	if !quiet {
 		m.showCopyConfirmation()
 	}
 	return nil
@@ -86,15 +146,20 @@ func (m *Manager) shouldCopyForSession() bool {
 
 // displayOutput shows the generated output with optional formatting
 func (m *Manager) displayOutput(output string) error {
+	if quiet {
+		fmt.Println(Markdown(output))
+		return nil
+	}
 	if m.useColors {
 		// Add a separator line before output
-		color.Blue("\n" + strings.Repeat("-", 80) + "\n")
+		printSeparator()
 
-		// Display output with subtle highlighting
-		fmt.Println(color.CyanString(output))
+		// Display output with subtle highlighting, rendering its markdown
+		markdown := NewMarkdownStream(Generated)
+		fmt.Println(markdown.Render(output) + markdown.Flush())
 
 		// Add a separator line after output
-		color.Blue("\n" + strings.Repeat("-", 80) + "\n")
+		printSeparator()
 	} else {
 		fmt.Printf("\n%s\n\n", output)
 	}
@@ -108,7 +173,7 @@ func (m *Manager) promptForCopy() bool {
 	prompt := "Copy to clipboard? [y/n]: "
 
 	if m.useColors {
-		prompt = color.YellowString(prompt)
+		prompt = Prompt(prompt)
 	}
 
 	fmt.Print(prompt)
@@ -117,29 +182,17 @@ func (m *Manager) promptForCopy() bool {
 	return strings.ToLower(response) == "y"
 }
 
-// copyToClipboard attempts to copy text to clipboard using available system commands
+// copyToClipboard copies text to the clipboard
 func (m *Manager) copyToClipboard(text string) error {
-	// Try different clipboard commands based on OS
-	commands := []struct {
-		name string
-		args []string
-	}{
-		{"pbcopy", nil}, // macOS
-		{"xclip", []string{"-selection", "clipboard"}}, // Linux
-		{"xsel", []string{"--clipboard", "--input"}},   // Linux alternative
-		{"clip", nil}, // Windows
-	}
-
-	for _, cmd := range commands {
-		command := exec.Command(cmd.name, cmd.args...)
-		command.Stdin = strings.NewReader(text)
-
-		if err := command.Run(); err == nil {
-			return nil
-		}
-	}
+	return writeClipboard(text)
+}
 
-	return fmt.Errorf("no clipboard command available")
+// printSeparator prints the line around generated output, unless quiet
+func printSeparator() {
+	if quiet {
+		return
+	}
+	fmt.Print("\n" + Heading(strings.Repeat("-", 80)) + "\n")
 }
 
 // showCopyConfirmation displays a confirmation message about successful copying
@@ -148,7 +201,7 @@ func (m *Manager) showCopyConfirmation() {
 
 	if m.useColors {
 		// Show an animated confirmation
-		color.Green(message)
+		fmt.Println(Success(message))
 	} else {
 		fmt.Printf("\n%s\n", message)
 	}
@@ -156,6 +209,12 @@ func (m *Manager) showCopyConfirmation() {
 
 // HandleOutput is a convenience function for simple output handling
 func HandleOutput(output string, cfg *config.Config) error {
-	manager := NewManager(true, cfg) // Enable colors by default
+	manager := NewManager(Colors(), cfg)
 	return manager.HandleOutput(output)
 }
+
+// StreamOutput is a convenience function for showing output as it is generated
+func StreamOutput(cfg *config.Config) (write func(text string), done func() error) {
+	manager := NewManager(Colors(), cfg)
+	return manager.StreamOutput()
+}
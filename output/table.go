@@ -0,0 +1,167 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// minShrunkWidth is the narrowest a column is truncated to
+const minShrunkWidth = 10
+
+// Column is a column of a table
+type Column struct {
+	Heading string
+	// Shrink marks a column that is truncated when the table is wider than
+	// the terminal, such as a description
+	Shrink bool
+	// Style, if set, styles the cells of the column, such as Ticket
+	Style func(text string) string
+}
+
+// Table is rows of text to print in aligned columns
+type Table struct {
+	columns []Column
+	rows    [][]string
+}
+
+// NewTable creates a table with the given columns
+func NewTable(columns ...Column) *Table {
+	return &Table{columns: columns}
+}
+
+// AddRow adds a row with a cell for each column. Line breaks in cells are
+// replaced with spaces.
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.columns))
+	for i := range row {
+		if i < len(cells) {
+			row[i] = strings.Join(strings.Fields(cells[i]), " ")
+		}
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Render writes the table with a heading and aligned columns. When it is
+// wider than width, the columns that shrink are truncated to fit, widest
+// first; a width of 0 or less leaves the table as wide as it is.
+func (t *Table) Render(w io.Writer, width int) error {
+	return t.render(w, width, true)
+}
+
+// render writes the table with aligned columns, and with a heading if heading is set
+func (t *Table) render(w io.Writer, width int, heading bool) error {
+	widths := t.widths(width)
+	lines := t.rows
+	if heading {
+		lines = append([][]string{t.headings()}, t.rows...)
+	}
+	for n, row := range lines {
+		// Trailing empty cells are left out, so lines don't end in spaces
+		last := len(row) - 1
+		for last > 0 && row[last] == "" {
+			last--
+		}
+		var line strings.Builder
+		for i, cell := range row[:last+1] {
+			cell = truncate(cell, widths[i])
+			padding := widths[i] - utf8.RuneCountInString(cell) + 2
+			switch {
+			case heading && n == 0:
+				cell = Heading(cell)
+			case t.columns[i].Style != nil && cell != "":
+				cell = t.columns[i].Style(cell)
+			}
+			line.WriteString(cell)
+			if i < last {
+				line.WriteString(strings.Repeat(" ", padding))
+			}
+		}
+		if _, err := fmt.Fprintln(w, line.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderPlain writes the rows with their cells separated by tabs, without
+// a heading or truncation, for other programs to read
+func (t *Table) RenderPlain(w io.Writer) error {
+	for _, row := range t.rows {
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Print writes the table to stdout: aligned to the terminal's width in a
+// terminal, without its heading when quiet, and as plain rows otherwise
+func (t *Table) Print() error {
+	if !readline.IsTerminal(int(os.Stdout.Fd())) {
+		return t.RenderPlain(os.Stdout)
+	}
+	return t.render(os.Stdout, readline.GetScreenWidth(), !Quiet())
+}
+
+// headings returns the headings of the columns
+func (t *Table) headings() []string {
+	headings := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		headings[i] = strings.ToUpper(column.Heading)
+	}
+	return headings
+}
+
+// widths returns the width of each column, shrunk to fit in width
+func (t *Table) widths(width int) []int {
+	widths := make([]int, len(t.columns))
+	for i, heading := range t.headings() {
+		widths[i] = utf8.RuneCountInString(heading)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	if width <= 0 {
+		return widths
+	}
+
+	total := 2 * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > width {
+		widest := -1
+		for i, column := range t.columns {
+			if column.Shrink && widths[i] > minShrunkWidth && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// truncate shortens text to width characters, ending it with an ellipsis
+// when it is cut
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width <= 1 {
+		return string([]rune(text)[:width])
+	}
+	return string([]rune(text)[:width-1]) + "…"
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newTestTable() *Table {
+	table := NewTable(Column{Heading: "ID"}, Column{Heading: "Description", Shrink: true}, Column{Heading: "Status"})
+	table.AddRow("a1", "Fix the login timeout on\nslow networks", "active")
+	table.AddRow("b22", "Review", "completed")
+	return table
+}
+
+func TestTableRender(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestTable().Render(&out, 0); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"ID   DESCRIPTION                             STATUS\n" +
+		"a1   Fix the login timeout on slow networks  active\n" +
+		"b22  Review                                  completed\n"
+	if out.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	newTestTable().Render(&out, 36)
+	want = "" +
+		"ID   DESCRIPTION           STATUS\n" +
+		"a1   Fix the login timeo…  active\n" +
+		"b22  Review                completed\n"
+	if out.String() != want {
+		t.Errorf("Render() to 36 columns =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// Columns aren't shrunk below a minimum, even if the table doesn't fit
+	out.Reset()
+	newTestTable().Render(&out, 10)
+	want = "" +
+		"ID   DESCRIPTI…  STATUS\n" +
+		"a1   Fix the l…  active\n" +
+		"b22  Review      completed\n"
+	if out.String() != want {
+		t.Errorf("Render() to 10 columns =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestTableRenderPlain(t *testing.T) {
+	var out bytes.Buffer
+	if err := newTestTable().RenderPlain(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := "a1\tFix the login timeout on slow networks\tactive\nb22\tReview\tcompleted\n"
+	if out.String() != want {
+		t.Errorf("RenderPlain() = %q, want %q", out.String(), want)
+	}
+}
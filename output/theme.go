@@ -0,0 +1,264 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/plannet-ai/plannet/config"
+)
+
+// Theme is the colors of each kind of text plannet prints. Colors are
+// given as space-separated words, such as "bold hi-cyan": a color (black,
+// red, green, yellow, blue, magenta, cyan or white), optionally prefixed
+// with hi- for its bright variant or bg- for a background, and the
+// attributes bold, faint, italic, underline and reverse. "none" is plain text.
+type Theme struct {
+	Heading   string
+	Ticket    string
+	Warning   string
+	SideQuest string
+	Success   string
+	Prompt    string
+	Output    string // Text generated by the LLM
+	Code      string // Code in rendered markdown
+}
+
+// Themes are the built-in themes, by name
+var Themes = map[string]Theme{
+	"default": {
+		Heading:   "bold blue",
+		Ticket:    "cyan",
+		Warning:   "yellow",
+		SideQuest: "magenta",
+		Success:   "green",
+		Prompt:    "yellow",
+		Output:    "cyan",
+		Code:      "green",
+	},
+	// high-contrast uses bright, bold colors that stand out on dark and
+	// light backgrounds alike
+	"high-contrast": {
+		Heading:   "bold underline hi-white",
+		Ticket:    "bold hi-cyan",
+		Warning:   "bold hi-yellow",
+		SideQuest: "bold hi-magenta",
+		Success:   "bold hi-green",
+		Prompt:    "bold hi-yellow",
+		Output:    "hi-white",
+		Code:      "bold hi-green",
+	},
+	// monochrome tells kinds of text apart without color
+	"monochrome": {
+		Heading:   "bold",
+		Ticket:    "underline",
+		Warning:   "bold reverse",
+		SideQuest: "italic",
+		Success:   "bold",
+		Prompt:    "bold",
+		Output:    "none",
+		Code:      "reverse",
+	},
+}
+
+// DefaultTheme is the theme used unless one is configured
+const DefaultTheme = "default"
+
+// styles are the colors of the current theme, as set by SetTheme
+var styles = mustStyles(Themes[DefaultTheme])
+
+// themeStyles holds the parsed colors of a theme
+type themeStyles struct {
+	heading, ticket, warning, sideQuest, success, prompt, output, code *color.Color
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadTheme returns the theme the settings select, with the colors they
+// set in place of the theme's own
+func LoadTheme(settings config.ThemeSettings) (Theme, error) {
+	name := settings.Name
+	if name == "" {
+		name = DefaultTheme
+	}
+	theme, ok := Themes[name]
+	if !ok {
+		return Themes[DefaultTheme], fmt.Errorf("unknown theme %q, expected one of: %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	for _, override := range []struct {
+		value string
+		style *string
+	}{
+		{settings.Heading, &theme.Heading},
+		{settings.Ticket, &theme.Ticket},
+		{settings.Warning, &theme.Warning},
+		{settings.SideQuest, &theme.SideQuest},
+		{settings.Success, &theme.Success},
+		{settings.Prompt, &theme.Prompt},
+		{settings.Output, &theme.Output},
+		{settings.Code, &theme.Code},
+	} {
+		if override.value != "" {
+			*override.style = override.value
+		}
+	}
+	if _, err := parseStyles(theme); err != nil {
+		return Themes[DefaultTheme], err
+	}
+	return theme, nil
+}
+
+// SetTheme selects the theme text is styled with
+func SetTheme(theme Theme) error {
+	parsed, err := parseStyles(theme)
+	if err != nil {
+		return err
+	}
+	styles = parsed
+	return nil
+}
+
+// parseStyles parses the colors of a theme
+func parseStyles(theme Theme) (themeStyles, error) {
+	var parsed themeStyles
+	for _, style := range []struct {
+		name  string
+		value string
+		color **color.Color
+	}{
+		{"heading", theme.Heading, &parsed.heading},
+		{"ticket", theme.Ticket, &parsed.ticket},
+		{"warning", theme.Warning, &parsed.warning},
+		{"side_quest", theme.SideQuest, &parsed.sideQuest},
+		{"success", theme.Success, &parsed.success},
+		{"prompt", theme.Prompt, &parsed.prompt},
+		{"output", theme.Output, &parsed.output},
+		{"code", theme.Code, &parsed.code},
+	} {
+		c, err := parseStyle(style.value)
+		if err != nil {
+			return themeStyles{}, fmt.Errorf("invalid %s color: %w", style.name, err)
+		}
+		*style.color = c
+	}
+	return parsed, nil
+}
+
+// mustStyles parses the colors of a built-in theme
+func mustStyles(theme Theme) themeStyles {
+	parsed, err := parseStyles(theme)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// styleColors are the colors a style can name, in the order of their
+// attributes
+var styleColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// styleAttributes are the attributes a style can name
+var styleAttributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"reverse":   color.ReverseVideo,
+}
+
+// parseStyle parses a color such as "bold hi-cyan", which is nil for "none"
+// or no color
+func parseStyle(value string) (*color.Color, error) {
+	var attributes []color.Attribute
+	for _, word := range strings.Fields(strings.ToLower(value)) {
+		if word == "none" {
+			continue
+		}
+		if attribute, ok := styleAttributes[word]; ok {
+			attributes = append(attributes, attribute)
+			continue
+		}
+		base := color.FgBlack
+		name := word
+		switch {
+		case strings.HasPrefix(word, "hi-"):
+			base, name = color.FgHiBlack, strings.TrimPrefix(word, "hi-")
+		case strings.HasPrefix(word, "bg-hi-"):
+			base, name = color.BgHiBlack, strings.TrimPrefix(word, "bg-hi-")
+		case strings.HasPrefix(word, "bg-"):
+			base, name = color.BgBlack, strings.TrimPrefix(word, "bg-")
+		}
+		found := false
+		for i, c := range styleColors {
+			if c == name {
+				attributes = append(attributes, base+color.Attribute(i))
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown color or attribute %q", word)
+		}
+	}
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	return color.New(attributes...), nil
+}
+
+// style styles text with a color of the theme, which is nil for plain text
+func style(c *color.Color, text string) string {
+	if c == nil {
+		return text
+	}
+	return c.Sprint(text)
+}
+
+// Heading styles a heading, such as "Side quests:"
+func Heading(text string) string {
+	return style(styles.heading, text)
+}
+
+// Ticket styles a ticket key
+func Ticket(text string) string {
+	return style(styles.ticket, text)
+}
+
+// Warning styles a warning
+func Warning(text string) string {
+	return style(styles.warning, text)
+}
+
+// SideQuest styles a side quest, work done outside any ticket
+func SideQuest(text string) string {
+	return style(styles.sideQuest, text)
+}
+
+// Success styles a message that something worked
+func Success(text string) string {
+	return style(styles.success, text)
+}
+
+// Prompt styles a question to the user
+func Prompt(text string) string {
+	return style(styles.prompt, text)
+}
+
+// Generated styles text generated by the LLM
+func Generated(text string) string {
+	return style(styles.output, text)
+}
+
+// Code styles code in rendered markdown
+func Code(text string) string {
+	return style(styles.code, text)
+}
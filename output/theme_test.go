@@ -0,0 +1,57 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/plannet-ai/plannet/config"
+)
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme(config.ThemeSettings{})
+	if err != nil || theme != Themes[DefaultTheme] {
+		t.Errorf("LoadTheme() without settings = %+v, %v, want the default theme", theme, err)
+	}
+
+	theme, err = LoadTheme(config.ThemeSettings{Name: "monochrome", Ticket: "bold hi-cyan"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Ticket != "bold hi-cyan" || theme.Heading != Themes["monochrome"].Heading {
+		t.Errorf("LoadTheme() = %+v, want monochrome with a bold hi-cyan ticket", theme)
+	}
+
+	if _, err := LoadTheme(config.ThemeSettings{Name: "solarized"}); err == nil {
+		t.Error("LoadTheme() of an unknown theme succeeded")
+	}
+	if _, err := LoadTheme(config.ThemeSettings{Warning: "blinking orange"}); err == nil {
+		t.Error("LoadTheme() with an unknown color succeeded")
+	}
+}
+
+func TestThemeStyles(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		SetTheme(Themes[DefaultTheme])
+	})
+
+	if err := SetTheme(Theme{Heading: "bold underline", Ticket: "hi-cyan bg-blue", Output: "none"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := Heading("Side quests:"), "\x1b[1;4mSide quests:\x1b[22;24m"; got != want {
+		t.Errorf("Heading() = %q, want %q", got, want)
+	}
+	if got, want := Ticket("PROJ-1"), "\x1b[96;44mPROJ-1\x1b[0;0m"; got != want {
+		t.Errorf("Ticket() = %q, want %q", got, want)
+	}
+	if got := Generated("plain"); got != "plain" {
+		t.Errorf("Generated() with no color = %q, want plain text", got)
+	}
+
+	color.NoColor = true
+	if got := Ticket("PROJ-1"); got != "PROJ-1" {
+		t.Errorf("Ticket() with colors off = %q", got)
+	}
+}
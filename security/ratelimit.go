@@ -1,12 +1,20 @@
 package security
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
 
+// ErrRateLimitExceeded is returned for requests refused by a rate limit
+var ErrRateLimitExceeded = errors.New("rate limit exceeded")
+
 // RateLimiter provides rate limiting functionality
 type RateLimiter struct {
 	mu       sync.Mutex
@@ -26,37 +34,37 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 
 // Allow checks if a request is allowed based on rate limiting rules
 func (rl *RateLimiter) Allow(key string) bool {
+	return rl.Reserve(key) == 0
+}
+
+// Reserve records a request and returns 0 if it is allowed, or returns how
+// long to wait until it would be allowed without recording it
+func (rl *RateLimiter) Reserve(key string) time.Duration {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	now := time.Now()
 	windowStart := now.Add(-rl.window)
 
-	// Get the requests for this key
-	requests, ok := rl.requests[key]
-	if !ok {
-		// First request for this key
-		rl.requests[key] = []time.Time{now}
-		return true
-	}
-
 	// Filter out requests outside the window
 	var validRequests []time.Time
-	for _, t := range requests {
+	for _, t := range rl.requests[key] {
 		if t.After(windowStart) {
 			validRequests = append(validRequests, t)
 		}
 	}
 
-	// Check if we're over the limit
+	// Check if we're over the limit; a slot frees up when the oldest
+	// request leaves the window
 	if len(validRequests) >= rl.limit {
-		return false
+		rl.requests[key] = validRequests
+		return validRequests[len(validRequests)-rl.limit].Sub(windowStart)
 	}
 
 	// Add the new request
 	validRequests = append(validRequests, now)
 	rl.requests[key] = validRequests
-	return true
+	return 0
 }
 
 // Reset clears all rate limiting data
@@ -67,15 +75,35 @@ func (rl *RateLimiter) Reset() {
 	rl.requests = make(map[string][]time.Time)
 }
 
-// HTTPRateLimiter provides rate limiting for HTTP clients
+// HTTPRateLimiter provides rate limiting for HTTP clients. Requests over
+// the limit wait for it rather than failing, and requests the server rate
+// limits are retried after the delay its Retry-After or X-RateLimit-Reset
+// headers ask for, or with jittered exponential backoff. With
+// RetryTransient, so are requests that fail for reasons likely to pass.
 type HTTPRateLimiter struct {
 	limiter *RateLimiter
+	// MaxRetries is how many times a request the server rate limits is retried
+	MaxRetries int
+	// BaseDelay is the first backoff delay when the server asks for none;
+	// it doubles on each retry
+	BaseDelay time.Duration
+	// MaxDelay is the longest a request waits at once; requests that would
+	// wait longer fail instead
+	MaxDelay time.Duration
+	// OnWait, if set, is called before each wait, to show progress
+	OnWait func(key string, delay time.Duration)
+	// RetryTransient also retries requests that fail with a server error,
+	// such as 502 or 503, or without a response, such as on a timeout
+	RetryTransient bool
 }
 
 // NewHTTPRateLimiter creates a new HTTPRateLimiter instance
 func NewHTTPRateLimiter(limit int, window time.Duration) *HTTPRateLimiter {
 	return &HTTPRateLimiter{
-		limiter: NewRateLimiter(limit, window),
+		limiter:    NewRateLimiter(limit, window),
+		MaxRetries: 4,
+		BaseDelay:  time.Second,
+		MaxDelay:   2 * time.Minute,
 	}
 }
 
@@ -84,7 +112,7 @@ func (rl *HTTPRateLimiter) WrapHTTPClient(client *http.Client, key string) *http
 	// Create a custom transport that applies rate limiting
 	transport := &rateLimitedTransport{
 		base:    client.Transport,
-		limiter: rl.limiter,
+		limiter: rl,
 		key:     key,
 	}
 
@@ -100,23 +128,149 @@ func (rl *HTTPRateLimiter) WrapHTTPClient(client *http.Client, key string) *http
 // rateLimitedTransport is a custom HTTP transport that applies rate limiting
 type rateLimitedTransport struct {
 	base    http.RoundTripper
-	limiter *RateLimiter
+	limiter *HTTPRateLimiter
 	key     string
 }
 
 // RoundTrip implements the http.RoundTripper interface
 func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	// Apply rate limiting
-	if !t.limiter.Allow(t.key) {
-		return nil, fmt.Errorf("rate limit exceeded for %s", t.key)
-	}
-
 	// Use the base transport if available, otherwise use the default
 	base := t.base
 	if base == nil {
 		base = http.DefaultTransport
 	}
 
-	// Forward the request to the base transport
-	return base.RoundTrip(req)
+	for attempt := 0; ; attempt++ {
+		// Wait for the client-side limit
+		for {
+			delay := t.limiter.limiter.Reserve(t.key)
+			if delay == 0 {
+				break
+			}
+			if delay > t.limiter.MaxDelay {
+				return nil, fmt.Errorf("%w for %s", ErrRateLimitExceeded, t.key)
+			}
+			if err := t.wait(req.Context(), delay); err != nil {
+				return nil, err
+			}
+		}
+
+		// Forward the request to the base transport
+		resp, err := base.RoundTrip(req)
+		if !t.shouldRetry(req, resp, err) || attempt >= t.limiter.MaxRetries {
+			return resp, err
+		}
+
+		delay := t.limiter.BaseDelay << attempt
+		if resp != nil {
+			delay = retryDelay(resp, delay, time.Now())
+		}
+		delay = jitter(delay)
+		if delay > t.limiter.MaxDelay {
+			return resp, err
+		}
+		// A request with a body can only be sent again if it can be rewound
+		retry := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			var bodyErr error
+			if retry.Body, bodyErr = req.GetBody(); bodyErr != nil {
+				return resp, err
+			}
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.wait(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		req = retry
+	}
+}
+
+// wait reports a wait and sleeps, unless the request is cancelled first
+func (t *rateLimitedTransport) wait(ctx context.Context, delay time.Duration) error {
+	if t.limiter.OnWait != nil {
+		t.limiter.OnWait(t.key, delay)
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// shouldRetry reports whether a request that got resp or err is retried
+func (t *rateLimitedTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// Requests cancelled by the caller are not retried
+		return t.limiter.RetryTransient && req.Context().Err() == nil
+	}
+	return isRateLimited(resp) || (t.limiter.RetryTransient && isTransient(resp))
+}
+
+// isTransient reports whether the server failed a request for a reason that
+// is likely to pass, such as being overloaded or restarting
+func isTransient(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout, 529: // 529 is Anthropic's "overloaded"
+		return true
+	}
+	return false
+}
+
+// isRateLimited reports whether the server refused a request because of its
+// rate limit
+func isRateLimited(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "")
+}
+
+// retryDelay returns how long the server asks to wait before retrying: the
+// Retry-After header, in seconds or as a date, or the X-RateLimit-Reset
+// header, as a date or in seconds since the epoch. Without either, backoff
+// is returned.
+func retryDelay(resp *http.Response, backoff time.Duration, now time.Time) time.Duration {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return nonNegative(at.Sub(now))
+		}
+	}
+	if value := resp.Header.Get("X-RateLimit-Reset"); value != "" {
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			return nonNegative(at.Sub(now))
+		}
+		if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return nonNegative(time.Unix(epoch, 0).Sub(now))
+		}
+	}
+	return backoff
+}
+
+// jitter adds up to a fifth to a delay, so that clients limited together
+// don't all retry at the same moment
+func jitter(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// nonNegative returns d, or 0 if d is negative
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
 }
@@ -0,0 +1,166 @@
+package security
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	rl := NewRateLimiter(2, time.Minute)
+	if rl.Reserve("api") != 0 || rl.Reserve("api") != 0 {
+		t.Fatal("The first two requests should be allowed")
+	}
+	delay := rl.Reserve("api")
+	if delay <= 0 || delay > time.Minute {
+		t.Errorf("The third request should wait up to a minute, got %s", delay)
+	}
+	if rl.Reserve("other") != 0 {
+		t.Error("Requests are limited separately for each key")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{"Retry-After seconds", http.Header{"Retry-After": {"30"}}, 30 * time.Second},
+		{"Retry-After date", http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}, time.Minute},
+		{"X-RateLimit-Reset date", http.Header{"X-Ratelimit-Reset": {"2024-03-04T10:00:10Z"}}, 10 * time.Second},
+		{"X-RateLimit-Reset epoch", http.Header{"X-Ratelimit-Reset": {"1709546405"}}, 5 * time.Second},
+		{"Reset in the past", http.Header{"X-Ratelimit-Reset": {"2024-03-04T09:00:00Z"}}, 0},
+		{"No headers", http.Header{}, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: tt.header}
+			if got := retryDelay(resp, 2*time.Second, now); got != tt.want {
+				t.Errorf("retryDelay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitedTransportRetries(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if len(bodies) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var waits int
+	rl := NewHTTPRateLimiter(10, time.Minute)
+	rl.OnWait = func(key string, delay time.Duration) { waits++ }
+	client := rl.WrapHTTPClient(&http.Client{}, "test")
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the retried request to succeed, got status %d", resp.StatusCode)
+	}
+	if len(bodies) != 3 || bodies[2] != "payload" {
+		t.Errorf("Expected the body to be sent three times, got %q", bodies)
+	}
+	if waits != 2 {
+		t.Errorf("Expected 2 waits to be reported, got %d", waits)
+	}
+}
+
+func TestRateLimitedTransportGivesUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	rl := NewHTTPRateLimiter(10, time.Minute)
+	rl.MaxRetries = 2
+	resp, err := rl.WrapHTTPClient(&http.Client{}, "test").Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || requests != 3 {
+		t.Errorf("Expected status 429 after 3 requests, got %d after %d", resp.StatusCode, requests)
+	}
+}
+
+func TestRateLimitedTransportWaitsTooLong(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Waiting a minute for the client-side limit is more than allowed
+	rl := NewHTTPRateLimiter(1, time.Minute)
+	rl.MaxDelay = time.Second
+	client := rl.WrapHTTPClient(&http.Client{}, "test")
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("First request failed: %v", err)
+	}
+	resp.Body.Close()
+	if _, err := client.Get(server.URL); err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Errorf("Expected a rate limit error, got %v", err)
+	}
+}
+
+func TestRateLimitedTransportRetriesTransient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			// Drop the connection without a response
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	rl := NewHTTPRateLimiter(10, time.Minute)
+	rl.BaseDelay = time.Millisecond
+	client := rl.WrapHTTPClient(&http.Client{}, "test")
+
+	// Server errors are returned as they are, unless they are to be retried
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || requests != 1 {
+		t.Errorf("Expected status 502 after 1 request, got %d after %d", resp.StatusCode, requests)
+	}
+
+	requests = 0
+	rl.RetryTransient = true
+	resp, err = client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || requests != 3 {
+		t.Errorf("Expected status 200 after 3 requests, got %d after %d", resp.StatusCode, requests)
+	}
+}
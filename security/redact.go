@@ -0,0 +1,116 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces the secrets a Redactor masks
+const Redacted = "[REDACTED]"
+
+// RedactionRule masks what its pattern matches. When the pattern has a
+// group named "secret", only that group is masked, so that a rule can
+// match the context of a secret, such as "password=", and keep it.
+type RedactionRule struct {
+	Name    string // Names what the rule masks in reports, such as "AWS access key"
+	Pattern *regexp.Regexp
+}
+
+// DefaultRedactionRules mask common formats of API keys and credentials
+var DefaultRedactionRules = []RedactionRule{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abeprs]-[A-Za-z0-9-]{10,}`)},
+	{"Stripe key", regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}`)},
+	{"OpenAI or Anthropic key", regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"URL password", regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:(?P<secret>[^/\s@]+)@`)},
+	{"bearer token", regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9._~+/=-]{16,})`)},
+	{"password or secret", regexp.MustCompile(`(?i)\b[\w.-]*(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key)["']?\s*[:=]\s*["']?(?P<secret>[^\s"',;]{8,})`)},
+}
+
+// Redactions counts the secrets a Redactor masked, by the name of the rule
+// that masked them
+type Redactions map[string]int
+
+// Total returns how many secrets were masked
+func (r Redactions) Total() int {
+	total := 0
+	for _, n := range r {
+		total += n
+	}
+	return total
+}
+
+// String lists what was masked, such as "AWS access key (2), GitHub token (1)"
+func (r Redactions) String() string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d)", name, r[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Redactor masks secrets in text before it leaves the machine
+type Redactor struct {
+	rules []RedactionRule
+}
+
+// NewRedactor creates a Redactor with the default rules and the given
+// patterns, which follow the same "secret" group convention
+func NewRedactor(patterns []string) (*Redactor, error) {
+	rules := append([]RedactionRule(nil), DefaultRedactionRules...)
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		rules = append(rules, RedactionRule{Name: "custom pattern", Pattern: re})
+	}
+	return &Redactor{rules: rules}, nil
+}
+
+// Redact returns text with the secrets the rules match masked, and counts
+// them into found
+func (r *Redactor) Redact(text string, found Redactions) string {
+	for _, rule := range r.rules {
+		text = redactRule(text, rule, found)
+	}
+	return text
+}
+
+// redactRule masks what a rule matches in text
+func redactRule(text string, rule RedactionRule, found Redactions) string {
+	secret := rule.Pattern.SubexpIndex("secret")
+	var b strings.Builder
+	last := 0
+	for _, match := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[0], match[1]
+		if secret > 0 {
+			start, end = match[2*secret], match[2*secret+1]
+		}
+		// Skip empty matches, and secrets an earlier rule already masked
+		if start < 0 || start == end || strings.Contains(text[start:end], Redacted) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(Redacted)
+		last = end
+		found[rule.Name]++
+	}
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}